@@ -20,6 +20,8 @@ import (
 	"os"
 
 	"github.com/dell/cert-csi/pkg/cmd"
+	"github.com/dell/cert-csi/pkg/reporter"
+	"github.com/dell/cert-csi/pkg/utils"
 
 	"github.com/rifflock/lfshook"
 
@@ -52,6 +54,7 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "cert-csi"
 	app.Version = "1.6.0"
+	reporter.ToolVersion = app.Version
 	app.Usage = "unified method of benchmarking and certification of csi drivers"
 	app.EnableBashCompletion = true
 	app.Flags = []cli.Flag{
@@ -68,6 +71,11 @@ func main() {
 			Usage: "provide db to use",
 			Value: "default.db",
 		},
+		cli.BoolFlag{
+			Name:   "non-interactive, ni",
+			Usage:  "never prompt on stdin, answer every question with its default (stop and cleanup on termination signal, approve certification plan)",
+			EnvVar: utils.NonInteractiveEnv,
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
@@ -77,6 +85,7 @@ func main() {
 		if c.Bool("quiet") {
 			log.SetLevel(log.PanicLevel)
 		}
+		utils.NonInteractive = c.Bool("non-interactive")
 		return nil
 	}
 
@@ -87,6 +96,13 @@ func main() {
 		cmd.GetFunctionalReportCommand(),
 		cmd.GetListCommand(),
 		cmd.GetCleanupCommand(),
+		cmd.GetAbortCommand(),
+		cmd.GetScheduleCommand(),
+		cmd.GetQueueCommand(),
+		cmd.GetDatabaseCommand(),
+		cmd.GetSeriesCommand(),
+		cmd.GetQueryCommand(),
+		cmd.GetServeCommand(),
 		cmd.GetCertifyCommand(),
 		cmd.GetK8sEndToEndCommand(),
 	}
@@ -0,0 +1,206 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Slack is how long after test case ended changes of its entities are still attached to it,
+// namespaces are torn down and volumes released after test case result is saved
+var Slack = 10 * time.Minute
+
+// maxLineSize limits a single audit event, request and response bodies of Metadata level events are far smaller
+const maxLineSize = 4 * 1024 * 1024
+
+// Event is a part of audit.k8s.io/v1 event audit log consists of, one JSON event per line
+type Event struct {
+	AuditID string `json:"auditID"`
+	Stage   string `json:"stage"`
+	Verb    string `json:"verb"`
+	User    struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	UserAgent string `json:"userAgent"`
+	ObjectRef *struct {
+		Resource    string `json:"resource"`
+		Namespace   string `json:"namespace"`
+		Name        string `json:"name"`
+		Subresource string `json:"subresource"`
+	} `json:"objectRef"`
+	ResponseStatus *struct {
+		Code int `json:"code"`
+	} `json:"responseStatus"`
+	RequestReceivedTimestamp time.Time `json:"requestReceivedTimestamp"`
+}
+
+// Result describes ingestion of audit log
+type Result struct {
+	// Read is number of events in log, Malformed ones couldn't be parsed
+	Read      int
+	Malformed int
+	// Matched is number of events which changed entities of the run, Added ones weren't ingested before
+	Matched int
+	Added   int
+}
+
+// mutatingVerbs are verbs of requests attached to entities, reads are too many to be useful
+var mutatingVerbs = map[string]bool{
+	"create":           true,
+	"update":           true,
+	"patch":            true,
+	"delete":           true,
+	"deletecollection": true,
+}
+
+// objectKey identifies object entity stands for or owns
+type objectKey struct {
+	resource string
+	name     string
+}
+
+// candidate is an entity with window of its test case
+type candidate struct {
+	entity store.Entity
+	start  time.Time
+	end    time.Time
+}
+
+// objectKeys returns objects whose changes are attached to entity, volumes provisioned for PVC and contents
+// created for snapshot are named after UID of their claim by external-provisioner and snapshot-controller
+func objectKeys(e store.Entity) []objectKey {
+	switch e.Type {
+	case store.Pvc:
+		return []objectKey{{"persistentvolumeclaims", e.Name}, {"persistentvolumes", "pvc-" + e.K8sUID}}
+	case store.Pod:
+		return []objectKey{{"pods", e.Name}}
+	case store.StatefulSet:
+		return []objectKey{{"statefulsets", e.Name}}
+	case store.Snapshot:
+		return []objectKey{{"volumesnapshots", e.Name}, {"volumesnapshotcontents", "snapcontent-" + e.K8sUID}}
+	}
+	return nil
+}
+
+// Ingest reads audit log and attaches requests which changed PVCs, volumes, pods and snapshots of the run
+// to their entities, entries ingested before are skipped, so overlapping log segments can be ingested
+func Ingest(db store.Store, runName string, r io.Reader) (Result, error) {
+	var res Result
+	runs, err := db.GetTestRuns(store.Conditions{"name": runName}, "", 1)
+	if err != nil {
+		return res, err
+	}
+	if len(runs) == 0 {
+		return res, fmt.Errorf("can't find run %s", runName)
+	}
+	testCases, err := db.GetTestCases(store.Conditions{"run_id": runs[0].ID}, "", 0)
+	if err != nil {
+		return res, err
+	}
+	candidates := make(map[objectKey][]candidate)
+	for _, tc := range testCases {
+		entities, err := db.GetEntities(store.Conditions{"tc_id": tc.ID}, "", 0)
+		if err != nil {
+			return res, err
+		}
+		end := tc.EndTimestamp
+		if end.Before(tc.StartTimestamp) {
+			end = tc.StartTimestamp
+		}
+		for _, e := range entities {
+			for _, key := range objectKeys(e) {
+				candidates[key] = append(candidates[key], candidate{e, tc.StartTimestamp, end.Add(Slack)})
+			}
+		}
+	}
+
+	for _, found := range candidates {
+		sort.SliceStable(found, func(i, j int) bool { return found[i].start.After(found[j].start) })
+	}
+
+	var entries []*store.AuditEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		res.Read++
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			res.Malformed++
+			continue
+		}
+		entity, ok := match(candidates, event)
+		if !ok {
+			continue
+		}
+		res.Matched++
+		entries = append(entries, newEntry(entity, event))
+	}
+	if err := scanner.Err(); err != nil {
+		return res, err
+	}
+
+	res.Added, err = db.SaveAuditEntries(entries)
+	return res, err
+}
+
+// match returns entity changed by audit event, candidates are sorted by start of their test cases, newest first,
+// so name reused by later test cases belongs to the latest one started before the request
+func match(candidates map[objectKey][]candidate, event Event) (store.Entity, bool) {
+	if event.ObjectRef == nil || !mutatingVerbs[event.Verb] || (event.Stage != "" && event.Stage != "ResponseComplete") {
+		return store.Entity{}, false
+	}
+	for _, c := range candidates[objectKey{event.ObjectRef.Resource, event.ObjectRef.Name}] {
+		ts := event.RequestReceivedTimestamp
+		if ts.Before(c.start) {
+			continue
+		}
+		if ts.After(c.end) {
+			return store.Entity{}, false
+		}
+		return c.entity, true
+	}
+	return store.Entity{}, false
+}
+
+func newEntry(entity store.Entity, event Event) *store.AuditEntry {
+	entry := &store.AuditEntry{
+		TcID:        entity.TcID,
+		EntityID:    entity.ID,
+		AuditID:     event.AuditID,
+		Timestamp:   event.RequestReceivedTimestamp,
+		Verb:        event.Verb,
+		Resource:    event.ObjectRef.Resource,
+		Subresource: event.ObjectRef.Subresource,
+		Name:        event.ObjectRef.Name,
+		User:        event.User.Username,
+		UserAgent:   event.UserAgent,
+	}
+	if event.ResponseStatus != nil {
+		entry.Code = event.ResponseStatus.Code
+	}
+	return entry
+}
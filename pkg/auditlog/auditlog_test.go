@@ -0,0 +1,78 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auditlog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func auditLine(id, verb, resource, name, user string, ts time.Time) string {
+	return fmt.Sprintf(`{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"%s","stage":"ResponseComplete",`+
+		`"verb":"%s","user":{"username":"%s"},"objectRef":{"resource":"%s","namespace":"ns","name":"%s"},`+
+		`"responseStatus":{"code":200},"requestReceivedTimestamp":"%s"}`, id, verb, user, resource, name, ts.Format(time.RFC3339Nano))
+}
+
+func TestIngest(t *testing.T) {
+	db := store.NewSQLiteStore("file:auditlog.db?cache=shared&mode=memory")
+	defer db.Close()
+
+	start := time.Now().Add(-time.Hour).UTC()
+	run := &store.TestRun{Name: "audited run", StartTimestamp: start, StorageClass: "sc", ClusterAddress: "localhost"}
+	assert.NoError(t, db.SaveTestRun(run))
+	tc := &store.TestCase{Name: "ProvisioningSuite", StartTimestamp: start, EndTimestamp: start.Add(time.Minute), RunID: run.ID}
+	assert.NoError(t, db.SaveTestCase(tc))
+	pvc := &store.Entity{Name: "vol-1", K8sUID: "uid-1", TcID: tc.ID, Type: store.Pvc}
+	pod := &store.Entity{Name: "pod-1", K8sUID: "uid-2", TcID: tc.ID, Type: store.Pod}
+	assert.NoError(t, db.SaveEntities([]*store.Entity{pvc, pod}))
+
+	log := strings.Join([]string{
+		auditLine("1", "create", "persistentvolumeclaims", "vol-1", "admin", start.Add(time.Second)),
+		auditLine("2", "patch", "persistentvolumes", "pvc-uid-1", "system:serviceaccount:csi:csi-attacher", start.Add(10*time.Second)),
+		// Reads and objects of other runs aren't attached
+		auditLine("3", "get", "pods", "pod-1", "admin", start.Add(20*time.Second)),
+		auditLine("4", "delete", "pods", "pod-2", "admin", start.Add(20*time.Second)),
+		`{"auditID": broken`,
+		auditLine("5", "delete", "persistentvolumes", "pvc-uid-1", "admin", start.Add(5*time.Minute)),
+		// Name reused long after test case ended
+		auditLine("6", "delete", "pods", "pod-1", "admin", start.Add(time.Minute+2*Slack)),
+	}, "\n")
+
+	res, err := Ingest(db, "audited run", strings.NewReader(log))
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Read: 7, Malformed: 1, Matched: 3, Added: 3}, res)
+
+	entries, err := db.GetAuditEntries(store.Conditions{"entity_id": pvc.ID}, "timestamp", 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "system:serviceaccount:csi:csi-attacher", entries[1].User)
+	assert.Equal(t, "delete", entries[2].Verb)
+	assert.Equal(t, 200, entries[2].Code)
+
+	// Overlapping segment adds nothing new
+	res, err = Ingest(db, "audited run", strings.NewReader(log))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, res.Added)
+
+	_, err = Ingest(db, "missing run", strings.NewReader(log))
+	assert.Error(t, err)
+}
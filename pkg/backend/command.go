@@ -0,0 +1,70 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandVerifier runs external command which prints inventory as JSON, e.g. {"volumes": [...], "snapshots": [...]}.
+// Params of config are passed to the command as environment variables, so array credentials don't appear in process list.
+type CommandVerifier struct {
+	Command []string
+	Env     []string
+}
+
+// NewCommandVerifier creates command verifier from config
+func NewCommandVerifier(config *Config) (Verifier, error) {
+	if len(config.Command) == 0 {
+		return nil, errors.New("command verifier requires command")
+	}
+	env := os.Environ()
+	for k, v := range config.Params {
+		env = append(env, k+"="+v)
+	}
+	return &CommandVerifier{Command: config.Command, Env: env}, nil
+}
+
+// Name returns verifier name
+func (cv *CommandVerifier) Name() string {
+	return "command " + cv.Command[0]
+}
+
+// Inventory runs command and parses its output
+func (cv *CommandVerifier) Inventory(ctx context.Context) (*Inventory, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cv.Command[0], cv.Command[1:]...) // #nosec G204
+	cmd.Env = cv.Env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %v: %s", cv.Name(), err, strings.TrimSpace(stderr.String()))
+	}
+
+	inventory := &Inventory{}
+	if err := json.Unmarshal(stdout.Bytes(), inventory); err != nil {
+		return nil, fmt.Errorf("can't parse output of %s: %v", cv.Name(), err)
+	}
+	return inventory, nil
+}
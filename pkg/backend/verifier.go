@@ -0,0 +1,118 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package backend lists objects existing on storage backend, so volumes and snapshots leaked by a run can be found
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Inventory contains names of volumes and snapshots existing on storage backend
+type Inventory struct {
+	Volumes   []string `json:"volumes"`
+	Snapshots []string `json:"snapshots"`
+}
+
+// Verifier lists backend inventory, implementations are driver-specific
+type Verifier interface {
+	Name() string
+	Inventory(ctx context.Context) (*Inventory, error)
+}
+
+// Config selects verifier by its type, the rest of the config is passed to verifier factory
+type Config struct {
+	Type string `json:"type"`
+	// Params contain verifier specific settings, such as array endpoint and credentials
+	Params map[string]string `json:"params"`
+	// Command is used by command verifier
+	Command []string `json:"command"`
+}
+
+// Factory creates verifier from config
+type Factory func(config *Config) (Verifier, error)
+
+var (
+	factoriesMutex sync.RWMutex
+	factories      = map[string]Factory{
+		"command": NewCommandVerifier,
+	}
+)
+
+// Register makes verifier available under given type, so driver-specific verifiers can be plugged in
+func Register(verifierType string, factory Factory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+	factories[verifierType] = factory
+}
+
+// New creates verifier of config type
+func New(config *Config) (Verifier, error) {
+	factoriesMutex.RLock()
+	factory, ok := factories[config.Type]
+	factoriesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown backend verifier type %q", config.Type)
+	}
+	return factory(config)
+}
+
+// LoadConfig reads verifier config from yaml file and creates verifier
+func LoadConfig(path string) (Verifier, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("can't read backend config: %v", err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("can't parse backend config: %v", err)
+	}
+	return New(config)
+}
+
+// Leaked returns objects which are present in after inventory, but weren't in before
+func Leaked(before, after *Inventory) *Inventory {
+	return &Inventory{
+		Volumes:   subtract(after.Volumes, before.Volumes),
+		Snapshots: subtract(after.Snapshots, before.Snapshots),
+	}
+}
+
+// IsEmpty checks whether inventory has no objects
+func (i *Inventory) IsEmpty() bool {
+	return len(i.Volumes) == 0 && len(i.Snapshots) == 0
+}
+
+func subtract(from, names []string) []string {
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+	var result []string
+	for _, name := range from {
+		if !existing[name] {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,61 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifierTestSuite struct {
+	suite.Suite
+}
+
+func (suite *VerifierTestSuite) TestLeaked() {
+	before := &Inventory{Volumes: []string{"vol-1"}, Snapshots: []string{"snap-1"}}
+	after := &Inventory{Volumes: []string{"vol-3", "vol-1", "vol-2"}, Snapshots: []string{"snap-1"}}
+
+	leaked := Leaked(before, after)
+	suite.Equal([]string{"vol-2", "vol-3"}, leaked.Volumes)
+	suite.Empty(leaked.Snapshots)
+	suite.False(leaked.IsEmpty())
+	suite.True(Leaked(after, after).IsEmpty())
+}
+
+func (suite *VerifierTestSuite) TestCommandVerifier() {
+	v, err := New(&Config{
+		Type:    "command",
+		Command: []string{"sh", "-c", `echo "{\"volumes\": [\"$ARRAY_PREFIX-1\"]}"`},
+		Params:  map[string]string{"ARRAY_PREFIX": "vol"},
+	})
+	suite.NoError(err)
+
+	inventory, err := v.Inventory(context.Background())
+	suite.NoError(err)
+	suite.Equal([]string{"vol-1"}, inventory.Volumes)
+
+	_, err = New(&Config{Type: "command"})
+	suite.Error(err)
+	_, err = New(&Config{Type: "unknown"})
+	suite.Error(err)
+}
+
+func TestVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifierTestSuite))
+}
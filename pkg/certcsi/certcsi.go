@@ -0,0 +1,309 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package certcsi is the Go API of cert-csi: it lets other tools run test suites against storage classes
+// programmatically and get results as structs instead of running the CLI and parsing its output.
+//
+//	r, err := certcsi.NewRunnerBuilder(
+//		certcsi.WithKubeconfig("/root/.kube/config"),
+//		certcsi.WithStorageClasses("powerstore"),
+//		certcsi.WithSuites(&suites.VolumeIoSuite{VolumeNumber: 2, VolumeSize: "3Gi", ChainNumber: 2, ChainLength: 2}),
+//	).Build()
+//	if err != nil {
+//		return err
+//	}
+//	defer r.Close()
+//	results, err := r.Run(ctx)
+//
+// Runs are recorded in a database per storage class like runs of the CLI, so they can be reported on and
+// compared with cert-csi commands later. Logs go to the standard logrus logger.
+package certcsi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/testcore/runner"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+)
+
+// Suite is a test suite run by Runner, every suite of the suites package implements it
+type Suite = suites.Interface
+
+// Option configures RunnerBuilder
+type Option func(*RunnerBuilder)
+
+// RunnerBuilder collects configuration of a run and builds Runner executing it
+type RunnerBuilder struct {
+	kubeconfig      string
+	driverNamespace string
+	storageClasses  []string
+	suites          []Suite
+	observerType    string
+	longevity       string
+	timeout         time.Duration
+	cooldown        time.Duration
+	sequential      bool
+	noCleanup       bool
+	noCleanupOnFail bool
+	noMetrics       bool
+	noReports       bool
+	databaseDir     string
+	reportPath      string
+	configure       []func(*runner.SuiteRunner)
+}
+
+// NewRunnerBuilder creates RunnerBuilder with defaults of the CLI: one iteration, event observer and
+// databases in working directory
+func NewRunnerBuilder(opts ...Option) *RunnerBuilder {
+	b := &RunnerBuilder{observerType: "event", longevity: "1"}
+	return b.With(opts...)
+}
+
+// With applies options to the builder
+func (b *RunnerBuilder) With(opts ...Option) *RunnerBuilder {
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithKubeconfig sets path to kubeconfig of the cluster, in-cluster config or KUBECONFIG is used if empty
+func WithKubeconfig(path string) Option {
+	return func(b *RunnerBuilder) {
+		b.kubeconfig = path
+	}
+}
+
+// WithDriverNamespace sets namespace of the driver, its resource usage and health are tracked during run
+func WithDriverNamespace(namespace string) Option {
+	return func(b *RunnerBuilder) {
+		b.driverNamespace = namespace
+	}
+}
+
+// WithStorageClasses adds storage classes suites are run against
+func WithStorageClasses(storageClasses ...string) Option {
+	return func(b *RunnerBuilder) {
+		b.storageClasses = append(b.storageClasses, storageClasses...)
+	}
+}
+
+// WithSuites adds suites run against every storage class
+func WithSuites(s ...Suite) Option {
+	return func(b *RunnerBuilder) {
+		b.suites = append(b.suites, s...)
+	}
+}
+
+// WithObserverType sets how suites observe resources, "event" or "list"
+func WithObserverType(observerType string) Option {
+	return func(b *RunnerBuilder) {
+		b.observerType = observerType
+	}
+}
+
+// WithLongevity sets number of iterations (ex. 10) or duration (ex. 3d.2h30m15s) suites are run for
+func WithLongevity(longevity string) Option {
+	return func(b *RunnerBuilder) {
+		b.longevity = longevity
+	}
+}
+
+// WithTimeout sets how long every stage of suites is waited for, defaults of resource clients are used if 0
+func WithTimeout(timeout time.Duration) Option {
+	return func(b *RunnerBuilder) {
+		b.timeout = timeout
+	}
+}
+
+// WithCooldown sets pause between iterations
+func WithCooldown(cooldown time.Duration) Option {
+	return func(b *RunnerBuilder) {
+		b.cooldown = cooldown
+	}
+}
+
+// WithSequential runs suites one by one instead of in parallel
+func WithSequential() Option {
+	return func(b *RunnerBuilder) {
+		b.sequential = true
+	}
+}
+
+// WithNoCleanup keeps namespaces of suites and their resources once suites are over
+func WithNoCleanup() Option {
+	return func(b *RunnerBuilder) {
+		b.noCleanup = true
+	}
+}
+
+// WithNoCleanupOnFail keeps namespaces of failed suites for investigation
+func WithNoCleanupOnFail() Option {
+	return func(b *RunnerBuilder) {
+		b.noCleanupOnFail = true
+	}
+}
+
+// WithNoMetrics disables collection of metrics and reports built from them
+func WithNoMetrics() Option {
+	return func(b *RunnerBuilder) {
+		b.noMetrics = true
+	}
+}
+
+// WithNoReports disables report generation once run is over
+func WithNoReports() Option {
+	return func(b *RunnerBuilder) {
+		b.noReports = true
+	}
+}
+
+// WithDatabaseDir sets directory databases of storage classes are kept in, working directory if empty
+func WithDatabaseDir(dir string) Option {
+	return func(b *RunnerBuilder) {
+		b.databaseDir = dir
+	}
+}
+
+// WithReportPath sets directory reports of the run are generated in, other runs of the process aren't affected
+func WithReportPath(path string) Option {
+	return func(b *RunnerBuilder) {
+		b.reportPath = path
+	}
+}
+
+// WithSuiteRunner configures underlying suite runner once it's created, for settings without an option,
+// ex. Budget or Backend
+func WithSuiteRunner(configure func(*runner.SuiteRunner)) Option {
+	return func(b *RunnerBuilder) {
+		b.configure = append(b.configure, configure)
+	}
+}
+
+// databasePath returns database file of the storage class
+func (b *RunnerBuilder) databasePath(storageClass string) string {
+	return filepath.Join(b.databaseDir, storageClass+".db")
+}
+
+// Build validates configuration, connects to the cluster and locks databases of storage classes, so that
+// another run can't write to them until Runner is run. Runner must be run or closed to release them
+func (b *RunnerBuilder) Build() (*Runner, error) {
+	if len(b.storageClasses) == 0 {
+		return nil, errors.New("at least one storage class is required")
+	}
+	if len(b.suites) == 0 {
+		return nil, errors.New("at least one suite is required")
+	}
+	var scDBs []*store.StorageClassDB
+	closeDBs := func() {
+		for _, scDB := range scDBs {
+			_ = scDB.DB.Close()
+		}
+	}
+	ss := make(map[string][]suites.Interface)
+	for _, sc := range b.storageClasses {
+		if _, ok := ss[sc]; ok {
+			closeDBs()
+			return nil, fmt.Errorf("storage class %s is given more than once", sc)
+		}
+		db := store.NewSQLiteStore("file:" + b.databasePath(sc))
+		if err := db.AcquireRunLock(false); err != nil {
+			_ = db.Close()
+			closeDBs()
+			return nil, fmt.Errorf("can't use database of storage class %s: %w", sc, err)
+		}
+		scDBs = append(scDBs, &store.StorageClassDB{StorageClass: sc, DB: db})
+		ss[sc] = b.suites
+	}
+
+	sr, err := runner.NewSuiteRunnerE(
+		b.kubeconfig,
+		b.driverNamespace,
+		"",
+		"",
+		"",
+		b.observerType,
+		b.longevity,
+		b.driverNamespace,
+		int(b.timeout.Seconds()),
+		int(b.cooldown.Seconds()),
+		b.sequential,
+		b.noCleanup,
+		b.noCleanupOnFail,
+		b.noMetrics,
+		b.noReports,
+		scDBs,
+	)
+	if err != nil {
+		closeDBs()
+		return nil, err
+	}
+	sr.IgnoreSignals = true
+	sr.ReportPath = b.reportPath
+	for _, configure := range b.configure {
+		configure(sr)
+	}
+	return &Runner{suiteRunner: sr, suites: ss, builder: b}, nil
+}
+
+// Runner executes suites configured by RunnerBuilder, it can be run only once
+type Runner struct {
+	suiteRunner *runner.SuiteRunner
+	suites      map[string][]suites.Interface
+	builder     *RunnerBuilder
+	ran         bool
+}
+
+// Run executes suites and returns results of runs of every storage class. Cancelling ctx aborts the run,
+// suites clean up like on interrupt of the CLI. Error is returned along with results if run failed
+func (r *Runner) Run(ctx context.Context) (*Results, error) {
+	if r.ran {
+		return nil, errors.New("runner was already run or closed, build a new one")
+	}
+	r.ran = true
+	runErr := r.suiteRunner.Run(ctx, r.suites)
+
+	results := &Results{Succeeded: r.suiteRunner.SucceededSuites}
+	for _, scDB := range r.suiteRunner.ScDBs {
+		run, err := readRunResult(r.builder.databasePath(scDB.StorageClass), scDB.TestRun.Name)
+		if err != nil {
+			return results, errors.Join(runErr, fmt.Errorf("can't read results of storage class %s: %w", scDB.StorageClass, err))
+		}
+		results.Runs = append(results.Runs, run)
+	}
+	return results, runErr
+}
+
+// Close closes databases of storage classes and releases their locks if Runner wasn't run, Run closes them itself,
+// so it's safe to defer Close right after Build. Runner can't be run once closed
+func (r *Runner) Close() error {
+	if r.ran {
+		return nil
+	}
+	r.ran = true
+	var errs []error
+	for _, scDB := range r.suiteRunner.ScDBs {
+		if err := scDB.DB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("can't close database of storage class %s: %w", scDB.StorageClass, err))
+		}
+	}
+	return errors.Join(errs...)
+}
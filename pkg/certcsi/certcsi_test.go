@@ -0,0 +1,95 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package certcsi
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/testcore/runner"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerBuilder(t *testing.T) {
+	b := NewRunnerBuilder(
+		WithStorageClasses("sc-a"),
+		WithLongevity("3"),
+		WithTimeout(time.Minute),
+		WithSequential(),
+	).With(WithStorageClasses("sc-b"), WithDatabaseDir("dbs"))
+	assert.Equal(t, []string{"sc-a", "sc-b"}, b.storageClasses)
+	assert.Equal(t, "3", b.longevity)
+	assert.Equal(t, "event", b.observerType)
+	assert.Equal(t, time.Minute, b.timeout)
+	assert.True(t, b.sequential)
+	assert.Equal(t, filepath.Join("dbs", "sc-a.db"), b.databasePath("sc-a"))
+
+	// Configuration is validated before cluster is contacted
+	_, err := b.Build()
+	assert.ErrorContains(t, err, "suite")
+	_, err = NewRunnerBuilder(WithSuites(&suites.VolumeIoSuite{})).Build()
+	assert.ErrorContains(t, err, "storage class")
+	_, err = NewRunnerBuilder(WithSuites(&suites.VolumeIoSuite{}), WithStorageClasses("sc", "sc"),
+		WithDatabaseDir(t.TempDir())).Build()
+	assert.ErrorContains(t, err, "more than once")
+}
+
+func TestRunnerClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sc.db")
+	db := store.NewSQLiteStore("file:" + path)
+	assert.NoError(t, db.AcquireRunLock(false))
+	r := &Runner{suiteRunner: &runner.SuiteRunner{ScDBs: []*store.StorageClassDB{{StorageClass: "sc", DB: db}}}}
+
+	// Locks of runner which is never run are released
+	assert.NoError(t, r.Close())
+	assert.NoError(t, r.Close())
+	other := store.NewSQLiteStore("file:" + path)
+	assert.NoError(t, other.AcquireRunLock(false))
+	assert.NoError(t, other.Close())
+
+	_, err := r.Run(context.Background())
+	assert.ErrorContains(t, err, "closed")
+}
+
+func TestReadRunResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sc.db")
+	db := store.NewSQLiteStore("file:" + path)
+	run := &store.TestRun{Name: "test-run-1", StorageClass: "sc", StartTimestamp: time.Now()}
+	assert.NoError(t, db.SaveTestRun(run))
+	assert.NoError(t, db.SaveTestCase(&store.TestCase{Name: "VolumeIoSuite", RunID: run.ID, Iteration: 1, Success: true}))
+	assert.NoError(t, db.SaveTestCase(&store.TestCase{Name: "SnapSuite", RunID: run.ID, Iteration: 1, ErrorMessage: "snapshot not ready"}))
+	assert.NoError(t, db.SaveTestCase(&store.TestCase{Name: "CloneVolumeSuite", RunID: run.ID, Iteration: 1, Skipped: true}))
+	assert.NoError(t, db.Close())
+
+	res, err := readRunResult(path, "test-run-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sc", res.StorageClass)
+	assert.Len(t, res.Suites, 3)
+
+	results := &Results{Runs: []RunResult{res}}
+	failed := results.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "SnapSuite", failed[0].Name)
+	assert.Equal(t, "snapshot not ready", failed[0].Error)
+
+	_, err = readRunResult(path, "test-run-2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package certcsi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Results are results of runs of all storage classes
+type Results struct {
+	// Succeeded is fraction of suites which succeeded over all storage classes and iterations
+	Succeeded float64
+	Runs      []RunResult
+}
+
+// RunResult is result of the run of a storage class
+type RunResult struct {
+	// Name identifies run in database, it's what reports of the CLI are generated for
+	Name         string
+	StorageClass string
+	Database     string
+	Start        time.Time
+	// AbortReason is why run was aborted, empty if it wasn't
+	AbortReason string
+	// TruncateReason is the budget run stopped launching iterations for, empty if it ran to the end
+	TruncateReason string
+	Suites         []SuiteResult
+}
+
+// SuiteResult is result of a suite in an iteration
+type SuiteResult struct {
+	Name       string
+	Parameters string
+	Iteration  int
+	Success    bool
+	// Skipped suites didn't run because suites they depend on failed
+	Skipped bool
+	Error   string
+	Start   time.Time
+	End     time.Time
+}
+
+// Failed returns suites of all runs which neither succeeded nor were skipped
+func (r *Results) Failed() []SuiteResult {
+	var failed []SuiteResult
+	for _, run := range r.Runs {
+		for _, s := range run.Suites {
+			if !s.Success && !s.Skipped {
+				failed = append(failed, s)
+			}
+		}
+	}
+	return failed
+}
+
+// readRunResult reads run with the name and its test cases from database file
+func readRunResult(path, name string) (RunResult, error) {
+	db := store.NewSQLiteStore("file:" + path)
+	defer db.Close()
+	runs, err := db.GetTestRuns(store.Conditions{"name": name}, "", 1)
+	if err != nil {
+		return RunResult{}, err
+	}
+	if len(runs) == 0 {
+		return RunResult{}, fmt.Errorf("run %s isn't in database %s", name, path)
+	}
+	run := runs[0]
+	res := RunResult{
+		Name:           run.Name,
+		StorageClass:   run.StorageClass,
+		Database:       path,
+		Start:          run.StartTimestamp,
+		AbortReason:    run.AbortReason,
+		TruncateReason: run.TruncateReason,
+	}
+	testCases, err := db.GetTestCases(store.Conditions{"run_id": run.ID}, "", 0)
+	if err != nil {
+		return RunResult{}, err
+	}
+	for _, tc := range testCases {
+		res.Suites = append(res.Suites, SuiteResult{
+			Name:       tc.Name,
+			Parameters: tc.Parameters,
+			Iteration:  tc.Iteration,
+			Success:    tc.Success,
+			Skipped:    tc.Skipped,
+			Error:      tc.ErrorMessage,
+			Start:      tc.StartTimestamp,
+			End:        tc.EndTimestamp,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,74 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dell/cert-csi/pkg/progress"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// GetAbortCommand returns abort CLI command
+func GetAbortCommand() cli.Command {
+	return cli.Command{
+		Name:      "abort",
+		Usage:     "aborts in-progress run, the run is marked aborted with the reason and its resources are cleaned up",
+		ArgsUsage: "[run name]",
+		Category:  "main",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "progress-address, pa",
+				Usage: "address the run serves progress on, as passed to its --progress-address",
+			},
+			cli.StringFlag{
+				Name:  "token",
+				Usage: "token abort is authorized with, read from .cert-csi folder of user home if empty",
+			},
+			cli.StringFlag{
+				Name:  "reason, r",
+				Usage: "reason the run is aborted with, shown in reports",
+				Value: "aborted by operator",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.String("progress-address") == "" {
+				return errors.New("progress-address is required to reach the run")
+			}
+			token := c.String("token")
+			if token == "" {
+				var err error
+				if token, err = progress.ReadToken(); err != nil {
+					return fmt.Errorf("can't read token of abort, pass it with --token: %w", err)
+				}
+			}
+			run := c.Args().First()
+			if err := progress.Abort(c.String("progress-address"), token, run, c.String("reason")); err != nil {
+				return err
+			}
+			if run == "" {
+				run = "run"
+			}
+			log.Infof("Requested abort of %s", color.YellowString(run))
+			return nil
+		},
+	}
+}
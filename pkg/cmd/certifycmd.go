@@ -17,15 +17,23 @@
 package cmd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/backend"
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/driverprofile"
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/load"
+	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/reporter"
 	"github.com/dell/cert-csi/pkg/store"
 	"github.com/dell/cert-csi/pkg/testcore/runner"
 	"github.com/dell/cert-csi/pkg/testcore/suites"
+	"github.com/dell/cert-csi/pkg/utils"
+	"github.com/dell/cert-csi/pkg/waitcondition"
 
 	"github.com/fatih/color"
 	log "github.com/sirupsen/logrus"
@@ -53,6 +61,15 @@ type Entry struct {
 	VGS              bool
 	Ephemeral        *EphemeralParams
 	CapacityTracking *CapacityTracking
+	// IPv6 validates driver on IPv6 or dual-stack cluster, driver-namespace is required
+	IPv6 bool
+	// Assertions are keyed by suite name, e.g. VolumeIoSuite
+	Assertions map[string]*collector.Assertions
+	// Dependencies list suites a suite is skipped without, keyed by suite name, e.g. SnapSuite: [VolumeIoSuite]
+	Dependencies map[string][]string
+	// WaitConditions are extra conditions objects must satisfy after suite, keyed by suite name, e.g.
+	// VolumeIoSuite: [{resource: pvc, expression: 'metadata.annotations["example.com/ready"]'}]
+	WaitConditions map[string][]*waitcondition.Condition
 }
 
 // CapacityTracking contains parameters specific to Storage Capacity Tracking tests
@@ -82,6 +99,10 @@ func GetCertifyCommand() cli.Command {
 				Usage:    "path to certification config file",
 				Required: true,
 			},
+			cli.StringFlag{
+				Name:  "driver-profile",
+				Usage: "preset suites, timeout and expected capabilities of a driver, one of " + strings.Join(driverprofile.Names(), ", ") + " or path to profile file, certification config and flags override it",
+			},
 			cli.StringFlag{
 				Name:  "image-config",
 				Usage: "path to images config file",
@@ -148,9 +169,37 @@ func GetCertifyCommand() cli.Command {
 				Usage: "define your volumeSnapshotClass",
 			},
 			cli.StringFlag{
-				Name:  "reportPath, path",
+				Name:  "reportPath, path, output-dir",
 				Usage: "path to folder where reports will be created (if not specified `~/.cert-csi/` will be used)",
 			},
+			cli.StringFlag{
+				Name:  "report-layout",
+				Usage: "layout of reports and plots inside the output folder: [per-run], [flat] or [timestamped]",
+				Value: string(plotter.LayoutPerRun),
+			},
+			cli.StringFlag{
+				Name:  "plot-format",
+				Usage: "file format of plots: [png], [svg] or [pdf], vector formats stay crisp when embedded into documents",
+				Value: string(plotter.FormatPNG),
+			},
+			cli.StringFlag{
+				Name:  "slo",
+				Usage: "latency objectives drawn on SLO burn-down chart of html report, ex. PVCBind=10s@95,PodCreation=1m (target defaults to 99%)",
+			},
+			cli.DurationFlag{
+				Name:  "slo-window",
+				Usage: "rolling window of SLO burn-down chart",
+				Value: reporter.SLOWindow,
+			},
+			cli.IntFlag{
+				Name:  "max-plot-points",
+				Usage: "maximum number of points drawn per chart series, longer series are downsampled to keep memory bounded (0 disables)",
+				Value: plotter.MaxPlotPoints,
+			},
+			cli.IntFlag{
+				Name:  "max-detailed-entities",
+				Usage: "list only failed and this many slowest PVCs and pods of every test case in html report, JSON report keeps all of them (0 lists all)",
+			},
 			cli.StringFlag{
 				Name:  "driver-namespace, driver-ns",
 				Usage: "specify the driver namespace to find the driver resources for the volume health metrics suite",
@@ -167,6 +216,85 @@ func GetCertifyCommand() cli.Command {
 				Name:  "vgs-name",
 				Usage: "specify the volume group name",
 			},
+			cli.Int64Flag{
+				Name:  "seed",
+				Usage: "seed for random resource names and ordering decisions, use the seed logged by a previous run to replay it",
+			},
+			cli.StringFlag{
+				Name:  "name-generator",
+				Usage: "generator of resource name suffixes: random, ulid, incremental (pvc-0001, pvc-0002, ...) or words (brave-otter)",
+				Value: "random",
+			},
+			cli.StringFlag{
+				Name:  "progress-address, pa",
+				Usage: "serve live progress as JSON and accept abort requests on this address (ex. :9090 binds to localhost), disabled if empty",
+			},
+			cli.StringFlag{
+				Name:  "backend-config",
+				Usage: "path to backend verifier config, lists volumes and snapshots on storage backend before and after run to find leaks",
+			},
+			cli.StringFlag{
+				Name:  "rbac-audit",
+				Usage: "path to save minimal ClusterRole with exactly the API verbs and resources used by the run",
+			},
+			cli.StringFlag{
+				Name:  "class-guard",
+				Value: "warn",
+				Usage: "what to do if storage or snapshot class used by run changes while it's in progress: off, warn or fail",
+			},
+			cli.StringFlag{
+				Name:  "teardown-timeouts",
+				Usage: "comma separated timeouts of namespace teardown tiers deleted in order pods, pvcs, snapshots, namespace (ex. pods=2m,pvcs=5m), unset tiers use namespace timeout",
+			},
+			cli.BoolFlag{
+				Name:  "kernel-log-scan",
+				Usage: "scrape kernel log of nodes running block volumes for SCSI/NVMe IO errors and path failures",
+			},
+			cli.BoolFlag{
+				Name:  "driver-health-probe",
+				Usage: "probe liveness endpoints of driver pods in driver namespace during run and report windows they didn't answer in",
+			},
+			cli.StringFlag{
+				Name:  "background-load",
+				Usage: "keep API server and scheduler busy creating and deleting configmaps and pods without volumes during run, light, moderate, heavy or configmaps=<rate>,pods=<rate>,max-pods=<n>",
+			},
+			cli.BoolFlag{
+				Name:  "mark-baseline",
+				Usage: "mark this run as baseline of its storage classes, later runs are compared with it and regressions are reported",
+			},
+			cli.Float64Flag{
+				Name:  "regression-threshold",
+				Usage: "percent average stage latency may exceed the one of baseline run by before it is reported as regression",
+				Value: 20,
+			},
+			cli.BoolFlag{
+				Name:  "fail-on-regression",
+				Usage: "exit with non-zero code if any stage latency regressed compared to baseline run",
+			},
+			cli.BoolFlag{
+				Name:  "compare",
+				Usage: "compare storage classes of different drivers: suites common to all of them run with the same parameters, side by side, and comparison report is generated",
+			},
+			cli.BoolFlag{
+				Name:  "fairness",
+				Usage: "run suites of every storage class alone in the first iteration and together afterwards, to report noisy-neighbor effect between them",
+			},
+			cli.DurationFlag{
+				Name:  "run-timeout",
+				Usage: "abort the whole run after that long, in-flight suites are cancelled and cleaned up, 0 disables it",
+			},
+			cli.StringSliceFlag{
+				Name:  "restricted-namespaces",
+				Usage: "pre-created namespaces to run suites in with namespaced permissions only, cluster-scoped resources are reported as not observable",
+			},
+			cli.StringFlag{
+				Name:  "metadata-config",
+				Usage: "path to yaml with annotations and labels added to every PVC and pod created by suites",
+			},
+			cli.BoolFlag{
+				Name:  "force-unlock",
+				Usage: "take over database run lock left by another cert-csi process, use only if that process is no longer alive",
+			},
 		},
 		Before: updatePath,
 		Action: func(c *cli.Context) error {
@@ -177,6 +305,23 @@ func GetCertifyCommand() cli.Command {
 				return fmt.Errorf("can't find config file: %w", err)
 			}
 
+			var profile *driverprofile.Profile
+			if c.String("driver-profile") != "" {
+				if profile, err = driverprofile.Get(c.String("driver-profile")); err != nil {
+					return err
+				}
+				// Storage classes are decoded from raw config, so only keys they don't set are taken from profile
+				if entries, ok := viper.Get("storageClasses").([]interface{}); ok {
+					for _, e := range entries {
+						if entry, ok := e.(map[string]interface{}); ok {
+							profile.Apply(entry)
+						}
+					}
+					viper.Set("storageClasses", entries)
+				}
+				log.Infof("Using %s driver profile", color.CyanString(profile.Name))
+			}
+
 			var certConfig CertConfig
 			err = viper.Unmarshal(&certConfig)
 			if err != nil {
@@ -192,10 +337,69 @@ func GetCertifyCommand() cli.Command {
 			if err != nil {
 				return errors.New("timeout is wrong formatted")
 			}
+			if profile != nil && !c.IsSet("timeout") {
+				timeout = profile.Timeout.Duration
+			}
 			timeOutInSeconds := int(timeout.Seconds())
 
+			var verifier backend.Verifier
+			if c.String("backend-config") != "" {
+				verifier, err = backend.LoadConfig(c.String("backend-config"))
+				if err != nil {
+					return fmt.Errorf("can't create backend verifier: %v", err)
+				}
+			}
+
+			var extraMetadata *k8sclient.ExtraMetadata
+			if c.String("metadata-config") != "" {
+				extraMetadata, err = k8sclient.LoadExtraMetadata(c.String("metadata-config"))
+				if err != nil {
+					return fmt.Errorf("can't load extra metadata: %v", err)
+				}
+			}
+
+			classGuard, err := runner.ParseClassGuardMode(c.String("class-guard"))
+			if err != nil {
+				return err
+			}
+
+			teardownTimeouts, err := k8sclient.ParseTierTimeouts(c.String("teardown-timeouts"))
+			if err != nil {
+				return err
+			}
+
+			var backgroundLoad *load.Profile
+			if c.String("background-load") != "" {
+				if backgroundLoad, err = load.ParseProfile(c.String("background-load")); err != nil {
+					return err
+				}
+			}
+
 			var scDBs []*store.StorageClassDB
 			ss := make(map[string][]suites.Interface)
+			assertions := make(map[string]map[string]*collector.Assertions)
+			dependencies := make(map[string]map[string][]string)
+			waitConditions := make(map[string]map[string][]*waitcondition.Condition)
+
+			// Compared storage classes get volumes of the same size, large enough for every one of them
+			commonSize := resource.MustParse("8Gi")
+			if c.Bool("compare") {
+				if len(certConfig.StorageClasses) < 2 {
+					return errors.New("at least two storage classes are required to compare them")
+				}
+				commonSize = resource.Quantity{}
+				for _, sc := range certConfig.StorageClasses {
+					size := resource.MustParse("8Gi")
+					if sc.MinSize != "" {
+						if size, err = resource.ParseQuantity(sc.MinSize); err != nil {
+							return fmt.Errorf("invalid minSize of %s storage class: %w", sc.Name, err)
+						}
+					}
+					if size.Cmp(commonSize) > 0 {
+						commonSize = size
+					}
+				}
+			}
 
 			for _, sc := range certConfig.StorageClasses {
 				pathToDb := fmt.Sprintf("file:%s.db", sc.Name)
@@ -208,8 +412,8 @@ func GetCertifyCommand() cli.Command {
 
 				var s []suites.Interface
 
-				minSize := "8Gi"
-				if sc.MinSize != "" {
+				minSize := commonSize.String()
+				if sc.MinSize != "" && !c.Bool("compare") {
 					minSize = sc.MinSize
 				}
 
@@ -370,26 +574,66 @@ func GetCertifyCommand() cli.Command {
 						Image:           testImage,
 					})
 				}
+				if sc.IPv6 {
+					if c.String("driver-namespace") == "" {
+						return errors.New("driver-namespace required to verify `ipv6` capability")
+					}
+					s = append(s, &suites.IPv6Suite{
+						DriverNamespace: c.String("driver-namespace"),
+						VolumeSize:      minSize,
+						Image:           testImage,
+					})
+				}
 				log.Infof("Suites to run with %s storage class:", color.CyanString(sc.Name))
 				for i, suite := range s {
 					log.Infof("%d. %s %s", i+1, color.HiMagentaString(suite.GetName()), suite.Parameters())
 				}
+				if err := runner.ValidateDependencies(s, sc.Dependencies); err != nil {
+					return fmt.Errorf("invalid dependencies of %s storage class: %w", sc.Name, err)
+				}
+				for suite, deps := range sc.Dependencies {
+					log.Infof("%s runs only if %s succeed", color.HiMagentaString(suite), strings.Join(deps, ", "))
+				}
+				if err := runner.ValidateWaitConditions(s, sc.WaitConditions); err != nil {
+					return fmt.Errorf("invalid wait conditions of %s storage class: %w", sc.Name, err)
+				}
+				for suite, conds := range sc.WaitConditions {
+					for _, cond := range conds {
+						log.Infof("%s waits for %s until %s", color.HiMagentaString(suite), cond.Resource, cond.Expression)
+					}
+				}
 				ss[sc.Name] = s
+				assertions[sc.Name] = sc.Assertions
+				dependencies[sc.Name] = sc.Dependencies
+				waitConditions[sc.Name] = sc.WaitConditions
 			}
 
-			fmt.Println("Does it look OK? (Y)es/(n)o")
-			readerCleanup := bufio.NewReader(os.Stdin)
-			fmt.Print("-> ")
-			charCleanup, _, err := readerCleanup.ReadRune()
-			if err != nil {
-				log.Error(err)
+			if c.Bool("compare") {
+				var order []string
+				for _, sc := range certConfig.StorageClasses {
+					order = append(order, sc.Name)
+				}
+				var dropped []string
+				ss, dropped = runner.CommonSuites(order, ss)
+				for _, d := range dropped {
+					log.Warnf("%s isn't planned for every storage class, it won't run", d)
+				}
+				if len(ss[order[0]]) == 0 {
+					return errors.New("storage classes have no suites in common to compare")
+				}
+				log.Infof("Comparing %s side by side with %s volumes", strings.Join(order, ", "), color.CyanString(commonSize.String()))
 			}
+
+			charCleanup := utils.Prompt("Does it look OK? (Y)es/(n)o", 'y')
 			switch charCleanup {
 			case 'n', 'N':
 				log.Infof("Cancelling launch of certification")
 				return nil
 			}
 
+			if c.String("rbac-audit") != "" {
+				k8sclient.Audit = k8sclient.NewPermissionAudit()
+			}
 			sr := runner.NewSuiteRunner(
 				c.String("config"),
 				c.String("namespace"),
@@ -408,7 +652,37 @@ func GetCertifyCommand() cli.Command {
 				c.Bool("no-reports"),
 				scDBs,
 			)
+			sr.Seed = c.Int64("seed")
+			if sr.NameGenerator, err = k8sclient.NewNameGenerator(c.String("name-generator")); err != nil {
+				log.Fatal(err)
+			}
+			sr.ProgressAddress = c.String("progress-address")
+			sr.Assertions = assertions
+			sr.Dependencies = dependencies
+			sr.WaitConditions = waitConditions
+			sr.Backend = verifier
+			sr.ExtraMetadata = extraMetadata
+			sr.RBACAuditPath = c.String("rbac-audit")
+			sr.ClassGuard = classGuard
+			sr.TeardownTimeouts = teardownTimeouts
+			sr.KernelLogScan = c.Bool("kernel-log-scan")
+			sr.DriverHealthProbe = c.Bool("driver-health-probe")
+			sr.Namespaces = c.StringSlice("restricted-namespaces")
+			sr.RunTimeout = c.Duration("run-timeout")
+			sr.Fairness = c.Bool("fairness")
+			sr.Compare = c.Bool("compare")
+			sr.MarkBaseline = c.Bool("mark-baseline")
+			sr.RegressionThreshold = c.Float64("regression-threshold")
+			sr.FailOnRegression = c.Bool("fail-on-regression")
+			sr.BackgroundLoad = backgroundLoad
+			if profile != nil {
+				sr.CapabilityExpectations = profile.Expectations()
+			}
 
+			// Locks are taken last, so that setup failing above doesn't leave them behind
+			if err := store.AcquireRunLocks(scDBs, c.Bool("force-unlock")); err != nil {
+				return err
+			}
 			sr.RunSuites(ss)
 			return nil
 		},
@@ -17,14 +17,13 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/utils"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -55,16 +54,12 @@ func GetCleanupCommand() cli.Command {
 		Category: "main",
 		Flags:    globalFlags,
 		Action: func(c *cli.Context) error {
-			fmt.Println("*** THIS WILL DELETE ALL NAMESPACES AND RESOURCES THAT HAVE A \"-test-\" or \"-suite-\" IN THEIR NAMES ***")
-			fmt.Println("Are you sure (y/N)")
+			fmt.Println("*** THIS WILL DELETE ALL NAMESPACES AND RESOURCES THAT HAVE A \"-test-\" or \"-suite-\" IN THEIR NAMES AND TEMPORARY CLASSES ***")
 			if !c.Bool("yes") {
-				reader := bufio.NewReader(os.Stdin)
-				fmt.Print("-> ")
-				char, _, err := reader.ReadRune()
-				if err != nil {
-					log.Error(err)
+				if utils.IsNonInteractive() {
+					return fmt.Errorf("refusing to cleanup in non-interactive mode without --yes")
 				}
-
+				char := utils.Prompt("Are you sure (y/N)", 'n')
 				if !(char == 'y' || char == 'Y') {
 					fmt.Println("Exiting...")
 					return nil
@@ -103,6 +98,13 @@ func GetCleanupCommand() cli.Command {
 				}
 			}
 			log.Infof("No suitable namespaces left")
+
+			deleted, err := kubeClient.DeleteLeftoverClasses(context.Background())
+			if err != nil {
+				log.Errorf("Can't delete temporary storage and snapshot classes; error=%v", err)
+			} else if deleted != 0 {
+				log.Infof("Deleted %d temporary storage and snapshot classes", deleted)
+			}
 			return nil
 		},
 	}
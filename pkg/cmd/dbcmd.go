@@ -0,0 +1,204 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dell/cert-csi/pkg/auditlog"
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// GetDatabaseCommand returns db CLI command
+func GetDatabaseCommand() cli.Command {
+	return cli.Command{
+		Name:     "db",
+		Usage:    "database management",
+		Category: "main",
+		Subcommands: []cli.Command{
+			{
+				Name:  "migrate",
+				Usage: "copies all runs, test cases, entities and events of SQLite database to another store keeping their ids, ids target already has are moved past its ones",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "from",
+						Usage: "path to SQLite database to copy from",
+					},
+					cli.StringFlag{
+						Name:  "to",
+						Usage: "postgres:// URL or path to SQLite database to copy to, Postgres copy is for querying runs with other tools as cert-csi reads SQLite only",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					from, to := c.String("from"), c.String("to")
+					if from == "" || to == "" {
+						return errors.New("both --from and --to are required")
+					}
+					if _, err := os.Stat(from); err != nil {
+						return fmt.Errorf("can't open source database: %w", err)
+					}
+					if !store.IsPostgresDSN(to) {
+						to = "file:" + to
+					}
+
+					source := store.NewSQLiteStore("file:" + from)
+					defer source.Close()
+					migrated, err := source.Migrate(to)
+					if err != nil {
+						return fmt.Errorf("migration failed, nothing was copied: %w", err)
+					}
+					for _, m := range migrated {
+						if m.IDOffset != 0 {
+							log.Infof("Copied %d rows of %s, ids moved by %d as target already had them", m.Rows, color.CyanString(m.Name), m.IDOffset)
+							continue
+						}
+						log.Infof("Copied %d rows of %s", m.Rows, color.CyanString(m.Name))
+					}
+					log.Infof("Migrated %s", color.GreenString(from))
+					return nil
+				},
+			},
+			{
+				Name:      "audit",
+				Usage:     "attaches API server audit log entries which changed PVCs, volumes, pods and snapshots of the run to them, so reports show who changed them and when",
+				ArgsUsage: "<audit log>",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "testrun, tr",
+						Usage: "name of the run audit log covers",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					path := c.Args().First()
+					if path == "" || c.String("testrun") == "" {
+						return errors.New("both audit log and --testrun are required")
+					}
+					f, err := os.Open(path) // #nosec G304
+					if err != nil {
+						return fmt.Errorf("can't open audit log: %w", err)
+					}
+					defer f.Close()
+					db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+					defer db.Close()
+					res, err := auditlog.Ingest(db, c.String("testrun"), f)
+					if err != nil {
+						return err
+					}
+					if res.Malformed != 0 {
+						log.Warnf("Skipped %d malformed audit events", res.Malformed)
+					}
+					log.Infof("Read %d audit events, %d changed entities of run %s, %s new", res.Read, res.Matched,
+						color.CyanString(c.String("testrun")), color.GreenString("%d", res.Added))
+					if res.Added == 0 {
+						return nil
+					}
+					// Audit entries are one of timeline sources, so timelines of the run are merged again
+					runs, err := db.GetTestRuns(store.Conditions{"name": c.String("testrun")}, "", 1)
+					if err != nil || len(runs) == 0 {
+						return err
+					}
+					testCases, err := db.GetTestCases(store.Conditions{"run_id": runs[0].ID}, "", 0)
+					if err != nil {
+						return err
+					}
+					for i := range testCases {
+						if _, err := collector.MergeTimeline(db, &testCases[i]); err != nil {
+							return fmt.Errorf("can't merge timeline of %s: %w", testCases[i].Name, err)
+						}
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "compact",
+				Usage: "removes duplicate events and orphaned entities and vacuums database, reporting space reclaimed",
+				Flags: []cli.Flag{
+					cli.DurationFlag{
+						Name:  "window",
+						Usage: "events of the same type and message of an entity within window of the first one are duplicates, widen it to remove events replayed after watch reconnects",
+						Value: store.EventDedupBucket,
+					},
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "only report what would be removed",
+					},
+					cli.BoolFlag{
+						Name:  "force-unlock",
+						Usage: "compact database locked by run which is no longer alive",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					if _, err := os.Stat(c.GlobalString("db")); err != nil {
+						return fmt.Errorf("can't open database: %w", err)
+					}
+					db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+					defer db.Close()
+					// Run writing to database would lose events saved while compaction is in progress
+					if err := db.AcquireRunLock(c.Bool("force-unlock")); err != nil {
+						return err
+					}
+					res, err := db.Compact(c.Duration("window"), c.Bool("dry-run"))
+					if err != nil {
+						return fmt.Errorf("compaction failed, nothing was removed: %w", err)
+					}
+					verb := "Removed"
+					if c.Bool("dry-run") {
+						verb = "Would remove"
+					}
+					log.Infof("%s %d duplicate events, %d orphaned entities and %d events of missing entities", verb,
+						res.DuplicateEvents, res.OrphanedEntities, res.OrphanedEvents)
+					if !c.Bool("dry-run") {
+						log.Infof("Reclaimed %s, database is %.1fMi now", color.GreenString("%.1fMi", float64(res.Reclaimed())/(1<<20)),
+							float64(res.SizeAfter)/(1<<20))
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "baseline",
+				Usage:     "marks finished run as baseline of its storage class, later runs are compared with it",
+				ArgsUsage: "<run name>",
+				Action: func(c *cli.Context) error {
+					name := c.Args().First()
+					if name == "" {
+						return errors.New("name of the run is required")
+					}
+					db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+					defer db.Close()
+					runs, err := db.GetTestRuns(store.Conditions{"name": name}, "", 1)
+					if err != nil {
+						return err
+					}
+					if len(runs) == 0 {
+						return fmt.Errorf("can't find run %s", name)
+					}
+					if err := db.MarkBaselineRun(&runs[0]); err != nil {
+						return err
+					}
+					log.Infof("Run %s is the baseline of storage class %s now", color.CyanString(name), runs[0].StorageClass)
+					return nil
+				},
+			},
+		},
+	}
+}
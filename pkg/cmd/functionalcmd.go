@@ -33,9 +33,9 @@ import (
 	"github.com/urfave/cli"
 )
 
-// GetFunctionalTestCommand returns a `functional-test` command with all prepared sub-commands
-func GetFunctionalTestCommand() cli.Command {
-	globalFlags := []cli.Flag{
+// getFunctionalGlobalFlags returns flags shared by all `functional-test` sub-commands
+func getFunctionalGlobalFlags() []cli.Flag {
+	return []cli.Flag{
 		cli.StringFlag{
 			Name:   "config, conf, c",
 			Usage:  "config for connecting to kubernetes",
@@ -66,20 +66,27 @@ func GetFunctionalTestCommand() cli.Command {
 			Name:  "image-config",
 			Usage: "path to images config file",
 		},
+		cli.BoolFlag{
+			Name:  "force-unlock",
+			Usage: "take over database run lock left by another cert-csi process, use only if that process is no longer alive",
+		},
 	}
+}
+
+// GetFunctionalTestCommand returns a `functional-test` command with all prepared sub-commands
+func GetFunctionalTestCommand() cli.Command {
+	globalFlags := getFunctionalGlobalFlags()
 
 	listCmd := cli.Command{
 		Name:     "list",
 		Usage:    "lists all available test suites",
 		Category: "functional-test",
 		Action: func(_ *cli.Context) error {
-			fmt.Println("volume-creation")
-			fmt.Println("pod-creation")
-			fmt.Println("volume-deletion")
-			fmt.Println("pod-deletion")
-			fmt.Println("cloned-volume-deletion")
-			fmt.Println("node-drain")
-			fmt.Println("node-uncordon")
+			for _, info := range suites.Registered() {
+				if name, ok := strings.CutPrefix(info.Command, "functional-test "); ok {
+					fmt.Println(name)
+				}
+			}
 			return nil
 		},
 	}
@@ -103,6 +110,7 @@ func GetFunctionalTestCommand() cli.Command {
 			getNodeDrainCommand(globalFlags),
 			getNodeUnCordonCommand(globalFlags),
 			getCapacityTrackingCommand(globalFlags),
+			getInvalidParamsCommand(globalFlags),
 		},
 	}
 
@@ -143,7 +151,7 @@ func createFunctionalSuiteRunner(c *cli.Context) *runner.FunctionalSuiteRunner {
 	}
 	log.SetOutput(io.MultiWriter(os.Stdout, logFile))
 
-	return runner.NewFunctionalSuiteRunner(
+	sr := runner.NewFunctionalSuiteRunner(
 		c.String("config"),
 		c.String("namespace"),
 		timeOutInSeconds,
@@ -152,6 +160,11 @@ func createFunctionalSuiteRunner(c *cli.Context) *runner.FunctionalSuiteRunner {
 		c.Bool("no-reports"),
 		scDB,
 	)
+	// Lock is taken last, so that setup failing above doesn't leave it behind
+	if err := DB.AcquireRunLock(c.Bool("force-unlock")); err != nil {
+		log.Fatalf("Can't use database %s; error=%v", pathToDb, err)
+	}
+	return sr
 }
 
 func getVolumeDeletionCommand(globalFlags []cli.Flag) cli.Command {
@@ -898,3 +911,55 @@ func getCapacityTrackingCommand(globalFlags []cli.Flag) cli.Command {
 		},
 	}
 }
+
+func getInvalidParamsCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "invalid-params",
+		Usage:    "clones storage class with sets of invalid parameters and validates that provisioning fails fast with an event and leaves no volume behind",
+		Category: "functional-test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:     "sc, storage, storageclass",
+					Usage:    "storage csi",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "parameter-sets",
+					Usage: "path to yaml config with invalid parameter sets, see example-invalid-params-config.yaml",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.DurationFlag{
+					Name:  "fail-timeout",
+					Usage: "consider provisioning of parameter set hung if it doesn't fail within this time",
+					Value: suites.InvalidParamsTimeout,
+				},
+			},
+			globalFlags...,
+		),
+		Action: func(c *cli.Context) error {
+			var parameterSets []suites.InvalidParameterSet
+			if c.String("parameter-sets") != "" {
+				var err error
+				parameterSets, err = suites.LoadInvalidParameterSets(c.String("parameter-sets"))
+				if err != nil {
+					return err
+				}
+			}
+			s := []suites.Interface{
+				&suites.InvalidSCParamsSuite{
+					ParameterSets: parameterSets,
+					VolumeSize:    c.String("size"),
+					FailTimeout:   c.Duration("fail-timeout"),
+				},
+			}
+			sr := createFunctionalSuiteRunner(c)
+			sr.RunFunctionalSuites(s)
+
+			return nil
+		},
+	}
+}
@@ -17,13 +17,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
 
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
@@ -37,6 +40,7 @@ func GetListCommand() cli.Command {
 		Category: "main",
 		Subcommands: []cli.Command{
 			getTestrunsCmd(),
+			getSuitesCmd(),
 		},
 	}
 
@@ -83,7 +87,9 @@ func getTestrunsCmd() cli.Command {
 					success = success && tc.Success
 				}
 				var result string
-				if success {
+				if run.Aborted() {
+					result = color.HiYellowString("ABORTED") + " "
+				} else if success {
 					result = color.HiGreenString("SUCCESS") + " "
 				} else {
 					result = color.HiRedString("FAILURE") + " "
@@ -107,3 +113,116 @@ func getTestrunsCmd() cli.Command {
 		},
 	}
 }
+
+func getSuitesCmd() cli.Command {
+	const padding = 3
+	return cli.Command{
+		Name:      "suites",
+		ShortName: "s",
+		Usage:     "lists available suites with their parameters and required cluster capabilities",
+		Category:  "list",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "output, o",
+				Usage: "output format, one of [table] or [json]",
+				Value: "table",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			infos := describeSuites()
+
+			switch c.String("output") {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(infos)
+			case "table":
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.TabIndent)
+				_, _ = fmt.Fprintln(w, "COMMAND\tSUITE\tPARAMETERS\tCAPABILITIES\tDESCRIPTION\t")
+				for _, info := range infos {
+					params := make([]string, 0, len(info.Parameters))
+					for _, p := range info.Parameters {
+						params = append(params, p.Name)
+					}
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n",
+						color.YellowString(info.Command),
+						info.Name,
+						strings.Join(params, ","),
+						strings.Join(info.Capabilities, ","),
+						info.Description,
+					)
+				}
+				return w.Flush()
+			default:
+				return fmt.Errorf("unknown output format %s", c.String("output"))
+			}
+		},
+	}
+}
+
+// describeSuites returns registered suites with parameters discovered from flags of their commands
+func describeSuites() []suites.Info {
+	commands := map[string]cli.Command{}
+	for _, parent := range []struct {
+		cmd    cli.Command
+		global []cli.Flag
+	}{
+		{GetTestCommand(), getTestGlobalFlags()},
+		{GetFunctionalTestCommand(), getFunctionalGlobalFlags()},
+	} {
+		global := make(map[string]bool)
+		for _, f := range parent.global {
+			global[f.GetName()] = true
+		}
+		for _, sub := range parent.cmd.Subcommands {
+			var own []cli.Flag
+			for _, f := range sub.Flags {
+				if !global[f.GetName()] {
+					own = append(own, f)
+				}
+			}
+			sub.Flags = own
+			commands[parent.cmd.Name+" "+sub.Name] = sub
+		}
+	}
+
+	infos := suites.Registered()
+	for i, info := range infos {
+		cmd, ok := commands[info.Command]
+		if !ok || len(info.Parameters) != 0 {
+			continue
+		}
+		for _, f := range cmd.Flags {
+			infos[i].Parameters = append(infos[i].Parameters, flagParameter(f))
+		}
+	}
+	return infos
+}
+
+func flagParameter(f cli.Flag) suites.Parameter {
+	p := suites.Parameter{Name: "--" + strings.Split(f.GetName(), ",")[0]}
+	switch flag := f.(type) {
+	case cli.StringFlag:
+		p.Description, p.Default, p.Required = flag.Usage, flag.Value, flag.Required
+	case cli.IntFlag:
+		p.Description, p.Required = flag.Usage, flag.Required
+		if flag.Value != 0 {
+			p.Default = strconv.Itoa(flag.Value)
+		}
+	case cli.Int64Flag:
+		p.Description, p.Required = flag.Usage, flag.Required
+		if flag.Value != 0 {
+			p.Default = strconv.FormatInt(flag.Value, 10)
+		}
+	case cli.BoolFlag:
+		p.Description, p.Required = flag.Usage, flag.Required
+	case cli.DurationFlag:
+		p.Description, p.Required = flag.Usage, flag.Required
+		if flag.Value != 0 {
+			p.Default = flag.Value.String()
+		}
+	case cli.StringSliceFlag:
+		p.Description, p.Required = flag.Usage, flag.Required
+	}
+	return p
+}
@@ -0,0 +1,91 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/query"
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/urfave/cli"
+)
+
+// GetQueryCommand returns query CLI command
+func GetQueryCommand() cli.Command {
+	return cli.Command{
+		Name:     "query",
+		Usage:    "prints PVCs and pods of a run matching conditions with their event timelines, ex. query --run my-run --kind pvc --where 'bind_time>30s'",
+		Category: "main",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "testrun, tr, run",
+				Usage: "name of the run to query",
+			},
+			cli.StringFlag{
+				Name:  "kind, k",
+				Usage: "kind of entities, [pvc] or [pod], both if not set",
+			},
+			cli.StringSliceFlag{
+				Name: "where, w",
+				Usage: "condition entities must match, repeat for several: name, test_case, failed and stages (bind_time, wait_time, attach_time, detach_time, " +
+					"ready_time, creation_time, deletion_time or stage name, ex. PVCBind) compared with >, >=, <, <=, =, != and ~ (substring), " +
+					"ex. bind_time>30s, name~vol, failed=true",
+			},
+			cli.StringFlag{
+				Name:  "output, o",
+				Usage: "output format, one of [table] or [json]",
+				Value: "table",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.String("testrun") == "" {
+				return errors.New("name of the run is required")
+			}
+			var conditions []query.Condition
+			for _, w := range c.StringSlice("where") {
+				cond, err := query.ParseCondition(w)
+				if err != nil {
+					return err
+				}
+				conditions = append(conditions, cond)
+			}
+			output := c.String("output")
+			if output != "table" && output != "json" {
+				return fmt.Errorf("unknown output format %s", output)
+			}
+
+			db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+			defer db.Close()
+			mc, err := collector.NewMetricsCollector(db).Collect(c.String("testrun"))
+			if err != nil {
+				return err
+			}
+			entities, err := query.Run(mc, c.String("kind"), conditions)
+			if err != nil {
+				return err
+			}
+			if output == "json" {
+				return query.WriteJSON(os.Stdout, entities)
+			}
+			return query.WriteTable(os.Stdout, entities)
+		},
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/queue"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var queueDirFlag = cli.StringFlag{
+	Name:  "dir",
+	Usage: "directory of the queue, each run gets its own subdirectory its databases, logs and reports are written to",
+	Value: "queue",
+}
+
+var queueTokenFlag = cli.StringFlag{
+	Name:   "token",
+	Usage:  "bearer token of queue API, by default it's generated and kept in token file of the queue directory",
+	EnvVar: "CERT_CSI_QUEUE_TOKEN",
+}
+
+// GetQueueCommand returns queue CLI command
+func GetQueueCommand() cli.Command {
+	return cli.Command{
+		Name:     "queue",
+		Usage:    "persistent run queue executed by a long-running daemon, so runs submitted by different people never overlap",
+		Category: "main",
+		Subcommands: []cli.Command{
+			{
+				Name:  "daemon",
+				Usage: "executes queued runs one by one, or with bounded parallelism, until interrupted",
+				Flags: []cli.Flag{
+					queueDirFlag,
+					cli.IntFlag{
+						Name:  "workers",
+						Usage: "number of runs executed at once",
+						Value: 1,
+					},
+					cli.StringFlag{
+						Name:  "address",
+						Usage: "address to serve queue API on, address without host binds to localhost only, empty disables API",
+						Value: ":8090",
+					},
+					queueTokenFlag,
+				},
+				Action: func(c *cli.Context) error {
+					q, err := queue.New(c.String("dir"))
+					if err != nil {
+						return err
+					}
+					release, err := q.Lock()
+					if err != nil {
+						return err
+					}
+					defer release()
+					executable, err := os.Executable()
+					if err != nil {
+						return err
+					}
+
+					// Each run is a separate process working in its own directory, so runs don't share databases
+					// and fatal errors of one run don't stop the daemon. Interrupted runs get SIGINT to clean up
+					job := func(ctx context.Context, e *queue.Entry, dir string) error {
+						run := exec.CommandContext(ctx, executable, e.Args...) // #nosec
+						run.Dir = dir
+						run.Stdout = os.Stdout
+						run.Stderr = os.Stderr
+						run.Cancel = func() error {
+							return run.Process.Signal(os.Interrupt)
+						}
+						return run.Run()
+					}
+					pool := queue.NewPool(q, c.Int("workers"), job)
+
+					if c.String("address") != "" {
+						token := c.String("token")
+						if token == "" {
+							if token, err = q.Token(); err != nil {
+								return err
+							}
+						}
+						server := queue.NewServer(c.String("address"), token, pool)
+						addr, err := server.Start()
+						if err != nil {
+							return err
+						}
+						defer server.Stop()
+						log.Infof("Accepting runs at %s", color.CyanString("http://"+addr+"/runs"))
+					}
+
+					ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+					defer stop()
+					log.Infof("Executing runs queued in %s with %d workers", color.CyanString(q.Dir), pool.Workers)
+					if err := pool.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+						return err
+					}
+					log.Infof("Queue daemon stopped")
+					return nil
+				},
+			},
+			{
+				Name:      "submit",
+				Usage:     "appends run to the queue, ex. queue submit -- test vio --sc sc",
+				ArgsUsage: "-- <cert-csi arguments>",
+				Flags: []cli.Flag{
+					queueDirFlag,
+					cli.StringFlag{
+						Name:  "address",
+						Usage: "address of daemon queue API to submit to instead of writing to queue directory",
+					},
+					queueTokenFlag,
+				},
+				Action: func(c *cli.Context) error {
+					args := []string(c.Args())
+					if len(args) == 0 {
+						return errors.New("cert-csi arguments of the run are required, ex. -- test vio --sc sc")
+					}
+					if err := queue.ValidateArgs(args); err != nil {
+						return err
+					}
+					var e *queue.Entry
+					var err error
+					if c.String("address") != "" {
+						token := c.String("token")
+						if token == "" {
+							if token, err = queue.ReadToken(c.String("dir")); err != nil {
+								return fmt.Errorf("can't read queue API token, pass it with --token: %w", err)
+							}
+						}
+						e, err = queue.SubmitTo(c.String("address"), token, args)
+					} else {
+						var q *queue.Queue
+						if q, err = queue.New(c.String("dir")); err == nil {
+							e, err = q.Submit(args)
+						}
+					}
+					if err != nil {
+						return err
+					}
+					log.Infof("Submitted run %s", color.CyanString(e.ID))
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "lists queued, running and finished runs",
+				Flags: []cli.Flag{queueDirFlag},
+				Action: func(c *cli.Context) error {
+					q, err := queue.New(c.String("dir"))
+					if err != nil {
+						return err
+					}
+					entries, err := q.List()
+					if err != nil {
+						return err
+					}
+					for _, e := range entries {
+						fmt.Printf("%s  %-9s  %s  %s\n", e.ID, e.State, e.Submitted.Format(time.RFC3339), strings.Join(e.Args, " "))
+						if e.Error != "" {
+							fmt.Printf("          %s\n", color.RedString(e.Error))
+						}
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
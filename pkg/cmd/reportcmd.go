@@ -49,15 +49,51 @@ func GetReportCommand() cli.Command {
 			Usage: "specifies if qTest xml report should be generated",
 		},
 		cli.StringFlag{
-			Name:  "reportPath, path",
+			Name:  "format, f",
+			Usage: "comma separated report formats to generate from stored data, ex. html,txt,json,sarif,tabular,junit,comparison",
+		},
+		cli.StringFlag{
+			Name:  "reportPath, path, output-dir",
 			Usage: "path to folder where reports will be created (if not specified `~/.cert-csi/` will be used)",
 		},
+		cli.StringFlag{
+			Name:  "report-layout",
+			Usage: "layout of reports and plots inside the output folder: [per-run], [flat] or [timestamped]",
+			Value: string(plotter.LayoutPerRun),
+		},
+		cli.StringFlag{
+			Name:  "plot-format",
+			Usage: "file format of plots: [png], [svg] or [pdf], vector formats stay crisp when embedded into documents",
+			Value: string(plotter.FormatPNG),
+		},
+		cli.StringFlag{
+			Name:  "slo",
+			Usage: "latency objectives drawn on SLO burn-down chart of html report, ex. PVCBind=10s@95,PodCreation=1m (target defaults to 99%)",
+		},
+		cli.DurationFlag{
+			Name:  "slo-window",
+			Usage: "rolling window of SLO burn-down chart",
+			Value: reporter.SLOWindow,
+		},
+		cli.BoolFlag{
+			Name:  "redact",
+			Usage: "replace cluster, node and namespace names and IP addresses with stable pseudonyms, so reports can be shared outside of the environment",
+		},
+		cli.IntFlag{
+			Name:  "max-plot-points",
+			Usage: "maximum number of points drawn per chart series, longer series are downsampled to keep memory bounded (0 disables)",
+			Value: plotter.MaxPlotPoints,
+		},
+		cli.IntFlag{
+			Name:  "max-detailed-entities",
+			Usage: "list only failed and this many slowest PVCs and pods of every test case in html report, JSON report keeps all of them (0 lists all)",
+		},
 	}
 
 	var testRunNames cli.StringSlice
 
 	testRunNamesFlag := cli.StringSliceFlag{
-		Name:     "testrun, tr",
+		Name:     "testrun, tr, run",
 		Usage:    "test run names from which reports will be generated (file.db:testrun)",
 		EnvVar:   "TESTRUNNAMES",
 		Required: true,
@@ -94,12 +130,15 @@ func GetReportCommand() cli.Command {
 				}
 			}()
 
-			if c.String("path") != "" {
-				plotter.UserPath = c.String("path")
-				plotter.FolderPath = ""
+			if err := updatePath(c); err != nil {
+				return err
 			}
+			reporter.Redact = c.Bool("redact")
 
-			var multiTypes []reporter.ReportType
+			types, multiTypes, err := parseReportFormats(c.String("format"))
+			if err != nil {
+				return err
+			}
 			if c.Bool("xml") {
 				multiTypes = append(multiTypes, reporter.XMLReport)
 			}
@@ -115,7 +154,6 @@ func GetReportCommand() cli.Command {
 				}
 			}
 
-			var types []reporter.ReportType
 			if c.Bool("html") {
 				types = append(types, reporter.HTMLReport)
 			}
@@ -123,7 +161,10 @@ func GetReportCommand() cli.Command {
 				types = append(types, reporter.TextReport)
 			}
 
-			var err error
+			if len(types) == 0 && len(multiTypes) != 0 && c.String("format") != "" {
+				// Only multi run formats were requested
+				return nil
+			}
 			if len(types) == 0 {
 				err = reporter.GenerateAllReports(scDBs)
 			} else {
@@ -141,6 +182,25 @@ func GetReportCommand() cli.Command {
 	return reportCmd
 }
 
+// parseReportFormats splits comma separated formats into single and multi run report types
+func parseReportFormats(formats string) (types []reporter.ReportType, multiTypes []reporter.ReportType, err error) {
+	if formats == "" {
+		return nil, nil, nil
+	}
+	for _, format := range strings.Split(formats, ",") {
+		reportType, multi, err := reporter.ParseReportType(format)
+		if err != nil {
+			return nil, nil, err
+		}
+		if multi {
+			multiTypes = append(multiTypes, reportType)
+		} else {
+			types = append(types, reportType)
+		}
+	}
+	return types, multiTypes, nil
+}
+
 func parseTestRun(tr string) (db string, name string) {
 	s := strings.Split(tr, ":")
 	if len(s) == 1 {
@@ -0,0 +1,99 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/dell/cert-csi/pkg/schedule"
+	"github.com/dell/cert-csi/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// GetScheduleCommand returns schedule CLI command
+func GetScheduleCommand() cli.Command {
+	return cli.Command{
+		Name:      "schedule",
+		Usage:     "runs cert-csi command repeatedly on cron schedule from a long-running process, ex. schedule --cron '0 2 * * *' -- test vio --sc sc",
+		ArgsUsage: "-- <cert-csi arguments>",
+		Category:  "main",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "cron",
+				Usage: "cron expression runs are triggered at: minute hour day-of-month month day-of-week, or @hourly, @daily, @weekly",
+			},
+			cli.StringFlag{
+				Name:  "dir",
+				Usage: "directory each run gets its own tagged subdirectory in, databases and logs of the run are written there",
+				Value: "schedule",
+			},
+			cli.IntFlag{
+				Name:  "retain",
+				Usage: "number of the latest runs to keep, older run directories are pruned, 0 keeps all",
+				Value: 10,
+			},
+			cli.IntFlag{
+				Name:  "max-runs",
+				Usage: "stop after that many runs, 0 runs until interrupted",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			cron, err := schedule.ParseCron(c.String("cron"))
+			if err != nil {
+				return err
+			}
+			args := []string(c.Args())
+			if len(args) == 0 {
+				return errors.New("cert-csi arguments of scheduled runs are required, ex. -- test vio --sc sc")
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return err
+			}
+
+			// Each run is a separate process, so fatal errors of one run don't stop the scheduler. Nobody answers
+			// prompts of unattended runs, and runs interrupted by stopping the scheduler get SIGINT to clean up
+			job := func(ctx context.Context, _, dir string) error {
+				run := exec.CommandContext(ctx, executable, args...) // #nosec
+				run.Dir = dir
+				run.Env = append(os.Environ(), utils.NonInteractiveEnv+"=1")
+				run.Stdout = os.Stdout
+				run.Stderr = os.Stderr
+				run.Cancel = func() error {
+					return run.Process.Signal(os.Interrupt)
+				}
+				return run.Run()
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			s := schedule.NewScheduler(cron, c.String("dir"), c.Int("retain"), c.Int("max-runs"), job)
+			if err := s.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			log.Infof("Scheduler stopped")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/reporter"
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var seriesFlag = cli.StringFlag{
+	Name:     "series, s",
+	Usage:    "name of certification series",
+	Required: true,
+}
+
+// GetSeriesCommand returns series CLI command
+func GetSeriesCommand() cli.Command {
+	return cli.Command{
+		Name:     "series",
+		Usage:    "links runs of the same cluster and storage class testing successive driver versions into certification series",
+		Category: "main",
+		Subcommands: []cli.Command{
+			{
+				Name:  "link",
+				Usage: "adds run to certification series as the run of driver version, linking run again replaces its version",
+				Flags: []cli.Flag{
+					seriesFlag,
+					cli.StringFlag{
+						Name:     "testrun, tr, run",
+						Usage:    "name of the run to link",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "driver-version",
+						Usage:    "version of the driver run tested",
+						Required: true,
+					},
+				},
+				Action: func(c *cli.Context) error {
+					db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+					defer db.Close()
+					runs, err := db.GetTestRuns(store.Conditions{"name": c.String("run")}, "", 1)
+					if err != nil {
+						return err
+					}
+					if len(runs) == 0 {
+						return fmt.Errorf("run %s doesn't exist in %s", c.String("run"), c.GlobalString("db"))
+					}
+					err = db.SaveSeriesRuns([]*store.SeriesRun{{
+						Series:         c.String("series"),
+						RunID:          runs[0].ID,
+						DriverVersion:  c.String("driver-version"),
+						AddedTimestamp: time.Now(),
+					}})
+					if err != nil {
+						return err
+					}
+					log.Infof("Linked run %s into series %s as driver version %s", color.CyanString(runs[0].Name),
+						color.CyanString(c.String("series")), color.CyanString(c.String("driver-version")))
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "lists runs of certification series",
+				Flags: []cli.Flag{seriesFlag},
+				Action: func(c *cli.Context) error {
+					db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+					defer db.Close()
+					links, err := db.GetSeriesRuns(store.Conditions{"series": c.String("series")}, "", 0)
+					if err != nil {
+						return err
+					}
+					for _, link := range links {
+						runs, err := db.GetTestRuns(store.Conditions{"id": link.RunID}, "", 1)
+						if err != nil {
+							return err
+						}
+						if len(runs) == 0 {
+							continue
+						}
+						fmt.Printf("%-12s  %s  %s\n", link.DriverVersion, runs[0].StartTimestamp.Format(time.RFC3339), runs[0].Name)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "report",
+				Usage: "generates html report showing how capabilities and metrics evolved across driver versions of certification series",
+				Flags: []cli.Flag{
+					seriesFlag,
+					cli.StringFlag{
+						Name:  "reportPath, path, output-dir",
+						Usage: "path to folder where report will be created (if not specified `~/.cert-csi/` will be used)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					if c.String("path") != "" {
+						plotter.UserPath = c.String("path")
+						plotter.FolderPath = ""
+					}
+					db := store.NewSQLiteStore("file:" + c.GlobalString("db"))
+					defer db.Close()
+					if err := reporter.GenerateSeriesReport(db, c.String("series")); err != nil {
+						return err
+					}
+					log.Infof("Generated report of series %s", color.CyanString(c.String("series")))
+					return nil
+				},
+			},
+		},
+	}
+}
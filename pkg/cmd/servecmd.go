@@ -0,0 +1,92 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/reporter"
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// GetServeCommand returns serve CLI command
+func GetServeCommand() cli.Command {
+	return cli.Command{
+		Name:     "serve",
+		Usage:    "hosts reports of runs stored in database over HTTP, reports are rendered on request",
+		Category: "main",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "db",
+				Usage: "path to SQLite database runs are served from",
+			},
+			cli.StringFlag{
+				Name:  "address",
+				Usage: "address to listen on, address without host binds to localhost only",
+				Value: ":8080",
+			},
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "path to folder where plots of served reports are written",
+			},
+			cli.BoolFlag{
+				Name:  "no-download",
+				Usage: "don't offer database file for download",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			db := c.String("db")
+			if db == "" {
+				return errors.New("--db is required")
+			}
+			if _, err := os.Stat(db); err != nil {
+				return fmt.Errorf("can't open database: %w", err)
+			}
+			if c.String("path") != "" {
+				plotter.UserPath = c.String("path")
+			}
+
+			st := store.NewSQLiteStore("file:" + db)
+			defer st.Close()
+			server := reporter.NewServer(c.String("address"), st)
+			if !c.Bool("no-download") {
+				server.DatabasePath = db
+			}
+			addr, err := server.Start()
+			if err != nil {
+				return err
+			}
+			log.Infof("Serving reports of %s at %s", db, color.CyanString("http://"+addr))
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			<-ctx.Done()
+			server.Stop()
+			log.Infof("Report server stopped")
+			return nil
+		},
+	}
+}
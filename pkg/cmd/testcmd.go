@@ -17,10 +17,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/backend"
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/driverprofile"
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/velero"
+	"github.com/dell/cert-csi/pkg/load"
 	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/reporter"
 	"github.com/dell/cert-csi/pkg/store"
 	"github.com/dell/cert-csi/pkg/testcore"
 	"github.com/dell/cert-csi/pkg/testcore/runner"
@@ -31,9 +41,9 @@ import (
 	"github.com/urfave/cli"
 )
 
-// GetTestCommand returns a `test` command with all prepared sub-commands
-func GetTestCommand() cli.Command {
-	globalFlags := []cli.Flag{
+// getTestGlobalFlags returns flags shared by all `test` sub-commands
+func getTestGlobalFlags() []cli.Flag {
+	return []cli.Flag{
 		cli.StringFlag{
 			Name:   "config, conf, c",
 			Usage:  "config for connecting to kubernetes",
@@ -94,9 +104,37 @@ func GetTestCommand() cli.Command {
 			Value: "event",
 		},
 		cli.StringFlag{
-			Name:  "reportPath, path",
+			Name:  "reportPath, path, output-dir",
 			Usage: "path to folder where reports will be created (if not specified `~/.cert-csi/` will be used)",
 		},
+		cli.StringFlag{
+			Name:  "report-layout",
+			Usage: "layout of reports and plots inside the output folder: [per-run], [flat] or [timestamped]",
+			Value: string(plotter.LayoutPerRun),
+		},
+		cli.StringFlag{
+			Name:  "plot-format",
+			Usage: "file format of plots: [png], [svg] or [pdf], vector formats stay crisp when embedded into documents",
+			Value: string(plotter.FormatPNG),
+		},
+		cli.StringFlag{
+			Name:  "slo",
+			Usage: "latency objectives drawn on SLO burn-down chart of html report, ex. PVCBind=10s@95,PodCreation=1m (target defaults to 99%)",
+		},
+		cli.DurationFlag{
+			Name:  "slo-window",
+			Usage: "rolling window of SLO burn-down chart",
+			Value: reporter.SLOWindow,
+		},
+		cli.IntFlag{
+			Name:  "max-plot-points",
+			Usage: "maximum number of points drawn per chart series, longer series are downsampled to keep memory bounded (0 disables)",
+			Value: plotter.MaxPlotPoints,
+		},
+		cli.IntFlag{
+			Name:  "max-detailed-entities",
+			Usage: "list only failed and this many slowest PVCs and pods of every test case in html report, JSON report keeps all of them (0 lists all)",
+		},
 		cli.StringFlag{
 			Name:  "cooldown, cd",
 			Usage: "set to add cooldown time between iterations, format is time (ex. 3d.2h30m15s)",
@@ -110,7 +148,123 @@ func GetTestCommand() cli.Command {
 			Name:  "image-config",
 			Usage: "path to images config file",
 		},
+		cli.Int64Flag{
+			Name:  "seed",
+			Usage: "seed for random resource names and ordering decisions, use the seed logged by a previous run to replay it",
+		},
+		cli.StringFlag{
+			Name:  "name-generator",
+			Usage: "generator of resource name suffixes: random, ulid, incremental (pvc-0001, pvc-0002, ...) or words (brave-otter)",
+			Value: "random",
+		},
+		cli.StringFlag{
+			Name:  "class-templates",
+			Usage: "path to yaml with storage and snapshot classes copied from existing ones with overrides for the duration of the run, temporary storage classes are tested alongside --sc ones",
+		},
+		cli.StringFlag{
+			Name:  "status-configmap",
+			Usage: "name of ConfigMap in namespace of cert-csi pod status of the run is written to when running in cluster, suites and result of run are always reported as events of the pod",
+		},
+		cli.StringFlag{
+			Name:  "only",
+			Usage: "comma separated numbers of iterations to run out of longevity iterations, ex. --longevity 50 --only 12,37 re-runs only iterations 12 and 37 with their seeds",
+		},
+		cli.StringFlag{
+			Name:  "retry-of",
+			Usage: "name of the run iterations selected with --only are re-run from, its seed is used unless --seed is set and run is recorded as its retry run",
+		},
+		cli.StringFlag{
+			Name:  "progress-address, pa",
+			Usage: "serve live progress as JSON and accept abort requests on this address (ex. :9090 binds to localhost), disabled if empty",
+		},
+		cli.BoolFlag{
+			Name:  "live",
+			Usage: "show rolling statistics of running suites (bound and attached volumes, p95 latencies) updated in place, printed as periodic lines if output isn't a terminal",
+		},
+		cli.StringFlag{
+			Name:  "budget",
+			Usage: "comma separated limits of the run (ex. wall-clock=6h,objects=5000,db-size=2Gi), once any is exceeded no further iterations are launched and run is marked truncated",
+		},
+		cli.StringFlag{
+			Name:  "backend-config",
+			Usage: "path to backend verifier config, lists volumes and snapshots on storage backend before and after run to find leaks",
+		},
+		cli.StringFlag{
+			Name:  "rbac-audit",
+			Usage: "path to save minimal ClusterRole with exactly the API verbs and resources used by the run",
+		},
+		cli.StringFlag{
+			Name:  "class-guard",
+			Value: "warn",
+			Usage: "what to do if storage or snapshot class used by run changes while it's in progress: off, warn or fail",
+		},
+		cli.StringFlag{
+			Name:  "teardown-timeouts",
+			Usage: "comma separated timeouts of namespace teardown tiers deleted in order pods, pvcs, snapshots, namespace (ex. pods=2m,pvcs=5m), unset tiers use namespace timeout",
+		},
+		cli.BoolFlag{
+			Name:  "kernel-log-scan",
+			Usage: "scrape kernel log of nodes running block volumes for SCSI/NVMe IO errors and path failures",
+		},
+		cli.BoolFlag{
+			Name:  "driver-health-probe",
+			Usage: "probe liveness endpoints of driver pods in driver namespace during run and report windows they didn't answer in",
+		},
+		cli.StringFlag{
+			Name:  "baseline-sc",
+			Usage: "storage class without CSI driver, e.g. local-path, the same suites run with it alongside and reports compare latencies with it to tell driver overhead from cluster slowness",
+		},
+		cli.StringFlag{
+			Name:  "driver-profile",
+			Usage: "preset timeout and expected capabilities of a driver, one of " + strings.Join(driverprofile.Names(), ", ") + " or path to profile file, flags override it",
+		},
+		cli.StringFlag{
+			Name:  "background-load",
+			Usage: "keep API server and scheduler busy creating and deleting configmaps and pods without volumes during run, light, moderate, heavy or configmaps=<rate>,pods=<rate>,max-pods=<n>",
+		},
+		cli.BoolFlag{
+			Name:  "mark-baseline",
+			Usage: "mark this run as baseline of its storage classes, later runs are compared with it and regressions are reported",
+		},
+		cli.Float64Flag{
+			Name:  "regression-threshold",
+			Usage: "percent average stage latency may exceed the one of baseline run by before it is reported as regression",
+			Value: 20,
+		},
+		cli.BoolFlag{
+			Name:  "fail-on-regression",
+			Usage: "exit with non-zero code if any stage latency regressed compared to baseline run",
+		},
+		cli.BoolFlag{
+			Name:  "compare",
+			Usage: "compare storage classes of different drivers: the same suite runs with every storage class at once, suite by suite, and comparison report is generated",
+		},
+		cli.BoolFlag{
+			Name:  "fairness",
+			Usage: "run suites of every storage class alone in the first iteration and together afterwards, to report noisy-neighbor effect between them",
+		},
+		cli.DurationFlag{
+			Name:  "run-timeout",
+			Usage: "abort the whole run after that long, in-flight suites are cancelled and cleaned up, 0 disables it",
+		},
+		cli.StringSliceFlag{
+			Name:  "restricted-namespaces",
+			Usage: "pre-created namespaces to run suites in with namespaced permissions only, cluster-scoped resources are reported as not observable",
+		},
+		cli.StringFlag{
+			Name:  "metadata-config",
+			Usage: "path to yaml with annotations and labels added to every PVC and pod created by suites",
+		},
+		cli.BoolFlag{
+			Name:  "force-unlock",
+			Usage: "take over database run lock left by another cert-csi process, use only if that process is no longer alive",
+		},
 	}
+}
+
+// GetTestCommand returns a `test` command with all prepared sub-commands
+func GetTestCommand() cli.Command {
+	globalFlags := getTestGlobalFlags()
 
 	testCmd := cli.Command{
 		Name:     "test",
@@ -132,6 +286,29 @@ func GetTestCommand() cli.Command {
 			getPostgresCommand(globalFlags),
 			getRemoteReplicationProvisioningCommand(globalFlags),
 			getVolumeMigrateCommand(globalFlags),
+			getFSGroupCommand(globalFlags),
+			getDiskPressureEvictionCommand(globalFlags),
+			getWorkloadChurnCommand(globalFlags),
+			getSnapshotConsistencyCommand(globalFlags),
+			getObservabilityCommand(globalFlags),
+			getBindingModeComparisonCommand(globalFlags),
+			getSnapshotScheduleCommand(globalFlags),
+			getAttachPingPongCommand(globalFlags),
+			getRWXSharingCommand(globalFlags),
+			getVeleroBackupCommand(globalFlags),
+			getVolumeStatsCommand(globalFlags),
+			getMountOptionMatrixCommand(globalFlags),
+			getCapacityFullCommand(globalFlags),
+			getQoSClassCommand(globalFlags),
+			getCrossNamespaceRestoreCommand(globalFlags),
+			getIPv6Command(globalFlags),
+			getKubeletRestartCommand(globalFlags),
+			getMountRecoveryCommand(globalFlags),
+			getRWXConcurrentWriteCommand(globalFlags),
+			getControllerFailoverCommand(globalFlags),
+			getReadAfterDetachCommand(globalFlags),
+			getNetworkPartitionCommand(globalFlags),
+			getCanaryCommand(globalFlags),
 			getEphemeralCreationCommand(globalFlags),
 		},
 	}
@@ -145,6 +322,15 @@ func createSuiteRunner(c *cli.Context, s []suites.Interface) (*runner.SuiteRunne
 	if err != nil {
 		log.Fatal("Timeout is wrong formatted")
 	}
+	var profile *driverprofile.Profile
+	if c.String("driver-profile") != "" {
+		if profile, err = driverprofile.Get(c.String("driver-profile")); err != nil {
+			log.Fatal(err)
+		}
+		if !c.IsSet("timeout") {
+			timeout = profile.Timeout.Duration
+		}
+	}
 	timeOutInSeconds := int(timeout.Seconds())
 
 	// Parse cooldown time
@@ -154,9 +340,64 @@ func createSuiteRunner(c *cli.Context, s []suites.Interface) (*runner.SuiteRunne
 	}
 	cooldownInSeconds := int(cooldown.Seconds())
 
+	var verifier backend.Verifier
+	if c.String("backend-config") != "" {
+		verifier, err = backend.LoadConfig(c.String("backend-config"))
+		if err != nil {
+			log.Fatalf("Can't create backend verifier; error=%v", err)
+		}
+	}
+
+	var extraMetadata *k8sclient.ExtraMetadata
+	if c.String("metadata-config") != "" {
+		extraMetadata, err = k8sclient.LoadExtraMetadata(c.String("metadata-config"))
+		if err != nil {
+			log.Fatalf("Can't load extra metadata; error=%v", err)
+		}
+	}
+
+	classGuard, err := runner.ParseClassGuardMode(c.String("class-guard"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	teardownTimeouts, err := k8sclient.ParseTierTimeouts(c.String("teardown-timeouts"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	budget, err := runner.ParseBudget(c.String("budget"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var backgroundLoad *load.Profile
+	if c.String("background-load") != "" {
+		if backgroundLoad, err = load.ParseProfile(c.String("background-load")); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	var scDBs []*store.StorageClassDB
 	ss := make(map[string][]suites.Interface)
-	for _, sc := range c.StringSlice("sc") {
+	storageClasses := c.StringSlice("sc")
+	var templates *k8sclient.ClassTemplates
+	if c.String("class-templates") != "" {
+		if templates, err = k8sclient.LoadClassTemplates(c.String("class-templates")); err != nil {
+			log.Fatal(err)
+		}
+		for _, t := range templates.StorageClasses {
+			storageClasses = append(storageClasses, t.Name)
+		}
+	}
+	if c.Bool("compare") && len(storageClasses) < 2 {
+		log.Fatalf("At least two storage classes are required to compare them")
+	}
+	baselineSC := c.String("baseline-sc")
+	if baselineSC != "" && !slices.Contains(storageClasses, baselineSC) {
+		storageClasses = append(storageClasses, baselineSC)
+	}
+	for _, sc := range storageClasses {
 		pathToDb := fmt.Sprintf("file:%s.db", sc)
 		DB := store.NewSQLiteStore(pathToDb) // dbs should be closed in suite runner
 		scDBs = append(scDBs, &store.StorageClassDB{
@@ -165,7 +406,13 @@ func createSuiteRunner(c *cli.Context, s []suites.Interface) (*runner.SuiteRunne
 		})
 		ss[sc] = s
 	}
-	return runner.NewSuiteRunner(
+	if c.String("rbac-audit") != "" {
+		k8sclient.Audit = k8sclient.NewPermissionAudit()
+	}
+	if budget != nil && budget.Objects > 0 {
+		k8sclient.Created = k8sclient.NewCreateCounter()
+	}
+	sr := runner.NewSuiteRunner(
 		c.String("config"),
 		c.String("namespace"),
 		c.String("start-hook"),
@@ -182,7 +429,157 @@ func createSuiteRunner(c *cli.Context, s []suites.Interface) (*runner.SuiteRunne
 		c.Bool("no-metrics"),
 		c.Bool("no-reports"),
 		scDBs,
-	), ss
+	)
+	sr.Seed = c.Int64("seed")
+	if sr.NameGenerator, err = k8sclient.NewNameGenerator(c.String("name-generator")); err != nil {
+		log.Fatal(err)
+	}
+	if err := sr.SelectIterations(c.String("only")); err != nil {
+		log.Fatalf("Can't select iterations to run; error=%v", err)
+	}
+	if c.String("retry-of") != "" {
+		if len(sr.Only) == 0 {
+			log.Fatalf("Iterations to re-run from %s have to be selected with --only", c.String("retry-of"))
+		}
+		retried, err := findRun(scDBs, c.String("retry-of"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !c.IsSet("seed") {
+			sr.Seed = retried.Seed
+		} else if sr.Seed != retried.Seed {
+			log.Warnf("Seed %d differs from seed %d of run %s, iterations won't be replayed exactly", sr.Seed, retried.Seed, retried.Name)
+		}
+		if !c.IsSet("name-generator") && retried.NameGenerator != "" {
+			if sr.NameGenerator, err = k8sclient.NewNameGenerator(retried.NameGenerator); err != nil {
+				log.Fatal(err)
+			}
+		}
+		sr.RetryOf = retried.Name
+	}
+	if k8sclient.InCluster() {
+		sr.Status = createRunnerStatus(c)
+	}
+	sr.ProgressAddress = c.String("progress-address")
+	sr.Live = c.Bool("live")
+	sr.Budget = budget
+	sr.Backend = verifier
+	sr.ExtraMetadata = extraMetadata
+	sr.RBACAuditPath = c.String("rbac-audit")
+	sr.ClassGuard = classGuard
+	sr.TeardownTimeouts = teardownTimeouts
+	sr.KernelLogScan = c.Bool("kernel-log-scan")
+	sr.DriverHealthProbe = c.Bool("driver-health-probe")
+	sr.Namespaces = c.StringSlice("restricted-namespaces")
+	sr.RunTimeout = c.Duration("run-timeout")
+	sr.Fairness = c.Bool("fairness")
+	sr.Compare = c.Bool("compare")
+	sr.MarkBaseline = c.Bool("mark-baseline")
+	sr.RegressionThreshold = c.Float64("regression-threshold")
+	sr.FailOnRegression = c.Bool("fail-on-regression")
+	sr.BaselineStorageClass = baselineSC
+	sr.BackgroundLoad = backgroundLoad
+	if profile != nil {
+		sr.CapabilityExpectations = profile.Expectations()
+	}
+	if err := store.AcquireRunLocks(scDBs, c.Bool("force-unlock")); err != nil {
+		log.Fatal(err)
+	}
+	// Classes are created after everything above is validated and locked, so nothing exits leaving them in cluster
+	if templates != nil {
+		if sr.TempClasses, err = createTempClasses(c, templates, storageClasses[0]); err != nil {
+			releaseRunLocks(scDBs)
+			log.Fatal(err)
+		}
+	}
+	return sr, ss
+}
+
+// createTempClasses creates classes from templates, storage classes without source are copied from defaultFrom.
+// Classes left by run which didn't get to delete them are labeled, so the next run with the same templates replaces them
+func createTempClasses(c *cli.Context, templates *k8sclient.ClassTemplates, defaultFrom string) (*k8sclient.TempClasses, error) {
+	config, err := k8sclient.GetConfig(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := k8sclient.NewKubeClient(config, 0)
+	if err != nil {
+		return nil, fmt.Errorf("can't create kubernetes client: %w", err)
+	}
+	tempClasses, err := kubeClient.NewTempClasses()
+	if err != nil {
+		return nil, err
+	}
+	if err := tempClasses.Create(context.Background(), templates, defaultFrom); err != nil {
+		if delErr := tempClasses.Delete(context.Background()); delErr != nil {
+			log.Errorf("Can't delete temporary classes; error=%v", delErr)
+		}
+		return nil, fmt.Errorf("can't create classes from templates: %w", err)
+	}
+	return tempClasses, nil
+}
+
+// createRunnerStatus creates status of run reported on cert-csi pod, run isn't stopped if pod can't be found
+func createRunnerStatus(c *cli.Context) *k8sclient.RunnerStatus {
+	config, err := k8sclient.GetConfig(c.String("config"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	kubeClient, err := k8sclient.NewKubeClient(config, 0)
+	if err != nil {
+		log.Fatalf("Can't create kubernetes client; error=%v", err)
+	}
+	status, err := k8sclient.NewRunnerStatus(context.Background(), kubeClient.ClientSet, c.String("status-configmap"))
+	if err != nil {
+		log.Warnf("Status of run won't be reported in cluster; error=%v", err)
+		return nil
+	}
+	log.Infof("Reporting status of run as events of pod %s", status.Pod())
+	return status
+}
+
+// createSweepClasses creates snapshot classes of sweep along with classes of templates, so they are deleted together
+func createSweepClasses(c *cli.Context, sr *runner.SuiteRunner, sweep []k8sclient.SnapshotClassTemplate) {
+	templates := &k8sclient.ClassTemplates{SnapshotClasses: sweep}
+	if sr.TempClasses == nil {
+		tempClasses, err := createTempClasses(c, templates, "")
+		if err != nil {
+			releaseRunLocks(sr.ScDBs)
+			log.Fatal(err)
+		}
+		sr.TempClasses = tempClasses
+		return
+	}
+	if err := sr.TempClasses.Create(context.Background(), templates, ""); err != nil {
+		if delErr := sr.TempClasses.Delete(context.Background()); delErr != nil {
+			log.Errorf("Can't delete temporary classes; error=%v", delErr)
+		}
+		releaseRunLocks(sr.ScDBs)
+		log.Fatalf("Can't create snapshot classes of sweep; error=%v", err)
+	}
+}
+
+// releaseRunLocks releases run locks taken by createSuiteRunner when run exits before it is started
+func releaseRunLocks(scDBs []*store.StorageClassDB) {
+	for _, scDB := range scDBs {
+		if err := scDB.DB.ReleaseRunLock(); err != nil {
+			log.Errorf("Can't release run lock; error=%v", err)
+		}
+	}
+}
+
+// findRun returns run with the name from databases of storage classes
+func findRun(scDBs []*store.StorageClassDB, name string) (*store.TestRun, error) {
+	for _, scDB := range scDBs {
+		runs, err := scDB.DB.GetTestRuns(store.Conditions{"name": name}, "", 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) != 0 {
+			return &runs[0], nil
+		}
+	}
+	return nil, fmt.Errorf("can't find run %s in databases of storage classes", name)
 }
 
 func updatePath(c *cli.Context) error {
@@ -190,6 +587,30 @@ func updatePath(c *cli.Context) error {
 		plotter.UserPath = c.String("path")
 		plotter.FolderPath = ""
 	}
+	layout, err := plotter.ParseLayout(c.String("report-layout"))
+	if err != nil {
+		return err
+	}
+	plotter.ReportLayout = layout
+	format, err := plotter.ParseFormat(c.String("plot-format"))
+	if err != nil {
+		return err
+	}
+	plotter.PlotFormat = format
+
+	slos, err := collector.ParseSLOs(c.String("slo"))
+	if err != nil {
+		return err
+	}
+	reporter.SLOs = slos
+	if c.Duration("slo-window") > 0 {
+		reporter.SLOWindow = c.Duration("slo-window")
+	}
+	if c.IsSet("max-plot-points") {
+		plotter.MaxPlotPoints = c.Int("max-plot-points")
+		collector.MaxSeriesPoints = c.Int("max-plot-points")
+	}
+	reporter.MaxDetailedEntities = c.Int("max-detailed-entities")
 	return nil
 }
 
@@ -247,6 +668,14 @@ func getVolumeCreationCommand(globalFlags []cli.Flag) cli.Command {
 					Name:  "access-mode, am",
 					Usage: "volume access mode",
 				},
+				cli.Float64Flag{
+					Name:  "rate",
+					Usage: "number of volumes to create per second, all at once if not set",
+				},
+				cli.StringFlag{
+					Name:  "ramp",
+					Usage: "comma separated volume numbers to create at once in consecutive stages, e.g. 10,50,100,250",
+				},
 			},
 			globalFlags...,
 		),
@@ -255,12 +684,18 @@ func getVolumeCreationCommand(globalFlags []cli.Flag) cli.Command {
 			volNum := c.Int("number")
 			volSize := c.String("size")
 			accessMode := c.String("access-mode")
+			ramp, err := suites.ParseRamp(c.String("ramp"))
+			if err != nil {
+				return err
+			}
 
 			s := []suites.Interface{
 				&suites.VolumeCreationSuite{
 					VolumeNumber: volNum,
 					VolumeSize:   volSize,
 					AccessMode:   accessMode,
+					Rate:         c.Float64("rate"),
+					Ramp:         ramp,
 				},
 			}
 
@@ -650,6 +1085,14 @@ func getProvisioningCommand(globalFlags []cli.Flag) cli.Command {
 					Name:  "podNumber, podNum, pn, p",
 					Usage: "number of pod to create",
 				},
+				cli.Float64Flag{
+					Name:  "rate",
+					Usage: "number of volumes to create per second, all at once if not set",
+				},
+				cli.BoolFlag{
+					Name:  "validate-mounts",
+					Usage: "check mount options and propagation of volumes on nodes using privileged diagnostic pod",
+				},
 			},
 			globalFlags...,
 		),
@@ -663,9 +1106,11 @@ func getProvisioningCommand(globalFlags []cli.Flag) cli.Command {
 			}
 			s := []suites.Interface{
 				&suites.ProvisioningSuite{
-					VolumeNumber: volNum,
-					PodNumber:    podNum,
-					Image:        testImage,
+					VolumeNumber:   volNum,
+					PodNumber:      podNum,
+					Image:          testImage,
+					Rate:           c.Float64("rate"),
+					ValidateMounts: c.Bool("validate-mounts"),
 				},
 			}
 
@@ -703,6 +1148,14 @@ func getScalingCommand(globalFlags []cli.Flag) cli.Command {
 					Usage: "change pod policy of the statefulset",
 					Value: "Parallel",
 				},
+				cli.Float64Flag{
+					Name:  "rate",
+					Usage: "number of volumes to create per second, all at once if not set",
+				},
+				cli.StringFlag{
+					Name:  "ramp",
+					Usage: "comma separated replica numbers to scale to in consecutive stages, e.g. 10,50,100",
+				},
 			},
 			globalFlags...,
 		),
@@ -716,6 +1169,10 @@ func getScalingCommand(globalFlags []cli.Flag) cli.Command {
 			if err != nil {
 				return fmt.Errorf("failed to get test image: %s", err)
 			}
+			ramp, err := suites.ParseRamp(c.String("ramp"))
+			if err != nil {
+				return err
+			}
 			s := []suites.Interface{
 				&suites.ScalingSuite{
 					ReplicaNumber:    repNum,
@@ -723,6 +1180,8 @@ func getScalingCommand(globalFlags []cli.Flag) cli.Command {
 					GradualScaleDown: gradual,
 					PodPolicy:        podPolicy,
 					Image:            testImage,
+					Rate:             c.Float64("rate"),
+					Ramp:             ramp,
 				},
 			}
 
@@ -777,45 +1236,97 @@ func getVolumeIoCommand(globalFlags []cli.Flag) cli.Command {
 	}
 }
 
-func getSnapCommand(globalFlags []cli.Flag) cli.Command {
+func getFSGroupCommand(globalFlags []cli.Flag) cli.Command {
 	return cli.Command{
-		Name:      "snapshot",
-		ShortName: "snap",
-		Usage:     "test full snapshot pipeline",
+		Name:      "fsgroup",
+		ShortName: "fsg",
+		Usage:     "test fsGroup and SecurityContext handling",
 		Category:  "test",
 		Flags: append(
 			[]cli.Flag{
 				cli.StringFlag{
-					Name:  "volumeSnapshotClass, vsc",
-					Usage: "define your volumeSnapshotClass",
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.Int64Flag{
+					Name:  "fs-group, fsg",
+					Usage: "fsGroup to set in pod SecurityContext",
+					Value: 2000,
+				},
+				cli.Int64Flag{
+					Name:  "run-as-user, user",
+					Usage: "user to run non-root containers with",
+					Value: 1000,
 				},
 				cli.IntFlag{
-					Name:  "snapshotAmount, sa",
-					Usage: "define the amount of snapshots to create",
-					Value: 3,
+					Name:  "files, f",
+					Usage: "number of files to pre-populate volume with, used to measure recursive ownership change",
+					Value: 1000,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.FSGroupSuite{
+					VolumeSize: c.String("size"),
+					FSGroup:    c.Int64("fs-group"),
+					RunAsUser:  c.Int64("run-as-user"),
+					FileNumber: c.Int("files"),
+					Image:      testImage,
 				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getDiskPressureEvictionCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "disk-pressure-eviction",
+		ShortName: "dpe",
+		Usage:     "test eviction of pods with volumes under ephemeral storage pressure",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
 				cli.StringFlag{
 					Name:  "size, s",
 					Usage: "volume size to be created",
 				},
+				cli.IntFlag{
+					Name:  "podNumber, pods",
+					Usage: "number of pods to evict",
+					Value: 1,
+				},
+				cli.StringFlag{
+					Name:  "ephemeral-storage-limit, esl",
+					Usage: "ephemeral storage limit of pod container, exceeding it triggers eviction",
+					Value: "64Mi",
+				},
 			},
 			globalFlags...,
 		),
 		Before: updatePath,
 		Action: func(c *cli.Context) error {
-			snapClass := c.String("volumeSnapshotClass")
-			snapshotAmount := c.Int("snapshotAmount")
-			size := c.String("size")
 			testImage, err := getTestImage(c.String("image-config"))
 			if err != nil {
 				return fmt.Errorf("failed to get test image: %s", err)
 			}
 			s := []suites.Interface{
-				&suites.SnapSuite{
-					SnapClass:  snapClass,
-					SnapAmount: snapshotAmount,
-					VolumeSize: size,
-					Image:      testImage,
+				&suites.DiskPressureEvictionSuite{
+					PodNumber:             c.Int("podNumber"),
+					VolumeSize:            c.String("size"),
+					EphemeralStorageLimit: c.String("ephemeral-storage-limit"),
+					Image:                 testImage,
 				},
 			}
 
@@ -827,6 +1338,1162 @@ func getSnapCommand(globalFlags []cli.Flag) cli.Command {
 	}
 }
 
+func getWorkloadChurnCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "workload-churn",
+		ShortName: "churn",
+		Usage:     "keep steady state of volumes with pods while continuously recreating part of them",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "volumeNumber, n",
+					Usage: "number of volumes with pods to keep",
+					Value: 10,
+				},
+				cli.IntFlag{
+					Name:  "churn-percent, cp",
+					Usage: "percent of volumes to recreate every minute",
+					Value: 10,
+				},
+				cli.StringFlag{
+					Name:  "duration, d",
+					Usage: "how long to churn volumes (ex. 1h30m)",
+					Value: "10m",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.WorkloadChurnSuite{
+					VolumeNumber: c.Int("volumeNumber"),
+					ChurnPercent: c.Int("churn-percent"),
+					Duration:     c.String("duration"),
+					VolumeSize:   c.String("size"),
+					Image:        testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getSnapshotConsistencyCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "snapshot-consistency",
+		ShortName: "snapcons",
+		Usage:     "test crash-consistency of snapshot taken while pod is writing",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "volumeSnapshotClass, vsc",
+					Usage: "define your volumeSnapshotClass",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.SnapshotConsistencySuite{
+					SnapClass:  c.String("volumeSnapshotClass"),
+					VolumeSize: c.String("size"),
+					Image:      testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getObservabilityCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "observability",
+		ShortName: "obs",
+		Usage:     "validates that CSM Observability exports metrics for created volumes",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "volumeNumber, volNum, vn, v",
+					Usage: "number of volumes to create",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.StringFlag{
+					Name:  "otel-namespace",
+					Usage: "namespace where CSM Observability is installed",
+					Value: "karavi",
+				},
+				cli.StringFlag{
+					Name:  "otel-service",
+					Usage: "name of the otel collector service",
+					Value: "otel-collector",
+				},
+				cli.StringFlag{
+					Name:  "otel-port",
+					Usage: "port of the otel collector service exposing metrics",
+					Value: "8443",
+				},
+				cli.StringFlag{
+					Name:  "otel-scheme",
+					Usage: "scheme used to scrape the otel collector, [http] or [https]",
+					Value: "https",
+				},
+				cli.StringFlag{
+					Name:  "metrics-timeout",
+					Usage: "how long to wait for volumes to show up in metrics, format is time (ex. 10m)",
+					Value: "10m",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.ObservabilitySuite{
+					VolumeNumber: c.Int("volumeNumber"),
+					VolumeSize:   c.String("size"),
+					Namespace:    c.String("otel-namespace"),
+					Service:      c.String("otel-service"),
+					Port:         c.String("otel-port"),
+					Scheme:       c.String("otel-scheme"),
+					Timeout:      c.String("metrics-timeout"),
+					Image:        testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getBindingModeComparisonCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "binding-mode-comparison",
+		ShortName: "bmc",
+		Usage:     "compares provisioning with Immediate and WaitForFirstConsumer binding modes of storage class",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "volumeNumber, volNum, vn, v",
+					Usage: "number of volumes to create with each binding mode",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.BindingModeComparisonSuite{
+					VolumeNumber: c.Int("volumeNumber"),
+					VolumeSize:   c.String("size"),
+					Image:        testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getSnapshotScheduleCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "snapshot-schedule",
+		ShortName: "snap-schedule",
+		Usage:     "takes snapshots of busy volumes on schedule, deleting the ones out of retention",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "volumeSnapshotClass, vsc",
+					Usage: "define your volumeSnapshotClass",
+				},
+				cli.IntFlag{
+					Name:  "volumeNumber, volNum, vn, v",
+					Usage: "number of volumes to snapshot",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.StringFlag{
+					Name:  "interval",
+					Usage: "time between snapshot rounds, format is time (ex. 5m)",
+					Value: "5m",
+				},
+				cli.StringFlag{
+					Name:  "duration",
+					Usage: "how long to keep taking snapshots, format is time (ex. 1h)",
+					Value: "1h",
+				},
+				cli.IntFlag{
+					Name:  "retention",
+					Usage: "number of snapshots kept for every volume, older ones are deleted",
+					Value: 3,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.SnapshotScheduleSuite{
+					VolumeNumber: c.Int("volumeNumber"),
+					VolumeSize:   c.String("size"),
+					SnapClass:    c.String("volumeSnapshotClass"),
+					Interval:     c.String("interval"),
+					Duration:     c.String("duration"),
+					Retention:    c.Int("retention"),
+					Image:        testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getAttachPingPongCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "attach-ping-pong",
+		ShortName: "ping-pong",
+		Usage:     "moves a pod with RWO volume between two nodes back and forth, measuring detach and attach latencies",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "moves",
+					Usage: "number of times volume is moved to another node",
+					Value: 10,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.Float64Flag{
+					Name:  "max-slowdown",
+					Usage: "fail if average latency of the last third of moves exceeds the first third this many times, not checked if 0",
+					Value: 2,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.AttachPingPongSuite{
+					Moves:       c.Int("moves"),
+					VolumeSize:  c.String("size"),
+					Image:       testImage,
+					MaxSlowdown: c.Float64("max-slowdown"),
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getRWXSharingCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "rwx-sharing",
+		ShortName: "rwx",
+		Usage:     "writer deployment and readers behind headless service share RWX volume, measuring content propagation latency",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "readers",
+					Usage: "number of reader pods",
+					Value: 3,
+				},
+				cli.IntFlag{
+					Name:  "writes",
+					Usage: "number of times writer updates content",
+					Value: 10,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.DurationFlag{
+					Name:  "propagation-timeout",
+					Usage: "fail if a reader doesn't see written content within this time",
+					Value: suites.PropagationTimeout,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			suites.PropagationTimeout = c.Duration("propagation-timeout")
+			s := []suites.Interface{
+				&suites.RWXSharingSuite{
+					Readers:    c.Int("readers"),
+					Writes:     c.Int("writes"),
+					VolumeSize: c.String("size"),
+					Image:      testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getVeleroBackupCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "velero-backup",
+		Usage:    "backs up volume with Velero using CSI snapshots, restores it to another namespace and validates restored data",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "velero-namespace",
+					Usage: "namespace Velero is installed to",
+					Value: velero.DefaultNamespace,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.VeleroBackupSuite{
+					VeleroNamespace: c.String("velero-namespace"),
+					VolumeSize:      c.String("size"),
+					Image:           testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getVolumeStatsCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "volume-stats",
+		Usage:    "writes data to volumes and validates usage stats kubelet reports for them",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "volumeNumber, volNum, vn, v",
+					Usage: "number of volumes to create",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.IntFlag{
+					Name:  "write-size",
+					Usage: "amount of data in MiB written to every volume",
+					Value: 256,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.VolumeStatsSuite{
+					VolumeNumber: c.Int("volumeNumber"),
+					VolumeSize:   c.String("size"),
+					WriteSize:    c.Int("write-size"),
+					Image:        testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getMountOptionMatrixCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "mount-options",
+		Usage:    "clones storage class with sets of mount options and validates that options are applied on node, or that mount fails cleanly",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "option-sets",
+					Usage: "path to yaml config with mount option sets, see example-mount-options-config.yaml",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.DurationFlag{
+					Name:  "mount-timeout",
+					Usage: "consider mount of option set failed if pod doesn't start within this time",
+					Value: suites.MountOptionTimeout,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			var optionSets []suites.MountOptionSet
+			if c.String("option-sets") != "" {
+				optionSets, err = suites.LoadMountOptionSets(c.String("option-sets"))
+				if err != nil {
+					return err
+				}
+			}
+			s := []suites.Interface{
+				&suites.MountOptionMatrixSuite{
+					OptionSets:   optionSets,
+					VolumeSize:   c.String("size"),
+					Image:        testImage,
+					MountTimeout: c.Duration("mount-timeout"),
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getCapacityFullCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "capacity-full",
+		Usage:    "provisions volumes until backend pool is exhausted and validates how driver rejects provisioning, health of bound volumes and cleanup",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "max-volumes",
+					Usage: "maximum number of volumes to provision while trying to exhaust the pool",
+					Value: 20,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created, pick it so the pool is exhausted within max-volumes",
+				},
+				cli.DurationFlag{
+					Name:  "bind-timeout",
+					Usage: "consider pool exhausted if volume isn't bound within this time",
+					Value: suites.CapacityFillTimeout,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.CapacityFullSuite{
+					MaxVolumes:  c.Int("max-volumes"),
+					VolumeSize:  c.String("size"),
+					Image:       testImage,
+					BindTimeout: c.Duration("bind-timeout"),
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getQoSClassCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "qos-class",
+		Usage:    "mounts and writes to volumes from pods of every QoS class on the same node and compares latencies with Guaranteed pods",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "iterations, i",
+					Usage: "number of volumes mounted from pods of every QoS class",
+					Value: 3,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.IntFlag{
+					Name:  "write-size",
+					Usage: "amount of data in MiB every pod writes to its volume",
+					Value: 64,
+				},
+				cli.IntFlag{
+					Name:  "pressure-workers",
+					Usage: "number of BestEffort pods burning CPU on the node while latencies are measured",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.QoSClassSuite{
+					Iterations:      c.Int("iterations"),
+					VolumeSize:      c.String("size"),
+					WriteSize:       c.Int("write-size"),
+					PressureWorkers: c.Int("pressure-workers"),
+					Image:           testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getCrossNamespaceRestoreCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "cross-namespace-restore",
+		Usage:    "snapshots volume and restores it in another namespace through ReferenceGrant, validates data and compares latency with same-namespace restore",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:     "volumeSnapshotClass, vsc",
+					Usage:    "volumeSnapshotClass to be used",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.CrossNamespaceRestoreSuite{
+					SnapClass:  c.String("volumeSnapshotClass"),
+					VolumeSize: c.String("size"),
+					Image:      testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getIPv6Command(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "ipv6",
+		Usage:    "checks that node plugins reach driver controller over IPv6 and volumes are provisioned and mounted on IPv6 or dual-stack cluster",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			if c.String("driver-namespace") == "" {
+				return fmt.Errorf("driver-namespace is required to find node plugin and controller pods")
+			}
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.IPv6Suite{
+					DriverNamespace: c.String("driver-namespace"),
+					VolumeSize:      c.String("size"),
+					Image:           testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getKubeletRestartCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "kubelet-restart",
+		Usage:    "restarts kubelet of a node with mounted volumes, validates volumes stay mounted and pods healthy, and that remounts after pod restart are idempotent",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "podNumber, podNum, pn, p",
+					Usage: "number of pods with volumes on the restarted node",
+					Value: 2,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.StringFlag{
+					Name:  "restart-hook",
+					Usage: "path to script restarting kubelet of node passed in " + suites.KubeletRestartNodeEnv + " environment variable, privileged pod restarts it with systemctl if not set",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.KubeletRestartSuite{
+					PodNumber:   c.Int("podNumber"),
+					VolumeSize:  c.String("size"),
+					RestartHook: c.String("restart-hook"),
+					Image:       testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getMountRecoveryCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "mount-recovery",
+		Usage:    "starts pods while fault hook keeps node from reaching backend, validates that attach and mount retries succeed once fault is cleared and measures recovery time and retries",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "podNumber, podNum, pn, p",
+					Usage: "number of pods started during fault",
+					Value: 2,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.StringFlag{
+					Name:     "fault-hook",
+					Usage:    "path to script injecting fault (ex. blocking backend portal) on node passed in " + suites.KubeletRestartNodeEnv + " when " + suites.MountFaultEnv + " is " + suites.MountFaultInject + " and clearing it when it's " + suites.MountFaultRecover,
+					Required: true,
+				},
+				cli.DurationFlag{
+					Name:  "fault-duration",
+					Usage: "how long fault is kept while pods are trying to mount their volumes",
+					Value: time.Minute,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.MountRecoverySuite{
+					PodNumber:     c.Int("podNumber"),
+					VolumeSize:    c.String("size"),
+					FaultHook:     c.String("fault-hook"),
+					FaultDuration: c.Duration("fault-duration"),
+					Image:         testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getRWXConcurrentWriteCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "rwx-concurrent-write",
+		Usage:    "writers spread over nodes append records to their own files of RWX volume at once, validator verifies no write was lost and sizes are correct",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "writers, w",
+					Usage: "number of writers, each runs on a different node",
+					Value: 2,
+				},
+				cli.IntFlag{
+					Name:  "appends, a",
+					Usage: "number of records every writer appends",
+					Value: 500,
+				},
+				cli.BoolFlag{
+					Name:  "shared-file",
+					Usage: "also let all writers append to one shared file and record observed semantics without failing",
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.RWXConcurrentWriteSuite{
+					Writers:    c.Int("writers"),
+					Appends:    c.Int("appends"),
+					SharedFile: c.Bool("shared-file"),
+					VolumeSize: c.String("size"),
+					Image:      testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getControllerFailoverCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "controller-failover",
+		Usage:    "deletes leader pod of driver controller or scales its deployment down and up during provisioning, verifies leader election hands over and provisioning resumes",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "deployment",
+					Usage: "name of controller deployment in driver namespace, deployment running csi-provisioner is used if not set",
+				},
+				cli.StringFlag{
+					Name:  "disruption",
+					Usage: "how controller is disrupted, " + suites.ControllerDeleteLeader + " deletes pod holding provisioner lease and " + suites.ControllerScale + " scales deployment to zero and back",
+					Value: suites.ControllerDeleteLeader,
+				},
+				cli.IntFlag{
+					Name:  "volumeNumber, volNum, vn, v",
+					Usage: "number of volumes created one by one, controller is disrupted after half of them",
+					Value: 10,
+				},
+				cli.DurationFlag{
+					Name:  "create-interval",
+					Usage: "interval between creation of volumes",
+					Value: 2 * time.Second,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			if c.String("driver-namespace") == "" {
+				return fmt.Errorf("driver-namespace is required to find controller deployment and its leases")
+			}
+			s := []suites.Interface{
+				&suites.ControllerFailoverSuite{
+					DriverNamespace: c.String("driver-namespace"),
+					Deployment:      c.String("deployment"),
+					Disruption:      c.String("disruption"),
+					VolumeNumber:    c.Int("volumeNumber"),
+					CreateInterval:  c.Duration("create-interval"),
+					VolumeSize:      c.String("size"),
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getReadAfterDetachCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "read-after-detach",
+		Usage:    "detaches written volumes from a node, probes it for stale mounts and readable devices and verifies data after reattach on another node",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "volumeNumber, volNum, vn, v",
+					Usage: "number of volumes detached and probed",
+					Value: 1,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.BoolFlag{
+					Name:  "block, raw-block",
+					Usage: "use raw block volumes",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.ReadAfterDetachSuite{
+					VolumeNumber: c.Int("volumeNumber"),
+					VolumeSize:   c.String("size"),
+					Image:        testImage,
+					RawBlock:     c.Bool("block"),
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getNetworkPartitionCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "network-partition",
+		Usage:    "writers keep doing IO on a node while fault hook partitions it from storage network at steps of fault plan, measures IO errors during faults and recovery time after them",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.IntFlag{
+					Name:  "podNumber, podNum, pn, p",
+					Usage: "number of writer pods on partitioned node",
+					Value: 1,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.StringFlag{
+					Name:     "fault-hook",
+					Usage:    "path to script injecting fault (ex. blocking storage VLAN) on node passed in " + suites.KubeletRestartNodeEnv + " when " + suites.MountFaultEnv + " is " + suites.MountFaultInject + " and clearing it when it's " + suites.MountFaultRecover,
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "fault-plan",
+					Usage: "comma separated at:duration steps, fault is injected at offset from start of IO and cleared after duration, ex. 30s:60s,5m:2m",
+					Value: "30s:60s",
+				},
+				cli.DurationFlag{
+					Name:  "settle",
+					Usage: "how long writers keep running after the last fault is cleared",
+					Value: time.Minute,
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			plan, err := suites.ParseFaultPlan(c.String("fault-plan"))
+			if err != nil {
+				return err
+			}
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.NetworkPartitionSuite{
+					PodNumber:  c.Int("podNumber"),
+					VolumeSize: c.String("size"),
+					FaultHook:  c.String("fault-hook"),
+					Plan:       plan,
+					Settle:     c.Duration("settle"),
+					Image:      testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getCanaryCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:     "canary",
+		Usage:    "provisions tiny volume and pod every interval until interrupted, appends latencies to rolling run and alerts when they exceed baseline",
+		Category: "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.DurationFlag{
+					Name:  "interval",
+					Usage: "time between canaries",
+					Value: 5 * time.Minute,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size of canary",
+					Value: "1Gi",
+				},
+				cli.IntFlag{
+					Name:  "baseline",
+					Usage: "number of latest successful canaries latency is compared with",
+					Value: 10,
+				},
+				cli.Float64Flag{
+					Name:  "alert-factor",
+					Usage: "alert when canary latency exceeds baseline that many times",
+					Value: 2,
+				},
+				cli.StringFlag{
+					Name:  "alert-hook",
+					Usage: "path to hook run on every alert, the alert is passed in " + runner.CanaryAlertEnv + " environment variable",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			if c.Int("baseline") < 1 || c.Float64("alert-factor") <= 1 {
+				return fmt.Errorf("baseline must be positive and alert factor greater than 1")
+			}
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			s := []suites.Interface{
+				&suites.ProvisioningSuite{
+					VolumeNumber: 1,
+					PodNumber:    1,
+					VolumeSize:   c.String("size"),
+					Image:        testImage,
+				},
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			sr.Canary = &runner.Canary{
+				Interval:  c.Duration("interval"),
+				Baseline:  c.Int("baseline"),
+				Factor:    c.Float64("alert-factor"),
+				AlertHook: c.String("alert-hook"),
+			}
+			// Canaries run until interrupted unless longevity limits them
+			if !c.IsSet("longevity") {
+				sr.IterationNum = 0
+			}
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
+func getSnapCommand(globalFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Name:      "snapshot",
+		ShortName: "snap",
+		Usage:     "test full snapshot pipeline",
+		Category:  "test",
+		Flags: append(
+			[]cli.Flag{
+				cli.StringFlag{
+					Name:  "volumeSnapshotClass, vsc",
+					Usage: "define your volumeSnapshotClass",
+				},
+				cli.IntFlag{
+					Name:  "snapshotAmount, sa",
+					Usage: "define the amount of snapshots to create",
+					Value: 3,
+				},
+				cli.StringFlag{
+					Name:  "size, s",
+					Usage: "volume size to be created",
+				},
+				cli.StringSliceFlag{
+					Name:  "sweep",
+					Usage: "snapshot class parameter and values to sweep, ex. --sweep retention=1h,24h; every combination of swept values gets temporary copy of --vsc and its own snapshot suite, compared with --vsc in report",
+				},
+			},
+			globalFlags...,
+		),
+		Before: updatePath,
+		Action: func(c *cli.Context) error {
+			snapClass := c.String("volumeSnapshotClass")
+			snapshotAmount := c.Int("snapshotAmount")
+			size := c.String("size")
+			testImage, err := getTestImage(c.String("image-config"))
+			if err != nil {
+				return fmt.Errorf("failed to get test image: %s", err)
+			}
+			var sweep []k8sclient.SnapshotClassTemplate
+			if len(c.StringSlice("sweep")) != 0 {
+				if snapClass == "" {
+					return fmt.Errorf("--sweep requires --vsc, snapshot classes of sweep are copied from it")
+				}
+				if sweep, err = k8sclient.SweepSnapshotClass(snapClass, c.StringSlice("sweep")); err != nil {
+					return err
+				}
+			}
+			base := &suites.SnapSuite{
+				SnapClass:  snapClass,
+				SnapAmount: snapshotAmount,
+				VolumeSize: size,
+				Image:      testImage,
+			}
+			s := []suites.Interface{base}
+			if sweep != nil {
+				base.Variant = "base"
+				for _, t := range sweep {
+					s = append(s, &suites.SnapSuite{
+						SnapClass:  t.Name,
+						SnapAmount: snapshotAmount,
+						VolumeSize: size,
+						Image:      testImage,
+						Variant:    t.Overrides(),
+					})
+				}
+			}
+
+			sr, ss := createSuiteRunner(c, s)
+			if sweep != nil {
+				createSweepClasses(c, sr, sweep)
+			}
+			sr.RunSuites(ss)
+
+			return nil
+		},
+	}
+}
+
 func getVolumeGroupSnapCommand(globalFlags []cli.Flag) cli.Command {
 	return cli.Command{
 		Name:      "volume-group-snapshot",
@@ -0,0 +1,171 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Assertions contains thresholds that metrics of a test case should satisfy, zero values are not checked
+type Assertions struct {
+	// MaxAvgBindTime is the maximum average time of PVC binding
+	MaxAvgBindTime time.Duration
+	// MinThroughput is the minimum number of PVCs bound per minute of test case
+	MinThroughput float64
+	// ZeroFailedAttaches requires every started attachment to be finished
+	ZeroFailedAttaches bool
+	// MaxControllerMemory is the maximum memory in MiB used by any driver controller pod
+	MaxControllerMemory int64
+	// MaxNodeCPU is the maximum CPU in millicores used by any driver node plugin pod
+	MaxNodeCPU int64
+}
+
+// IsEmpty checks whether there is nothing to assert
+func (a *Assertions) IsEmpty() bool {
+	return a == nil || (a.MaxAvgBindTime == 0 && a.MinThroughput == 0 && !a.ZeroFailedAttaches &&
+		a.MaxControllerMemory == 0 && a.MaxNodeCPU == 0)
+}
+
+// Evaluate checks test case metrics against assertions, elapsed is the duration of the test case
+func (a *Assertions) Evaluate(tcMetrics *TestCaseMetrics, elapsed time.Duration) []*store.AssertionResult {
+	var results []*store.AssertionResult
+	if a.IsEmpty() {
+		return results
+	}
+
+	if a.MaxAvgBindTime != 0 {
+		avg := tcMetrics.StageMetrics[PVCBind].Avg
+		results = append(results, &store.AssertionResult{
+			TcID:     tcMetrics.TestCase.ID,
+			Name:     "MaxAvgBindTime",
+			Expected: "<= " + a.MaxAvgBindTime.String(),
+			Actual:   avg.String(),
+			Passed:   avg <= a.MaxAvgBindTime,
+		})
+	}
+
+	if a.MinThroughput != 0 {
+		var throughput float64
+		if elapsed > 0 {
+			throughput = float64(boundPVCs(tcMetrics.PVCs)) / elapsed.Minutes()
+		}
+		results = append(results, &store.AssertionResult{
+			TcID:     tcMetrics.TestCase.ID,
+			Name:     "MinThroughput",
+			Expected: fmt.Sprintf(">= %.2f PVC/min", a.MinThroughput),
+			Actual:   fmt.Sprintf("%.2f PVC/min", throughput),
+			Passed:   throughput >= a.MinThroughput,
+		})
+	}
+
+	if a.ZeroFailedAttaches {
+		failed := failedAttaches(tcMetrics.PVCs)
+		results = append(results, &store.AssertionResult{
+			TcID:     tcMetrics.TestCase.ID,
+			Name:     "ZeroFailedAttaches",
+			Expected: "0",
+			Actual:   fmt.Sprint(failed),
+			Passed:   failed == 0,
+		})
+	}
+
+	if a.MaxControllerMemory != 0 {
+		pod, peak := peakPodUsage(tcMetrics.ResourceUsageMetrics, "controller", func(r store.ResourceUsage) int64 { return r.Mem })
+		results = append(results, &store.AssertionResult{
+			TcID:     tcMetrics.TestCase.ID,
+			Name:     "MaxControllerMemory",
+			Expected: fmt.Sprintf("<= %dMiB", a.MaxControllerMemory),
+			Actual:   usageActual(pod, peak, "MiB"),
+			Passed:   peak <= a.MaxControllerMemory,
+		})
+	}
+
+	if a.MaxNodeCPU != 0 {
+		pod, peak := peakPodUsage(tcMetrics.ResourceUsageMetrics, "node", func(r store.ResourceUsage) int64 { return r.CPU })
+		results = append(results, &store.AssertionResult{
+			TcID:     tcMetrics.TestCase.ID,
+			Name:     "MaxNodeCPU",
+			Expected: fmt.Sprintf("<= %dm", a.MaxNodeCPU),
+			Actual:   usageActual(pod, peak, "m"),
+			Passed:   peak <= a.MaxNodeCPU,
+		})
+	}
+
+	return results
+}
+
+// peakPodUsage finds the highest usage of a single driver pod whose name contains role, summed over its containers.
+// Containers of a pod are saved together on every poll, so a repeated container starts the next sample of that pod.
+func peakPodUsage(usage []store.ResourceUsage, role string, value func(store.ResourceUsage) int64) (string, int64) {
+	type sample struct {
+		containers map[string]bool
+		total      int64
+	}
+	current := make(map[string]*sample)
+	var peakPod string
+	var peak int64
+	for _, r := range usage {
+		if !strings.Contains(r.PodName, role) {
+			continue
+		}
+		s, ok := current[r.PodName]
+		if !ok || s.containers[r.ContainerName] {
+			s = &sample{containers: make(map[string]bool)}
+			current[r.PodName] = s
+		}
+		s.containers[r.ContainerName] = true
+		s.total += value(r)
+		if s.total > peak {
+			peak = s.total
+			peakPod = r.PodName
+		}
+	}
+	return peakPod, peak
+}
+
+func usageActual(pod string, peak int64, unit string) string {
+	if pod == "" {
+		return "no usage collected"
+	}
+	return fmt.Sprintf("%d%s (%s)", peak, unit, pod)
+}
+
+func boundPVCs(pvcs []PVCMetrics) int {
+	bound := 0
+	for _, pvc := range pvcs {
+		if pvc.Metrics[PVCBind] > 0 {
+			bound++
+		}
+	}
+	return bound
+}
+
+// failedAttaches counts PVCs whose attachment started but never ended,
+// such PVCs have negative attachment duration as end timestamp is zero
+func failedAttaches(pvcs []PVCMetrics) int {
+	failed := 0
+	for _, pvc := range pvcs {
+		if pvc.Metrics[PVCAttachment] < 0 {
+			failed++
+		}
+	}
+	return failed
+}
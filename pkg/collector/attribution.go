@@ -0,0 +1,152 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package collector
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Control-plane components latency is attributed to
+const (
+	ComponentProvisioner  = "external-provisioner"
+	ComponentAttacher     = "external-attacher"
+	ComponentResizer      = "external-resizer"
+	ComponentKubelet      = "kubelet"
+	ComponentScheduler    = "scheduler"
+	ComponentPVController = "persistentvolume-controller"
+	// ComponentSchedulingChurn is preemption, eviction or descheduling of pod, not caused by the driver
+	ComponentSchedulingChurn = "scheduling-churn"
+)
+
+// ComponentLatency is part of stage latency attributed to a component
+type ComponentLatency struct {
+	Stage     string
+	Component string
+	// Total is time spent waiting for events of the component, summed over all entities of the stage
+	Total time.Duration
+	// Events is number of decisive events the component emitted
+	Events int
+	// Share is percent of total stage latency attributed to the component
+	Share float64
+}
+
+// Component maps source and reason of Kubernetes event to control-plane component. Sidecars report with driver
+// and pod name as source, so they are recognized by reason, and attachdetach-controller reports attaches
+// external-attacher has done
+func Component(source, reason string) string {
+	lower := strings.ToLower(source)
+	switch {
+	case strings.HasPrefix(reason, "Provisioning"), strings.Contains(lower, "provisioner"):
+		return ComponentProvisioner
+	case strings.Contains(lower, "attacher"), lower == "attachdetach-controller":
+		return ComponentAttacher
+	case reason == "Resizing", strings.HasPrefix(reason, "VolumeResize"), strings.Contains(lower, "resizer"):
+		return ComponentResizer
+	case lower == "kubelet":
+		return ComponentKubelet
+	case reason == "Preempted", reason == "Descheduled", reason == "Evicted", reason == "TaintManagerEviction":
+		return ComponentSchedulingChurn
+	case strings.Contains(lower, "scheduler"):
+		return ComponentScheduler
+	case lower == ComponentPVController:
+		return ComponentPVController
+	case source == "":
+		return "unknown"
+	}
+	return source
+}
+
+// attribute splits time between start and end events of the stage: every gap is attributed to the component,
+// which event ended it, and the gap after the last event goes to the fallback component finishing the stage
+func attribute(stage string, events []store.Event, start, end store.EventTypeEnum, fallback string, totals map[[2]string]*ComponentLatency) {
+	sorted := make([]store.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var last, finish time.Time
+	for _, e := range sorted {
+		if e.Type == start && last.IsZero() {
+			last = e.Timestamp
+		}
+		if e.Type == end && finish.IsZero() {
+			finish = e.Timestamp
+		}
+	}
+	if last.IsZero() || finish.Before(last) {
+		return
+	}
+
+	add := func(component string, d time.Duration) {
+		key := [2]string{stage, component}
+		if totals[key] == nil {
+			totals[key] = &ComponentLatency{Stage: stage, Component: component}
+		}
+		totals[key].Total += d
+		totals[key].Events++
+	}
+	for _, e := range sorted {
+		if e.Type != store.ComponentEvent || e.Timestamp.Before(last) || e.Timestamp.After(finish) {
+			continue
+		}
+		add(Component(e.Source, e.Message), e.Timestamp.Sub(last))
+		last = e.Timestamp
+	}
+	add(fallback, finish.Sub(last))
+}
+
+// getComponentLatencies attributes PVC binding and pod creation latencies to control-plane components,
+// stages are sorted by name and components by attributed time, longest first
+func getComponentLatencies(pvcs []PVCMetrics, pods []PodMetrics) []ComponentLatency {
+	totals := make(map[[2]string]*ComponentLatency)
+	for _, p := range pvcs {
+		start := store.PvcAdded
+		if p.DelayedBinding {
+			start = store.PvcNodeSelected
+		}
+		attribute(string(PVCBind), p.Events, start, store.PvcBound, ComponentPVController, totals)
+	}
+	for _, p := range pods {
+		attribute(string(PodCreation), p.Events, store.PodAdded, store.PodReady, ComponentKubelet, totals)
+	}
+
+	stageTotals := make(map[string]time.Duration)
+	for _, l := range totals {
+		stageTotals[l.Stage] += l.Total
+	}
+	latencies := make([]ComponentLatency, 0, len(totals))
+	for _, l := range totals {
+		if stageTotals[l.Stage] > 0 {
+			l.Share = 100 * float64(l.Total) / float64(stageTotals[l.Stage])
+		}
+		latencies = append(latencies, *l)
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		if latencies[i].Stage != latencies[j].Stage {
+			return latencies[i].Stage < latencies[j].Stage
+		}
+		if latencies[i].Total != latencies[j].Total {
+			return latencies[i].Total > latencies[j].Total
+		}
+		return latencies[i].Component < latencies[j].Component
+	})
+	return latencies
+}
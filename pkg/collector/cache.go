@@ -0,0 +1,197 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// metricsCacheVersion is a format of cached metrics, it must be bumped whenever metrics gain fields or stages,
+// so caches written by older versions are recomputed instead of served without new data
+const metricsCacheVersion = 1
+
+// cachedMetrics is serializable form of TestCaseMetrics, stage metrics are split by stage type
+// because JSON can't hold interface keys
+type cachedMetrics struct {
+	Version                   int
+	Pods                      []PodMetrics
+	PVCs                      []PVCMetrics
+	PodStageMetrics           map[PodStage]DurationOfStage
+	PVCStageMetrics           map[PVCStage]DurationOfStage
+	EntityNumberMetrics       []store.NumberEntities
+	ResourceUsageMetrics      []store.ResourceUsage
+	AssertionResults          []store.AssertionResult
+	Orphans                   []store.Orphan
+	TeardownLatencies         []store.TeardownLatency
+	TeardownTiers             []store.TeardownTier
+	Comparisons               []store.Comparison
+	RampMetrics               []RampStageMetrics
+	MountChecks               []store.MountCheck
+	VolumeStats               []store.VolumeStat
+	MountOptionResults        []store.MountOptionResult
+	CapacityFillResults       []store.CapacityFillResult
+	MountRecoveryResults      []store.MountRecoveryResult
+	ConcurrentWriteResults    []store.ConcurrentWriteResult
+	ControllerFailoverResults []store.ControllerFailoverResult
+	DetachProbeResults        []store.DetachProbeResult
+	FaultWindows              []store.FaultWindow
+	LatencySamples            []store.LatencySample
+	NodeWarnings              []NodeWarning
+	SchedulingChurn           []SchedulingChurn
+	DriverOutages             []DriverOutage
+	SnapshotReadiness         *SnapshotReadiness
+	AuditEntries              []store.AuditEntry
+	ComponentLatencies        []ComponentLatency
+	Outliers                  []EntityOutlier
+	OutlierCauses             []OutlierCause
+	ExpansionOutcomes         []store.ExpansionOutcome
+	Timeline                  []store.TimelineEntry
+	EventsPerSecond           map[store.EventTypeEnum]map[int64]int
+}
+
+// getCachedMetrics returns metrics of test case stored in db, they are dropped by store whenever new data arrives
+func (mc *MetricsCollector) getCachedMetrics(tc *store.TestCase) (TestCaseMetrics, bool) {
+	cache, err := mc.db.GetMetricsCache(tc.ID)
+	if err != nil {
+		log.Debugf("Can't get metrics cache for test case %d; error=%v", tc.ID, err)
+		return TestCaseMetrics{}, false
+	}
+	if cache == nil {
+		return TestCaseMetrics{}, false
+	}
+
+	var cached cachedMetrics
+	if err := json.Unmarshal(cache.Data, &cached); err != nil {
+		log.Debugf("Can't decode metrics cache for test case %d; error=%v", tc.ID, err)
+		return TestCaseMetrics{}, false
+	}
+	if cached.Version != metricsCacheVersion {
+		log.Debugf("Metrics cache for test case %d has version %d, expected %d", tc.ID, cached.Version, metricsCacheVersion)
+		return TestCaseMetrics{}, false
+	}
+
+	stageMetrics := make(map[interface{}]DurationOfStage)
+	for k, v := range cached.PodStageMetrics {
+		stageMetrics[k] = v
+	}
+	for k, v := range cached.PVCStageMetrics {
+		stageMetrics[k] = v
+	}
+
+	// Test case itself isn't cached, since its status can change without new events
+	return TestCaseMetrics{
+		TestCase:                  *tc,
+		Pods:                      cached.Pods,
+		PVCs:                      cached.PVCs,
+		StageMetrics:              stageMetrics,
+		EntityNumberMetrics:       cached.EntityNumberMetrics,
+		ResourceUsageMetrics:      cached.ResourceUsageMetrics,
+		AssertionResults:          cached.AssertionResults,
+		Orphans:                   cached.Orphans,
+		TeardownLatencies:         cached.TeardownLatencies,
+		TeardownTiers:             cached.TeardownTiers,
+		Comparisons:               cached.Comparisons,
+		RampMetrics:               cached.RampMetrics,
+		MountChecks:               cached.MountChecks,
+		VolumeStats:               cached.VolumeStats,
+		MountOptionResults:        cached.MountOptionResults,
+		CapacityFillResults:       cached.CapacityFillResults,
+		MountRecoveryResults:      cached.MountRecoveryResults,
+		ConcurrentWriteResults:    cached.ConcurrentWriteResults,
+		ControllerFailoverResults: cached.ControllerFailoverResults,
+		DetachProbeResults:        cached.DetachProbeResults,
+		FaultWindows:              cached.FaultWindows,
+		LatencySamples:            cached.LatencySamples,
+		NodeWarnings:              cached.NodeWarnings,
+		SchedulingChurn:           cached.SchedulingChurn,
+		DriverOutages:             cached.DriverOutages,
+		SnapshotReadiness:         cached.SnapshotReadiness,
+		AuditEntries:              cached.AuditEntries,
+		ComponentLatencies:        cached.ComponentLatencies,
+		Outliers:                  cached.Outliers,
+		OutlierCauses:             cached.OutlierCauses,
+		ExpansionOutcomes:         cached.ExpansionOutcomes,
+		Timeline:                  cached.Timeline,
+		EventsPerSecond:           cached.EventsPerSecond,
+	}, true
+}
+
+func (mc *MetricsCollector) saveCachedMetrics(tcMetrics *TestCaseMetrics) {
+	cached := cachedMetrics{
+		Version:                   metricsCacheVersion,
+		Pods:                      tcMetrics.Pods,
+		PVCs:                      tcMetrics.PVCs,
+		PodStageMetrics:           make(map[PodStage]DurationOfStage),
+		PVCStageMetrics:           make(map[PVCStage]DurationOfStage),
+		EntityNumberMetrics:       tcMetrics.EntityNumberMetrics,
+		ResourceUsageMetrics:      tcMetrics.ResourceUsageMetrics,
+		AssertionResults:          tcMetrics.AssertionResults,
+		Orphans:                   tcMetrics.Orphans,
+		TeardownLatencies:         tcMetrics.TeardownLatencies,
+		TeardownTiers:             tcMetrics.TeardownTiers,
+		Comparisons:               tcMetrics.Comparisons,
+		RampMetrics:               tcMetrics.RampMetrics,
+		MountChecks:               tcMetrics.MountChecks,
+		VolumeStats:               tcMetrics.VolumeStats,
+		MountOptionResults:        tcMetrics.MountOptionResults,
+		CapacityFillResults:       tcMetrics.CapacityFillResults,
+		MountRecoveryResults:      tcMetrics.MountRecoveryResults,
+		ConcurrentWriteResults:    tcMetrics.ConcurrentWriteResults,
+		ControllerFailoverResults: tcMetrics.ControllerFailoverResults,
+		DetachProbeResults:        tcMetrics.DetachProbeResults,
+		FaultWindows:              tcMetrics.FaultWindows,
+		LatencySamples:            tcMetrics.LatencySamples,
+		NodeWarnings:              tcMetrics.NodeWarnings,
+		SchedulingChurn:           tcMetrics.SchedulingChurn,
+		DriverOutages:             tcMetrics.DriverOutages,
+		SnapshotReadiness:         tcMetrics.SnapshotReadiness,
+		AuditEntries:              tcMetrics.AuditEntries,
+		ComponentLatencies:        tcMetrics.ComponentLatencies,
+		Outliers:                  tcMetrics.Outliers,
+		OutlierCauses:             tcMetrics.OutlierCauses,
+		ExpansionOutcomes:         tcMetrics.ExpansionOutcomes,
+		Timeline:                  tcMetrics.Timeline,
+		EventsPerSecond:           tcMetrics.EventsPerSecond,
+	}
+	for k, v := range tcMetrics.StageMetrics {
+		switch stage := k.(type) {
+		case PodStage:
+			cached.PodStageMetrics[stage] = v
+		case PVCStage:
+			cached.PVCStageMetrics[stage] = v
+		}
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		log.Debugf("Can't encode metrics cache for test case %d; error=%v", tcMetrics.TestCase.ID, err)
+		return
+	}
+	err = mc.db.SaveMetricsCache(&store.MetricsCache{
+		TcID:             tcMetrics.TestCase.ID,
+		Data:             data,
+		UpdatedTimestamp: time.Now(),
+	})
+	if err != nil {
+		log.Debugf("Can't save metrics cache for test case %d; error=%v", tcMetrics.TestCase.ID, err)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+	log "github.com/sirupsen/logrus"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+// CapabilityCheck is a capability declared by driver cross-checked with behavior observed during run
+type CapabilityCheck struct {
+	Name     string
+	Declared string
+	// Observed is empty if run didn't exercise the capability or it can't be observed
+	Observed string
+	Mismatch bool
+}
+
+// GetCapabilityChecks returns capabilities declared by driver of the run with mismatches to behavior observed
+// in its test cases flagged, nil if capabilities weren't probed when run started
+func GetCapabilityChecks(run store.TestRun, metrics []TestCaseMetrics) []CapabilityCheck {
+	if run.Capabilities == "" {
+		return nil
+	}
+	var caps k8sclient.DriverCapabilities
+	if err := json.Unmarshal([]byte(run.Capabilities), &caps); err != nil {
+		log.Errorf("Can't parse capabilities of run %s; error=%v", run.Name, err)
+		return nil
+	}
+
+	events := make(map[store.EventTypeEnum]int)
+	var expansions []store.ExpansionOutcome
+	var samples []store.LatencySample
+	for _, tc := range metrics {
+		for eventType, perSecond := range tc.EventsPerSecond {
+			for _, n := range perSecond {
+				events[eventType] += n
+			}
+		}
+		expansions = append(expansions, tc.ExpansionOutcomes...)
+		samples = append(samples, tc.LatencySamples...)
+	}
+
+	registered := "CSIDriver " + caps.Driver
+	if !caps.Registered {
+		registered = "no CSIDriver object, defaults apply"
+	}
+	checks := []CapabilityCheck{
+		{Name: "driver", Declared: registered},
+		attachRequiredCheck(caps, events, strings.Contains(run.NotObservable, "VolumeAttachments")),
+		{Name: "podInfoOnMount", Declared: strconv.FormatBool(caps.PodInfoOnMount)},
+		lifecycleModesCheck(caps, events),
+		{Name: "fsGroupPolicy", Declared: caps.FSGroupPolicy},
+		expansionCheck(caps, expansions),
+		{Name: "volumeBindingMode", Declared: caps.VolumeBindingMode},
+	}
+	snapshotClasses := strings.Join(caps.SnapshotClasses, ", ")
+	if snapshotClasses == "" {
+		snapshotClasses = "none"
+	}
+	checks = append(checks, CapabilityCheck{Name: "snapshotClasses", Declared: snapshotClasses})
+	if check, ok := ipFamiliesCheck(caps, samples); ok {
+		checks = append(checks, check)
+	}
+	if caps.Expected != nil {
+		checks = append(checks, profileCheck(caps))
+	}
+	return checks
+}
+
+// profileCheck compares declared capabilities with the ones driver profile of the run expects
+func profileCheck(caps k8sclient.DriverCapabilities) CapabilityCheck {
+	check := CapabilityCheck{Name: "driverProfile", Declared: caps.Expected.Profile, Observed: "as expected"}
+	if unexpected := caps.Unexpected(); len(unexpected) != 0 {
+		check.Observed = "unexpected " + strings.Join(unexpected, ", ")
+		check.Mismatch = true
+	}
+	return check
+}
+
+// ipFamiliesCheck compares IP families of cluster nodes with node plugin to controller connections made over IPv6,
+// false if neither families were probed nor connections made
+func ipFamiliesCheck(caps k8sclient.DriverCapabilities, samples []store.LatencySample) (CapabilityCheck, bool) {
+	check := CapabilityCheck{Name: "ipFamilies", Declared: strings.Join(caps.IPFamilies, ", ")}
+	connected, failed := 0, 0
+	for _, ls := range samples {
+		switch ls.Metric {
+		case store.IPv6ConnectMetric:
+			connected++
+		case store.IPv6ConnectFailedMetric:
+			failed++
+		}
+	}
+	if connected+failed == 0 {
+		return check, len(caps.IPFamilies) != 0
+	}
+	check.Observed = strconv.Itoa(connected) + "/" + strconv.Itoa(connected+failed) + " node-controller connections over IPv6"
+	check.Mismatch = failed > 0
+	return check, true
+}
+
+// attachRequiredCheck compares attachRequired with VolumeAttachments seen for pods consuming bound PVCs
+func attachRequiredCheck(caps k8sclient.DriverCapabilities, events map[store.EventTypeEnum]int, notObservable bool) CapabilityCheck {
+	check := CapabilityCheck{Name: "attachRequired", Declared: strconv.FormatBool(caps.AttachRequired)}
+	attached := events[store.PvcAttachStarted]+events[store.PvcAttachEnded] > 0
+	if notObservable || (!attached && (events[store.PvcBound] == 0 || events[store.PodReady] == 0)) {
+		return check
+	}
+	if attached {
+		check.Observed = "VolumeAttachments created"
+	} else {
+		check.Observed = "no VolumeAttachments"
+	}
+	check.Mismatch = attached != caps.AttachRequired
+	return check
+}
+
+// lifecycleModesCheck compares declared lifecycle modes with PVCs bound and inline volumes published during run
+func lifecycleModesCheck(caps k8sclient.DriverCapabilities, events map[store.EventTypeEnum]int) CapabilityCheck {
+	check := CapabilityCheck{Name: "volumeLifecycleModes", Declared: strings.Join(caps.VolumeLifecycleModes, ", ")}
+	var used []string
+	if events[store.PvcBound] > 0 {
+		used = append(used, string(storagev1.VolumeLifecyclePersistent))
+		check.Mismatch = !caps.DeclaresLifecycleMode(storagev1.VolumeLifecyclePersistent)
+	}
+	if events[store.EphemeralPublishStarted]+events[store.EphemeralPublishEnded] > 0 {
+		used = append(used, string(storagev1.VolumeLifecycleEphemeral))
+		check.Mismatch = check.Mismatch || !caps.DeclaresLifecycleMode(storagev1.VolumeLifecycleEphemeral)
+	}
+	check.Observed = strings.Join(used, ", ")
+	return check
+}
+
+// expansionCheck compares allowVolumeExpansion of storage class with how expansions of the run ended
+func expansionCheck(caps k8sclient.DriverCapabilities, outcomes []store.ExpansionOutcome) CapabilityCheck {
+	check := CapabilityCheck{Name: "allowVolumeExpansion", Declared: strconv.FormatBool(caps.AllowVolumeExpansion)}
+	if len(outcomes) == 0 {
+		return check
+	}
+	completed := 0
+	for _, o := range outcomes {
+		if o.Outcome == store.ExpansionCompleted {
+			completed++
+		}
+	}
+	check.Observed = strconv.Itoa(completed) + "/" + strconv.Itoa(len(outcomes)) + " expansions completed"
+	check.Mismatch = (completed > 0) != caps.AllowVolumeExpansion
+	return check
+}
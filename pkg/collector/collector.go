@@ -37,8 +37,10 @@ type PVCStage Stage
 type PodStage Stage
 
 const (
-	// PVCBind stage
+	// PVCBind stage, measured from node selection for delayed-binding PVCs
 	PVCBind PVCStage = "PVCBind"
+	// PVCConsumerWait stage, time delayed-binding PVC waited for its consumer pod to be scheduled
+	PVCConsumerWait PVCStage = "PVCConsumerWait"
 	// PVCAttachment stage
 	PVCAttachment PVCStage = "PVCAttachment"
 	// PVCCreation stage
@@ -52,6 +54,10 @@ const (
 	PodCreation PodStage = "PodCreation"
 	// PodDeletion stage
 	PodDeletion PodStage = "PodDeletion"
+	// EphemeralPublish stage
+	EphemeralPublish PodStage = "EphemeralPublish"
+	// EphemeralUnpublish stage
+	EphemeralUnpublish PodStage = "EphemeralUnpublish"
 )
 
 // DurationOfStage represents staging time
@@ -65,12 +71,31 @@ type DurationOfStage struct {
 type PVCMetrics struct {
 	PVC     store.Entity
 	Metrics map[PVCStage]time.Duration
+	// Added is the time PVC was added, zero if its creation wasn't observed
+	Added time.Time
+	// Events is the full timeline of the PVC
+	Events []store.Event
+	// DelayedBinding is set for PVCs of WaitForFirstConsumer storage class which waited for consumer pod
+	DelayedBinding bool
 }
 
 // PodMetrics contains Pod and corresponding metrics
 type PodMetrics struct {
 	Pod     store.Entity
 	Metrics map[PodStage]time.Duration
+	// Added is the time pod was added, zero if its creation wasn't observed
+	Added time.Time
+	// Events is the full timeline of the pod
+	Events []store.Event
+	// Restarts, OOMKills and CrashLoops count container instability events of the pod
+	Restarts   int
+	OOMKills   int
+	CrashLoops int
+}
+
+// IsUnstable checks whether any container of the pod restarted or got into CrashLoopBackOff
+func (pm PodMetrics) IsUnstable() bool {
+	return pm.Restarts != 0 || pm.OOMKills != 0 || pm.CrashLoops != 0
 }
 
 // TestCaseMetrics contains metrics for each testcase
@@ -80,14 +105,45 @@ type TestCaseMetrics struct {
 	PVCs         []PVCMetrics
 	StageMetrics map[interface{}]DurationOfStage
 
-	EntityNumberMetrics  []store.NumberEntities
-	ResourceUsageMetrics []store.ResourceUsage
+	EntityNumberMetrics       []store.NumberEntities
+	ResourceUsageMetrics      []store.ResourceUsage
+	AssertionResults          []store.AssertionResult
+	Orphans                   []store.Orphan
+	TeardownLatencies         []store.TeardownLatency
+	TeardownTiers             []store.TeardownTier
+	Comparisons               []store.Comparison
+	RampMetrics               []RampStageMetrics
+	MountChecks               []store.MountCheck
+	VolumeStats               []store.VolumeStat
+	MountOptionResults        []store.MountOptionResult
+	CapacityFillResults       []store.CapacityFillResult
+	MountRecoveryResults      []store.MountRecoveryResult
+	ConcurrentWriteResults    []store.ConcurrentWriteResult
+	ControllerFailoverResults []store.ControllerFailoverResult
+	DetachProbeResults        []store.DetachProbeResult
+	FaultWindows              []store.FaultWindow
+	LatencySamples            []store.LatencySample
+	NodeWarnings              []NodeWarning
+	SchedulingChurn           []SchedulingChurn
+	DriverOutages             []DriverOutage
+	SnapshotReadiness         *SnapshotReadiness
+	AuditEntries              []store.AuditEntry
+	ComponentLatencies        []ComponentLatency
+	Outliers                  []EntityOutlier
+	OutlierCauses             []OutlierCause
+	ExpansionOutcomes         []store.ExpansionOutcome
+	// Timeline is merged timeline of all sources, empty if it wasn't merged for test case
+	Timeline []store.TimelineEntry
+	// EventsPerSecond holds number of events of each type by unix second they happened at
+	EventsPerSecond map[store.EventTypeEnum]map[int64]int
 }
 
 // MetricsCollection contains collection of TestCaseMetrics
 type MetricsCollection struct {
 	Run              store.TestRun
 	TestCasesMetrics []TestCaseMetrics
+	BackendLeaks     []store.BackendLeak
+	Capabilities     []CapabilityCheck
 }
 
 // MetricsCollector contains db store and metrics collection
@@ -134,55 +190,244 @@ func (mc *MetricsCollector) Collect(runName string) (*MetricsCollection, error)
 		fmt.Println("Collecting metrics")
 		bar = pb.Default.Start(len(testCases))
 	}
-	for i, tc := range testCases {
+	for i := range testCases {
 		if bar != nil {
 			bar.Increment()
 		}
-		var (
-			tcPodsMetrics                          []PodMetrics
-			tcPVCsMetrics                          []PVCMetrics
-			tcPodsStageMetrics, tcPVCSStageMetrics map[interface{}]DurationOfStage
-		)
-
-		tcPodsMetrics, tcPodsStageMetrics, err := mc.getPodsMetrics(&testCases[i])
-		if err != nil {
-			log.Errorf("Can't get pods with events for test case %d", tc.ID)
-		}
+		testCasesMetrics = append(testCasesMetrics, mc.CollectTestCase(&testCases[i]))
+	}
+	if bar != nil {
+		bar.Finish()
+	}
+	backendLeaks, err := mc.db.GetBackendLeaks(store.Conditions{"run_id": runs[0].ID}, "", 0)
+	if err != nil {
+		log.Errorf("Couldn't get backend leaks for test run with name %s", runName)
+		return nil, err
+	}
+	capabilities := GetCapabilityChecks(runs[0], testCasesMetrics)
+	mc.metricsCache[runName] = &MetricsCollection{runs[0], testCasesMetrics, backendLeaks, capabilities}
+	return mc.metricsCache[runName], nil
+}
 
-		tcPVCsMetrics, tcPVCSStageMetrics, err = mc.getPVCsMetrics(&testCases[i])
-		if err != nil {
-			log.Errorf("Can't get pvcs with events for test case %d", tc.ID)
-		}
+// CollectTestCase consolidates the metrics of single test case, reusing metrics cached in db when possible
+func (mc *MetricsCollector) CollectTestCase(tc *store.TestCase) TestCaseMetrics {
+	if metrics, ok := mc.getCachedMetrics(tc); ok {
+		return metrics
+	}
 
-		tcNumber, err := mc.db.GetNumberEntities(store.Conditions{"tc_id": tc.ID}, "", 0)
-		if err != nil {
-			log.Errorf("Failed to get Number Entities for test case with name %s", tc.Name)
-		}
+	// Metrics computed from partially read data must not be cached
+	complete := true
 
-		resUsage, err := mc.db.GetResourceUsage(store.Conditions{"tc_id": tc.ID}, "", 0)
-		if err != nil {
-			log.Errorf("Failed to get Number Entities for test case with name %s", tc.Name)
-		}
+	tcPodsMetrics, tcPodsStageMetrics, err := mc.getPodsMetrics(tc)
+	if err != nil {
+		log.Errorf("Can't get pods with events for test case %d", tc.ID)
+		complete = false
+	}
 
-		stageMetrics := make(map[interface{}]DurationOfStage)
-		mergeStageMetrics(stageMetrics, tcPodsStageMetrics)
-		mergeStageMetrics(stageMetrics, tcPVCSStageMetrics)
-
-		testCaseMetrics := TestCaseMetrics{
-			TestCase:             tc,
-			Pods:                 tcPodsMetrics,
-			PVCs:                 tcPVCsMetrics,
-			StageMetrics:         stageMetrics,
-			EntityNumberMetrics:  tcNumber,
-			ResourceUsageMetrics: resUsage,
-		}
-		testCasesMetrics = append(testCasesMetrics, testCaseMetrics)
+	tcPVCsMetrics, tcPVCSStageMetrics, err := mc.getPVCsMetrics(tc)
+	if err != nil {
+		log.Errorf("Can't get pvcs with events for test case %d", tc.ID)
+		complete = false
 	}
-	if bar != nil {
-		bar.Finish()
+
+	tcNumber, err := mc.getNumberEntities(tc)
+	if err != nil {
+		log.Errorf("Failed to get Number Entities for test case with name %s", tc.Name)
+		complete = false
 	}
-	mc.metricsCache[runName] = &MetricsCollection{runs[0], testCasesMetrics}
-	return mc.metricsCache[runName], nil
+
+	resUsage, err := mc.getResourceUsage(tc)
+	if err != nil {
+		log.Errorf("Failed to get resource usage for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	assertionResults, err := mc.db.GetAssertionResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get assertion results for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	eventsPerSecond, err := mc.getEventsPerSecond(tc)
+	if err != nil {
+		log.Errorf("Failed to get events for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	orphans, err := mc.db.GetOrphans(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get orphans for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	teardownLatencies, err := mc.db.GetTeardownLatencies(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get teardown latencies for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	teardownTiers, err := mc.db.GetTeardownTiers(store.Conditions{"tc_id": tc.ID}, "position", 0)
+	if err != nil {
+		log.Errorf("Failed to get teardown tiers for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	comparisons, err := mc.db.GetComparisons(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get comparisons for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	rampMetrics, err := mc.getRampMetrics(tc)
+	if err != nil {
+		log.Errorf("Failed to get ramp stages for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	mountChecks, err := mc.db.GetMountChecks(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get mount checks for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	volumeStats, err := mc.db.GetVolumeStats(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get volume stats for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	mountOptionResults, err := mc.db.GetMountOptionResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get mount option results for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	capacityFillResults, err := mc.db.GetCapacityFillResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get capacity fill results for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	mountRecoveryResults, err := mc.db.GetMountRecoveryResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get mount recovery results for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	concurrentWriteResults, err := mc.db.GetConcurrentWriteResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get concurrent write results for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	controllerFailoverResults, err := mc.db.GetControllerFailoverResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get controller failover results for test case with name %s", tc.Name)
+		complete = false
+	}
+	detachProbeResults, err := mc.db.GetDetachProbeResults(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get detach probe results for test case with name %s", tc.Name)
+		complete = false
+	}
+	faultWindows, err := mc.db.GetFaultWindows(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get fault windows for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	latencySamples, err := mc.db.GetLatencySamples(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get latency samples for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	expansionOutcomes, err := mc.db.GetExpansionOutcomes(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get expansion outcomes for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	nodeWarnings, err := mc.getNodeWarnings(tc)
+	if err != nil {
+		log.Errorf("Failed to get node warnings for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	driverOutages, err := mc.getDriverOutages(tc, tcPVCsMetrics, tcPodsMetrics)
+	if err != nil {
+		log.Errorf("Failed to get driver outages for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	auditEntries, err := mc.db.GetAuditEntries(store.Conditions{"tc_id": tc.ID}, "timestamp", 0)
+	if err != nil {
+		log.Errorf("Failed to get audit entries for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	timeline, err := mc.db.GetTimelineEntries(store.Conditions{"tc_id": tc.ID}, "timestamp", 0)
+	if err != nil {
+		log.Errorf("Failed to get timeline for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	snapshotReadiness, err := mc.getSnapshotReadiness(tc)
+	if err != nil {
+		log.Errorf("Failed to get snapshot readiness for test case with name %s", tc.Name)
+		complete = false
+	}
+
+	placements, err := mc.db.GetEntityPlacements(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Failed to get entity placements for test case with name %s", tc.Name)
+		complete = false
+	}
+	outliers, outlierCauses := FindOutliers(tcPodsMetrics, tcPVCsMetrics, placements)
+
+	stageMetrics := make(map[interface{}]DurationOfStage)
+	mergeStageMetrics(stageMetrics, tcPodsStageMetrics)
+	mergeStageMetrics(stageMetrics, tcPVCSStageMetrics)
+
+	metrics := TestCaseMetrics{
+		TestCase:                  *tc,
+		Pods:                      tcPodsMetrics,
+		PVCs:                      tcPVCsMetrics,
+		StageMetrics:              stageMetrics,
+		EntityNumberMetrics:       tcNumber,
+		ResourceUsageMetrics:      resUsage,
+		AssertionResults:          assertionResults,
+		Orphans:                   orphans,
+		TeardownLatencies:         teardownLatencies,
+		TeardownTiers:             teardownTiers,
+		Comparisons:               comparisons,
+		RampMetrics:               rampMetrics,
+		MountChecks:               mountChecks,
+		VolumeStats:               volumeStats,
+		MountOptionResults:        mountOptionResults,
+		CapacityFillResults:       capacityFillResults,
+		MountRecoveryResults:      mountRecoveryResults,
+		ConcurrentWriteResults:    concurrentWriteResults,
+		ControllerFailoverResults: controllerFailoverResults,
+		DetachProbeResults:        detachProbeResults,
+		FaultWindows:              faultWindows,
+		LatencySamples:            latencySamples,
+		NodeWarnings:              nodeWarnings,
+		SchedulingChurn:           getSchedulingChurn(tcPodsMetrics),
+		DriverOutages:             driverOutages,
+		SnapshotReadiness:         snapshotReadiness,
+		AuditEntries:              auditEntries,
+		ComponentLatencies:        getComponentLatencies(tcPVCsMetrics, tcPodsMetrics),
+		Outliers:                  outliers,
+		OutlierCauses:             outlierCauses,
+		ExpansionOutcomes:         expansionOutcomes,
+		Timeline:                  timeline,
+		EventsPerSecond:           eventsPerSecond,
+	}
+	if complete {
+		mc.saveCachedMetrics(&metrics)
+	}
+	return metrics
 }
 
 func (mc *MetricsCollector) getPodsMetrics(
@@ -198,9 +443,11 @@ func (mc *MetricsCollector) getPodsMetrics(
 
 	for pod, events := range entitiesWithEvents {
 		timestamps := make(map[store.EventTypeEnum]time.Time)
+		counts := make(map[store.EventTypeEnum]int)
 
 		for _, e := range events {
 			timestamps[e.Type] = e.Timestamp
+			counts[e.Type]++
 		}
 		metrics := make(map[PodStage]time.Duration)
 
@@ -210,7 +457,32 @@ func (mc *MetricsCollector) getPodsMetrics(
 		stageMetrics[PodCreation] = append(stageMetrics[PodCreation], metrics[PodCreation])
 		stageMetrics[PodDeletion] = append(stageMetrics[PodDeletion], metrics[PodDeletion])
 
-		podMetrics = append(podMetrics, PodMetrics{pod, metrics})
+		// Only pods with CSI inline volumes have ephemeral events
+		if _, ok := timestamps[store.EphemeralPublishEnded]; ok {
+			metrics[EphemeralPublish] = timestamps[store.EphemeralPublishEnded].Sub(timestamps[store.EphemeralPublishStarted])
+			stageMetrics[EphemeralPublish] = append(stageMetrics[EphemeralPublish], metrics[EphemeralPublish])
+		}
+		if _, ok := timestamps[store.EphemeralUnpublishEnded]; ok {
+			metrics[EphemeralUnpublish] = timestamps[store.EphemeralUnpublishEnded].Sub(timestamps[store.EphemeralUnpublishStarted])
+			stageMetrics[EphemeralUnpublish] = append(stageMetrics[EphemeralUnpublish], metrics[EphemeralUnpublish])
+		}
+
+		for _, cs := range getCustomStages(store.Pod) {
+			if d, ok := customStageDuration(timestamps, cs); ok {
+				metrics[PodStage(cs.Name)] = d
+				stageMetrics[PodStage(cs.Name)] = append(stageMetrics[PodStage(cs.Name)], d)
+			}
+		}
+
+		podMetrics = append(podMetrics, PodMetrics{
+			Pod:        pod,
+			Metrics:    metrics,
+			Added:      timestamps[store.PodAdded],
+			Events:     events,
+			Restarts:   counts[store.PodContainerRestarted],
+			OOMKills:   counts[store.PodOOMKilled],
+			CrashLoops: counts[store.PodCrashLoopBackOff],
+		})
 	}
 	return podMetrics, calculateMetricsOfStages(stageMetrics), nil
 }
@@ -234,7 +506,15 @@ func (mc *MetricsCollector) getPVCsMetrics(
 		}
 		metrics := make(map[PVCStage]time.Duration)
 
-		metrics[PVCBind] = timestamps[store.PvcBound].Sub(timestamps[store.PvcAdded])
+		// Delayed-binding PVC isn't provisioned until its consumer is scheduled, so bind is split in two stages
+		selected, delayed := timestamps[store.PvcNodeSelected]
+		if delayed {
+			metrics[PVCConsumerWait] = selected.Sub(timestamps[store.PvcAdded])
+			metrics[PVCBind] = timestamps[store.PvcBound].Sub(selected)
+			stageMetrics[PVCConsumerWait] = append(stageMetrics[PVCConsumerWait], metrics[PVCConsumerWait])
+		} else {
+			metrics[PVCBind] = timestamps[store.PvcBound].Sub(timestamps[store.PvcAdded])
+		}
 		metrics[PVCAttachment] = timestamps[store.PvcAttachEnded].Sub(timestamps[store.PvcAttachStarted])
 		metrics[PVCCreation] = timestamps[store.PvcAttachEnded].Sub(timestamps[store.PvcAdded])
 		metrics[PVCDeletion] = timestamps[store.PvcDeletingEnded].Sub(timestamps[store.PvcDeletingStarted])
@@ -246,12 +526,41 @@ func (mc *MetricsCollector) getPVCsMetrics(
 		stageMetrics[PVCDeletion] = append(stageMetrics[PVCDeletion], metrics[PVCDeletion])
 		stageMetrics[PVCUnattachment] = append(stageMetrics[PVCUnattachment], metrics[PVCUnattachment])
 
-		pvcMetrics = append(pvcMetrics, PVCMetrics{pvc, metrics})
+		for _, cs := range getCustomStages(store.Pvc) {
+			if d, ok := customStageDuration(timestamps, cs); ok {
+				metrics[PVCStage(cs.Name)] = d
+				stageMetrics[PVCStage(cs.Name)] = append(stageMetrics[PVCStage(cs.Name)], d)
+			}
+		}
+
+		pvcMetrics = append(pvcMetrics, PVCMetrics{
+			PVC:            pvc,
+			Metrics:        metrics,
+			Added:          timestamps[store.PvcAdded],
+			Events:         events,
+			DelayedBinding: delayed,
+		})
 	}
 
 	return pvcMetrics, calculateMetricsOfStages(stageMetrics), nil
 }
 
+func (mc *MetricsCollector) getEventsPerSecond(tc *store.TestCase) (map[store.EventTypeEnum]map[int64]int, error) {
+	eventsPerSecond := make(map[store.EventTypeEnum]map[int64]int)
+
+	events, err := mc.db.GetEvents(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		return eventsPerSecond, err
+	}
+	for _, e := range events {
+		if eventsPerSecond[e.Type] == nil {
+			eventsPerSecond[e.Type] = make(map[int64]int)
+		}
+		eventsPerSecond[e.Type][e.Timestamp.Unix()]++
+	}
+	return eventsPerSecond, nil
+}
+
 func calculateMetricsOfStages(stageMetrics map[interface{}][]time.Duration) map[interface{}]DurationOfStage {
 	calculatedMetrics := make(map[interface{}]DurationOfStage)
 	for k, v := range stageMetrics {
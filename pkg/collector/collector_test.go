@@ -17,6 +17,7 @@
 package collector
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -69,7 +70,13 @@ func (suite *CollectorTestSuit) SetupSuite() {
 		TcID:   testCase.ID,
 		Type:   store.Pod,
 	}
-	_ = suite.db.SaveEntities([]*store.Entity{entityPVC1, entityPVC2, entityPod})
+	entityNode := &store.Entity{
+		Name:   "node1",
+		K8sUID: "node1-b0db734f",
+		TcID:   testCase.ID,
+		Type:   store.Node,
+	}
+	_ = suite.db.SaveEntities([]*store.Entity{entityPVC1, entityPVC2, entityPod, entityNode})
 
 	startTime := time.Now()
 
@@ -217,6 +224,15 @@ func (suite *CollectorTestSuit) SetupSuite() {
 			Type:      store.PodDeleted,
 			Timestamp: startTime.Add(time.Second * 25),
 		},
+		// Node events
+		{
+			Name:      "kernel io error node",
+			TcID:      testCase.ID,
+			EntityID:  entityNode.ID,
+			Type:      store.NodeKernelIOError,
+			Timestamp: startTime.Add(time.Second * 10),
+			Message:   "blk_update_request: I/O error, dev sdb, sector 0",
+		},
 	}
 	_ = suite.db.SaveEvents(events)
 }
@@ -251,6 +267,631 @@ func (suite *CollectorTestSuit) TestCollectMetrics() {
 	suite.Equal(tc.StageMetrics[PodCreation].Max.Seconds(), float64(7))
 	suite.Equal(tc.StageMetrics[PodCreation].Min.Seconds(), float64(7))
 	suite.Equal(tc.StageMetrics[PodCreation].Avg.Seconds(), float64(7))
+
+	var pvcAdds int
+	for _, count := range tc.EventsPerSecond[store.PvcAdded] {
+		pvcAdds += count
+	}
+	suite.Equal(2, pvcAdds)
+
+	suite.Len(tc.NodeWarnings, 1)
+	suite.Equal("node1", tc.NodeWarnings[0].Node)
+	suite.Contains(tc.NodeWarnings[0].Message, "I/O error")
+}
+
+func (suite *CollectorTestSuit) TestEvaluateAssertions() {
+	mc, err := suite.collector.Collect("test run 1")
+	suite.Nil(err)
+	tc := mc.TestCasesMetrics[0]
+
+	assertions := &Assertions{
+		MaxAvgBindTime:     time.Second,
+		MinThroughput:      1,
+		ZeroFailedAttaches: true,
+	}
+	results := assertions.Evaluate(&tc, time.Minute)
+	suite.Equal(3, len(results))
+
+	suite.Equal("MaxAvgBindTime", results[0].Name)
+	suite.False(results[0].Passed)
+	suite.Equal("MinThroughput", results[1].Name)
+	suite.True(results[1].Passed)
+	suite.Equal("ZeroFailedAttaches", results[2].Name)
+	suite.True(results[2].Passed)
+
+	suite.Empty((&Assertions{}).Evaluate(&tc, time.Minute))
+
+	tc.ResourceUsageMetrics = []store.ResourceUsage{
+		{PodName: "driver-controller-0", ContainerName: "driver", Mem: 100, CPU: 10},
+		{PodName: "driver-controller-0", ContainerName: "provisioner", Mem: 50, CPU: 5},
+		{PodName: "driver-node-abc", ContainerName: "driver", Mem: 30, CPU: 200},
+		{PodName: "driver-controller-0", ContainerName: "driver", Mem: 120, CPU: 10},
+		{PodName: "driver-controller-0", ContainerName: "provisioner", Mem: 60, CPU: 5},
+		{PodName: "driver-node-abc", ContainerName: "driver", Mem: 30, CPU: 150},
+	}
+	budgets := &Assertions{MaxControllerMemory: 150, MaxNodeCPU: 200}
+	results = budgets.Evaluate(&tc, time.Minute)
+	suite.Equal(2, len(results))
+	suite.False(results[0].Passed)
+	suite.Equal("180MiB (driver-controller-0)", results[0].Actual)
+	suite.True(results[1].Passed)
+	suite.Equal("200m (driver-node-abc)", results[1].Actual)
+}
+
+func (suite *CollectorTestSuit) TestMetricsCache() {
+	tcs, err := suite.db.GetTestCases(store.Conditions{"name": "test case 1"}, "", 1)
+	suite.NoError(err)
+	tc := tcs[0]
+
+	metrics := suite.collector.CollectTestCase(&tc)
+	cache, err := suite.db.GetMetricsCache(tc.ID)
+	suite.NoError(err)
+	suite.NotNil(cache)
+
+	cached := suite.collector.CollectTestCase(&tc)
+	suite.Equal(metrics.StageMetrics, cached.StageMetrics)
+	suite.Equal(len(metrics.PVCs), len(cached.PVCs))
+
+	// Cache written before it was versioned is recomputed
+	suite.NoError(suite.db.SaveMetricsCache(&store.MetricsCache{TcID: tc.ID, Data: []byte(`{"PVCs":[]}`), UpdatedTimestamp: time.Now()}))
+	_, ok := suite.collector.getCachedMetrics(&tc)
+	suite.False(ok)
+	recomputed := suite.collector.CollectTestCase(&tc)
+	suite.Equal(len(metrics.PVCs), len(recomputed.PVCs))
+	_, ok = suite.collector.getCachedMetrics(&tc)
+	suite.True(ok)
+
+	pods, err := suite.db.GetEntities(store.Conditions{"type": store.Pod}, "", 1)
+	suite.NoError(err)
+	err = suite.db.SaveEvents([]*store.Event{
+		{
+			Name:      "ready pod again",
+			TcID:      tc.ID,
+			EntityID:  pods[0].ID,
+			Type:      store.PodReady,
+			Timestamp: time.Now().Add(time.Hour),
+		},
+	})
+	suite.NoError(err)
+
+	cache, err = suite.db.GetMetricsCache(tc.ID)
+	suite.NoError(err)
+	suite.Nil(cache)
+}
+
+func (suite *CollectorTestSuit) TestParseSLOs() {
+	slos, err := ParseSLOs("PVCBind=10s@95, PodCreation=1m")
+	suite.NoError(err)
+	suite.Equal([]SLO{
+		{Stage: "PVCBind", Threshold: 10 * time.Second, Target: 95},
+		{Stage: "PodCreation", Threshold: time.Minute, Target: DefaultSLOTarget},
+	}, slos)
+
+	_, err = ParseSLOs("PVCBind")
+	suite.Error(err)
+	_, err = ParseSLOs("Unknown=1s")
+	suite.Error(err)
+	_, err = ParseSLOs("PVCBind=1s@120")
+	suite.Error(err)
+}
+
+func (suite *CollectorTestSuit) TestSLOBurnDown() {
+	start := time.Now()
+	var pvcs []PVCMetrics
+	// Every other PVC in the second minute takes too long to bind, the last one never binds
+	for i := 0; i < 12; i++ {
+		bind := time.Second
+		if i >= 6 && i%2 == 0 {
+			bind = time.Minute
+		}
+		pvcs = append(pvcs, PVCMetrics{
+			Added:   start.Add(time.Duration(i) * 10 * time.Second),
+			Metrics: map[PVCStage]time.Duration{PVCBind: bind},
+		})
+	}
+	pvcs = append(pvcs, PVCMetrics{Added: start.Add(2 * time.Minute), Metrics: map[PVCStage]time.Duration{PVCBind: -time.Second}})
+	tc := TestCaseMetrics{PVCs: pvcs}
+	slo := SLO{Stage: "PVCBind", Threshold: 10 * time.Second, Target: 90}
+
+	points := SLOBurnDown(tc, slo, 30*time.Second)
+	suite.Equal(13, len(points))
+	suite.Equal(100.0, points[5].Percent())
+	suite.Equal(4, points[9].Total)
+	suite.Equal(2, points[9].Met)
+
+	summary := SummarizeSLO(tc, slo, 30*time.Second)
+	suite.Equal(13, summary.Total)
+	suite.Equal(9, summary.Met)
+	suite.True(summary.Degraded)
+	suite.Equal(50.0, summary.Worst)
+}
+
+func (suite *CollectorTestSuit) TestRampMetrics() {
+	tcs, err := suite.db.GetTestCases(store.Conditions{"name": "test case 1"}, "", 1)
+	suite.NoError(err)
+	tc := tcs[0]
+
+	added := make(map[string]time.Time)
+	events, err := suite.db.GetEvents(store.Conditions{"tc_id": tc.ID}, "", 0)
+	suite.NoError(err)
+	for _, e := range events {
+		added[e.Name] = e.Timestamp
+	}
+
+	// Each PVC falls into its own stage
+	err = suite.db.SaveRampStages([]*store.RampStage{
+		{TcID: tc.ID, Concurrency: 1, StartTimestamp: added["added pvc 1"], EndTimestamp: added["added pvc 1"].Add(500 * time.Millisecond)},
+		{TcID: tc.ID, Concurrency: 2, StartTimestamp: added["added pvc 2"], EndTimestamp: added["added pvc 2"].Add(500 * time.Millisecond)},
+	})
+	suite.NoError(err)
+
+	metrics := suite.collector.CollectTestCase(&tc)
+	suite.Equal(2, len(metrics.RampMetrics))
+	suite.Equal(1, metrics.RampMetrics[0].Volumes)
+	suite.Equal(2*time.Second, metrics.RampMetrics[0].PVCBind.Avg)
+	suite.Equal(2, metrics.RampMetrics[1].Stage.Concurrency)
+	suite.Equal(time.Second, metrics.RampMetrics[1].PVCBind.Avg)
+}
+
+func (suite *CollectorTestSuit) TestDistributeLatencies() {
+	var samples []store.LatencySample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, store.LatencySample{
+			Metric: "Propagation",
+			Source: fmt.Sprintf("reader-%d", i%3),
+			Value:  time.Duration(i) * time.Millisecond,
+		})
+	}
+	samples = append(samples, store.LatencySample{Metric: "Write", Source: "writer", Value: time.Second})
+
+	distributions := DistributeLatencies(samples)
+	suite.Equal([]LatencyDistribution{
+		{
+			Metric:  "Propagation",
+			Count:   100,
+			Sources: 3,
+			Min:     time.Millisecond,
+			Avg:     50500 * time.Microsecond,
+			P50:     50 * time.Millisecond,
+			P90:     90 * time.Millisecond,
+			P99:     99 * time.Millisecond,
+			Max:     100 * time.Millisecond,
+		},
+		{Metric: "Write", Count: 1, Sources: 1, Min: time.Second, Avg: time.Second, P50: time.Second, P90: time.Second, P99: time.Second, Max: time.Second},
+	}, distributions)
+	suite.Empty(DistributeLatencies(nil))
+}
+
+func (suite *CollectorTestSuit) TestStageHeadrooms() {
+	suite.Equal(DefaultStageTimeout, StageTimeout(store.TestRun{}))
+	suite.Equal(time.Minute, StageTimeout(store.TestRun{Timeout: time.Minute}))
+
+	tc := TestCaseMetrics{StageMetrics: map[interface{}]DurationOfStage{
+		PVCBind:     {Max: 15 * time.Second},
+		PodCreation: {Max: 45 * time.Second},
+		PVCDeletion: {},
+	}}
+	headrooms := StageHeadrooms(tc, time.Minute)
+	suite.Equal([]StageHeadroom{
+		{Stage: "PodCreation", Max: 45 * time.Second, Timeout: time.Minute, Headroom: 15 * time.Second},
+		{Stage: "PVCBind", Max: 15 * time.Second, Timeout: time.Minute, Headroom: 45 * time.Second},
+	}, headrooms)
+	suite.Equal(75.0, headrooms[0].Used())
+}
+
+func (suite *CollectorTestSuit) TestFindOutliers() {
+	var pods []PodMetrics
+	var placements []store.EntityPlacement
+	for i := 0; i < 20; i++ {
+		entity := store.Entity{ID: int64(i + 1), Name: fmt.Sprintf("pod-%d", i), Type: store.Pod}
+		creation := 10*time.Second + time.Duration(i%5)*100*time.Millisecond
+		placement := store.EntityPlacement{EntityID: entity.ID, Node: fmt.Sprintf("worker-%d", i%3), Zone: "zone-a"}
+		if i >= 18 {
+			creation = time.Minute
+			placement = store.EntityPlacement{EntityID: entity.ID, Node: "worker-7", Zone: "zone-b"}
+		}
+		pods = append(pods, PodMetrics{Pod: entity, Metrics: map[PodStage]time.Duration{PodCreation: creation, PodDeletion: 0}})
+		placements = append(placements, placement)
+	}
+
+	outliers, causes := FindOutliers(pods, nil, placements)
+	suite.Len(outliers, 2)
+	suite.Equal(string(PodCreation), outliers[0].Stage)
+	suite.Equal(time.Minute, outliers[0].Value)
+	suite.Equal("worker-7", outliers[0].Node)
+	suite.Greater(outliers[0].ZScore, OutlierZScore)
+
+	suite.Len(causes, 2)
+	suite.Equal("all 2 outliers were on node worker-7 (2 of 20 entities)", causes[0].String())
+	suite.Equal("zone", causes[1].Attribute)
+	suite.Equal("zone-b", causes[1].Value)
+
+	// Too few samples to tell outliers from noise
+	outliers, causes = FindOutliers(pods[10:], nil, placements)
+	suite.Empty(outliers)
+	suite.Empty(causes)
+}
+
+func (suite *CollectorTestSuit) TestComponentLatencies() {
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	pvcs := []PVCMetrics{{Events: []store.Event{
+		{Type: store.PvcAdded, Timestamp: at(0)},
+		{Type: store.ComponentEvent, Timestamp: at(time.Second), Source: "persistentvolume-controller", Message: "ExternalProvisioning"},
+		{Type: store.ComponentEvent, Timestamp: at(9 * time.Second), Source: "csi-powerstore.dellemc.com_node1_1b2c", Message: "ProvisioningSucceeded"},
+		{Type: store.PvcBound, Timestamp: at(10 * time.Second)},
+	}}}
+	pods := []PodMetrics{{Events: []store.Event{
+		{Type: store.PodAdded, Timestamp: at(0)},
+		{Type: store.ComponentEvent, Timestamp: at(time.Second), Source: "default-scheduler", Message: "Scheduled"},
+		{Type: store.ComponentEvent, Timestamp: at(4 * time.Second), Source: "attachdetach-controller", Message: "SuccessfulAttachVolume"},
+		{Type: store.PodReady, Timestamp: at(6 * time.Second)},
+	}}}
+
+	latencies := getComponentLatencies(pvcs, pods)
+	suite.Len(latencies, 5)
+
+	// Waiting for external provisioner and binding after it are both spent in persistentvolume-controller
+	bind := latencies[:2]
+	suite.Equal(string(PVCBind), bind[0].Stage)
+	suite.Equal(ComponentProvisioner, bind[0].Component)
+	suite.Equal(8*time.Second, bind[0].Total)
+	suite.InDelta(80, bind[0].Share, 0.001)
+	suite.Equal(ComponentPVController, bind[1].Component)
+	suite.Equal(2*time.Second, bind[1].Total)
+	suite.Equal(2, bind[1].Events)
+
+	creation := latencies[2:]
+	suite.Equal(string(PodCreation), creation[0].Stage)
+	suite.Equal(ComponentAttacher, creation[0].Component)
+	suite.Equal(3*time.Second, creation[0].Total)
+	suite.Equal(ComponentKubelet, creation[1].Component)
+	suite.Equal(ComponentScheduler, creation[2].Component)
+}
+
+func (suite *CollectorTestSuit) TestSchedulingChurn() {
+	start := time.Now()
+	pods := []PodMetrics{
+		{Pod: store.Entity{Name: "sts-1"}, Events: []store.Event{
+			{Type: store.PodAdded, Timestamp: start},
+			{Type: store.PodRescheduled, Timestamp: start.Add(3 * time.Second), Message: "node1 -> node2"},
+		}},
+		{Pod: store.Entity{Name: "sts-0"}, Events: []store.Event{
+			{Type: store.PodPreempted, Timestamp: start.Add(time.Second)},
+		}},
+	}
+	churn := getSchedulingChurn(pods)
+	suite.Len(churn, 2)
+	suite.Equal("sts-0", churn[0].Pod)
+	suite.Equal(store.PodPreempted, churn[0].Type)
+	suite.Equal("node1 -> node2", churn[1].Message)
+
+	suite.Equal(ComponentSchedulingChurn, Component("default-scheduler", "Preempted"))
+	suite.Equal(ComponentSchedulingChurn, Component("sigs.k8s.io/descheduler", "Descheduled"))
+	suite.Equal(ComponentScheduler, Component("default-scheduler", "Scheduled"))
+}
+
+func (suite *CollectorTestSuit) TestDriverOutages() {
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	events := []store.Event{
+		{Type: store.DriverAvailable, Timestamp: at(0)},
+		{Type: store.DriverUnavailable, Timestamp: at(10 * time.Second), Message: "connection refused"},
+		{Type: store.DriverAvailable, Timestamp: at(25 * time.Second), Message: "unavailable for 15s"},
+		{Type: store.DriverUnavailable, Timestamp: at(40 * time.Second), Message: "timeout"},
+	}
+
+	outages := driverOutages("node-pod/driver", events, at(60*time.Second))
+	suite.Len(outages, 2)
+	suite.Equal(15*time.Second, outages[0].Duration())
+	suite.Equal("connection refused", outages[0].Message)
+	suite.False(outages[0].Open)
+	// Outage without recovery lasts until test case ended
+	suite.Equal(20*time.Second, outages[1].Duration())
+	suite.True(outages[1].Open)
+
+	pvc := []store.Event{{Type: store.PvcAdded, Timestamp: at(5 * time.Second)}, {Type: store.PvcBound, Timestamp: at(12 * time.Second)}}
+	suite.True(inProgressDuring(pvc, outages[0]))
+	suite.False(inProgressDuring(pvc, outages[1]))
+}
+
+func (suite *CollectorTestSuit) TestSnapshotReadiness() {
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	sr := &SnapshotReadiness{}
+	addSnapshotReadiness(sr, "snap-ready", []store.Event{
+		{Type: store.SnapshotAdded, Timestamp: at(0)},
+		{Type: store.SnapshotReady, Timestamp: at(4 * time.Second)},
+	}, at(time.Minute))
+	// Snapshot which became ready after the deadline is still censored
+	addSnapshotReadiness(sr, "snap-stuck", []store.Event{
+		{Type: store.SnapshotAdded, Timestamp: at(0)},
+		{Type: store.SnapshotReadyTimeout, Timestamp: at(30 * time.Second), Message: "failed to take snapshot"},
+		{Type: store.SnapshotReady, Timestamp: at(50 * time.Second)},
+	}, at(time.Minute))
+	addSnapshotReadiness(sr, "snap-pending", []store.Event{
+		{Type: store.SnapshotAdded, Timestamp: at(40 * time.Second)},
+	}, at(time.Minute))
+
+	suite.Equal([]time.Duration{4 * time.Second}, sr.Ready)
+	suite.Equal(3, sr.Samples())
+	suite.Equal(1, sr.TimedOut())
+	suite.Equal(4*time.Second, sr.Max())
+	suite.Equal(CensoredSnapshot{Name: "snap-stuck", Waited: 30 * time.Second, TimedOut: true, LastError: "failed to take snapshot"}, sr.Censored[0])
+	suite.Equal(CensoredSnapshot{Name: "snap-pending", Waited: 20 * time.Second}, sr.Censored[1])
+}
+
+func (suite *CollectorTestSuit) TestGetContention() {
+	start := time.Now()
+	testCase := func(from, to time.Duration, bind time.Duration) TestCaseMetrics {
+		return TestCaseMetrics{
+			TestCase: store.TestCase{StartTimestamp: start.Add(from), EndTimestamp: start.Add(to)},
+			PVCs:     []PVCMetrics{{Metrics: map[PVCStage]time.Duration{PVCBind: bind}}},
+		}
+	}
+	// Both classes run alone first, then together
+	fast := &MetricsCollection{
+		Run: store.TestRun{StorageClass: "fast"},
+		TestCasesMetrics: []TestCaseMetrics{
+			testCase(0, time.Minute, time.Second),
+			testCase(3*time.Minute, 4*time.Minute, 2*time.Second),
+		},
+	}
+	slow := &MetricsCollection{
+		Run: store.TestRun{StorageClass: "slow"},
+		TestCasesMetrics: []TestCaseMetrics{
+			testCase(time.Minute+time.Second, 2*time.Minute, time.Second),
+			testCase(3*time.Minute, 4*time.Minute, 4*time.Second),
+		},
+	}
+
+	suite.Nil(GetContention([]*MetricsCollection{fast}))
+
+	contention := GetContention([]*MetricsCollection{fast, slow})
+	suite.Len(contention.Classes, 2)
+	suite.Equal("slow", contention.Classes[0].StorageClass)
+	suite.Equal(1, contention.Classes[0].IsolatedCases)
+	suite.Equal(1, contention.Classes[0].SharedCases)
+	suite.Equal(4*time.Second, contention.Classes[0].SharedBind)
+	suite.InDelta(4, contention.Classes[0].Slowdown, 0.001)
+	suite.InDelta(2, contention.Classes[1].Slowdown, 0.001)
+	// Speeds 0.25 and 0.5: (0.75)^2 / (2 * 0.3125)
+	suite.InDelta(0.9, contention.FairnessIndex, 0.001)
+}
+
+func (suite *CollectorTestSuit) TestCustomStages() {
+	warmed, err := store.RegisterEventType("POD_CACHE_WARMED", store.LifecycleCategory, store.Pod)
+	suite.NoError(err)
+	again, err := store.RegisterEventType("POD_CACHE_WARMED", store.LifecycleCategory, store.Pod)
+	suite.NoError(err)
+	suite.Equal(warmed, again)
+	_, err = store.RegisterEventType("POD_CACHE_WARMED", store.WarningCategory, store.Pod)
+	suite.Error(err)
+
+	suite.Error(RegisterStage(string(PodCreation), store.PodAdded, warmed))
+	suite.Error(RegisterStage("CacheWarmup", store.PvcAdded, warmed))
+	suite.Error(RegisterStage("CacheWarmup", store.PodAdded, store.PodOOMKilled))
+	suite.NoError(RegisterStage("CacheWarmup", store.PodReady, warmed))
+	suite.True(isKnownStage("CacheWarmup"))
+
+	testCase := &store.TestCase{Name: "custom stages", StartTimestamp: time.Now(), RunID: 1}
+	suite.NoError(suite.db.SaveTestCase(testCase))
+	pod := &store.Entity{Name: "warm-pod", K8sUID: "warm-pod-uid", TcID: testCase.ID, Type: store.Pod}
+	suite.NoError(suite.db.SaveEntities([]*store.Entity{pod}))
+	start := time.Now()
+	suite.NoError(suite.db.SaveEvents([]*store.Event{
+		{Name: "warm-pod-added", TcID: testCase.ID, EntityID: pod.ID, Type: store.PodAdded, Timestamp: start},
+		{Name: "warm-pod-ready", TcID: testCase.ID, EntityID: pod.ID, Type: store.PodReady, Timestamp: start.Add(time.Second)},
+		{Name: "warm-pod-warmed", TcID: testCase.ID, EntityID: pod.ID, Type: warmed, Timestamp: start.Add(4 * time.Second)},
+	}))
+
+	metrics := suite.collector.CollectTestCase(testCase)
+	suite.Len(metrics.Pods, 1)
+	suite.Equal(3*time.Second, metrics.Pods[0].Metrics[PodStage("CacheWarmup")])
+	suite.Equal(3*time.Second, metrics.StageMetrics[PodStage("CacheWarmup")].Avg)
+}
+
+func (suite *CollectorTestSuit) TestSummarizeExpansions() {
+	tcs, err := suite.db.GetTestCases(store.Conditions{"name": "test case 1"}, "", 1)
+	suite.NoError(err)
+	suite.NoError(suite.db.SaveExpansionOutcomes([]*store.ExpansionOutcome{
+		{TcID: tcs[0].ID, PVC: "pvc1", Outcome: store.ExpansionCompleted, Controller: 2 * time.Second, Node: 4 * time.Second},
+		{TcID: tcs[0].ID, PVC: "pvc2", Outcome: store.ExpansionCompleted, Controller: 4 * time.Second, Node: 6 * time.Second},
+		{TcID: tcs[0].ID, PVC: "pvc3", Outcome: store.ExpansionNodePending, Controller: 3 * time.Second, Message: "waiting for user to (re-)start a pod"},
+		{TcID: tcs[0].ID, PVC: "pvc4", Outcome: store.ExpansionInfeasible, Message: "volume can't be expanded beyond 1Ti"},
+	}))
+
+	metrics := suite.collector.CollectTestCase(&tcs[0])
+	suite.Len(metrics.ExpansionOutcomes, 4)
+
+	summaries := SummarizeExpansions(metrics.ExpansionOutcomes)
+	suite.Equal([]ExpansionSummary{
+		{Outcome: store.ExpansionCompleted, Count: 2, AvgController: 3 * time.Second, AvgNode: 5 * time.Second},
+		{Outcome: store.ExpansionNodePending, Count: 1, AvgController: 3 * time.Second},
+		{Outcome: store.ExpansionInfeasible, Count: 1},
+	}, summaries)
+}
+
+func (suite *CollectorTestSuit) TestGetCapabilityChecks() {
+	suite.Nil(GetCapabilityChecks(store.TestRun{Name: "not probed"}, nil))
+
+	run := store.TestRun{
+		Name:         "probed",
+		Capabilities: `{"driver":"csi-unity.dellemc.com","registered":true,"attachRequired":false,"volumeLifecycleModes":["Persistent"],"fsGroupPolicy":"File","allowVolumeExpansion":true,"volumeBindingMode":"Immediate"}`,
+	}
+	metrics := []TestCaseMetrics{
+		{EventsPerSecond: map[store.EventTypeEnum]map[int64]int{
+			store.PvcBound:         {1: 2},
+			store.PodReady:         {2: 2},
+			store.PvcAttachStarted: {1: 1, 2: 1},
+		}},
+		{
+			EventsPerSecond:   map[store.EventTypeEnum]map[int64]int{store.EphemeralPublishStarted: {5: 1}},
+			ExpansionOutcomes: []store.ExpansionOutcome{{PVC: "pvc1", Outcome: store.ExpansionCompleted}, {PVC: "pvc2", Outcome: store.ExpansionInfeasible}},
+		},
+	}
+	suite.Equal([]CapabilityCheck{
+		{Name: "driver", Declared: "CSIDriver csi-unity.dellemc.com"},
+		{Name: "attachRequired", Declared: "false", Observed: "VolumeAttachments created", Mismatch: true},
+		{Name: "podInfoOnMount", Declared: "false"},
+		{Name: "volumeLifecycleModes", Declared: "Persistent", Observed: "Persistent, Ephemeral", Mismatch: true},
+		{Name: "fsGroupPolicy", Declared: "File"},
+		{Name: "allowVolumeExpansion", Declared: "true", Observed: "1/2 expansions completed"},
+		{Name: "volumeBindingMode", Declared: "Immediate"},
+		{Name: "snapshotClasses", Declared: "none"},
+	}, GetCapabilityChecks(run, metrics))
+
+	// VolumeAttachments aren't watched in restricted runs, so attachRequired can't be checked
+	run.NotObservable = "VolumeAttachments"
+	checks := GetCapabilityChecks(run, metrics)
+	suite.Equal(CapabilityCheck{Name: "attachRequired", Declared: "false"}, checks[1])
+
+	// IPv6 is validated by connections node plugins made to controller
+	run.Capabilities = `{"driver":"csi-unity.dellemc.com","ipFamilies":["IPv4","IPv6"]}`
+	metrics = []TestCaseMetrics{{LatencySamples: []store.LatencySample{
+		{Metric: store.IPv6ConnectMetric, Source: "node1 -> controller/[fd00::1]:9808"},
+		{Metric: store.IPv6ConnectFailedMetric, Source: "node2 -> controller/[fd00::1]:9808"},
+		{Metric: "Pod ready (IPv6)", Source: "ipv6-volume-1"},
+	}}}
+	checks = GetCapabilityChecks(run, metrics)
+	suite.Equal(CapabilityCheck{Name: "ipFamilies", Declared: "IPv4, IPv6", Observed: "1/2 node-controller connections over IPv6", Mismatch: true}, checks[len(checks)-1])
+
+	// Driver profile expectations are checked as a whole
+	run.Capabilities = `{"driver":"csi-unity.dellemc.com","attachRequired":true,"expected":{"profile":"unity","driver":"csi-unity.dellemc.com","attachRequired":false}}`
+	checks = GetCapabilityChecks(run, nil)
+	suite.Equal(CapabilityCheck{Name: "driverProfile", Declared: "unity", Observed: "unexpected attachRequired", Mismatch: true}, checks[len(checks)-1])
+}
+
+func (suite *CollectorTestSuit) TestMergeTimeline() {
+	start := time.Now()
+	tc := &store.TestCase{Name: "timeline", StartTimestamp: start, RunID: 1}
+	suite.NoError(suite.db.SaveTestCase(tc))
+	// Cluster clock is 2s ahead
+	suite.NoError(suite.db.SaveClockSkew(tc, 2*time.Second))
+	pvc := &store.Entity{Name: "pvc-timeline", K8sUID: "uid-timeline", TcID: tc.ID, Type: store.Pvc}
+	suite.NoError(suite.db.SaveEntities([]*store.Entity{pvc}))
+	suite.NoError(suite.db.SaveEvents([]*store.Event{
+		{Name: "timeline-added", TcID: tc.ID, EntityID: pvc.ID, Type: store.PvcAdded, Timestamp: start},
+		{Name: "timeline-provisioning", TcID: tc.ID, EntityID: pvc.ID, Type: store.ComponentEvent, Timestamp: start.Add(time.Second),
+			Message: "Provisioning", Source: "csi-provisioner"},
+		{Name: "timeline-bound", TcID: tc.ID, EntityID: pvc.ID, Type: store.PvcBound, Timestamp: start.Add(4 * time.Second)},
+	}))
+	suite.NoError(suite.db.SaveResourceUsage([]*store.ResourceUsage{
+		{TcID: tc.ID, Timestamp: start, PodName: "controller", ContainerName: "provisioner", CPU: 10, Mem: 40},
+		{TcID: tc.ID, Timestamp: start.Add(5 * time.Second), PodName: "controller", ContainerName: "provisioner", CPU: 11, Mem: 41},
+		{TcID: tc.ID, Timestamp: start.Add(10 * time.Second), PodName: "controller", ContainerName: "provisioner", CPU: 30, Mem: 41},
+	}))
+	suite.NoError(suite.db.SaveFaultWindows([]*store.FaultWindow{
+		{TcID: tc.ID, Step: 1, Node: "node1", Start: start.Add(6 * time.Second), End: start.Add(8 * time.Second)},
+	}))
+	_, err := suite.db.SaveAuditEntries([]*store.AuditEntry{
+		{TcID: tc.ID, EntityID: pvc.ID, AuditID: "timeline", Timestamp: start.Add(5 * time.Second), Verb: "create",
+			Resource: "persistentvolumes", Name: "pvc-uid-timeline", User: "csi-provisioner", Code: 201},
+	})
+	suite.NoError(err)
+
+	timeline, err := MergeTimeline(suite.db, tc)
+	suite.NoError(err)
+	var got []string
+	for _, e := range timeline {
+		got = append(got, fmt.Sprintf("%s %s %s %s", e.Timestamp.Sub(start), e.Source, e.Object, e.Message))
+	}
+	suite.Equal([]string{
+		"0s observer PVC/pvc-timeline PVC_ADDED",
+		"0s metrics Pod/controller/provisioner cpu 10m, memory 40Mi",
+		"1s kubernetes PVC/pvc-timeline Provisioning from csi-provisioner",
+		"3s audit persistentvolumes/pvc-uid-timeline create by csi-provisioner (201)",
+		"4s observer PVC/pvc-timeline PVC_BOUND",
+		"6s fault Node/node1 fault of step 1 injected",
+		"8s fault Node/node1 fault of step 1 cleared",
+		"10s metrics Pod/controller/provisioner cpu 30m, memory 41Mi",
+	}, got)
+
+	metrics := suite.collector.CollectTestCase(tc)
+	suite.Len(metrics.Timeline, 8)
+	suite.True(metrics.Timeline[3].Corrected)
+}
+
+func (suite *CollectorTestSuit) TestSeriesBuckets() {
+	defer func(max int) { MaxSeriesPoints = max }(MaxSeriesPoints)
+	MaxSeriesPoints = 10
+
+	start := time.Now()
+	tc := &store.TestCase{Name: "series", StartTimestamp: start, EndTimestamp: start.Add(100 * time.Second), RunID: 1}
+	suite.NoError(suite.db.SaveTestCase(tc))
+	var entities []*store.NumberEntities
+	var usage []*store.ResourceUsage
+	for i := 0; i < 1000; i++ {
+		ts := start.Add(time.Duration(i) * 100 * time.Millisecond)
+		entities = append(entities, &store.NumberEntities{TcID: tc.ID, Timestamp: ts, PodsReady: i % 10, PvcBound: i})
+		for _, container := range []string{"provisioner", "attacher"} {
+			usage = append(usage, &store.ResourceUsage{TcID: tc.ID, Timestamp: ts, PodName: "controller", ContainerName: container, CPU: int64(i % 10), Mem: 10})
+		}
+	}
+	entities[555].PodsReady = 500
+	usage[777].Mem = 691
+	suite.NoError(suite.db.SaveNumberEntities(entities))
+	suite.NoError(suite.db.SaveResourceUsage(usage))
+
+	metrics := suite.collector.CollectTestCase(tc)
+	suite.Less(len(metrics.EntityNumberMetrics), 100)
+	suite.Equal(0, metrics.EntityNumberMetrics[0].PvcBound)
+	suite.Equal(999, metrics.EntityNumberMetrics[len(metrics.EntityNumberMetrics)-1].PvcBound)
+	var peak int
+	for i, e := range metrics.EntityNumberMetrics {
+		peak = max(peak, e.PodsReady)
+		if i > 0 {
+			suite.False(e.Timestamp.Before(metrics.EntityNumberMetrics[i-1].Timestamp))
+		}
+	}
+	suite.Equal(500, peak)
+
+	suite.Less(len(metrics.ResourceUsageMetrics), 200)
+	pod, mem := peakPodUsage(metrics.ResourceUsageMetrics, "controller", func(r store.ResourceUsage) int64 { return r.Mem })
+	suite.Equal("controller", pod)
+	suite.Equal(int64(701), mem)
+	containers := make(map[string]int)
+	for i, r := range metrics.ResourceUsageMetrics {
+		containers[r.ContainerName]++
+		if i > 0 {
+			suite.False(r.Timestamp.Before(metrics.ResourceUsageMetrics[i-1].Timestamp))
+		}
+	}
+	suite.Len(containers, 2)
+}
+
+func (suite *CollectorTestSuit) TestDelayedBinding() {
+	start := time.Now()
+	tc := &store.TestCase{Name: "delayed binding", StartTimestamp: start, RunID: 1}
+	suite.NoError(suite.db.SaveTestCase(tc))
+	immediate := &store.Entity{Name: "pvc-immediate", K8sUID: "uid-immediate", TcID: tc.ID, Type: store.Pvc}
+	delayed := &store.Entity{Name: "pvc-delayed", K8sUID: "uid-delayed", TcID: tc.ID, Type: store.Pvc}
+	suite.NoError(suite.db.SaveEntities([]*store.Entity{immediate, delayed}))
+	suite.NoError(suite.db.SaveEvents([]*store.Event{
+		{Name: "immediate-added", TcID: tc.ID, EntityID: immediate.ID, Type: store.PvcAdded, Timestamp: start},
+		{Name: "immediate-bound", TcID: tc.ID, EntityID: immediate.ID, Type: store.PvcBound, Timestamp: start.Add(2 * time.Second)},
+		{Name: "delayed-added", TcID: tc.ID, EntityID: delayed.ID, Type: store.PvcAdded, Timestamp: start},
+		{Name: "delayed-selected", TcID: tc.ID, EntityID: delayed.ID, Type: store.PvcNodeSelected, Timestamp: start.Add(30 * time.Second),
+			Message: "node1"},
+		{Name: "delayed-bound", TcID: tc.ID, EntityID: delayed.ID, Type: store.PvcBound, Timestamp: start.Add(34 * time.Second)},
+	}))
+
+	metrics := suite.collector.CollectTestCase(tc)
+	suite.Len(metrics.PVCs, 2)
+	for _, p := range metrics.PVCs {
+		if p.PVC.Name == delayed.Name {
+			suite.True(p.DelayedBinding)
+			suite.Equal(30*time.Second, p.Metrics[PVCConsumerWait])
+			suite.Equal(4*time.Second, p.Metrics[PVCBind])
+		} else {
+			suite.False(p.DelayedBinding)
+			suite.NotContains(p.Metrics, PVCConsumerWait)
+			suite.Equal(2*time.Second, p.Metrics[PVCBind])
+		}
+	}
+	suite.Equal(4*time.Second, metrics.StageMetrics[PVCBind].Max)
+	suite.Equal(30*time.Second, metrics.StageMetrics[PVCConsumerWait].Avg)
+	suite.True(isKnownStage(string(PVCConsumerWait)))
 }
 
 func TestCollectorTestSuite(t *testing.T) {
@@ -0,0 +1,83 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// LatencyDistribution describes distribution of latency samples of a single metric
+type LatencyDistribution struct {
+	Metric  string
+	Count   int
+	Sources int
+	Min     time.Duration
+	Avg     time.Duration
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+// DistributeLatencies groups samples by metric and calculates their distributions, metrics keep order of their first sample
+func DistributeLatencies(samples []store.LatencySample) []LatencyDistribution {
+	var metrics []string
+	values := make(map[string][]time.Duration)
+	sources := make(map[string]map[string]struct{})
+	for _, s := range samples {
+		if _, ok := values[s.Metric]; !ok {
+			metrics = append(metrics, s.Metric)
+			sources[s.Metric] = make(map[string]struct{})
+		}
+		values[s.Metric] = append(values[s.Metric], s.Value)
+		sources[s.Metric][s.Source] = struct{}{}
+	}
+
+	distributions := make([]LatencyDistribution, 0, len(metrics))
+	for _, metric := range metrics {
+		v := values[metric]
+		sort.Slice(v, func(i, j int) bool { return v[i] < v[j] })
+		distributions = append(distributions, LatencyDistribution{
+			Metric:  metric,
+			Count:   len(v),
+			Sources: len(sources[metric]),
+			Min:     v[0],
+			Avg:     findAvg(v),
+			P50:     percentile(v, 50),
+			P90:     percentile(v, 90),
+			P99:     percentile(v, 99),
+			Max:     v[len(v)-1],
+		})
+	}
+	return distributions
+}
+
+// percentile returns nearest-rank percentile of sorted values
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,118 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// DriverOutage is a window driver health endpoint didn't answer in
+type DriverOutage struct {
+	Endpoint string
+	Start    time.Time
+	End      time.Time
+	Message  string
+	// Open marks outage still in progress when test case ended
+	Open bool
+	// Overlapping is number of PVCs and pods which were in progress during the outage
+	Overlapping int
+}
+
+// Duration returns length of the outage
+func (o DriverOutage) Duration() time.Duration {
+	return o.End.Sub(o.Start)
+}
+
+// getDriverOutages pairs driver unavailability events with recoveries, outages are sorted by start
+func (mc *MetricsCollector) getDriverOutages(tc *store.TestCase, pvcs []PVCMetrics, pods []PodMetrics) ([]DriverOutage, error) {
+	entitiesWithEvents, err := mc.db.GetEntitiesWithEventsByTestCaseAndEntityType(tc, store.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var outages []DriverOutage
+	for endpoint, events := range entitiesWithEvents {
+		outages = append(outages, driverOutages(endpoint.Name, events, tc.EndTimestamp)...)
+	}
+	for i := range outages {
+		for _, p := range pvcs {
+			if inProgressDuring(p.Events, outages[i]) {
+				outages[i].Overlapping++
+			}
+		}
+		for _, p := range pods {
+			if inProgressDuring(p.Events, outages[i]) {
+				outages[i].Overlapping++
+			}
+		}
+	}
+	sort.SliceStable(outages, func(i, j int) bool {
+		return outages[i].Start.Before(outages[j].Start)
+	})
+	return outages, nil
+}
+
+// driverOutages returns outages of a single endpoint, outage without recovery lasts until end
+func driverOutages(endpoint string, events []store.Event, end time.Time) []DriverOutage {
+	sorted := make([]store.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var outages []DriverOutage
+	var current *DriverOutage
+	for _, e := range sorted {
+		switch {
+		case e.Type == store.DriverUnavailable && current == nil:
+			current = &DriverOutage{Endpoint: endpoint, Start: e.Timestamp, Message: e.Message}
+		case e.Type == store.DriverAvailable && current != nil:
+			current.End = e.Timestamp
+			outages = append(outages, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		current.End = end
+		current.Open = true
+		if end.Before(current.Start) {
+			current.End = current.Start
+		}
+		outages = append(outages, *current)
+	}
+	return outages
+}
+
+// inProgressDuring checks whether timeline of the entity intersects the outage
+func inProgressDuring(events []store.Event, o DriverOutage) bool {
+	if len(events) == 0 {
+		return false
+	}
+	first, last := events[0].Timestamp, events[0].Timestamp
+	for _, e := range events {
+		if e.Timestamp.Before(first) {
+			first = e.Timestamp
+		}
+		if e.Timestamp.After(last) {
+			last = e.Timestamp
+		}
+	}
+	return !first.After(o.End) && !last.Before(o.Start)
+}
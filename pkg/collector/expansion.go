@@ -0,0 +1,74 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// ExpansionSummary is number of volume expansions which ended the same way and average durations of their phases
+type ExpansionSummary struct {
+	Outcome store.ExpansionOutcomeEnum
+	Count   int
+	// AvgController and AvgNode are averaged over expansions which finished the phase
+	AvgController time.Duration
+	AvgNode       time.Duration
+}
+
+// expansionOutcomesOrder lists outcomes from successful to the ones which got least far
+var expansionOutcomesOrder = []store.ExpansionOutcomeEnum{
+	store.ExpansionCompleted,
+	store.ExpansionNodePending,
+	store.ExpansionControllerOnly,
+	store.ExpansionControllerPending,
+	store.ExpansionInfeasible,
+}
+
+// SummarizeExpansions groups expansion outcomes, so failures of controller and node phases are told apart
+func SummarizeExpansions(outcomes []store.ExpansionOutcome) []ExpansionSummary {
+	var summaries []ExpansionSummary
+	for _, outcome := range expansionOutcomesOrder {
+		var controller, node []time.Duration
+		count := 0
+		for _, eo := range outcomes {
+			if eo.Outcome != outcome {
+				continue
+			}
+			count++
+			if eo.Controller > 0 {
+				controller = append(controller, eo.Controller)
+			}
+			if eo.Node > 0 {
+				node = append(node, eo.Node)
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		summary := ExpansionSummary{Outcome: outcome, Count: count}
+		if len(controller) != 0 {
+			summary.AvgController = findAvg(controller)
+		}
+		if len(node) != 0 {
+			summary.AvgNode = findAvg(node)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
@@ -0,0 +1,135 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package collector
+
+import (
+	"sort"
+	"time"
+)
+
+// ClassContention compares latencies of storage class while suites of other classes were running and while they weren't
+type ClassContention struct {
+	StorageClass string
+	// IsolatedCases and SharedCases count test cases, that didn't overlap and overlapped with test cases of other classes
+	IsolatedCases int
+	SharedCases   int
+	IsolatedBind  time.Duration
+	SharedBind    time.Duration
+	IsolatedReady time.Duration
+	SharedReady   time.Duration
+	// Slowdown is ratio of shared to isolated average PVC bind time, pod ready time is used if no PVCs were bound, 0 if unknown
+	Slowdown float64
+}
+
+// Contention is noisy-neighbor effect of storage classes sharing a driver
+type Contention struct {
+	Classes []ClassContention
+	// FairnessIndex is Jain's index of isolated to shared speed ratios, 1 if all classes slowed down equally
+	FairnessIndex float64
+}
+
+type latencySamples struct {
+	bind, ready []time.Duration
+}
+
+func (ls *latencySamples) add(tc TestCaseMetrics) {
+	for _, p := range tc.PVCs {
+		if d := p.Metrics[PVCBind]; d > 0 {
+			ls.bind = append(ls.bind, d)
+		}
+	}
+	for _, p := range tc.Pods {
+		if d := p.Metrics[PodCreation]; d > 0 {
+			ls.ready = append(ls.ready, d)
+		}
+	}
+}
+
+func avgOrZero(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	return findAvg(samples)
+}
+
+func overlaps(tc TestCaseMetrics, other TestCaseMetrics) bool {
+	return tc.TestCase.StartTimestamp.Before(other.TestCase.EndTimestamp) &&
+		other.TestCase.StartTimestamp.Before(tc.TestCase.EndTimestamp)
+}
+
+// GetContention splits test cases of every storage class by whether they overlapped with test cases of other classes
+// and compares latencies of both groups, nil is returned for runs of a single storage class
+func GetContention(mcs []*MetricsCollection) *Contention {
+	if len(mcs) < 2 {
+		return nil
+	}
+
+	contention := &Contention{}
+	var speeds []float64
+	for i, mc := range mcs {
+		var isolated, shared latencySamples
+		cc := ClassContention{StorageClass: mc.Run.StorageClass}
+		for _, tc := range mc.TestCasesMetrics {
+			overlapped := false
+			for j, other := range mcs {
+				if i == j {
+					continue
+				}
+				for _, otherTc := range other.TestCasesMetrics {
+					if overlaps(tc, otherTc) {
+						overlapped = true
+						break
+					}
+				}
+				if overlapped {
+					break
+				}
+			}
+			if overlapped {
+				cc.SharedCases++
+				shared.add(tc)
+			} else {
+				cc.IsolatedCases++
+				isolated.add(tc)
+			}
+		}
+
+		cc.IsolatedBind, cc.SharedBind = avgOrZero(isolated.bind), avgOrZero(shared.bind)
+		cc.IsolatedReady, cc.SharedReady = avgOrZero(isolated.ready), avgOrZero(shared.ready)
+		if cc.IsolatedBind > 0 && cc.SharedBind > 0 {
+			cc.Slowdown = float64(cc.SharedBind) / float64(cc.IsolatedBind)
+		} else if cc.IsolatedReady > 0 && cc.SharedReady > 0 {
+			cc.Slowdown = float64(cc.SharedReady) / float64(cc.IsolatedReady)
+		}
+		if cc.Slowdown > 0 {
+			speeds = append(speeds, 1/cc.Slowdown)
+		}
+		contention.Classes = append(contention.Classes, cc)
+	}
+	sort.SliceStable(contention.Classes, func(i, j int) bool {
+		return contention.Classes[i].Slowdown > contention.Classes[j].Slowdown
+	})
+
+	if len(speeds) > 1 {
+		var sum, squares float64
+		for _, s := range speeds {
+			sum += s
+			squares += s * s
+		}
+		contention.FairnessIndex = sum * sum / (float64(len(speeds)) * squares)
+	}
+	return contention
+}
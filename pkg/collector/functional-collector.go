@@ -65,7 +65,7 @@ func (mc *MetricsCollector) CollectFunctionalMetrics() (*MetricsCollection, erro
 	if bar != nil {
 		bar.Finish()
 	}
-	mc.metricsCache[""] = &MetricsCollection{testRun, testCasesMetrics}
+	mc.metricsCache[""] = &MetricsCollection{testRun, testCasesMetrics, nil, nil}
 
 	return mc.metricsCache[""], nil
 }
@@ -0,0 +1,75 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// DefaultStageTimeout is the timeout resource clients wait for stages with if run didn't set one
+const DefaultStageTimeout = 1800 * time.Second
+
+// StageHeadroom shows how close the slowest operation of a stage came to the timeout
+type StageHeadroom struct {
+	Stage    string
+	Max      time.Duration
+	Timeout  time.Duration
+	Headroom time.Duration
+}
+
+// Used returns percentage of timeout the slowest operation took
+func (h StageHeadroom) Used() float64 {
+	if h.Timeout <= 0 {
+		return 0
+	}
+	return float64(h.Max) * 100 / float64(h.Timeout)
+}
+
+// StageTimeout returns timeout stages of the run were waited for with
+func StageTimeout(tr store.TestRun) time.Duration {
+	if tr.Timeout <= 0 {
+		return DefaultStageTimeout
+	}
+	return tr.Timeout
+}
+
+// StageHeadrooms returns headroom of every measured stage of test case, stages closest to the timeout go first
+func StageHeadrooms(tc TestCaseMetrics, timeout time.Duration) []StageHeadroom {
+	var headrooms []StageHeadroom
+	for stage, metrics := range tc.StageMetrics {
+		if metrics.Max <= 0 {
+			continue
+		}
+		headrooms = append(headrooms, StageHeadroom{
+			Stage:    fmt.Sprint(stage),
+			Max:      metrics.Max,
+			Timeout:  timeout,
+			Headroom: timeout - metrics.Max,
+		})
+	}
+	sort.Slice(headrooms, func(i, j int) bool {
+		if headrooms[i].Max != headrooms[j].Max {
+			return headrooms[i].Max > headrooms[j].Max
+		}
+		return headrooms[i].Stage < headrooms[j].Stage
+	})
+	return headrooms
+}
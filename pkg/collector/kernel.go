@@ -0,0 +1,53 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// NodeWarning is a kernel IO error logged on node while test case was running
+type NodeWarning struct {
+	Node      string
+	Timestamp time.Time
+	Message   string
+}
+
+// getNodeWarnings returns kernel IO errors and other warning events of all nodes sorted by time they were logged at
+func (mc *MetricsCollector) getNodeWarnings(tc *store.TestCase) ([]NodeWarning, error) {
+	entitiesWithEvents, err := mc.db.GetEntitiesWithEventsByTestCaseAndEntityType(tc, store.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []NodeWarning
+	for node, events := range entitiesWithEvents {
+		for _, e := range events {
+			if info, ok := store.GetEventType(e.Type); !ok || info.Category != store.WarningCategory {
+				continue
+			}
+			warnings = append(warnings, NodeWarning{Node: node.Name, Timestamp: e.Timestamp, Message: e.Message})
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Timestamp.Before(warnings[j].Timestamp)
+	})
+	return warnings, nil
+}
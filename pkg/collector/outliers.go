@@ -0,0 +1,208 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+var (
+	// OutlierMinSamples is the least number of entities stage must be measured for to look for outliers
+	OutlierMinSamples = 10
+	// OutlierIQRFactor is how many interquartile ranges above the third quartile latency must be to be an outlier
+	OutlierIQRFactor = 1.5
+	// OutlierZScore is how many standard deviations above the mean latency must also be to be an outlier
+	OutlierZScore = 2.0
+	// OutlierCauseShare is the least share of outliers placed on the same node, zone or backend to suggest it as a cause
+	OutlierCauseShare = 0.8
+)
+
+// EntityOutlier is entity whose stage took statistically longer than the same stage of other entities
+type EntityOutlier struct {
+	Entity  string
+	Stage   string
+	Value   time.Duration
+	Median  time.Duration
+	ZScore  float64
+	Node    string
+	Zone    string
+	Backend string
+}
+
+// OutlierCause is node, zone or backend most outliers of test case were placed on, a likely cause of their latency
+type OutlierCause struct {
+	// Attribute is node, zone or backend
+	Attribute string
+	Value     string
+	// Outliers is number of outlying entities placed there out of TotalOutliers
+	Outliers      int
+	TotalOutliers int
+	// Entities is number of measured entities placed there out of TotalEntities
+	Entities      int
+	TotalEntities int
+}
+
+// String describes cause, ex. all 3 outliers were on node worker-7 (4 of 40 entities)
+func (oc OutlierCause) String() string {
+	share := fmt.Sprintf("%d of %d outliers were", oc.Outliers, oc.TotalOutliers)
+	if oc.Outliers == oc.TotalOutliers {
+		share = fmt.Sprintf("all %d outliers were", oc.Outliers)
+	}
+	return fmt.Sprintf("%s on %s %s (%d of %d entities)", share, oc.Attribute, oc.Value, oc.Entities, oc.TotalEntities)
+}
+
+// stageSample is latency of a stage of a single entity
+type stageSample struct {
+	entity store.Entity
+	value  time.Duration
+}
+
+// FindOutliers returns entities whose stage latencies are outliers by both interquartile range and z-score,
+// and nodes, zones or backends most of them share
+func FindOutliers(pods []PodMetrics, pvcs []PVCMetrics, placements []store.EntityPlacement) ([]EntityOutlier, []OutlierCause) {
+	stages := make(map[string][]stageSample)
+	for _, pm := range pods {
+		for stage, d := range pm.Metrics {
+			if d > 0 {
+				stages[string(stage)] = append(stages[string(stage)], stageSample{pm.Pod, d})
+			}
+		}
+	}
+	for _, pm := range pvcs {
+		for stage, d := range pm.Metrics {
+			if d > 0 {
+				stages[string(stage)] = append(stages[string(stage)], stageSample{pm.PVC, d})
+			}
+		}
+	}
+	placed := make(map[int64]store.EntityPlacement)
+	for _, p := range placements {
+		placed[p.EntityID] = p
+	}
+
+	var outliers []EntityOutlier
+	measured := make(map[int64]bool)
+	outlying := make(map[int64]bool)
+	for stage, samples := range stages {
+		if len(samples) < OutlierMinSamples {
+			continue
+		}
+		sorted := make([]time.Duration, len(samples))
+		var sum float64
+		for i, s := range samples {
+			sorted[i] = s.value
+			sum += float64(s.value)
+			measured[s.entity.ID] = true
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		mean := sum / float64(len(sorted))
+		var variance float64
+		for _, v := range sorted {
+			variance += (float64(v) - mean) * (float64(v) - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(sorted)))
+		if stddev == 0 {
+			continue
+		}
+		q1, q3 := percentile(sorted, 25), percentile(sorted, 75)
+		fence := float64(q3) + OutlierIQRFactor*float64(q3-q1)
+		for _, s := range samples {
+			z := (float64(s.value) - mean) / stddev
+			if float64(s.value) <= fence || z < OutlierZScore {
+				continue
+			}
+			p := placed[s.entity.ID]
+			outlying[s.entity.ID] = true
+			outliers = append(outliers, EntityOutlier{
+				Entity:  s.entity.Name,
+				Stage:   stage,
+				Value:   s.value,
+				Median:  percentile(sorted, 50),
+				ZScore:  z,
+				Node:    p.Node,
+				Zone:    p.Zone,
+				Backend: p.Backend,
+			})
+		}
+	}
+	sort.Slice(outliers, func(i, j int) bool {
+		if outliers[i].ZScore != outliers[j].ZScore {
+			return outliers[i].ZScore > outliers[j].ZScore
+		}
+		return outliers[i].Entity < outliers[j].Entity
+	})
+
+	var causes []OutlierCause
+	for _, attr := range []struct {
+		name  string
+		value func(store.EntityPlacement) string
+	}{
+		{"node", func(p store.EntityPlacement) string { return p.Node }},
+		{"zone", func(p store.EntityPlacement) string { return p.Zone }},
+		{"backend", func(p store.EntityPlacement) string { return p.Backend }},
+	} {
+		if cause, ok := outlierCause(attr.name, attr.value, placed, measured, outlying); ok {
+			causes = append(causes, cause)
+		}
+	}
+	return outliers, causes
+}
+
+// outlierCause returns value of placement attribute most outlying entities share, if they share one and
+// measured entities were spread over more than one value
+func outlierCause(attribute string, value func(store.EntityPlacement) string, placed map[int64]store.EntityPlacement,
+	measured, outlying map[int64]bool,
+) (OutlierCause, bool) {
+	entities := make(map[string]int)
+	totalEntities := 0
+	for id := range measured {
+		if v := value(placed[id]); v != "" {
+			entities[v]++
+			totalEntities++
+		}
+	}
+	outliers := make(map[string]int)
+	totalOutliers := 0
+	for id := range outlying {
+		if v := value(placed[id]); v != "" {
+			outliers[v]++
+			totalOutliers++
+		}
+	}
+	if len(entities) < 2 || totalOutliers < 2 {
+		return OutlierCause{}, false
+	}
+
+	cause := OutlierCause{Attribute: attribute, TotalOutliers: totalOutliers, TotalEntities: totalEntities}
+	for v, n := range outliers {
+		if n > cause.Outliers || (n == cause.Outliers && v < cause.Value) {
+			cause.Value, cause.Outliers = v, n
+		}
+	}
+	cause.Entities = entities[cause.Value]
+	// Value holding most entities also holds most outliers by chance, so share of outliers must exceed share of entities
+	if float64(cause.Outliers) < OutlierCauseShare*float64(totalOutliers) ||
+		float64(cause.Outliers)/float64(totalOutliers) <= float64(cause.Entities)/float64(totalEntities) {
+		return OutlierCause{}, false
+	}
+	return cause, true
+}
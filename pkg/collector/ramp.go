@@ -0,0 +1,81 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// RampStageMetrics contains metrics of volumes created during a single stage of ramp profile
+type RampStageMetrics struct {
+	Stage store.RampStage
+	// Volumes is number of PVCs which were added during the stage
+	Volumes int
+	PVCBind DurationOfStage
+}
+
+// getRampMetrics splits PVCs of test case by stages of ramp profile, based on the time PVC was added
+func (mc *MetricsCollector) getRampMetrics(tc *store.TestCase) ([]RampStageMetrics, error) {
+	stages, err := mc.db.GetRampStages(store.Conditions{"tc_id": tc.ID}, "start_timestamp", 0)
+	if err != nil || len(stages) == 0 {
+		return nil, err
+	}
+
+	entitiesWithEvents, err := mc.db.GetEntitiesWithEventsByTestCaseAndEntityType(tc, store.Pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	bindTimes := make([][]time.Duration, len(stages))
+	for _, events := range entitiesWithEvents {
+		timestamps := make(map[store.EventTypeEnum]time.Time)
+		for _, e := range events {
+			timestamps[e.Type] = e.Timestamp
+		}
+		added, ok := timestamps[store.PvcAdded]
+		if !ok {
+			continue
+		}
+		bound, ok := timestamps[store.PvcBound]
+		if !ok {
+			continue
+		}
+		for i, stage := range stages {
+			if !added.Before(stage.StartTimestamp) && !added.After(stage.EndTimestamp) {
+				if selected, ok := timestamps[store.PvcNodeSelected]; ok {
+					bindTimes[i] = append(bindTimes[i], bound.Sub(selected))
+				} else {
+					bindTimes[i] = append(bindTimes[i], bound.Sub(added))
+				}
+				break
+			}
+		}
+	}
+
+	var rampMetrics []RampStageMetrics
+	for i, stage := range stages {
+		m := RampStageMetrics{Stage: stage, Volumes: len(bindTimes[i])}
+		if len(bindTimes[i]) != 0 {
+			min, max := findMaxAndMin(bindTimes[i])
+			m.PVCBind = DurationOfStage{min, max, findAvg(bindTimes[i])}
+		}
+		rampMetrics = append(rampMetrics, m)
+	}
+	return rampMetrics, nil
+}
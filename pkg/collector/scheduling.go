@@ -0,0 +1,49 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// SchedulingChurn is preemption, eviction or rescheduling of pod that happened while test case was running
+type SchedulingChurn struct {
+	Pod       string
+	Type      store.EventTypeEnum
+	Timestamp time.Time
+	Message   string
+}
+
+// getSchedulingChurn returns scheduling churn events of pods sorted by time they happened at
+func getSchedulingChurn(pods []PodMetrics) []SchedulingChurn {
+	var churn []SchedulingChurn
+	for _, p := range pods {
+		for _, e := range p.Events {
+			switch e.Type {
+			case store.PodPreempted, store.PodEvicted, store.PodRescheduled:
+				churn = append(churn, SchedulingChurn{Pod: p.Pod.Name, Type: e.Type, Timestamp: e.Timestamp, Message: e.Message})
+			}
+		}
+	}
+	sort.SliceStable(churn, func(i, j int) bool {
+		return churn[i].Timestamp.Before(churn[j].Timestamp)
+	})
+	return churn
+}
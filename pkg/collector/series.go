@@ -0,0 +1,198 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// MaxSeriesPoints is the number of time buckets number entities and resource usage of a test case are read into.
+// Every bucket keeps only its first and last rows and the rows with the lowest and highest value of each column,
+// so long runs don't have to be held in memory while their peaks are kept. Zero or negative value keeps all rows
+var MaxSeriesPoints = 2000
+
+type seriesRow[T any] struct {
+	seq int
+	row T
+}
+
+// timeBuckets downsamples rows into buckets of equal width covering time range of a test case
+type timeBuckets[T any] struct {
+	start   time.Time
+	width   time.Duration
+	count   int
+	timeOf  func(T) time.Time
+	values  []func(T) int64
+	buckets map[int][]seriesRow[T]
+	seq     int
+}
+
+func newTimeBuckets[T any](tc *store.TestCase, timeOf func(T) time.Time, values ...func(T) int64) *timeBuckets[T] {
+	end := tc.EndTimestamp
+	if end.IsZero() {
+		end = time.Now()
+	}
+	width := time.Duration(1)
+	if MaxSeriesPoints > 0 && end.Sub(tc.StartTimestamp) > time.Duration(MaxSeriesPoints) {
+		width = end.Sub(tc.StartTimestamp) / time.Duration(MaxSeriesPoints)
+	}
+	return &timeBuckets[T]{
+		start:   tc.StartTimestamp,
+		width:   width,
+		count:   MaxSeriesPoints,
+		timeOf:  timeOf,
+		values:  values,
+		buckets: make(map[int][]seriesRow[T]),
+	}
+}
+
+func (b *timeBuckets[T]) add(row T) {
+	s := seriesRow[T]{seq: b.seq, row: row}
+	b.seq++
+	if b.count <= 0 {
+		b.buckets[s.seq] = []seriesRow[T]{s}
+		return
+	}
+	// Rows recorded before start or after end of test case go to the edge buckets
+	i := int(b.timeOf(row).Sub(b.start) / b.width)
+	if i < 0 {
+		i = 0
+	} else if i >= b.count {
+		i = b.count - 1
+	}
+	slots, ok := b.buckets[i]
+	if !ok {
+		// Slots are first row, last row and then lowest and highest row of every value
+		slots = make([]seriesRow[T], 2+2*len(b.values))
+		for j := range slots {
+			slots[j] = s
+		}
+		b.buckets[i] = slots
+		return
+	}
+	slots[1] = s
+	for j, value := range b.values {
+		if value(row) < value(slots[2+2*j].row) {
+			slots[2+2*j] = s
+		}
+		if value(row) > value(slots[3+2*j].row) {
+			slots[3+2*j] = s
+		}
+	}
+}
+
+// rows returns kept rows bucket by bucket, in the order they were added within a bucket
+func (b *timeBuckets[T]) rows() []T {
+	keys := make([]int, 0, len(b.buckets))
+	for i := range b.buckets {
+		keys = append(keys, i)
+	}
+	sort.Ints(keys)
+	var rows []T
+	for _, i := range keys {
+		slots := b.buckets[i]
+		sort.Slice(slots, func(x, y int) bool { return slots[x].seq < slots[y].seq })
+		for j, s := range slots {
+			if j == 0 || s.seq != slots[j-1].seq {
+				rows = append(rows, s.row)
+			}
+		}
+	}
+	return rows
+}
+
+// getNumberEntities streams number entities of test case into time buckets
+func (mc *MetricsCollector) getNumberEntities(tc *store.TestCase) ([]store.NumberEntities, error) {
+	buckets := newTimeBuckets(tc,
+		func(e store.NumberEntities) time.Time { return e.Timestamp },
+		func(e store.NumberEntities) int64 { return int64(e.PodsCreating) },
+		func(e store.NumberEntities) int64 { return int64(e.PodsReady) },
+		func(e store.NumberEntities) int64 { return int64(e.PodsTerminating) },
+		func(e store.NumberEntities) int64 { return int64(e.PvcCreating) },
+		func(e store.NumberEntities) int64 { return int64(e.PvcBound) },
+		func(e store.NumberEntities) int64 { return int64(e.PvcTerminating) },
+	)
+	if err := mc.db.EachNumberEntities(store.Conditions{"tc_id": tc.ID}, "", buckets.add); err != nil {
+		return nil, err
+	}
+	return buckets.rows(), nil
+}
+
+// getResourceUsage streams resource usage of test case into time buckets of its pod. Containers of a pod are
+// saved together on every poll, so whole polls are bucketed by usage summed over containers and peak usage of a pod
+// stays what assertions compare against
+func (mc *MetricsCollector) getResourceUsage(tc *store.TestCase) ([]store.ResourceUsage, error) {
+	type pod struct {
+		buckets *timeBuckets[[]store.ResourceUsage]
+		poll    []store.ResourceUsage
+	}
+	pods := make(map[string]*pod)
+	err := mc.db.EachResourceUsage(store.Conditions{"tc_id": tc.ID}, "", func(r store.ResourceUsage) {
+		p, ok := pods[r.PodName]
+		if !ok {
+			p = &pod{buckets: newTimeBuckets(tc,
+				func(poll []store.ResourceUsage) time.Time { return poll[0].Timestamp },
+				func(poll []store.ResourceUsage) int64 {
+					return sumUsage(poll, func(r store.ResourceUsage) int64 { return r.CPU })
+				},
+				func(poll []store.ResourceUsage) int64 {
+					return sumUsage(poll, func(r store.ResourceUsage) int64 { return r.Mem })
+				},
+			)}
+			pods[r.PodName] = p
+		}
+		// A repeated container starts the next poll
+		for _, c := range p.poll {
+			if c.ContainerName == r.ContainerName {
+				p.buckets.add(p.poll)
+				p.poll = nil
+				break
+			}
+		}
+		p.poll = append(p.poll, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pods))
+	for name := range pods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var usage []store.ResourceUsage
+	for _, name := range names {
+		p := pods[name]
+		p.buckets.add(p.poll)
+		for _, poll := range p.buckets.rows() {
+			usage = append(usage, poll...)
+		}
+	}
+	sort.SliceStable(usage, func(i, j int) bool { return usage[i].Timestamp.Before(usage[j].Timestamp) })
+	return usage, nil
+}
+
+func sumUsage(poll []store.ResourceUsage, value func(store.ResourceUsage) int64) int64 {
+	var sum int64
+	for _, r := range poll {
+		sum += value(r)
+	}
+	return sum
+}
@@ -0,0 +1,195 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSLOTarget is percentage of operations which should meet SLO if target isn't specified
+const DefaultSLOTarget = 99.0
+
+// SLO is a latency objective of a stage, operation meets it if the stage took no longer than Threshold
+type SLO struct {
+	Stage     string
+	Threshold time.Duration
+	// Target is percentage of operations which should meet the objective
+	Target float64
+}
+
+// String formats SLO the way it's parsed
+func (s SLO) String() string {
+	return fmt.Sprintf("%s<=%s@%s%%", s.Stage, s.Threshold, strconv.FormatFloat(s.Target, 'f', -1, 64))
+}
+
+// SLOPoint is a share of operations meeting SLO among the ones started within rolling window ending at Time
+type SLOPoint struct {
+	Time  time.Time
+	Total int
+	Met   int
+}
+
+// Percent returns percentage of operations meeting SLO
+func (p SLOPoint) Percent() float64 {
+	if p.Total == 0 {
+		return 100
+	}
+	return float64(p.Met) * 100 / float64(p.Total)
+}
+
+// ParseSLOs parses comma separated objectives in stage=threshold[@target] format, ex. PVCBind=10s@95,PodCreation=1m
+func ParseSLOs(value string) ([]SLO, error) {
+	var slos []SLO
+	if strings.TrimSpace(value) == "" {
+		return slos, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		stage, rest, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found {
+			return nil, fmt.Errorf("SLO %q should be in stage=threshold[@target] format", item)
+		}
+		if !isKnownStage(stage) {
+			return nil, fmt.Errorf("unknown stage %q in SLO %q", stage, item)
+		}
+		slo := SLO{Stage: stage, Target: DefaultSLOTarget}
+		threshold, target, hasTarget := strings.Cut(rest, "@")
+		d, err := time.ParseDuration(threshold)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid threshold in SLO %q", item)
+		}
+		slo.Threshold = d
+		if hasTarget {
+			t, err := strconv.ParseFloat(strings.TrimSuffix(target, "%"), 64)
+			if err != nil || t <= 0 || t > 100 {
+				return nil, fmt.Errorf("invalid target in SLO %q, should be percentage", item)
+			}
+			slo.Target = t
+		}
+		slos = append(slos, slo)
+	}
+	return slos, nil
+}
+
+func isKnownStage(stage string) bool {
+	for _, s := range []PVCStage{PVCBind, PVCConsumerWait, PVCAttachment, PVCCreation, PVCDeletion, PVCUnattachment} {
+		if string(s) == stage {
+			return true
+		}
+	}
+	for _, s := range []PodStage{PodCreation, PodDeletion, EphemeralPublish, EphemeralUnpublish} {
+		if string(s) == stage {
+			return true
+		}
+	}
+	for _, s := range getCustomStages("") {
+		if s.Name == stage {
+			return true
+		}
+	}
+	return false
+}
+
+type sloOperation struct {
+	started time.Time
+	met     bool
+}
+
+// sloOperations returns operations of SLO stage ordered by the time their entity was added.
+// Stages which never started are skipped, stages which never ended have negative duration and don't meet SLO
+func sloOperations(tc TestCaseMetrics, slo SLO) []sloOperation {
+	var ops []sloOperation
+	add := func(added time.Time, d time.Duration, ok bool) {
+		if !ok || d == 0 || added.IsZero() {
+			return
+		}
+		ops = append(ops, sloOperation{started: added, met: d > 0 && d <= slo.Threshold})
+	}
+	for _, pvc := range tc.PVCs {
+		d, ok := pvc.Metrics[PVCStage(slo.Stage)]
+		add(pvc.Added, d, ok)
+	}
+	for _, pod := range tc.Pods {
+		d, ok := pod.Metrics[PodStage(slo.Stage)]
+		add(pod.Added, d, ok)
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].started.Before(ops[j].started)
+	})
+	return ops
+}
+
+// SLOBurnDown returns share of operations meeting SLO over the course of test case,
+// a point is produced for every operation and covers operations started within window before it
+func SLOBurnDown(tc TestCaseMetrics, slo SLO, window time.Duration) []SLOPoint {
+	ops := sloOperations(tc, slo)
+	points := make([]SLOPoint, 0, len(ops))
+	first := 0
+	for i, op := range ops {
+		for ops[first].started.Before(op.started.Add(-window)) {
+			first++
+		}
+		p := SLOPoint{Time: op.started}
+		for _, o := range ops[first : i+1] {
+			p.Total++
+			if o.met {
+				p.Met++
+			}
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// SLOSummary is the overall result of SLO in test case
+type SLOSummary struct {
+	SLO   SLO
+	Total int
+	Met   int
+	// Degraded is true if rolling share of operations meeting SLO dropped below target at any point
+	Degraded bool
+	// Worst is the lowest rolling share of operations meeting SLO
+	Worst float64
+}
+
+// Percent returns percentage of all operations meeting SLO
+func (s SLOSummary) Percent() float64 {
+	return SLOPoint{Total: s.Total, Met: s.Met}.Percent()
+}
+
+// SummarizeSLO evaluates SLO over the whole test case and its rolling windows
+func SummarizeSLO(tc TestCaseMetrics, slo SLO, window time.Duration) SLOSummary {
+	summary := SLOSummary{SLO: slo, Worst: 100}
+	for _, op := range sloOperations(tc, slo) {
+		summary.Total++
+		if op.met {
+			summary.Met++
+		}
+	}
+	for _, p := range SLOBurnDown(tc, slo, window) {
+		if p.Percent() < summary.Worst {
+			summary.Worst = p.Percent()
+		}
+		if p.Percent() < slo.Target {
+			summary.Degraded = true
+		}
+	}
+	return summary
+}
@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// SnapshotReadiness holds how long snapshots of test case took to become ready
+type SnapshotReadiness struct {
+	// Ready are readiness times of snapshots which became ready within the deadline
+	Ready []time.Duration
+	// Censored are snapshots which weren't ready within the deadline or when test case ended,
+	// their readiness time is only known to be longer than they were waited for
+	Censored []CensoredSnapshot
+}
+
+// CensoredSnapshot is a snapshot whose readiness wasn't observed
+type CensoredSnapshot struct {
+	Name   string
+	Waited time.Duration
+	// TimedOut marks snapshots which weren't ready within the deadline, others were still pending when test case ended
+	TimedOut bool
+	// LastError is an error snapshot controller reported in status of timed out snapshot
+	LastError string
+}
+
+// Samples returns number of snapshots readiness of which was observed or censored
+func (sr SnapshotReadiness) Samples() int {
+	return len(sr.Ready) + len(sr.Censored)
+}
+
+// Avg returns average readiness time of snapshots which became ready, zero if none did
+func (sr SnapshotReadiness) Avg() time.Duration {
+	if len(sr.Ready) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range sr.Ready {
+		total += d
+	}
+	return total / time.Duration(len(sr.Ready))
+}
+
+// Max returns the longest readiness time of snapshots which became ready, zero if none did
+func (sr SnapshotReadiness) Max() time.Duration {
+	var longest time.Duration
+	for _, d := range sr.Ready {
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// TimedOut returns number of snapshots which weren't ready within the deadline
+func (sr SnapshotReadiness) TimedOut() int {
+	n := 0
+	for _, c := range sr.Censored {
+		if c.TimedOut {
+			n++
+		}
+	}
+	return n
+}
+
+// getSnapshotReadiness collects readiness of snapshots observed during test case, nil if there were none
+func (mc *MetricsCollector) getSnapshotReadiness(tc *store.TestCase) (*SnapshotReadiness, error) {
+	entitiesWithEvents, err := mc.db.GetEntitiesWithEventsByTestCaseAndEntityType(tc, store.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if len(entitiesWithEvents) == 0 {
+		return nil, nil
+	}
+	sr := &SnapshotReadiness{}
+	for snapshot, events := range entitiesWithEvents {
+		addSnapshotReadiness(sr, snapshot.Name, events, tc.EndTimestamp)
+	}
+	sort.Slice(sr.Ready, func(i, j int) bool { return sr.Ready[i] < sr.Ready[j] })
+	sort.SliceStable(sr.Censored, func(i, j int) bool { return sr.Censored[i].Name < sr.Censored[j].Name })
+	return sr, nil
+}
+
+// addSnapshotReadiness adds readiness of a single snapshot, snapshot which timed out is censored even if it
+// became ready later, snapshot without ready event is censored at the end of test case
+func addSnapshotReadiness(sr *SnapshotReadiness, name string, events []store.Event, end time.Time) {
+	var added, ready, timedOut *store.Event
+	for i, e := range events {
+		switch e.Type {
+		case store.SnapshotAdded:
+			added = &events[i]
+		case store.SnapshotReady:
+			ready = &events[i]
+		case store.SnapshotReadyTimeout:
+			timedOut = &events[i]
+		}
+	}
+	if added == nil {
+		return
+	}
+	switch {
+	case timedOut != nil:
+		sr.Censored = append(sr.Censored, CensoredSnapshot{
+			Name:      name,
+			Waited:    timedOut.Timestamp.Sub(added.Timestamp),
+			TimedOut:  true,
+			LastError: timedOut.Message,
+		})
+	case ready != nil:
+		sr.Ready = append(sr.Ready, ready.Timestamp.Sub(added.Timestamp))
+	default:
+		waited := end.Sub(added.Timestamp)
+		if waited < 0 {
+			waited = 0
+		}
+		sr.Censored = append(sr.Censored, CensoredSnapshot{Name: name, Waited: waited})
+	}
+}
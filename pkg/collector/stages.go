@@ -0,0 +1,91 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// CustomStage is a stage of PVC or Pod measured between two registered lifecycle events
+type CustomStage struct {
+	Name   string
+	Entity store.EntityTypeEnum
+	Start  store.EventTypeEnum
+	End    store.EventTypeEnum
+}
+
+var (
+	customStagesMutex sync.RWMutex
+	customStages      []CustomStage
+)
+
+// RegisterStage adds stage measured for every entity having both start and end events,
+// events must be registered lifecycle events of the same PVC or Pod entity
+func RegisterStage(name string, start, end store.EventTypeEnum) error {
+	if isKnownStage(name) {
+		return fmt.Errorf("stage %s is already registered", name)
+	}
+	startInfo, ok := store.GetEventType(start)
+	if !ok {
+		return fmt.Errorf("start event type %s of stage %s isn't registered", start, name)
+	}
+	endInfo, ok := store.GetEventType(end)
+	if !ok {
+		return fmt.Errorf("end event type %s of stage %s isn't registered", end, name)
+	}
+	if startInfo.Category != store.LifecycleCategory || endInfo.Category != store.LifecycleCategory {
+		return fmt.Errorf("stage %s must start and end with %s events", name, store.LifecycleCategory)
+	}
+	if startInfo.Entity != endInfo.Entity || (startInfo.Entity != store.Pvc && startInfo.Entity != store.Pod) {
+		return fmt.Errorf("stage %s must start and end with events of the same PVC or Pod", name)
+	}
+
+	customStagesMutex.Lock()
+	defer customStagesMutex.Unlock()
+	customStages = append(customStages, CustomStage{Name: name, Entity: startInfo.Entity, Start: start, End: end})
+	return nil
+}
+
+// getCustomStages returns registered stages of entity type
+func getCustomStages(entity store.EntityTypeEnum) []CustomStage {
+	customStagesMutex.RLock()
+	defer customStagesMutex.RUnlock()
+	var stages []CustomStage
+	for _, s := range customStages {
+		if entity == "" || s.Entity == entity {
+			stages = append(stages, s)
+		}
+	}
+	return stages
+}
+
+// customStageDuration returns duration of stage if entity has both of its events
+func customStageDuration(timestamps map[store.EventTypeEnum]time.Time, cs CustomStage) (time.Duration, bool) {
+	start, ok := timestamps[cs.Start]
+	if !ok {
+		return 0, false
+	}
+	end, ok := timestamps[cs.End]
+	if !ok {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
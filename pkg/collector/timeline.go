@@ -0,0 +1,142 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// UsageChangeThreshold is relative change of CPU or memory usage of driver container which makes it to timeline,
+// so steady usage sampled every few seconds doesn't flood it
+var UsageChangeThreshold = 0.25
+
+// MergeTimeline merges events recorded by observers, Kubernetes events, resource usage of driver containers,
+// injected faults and ingested audit log of test case into one timeline sorted by time and saves it, replacing
+// the previous one. Audit log timestamps come from API server, they are moved to runner host clock by clock skew
+// of test case
+func MergeTimeline(db store.Store, tc *store.TestCase) ([]*store.TimelineEntry, error) {
+	entities, err := db.GetEntities(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[int64]string, len(entities))
+	for _, e := range entities {
+		objects[e.ID] = fmt.Sprintf("%s/%s", e.Type, e.Name)
+	}
+
+	var timeline []*store.TimelineEntry
+	events, err := db.GetEvents(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		entry := &store.TimelineEntry{Timestamp: e.Timestamp, Source: store.TimelineObserver, Object: objects[e.EntityID], Message: string(e.Type)}
+		if e.Type == store.ComponentEvent {
+			entry.Source = store.TimelineKubernetes
+			entry.Message = e.Message
+			if e.Source != "" {
+				entry.Message += " from " + e.Source
+			}
+		} else if e.Message != "" {
+			entry.Message += ": " + e.Message
+		}
+		timeline = append(timeline, entry)
+	}
+
+	usage, err := db.GetResourceUsage(store.Conditions{"tc_id": tc.ID}, "timestamp", 0)
+	if err != nil {
+		return nil, err
+	}
+	timeline = append(timeline, usageChanges(usage)...)
+
+	windows, err := db.GetFaultWindows(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, fw := range windows {
+		object := "Node/" + fw.Node
+		timeline = append(timeline,
+			&store.TimelineEntry{Timestamp: fw.Start, Source: store.TimelineFault, Object: object, Message: fmt.Sprintf("fault of step %d injected", fw.Step)},
+			&store.TimelineEntry{Timestamp: fw.End, Source: store.TimelineFault, Object: object, Message: fmt.Sprintf("fault of step %d cleared", fw.Step)})
+		if fw.Recovered {
+			timeline = append(timeline, &store.TimelineEntry{
+				Timestamp: fw.End.Add(fw.Recovery), Source: store.TimelineFault, Object: object,
+				Message: fmt.Sprintf("IO recovered from fault of step %d", fw.Step),
+			})
+		}
+	}
+
+	audit, err := db.GetAuditEntries(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range audit {
+		verb := a.Verb
+		if a.Subresource != "" {
+			verb += " " + a.Subresource
+		}
+		timeline = append(timeline, &store.TimelineEntry{
+			Timestamp: a.Timestamp.Add(-tc.ClockSkew),
+			Source:    store.TimelineAudit,
+			Object:    a.Resource + "/" + a.Name,
+			Message:   fmt.Sprintf("%s by %s (%d)", verb, a.User, a.Code),
+			Corrected: true,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	if err := db.SaveTimeline(tc.ID, timeline); err != nil {
+		return nil, err
+	}
+	return timeline, nil
+}
+
+// usageChanges returns the first resource usage sample of every driver container and samples which differ
+// from the last one included by at least UsageChangeThreshold
+func usageChanges(usage []store.ResourceUsage) []*store.TimelineEntry {
+	type container struct{ pod, name string }
+	last := make(map[container]store.ResourceUsage)
+	var changes []*store.TimelineEntry
+	for _, u := range usage {
+		key := container{u.PodName, u.ContainerName}
+		prev, ok := last[key]
+		if ok && !changed(prev.CPU, u.CPU) && !changed(prev.Mem, u.Mem) {
+			continue
+		}
+		last[key] = u
+		changes = append(changes, &store.TimelineEntry{
+			Timestamp: u.Timestamp,
+			Source:    store.TimelineMetrics,
+			Object:    fmt.Sprintf("Pod/%s/%s", u.PodName, u.ContainerName),
+			Message:   fmt.Sprintf("cpu %dm, memory %dMi", u.CPU, u.Mem),
+		})
+	}
+	return changes
+}
+
+func changed(prev, cur int64) bool {
+	if prev == 0 {
+		return cur != 0
+	}
+	diff := float64(cur-prev) / float64(prev)
+	return diff >= UsageChangeThreshold || diff <= -UsageChangeThreshold
+}
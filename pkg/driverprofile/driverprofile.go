@@ -0,0 +1,121 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package driverprofile
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed profiles/*.yaml
+var profilesFS embed.FS
+
+// Profile presets certification of a driver: suites worth running, resource timeout and capabilities driver is
+// expected to declare. Everything it sets can be overridden by certification config and flags
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Timeout is used for resources when timeout flag isn't given
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// AccessModes are access modes driver supports, RWX and RWOP suites are enabled for the ones listed
+	AccessModes []string `json:"accessModes,omitempty"`
+	// Suites are defaults of storage class entry of certification config, e.g. Snapshot: true
+	Suites       map[string]interface{}           `json:"suites,omitempty"`
+	Capabilities k8sclient.CapabilityExpectations `json:"capabilities"`
+}
+
+// Names returns sorted names of profiles bundled in the binary
+func Names() []string {
+	files, err := profilesFS.ReadDir("profiles")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(f.Name(), path.Ext(f.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns bundled profile by name, or profile read from yaml file if name is a path to one
+func Get(name string) (*Profile, error) {
+	data, err := profilesFS.ReadFile("profiles/" + name + ".yaml")
+	if err != nil {
+		if data, err = os.ReadFile(name); err != nil { // #nosec G304
+			return nil, fmt.Errorf("unknown driver profile %s, use one of %s or path to profile file", name, strings.Join(Names(), ", "))
+		}
+	}
+	p := &Profile{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("can't parse driver profile %s: %v", name, err)
+	}
+	if p.Name == "" {
+		return nil, errors.New("driver profile must have a name")
+	}
+	p.Capabilities.Profile = p.Name
+	return p, nil
+}
+
+// EntryDefaults returns defaults of storage class entry of certification config
+func (p *Profile) EntryDefaults() map[string]interface{} {
+	defaults := make(map[string]interface{}, len(p.Suites)+2)
+	for _, mode := range p.AccessModes {
+		switch mode {
+		case "ReadWriteMany":
+			defaults["RWX"] = true
+		case "ReadWriteOncePod":
+			defaults["RWOP"] = true
+		}
+	}
+	for key, value := range p.Suites {
+		defaults[key] = value
+	}
+	return defaults
+}
+
+// Apply sets defaults of the profile in storage class entry of certification config, keys already set in the
+// entry are kept, they are matched case-insensitively like config decoder does
+func (p *Profile) Apply(entry map[string]interface{}) {
+	for key, value := range p.EntryDefaults() {
+		set := false
+		for k := range entry {
+			if strings.EqualFold(k, key) {
+				set = true
+				break
+			}
+		}
+		if !set {
+			entry[key] = value
+		}
+	}
+}
+
+// Expectations returns capabilities profile expects driver to declare
+func (p *Profile) Expectations() *k8sclient.CapabilityExpectations {
+	e := p.Capabilities
+	return &e
+}
@@ -0,0 +1,69 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package driverprofile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundledProfiles(t *testing.T) {
+	assert.Equal(t, []string{"powerflex", "powermax", "powerscale", "powerstore", "unity"}, Names())
+	for _, name := range Names() {
+		p, err := Get(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, name, p.Name)
+		assert.Equal(t, name, p.Capabilities.Profile)
+		assert.NotEmpty(t, p.Capabilities.Driver, name)
+		assert.NotZero(t, p.Timeout.Duration, name)
+	}
+
+	_, err := Get("ontap")
+	assert.Error(t, err)
+}
+
+func TestProfileFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "custom.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("name: custom\ntimeout: 90s\nsuites:\n  Clone: true\ncapabilities:\n  driver: csi.example.com\n"), 0o600))
+	p, err := Get(file)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, p.Timeout.Duration)
+	assert.Equal(t, "csi.example.com", p.Expectations().Driver)
+
+	assert.NoError(t, os.WriteFile(file, []byte("name: custom\nsuits: {}\n"), 0o600))
+	_, err = Get(file)
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	p, err := Get("powerscale")
+	assert.NoError(t, err)
+
+	entry := map[string]interface{}{"name": "isilon", "snapshot": false, "rwx": false}
+	p.Apply(entry)
+	assert.Equal(t, false, entry["snapshot"])
+	assert.Equal(t, false, entry["rwx"])
+	assert.NotContains(t, entry, "RWX")
+	assert.Equal(t, true, entry["RWOP"])
+	assert.Equal(t, true, entry["Clone"])
+	assert.Equal(t, false, entry["RawBlock"])
+	assert.Equal(t, "8Gi", entry["MinSize"])
+}
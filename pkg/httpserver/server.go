@@ -0,0 +1,87 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package httpserver runs HTTP APIs of cert-csi in background
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// shutdownTimeout bounds waiting for in-flight requests on Stop
+const shutdownTimeout = 5 * time.Second
+
+// Server serves handler in background, name is used in logs
+type Server struct {
+	name   string
+	server *http.Server
+}
+
+// New creates a Server, address without host is bound to localhost
+func New(name, address string, handler http.Handler) *Server {
+	if strings.HasPrefix(address, ":") {
+		address = "localhost" + address
+	}
+	return &Server{
+		name: name,
+		server: &http.Server{
+			Addr:              address,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start starts listening in background and returns actual address
+func (s *Server) Start() (string, error) {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return "", err
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("%s server stopped; error=%v", s.name, err)
+		}
+	}()
+	return listener.Addr().String(), nil
+}
+
+// Stop gracefully shuts down the server
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Errorf("Can't stop %s server; error=%v", s.name, err)
+	}
+}
+
+// URL returns base URL of server listening on address, address without host means localhost
+func URL(address string) string {
+	if !strings.Contains(address, "://") {
+		if strings.HasPrefix(address, ":") {
+			address = "localhost" + address
+		}
+		address = "http://" + address
+	}
+	return strings.TrimSuffix(address, "/")
+}
@@ -0,0 +1,50 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package httpserver
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	server := New("Test", ":0", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	addr, err := server.Start()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, addr)
+
+	resp, err := http.Get(URL(addr) + "/")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+
+	server.Stop()
+	_, err = http.Get(URL(addr) + "/")
+	assert.Error(t, err)
+}
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "http://localhost:8090", URL(":8090"))
+	assert.Equal(t, "http://host:8090", URL("host:8090"))
+	assert.Equal(t, "https://host", URL("https://host/"))
+}
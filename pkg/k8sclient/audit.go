@@ -0,0 +1,275 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Audit records API requests made with every config loaded by GetConfig, disabled if nil
+var Audit *PermissionAudit
+
+// Permission is an API verb used on a resource or a non-resource URL
+type Permission struct {
+	Group string
+	// Resource includes subresource, ex. pods/exec
+	Resource       string
+	Verb           string
+	NonResourceURL string
+	Namespaced     bool
+}
+
+// PermissionAudit records permissions used by API requests, so minimal RBAC role of a run can be generated
+type PermissionAudit struct {
+	permissions map[Permission]struct{}
+	namespaces  map[string]struct{}
+	mutex       sync.Mutex
+}
+
+// NewPermissionAudit is a PermissionAudit constructor
+func NewPermissionAudit() *PermissionAudit {
+	return &PermissionAudit{
+		permissions: make(map[Permission]struct{}),
+		namespaces:  make(map[string]struct{}),
+	}
+}
+
+// Wrap wraps transport of rest config so every request is recorded before it's sent
+func (a *PermissionAudit) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return auditRoundTripper{audit: a, next: rt}
+}
+
+type auditRoundTripper struct {
+	audit *PermissionAudit
+	next  http.RoundTripper
+}
+
+func (rt auditRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.audit.Record(req.Method, req.URL)
+	return rt.next.RoundTrip(req)
+}
+
+// Record records permission needed by request
+func (a *PermissionAudit) Record(method string, u *url.URL) {
+	p, namespace := parsePermission(method, u)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.permissions[p] = struct{}{}
+	if namespace != "" {
+		a.namespaces[namespace] = struct{}{}
+	}
+}
+
+// Permissions returns recorded permissions sorted by group, resource and verb
+func (a *PermissionAudit) Permissions() []Permission {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	permissions := make([]Permission, 0, len(a.permissions))
+	for p := range a.permissions {
+		permissions = append(permissions, p)
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		pi, pj := permissions[i], permissions[j]
+		if pi.NonResourceURL != pj.NonResourceURL {
+			return pi.NonResourceURL < pj.NonResourceURL
+		}
+		if pi.Group != pj.Group {
+			return pi.Group < pj.Group
+		}
+		if pi.Resource != pj.Resource {
+			return pi.Resource < pj.Resource
+		}
+		return pi.Verb < pj.Verb
+	})
+	return permissions
+}
+
+// Namespaces returns sorted namespaces requests were made in
+func (a *PermissionAudit) Namespaces() []string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	namespaces := make([]string, 0, len(a.namespaces))
+	for ns := range a.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// ClusterRole returns role granting exactly the recorded permissions. Namespaced resources are granted
+// cluster wide too, since suites create their namespaces with random suffixes
+func (a *PermissionAudit) ClusterRole(name string) *rbacv1.ClusterRole {
+	role := &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: rbacv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+
+	verbs := make(map[string]map[string][]string) // group -> resource -> verbs
+	var groups []string
+	nonResource := make(map[string][]string) // verb -> urls
+	for _, p := range a.Permissions() {
+		if p.NonResourceURL != "" {
+			nonResource[p.Verb] = append(nonResource[p.Verb], p.NonResourceURL)
+			continue
+		}
+		if _, ok := verbs[p.Group]; !ok {
+			verbs[p.Group] = make(map[string][]string)
+			groups = append(groups, p.Group)
+		}
+		verbs[p.Group][p.Resource] = append(verbs[p.Group][p.Resource], p.Verb)
+	}
+
+	for _, group := range groups {
+		// Resources with the same verbs share a rule to keep the role short
+		var keys []string
+		resources := make(map[string][]string)
+		for resource, v := range verbs[group] {
+			key := strings.Join(v, ",")
+			if _, ok := resources[key]; !ok {
+				keys = append(keys, key)
+			}
+			resources[key] = append(resources[key], resource)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sort.Strings(resources[key])
+			role.Rules = append(role.Rules, rbacv1.PolicyRule{
+				APIGroups: []string{group},
+				Resources: resources[key],
+				Verbs:     strings.Split(key, ","),
+			})
+		}
+	}
+
+	var nonResourceVerbs []string
+	for verb := range nonResource {
+		nonResourceVerbs = append(nonResourceVerbs, verb)
+	}
+	sort.Strings(nonResourceVerbs)
+	for _, verb := range nonResourceVerbs {
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			NonResourceURLs: nonResource[verb],
+			Verbs:           []string{verb},
+		})
+	}
+	return role
+}
+
+// WriteRole saves recorded permissions as ClusterRole yaml
+func (a *PermissionAudit) WriteRole(path, name string) error {
+	data, err := yaml.Marshal(a.ClusterRole(name))
+	if err != nil {
+		return err
+	}
+	header := "# Permissions used by cert-csi run\n"
+	if namespaces := a.Namespaces(); len(namespaces) != 0 {
+		header += fmt.Sprintf("# Namespaces accessed: %s\n", strings.Join(namespaces, ", "))
+	}
+	return os.WriteFile(path, append([]byte(header), data...), 0o600)
+}
+
+// parsePermission converts API request into verb and resource it needs, as authorizer of API server does
+func parsePermission(method string, u *url.URL) (Permission, string) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var p Permission
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		p.Group = parts[1]
+		parts = parts[3:]
+	default:
+		p.NonResourceURL = u.Path
+		p.Verb = strings.ToLower(method)
+		if method == http.MethodHead {
+			p.Verb = "get"
+		}
+		return p, ""
+	}
+	if len(parts) == 0 {
+		// Discovery of API group version
+		return Permission{NonResourceURL: u.Path, Verb: "get"}, ""
+	}
+
+	watch := u.Query().Get("watch") == "true" || u.Query().Get("watch") == "1"
+	if parts[0] == "watch" {
+		watch = true
+		parts = parts[1:]
+		if len(parts) == 0 {
+			return Permission{NonResourceURL: u.Path, Verb: "get"}, ""
+		}
+	}
+
+	var namespace string
+	// namespaces/<name>/<resource>... addresses namespaced resource, namespaces/<name>[/status|/finalize] namespace itself
+	if len(parts) >= 3 && parts[0] == "namespaces" && parts[2] != "status" && parts[2] != "finalize" {
+		namespace = parts[1]
+		p.Namespaced = true
+		parts = parts[2:]
+	}
+
+	p.Resource = parts[0]
+	var name string
+	if len(parts) >= 2 {
+		name = parts[1]
+	}
+	if len(parts) >= 3 {
+		p.Resource += "/" + parts[2]
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case watch:
+			p.Verb = "watch"
+		case name == "":
+			p.Verb = "list"
+		default:
+			p.Verb = "get"
+		}
+	case http.MethodPost:
+		p.Verb = "create"
+	case http.MethodPut:
+		p.Verb = "update"
+	case http.MethodPatch:
+		p.Verb = "patch"
+	case http.MethodDelete:
+		if name == "" {
+			p.Verb = "deletecollection"
+		} else {
+			p.Verb = "delete"
+		}
+	default:
+		p.Verb = strings.ToLower(method)
+	}
+	// Streaming subresources are authorized as create regardless of the method used to upgrade connection
+	switch p.Resource {
+	case "pods/exec", "pods/attach", "pods/portforward":
+		p.Verb = "create"
+	}
+	return p, namespace
+}
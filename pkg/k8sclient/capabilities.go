@@ -0,0 +1,240 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sort"
+
+	snapclient "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FSTypeParameter is storage class parameter external-provisioner passes filesystem type in
+const FSTypeParameter = "csi.storage.k8s.io/fstype"
+
+// DriverCapabilities are capabilities CSI driver declares with its CSIDriver object, storage class and snapshot classes
+type DriverCapabilities struct {
+	Driver string `json:"driver"`
+	// Registered is false if driver has no CSIDriver object, Kubernetes defaults are declared then
+	Registered           bool     `json:"registered"`
+	AttachRequired       bool     `json:"attachRequired"`
+	PodInfoOnMount       bool     `json:"podInfoOnMount"`
+	VolumeLifecycleModes []string `json:"volumeLifecycleModes"`
+	FSGroupPolicy        string   `json:"fsGroupPolicy"`
+	AllowVolumeExpansion bool     `json:"allowVolumeExpansion"`
+	VolumeBindingMode    string   `json:"volumeBindingMode"`
+	// SnapshotClasses are volume snapshot classes of the driver, empty if snapshot CRDs aren't installed
+	SnapshotClasses []string `json:"snapshotClasses,omitempty"`
+	// IPFamilies are families of internal addresses of cluster nodes, empty if nodes can't be listed
+	IPFamilies []string `json:"ipFamilies,omitempty"`
+	// FSType is filesystem type storage class formats volumes with, empty if driver picks it
+	FSType string `json:"fsType,omitempty"`
+	// Expected are capabilities driver profile of the run expects, nil if run has no profile
+	Expected *CapabilityExpectations `json:"expected,omitempty"`
+}
+
+// CapabilityExpectations are capabilities driver profile expects driver to declare, unset ones aren't checked
+type CapabilityExpectations struct {
+	Profile              string   `json:"profile"`
+	Driver               string   `json:"driver,omitempty"`
+	AttachRequired       *bool    `json:"attachRequired,omitempty"`
+	PodInfoOnMount       *bool    `json:"podInfoOnMount,omitempty"`
+	VolumeLifecycleModes []string `json:"volumeLifecycleModes,omitempty"`
+	FSGroupPolicy        string   `json:"fsGroupPolicy,omitempty"`
+	AllowVolumeExpansion *bool    `json:"allowVolumeExpansion,omitempty"`
+	// FSTypes are filesystem types driver supports, storage class may use any of them
+	FSTypes []string `json:"fsTypes,omitempty"`
+}
+
+// Unexpected returns names of capabilities which differ from the ones expected, nil if nothing is expected
+func (dc *DriverCapabilities) Unexpected() []string {
+	e := dc.Expected
+	if e == nil {
+		return nil
+	}
+	var names []string
+	if e.Driver != "" && e.Driver != dc.Driver {
+		names = append(names, "driver")
+	}
+	if e.AttachRequired != nil && *e.AttachRequired != dc.AttachRequired {
+		names = append(names, "attachRequired")
+	}
+	if e.PodInfoOnMount != nil && *e.PodInfoOnMount != dc.PodInfoOnMount {
+		names = append(names, "podInfoOnMount")
+	}
+	for _, m := range e.VolumeLifecycleModes {
+		if !dc.DeclaresLifecycleMode(storagev1.VolumeLifecycleMode(m)) {
+			names = append(names, "volumeLifecycleModes")
+			break
+		}
+	}
+	if e.FSGroupPolicy != "" && e.FSGroupPolicy != dc.FSGroupPolicy {
+		names = append(names, "fsGroupPolicy")
+	}
+	if e.AllowVolumeExpansion != nil && *e.AllowVolumeExpansion != dc.AllowVolumeExpansion {
+		names = append(names, "allowVolumeExpansion")
+	}
+	if dc.FSType != "" && len(e.FSTypes) != 0 && !slices.Contains(e.FSTypes, dc.FSType) {
+		names = append(names, "fsType")
+	}
+	return names
+}
+
+// DeclaresLifecycleMode checks whether driver declares volume lifecycle mode
+func (dc *DriverCapabilities) DeclaresLifecycleMode(mode storagev1.VolumeLifecycleMode) bool {
+	for _, m := range dc.VolumeLifecycleModes {
+		if m == string(mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// DriverCapabilities returns capabilities declared for driver provisioning volumes of storage class
+func (c *KubeClient) DriverCapabilities(ctx context.Context, storageClass string) (*DriverCapabilities, error) {
+	sc, err := c.ClientSet.StorageV1().StorageClasses().Get(ctx, storageClass, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	caps := &DriverCapabilities{
+		Driver:               sc.Provisioner,
+		AttachRequired:       true,
+		VolumeLifecycleModes: []string{string(storagev1.VolumeLifecyclePersistent)},
+		FSGroupPolicy:        string(storagev1.ReadWriteOnceWithFSTypeFSGroupPolicy),
+		VolumeBindingMode:    string(storagev1.VolumeBindingImmediate),
+	}
+	if sc.AllowVolumeExpansion != nil {
+		caps.AllowVolumeExpansion = *sc.AllowVolumeExpansion
+	}
+	if sc.VolumeBindingMode != nil {
+		caps.VolumeBindingMode = string(*sc.VolumeBindingMode)
+	}
+	caps.FSType = sc.Parameters[FSTypeParameter]
+
+	driver, err := c.ClientSet.StorageV1().CSIDrivers().Get(ctx, sc.Provisioner, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	default:
+		caps.Registered = true
+		if driver.Spec.AttachRequired != nil {
+			caps.AttachRequired = *driver.Spec.AttachRequired
+		}
+		if driver.Spec.PodInfoOnMount != nil {
+			caps.PodInfoOnMount = *driver.Spec.PodInfoOnMount
+		}
+		if len(driver.Spec.VolumeLifecycleModes) != 0 {
+			caps.VolumeLifecycleModes = nil
+			for _, m := range driver.Spec.VolumeLifecycleModes {
+				caps.VolumeLifecycleModes = append(caps.VolumeLifecycleModes, string(m))
+			}
+		}
+		if driver.Spec.FSGroupPolicy != nil {
+			caps.FSGroupPolicy = string(*driver.Spec.FSGroupPolicy)
+		}
+	}
+
+	caps.SnapshotClasses, err = c.driverSnapshotClasses(ctx, sc.Provisioner)
+	if err != nil {
+		return nil, err
+	}
+	// Restricted users may not be allowed to list nodes, IP families are just left out then
+	if caps.IPFamilies, err = c.NodeIPFamilies(ctx); err != nil {
+		logrus.Debugf("Can't list IP families of nodes; error=%v", err)
+	}
+	return caps, nil
+}
+
+// NodeIPFamilies returns sorted IP families of internal addresses of cluster nodes, dual-stack clusters have both
+func (c *KubeClient) NodeIPFamilies(ctx context.Context) ([]string, error) {
+	nodes, err := c.ClientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[v1.IPFamily]bool)
+	for _, n := range nodes.Items {
+		for _, addr := range n.Status.Addresses {
+			if addr.Type == v1.NodeInternalIP {
+				seen[IPFamilyOf(addr.Address)] = true
+			}
+		}
+	}
+	var families []string
+	for _, family := range []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol} {
+		if seen[family] {
+			families = append(families, string(family))
+		}
+	}
+	return families, nil
+}
+
+// IPFamilyOf returns IP family of address, empty for anything but IP address
+func IPFamilyOf(addr string) v1.IPFamily {
+	ip := net.ParseIP(addr)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return v1.IPv4Protocol
+	}
+	return v1.IPv6Protocol
+}
+
+// driverSnapshotClasses returns sorted names of volume snapshot classes of the driver
+func (c *KubeClient) driverSnapshotClasses(ctx context.Context, driver string) ([]string, error) {
+	api, err := c.SnapshotAPI()
+	if err != nil || api == SnapshotAPINone {
+		return nil, err
+	}
+	cset, err := snapclient.NewForConfig(c.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []string
+	if api == SnapshotAPIV1 {
+		list, err := cset.SnapshotV1().VolumeSnapshotClasses().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, vsc := range list.Items {
+			if vsc.Driver == driver {
+				classes = append(classes, vsc.Name)
+			}
+		}
+	} else {
+		list, err := cset.SnapshotV1beta1().VolumeSnapshotClasses().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, vsc := range list.Items {
+			if vsc.Driver == driver {
+				classes = append(classes, vsc.Name)
+			}
+		}
+	}
+	sort.Strings(classes)
+	return classes, nil
+}
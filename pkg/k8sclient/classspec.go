@@ -0,0 +1,131 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	snapclient "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClassSpec is a flattened spec of StorageClass or VolumeSnapshotClass, parameters are kept under parameters.<key>
+type ClassSpec map[string]string
+
+// Diff returns sorted changes between spec and the updated one in "field: old -> new" format
+func (s ClassSpec) Diff(updated ClassSpec) []string {
+	keys := make(map[string]struct{})
+	for k := range s {
+		keys[k] = struct{}{}
+	}
+	for k := range updated {
+		keys[k] = struct{}{}
+	}
+	var diff []string
+	for k := range keys {
+		old, hadOld := s[k]
+		cur, hasCur := updated[k]
+		switch {
+		case !hasCur:
+			diff = append(diff, fmt.Sprintf("%s: %q removed", k, old))
+		case !hadOld:
+			diff = append(diff, fmt.Sprintf("%s: %q added", k, cur))
+		case old != cur:
+			diff = append(diff, fmt.Sprintf("%s: %q -> %q", k, old, cur))
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// StorageClassSpec returns flattened spec of storage class
+func (c *KubeClient) StorageClassSpec(ctx context.Context, name string) (ClassSpec, error) {
+	sc, err := c.ClientSet.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storageClassSpec(sc), nil
+}
+
+func storageClassSpec(sc *storagev1.StorageClass) ClassSpec {
+	spec := ClassSpec{"provisioner": sc.Provisioner}
+	if sc.ReclaimPolicy != nil {
+		spec["reclaimPolicy"] = string(*sc.ReclaimPolicy)
+	}
+	if sc.VolumeBindingMode != nil {
+		spec["volumeBindingMode"] = string(*sc.VolumeBindingMode)
+	}
+	if sc.AllowVolumeExpansion != nil {
+		spec["allowVolumeExpansion"] = strconv.FormatBool(*sc.AllowVolumeExpansion)
+	}
+	if len(sc.MountOptions) != 0 {
+		spec["mountOptions"] = strings.Join(sc.MountOptions, ",")
+	}
+	if len(sc.AllowedTopologies) != 0 {
+		topologies, _ := json.Marshal(sc.AllowedTopologies)
+		spec["allowedTopologies"] = string(topologies)
+	}
+	for k, v := range sc.Parameters {
+		spec["parameters."+k] = v
+	}
+	return spec
+}
+
+// SnapshotClassSpec returns flattened spec of volume snapshot class
+func (c *KubeClient) SnapshotClassSpec(ctx context.Context, name string) (ClassSpec, error) {
+	api, err := c.SnapshotAPI()
+	if err != nil {
+		return nil, err
+	}
+	if api == SnapshotAPINone {
+		return nil, ErrNoSnapshotAPI
+	}
+	cset, err := snapclient.NewForConfig(c.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := ClassSpec{}
+	var parameters map[string]string
+	if api == SnapshotAPIV1 {
+		vsc, err := cset.SnapshotV1().VolumeSnapshotClasses().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec["driver"] = vsc.Driver
+		spec["deletionPolicy"] = string(vsc.DeletionPolicy)
+		parameters = vsc.Parameters
+	} else {
+		vsc, err := cset.SnapshotV1beta1().VolumeSnapshotClasses().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec["driver"] = vsc.Driver
+		spec["deletionPolicy"] = string(vsc.DeletionPolicy)
+		parameters = vsc.Parameters
+	}
+	for k, v := range parameters {
+		spec["parameters."+k] = v
+	}
+	return spec, nil
+}
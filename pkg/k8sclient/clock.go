@@ -0,0 +1,65 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClockSkewSamples is how many dry-run requests clock skew is estimated from, they are spread over a second
+var ClockSkewSamples = 5
+
+// ClockSkew estimates how far API server clock is ahead of clock of this host from creation timestamps API server
+// gives dry-run config maps in namespace. Timestamps have second precision, so every sample bounds skew to a window
+// a second wide, windows of samples taken at different fractions of a second are intersected
+func (c *KubeClient) ClockSkew(ctx context.Context, namespace string) (time.Duration, error) {
+	lower, upper := time.Duration(math.MinInt64), time.Duration(math.MaxInt64)
+	cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{GenerateName: "cert-csi-clock-"}}
+	for i := 0; i < ClockSkewSamples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(time.Second / time.Duration(ClockSkewSamples)):
+			}
+		}
+		before := time.Now()
+		created, err := c.ClientSet.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		after := time.Now()
+		if err != nil {
+			return 0, err
+		}
+		stamped := created.CreationTimestamp.Time
+		if stamped.IsZero() {
+			return 0, errors.New("API server didn't set creation timestamp")
+		}
+		// Object was stamped between before and after on this host, server truncated its own time to seconds
+		lower = max(lower, stamped.Sub(after))
+		upper = min(upper, stamped.Add(time.Second).Sub(before))
+	}
+	if lower > upper {
+		// Windows don't overlap only if one of the clocks jumped while sampling
+		return lower, nil
+	}
+	return lower + (upper-lower)/2, nil
+}
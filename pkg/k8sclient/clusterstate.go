@@ -0,0 +1,230 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// volumeSnapshotResource is GA volume snapshot API captured objects are listed with
+var volumeSnapshotResource = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"}
+
+// capturedObject is an object of cluster state snapshot
+type capturedObject struct {
+	kind string
+	name string
+	uid  types.UID
+	obj  interface{}
+}
+
+// capturedEvent is a condensed event about captured object
+type capturedEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	From    string    `json:"from,omitempty"`
+	Count   int32     `json:"count,omitempty"`
+	Message string    `json:"message"`
+}
+
+// CaptureState saves cluster state of namespace at the time of failure into dir: PVCs, pods and volume snapshots
+// of namespace, PVs bound to the PVCs and volume attachments of the PVs, each along with its events into
+// <kind>-<name>.yaml. Objects which can't be read, e.g. cluster-scoped ones in restricted mode, are skipped and
+// reported with error. Snapshots aren't captured if dynamicClient is nil. Paths of written files are returned
+func CaptureState(ctx context.Context, clientSet kubernetes.Interface, dynamicClient dynamic.Interface, namespace, dir string) ([]string, error) {
+	var objects []capturedObject
+	var errs []error
+
+	pvs := make(map[string]bool)
+	pvcs, err := clientSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("can't list PVCs: %w", err))
+	} else {
+		for i := range pvcs.Items {
+			pvc := &pvcs.Items[i]
+			pvc.Kind, pvc.APIVersion = "PersistentVolumeClaim", "v1"
+			objects = append(objects, capturedObject{"pvc", pvc.Name, pvc.UID, pvc})
+			if pvc.Spec.VolumeName != "" {
+				pvs[pvc.Spec.VolumeName] = true
+			}
+		}
+	}
+
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("can't list pods: %w", err))
+	} else {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			pod.Kind, pod.APIVersion = "Pod", "v1"
+			objects = append(objects, capturedObject{"pod", pod.Name, pod.UID, pod})
+		}
+	}
+
+	for _, name := range sortedKeys(pvs) {
+		pv, err := clientSet.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't get PV %s: %w", name, err))
+			continue
+		}
+		pv.Kind, pv.APIVersion = "PersistentVolume", "v1"
+		objects = append(objects, capturedObject{"pv", pv.Name, pv.UID, pv})
+	}
+
+	if len(pvs) != 0 {
+		vas, err := clientSet.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't list volume attachments: %w", err))
+		} else {
+			for i := range vas.Items {
+				va := &vas.Items[i]
+				if va.Spec.Source.PersistentVolumeName == nil || !pvs[*va.Spec.Source.PersistentVolumeName] {
+					continue
+				}
+				va.Kind, va.APIVersion = "VolumeAttachment", "storage.k8s.io/v1"
+				objects = append(objects, capturedObject{"va", va.Name, va.UID, va})
+			}
+		}
+	}
+
+	if dynamicClient != nil {
+		snapshots, err := dynamicClient.Resource(volumeSnapshotResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		// Cluster without snapshot CRDs has no snapshots to capture
+		if err != nil && !apierrs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("can't list volume snapshots: %w", err))
+		} else if err == nil {
+			for i := range snapshots.Items {
+				vs := &snapshots.Items[i]
+				objects = append(objects, capturedObject{"vs", vs.GetName(), vs.GetUID(), vs.Object})
+			}
+		}
+	}
+
+	// Events of cluster-scoped objects are recorded in default namespace
+	eventNamespaces := []string{namespace}
+	if len(pvs) != 0 && namespace != v1.NamespaceDefault {
+		eventNamespaces = append(eventNamespaces, v1.NamespaceDefault)
+	}
+	events := make(map[types.UID][]capturedEvent)
+	for _, ns := range eventNamespaces {
+		list, err := clientSet.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't list events of namespace %s: %w", ns, err))
+			continue
+		}
+		for _, e := range list.Items {
+			events[e.InvolvedObject.UID] = append(events[e.InvolvedObject.UID], condenseEvent(e))
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, o := range objects {
+		path, err := writeCapturedObject(dir, o, events[o.uid])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, errors.Join(errs...)
+}
+
+// condenseEvent keeps fields of event relevant to post-mortem
+func condenseEvent(e v1.Event) capturedEvent {
+	ce := capturedEvent{Type: e.Type, Reason: e.Reason, Count: e.Count, Message: e.Message, From: e.Source.Component}
+	switch {
+	case !e.LastTimestamp.IsZero():
+		ce.Time = e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		ce.Time = e.EventTime.Time
+	default:
+		ce.Time = e.CreationTimestamp.Time
+	}
+	if ce.From == "" {
+		ce.From = e.ReportingController
+	}
+	return ce
+}
+
+// writeCapturedObject writes object and its events, oldest first, as two YAML documents
+func writeCapturedObject(dir string, o capturedObject, events []capturedEvent) (string, error) {
+	if meta, ok := o.obj.(metav1.Object); ok {
+		meta.SetManagedFields(nil)
+	} else if m, ok := o.obj.(map[string]interface{}); ok {
+		if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "managedFields")
+		}
+	}
+	data, err := yaml.Marshal(o.obj)
+	if err != nil {
+		return "", fmt.Errorf("can't marshal %s %s: %w", o.kind, o.name, err)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	if events == nil {
+		events = []capturedEvent{}
+	}
+	eventData, err := yaml.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return "", fmt.Errorf("can't marshal events of %s %s: %w", o.kind, o.name, err)
+	}
+
+	var b strings.Builder
+	b.Write(data)
+	b.WriteString("---\n")
+	b.Write(eventData)
+	path := filepath.Join(dir, o.kind+"-"+o.name+".yaml")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -18,6 +18,7 @@ package k8sclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"regexp"
@@ -32,10 +33,12 @@ import (
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pv"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pvc"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/referencegrant"
 	rg "github.com/dell/cert-csi/pkg/k8sclient/resources/replicationgroup"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/sc"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/statefulset"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/va"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/velero"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/volumegroupsnapshot"
 	snapv1 "github.com/dell/cert-csi/pkg/k8sclient/resources/volumesnapshot/v1"
 	snapbeta "github.com/dell/cert-csi/pkg/k8sclient/resources/volumesnapshot/v1beta1"
@@ -51,6 +54,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
@@ -99,8 +103,27 @@ type KubeClient struct {
 	timeout     int
 	mutex       sync.Mutex
 	Minor       int
+
+	snapshotAPI *SnapshotAPIVersion
+	// ExtraMetadata is added to PVCs and pods created by clients, nil if there is none
+	ExtraMetadata *ExtraMetadata
 }
 
+// SnapshotAPIVersion is a version of snapshot.storage.k8s.io API group served by cluster
+type SnapshotAPIVersion string
+
+const (
+	// SnapshotAPINone means snapshot CRDs are not installed in cluster
+	SnapshotAPINone SnapshotAPIVersion = ""
+	// SnapshotAPIV1 is GA snapshot API
+	SnapshotAPIV1 SnapshotAPIVersion = "v1"
+	// SnapshotAPIV1beta1 is legacy snapshot API still used by older distributions
+	SnapshotAPIV1beta1 SnapshotAPIVersion = "v1beta1"
+)
+
+// ErrNoSnapshotAPI is returned when snapshot clients are requested from cluster without snapshot CRDs
+var ErrNoSnapshotAPI = errors.New("snapshot.storage.k8s.io CRDs are not installed in cluster")
+
 // Clients contains client handles for K8s resources
 type Clients struct {
 	PVCClient              *pvc.Client
@@ -115,6 +138,8 @@ type Clients struct {
 	SCClient               *sc.Client
 	RgClient               *rg.Client
 	VgsClient              *volumegroupsnapshot.Client
+	VeleroClient           *velero.Client
+	RefGrantClient         *referencegrant.Client
 	KubeClient             *KubeClient
 	CSISCClient            *csistoragecapacity.Client
 }
@@ -200,6 +225,10 @@ func (c *KubeClient) CreatePVCClient(namespace string) (*pvc.Client, error) {
 		Namespace: namespace,
 		Timeout:   c.timeout,
 	}
+	if c.ExtraMetadata != nil {
+		pvcc.ExtraAnnotations = c.ExtraMetadata.PVC.Annotations
+		pvcc.ExtraLabels = c.ExtraMetadata.PVC.Labels
+	}
 	logrus.Debugf("Created PersistentVolumeClaim client in %s namespace", namespace)
 	return pvcc, nil
 }
@@ -229,6 +258,7 @@ func (c *KubeClient) CreatePVClient() (*pv.Client, error) {
 func (c *KubeClient) CreateNodeClient() (*node.Client, error) {
 	node := &node.Client{
 		Interface: c.ClientSet.CoreV1().Nodes(),
+		ClientSet: c.ClientSet,
 		Timeout:   c.timeout,
 	}
 	logrus.Debugf("Created NodeClient ")
@@ -244,6 +274,10 @@ func (c *KubeClient) CreatePodClient(namespace string) (*pod.Client, error) {
 		Namespace: namespace,
 		Timeout:   c.timeout,
 	}
+	if c.ExtraMetadata != nil {
+		podc.ExtraAnnotations = c.ExtraMetadata.Pod.Annotations
+		podc.ExtraLabels = c.ExtraMetadata.Pod.Labels
+	}
 	logrus.Debugf("Created Pod client in %s namespace", namespace)
 	return podc, nil
 }
@@ -274,6 +308,16 @@ func (c *KubeClient) CreateMetricsClient(namespace string) (*metrics.Client, err
 	return mc, nil
 }
 
+// CreateDynamicClient creates a client of arbitrary resources, e.g. driver CRs suites wait for
+func (c *KubeClient) CreateDynamicClient() (dynamic.Interface, error) {
+	client, err := dynamic.NewForConfig(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Debugf("Created new dynamic client")
+	return client, nil
+}
+
 // CreateRGClient creates a new instance of replication group client
 func (c *KubeClient) CreateRGClient() (*rg.Client, error) {
 	scheme := runtime.NewScheme()
@@ -320,6 +364,40 @@ func (c *KubeClient) CreateVGSClient() (*volumegroupsnapshot.Client, error) {
 	return vgs, nil
 }
 
+// CreateVeleroClient creates a new instance of Velero client managing backups in the namespace Velero is installed to
+func (c *KubeClient) CreateVeleroClient(namespace string) (*velero.Client, error) {
+	scheme := runtime.NewScheme()
+
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	k8sClient, err := client.New(c.Config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	vc := &velero.Client{
+		Interface: k8sClient,
+		Namespace: namespace,
+		Timeout:   c.timeout,
+	}
+
+	logrus.Debugf("Created new Velero client")
+
+	return vc, nil
+}
+
+// CreateReferenceGrantClient creates a new instance of ReferenceGrant client
+func (c *KubeClient) CreateReferenceGrantClient() (*referencegrant.Client, error) {
+	k8sClient, err := client.New(c.Config, client.Options{Scheme: runtime.NewScheme()})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("Created new ReferenceGrant client")
+
+	return &referencegrant.Client{Interface: k8sClient}, nil
+}
+
 // CreateSnapshotGAClient creates a new instance of snapshot client
 func (c *KubeClient) CreateSnapshotGAClient(namespace string) (*snapv1.SnapshotClient, error) {
 	cset, err := snapclient.NewForConfig(c.Config)
@@ -333,7 +411,7 @@ func (c *KubeClient) CreateSnapshotGAClient(namespace string) (*snapv1.SnapshotC
 		Timeout:   c.timeout,
 	}
 
-	logrus.Debugf("Created Alpha Snapshot client in %s namespace", namespace)
+	logrus.Debugf("Created GA Snapshot client in %s namespace", namespace)
 	return sc, nil
 }
 
@@ -416,30 +494,41 @@ func (c *KubeClient) CreateNamespace(ctx context.Context, namespace string) (*v1
 	return ns, nil
 }
 
-// CreateNamespaceWithSuffix creates new namespace with provided name and appends random suffix
+// CreateNamespaceWithSuffix creates new namespace with provided name and appends suffix of the name generator,
+// suffix of a namespace which already exists, ex. left by another run, is regenerated
 func (c *KubeClient) CreateNamespaceWithSuffix(ctx context.Context, namespace string) (*v1.Namespace, error) {
-	suffix := RandomSuffix()
-	ns, err := c.CreateNamespace(ctx, namespace+"-"+suffix)
-	if err != nil {
-		return nil, err
+	for attempt := 1; ; attempt++ {
+		ns, err := c.CreateNamespace(ctx, GenerateName(namespace+"-"))
+		if !apierrs.IsAlreadyExists(err) || attempt == maxNameAttempts {
+			return ns, err
+		}
+		nameTaken()
 	}
-	return ns, nil
 }
 
 // DeleteNamespace deletes all resources inside namespace, and waits for termination
 func (c *KubeClient) DeleteNamespace(ctx context.Context, namespace string) error {
+	return c.deleteNamespace(ctx, namespace, c.namespaceTimeout())
+}
+
+// namespaceTimeout returns how long namespace operations are waited for
+func (c *KubeClient) namespaceTimeout() time.Duration {
+	if c.Timeout() != 0 {
+		return time.Duration(c.Timeout()) * time.Second
+	}
+	return NamespaceTimeout
+}
+
+// deleteNamespace deletes namespace and waits for termination, namespace still there after timeout is force deleted
+func (c *KubeClient) deleteNamespace(ctx context.Context, namespace string, timeout time.Duration) error {
 	log := utils.GetLoggerFromContext(ctx)
 	startTime := time.Now()
 	if err := c.ClientSet.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{}); err != nil {
 		return err
 	}
-	timeout := NamespaceTimeout
-	if c.Timeout() != 0 {
-		timeout = time.Duration(c.Timeout()) * time.Second
-	}
 
-	pollErr := wait.PollImmediate(NamespacePoll, timeout,
-		func() (bool, error) {
+	pollErr := wait.PollUntilContextTimeout(ctx, NamespacePoll, timeout, true,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Namespace deletion interrupted")
@@ -515,8 +604,12 @@ func (c *KubeClient) ForceDeleteNamespace(ctx context.Context, namespace string)
 	}
 	logrus.Debugf("All Pods are gone")
 
-	if c.Minor >= 17 {
-		logrus.Debug("Beta here")
+	api, err := c.SnapshotAPI()
+	if err != nil {
+		return err
+	}
+	switch api {
+	case SnapshotAPIV1beta1:
 		k8sbeta, err := c.CreateSnapshotBetaClient(namespace)
 		if err != nil {
 			return err
@@ -536,14 +629,13 @@ func (c *KubeClient) ForceDeleteNamespace(ctx context.Context, namespace string)
 			return err
 		}
 		logrus.Debugf("All VSConts are gone")
-	} else {
-		logrus.Debug("Alpha here")
-		k8salpha, err := c.CreateSnapshotGAClient(namespace)
+	case SnapshotAPIV1:
+		k8sga, err := c.CreateSnapshotGAClient(namespace)
 		if err != nil {
 			return err
 		}
-		err = k8salpha.DeleteAll(ctx)
-		if err != nil {
+		err = k8sga.DeleteAll(ctx)
+		if err != nil && !apierrs.IsNotFound(err) {
 			return err
 		}
 		logrus.Debugf("All VS's are gone")
@@ -556,6 +648,8 @@ func (c *KubeClient) ForceDeleteNamespace(ctx context.Context, namespace string)
 			return err
 		}
 		logrus.Debugf("All VSConts are gone")
+	default:
+		logrus.Debugf("Snapshot CRDs are not installed, skipping VS cleanup")
 	}
 
 	pvcClient, err := c.CreatePVCClient(namespace)
@@ -584,18 +678,87 @@ func (c *KubeClient) ForceDeleteNamespace(ctx context.Context, namespace string)
 	return nil
 }
 
+// SnapshotAPI detects which version of snapshot API is served by cluster, preferring v1 over v1beta1.
+// SnapshotAPINone is returned when neither is served, successful detection is cached for lifetime of client.
+func (c *KubeClient) SnapshotAPI() (SnapshotAPIVersion, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.snapshotAPI != nil {
+		return *c.snapshotAPI, nil
+	}
+
+	detected := SnapshotAPINone
+	for _, version := range []SnapshotAPIVersion{SnapshotAPIV1, SnapshotAPIV1beta1} {
+		resources, err := c.ClientSet.Discovery().ServerResourcesForGroupVersion("snapshot.storage.k8s.io/" + string(version))
+		if err != nil {
+			if apierrs.IsNotFound(err) {
+				continue
+			}
+			return SnapshotAPINone, fmt.Errorf("can't discover snapshot API: %v", err)
+		}
+		if hasResource(resources, "volumesnapshots") {
+			detected = version
+			break
+		}
+	}
+
+	if detected == SnapshotAPINone {
+		logrus.Warn("Snapshot CRDs are not installed, snapshot functionality is disabled")
+	} else {
+		logrus.Debugf("Using snapshot.storage.k8s.io/%s API", detected)
+	}
+	c.snapshotAPI = &detected
+	return detected, nil
+}
+
+func hasResource(resources *metav1.APIResourceList, name string) bool {
+	for _, r := range resources.APIResources {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSnapshotClients creates snapshot client for API version served by cluster, only one of returned clients is set
+func (c *KubeClient) CreateSnapshotClients(namespace string) (*snapv1.SnapshotClient, *snapbeta.SnapshotClient, error) {
+	api, err := c.SnapshotAPI()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch api {
+	case SnapshotAPIV1:
+		gaClient, err := c.CreateSnapshotGAClient(namespace)
+		return gaClient, nil, err
+	case SnapshotAPIV1beta1:
+		betaClient, err := c.CreateSnapshotBetaClient(namespace)
+		return nil, betaClient, err
+	default:
+		return nil, nil, ErrNoSnapshotAPI
+	}
+}
+
 // SnapshotClassExists checks whether snapshot class exists
 func (c *KubeClient) SnapshotClassExists(snapClass string) (bool, error) {
-	cset, err := snapclient.NewForConfig(c.Config)
+	api, err := c.SnapshotAPI()
 	if err != nil {
 		return false, err
 	}
-	_, err = cset.SnapshotV1beta1().VolumeSnapshotClasses().Get(context.Background(), snapClass, metav1.GetOptions{})
+	if api == SnapshotAPINone {
+		return false, ErrNoSnapshotAPI
+	}
+
+	cset, err := snapclient.NewForConfig(c.Config)
 	if err != nil {
+		return false, err
+	}
+	if api == SnapshotAPIV1 {
 		_, err = cset.SnapshotV1().VolumeSnapshotClasses().Get(context.Background(), snapClass, metav1.GetOptions{})
-		if err != nil {
-			return false, err
-		}
+	} else {
+		_, err = cset.SnapshotV1beta1().VolumeSnapshotClasses().Get(context.Background(), snapClass, metav1.GetOptions{})
+	}
+	if err != nil {
+		return false, err
 	}
 
 	return true, nil
@@ -663,6 +826,12 @@ func GetConfig(configPath string) (*rest.Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("can't get config from specified file; %e", err)
 	}
+	if Audit != nil {
+		config.Wrap(Audit.Wrap)
+	}
+	if Created != nil {
+		config.Wrap(Created.Wrap)
+	}
 	logrus.Infof("Successfully loaded config. Host: %s", color.CyanString(config.Host))
 	return config, nil
 }
@@ -18,16 +18,27 @@ package k8sclient
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 type CoreTestSuite struct {
@@ -104,6 +115,83 @@ func (suite *CoreTestSuite) TestCreateClients() {
 	})
 }
 
+func (suite *CoreTestSuite) TestNameGenerator() {
+	defer SetNameGenerator(randomNames{})
+
+	_, err := NewNameGenerator("uuid")
+	suite.Error(err)
+
+	suite.Run("incremental", func() {
+		g, err := NewNameGenerator("incremental")
+		suite.NoError(err)
+		SetNameGenerator(g)
+		suite.Equal("pvc-0001", GenerateName("pvc-"))
+		suite.Equal("pvc-0002", GenerateName("pvc-"))
+		suite.Equal("pod-0001", GenerateName("pod-"))
+	})
+
+	suite.Run("ulid", func() {
+		g, err := NewNameGenerator(ULIDNames)
+		suite.NoError(err)
+		first, second := g.Suffix(""), g.Suffix("")
+		suite.Len(first, 26)
+		suite.Regexp("^[0-9a-z]+$", first)
+		suite.NotEqual(first, second)
+	})
+
+	suite.Run("collisions are regenerated", func() {
+		g, err := NewNameGenerator(WordNames)
+		suite.NoError(err)
+		SetNameGenerator(g)
+		SetSeed(7)
+		before := NameCollisions()
+		names := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			name := GenerateName("sc-")
+			suite.False(names[name], name)
+			names[name] = true
+		}
+		suite.Greater(NameCollisions(), before)
+
+		ResetNames()
+		suite.Zero(NameCollisions())
+		suite.Empty(issuedNames)
+	})
+
+	suite.Run("taken namespace", func() {
+		g, err := NewNameGenerator(IncrementalNames)
+		suite.NoError(err)
+		SetNameGenerator(g)
+		client := &KubeClient{ClientSet: fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "vio-0001"}})}
+		before := NameCollisions()
+		ns, err := client.CreateNamespaceWithSuffix(context.Background(), "vio")
+		suite.NoError(err)
+		suite.Equal("vio-0002", ns.Name)
+		suite.Equal(before+1, NameCollisions())
+	})
+}
+
+func (suite *CoreTestSuite) TestClockSkew() {
+	defer func(samples int) { ClockSkewSamples = samples }(ClockSkewSamples)
+	ClockSkewSamples = 10
+
+	// API server clock is 3.4s ahead and truncates timestamps to seconds
+	clientSet := fake.NewSimpleClientset()
+	clientSet.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		cm := action.(k8stesting.CreateAction).GetObject().(*v1.ConfigMap).DeepCopy()
+		cm.CreationTimestamp = metav1.NewTime(time.Now().Add(3400 * time.Millisecond).Truncate(time.Second))
+		return true, cm, nil
+	})
+	client := &KubeClient{ClientSet: clientSet}
+	skew, err := client.ClockSkew(context.Background(), "default")
+	suite.NoError(err)
+	suite.InDelta(float64(3400*time.Millisecond), float64(skew), float64(150*time.Millisecond))
+
+	// Fake client doesn't set timestamps
+	_, err = suite.kubeClient.ClockSkew(context.Background(), "default")
+	suite.Error(err)
+}
+
 func (suite *CoreTestSuite) TestCreateNamespace() {
 	type fields struct {
 		ClientSet   kubernetes.Interface
@@ -244,6 +332,127 @@ func (suite *CoreTestSuite) TestStorageExists() {
 	suite.Equal(true, exists)
 }
 
+func (suite *CoreTestSuite) TestStorageClassSpec() {
+	expansion := true
+	_, err := suite.kubeClient.ClientSet.StorageV1().StorageClasses().Create(context.Background(), &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "guarded"},
+		Provisioner:          "csi-powerstore.dellemc.com",
+		AllowVolumeExpansion: &expansion,
+		MountOptions:         []string{"noatime"},
+		Parameters:           map[string]string{"csi.storage.k8s.io/fstype": "ext4"},
+	}, metav1.CreateOptions{})
+	suite.NoError(err)
+
+	spec, err := suite.kubeClient.StorageClassSpec(context.Background(), "guarded")
+	suite.NoError(err)
+	suite.Equal(ClassSpec{
+		"provisioner":                          "csi-powerstore.dellemc.com",
+		"allowVolumeExpansion":                 "true",
+		"mountOptions":                         "noatime",
+		"parameters.csi.storage.k8s.io/fstype": "ext4",
+	}, spec)
+
+	updated := ClassSpec{
+		"provisioner":                          "csi-powerstore.dellemc.com",
+		"allowVolumeExpansion":                 "false",
+		"parameters.csi.storage.k8s.io/fstype": "xfs",
+		"reclaimPolicy":                        "Retain",
+	}
+	suite.Equal([]string{
+		`allowVolumeExpansion: "true" -> "false"`,
+		`mountOptions: "noatime" removed`,
+		`parameters.csi.storage.k8s.io/fstype: "ext4" -> "xfs"`,
+		`reclaimPolicy: "Retain" added`,
+	}, spec.Diff(updated))
+	suite.Empty(spec.Diff(spec))
+
+	_, err = suite.kubeClient.StorageClassSpec(context.Background(), "missing")
+	suite.Error(err)
+}
+
+func (suite *CoreTestSuite) TestDriverCapabilities() {
+	attach := false
+	fsGroupPolicy := storagev1.FileFSGroupPolicy
+	binding := storagev1.VolumeBindingWaitForFirstConsumer
+	client := fake.NewSimpleClientset(
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "registered"}, Provisioner: "csi-unity.dellemc.com", VolumeBindingMode: &binding},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "unregistered"}, Provisioner: "csi-isilon.dellemc.com"},
+		&storagev1.CSIDriver{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-unity.dellemc.com"},
+			Spec: storagev1.CSIDriverSpec{
+				AttachRequired:       &attach,
+				FSGroupPolicy:        &fsGroupPolicy,
+				VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecyclePersistent, storagev1.VolumeLifecycleEphemeral},
+			},
+		},
+	)
+	kubeClient := &KubeClient{ClientSet: client, Config: &rest.Config{}}
+
+	caps, err := kubeClient.DriverCapabilities(context.Background(), "registered")
+	suite.NoError(err)
+	suite.Equal(&DriverCapabilities{
+		Driver:               "csi-unity.dellemc.com",
+		Registered:           true,
+		VolumeLifecycleModes: []string{"Persistent", "Ephemeral"},
+		FSGroupPolicy:        "File",
+		VolumeBindingMode:    "WaitForFirstConsumer",
+	}, caps)
+	suite.True(caps.DeclaresLifecycleMode(storagev1.VolumeLifecycleEphemeral))
+
+	// Drivers without CSIDriver object get Kubernetes defaults
+	caps, err = kubeClient.DriverCapabilities(context.Background(), "unregistered")
+	suite.NoError(err)
+	suite.False(caps.Registered)
+	suite.True(caps.AttachRequired)
+	suite.Equal([]string{"Persistent"}, caps.VolumeLifecycleModes)
+	suite.Equal("ReadWriteOnceWithFSType", caps.FSGroupPolicy)
+	suite.False(caps.DeclaresLifecycleMode(storagev1.VolumeLifecycleEphemeral))
+
+	_, err = kubeClient.DriverCapabilities(context.Background(), "missing")
+	suite.Error(err)
+
+	// Dual-stack nodes declare both families, addresses other than internal ones are ignored
+	_, err = client.CoreV1().Nodes().Create(context.Background(), &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "dual-stack"},
+		Status: v1.NodeStatus{Addresses: []v1.NodeAddress{
+			{Type: v1.NodeInternalIP, Address: "fd00::10"},
+			{Type: v1.NodeInternalIP, Address: "10.0.0.10"},
+			{Type: v1.NodeHostName, Address: "dual-stack"},
+		}},
+	}, metav1.CreateOptions{})
+	suite.NoError(err)
+	caps, err = kubeClient.DriverCapabilities(context.Background(), "registered")
+	suite.NoError(err)
+	suite.Equal([]string{"IPv4", "IPv6"}, caps.IPFamilies)
+	suite.Equal(v1.IPv6Protocol, IPFamilyOf("fd00::10"))
+	suite.Equal(v1.IPFamily(""), IPFamilyOf("node-1"))
+}
+
+func (suite *CoreTestSuite) TestCapabilitiesUnexpected() {
+	yes, no := true, false
+	caps := &DriverCapabilities{
+		Driver:               "csi-powerstore.dellemc.com",
+		AttachRequired:       true,
+		VolumeLifecycleModes: []string{"Persistent"},
+		FSGroupPolicy:        "ReadWriteOnceWithFSType",
+		FSType:               "btrfs",
+	}
+	suite.Nil(caps.Unexpected())
+
+	caps.Expected = &CapabilityExpectations{Profile: "powerstore", Driver: "csi-powerstore.dellemc.com", AttachRequired: &yes}
+	suite.Empty(caps.Unexpected())
+
+	caps.Expected = &CapabilityExpectations{
+		Profile:              "powerstore",
+		Driver:               "csi-powerstore.dellemc.com",
+		AttachRequired:       &no,
+		VolumeLifecycleModes: []string{"Persistent", "Ephemeral"},
+		AllowVolumeExpansion: &yes,
+		FSTypes:              []string{"ext4", "xfs"},
+	}
+	suite.Equal([]string{"attachRequired", "volumeLifecycleModes", "allowVolumeExpansion", "fsType"}, caps.Unexpected())
+}
+
 func (suite *CoreTestSuite) TestNamespaceExists() {
 	client := fake.NewSimpleClientset()
 
@@ -267,6 +476,47 @@ func (suite *CoreTestSuite) TestNamespaceExists() {
 	suite.Equal(true, exists)
 }
 
+func (suite *CoreTestSuite) TestSnapshotAPI() {
+	newClient := func(groupVersions ...string) *KubeClient {
+		client := fake.NewSimpleClientset()
+		for _, gv := range groupVersions {
+			client.Resources = append(client.Resources, &metav1.APIResourceList{
+				GroupVersion: gv,
+				APIResources: []metav1.APIResource{{Name: "volumesnapshots", Namespaced: true, Kind: "VolumeSnapshot"}},
+			})
+		}
+		return &KubeClient{ClientSet: client, Config: &rest.Config{}, timeout: 1}
+	}
+
+	suite.Run("v1 preferred", func() {
+		api, err := newClient("snapshot.storage.k8s.io/v1beta1", "snapshot.storage.k8s.io/v1").SnapshotAPI()
+		suite.NoError(err)
+		suite.Equal(SnapshotAPIV1, api)
+	})
+
+	suite.Run("legacy v1beta1", func() {
+		kubeClient := newClient("snapshot.storage.k8s.io/v1beta1")
+		api, err := kubeClient.SnapshotAPI()
+		suite.NoError(err)
+		suite.Equal(SnapshotAPIV1beta1, api)
+
+		ga, beta, err := kubeClient.CreateSnapshotClients("test-namespace")
+		suite.NoError(err)
+		suite.Nil(ga)
+		suite.NotNil(beta)
+	})
+
+	suite.Run("no CRDs", func() {
+		kubeClient := newClient()
+		api, err := kubeClient.SnapshotAPI()
+		suite.NoError(err)
+		suite.Equal(SnapshotAPINone, api)
+
+		_, _, err = kubeClient.CreateSnapshotClients("test-namespace")
+		suite.ErrorIs(err, ErrNoSnapshotAPI)
+	})
+}
+
 func (suite *CoreTestSuite) TestGetConfig() {
 	conf, err := GetConfig("testdata/config")
 	suite.NoError(err)
@@ -277,6 +527,313 @@ func (suite *CoreTestSuite) TestGetConfig() {
 	suite.Nil(errConf)
 }
 
+func (suite *CoreTestSuite) TestRandomSuffix() {
+	suite.Run("same seed", func() {
+		SetSeed(42)
+		first := []string{RandomSuffix(), RandomSuffix()}
+		n := RandomIntn(100)
+		SetSeed(42)
+		suite.Equal(first, []string{RandomSuffix(), RandomSuffix()})
+		suite.Equal(n, RandomIntn(100))
+	})
+
+	suite.Run("unique suffix ignores seed", func() {
+		SetSeed(42)
+		first := UniqueSuffix()
+		SetSeed(42)
+		suite.NotEqual(first, UniqueSuffix())
+	})
+}
+
+func (suite *CoreTestSuite) TestPermissionAudit() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+	}))
+	defer server.Close()
+
+	audit := NewPermissionAudit()
+	config := &rest.Config{Host: server.URL}
+	config.Wrap(audit.Wrap)
+	clientset, err := kubernetes.NewForConfig(config)
+	suite.NoError(err)
+	_, _ = clientset.CoreV1().Pods("ns-1").Get(context.Background(), "pod", metav1.GetOptions{})
+	_, _ = clientset.CoreV1().Pods("ns-1").List(context.Background(), metav1.ListOptions{})
+	_ = clientset.StorageV1().VolumeAttachments().Delete(context.Background(), "va", metav1.DeleteOptions{})
+
+	for _, r := range []struct{ method, url string }{
+		{http.MethodGet, "/api/v1/namespaces/ns-2/pods?watch=true"},
+		{http.MethodPost, "/api/v1/namespaces/ns-2/pods/pod/exec?command=ls"},
+		{http.MethodPost, "/api/v1/namespaces"},
+		{http.MethodPatch, "/api/v1/namespaces/ns-2/persistentvolumeclaims/pvc"},
+		{http.MethodPut, "/apis/snapshot.storage.k8s.io/v1/namespaces/ns-2/volumesnapshots/snap/status"},
+		{http.MethodGet, "/version"},
+		{http.MethodGet, "/apis/snapshot.storage.k8s.io/v1"},
+	} {
+		u, err := url.Parse(r.url)
+		suite.NoError(err)
+		audit.Record(r.method, u)
+	}
+
+	suite.Equal([]Permission{
+		{Resource: "namespaces", Verb: "create"},
+		{Resource: "persistentvolumeclaims", Verb: "patch", Namespaced: true},
+		{Resource: "pods", Verb: "get", Namespaced: true},
+		{Resource: "pods", Verb: "list", Namespaced: true},
+		{Resource: "pods", Verb: "watch", Namespaced: true},
+		{Resource: "pods/exec", Verb: "create", Namespaced: true},
+		{Group: "snapshot.storage.k8s.io", Resource: "volumesnapshots/status", Verb: "update", Namespaced: true},
+		{Group: "storage.k8s.io", Resource: "volumeattachments", Verb: "delete"},
+		{NonResourceURL: "/apis/snapshot.storage.k8s.io/v1", Verb: "get"},
+		{NonResourceURL: "/version", Verb: "get"},
+	}, audit.Permissions())
+	suite.Equal([]string{"ns-1", "ns-2"}, audit.Namespaces())
+
+	role := audit.ClusterRole("cert-csi")
+	suite.Equal("cert-csi", role.Name)
+	suite.Equal([]rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"namespaces", "pods/exec"}, Verbs: []string{"create"}},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{""}, Resources: []string{"persistentvolumeclaims"}, Verbs: []string{"patch"}},
+		{APIGroups: []string{"snapshot.storage.k8s.io"}, Resources: []string{"volumesnapshots/status"}, Verbs: []string{"update"}},
+		{APIGroups: []string{"storage.k8s.io"}, Resources: []string{"volumeattachments"}, Verbs: []string{"delete"}},
+		{NonResourceURLs: []string{"/apis/snapshot.storage.k8s.io/v1", "/version"}, Verbs: []string{"get"}},
+	}, role.Rules)
+
+	path := filepath.Join(suite.T().TempDir(), "role.yaml")
+	suite.NoError(audit.WriteRole(path, "cert-csi"))
+	data, err := os.ReadFile(path)
+	suite.NoError(err)
+	suite.Contains(string(data), "# Namespaces accessed: ns-1, ns-2")
+	suite.Contains(string(data), "kind: ClusterRole")
+}
+
+func (suite *CoreTestSuite) TestCreateCounter() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/namespaces") {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write([]byte(`{"kind":"Namespace","apiVersion":"v1"}`))
+	}))
+	defer server.Close()
+
+	counter := NewCreateCounter()
+	config := &rest.Config{Host: server.URL}
+	config.Wrap(counter.Wrap)
+	clientset, err := kubernetes.NewForConfig(config)
+	suite.NoError(err)
+	for i := 0; i < 2; i++ {
+		_, err = clientset.CoreV1().Namespaces().Create(context.Background(), &v1.Namespace{}, metav1.CreateOptions{})
+		suite.NoError(err)
+	}
+	_, _ = clientset.CoreV1().Namespaces().Get(context.Background(), "ns", metav1.GetOptions{})
+	// Subresources aren't objects
+	_ = clientset.CoreV1().Pods("ns").EvictV1(context.Background(), &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: "pod"}})
+	suite.Equal(int64(2), counter.Count())
+
+	var nilCounter *CreateCounter
+	suite.Zero(nilCounter.Count())
+}
+
+func (suite *CoreTestSuite) TestParseTierTimeouts() {
+	timeouts, err := ParseTierTimeouts("pods=2m, pvcs=30s")
+	suite.NoError(err)
+	suite.Equal(TierTimeouts{TierPods: 2 * time.Minute, TierClaims: 30 * time.Second}, timeouts)
+
+	_, err = ParseTierTimeouts("volumes=1m")
+	suite.Error(err)
+	_, err = ParseTierTimeouts("pods")
+	suite.Error(err)
+	_, err = ParseTierTimeouts("pods=-1s")
+	suite.Error(err)
+}
+
+func (suite *CoreTestSuite) TestRunTiers() {
+	pollBefore := TeardownTierPoll
+	TeardownTierPoll = 10 * time.Millisecond
+	defer func() { TeardownTierPoll = pollBefore }()
+
+	var order []string
+	pods, claims := 2, 1
+	tiers := []teardownTier{
+		{
+			name:   TierPods,
+			delete: func(context.Context) error { order = append(order, TierPods); pods = 0; return nil },
+			count:  func(context.Context) (int, error) { return pods, nil },
+		},
+		{
+			// Claim stuck on finalizer is never gone
+			name:   TierClaims,
+			delete: func(context.Context) error { order = append(order, TierClaims); return nil },
+			count:  func(context.Context) (int, error) { return claims, nil },
+		},
+		{
+			name:   TierSnapshots,
+			delete: func(context.Context) error { order = append(order, TierSnapshots); return nil },
+			count:  func(context.Context) (int, error) { return 0, nil },
+		},
+	}
+
+	results := runTiers(context.Background(), tiers, TierTimeouts{TierClaims: 50 * time.Millisecond}, time.Second)
+	suite.Equal([]string{TierPods, TierClaims}, order)
+	suite.Len(results, 3)
+	suite.Equal(2, results[0].Deleted)
+	suite.NoError(results[0].Err)
+	suite.Equal(1, results[1].Remaining)
+	suite.Error(results[1].Err)
+	suite.Equal(0, results[2].Deleted)
+}
+
+func (suite *CoreTestSuite) TestTempClasses() {
+	ctx := context.Background()
+	wffc := storagev1.VolumeBindingWaitForFirstConsumer
+	client := fake.NewSimpleClientset(
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "powerstore"},
+			Provisioner: "csi-powerstore.dellemc.com",
+			Parameters:  map[string]string{"arrayID": "PS1", "csi.storage.k8s.io/fstype": "ext4"},
+		},
+		&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "leftover", Labels: map[string]string{TempClassLabel: "true"}},
+		},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "production"}},
+	)
+	tc := &TempClasses{clientSet: client}
+
+	err := tc.Create(ctx, &ClassTemplates{StorageClasses: []StorageClassTemplate{
+		{Name: "powerstore-xfs", Parameters: map[string]string{"csi.storage.k8s.io/fstype": "xfs", "arrayID": ""}},
+		{Name: "leftover", From: "powerstore", VolumeBindingMode: &wffc},
+	}}, "powerstore")
+	suite.NoError(err)
+	suite.Equal([]string{"powerstore-xfs", "leftover"}, tc.StorageClasses())
+
+	xfs, err := client.StorageV1().StorageClasses().Get(ctx, "powerstore-xfs", metav1.GetOptions{})
+	suite.NoError(err)
+	suite.Equal("csi-powerstore.dellemc.com", xfs.Provisioner)
+	suite.Equal(map[string]string{"csi.storage.k8s.io/fstype": "xfs"}, xfs.Parameters)
+	suite.Equal("true", xfs.Labels[TempClassLabel])
+	replaced, err := client.StorageV1().StorageClasses().Get(ctx, "leftover", metav1.GetOptions{})
+	suite.NoError(err)
+	suite.Equal(wffc, *replaced.VolumeBindingMode)
+
+	// Class which wasn't created from template is never replaced
+	err = tc.Create(ctx, &ClassTemplates{StorageClasses: []StorageClassTemplate{{Name: "production"}}}, "powerstore")
+	suite.Error(err)
+
+	suite.NoError(tc.Delete(ctx))
+	list, err := client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	suite.NoError(err)
+	suite.Len(list.Items, 2)
+}
+
+func (suite *CoreTestSuite) TestSweepSnapshotClass() {
+	templates, err := SweepSnapshotClass("snapclass", []string{"retention=1h,24h", "consistency=crash,app"})
+	suite.NoError(err)
+	suite.Len(templates, 4)
+	suite.Equal("snapclass-sweep-1", templates[0].Name)
+	suite.Equal("snapclass", templates[0].From)
+	suite.Equal("consistency=crash, retention=1h", templates[0].Overrides())
+	suite.Equal("consistency=app, retention=24h", templates[3].Overrides())
+
+	_, err = SweepSnapshotClass("snapclass", []string{"retention"})
+	suite.Error(err)
+	_, err = SweepSnapshotClass("snapclass", []string{"retention=1h", "retention=2h"})
+	suite.Error(err)
+	_, err = SweepSnapshotClass("snapclass", nil)
+	suite.Error(err)
+}
+
+func (suite *CoreTestSuite) TestRunnerStatus() {
+	ctx := context.Background()
+	suite.T().Setenv("POD_NAME", "cert-csi")
+	suite.T().Setenv("POD_NAMESPACE", "cert")
+	client := fake.NewSimpleClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cert-csi", Namespace: "cert", UID: "pod-uid"}})
+
+	status, err := NewRunnerStatus(ctx, client, "cert-csi-status")
+	suite.NoError(err)
+	suite.Equal("cert/cert-csi", status.Pod())
+	status.SuiteStarted(ctx, "VolumeIoSuite", "powerstore")
+	status.SuiteFinished(ctx, "VolumeIoSuite", "powerstore", false, time.Minute, "timed out")
+	status.RunFinished(ctx, "run", nil)
+
+	events, err := client.CoreV1().Events("cert").List(ctx, metav1.ListOptions{})
+	suite.NoError(err)
+	suite.Len(events.Items, 3)
+	reasons := make(map[string]string)
+	for _, e := range events.Items {
+		suite.Equal("pod-uid", string(e.InvolvedObject.UID))
+		reasons[e.Reason] = e.Type
+	}
+	suite.Equal(v1.EventTypeWarning, reasons[ReasonSuiteFailed])
+	suite.Equal(v1.EventTypeNormal, reasons[ReasonRunPassed])
+
+	cm, err := client.CoreV1().ConfigMaps("cert").Get(ctx, "cert-csi-status", metav1.GetOptions{})
+	suite.NoError(err)
+	suite.Equal("passed", cm.Data["state"])
+	suite.Equal("1", cm.Data["failed"])
+
+	// Status of run outside of cluster is nil and reports nothing
+	var disabled *RunnerStatus
+	disabled.SuiteStarted(ctx, "VolumeIoSuite", "powerstore")
+
+	suite.T().Setenv("POD_NAME", "gone")
+	_, err = NewRunnerStatus(ctx, client, "")
+	suite.Error(err)
+}
+
+func (suite *CoreTestSuite) TestCaptureState() {
+	ctx := context.Background()
+	pvName := "pv-1"
+	client := fake.NewSimpleClientset(
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Namespace: "suite", UID: "pvc-uid", ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: pvName},
+		},
+		&v1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: pvName, UID: "pv-uid"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "suite", UID: "pod-uid"}},
+		&storagev1.VolumeAttachment{ObjectMeta: metav1.ObjectMeta{Name: "va-1"}, Spec: storagev1.VolumeAttachmentSpec{Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName}}},
+		&storagev1.VolumeAttachment{ObjectMeta: metav1.ObjectMeta{Name: "va-other"}},
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e1", Namespace: "suite"},
+			InvolvedObject: v1.ObjectReference{UID: "pod-uid"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedMount",
+			Message:        "MountVolume.SetUp failed",
+			LastTimestamp:  metav1.Now(),
+		},
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e2", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{UID: "pv-uid"},
+			Reason:         "VolumeFailedDelete",
+		},
+	)
+
+	dir := filepath.Join(suite.T().TempDir(), "cluster-state")
+	files, err := CaptureState(ctx, client, nil, "suite", dir)
+	suite.NoError(err)
+	suite.Len(files, 4)
+	for _, name := range []string{"pvc-pvc-1.yaml", "pod-pod-1.yaml", "pv-pv-1.yaml", "va-va-1.yaml"} {
+		suite.FileExists(filepath.Join(dir, name))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "pod-pod-1.yaml"))
+	suite.NoError(err)
+	suite.Contains(string(data), "kind: Pod")
+	suite.Contains(string(data), "reason: FailedMount")
+	data, err = os.ReadFile(filepath.Join(dir, "pv-pv-1.yaml"))
+	suite.NoError(err)
+	suite.Contains(string(data), "reason: VolumeFailedDelete")
+	data, err = os.ReadFile(filepath.Join(dir, "pvc-pvc-1.yaml"))
+	suite.NoError(err)
+	suite.NotContains(string(data), "managedFields")
+	suite.Contains(string(data), "events: []")
+
+	// Namespace already cleaned up leaves nothing to capture
+	files, err = CaptureState(ctx, client, nil, "gone", filepath.Join(suite.T().TempDir(), "gone"))
+	suite.NoError(err)
+	suite.Empty(files)
+}
+
 func TestCoreTestSuite(t *testing.T) {
 	suite.Run(t, new(CoreTestSuite))
 }
@@ -0,0 +1,65 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Created counts objects created with every config loaded by GetConfig, disabled if nil
+var Created *CreateCounter
+
+// CreateCounter counts objects successfully created by API requests, requests to subresources aren't counted
+type CreateCounter struct {
+	count atomic.Int64
+}
+
+// NewCreateCounter is a CreateCounter constructor
+func NewCreateCounter() *CreateCounter {
+	return &CreateCounter{}
+}
+
+// Wrap wraps transport of rest config so every successful create is counted once it's answered
+func (c *CreateCounter) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return createRoundTripper{counter: c, next: rt}
+}
+
+// Count returns number of objects created so far
+func (c *CreateCounter) Count() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.count.Load()
+}
+
+type createRoundTripper struct {
+	counter *CreateCounter
+	next    http.RoundTripper
+}
+
+func (rt createRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodPost || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, err
+	}
+	if p, _ := parsePermission(req.Method, req.URL); p.Verb == "create" && p.Resource != "" && !strings.Contains(p.Resource, "/") {
+		rt.counter.count.Add(1)
+	}
+	return resp, err
+}
@@ -0,0 +1,63 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ObjectMetadata contains annotations and labels added to objects created by suites
+type ObjectMetadata struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ExtraMetadata is user specified metadata of PVCs and pods, so driver features triggered by annotations
+// or labels, such as QoS or replication group selectors, can be certified without code changes
+type ExtraMetadata struct {
+	PVC ObjectMetadata `json:"pvc,omitempty"`
+	Pod ObjectMetadata `json:"pod,omitempty"`
+}
+
+// LoadExtraMetadata reads extra metadata from yaml file
+func LoadExtraMetadata(path string) (*ExtraMetadata, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("can't read metadata config: %v", err)
+	}
+	em := &ExtraMetadata{}
+	if err := yaml.UnmarshalStrict(data, em); err != nil {
+		return nil, fmt.Errorf("can't parse metadata config: %v", err)
+	}
+	return em, nil
+}
+
+// String formats metadata to be recorded along with test run, empty if there is none
+func (em *ExtraMetadata) String() string {
+	if em == nil || (len(em.PVC.Annotations) == 0 && len(em.PVC.Labels) == 0 && len(em.Pod.Annotations) == 0 && len(em.Pod.Labels) == 0) {
+		return ""
+	}
+	data, err := json.Marshal(em)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
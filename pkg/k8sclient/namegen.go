@@ -0,0 +1,238 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	log "github.com/sirupsen/logrus"
+)
+
+// Kinds of name generators
+const (
+	// RandomNames are 8 hex characters, reproducible if seed was set
+	RandomNames = "random"
+	// ULIDNames are lexicographically sortable, time ordered ULIDs
+	ULIDNames = "ulid"
+	// IncrementalNames are numbered per prefix, ex. pvc-0001, pvc-0002
+	IncrementalNames = "incremental"
+	// WordNames are human-readable adjective-noun pairs, ex. brave-otter
+	WordNames = "words"
+)
+
+// maxNameAttempts is how many times generator is asked for a name before falling back to a unique suffix
+const maxNameAttempts = 10
+
+// NameGenerator generates suffixes of resource names
+type NameGenerator interface {
+	// Kind is what generator is selected with and recorded in test runs
+	Kind() string
+	// Suffix returns suffix of the next name with provided prefix
+	Suffix(prefix string) string
+}
+
+var (
+	nameGenerator NameGenerator = randomNames{}
+	// issuedNames tracks names given out in this run, so generated names never repeat
+	issuedNames    = make(map[string]struct{})
+	nameCollisions int
+	namesMutex     sync.Mutex
+)
+
+// NameGeneratorKinds returns kinds NewNameGenerator accepts
+func NameGeneratorKinds() []string {
+	return []string{RandomNames, ULIDNames, IncrementalNames, WordNames}
+}
+
+// NewNameGenerator returns name generator of provided kind
+func NewNameGenerator(kind string) (NameGenerator, error) {
+	switch strings.ToLower(kind) {
+	case "", RandomNames:
+		return randomNames{}, nil
+	case ULIDNames:
+		return ulidNames{}, nil
+	case IncrementalNames:
+		return &incrementalNames{counters: make(map[string]int)}, nil
+	case WordNames:
+		return wordNames{}, nil
+	}
+	return nil, fmt.Errorf("unknown name generator %q, expected one of %s", kind, strings.Join(NameGeneratorKinds(), ", "))
+}
+
+// SetNameGenerator makes RandomSuffix and GenerateName use provided generator. Objects which are named by
+// API server by default (PVCs, pods, statefulsets) are named by the generator too, unless it is the random one
+func SetNameGenerator(g NameGenerator) {
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	nameGenerator = g
+	if g.Kind() == RandomNames {
+		commonparams.GenerateName, commonparams.NameTaken = nil, nil
+	} else {
+		commonparams.GenerateName, commonparams.NameTaken = GenerateName, nameTaken
+	}
+}
+
+// GetNameGenerator returns generator resource names are generated with
+func GetNameGenerator() NameGenerator {
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	return nameGenerator
+}
+
+// NameCollisions returns how many generated names repeated a name already given out and were regenerated
+func NameCollisions() int {
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	return nameCollisions
+}
+
+// ResetNames forgets names given out and collisions counted so far, so they are tracked per run
+func ResetNames() {
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	issuedNames = make(map[string]struct{})
+	nameCollisions = 0
+}
+
+// GenerateName returns prefix followed by suffix of the active generator, name is guaranteed not to repeat
+// any name given out by this process
+func GenerateName(prefix string) string {
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	return prefix + nextSuffix(prefix)
+}
+
+// nextSuffix returns suffix not used with prefix yet, namesMutex must be held
+func nextSuffix(prefix string) string {
+	for i := 0; i < maxNameAttempts; i++ {
+		suffix := nameGenerator.Suffix(prefix)
+		if _, ok := issuedNames[prefix+suffix]; !ok {
+			issuedNames[prefix+suffix] = struct{}{}
+			return suffix
+		}
+		nameCollisions++
+		log.Debugf("Name %s%s was already given out, generating another one", prefix, suffix)
+	}
+	// Generator keeps repeating itself, ex. word list is exhausted
+	suffix := nameGenerator.Suffix(prefix) + "-" + UniqueSuffix()
+	issuedNames[prefix+suffix] = struct{}{}
+	return suffix
+}
+
+// nameTaken records that name given out was rejected by API server because object with that name already exists
+func nameTaken() {
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	nameCollisions++
+}
+
+// randomNames is the default generator, names are reproducible if seed was set
+type randomNames struct{}
+
+func (randomNames) Kind() string { return RandomNames }
+
+func (randomNames) Suffix(string) string {
+	b := make([]byte, 4)
+	randMutex.Lock()
+	defer randMutex.Unlock()
+	if seededRand != nil {
+		_, _ = seededRand.Read(b)
+		return fmt.Sprintf("%x", b[0:])
+	}
+	return cryptoSuffix(b)
+}
+
+// crockford is the ULID alphabet, lowercased as object names can't contain upper case letters
+const crockford = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// ulidNames generates 48 bits of millisecond timestamp followed by 80 random bits
+type ulidNames struct{}
+
+func (ulidNames) Kind() string { return ULIDNames }
+
+func (ulidNames) Suffix(string) string {
+	b := make([]byte, 16)
+	ms := uint64(time.Now().UnixMilli()) // #nosec G115
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	randMutex.Lock()
+	if seededRand != nil {
+		_, _ = seededRand.Read(b[6:])
+	} else {
+		cryptoSuffix(b[6:])
+	}
+	randMutex.Unlock()
+
+	// 128 bits are encoded by 26 characters of 5 bits, the first one carries only 3 bits
+	var sb strings.Builder
+	sb.WriteByte(crockford[b[0]>>5])
+	acc, bits := uint16(b[0])&0x1f, 5
+	for _, v := range b[1:] {
+		acc = acc<<8 | uint16(v)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			sb.WriteByte(crockford[(acc>>bits)&0x1f])
+		}
+	}
+	return sb.String()
+}
+
+// incrementalNames numbers names of each prefix starting with 1
+type incrementalNames struct {
+	mutex    sync.Mutex
+	counters map[string]int
+}
+
+func (*incrementalNames) Kind() string { return IncrementalNames }
+
+func (g *incrementalNames) Suffix(prefix string) string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.counters[prefix]++
+	return fmt.Sprintf("%04d", g.counters[prefix])
+}
+
+var (
+	nameAdjectives = []string{
+		"amber", "bold", "brave", "brisk", "calm", "clever", "crisp", "eager", "fancy", "gentle",
+		"golden", "happy", "jolly", "keen", "lively", "lucky", "mellow", "misty", "noble", "proud",
+		"quick", "quiet", "rapid", "shiny", "silent", "snowy", "steady", "sunny", "swift", "tidy",
+		"vivid", "witty",
+	}
+	nameNouns = []string{
+		"badger", "beacon", "bison", "canyon", "cedar", "comet", "coral", "falcon", "fjord", "gecko",
+		"glacier", "harbor", "heron", "island", "lagoon", "lynx", "maple", "meadow", "otter", "panda",
+		"pebble", "pine", "raven", "reef", "river", "salmon", "summit", "tiger", "tundra", "walrus",
+		"willow", "zebra",
+	}
+)
+
+// wordNames generates adjective-noun pairs, reproducible if seed was set
+type wordNames struct{}
+
+func (wordNames) Kind() string { return WordNames }
+
+func (wordNames) Suffix(string) string {
+	return nameAdjectives[RandomIntn(len(nameAdjectives))] + "-" + nameNouns[RandomIntn(len(nameNouns))]
+}
@@ -16,6 +16,12 @@
 
 package commonparams
 
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
 const (
 	// RemoteClusterID represents remote cluster ID
 	RemoteClusterID = "replication.storage.dell.com/remoteClusterID"
@@ -44,4 +50,54 @@ var (
 	RemotePVAnnotations = []string{ReplicationGroupName}
 	// RemotePVLabels represents remote PV labels
 	RemotePVLabels = []string{ReplicationGroupName}
+
+	// GenerateName returns name for object with provided generate name, nil leaves naming to API server
+	GenerateName func(prefix string) string
+	// NameTaken is called when generated name was rejected because object with that name already exists
+	NameTaken func()
 )
+
+// maxNameAttempts is how many generated names object creation is tried with
+const maxNameAttempts = 5
+
+// namedObject is an API object which can be copied
+type namedObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+// CreateNamed calls create with a copy of obj named by GenerateName if obj has only generate name and GenerateName
+// is set, otherwise with obj itself. Name taken by an existing object is regenerated
+func CreateNamed[T namedObject](obj T, create func(T) (T, error)) (T, error) {
+	if GenerateName == nil || obj.GetName() != "" || obj.GetGenerateName() == "" {
+		return create(obj)
+	}
+	for attempt := 1; ; attempt++ {
+		named, _ := obj.DeepCopyObject().(T)
+		named.SetName(GenerateName(obj.GetGenerateName()))
+		named.SetGenerateName("")
+		created, err := create(named)
+		if !apierrors.IsAlreadyExists(err) || attempt == maxNameAttempts {
+			return created, err
+		}
+		if NameTaken != nil {
+			NameTaken()
+		}
+	}
+}
+
+// MergeMetadata returns values with extra entries added, values set by suite take precedence
+// as suites may rely on them, e.g. for label selectors
+func MergeMetadata(values, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return values
+	}
+	merged := make(map[string]string, len(values)+len(extra))
+	for k, v := range extra {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,59 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package commonparams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCreateNamed(t *testing.T) {
+	defer func() { GenerateName, NameTaken = nil, nil }()
+	template := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{GenerateName: "pvc-"}}
+	create := func(pvc *v1.PersistentVolumeClaim) (*v1.PersistentVolumeClaim, error) {
+		if pvc.Name == "pvc-1" {
+			return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "persistentvolumeclaims"}, pvc.Name)
+		}
+		return pvc, nil
+	}
+
+	// Naming is left to API server without generator
+	created, err := CreateNamed(template, create)
+	assert.NoError(t, err)
+	assert.Empty(t, created.Name)
+
+	n, taken := 0, 0
+	GenerateName = func(prefix string) string {
+		n++
+		return fmt.Sprintf("%s%d", prefix, n)
+	}
+	NameTaken = func() { taken++ }
+	created, err = CreateNamed(template, create)
+	assert.NoError(t, err)
+	assert.Equal(t, "pvc-2", created.Name)
+	assert.Empty(t, created.GenerateName)
+	assert.Equal(t, 1, taken)
+	// Template is reusable
+	assert.Empty(t, template.Name)
+	assert.Equal(t, "pvc-", template.GenerateName)
+}
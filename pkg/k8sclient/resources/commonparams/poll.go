@@ -0,0 +1,168 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package commonparams
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var (
+	// WatchedPollInterval is a maximum delay between rechecks while watch reports changes of polled objects
+	WatchedPollInterval = 10 * time.Second
+	// WatchedPollFloor is a minimum delay between rechecks triggered by watch, events arriving sooner are coalesced
+	WatchedPollFloor = 100 * time.Millisecond
+	// MaxPollInterval caps delay between rechecks without watch, delay doubles from interval up to it
+	// while state reported by condition doesn't change
+	MaxPollInterval = 16 * time.Second
+)
+
+// stateKey is a context key of pollState
+type stateKey struct{}
+
+// pollState holds the last state condition reported with ReportState
+type pollState struct {
+	last    string
+	changed bool
+}
+
+// ReportState lets condition of PollUntil report observed state, ex. number of ready pods. Delay between rechecks
+// without watch starts again from interval once state changes, so progress is measured precisely
+func ReportState(ctx context.Context, state string) {
+	ps, ok := ctx.Value(stateKey{}).(*pollState)
+	if !ok {
+		return
+	}
+	if state != ps.last {
+		ps.last, ps.changed = state, true
+	}
+}
+
+// WatchFunc starts watch of objects condition is checked against
+type WatchFunc func(ctx context.Context) (watch.Interface, error)
+
+// Watcher is implemented by typed clients of every resource
+type Watcher interface {
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// Watch returns WatchFunc watching objects client lists with opts
+func Watch(client Watcher, opts metav1.ListOptions) WatchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		return client.Watch(ctx, opts)
+	}
+}
+
+// NameSelector returns list options selecting only object with given name, so watch of it is cheap
+func NameSelector(name string) metav1.ListOptions {
+	return metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+}
+
+// PollUntil checks condition immediately and then until it's done or timeout passes. Without watch delays between
+// rechecks grow exponentially from interval up to MaxPollInterval and are reset when condition reports new state with
+// ReportState. If watchFunc is given and watch can be started, every watch event triggers a recheck no sooner than
+// WatchedPollFloor after the previous one, and without events condition is rechecked every WatchedPollInterval
+func PollUntil(ctx context.Context, interval, timeout time.Duration, watchFunc WatchFunc, condition wait.ConditionWithContextFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	maxDelay := MaxPollInterval
+	if interval > maxDelay {
+		maxDelay = interval
+	}
+	changed := make(chan struct{}, 1)
+	var watchClosed chan struct{}
+	if watchFunc != nil {
+		if w, err := watchFunc(ctx); err == nil {
+			defer w.Stop()
+			watchClosed = make(chan struct{})
+			go func() {
+				defer close(watchClosed)
+				for range w.ResultChan() {
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+				}
+			}()
+		}
+	}
+
+	state := &pollState{}
+	conditionCtx := context.WithValue(ctx, stateKey{}, state)
+	delay := interval
+	for {
+		checked := time.Now()
+		state.changed = false
+		done, err := condition(conditionCtx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if watchClosed == nil {
+			if state.changed {
+				delay = interval
+			}
+			if !sleepUntil(ctx, checked.Add(delay)) {
+				return ctx.Err()
+			}
+			if delay *= 2; delay > maxDelay {
+				delay = maxDelay
+			}
+			continue
+		}
+
+		timer := time.NewTimer(time.Until(checked.Add(WatchedPollInterval)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-changed:
+		case <-watchClosed:
+			// Changes are no longer reported, so fall back to polling with growing delays
+			watchClosed = nil
+			delay = interval
+		case <-timer.C:
+			continue
+		}
+		timer.Stop()
+		// Bursts of events are rechecked together once floor passes
+		if !sleepUntil(ctx, checked.Add(WatchedPollFloor)) {
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepUntil waits until deadline, returns false if context is done first
+func sleepUntil(ctx context.Context, deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
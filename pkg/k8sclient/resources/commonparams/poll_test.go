@@ -0,0 +1,90 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package commonparams
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestPollUntil(t *testing.T) {
+	defer func(watched, floor, maxInterval time.Duration) {
+		WatchedPollInterval, WatchedPollFloor, MaxPollInterval = watched, floor, maxInterval
+	}(WatchedPollInterval, WatchedPollFloor, MaxPollInterval)
+
+	// Without watch delays double from interval up to MaxPollInterval and restart from interval on state change
+	MaxPollInterval = 400 * time.Millisecond
+	var checks []time.Time
+	err := PollUntil(context.Background(), 10*time.Millisecond, 10*time.Second, nil, func(ctx context.Context) (bool, error) {
+		checks = append(checks, time.Now())
+		if len(checks) < 5 {
+			ReportState(ctx, "a")
+		} else {
+			ReportState(ctx, "b")
+		}
+		return len(checks) == 6, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, checks, 6)
+	assert.GreaterOrEqual(t, checks[1].Sub(checks[0]), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, checks[2].Sub(checks[1]), 20*time.Millisecond)
+	assert.GreaterOrEqual(t, checks[4].Sub(checks[3]), 80*time.Millisecond)
+	assert.Less(t, checks[5].Sub(checks[4]), 80*time.Millisecond)
+
+	err = PollUntil(context.Background(), time.Millisecond, 20*time.Millisecond, nil, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	assert.True(t, wait.Interrupted(err))
+
+	// Without changes watched condition is checked every WatchedPollInterval
+	WatchedPollInterval = 50 * time.Millisecond
+	var calls atomic.Int32
+	err = PollUntil(context.Background(), time.Millisecond, 120*time.Millisecond, func(context.Context) (watch.Interface, error) {
+		return watch.NewFake(), nil
+	}, func(context.Context) (bool, error) {
+		calls.Add(1)
+		return false, nil
+	})
+	assert.True(t, wait.Interrupted(err))
+	assert.LessOrEqual(t, calls.Load(), int32(4))
+
+	// Watch events trigger recheck right away instead of waiting for interval, bursts are coalesced per floor
+	WatchedPollInterval, WatchedPollFloor = time.Hour, 5*time.Millisecond
+	fake := watch.NewFakeWithChanSize(10, false)
+	for i := 0; i < 10; i++ {
+		fake.Modify(nil)
+	}
+	checks = nil
+	start := time.Now()
+	err = PollUntil(context.Background(), time.Minute, time.Minute, func(context.Context) (watch.Interface, error) {
+		return fake, nil
+	}, func(context.Context) (bool, error) {
+		checks = append(checks, time.Now())
+		return len(checks) == 3, nil
+	})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	for i := 1; i < len(checks); i++ {
+		assert.GreaterOrEqual(t, checks[i].Sub(checks[i-1]), 5*time.Millisecond)
+	}
+}
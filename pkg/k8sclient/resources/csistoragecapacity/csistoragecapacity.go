@@ -19,16 +19,18 @@ package csistoragecapacity
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+
 	"github.com/fatih/color"
 	log "github.com/sirupsen/logrus"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	tcorev1 "k8s.io/client-go/kubernetes/typed/storage/v1"
 )
@@ -108,8 +110,8 @@ func (c *Client) WaitForAllToBeCreated(ctx context.Context, scName string, expec
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(pollCtx context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping CSIStorageCapacity wait polling")
@@ -131,6 +133,7 @@ func (c *Client) WaitForAllToBeCreated(ctx context.Context, scName string, expec
 				}
 			}
 
+			commonparams.ReportState(pollCtx, strconv.Itoa(createdCount))
 			return createdCount == expectedCount, nil
 		})
 
@@ -152,8 +155,8 @@ func (c *Client) WaitForAllToBeDeleted(ctx context.Context, scName string) error
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(pollCtx context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping CSIStorageCapacity wait polling")
@@ -175,6 +178,7 @@ func (c *Client) WaitForAllToBeDeleted(ctx context.Context, scName string) error
 				}
 			}
 
+			commonparams.ReportState(pollCtx, strconv.Itoa(foundCount))
 			return foundCount == 0, nil
 		})
 
@@ -78,3 +78,75 @@ func (c *Client) cordonUnCordon(ctx context.Context, nodename string, cordon boo
 	}
 	return err
 }
+
+// VolumeStats is usage of pod volume reported by kubelet, sizes are in bytes
+type VolumeStats struct {
+	Namespace string
+	PVC       string
+	Capacity  int64
+	Used      int64
+	Available int64
+}
+
+// summary is a part of kubelet summary API response describing volumes of pods
+type summary struct {
+	Pods []struct {
+		Volumes []struct {
+			CapacityBytes  *int64 `json:"capacityBytes"`
+			UsedBytes      *int64 `json:"usedBytes"`
+			AvailableBytes *int64 `json:"availableBytes"`
+			PVCRef         *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// GetVolumeStats reads usage of persistent volumes of node pods from kubelet summary API
+func (c *Client) GetVolumeStats(ctx context.Context, nodeName string) ([]VolumeStats, error) {
+	data, err := c.ClientSet.CoreV1().RESTClient().Get().
+		Resource("nodes").Name(nodeName).SubResource("proxy").Suffix("stats/summary").DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVolumeStats(data)
+}
+
+// KubeletHealthy checks health endpoint of kubelet of the node through API server node proxy
+func (c *Client) KubeletHealthy(ctx context.Context, nodeName string) error {
+	_, err := c.ClientSet.CoreV1().RESTClient().Get().
+		Resource("nodes").Name(nodeName).SubResource("proxy").Suffix("healthz").DoRaw(ctx)
+	return err
+}
+
+// ParseVolumeStats returns usage of persistent volumes from kubelet summary API response, missing sizes are zero
+func ParseVolumeStats(data []byte) ([]VolumeStats, error) {
+	var s summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	var stats []VolumeStats
+	for _, pod := range s.Pods {
+		for _, volume := range pod.Volumes {
+			if volume.PVCRef == nil {
+				continue
+			}
+			stats = append(stats, VolumeStats{
+				Namespace: volume.PVCRef.Namespace,
+				PVC:       volume.PVCRef.Name,
+				Capacity:  valueOf(volume.CapacityBytes),
+				Used:      valueOf(volume.UsedBytes),
+				Available: valueOf(volume.AvailableBytes),
+			})
+		}
+	}
+	return stats, nil
+}
+
+func valueOf(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,45 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package node_test
+
+import (
+	"testing"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/node"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVolumeStats(t *testing.T) {
+	data := []byte(`{"node":{"nodeName":"node-1"},"pods":[
+		{"podRef":{"name":"io-pod"},"volume":[
+			{"name":"vol0","capacityBytes":3221225472,"usedBytes":268435456,"availableBytes":2952790016,"pvcRef":{"name":"pvc-1","namespace":"stats-test"}},
+			{"name":"kube-api-access","capacityBytes":1024,"usedBytes":12}
+		]},
+		{"podRef":{"name":"broken-pod"},"volume":[
+			{"name":"vol0","pvcRef":{"name":"pvc-2","namespace":"stats-test"}}
+		]}
+	]}`)
+
+	stats, err := node.ParseVolumeStats(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []node.VolumeStats{
+		{Namespace: "stats-test", PVC: "pvc-1", Capacity: 3221225472, Used: 268435456, Available: 2952790016},
+		{Namespace: "stats-test", PVC: "pvc-2"},
+	}, stats)
+
+	_, err = node.ParseVolumeStats([]byte("not json"))
+	assert.Error(t, err)
+}
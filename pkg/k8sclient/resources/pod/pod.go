@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
 	"github.com/dell/cert-csi/pkg/utils"
 
 	"golang.org/x/sync/errgroup"
@@ -38,7 +39,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -77,6 +77,9 @@ type Config struct {
 	EnvVars         []v1.EnvVar
 	CSIVolumeSource v1.CSIVolumeSource
 	ReadOnlyFlag    bool
+	SecurityContext *v1.PodSecurityContext
+	Limits          v1.ResourceList
+	Requests        v1.ResourceList
 }
 
 // Client contains node client information
@@ -87,6 +90,9 @@ type Client struct {
 	Namespace string
 	Timeout   int
 	nodeInfos []*resource.Info
+	// ExtraAnnotations and ExtraLabels are added to every pod created by the client
+	ExtraAnnotations map[string]string
+	ExtraLabels      map[string]string
 }
 
 // Pod contains pod related information
@@ -162,6 +168,7 @@ func (c *Client) MakePod(config *Config) *v1.Pod {
 		SecurityContext: &v1.SecurityContext{
 			Capabilities: &v1.Capabilities{Add: config.Capabilities},
 		},
+		Resources: v1.ResourceRequirements{Limits: config.Limits, Requests: config.Requests},
 	}
 
 	container.VolumeMounts = volumeMounts
@@ -185,8 +192,9 @@ func (c *Client) MakePod(config *Config) *v1.Pod {
 	return &v1.Pod{
 		ObjectMeta: ObjMeta,
 		Spec: v1.PodSpec{
-			Volumes:    volumes,
-			Containers: []v1.Container{container},
+			Volumes:         volumes,
+			Containers:      []v1.Container{container},
+			SecurityContext: config.SecurityContext,
 		},
 	}
 }
@@ -200,7 +208,10 @@ func (c *Client) MakePodFromYaml(_ string) *v1.Pod {
 func (c *Client) Create(ctx context.Context, pod *v1.Pod) *Pod {
 	log := utils.GetLoggerFromContext(ctx)
 	var funcErr error
-	newPod, err := c.Interface.Create(ctx, pod, metav1.CreateOptions{})
+	c.addExtraMetadata(pod)
+	newPod, err := commonparams.CreateNamed(pod, func(pod *v1.Pod) (*v1.Pod, error) {
+		return c.Interface.Create(ctx, pod, metav1.CreateOptions{})
+	})
 
 	if err != nil {
 		funcErr = err
@@ -215,6 +226,15 @@ func (c *Client) Create(ctx context.Context, pod *v1.Pod) *Pod {
 	}
 }
 
+// addExtraMetadata adds user specified annotations and labels to pod
+func (c *Client) addExtraMetadata(pod *v1.Pod) {
+	if pod == nil {
+		return
+	}
+	pod.Annotations = commonparams.MergeMetadata(pod.Annotations, c.ExtraAnnotations)
+	pod.Labels = commonparams.MergeMetadata(pod.Labels, c.ExtraLabels)
+}
+
 // Delete deletes the specified pod
 func (c *Client) Delete(ctx context.Context, pod *v1.Pod) *Pod {
 	var funcErr error
@@ -300,8 +320,8 @@ func (c *Client) WaitForAllToBeReady(ctx context.Context) error {
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(c.Interface, metav1.ListOptions{}),
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pod wait polling")
@@ -344,8 +364,8 @@ func (pod *Pod) WaitForRunning(ctx context.Context) error {
 		timeout = time.Duration(pod.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(pod.Client.ClientSet.CoreV1().Pods(pod.Object.Namespace), commonparams.NameSelector(pod.Object.Name)),
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pod wait polling")
@@ -370,6 +390,41 @@ func (pod *Pod) WaitForRunning(ctx context.Context) error {
 	return nil
 }
 
+// WaitForEviction stalls until pod is evicted by kubelet
+func (pod *Pod) WaitForEviction(ctx context.Context) error {
+	log := utils.GetLoggerFromContext(ctx)
+	log.Infof("Waiting for pod %s to be EVICTED", pod.Object.Name)
+	timeout := Timeout
+	if pod.Client.Timeout != 0 {
+		timeout = time.Duration(pod.Client.Timeout) * time.Second
+	}
+
+	return commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(pod.Client.Interface, commonparams.NameSelector(pod.Object.Name)),
+		func(context.Context) (bool, error) {
+			select {
+			case <-ctx.Done():
+				log.Infof("Stopping pod wait polling")
+				return true, fmt.Errorf("stopped waiting to be evicted")
+			default:
+				break
+			}
+
+			p, err := pod.Client.Interface.Get(ctx, pod.Object.Name, metav1.GetOptions{})
+			if err != nil {
+				log.Errorf("Can't find pod %s", pod.Object.Name)
+				return false, err
+			}
+			pod.Object = p
+
+			return IsPodEvicted(p), nil
+		})
+}
+
+// IsPodEvicted checks whether pod was evicted by kubelet
+func IsPodEvicted(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodFailed && pod.Status.Reason == "Evicted"
+}
+
 func (pod *Pod) pollWait(ctx context.Context) (bool, error) {
 	log := utils.GetLoggerFromContext(ctx)
 	select {
@@ -398,7 +453,7 @@ func (pod *Pod) WaitUntilGone(ctx context.Context) error {
 		timeout = time.Duration(pod.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout/100*95, func() (done bool, err error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout/100*95, commonparams.Watch(pod.Client.Interface, commonparams.NameSelector(pod.Object.Name)), func(context.Context) (done bool, err error) {
 		done, err = pod.pollWait(ctx)
 		return done, err
 	})
@@ -422,7 +477,7 @@ func (pod *Pod) WaitUntilGone(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		pollErr = wait.PollImmediate(Poll, timeout, func() (done bool, err error) {
+		pollErr = commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(pod.Client.Interface, commonparams.NameSelector(pod.Object.Name)), func(context.Context) (done bool, err error) {
 			done, err = pod.pollWait(ctx)
 			return done, err
 		})
@@ -587,6 +642,37 @@ func (c *Client) MakeEphemeralPod(config *Config) *v1.Pod {
 	}
 }
 
+// MakeDiagnosticPod creates a privileged pod pinned to the node, it shares host PID namespace
+// so that host mount table can be read from /proc/1/mountinfo
+func (c *Client) MakeDiagnosticPod(nodeName, image string) *v1.Pod {
+	if len(image) == 0 {
+		image = "quay.io/centos/centos:latest"
+	}
+	privileged := true
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "diag-",
+			Namespace:    c.Namespace,
+		},
+		Spec: v1.PodSpec{
+			NodeName:      nodeName,
+			HostPID:       true,
+			RestartPolicy: v1.RestartPolicyNever,
+			Tolerations:   []v1.Toleration{{Operator: v1.TolerationOpExists}},
+			Containers: []v1.Container{
+				{
+					Name:            "diag",
+					Image:           image,
+					Command:         []string{"/bin/bash"},
+					Args:            []string{"-c", "trap 'exit 0' SIGTERM; while true; do sleep 1; done"},
+					ImagePullPolicy: "IfNotPresent",
+					SecurityContext: &v1.SecurityContext{Privileged: &privileged},
+				},
+			},
+		},
+	}
+}
+
 // DeleteOrEvictPods deletes or evicts pod from a node
 func (c *Client) DeleteOrEvictPods(ctx context.Context, nodeName string, gracePeriodSeconds int) error {
 	podList, podErr := c.Interface.List(ctx, metav1.ListOptions{
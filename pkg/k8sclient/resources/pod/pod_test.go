@@ -86,6 +86,17 @@ func (suite *PodTestSuite) TestMakePod_default() {
 	suite.Equal(podconf.Command, []string{"/bin/bash"})
 }
 
+func (suite *PodTestSuite) TestMakeDiagnosticPod() {
+	podClient, err := suite.kubeClient.CreatePodClient("test-namespace")
+	suite.NoError(err)
+	podTmpl := podClient.MakeDiagnosticPod("node-1", "")
+	suite.Equal("test-namespace", podTmpl.Namespace)
+	suite.Equal("node-1", podTmpl.Spec.NodeName)
+	suite.True(podTmpl.Spec.HostPID)
+	suite.True(*podTmpl.Spec.Containers[0].SecurityContext.Privileged)
+	suite.Equal("quay.io/centos/centos:latest", podTmpl.Spec.Containers[0].Image)
+}
+
 func (suite *PodTestSuite) TestCreatePod() {
 	type fields struct {
 		KubeClient *k8sclient.KubeClient
@@ -168,6 +179,24 @@ func (suite *PodTestSuite) TestCreatePod() {
 	}
 }
 
+func (suite *PodTestSuite) TestCreatePod_extraMetadata() {
+	client, err := suite.kubeClient.CreatePodClient("test-namespace")
+	suite.NoError(err)
+	client.ExtraAnnotations = map[string]string{"backup": "daily"}
+	client.ExtraLabels = map[string]string{"app": "extra", "team": "storage"}
+
+	p := client.Create(context.Background(), &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled-pod",
+			Labels: map[string]string{"app": "suite"},
+		},
+	})
+	suite.NoError(p.GetError())
+	suite.Equal("daily", p.Object.Annotations["backup"])
+	suite.Equal("suite", p.Object.Labels["app"])
+	suite.Equal("storage", p.Object.Labels["team"])
+}
+
 func TestPodTestSuite(t *testing.T) {
 	suite.Run(t, new(PodTestSuite))
 }
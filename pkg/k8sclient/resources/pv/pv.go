@@ -32,7 +32,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	tcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -165,8 +164,8 @@ func (c *Client) WaitPV(ctx context.Context, newPVName string) error {
 	if c.Timeout != 0 {
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pv wait polling")
@@ -200,8 +199,8 @@ func (pv *PersistentVolume) WaitToBeBound(ctx context.Context) error {
 	if pv.Client.Timeout != 0 {
 		timeout = time.Duration(pv.Client.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pv wait polling")
@@ -234,8 +233,8 @@ func (c *Client) CheckReplicationAnnotationsForPV(ctx context.Context, object *v
 	if c.Timeout != 0 {
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pv check polling")
@@ -281,8 +280,8 @@ func (c *Client) CheckReplicationAnnotationsForRemotePV(ctx context.Context, obj
 	if c.Timeout != 0 {
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pvc wait polling")
@@ -346,7 +345,7 @@ func (pv *PersistentVolume) WaitUntilGone(ctx context.Context) error {
 		timeout = time.Duration(pv.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout/100*95, func() (done bool, err error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout/100*95, nil, func(context.Context) (done bool, err error) {
 		done, err = pv.pollWait(ctx)
 		return done, err
 	})
@@ -362,7 +361,7 @@ func (pv *PersistentVolume) WaitUntilGone(ctx context.Context) error {
 		if er != nil {
 			return er
 		}
-		pollErr = wait.PollImmediate(Poll, timeout/2, func() (done bool, err error) {
+		pollErr = commonparams.PollUntil(ctx, Poll, timeout/2, nil, func(context.Context) (done bool, err error) {
 			done, err = pv.pollWait(ctx)
 			return done, err
 		})
@@ -39,8 +39,9 @@ import (
 	v2 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/types"
 	tcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/yaml"
 )
 
@@ -63,6 +64,8 @@ type Config struct {
 
 	// SnapName
 	SnapName string
+	// SnapNamespace is namespace of SnapName snapshot, if set PVC is restored across namespaces with dataSourceRef
+	SnapNamespace string
 
 	// SourceVolumeName
 	SourceVolumeName string
@@ -89,6 +92,9 @@ type Client struct {
 	ClientSet kubernetes.Interface
 	Namespace string
 	Timeout   int
+	// ExtraAnnotations and ExtraLabels are added to every PVC created by the client
+	ExtraAnnotations map[string]string
+	ExtraLabels      map[string]string
 }
 
 // PersistentVolumeClaim conatins pvc client and claim
@@ -121,7 +127,16 @@ func (c *Client) MakePVC(cfg *Config) *v1.PersistentVolumeClaim {
 	}
 
 	var dataSource *v1.TypedLocalObjectReference
-	if cfg.SnapName != "" {
+	var dataSourceRef *v1.TypedObjectReference
+	if cfg.SnapName != "" && cfg.SnapNamespace != "" {
+		apiGroup := "snapshot.storage.k8s.io"
+		dataSourceRef = &v1.TypedObjectReference{
+			APIGroup:  &apiGroup,
+			Kind:      "VolumeSnapshot",
+			Name:      cfg.SnapName,
+			Namespace: &cfg.SnapNamespace,
+		}
+	} else if cfg.SnapName != "" {
 		apiGroup := "snapshot.storage.k8s.io"
 		dataSource = &v1.TypedLocalObjectReference{
 			APIGroup: &apiGroup,
@@ -147,6 +162,7 @@ func (c *Client) MakePVC(cfg *Config) *v1.PersistentVolumeClaim {
 			Name:        cfg.Name,
 			Namespace:   c.Namespace,
 			Annotations: cfg.Annotations,
+			Labels:      cfg.Labels,
 		}
 	}
 
@@ -163,6 +179,7 @@ func (c *Client) MakePVC(cfg *Config) *v1.PersistentVolumeClaim {
 			StorageClassName: cfg.StorageClassName,
 			VolumeMode:       cfg.VolumeMode,
 			DataSource:       dataSource,
+			DataSourceRef:    dataSourceRef,
 		},
 	}
 }
@@ -188,7 +205,10 @@ func (c *Client) MakePVCFromYaml(ctx context.Context, filePath string) (*v1.Pers
 func (c *Client) Create(ctx context.Context, pvc *v1.PersistentVolumeClaim, _ ...int) *PersistentVolumeClaim {
 	log := utils.GetLoggerFromContext(ctx)
 	var funcErr error
-	newPVC, err := c.Interface.Create(ctx, pvc, metav1.CreateOptions{})
+	c.addExtraMetadata(pvc)
+	newPVC, err := commonparams.CreateNamed(pvc, func(pvc *v1.PersistentVolumeClaim) (*v1.PersistentVolumeClaim, error) {
+		return c.Interface.Create(ctx, pvc, metav1.CreateOptions{})
+	})
 	if err != nil {
 		funcErr = err
 	}
@@ -202,6 +222,15 @@ func (c *Client) Create(ctx context.Context, pvc *v1.PersistentVolumeClaim, _ ..
 	}
 }
 
+// addExtraMetadata adds user specified annotations and labels to PVC
+func (c *Client) addExtraMetadata(pvc *v1.PersistentVolumeClaim) {
+	if pvc == nil {
+		return
+	}
+	pvc.Annotations = commonparams.MergeMetadata(pvc.Annotations, c.ExtraAnnotations)
+	pvc.Labels = commonparams.MergeMetadata(pvc.Labels, c.ExtraLabels)
+}
+
 // Get uses client interface to make API call for getting provided PersistentVolumeClaim
 func (c *Client) Get(ctx context.Context, name string) *PersistentVolumeClaim {
 	log := utils.GetLoggerFromContext(ctx)
@@ -228,8 +257,11 @@ func (c *Client) CreateMultiple(ctx context.Context, pvc *v1.PersistentVolumeCla
 	if pvcSize == "" {
 		return errors.New("volume size cannot be nulls")
 	}
+	c.addExtraMetadata(pvc)
 	for i := 0; i < pvcNum; i++ {
-		_, err := c.Interface.Create(ctx, pvc, metav1.CreateOptions{})
+		_, err := commonparams.CreateNamed(pvc, func(pvc *v1.PersistentVolumeClaim) (*v1.PersistentVolumeClaim, error) {
+			return c.Interface.Create(ctx, pvc, metav1.CreateOptions{})
+		})
 		if err != nil {
 			return err
 		}
@@ -270,6 +302,36 @@ func (c *Client) Update(ctx context.Context, pvc *v1.PersistentVolumeClaim) *Per
 	}
 }
 
+// Expand requests new size of PersistentVolumeClaim with merge patch, so it doesn't conflict with status updates
+// made by resizer, transient API errors are retried
+func (c *Client) Expand(ctx context.Context, name, size string) *PersistentVolumeClaim {
+	patch := []byte(fmt.Sprintf(`{"spec":{"resources":{"requests":{"storage":%q}}}}`, size))
+	var expandedPVC *v1.PersistentVolumeClaim
+	funcErr := retry.OnError(retry.DefaultBackoff, isTransient, func() error {
+		var err error
+		expandedPVC, err = c.Interface.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			logrus.Debugf("Can't expand PVC %s, retrying; error=%v", name, err)
+		}
+		return err
+	})
+	if funcErr == nil {
+		logrus.Debugf("Requested %s for PVC %s", size, name)
+	}
+	return &PersistentVolumeClaim{
+		Client:  c,
+		Object:  expandedPVC,
+		Deleted: false,
+		error:   funcErr,
+	}
+}
+
+// isTransient checks whether request may succeed if retried
+func isTransient(err error) bool {
+	return apierrs.IsConflict(err) || apierrs.IsServerTimeout(err) || apierrs.IsTimeout(err) ||
+		apierrs.IsTooManyRequests(err) || apierrs.IsServiceUnavailable(err) || apierrs.IsInternalError(err)
+}
+
 // DeleteAll deletes all client pvcs in timely manner, using event subscription model
 func (c *Client) DeleteAll(ctx context.Context) error {
 	log := utils.GetLoggerFromContext(ctx)
@@ -298,8 +360,8 @@ func (c *Client) WaitForAllToBeBound(ctx context.Context) error {
 	if c.Timeout != 0 {
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(c.Interface, metav1.ListOptions{}),
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pvc wait polling")
@@ -339,8 +401,8 @@ func (c *Client) CheckAnnotationsForVolumes(ctx context.Context, scObject *v2.St
 	if c.Timeout != 0 {
 		timeout = time.Duration(c.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pvc annotations wait polling")
@@ -457,8 +519,8 @@ func (pvc *PersistentVolumeClaim) WaitToBeBound(ctx context.Context) error {
 	if pvc.Client.Timeout != 0 {
 		timeout = time.Duration(pvc.Client.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pvc wait polling")
@@ -537,7 +599,7 @@ func (pvc *PersistentVolumeClaim) WaitUntilGone(ctx context.Context) error {
 		timeout = time.Duration(pvc.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout/100*95, func() (done bool, err error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout/100*95, commonparams.Watch(pvc.Client.Interface, commonparams.NameSelector(pvc.Object.Name)), func(context.Context) (done bool, err error) {
 		done, err = pvc.pollWait(ctx)
 		return done, err
 	})
@@ -553,7 +615,7 @@ func (pvc *PersistentVolumeClaim) WaitUntilGone(ctx context.Context) error {
 		if er != nil {
 			return er
 		}
-		pollErr = wait.PollImmediate(Poll, timeout/2, func() (done bool, err error) {
+		pollErr = commonparams.PollUntil(ctx, Poll, timeout/2, commonparams.Watch(pvc.Client.Interface, commonparams.NameSelector(pvc.Object.Name)), func(context.Context) (done bool, err error) {
 			done, err = pvc.pollWait(ctx)
 			return done, err
 		})
@@ -0,0 +1,71 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package referencegrant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dell/cert-csi/pkg/utils"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GroupVersion of Gateway API ReferenceGrant, it's installed by Gateway API CRDs
+var GroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1beta1"}
+
+// Client manages ReferenceGrants, they are handled as unstructured objects so Gateway API module isn't required
+type Client struct {
+	Interface runtimeclient.Client
+}
+
+// Create creates ReferenceGrant in namespace allowing PVCs of fromNamespace to use its VolumeSnapshots as data source
+func (c *Client) Create(ctx context.Context, namespace, name, fromNamespace string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersion.WithKind("ReferenceGrant"))
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.Object["spec"] = map[string]interface{}{
+		"from": []interface{}{
+			map[string]interface{}{"group": "", "kind": "PersistentVolumeClaim", "namespace": fromNamespace},
+		},
+		"to": []interface{}{
+			map[string]interface{}{"group": "snapshot.storage.k8s.io", "kind": "VolumeSnapshot"},
+		},
+	}
+	if err := c.Interface.Create(ctx, obj); err != nil {
+		return nil, err
+	}
+	utils.GetLoggerFromContext(ctx).Debugf("Created ReferenceGrant %s/%s for PVCs of %s", namespace, name, fromNamespace)
+	return obj, nil
+}
+
+// Delete deletes the ReferenceGrant, grants already gone are ignored
+func (c *Client) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	return runtimeclient.IgnoreNotFound(c.Interface.Delete(ctx, obj))
+}
+
+// Installed checks whether ReferenceGrant CRD is installed
+func (c *Client) Installed(ctx context.Context) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersion.WithKind("ReferenceGrantList"))
+	if err := c.Interface.List(ctx, list, runtimeclient.Limit(1)); err != nil {
+		return fmt.Errorf("can't list ReferenceGrants, are Gateway API CRDs installed: %w", err)
+	}
+	return nil
+}
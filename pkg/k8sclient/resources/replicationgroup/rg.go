@@ -22,13 +22,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
 	"github.com/dell/cert-csi/pkg/utils"
 
 	replv1 "github.com/dell/csm-replication/api/v1"
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -36,6 +37,10 @@ import (
 const (
 	// Timeout is a timeout interval for RG actions
 	Timeout = 1800 * time.Second
+	// Poll is a maximum poll interval of RG state
+	Poll = 10 * time.Second
+	// DeleteTimeout is how long deleted RG is waited for to be gone
+	DeleteTimeout = 5 * time.Minute
 )
 
 // Client is a client for managing RGs
@@ -92,6 +97,23 @@ func (c *Client) Get(ctx context.Context, name string) *RG {
 	}
 }
 
+// WaitUntilGone stalls until deleted replication group no longer can be found in Kubernetes
+func (rg *RG) WaitUntilGone(ctx context.Context) error {
+	startTime := time.Now()
+	pollErr := commonparams.PollUntil(ctx, Poll, DeleteTimeout, nil, func(context.Context) (bool, error) {
+		err := rg.Client.Interface.Get(ctx, types.NamespacedName{Name: rg.Object.Name}, &replv1.DellCSIReplicationGroup{})
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if pollErr != nil {
+		return pollErr
+	}
+	logrus.Debugf("RG %s was deleted in %s", rg.Object.Name, time.Since(startTime))
+	return nil
+}
+
 // Name returns replication group name
 func (rg *RG) Name() string {
 	return rg.Object.Name
@@ -189,8 +211,8 @@ func (rg *RG) stablelize(ctx context.Context, rgAction, expectedState string) er
 		error:   nil,
 	}
 
-	pollErr := wait.PollImmediate(10*time.Second, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(pollCtx context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping RG state check wait polling")
@@ -200,6 +222,7 @@ func (rg *RG) stablelize(ctx context.Context, rgAction, expectedState string) er
 			}
 
 			rgObject = rg.Client.Get(ctx, rgName)
+			commonparams.ReportState(pollCtx, rgObject.Object.Status.ReplicationLinkState.State)
 			log.Infof("current RG state is %s and expected is %s", rgObject.Object.Status.ReplicationLinkState.State, expectedState)
 			if rgObject.Object.Status.ReplicationLinkState.State != expectedState {
 				log.Debugf("RG is not reached to expected state %s", expectedState)
@@ -34,6 +34,8 @@ const (
 	RemoteClusterID = "replication.storage.dell.com/remoteClusterID"
 	// RemoteStorageClassName represents remote sc name
 	RemoteStorageClassName = "replication.storage.dell.com/remoteStorageClassName"
+	// TempClassLabel marks classes cert-csi creates for the duration of a run, so leftovers can be found and deleted
+	TempClassLabel = "cert-csi.dell.com/temporary-class"
 )
 
 // Client conatins sc interface and kubeclient
@@ -107,7 +109,7 @@ func (c *Client) MakeStorageClass(name string, provisioner string) *v1.StorageCl
 // DuplicateStorageClass creates a copy of storage class
 func (c *Client) DuplicateStorageClass(name string, sourceSc *v1.StorageClass) *v1.StorageClass {
 	newSc := sourceSc.DeepCopy()
-	newSc.ObjectMeta = metav1.ObjectMeta{Name: name}
+	newSc.ObjectMeta = metav1.ObjectMeta{Name: name, Labels: map[string]string{TempClassLabel: "true"}}
 	return newSc
 }
 
@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
 	"github.com/dell/cert-csi/pkg/utils"
 
@@ -38,7 +39,6 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	tappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -214,7 +214,9 @@ func (c *Client) MakeStatefulSetFromYaml(filePath string) *appsv1.StatefulSet {
 func (c *Client) Create(ctx context.Context, sts *appsv1.StatefulSet) *StatefulSet {
 	log := utils.GetLoggerFromContext(ctx)
 	var funcErr error
-	newSTS, err := c.Interface.Create(ctx, sts, metav1.CreateOptions{})
+	newSTS, err := commonparams.CreateNamed(sts, func(sts *appsv1.StatefulSet) (*appsv1.StatefulSet, error) {
+		return c.Interface.Create(ctx, sts, metav1.CreateOptions{})
+	})
 	if err != nil {
 		funcErr = err
 	}
@@ -380,8 +382,8 @@ func (sts *StatefulSet) WaitForRunningAndReady(ctx context.Context, numPodsRunni
 	if sts.Client.Timeout != 0 {
 		timeout = time.Duration(sts.Client.Timeout) * time.Second
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(pollCtx context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping sts wait polling")
@@ -412,14 +414,16 @@ func (sts *StatefulSet) WaitForRunningAndReady(ctx context.Context, numPodsRunni
 				return true, nil
 			}
 
+			ready := 0
 			for i, p := range podList.Items {
 				isReady := pod.IsPodReady(&podList.Items[i])
 				log.Debugf("Waiting for pod %v to be ready, currently = %v", p.Name, isReady)
-				if p.Status.Phase != v1.PodRunning || !isReady {
-					return false, nil
+				if p.Status.Phase == v1.PodRunning && isReady {
+					ready++
 				}
 			}
-			return true, nil
+			commonparams.ReportState(pollCtx, strconv.Itoa(ready))
+			return ready == len(podList.Items), nil
 		})
 
 	if pollErr != nil {
@@ -457,7 +461,7 @@ func (sts *StatefulSet) WaitUntilGone(ctx context.Context) error {
 		timeout = time.Duration(sts.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout/100*95, func() (done bool, err error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout/100*95, commonparams.Watch(sts.Client.Interface, commonparams.NameSelector(sts.Set.Name)), func(context.Context) (done bool, err error) {
 		done, err = sts.pollWait(ctx)
 		return done, err
 	})
@@ -474,7 +478,7 @@ func (sts *StatefulSet) WaitUntilGone(ctx context.Context) error {
 		if er != nil {
 			return er
 		}
-		pollErr = wait.PollImmediate(Poll, timeout/2, func() (done bool, err error) {
+		pollErr = commonparams.PollUntil(ctx, Poll, timeout/2, commonparams.Watch(sts.Client.Interface, commonparams.NameSelector(sts.Set.Name)), func(context.Context) (done bool, err error) {
 			done, err = sts.pollWait(ctx)
 			return done, err
 		})
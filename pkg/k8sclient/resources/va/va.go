@@ -20,9 +20,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	v12 "k8s.io/client-go/kubernetes/typed/storage/v1"
 )
 
@@ -54,8 +55,8 @@ func (c *Client) WaitUntilNoneLeft(ctx context.Context) error {
 	} else {
 		timeout = c.CustomTimeout
 	}
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			vaList, err := c.Interface.List(ctx, metav1.ListOptions{})
 			if err != nil {
 				return false, err
@@ -87,8 +88,8 @@ func (c *Client) WaitUntilVaGone(ctx context.Context, pvName string) error {
 		timeout = c.CustomTimeout
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			vaList, err := c.Interface.List(ctx, metav1.ListOptions{
 				FieldSelector: "",
 			})
@@ -0,0 +1,176 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	"github.com/dell/cert-csi/pkg/utils"
+
+	"github.com/fatih/color"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Timeout is a timeout interval for backups and restores
+	Timeout = 1800 * time.Second
+	// DefaultNamespace is the namespace Velero is installed to by default
+	DefaultNamespace = "velero"
+)
+
+var (
+	// Poll is a poll interval of backup and restore phase
+	Poll = 5 * time.Second
+
+	// GroupVersion of Velero API
+	GroupVersion = schema.GroupVersion{Group: "velero.io", Version: "v1"}
+)
+
+// Client manages Velero backups and restores, Velero types are handled as unstructured objects
+// so its API module isn't required
+type Client struct {
+	Interface runtimeclient.Client
+	// Namespace is the namespace Velero is installed to, backups and restores are created there
+	Namespace string
+	Timeout   int
+}
+
+// Operation is a Velero backup or restore
+type Operation struct {
+	Client *Client
+	Object *unstructured.Unstructured
+}
+
+// Name returns name of the backup or restore
+func (o *Operation) Name() string {
+	return o.Object.GetName()
+}
+
+// Phase returns the phase backup or restore is in, empty if Velero hasn't processed it yet
+func (o *Operation) Phase() string {
+	phase, _, _ := unstructured.NestedString(o.Object.Object, "status", "phase")
+	return phase
+}
+
+func (c *Client) newObject(kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersion.WithKind(kind))
+	obj.SetNamespace(c.Namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// CreateBackup creates backup of the namespace, volumes are backed up with CSI snapshots
+func (c *Client) CreateBackup(ctx context.Context, name, namespace string) (*Operation, error) {
+	obj := c.newObject("Backup", name)
+	obj.Object["spec"] = map[string]interface{}{
+		"includedNamespaces": []interface{}{namespace},
+		"snapshotVolumes":    true,
+		"ttl":                "24h0m0s",
+	}
+	if err := c.Interface.Create(ctx, obj); err != nil {
+		return nil, err
+	}
+	utils.GetLoggerFromContext(ctx).Debugf("Created Velero backup %s of namespace %s", name, namespace)
+	return &Operation{Client: c, Object: obj}, nil
+}
+
+// CreateRestore creates restore of the backup, namespace of the backup is restored as targetNamespace
+func (c *Client) CreateRestore(ctx context.Context, name, backup, namespace, targetNamespace string) (*Operation, error) {
+	obj := c.newObject("Restore", name)
+	obj.Object["spec"] = map[string]interface{}{
+		"backupName":         backup,
+		"includedNamespaces": []interface{}{namespace},
+		"namespaceMapping":   map[string]interface{}{namespace: targetNamespace},
+		"restorePVs":         true,
+	}
+	if err := c.Interface.Create(ctx, obj); err != nil {
+		return nil, err
+	}
+	utils.GetLoggerFromContext(ctx).Debugf("Created Velero restore %s of backup %s", name, backup)
+	return &Operation{Client: c, Object: obj}, nil
+}
+
+// DeleteBackup requests deletion of backup with its data and snapshots, deleting the Backup object alone would leave them
+func (c *Client) DeleteBackup(ctx context.Context, backup string) error {
+	obj := c.newObject("DeleteBackupRequest", "")
+	obj.SetGenerateName(backup + "-")
+	obj.Object["spec"] = map[string]interface{}{"backupName": backup}
+	return c.Interface.Create(ctx, obj)
+}
+
+// Delete deletes the restore or backup object
+func (o *Operation) Delete(ctx context.Context) error {
+	return runtimeclient.IgnoreNotFound(o.Client.Interface.Delete(ctx, o.Object))
+}
+
+// WaitForCompletion stalls until backup or restore is completed, error is returned if Velero failed it
+func (o *Operation) WaitForCompletion(ctx context.Context) error {
+	log := utils.GetLoggerFromContext(ctx)
+	kind := o.Object.GetKind()
+	log.Infof("Waiting for Velero %s %s to complete", kind, o.Name())
+	timeout := Timeout
+	if o.Client.Timeout != 0 {
+		timeout = time.Duration(o.Client.Timeout) * time.Second
+	}
+
+	startTime := time.Now()
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil, func(context.Context) (bool, error) {
+		select {
+		case <-ctx.Done():
+			return true, fmt.Errorf("stopped waiting for %s %s", kind, o.Name())
+		default:
+		}
+		if err := o.Client.Interface.Get(ctx, types.NamespacedName{Namespace: o.Object.GetNamespace(), Name: o.Name()}, o.Object); err != nil {
+			return false, err
+		}
+		switch o.Phase() {
+		case "Completed":
+			return true, nil
+		case "Failed", "PartiallyFailed", "FailedValidation":
+			errs, _, _ := unstructured.NestedInt64(o.Object.Object, "status", "errors")
+			reason, _, _ := unstructured.NestedString(o.Object.Object, "status", "failureReason")
+			validation, _, _ := unstructured.NestedStringSlice(o.Object.Object, "status", "validationErrors")
+			return false, fmt.Errorf("%s %s is %s with %d errors: %s %v", kind, o.Name(), o.Phase(), errs, reason, validation)
+		}
+		return false, nil
+	})
+	if pollErr != nil {
+		return pollErr
+	}
+	log.Infof("Velero %s %s completed in %s", kind, o.Name(), color.New(color.FgHiYellow).Sprint(time.Since(startTime)))
+	return nil
+}
+
+// Installed checks whether Velero is installed to the namespace of client
+func (c *Client) Installed(ctx context.Context) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersion.WithKind("BackupStorageLocationList"))
+	if err := c.Interface.List(ctx, list, runtimeclient.InNamespace(c.Namespace)); err != nil {
+		return fmt.Errorf("can't list Velero backup storage locations, is Velero installed to %s: %w", c.Namespace, err)
+	}
+	if len(list.Items) == 0 {
+		return fmt.Errorf("velero in %s has no backup storage locations", c.Namespace)
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
 	"github.com/dell/cert-csi/pkg/utils"
 
 	vgsAlpha "github.com/dell/csi-volumegroup-snapshotter/api/v1"
@@ -28,7 +29,6 @@ import (
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -157,8 +157,8 @@ func (c *Client) WaitForComplete(ctx context.Context, name, namespace string) er
 	}
 	var snapList string
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(pollCtx context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping vgs wait polling")
@@ -168,6 +168,7 @@ func (c *Client) WaitForComplete(ctx context.Context, name, namespace string) er
 			}
 
 			gotVg := c.Get(ctx, name, namespace)
+			commonparams.ReportState(pollCtx, gotVg.Object.Status.Status)
 			if gotVg.Object.Status.Status != StatusComplete {
 				return false, nil
 			}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
 	"github.com/dell/cert-csi/pkg/utils"
 
 	"github.com/fatih/color"
@@ -30,7 +31,6 @@ import (
 	"github.com/sirupsen/logrus"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const (
@@ -122,8 +122,8 @@ func (sc *SnapshotClient) WaitForAllToBeReady(ctx context.Context) error {
 		timeout = time.Duration(sc.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(sc.Interface, metav1.ListOptions{}),
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping Snap wait polling")
@@ -164,6 +164,22 @@ func IsSnapReady(sn *v1beta1.VolumeSnapshot) bool {
 	return ready
 }
 
+// LastError returns error snapshot controller reported in status of snapshot, empty if there is none
+func LastError(sn *v1beta1.VolumeSnapshot) string {
+	if sn.Status == nil || sn.Status.Error == nil || sn.Status.Error.Message == nil {
+		return ""
+	}
+	return *sn.Status.Error.Message
+}
+
+// ReadyTimeout returns how long snapshots of the client are waited for to become ready
+func (sc *SnapshotClient) ReadyTimeout() time.Duration {
+	if sc.Timeout != 0 {
+		return time.Duration(sc.Timeout) * time.Second
+	}
+	return Timeout
+}
+
 // WaitUntilGone waits until snapshot is deleted
 func (snap *Snapshot) WaitUntilGone(ctx context.Context) error {
 	log := utils.GetLoggerFromContext(ctx)
@@ -173,7 +189,7 @@ func (snap *Snapshot) WaitUntilGone(ctx context.Context) error {
 		timeout = time.Duration(snap.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout/100*95, func() (done bool, err error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout/100*95, commonparams.Watch(snap.Client.Interface, commonparams.NameSelector(snap.Object.Name)), func(context.Context) (done bool, err error) {
 		done, err = snap.pollWait(ctx)
 		return done, err
 	})
@@ -189,7 +205,7 @@ func (snap *Snapshot) WaitUntilGone(ctx context.Context) error {
 		if er != nil {
 			return er
 		}
-		pollErr = wait.PollImmediate(Poll, timeout/2, func() (done bool, err error) {
+		pollErr = commonparams.PollUntil(ctx, Poll, timeout/2, commonparams.Watch(snap.Client.Interface, commonparams.NameSelector(snap.Object.Name)), func(context.Context) (done bool, err error) {
 			done, err = snap.pollWait(ctx)
 			return done, err
 		})
@@ -228,13 +244,10 @@ func (snap *Snapshot) pollWait(ctx context.Context) (bool, error) {
 func (snap *Snapshot) WaitForRunning(ctx context.Context) error {
 	log := utils.GetLoggerFromContext(ctx)
 	log.Infof("Waiting for Snapshot '%s' to be READY", snap.Object.Name)
-	timeout := Timeout
-	if snap.Client.Timeout != 0 {
-		timeout = time.Duration(snap.Client.Timeout) * time.Second
-	}
+	timeout := snap.Client.ReadyTimeout()
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, commonparams.Watch(snap.Client.Interface, commonparams.NameSelector(snap.Object.Name)),
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping Snap wait polling")
@@ -254,7 +267,15 @@ func (snap *Snapshot) WaitForRunning(ctx context.Context) error {
 		})
 
 	if pollErr != nil {
-		return pollErr
+		if ctx.Err() != nil {
+			return pollErr
+		}
+		// Stuck snapshot is reported with the error snapshot controller left in its status
+		lastErr := "none reported"
+		if sn, err := snap.Client.Interface.Get(ctx, snap.Object.Name, metav1.GetOptions{}); err == nil && LastError(sn) != "" {
+			lastErr = LastError(sn)
+		}
+		return fmt.Errorf("snapshot %s isn't ready after %s: %w; last error: %s", snap.Object.Name, timeout, pollErr, lastErr)
 	}
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
 	"github.com/dell/cert-csi/pkg/utils"
 
 	"github.com/fatih/color"
@@ -30,7 +31,6 @@ import (
 	"github.com/sirupsen/logrus"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const (
@@ -104,7 +104,7 @@ func (cont *SnapshotContent) WaitUntilGone(ctx context.Context) error {
 		timeout = time.Duration(cont.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout/2, func() (done bool, err error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout/2, nil, func(context.Context) (done bool, err error) {
 		done, err = cont.pollWait(ctx)
 		return done, err
 	})
@@ -121,7 +121,7 @@ func (cont *SnapshotContent) WaitUntilGone(ctx context.Context) error {
 		if er != nil {
 			return er
 		}
-		pollErr = wait.PollImmediate(Poll, timeout/100*95, func() (done bool, err error) {
+		pollErr = commonparams.PollUntil(ctx, Poll, timeout/100*95, nil, func(context.Context) (done bool, err error) {
 			done, err = cont.pollWait(ctx)
 			return done, err
 		})
@@ -165,8 +165,8 @@ func (cont *SnapshotContent) WaitForRunning(ctx context.Context) error {
 		timeout = time.Duration(cont.Client.Timeout) * time.Second
 	}
 
-	pollErr := wait.PollImmediate(Poll, timeout,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, Poll, timeout, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping Snap wait polling")
@@ -0,0 +1,213 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// serviceAccountNamespace is a file namespace of the pod is mounted to by Kubernetes
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Reasons of events runner status is reported with
+const (
+	ReasonSuiteStarted = "SuiteStarted"
+	ReasonSuitePassed  = "SuitePassed"
+	ReasonSuiteFailed  = "SuiteFailed"
+	ReasonRunPassed    = "CertificationPassed"
+	ReasonRunFailed    = "CertificationFailed"
+)
+
+// InCluster checks whether cert-csi runs in a pod
+func InCluster() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
+
+// RunnerStatus reports progress of run as Kubernetes Events of the pod cert-csi runs in, and optionally as
+// a ConfigMap in its namespace, so cluster dashboards show certification status without external systems.
+// Reporting stops after the first error, so missing permissions don't flood the log
+type RunnerStatus struct {
+	clientSet kubernetes.Interface
+	pod       v1.ObjectReference
+	// ConfigMap is a name of ConfigMap status is written to, disabled if empty
+	ConfigMap string
+
+	mutex    sync.Mutex
+	disabled bool
+	passed   int
+	failed   int
+}
+
+// NewRunnerStatus creates RunnerStatus of the pod cert-csi runs in, pod is identified by POD_NAME and POD_NAMESPACE
+// set with downward API, or by hostname and namespace of its service account
+func NewRunnerStatus(ctx context.Context, clientSet kubernetes.Interface, configMap string) (*RunnerStatus, error) {
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		name = hostname
+	}
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("can't find namespace of cert-csi pod, set POD_NAMESPACE: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	pod, err := clientSet.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("can't get cert-csi pod %s/%s, set POD_NAME: %w", namespace, name, err)
+	}
+	return &RunnerStatus{
+		clientSet: clientSet,
+		pod: v1.ObjectReference{
+			Kind:       "Pod",
+			APIVersion: "v1",
+			Name:       pod.Name,
+			Namespace:  pod.Namespace,
+			UID:        pod.UID,
+		},
+		ConfigMap: configMap,
+	}, nil
+}
+
+// Pod returns namespace and name of the pod status is reported on
+func (rs *RunnerStatus) Pod() string {
+	return rs.pod.Namespace + "/" + rs.pod.Name
+}
+
+// SuiteStarted reports suite started with storage class
+func (rs *RunnerStatus) SuiteStarted(ctx context.Context, suite, storageClass string) {
+	if rs == nil {
+		return
+	}
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.report(ctx, v1.EventTypeNormal, ReasonSuiteStarted, fmt.Sprintf("%s started with storage class %s", suite, storageClass), "running", "")
+}
+
+// SuiteFinished reports result of suite, message is the error suite failed with
+func (rs *RunnerStatus) SuiteFinished(ctx context.Context, suite, storageClass string, passed bool, elapsed time.Duration, message string) {
+	if rs == nil {
+		return
+	}
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	if passed {
+		rs.passed++
+		rs.report(ctx, v1.EventTypeNormal, ReasonSuitePassed,
+			fmt.Sprintf("%s passed with storage class %s in %s", suite, storageClass, elapsed.Round(time.Second)), "running", "")
+		return
+	}
+	rs.failed++
+	rs.report(ctx, v1.EventTypeWarning, ReasonSuiteFailed,
+		fmt.Sprintf("%s failed with storage class %s: %s", suite, storageClass, message), "running", message)
+}
+
+// RunFinished reports result of the whole run, err is the error run failed with
+func (rs *RunnerStatus) RunFinished(ctx context.Context, run string, err error) {
+	if rs == nil {
+		return
+	}
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	summary := fmt.Sprintf("run %s: %d suites passed, %d failed", run, rs.passed, rs.failed)
+	if err != nil {
+		rs.report(ctx, v1.EventTypeWarning, ReasonRunFailed, summary+": "+err.Error(), "failed", err.Error())
+		return
+	}
+	rs.report(ctx, v1.EventTypeNormal, ReasonRunPassed, summary, "passed", "")
+}
+
+// report emits event on the pod and updates ConfigMap with state of the run
+func (rs *RunnerStatus) report(ctx context.Context, eventType, reason, message, state, lastError string) {
+	if rs.disabled {
+		return
+	}
+	err := rs.emit(ctx, eventType, reason, message)
+	if err == nil && rs.ConfigMap != "" {
+		err = rs.updateConfigMap(ctx, state, message, lastError)
+	}
+	if err != nil {
+		rs.disabled = true
+		logrus.Warnf("Can't report status of run in cluster, reporting disabled; error=%v", err)
+	}
+}
+
+func (rs *RunnerStatus) emit(ctx context.Context, eventType, reason, message string) error {
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rs.pod.Name + "." + UniqueSuffix(),
+			Namespace: rs.pod.Namespace,
+		},
+		InvolvedObject:      rs.pod,
+		Reason:              reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              v1.EventSource{Component: "cert-csi"},
+		ReportingController: "cert-csi",
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	_, err := rs.clientSet.CoreV1().Events(rs.pod.Namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+func (rs *RunnerStatus) updateConfigMap(ctx context.Context, state, message, lastError string) error {
+	data := map[string]string{
+		"state":   state,
+		"passed":  strconv.Itoa(rs.passed),
+		"failed":  strconv.Itoa(rs.failed),
+		"last":    message,
+		"updated": time.Now().Format(time.RFC3339),
+	}
+	if lastError != "" {
+		data["lastError"] = lastError
+	}
+
+	configMaps := rs.clientSet.CoreV1().ConfigMaps(rs.pod.Namespace)
+	cm, err := configMaps.Get(ctx, rs.ConfigMap, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: rs.ConfigMap, Namespace: rs.pod.Namespace},
+			Data:       data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cm.Data = data
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
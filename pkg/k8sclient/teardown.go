@@ -0,0 +1,228 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/utils"
+
+	"github.com/fatih/color"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Teardown tiers, resources of namespace are deleted tier by tier so nothing is deleted while objects of
+// the previous tier still use it, and deletion of every tier is measured on its own
+const (
+	TierPods      = "pods"
+	TierClaims    = "pvcs"
+	TierSnapshots = "snapshots"
+	TierNamespace = "namespace"
+)
+
+// TeardownTierPoll is a poll interval of waiting for teardown tier to be gone
+var TeardownTierPoll = NamespacePoll
+
+// TeardownTiers are names of teardown tiers in order they are deleted in
+var TeardownTiers = []string{TierPods, TierClaims, TierSnapshots, TierNamespace}
+
+// TierTimeouts limit how long deletion of each tier is waited for, tiers missing from it use namespace timeout
+type TierTimeouts map[string]time.Duration
+
+// ParseTierTimeouts parses comma separated tier=duration pairs, ex. pods=2m,pvcs=5m
+func ParseTierTimeouts(s string) (TierTimeouts, error) {
+	timeouts := make(TierTimeouts)
+	if s == "" {
+		return timeouts, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		tier, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("teardown timeout %q must be tier=duration", pair)
+		}
+		known := false
+		for _, t := range TeardownTiers {
+			known = known || t == tier
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown teardown tier %s, expected one of %s", tier, strings.Join(TeardownTiers, ", "))
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q of teardown tier %s", value, tier)
+		}
+		timeouts[tier] = d
+	}
+	return timeouts, nil
+}
+
+// TierResult describes deletion of a single teardown tier
+type TierResult struct {
+	Tier     string
+	Started  time.Time
+	Duration time.Duration
+	// Deleted is number of objects tier had when its deletion started, Remaining is number of them left after timeout
+	Deleted   int
+	Remaining int
+	Err       error
+}
+
+// teardownTier deletes objects of a single tier and counts ones still there
+type teardownTier struct {
+	name   string
+	delete func(ctx context.Context) error
+	count  func(ctx context.Context) (int, error)
+}
+
+// TeardownNamespace deletes resources of namespace tier by tier: workloads and pods, claims, snapshots and then
+// namespace itself unless keepNamespace is set. Every tier is waited for before the next one starts, tier which
+// isn't gone in time doesn't stop the following ones, so namespace deletion still sorts out what is left
+func (c *KubeClient) TeardownNamespace(ctx context.Context, namespace string, keepNamespace bool, timeouts TierTimeouts) ([]TierResult, error) {
+	log := utils.GetLoggerFromContext(ctx)
+	startTime := time.Now()
+	background := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &background}
+	core := c.ClientSet.CoreV1()
+
+	snapClient, err := c.CreateSnapshotGAClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	tiers := []teardownTier{
+		{
+			name: TierPods,
+			delete: func(ctx context.Context) error {
+				if err := c.ClientSet.AppsV1().StatefulSets(namespace).DeleteCollection(ctx, opts, metav1.ListOptions{}); err != nil {
+					return err
+				}
+				if err := c.ClientSet.AppsV1().Deployments(namespace).DeleteCollection(ctx, opts, metav1.ListOptions{}); err != nil {
+					return err
+				}
+				return core.Pods(namespace).DeleteCollection(ctx, opts, metav1.ListOptions{})
+			},
+			count: func(ctx context.Context) (int, error) {
+				pods, err := core.Pods(namespace).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(pods.Items), nil
+			},
+		},
+		{
+			name: TierClaims,
+			delete: func(ctx context.Context) error {
+				return core.PersistentVolumeClaims(namespace).DeleteCollection(ctx, opts, metav1.ListOptions{})
+			},
+			count: func(ctx context.Context) (int, error) {
+				pvcs, err := core.PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(pvcs.Items), nil
+			},
+		},
+		{
+			name: TierSnapshots,
+			// Snapshot CRDs may be missing from cluster, there is nothing to delete then
+			delete: func(ctx context.Context) error {
+				if err := snapClient.Interface.DeleteCollection(ctx, opts, metav1.ListOptions{}); err != nil && !apierrs.IsNotFound(err) {
+					return err
+				}
+				return nil
+			},
+			count: func(ctx context.Context) (int, error) {
+				snaps, err := snapClient.Interface.List(ctx, metav1.ListOptions{})
+				if apierrs.IsNotFound(err) {
+					return 0, nil
+				}
+				if err != nil {
+					return 0, err
+				}
+				return len(snaps.Items), nil
+			},
+		},
+	}
+
+	results := runTiers(ctx, tiers, timeouts, c.namespaceTimeout())
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", r.Tier, r.Err))
+		}
+	}
+
+	if keepNamespace {
+		if len(failed) != 0 {
+			return results, fmt.Errorf("resources of namespace %s weren't deleted; error=%s", namespace, strings.Join(failed, "; "))
+		}
+		log.Infof("Namespace %s was emptied in %s", namespace, color.HiYellowString(time.Since(startTime).String()))
+		return results, nil
+	}
+
+	timeout := c.namespaceTimeout()
+	if d, ok := timeouts[TierNamespace]; ok {
+		timeout = d
+	}
+	ns := TierResult{Tier: TierNamespace, Started: time.Now(), Deleted: 1}
+	ns.Err = c.deleteNamespace(ctx, namespace, timeout)
+	ns.Duration = time.Since(ns.Started)
+	if ns.Err != nil {
+		ns.Remaining = 1
+	}
+	return append(results, ns), ns.Err
+}
+
+// runTiers deletes tiers one by one, waiting up to tier timeout for every one of them to be gone
+func runTiers(ctx context.Context, tiers []teardownTier, timeouts TierTimeouts, defaultTimeout time.Duration) []TierResult {
+	log := utils.GetLoggerFromContext(ctx)
+	var results []TierResult
+	for _, tier := range tiers {
+		r := TierResult{Tier: tier.name, Started: time.Now()}
+		timeout := defaultTimeout
+		if d, ok := timeouts[tier.name]; ok {
+			timeout = d
+		}
+
+		r.Deleted, r.Err = tier.count(ctx)
+		if r.Err == nil && r.Deleted != 0 {
+			r.Err = tier.delete(ctx)
+		}
+		if r.Err == nil && r.Deleted != 0 {
+			r.Err = wait.PollUntilContextTimeout(ctx, TeardownTierPoll, timeout, true, func(ctx context.Context) (bool, error) {
+				left, err := tier.count(ctx)
+				if err != nil {
+					return false, nil
+				}
+				r.Remaining = left
+				return left == 0, nil
+			})
+		}
+		r.Duration = time.Since(r.Started)
+		if r.Err != nil {
+			log.Warnf("Teardown tier %s isn't gone after %s; error=%v", tier.name, r.Duration.Round(time.Second), r.Err)
+		} else if r.Deleted != 0 {
+			log.Debugf("Teardown tier %s with %d objects was deleted in %s", tier.name, r.Deleted, r.Duration)
+		}
+		results = append(results, r)
+	}
+	return results
+}
@@ -0,0 +1,411 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/sc"
+	snapv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	snapbeta "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1beta1"
+	snapclient "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// TempClassLabel marks storage and snapshot classes created for the duration of a run, classes with it are never
+// left in cluster on purpose, so leftovers of interrupted runs are replaced or deleted by cleanup
+const TempClassLabel = sc.TempClassLabel
+
+// ClassTemplates are storage and snapshot classes created for the duration of a run
+type ClassTemplates struct {
+	StorageClasses  []StorageClassTemplate  `json:"storageClasses,omitempty"`
+	SnapshotClasses []SnapshotClassTemplate `json:"snapshotClasses,omitempty"`
+}
+
+// StorageClassTemplate is a StorageClass copied from an existing one with overrides
+type StorageClassTemplate struct {
+	Name string `json:"name"`
+	// From is storage class being copied, the first storage class of the run is copied if empty
+	From string `json:"from,omitempty"`
+	// Parameters override parameters of copied class, parameter with empty value is removed
+	Parameters           map[string]string                 `json:"parameters,omitempty"`
+	VolumeBindingMode    *storagev1.VolumeBindingMode      `json:"volumeBindingMode,omitempty"`
+	ReclaimPolicy        *v1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	AllowVolumeExpansion *bool                             `json:"allowVolumeExpansion,omitempty"`
+	MountOptions         []string                          `json:"mountOptions,omitempty"`
+}
+
+// SnapshotClassTemplate is a VolumeSnapshotClass copied from an existing one with overrides
+type SnapshotClassTemplate struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	// Parameters override parameters of copied class, parameter with empty value is removed
+	Parameters     map[string]string `json:"parameters,omitempty"`
+	DeletionPolicy string            `json:"deletionPolicy,omitempty"`
+}
+
+// LoadClassTemplates reads templates of temporary classes from yaml file
+func LoadClassTemplates(path string) (*ClassTemplates, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("can't read class templates: %v", err)
+	}
+	templates := &ClassTemplates{}
+	if err := yaml.UnmarshalStrict(data, templates); err != nil {
+		return nil, fmt.Errorf("can't parse class templates: %v", err)
+	}
+	if len(templates.StorageClasses) == 0 && len(templates.SnapshotClasses) == 0 {
+		return nil, errors.New("class templates define no classes")
+	}
+	names := make(map[string]bool)
+	for _, t := range templates.StorageClasses {
+		if t.Name == "" {
+			return nil, errors.New("every storage class template must have a name")
+		}
+		if names["sc/"+t.Name] {
+			return nil, fmt.Errorf("storage class %s is defined more than once", t.Name)
+		}
+		names["sc/"+t.Name] = true
+	}
+	for _, t := range templates.SnapshotClasses {
+		if t.Name == "" || t.From == "" {
+			return nil, errors.New("every snapshot class template must have a name and a class it's copied from")
+		}
+		if names["vsc/"+t.Name] {
+			return nil, fmt.Errorf("snapshot class %s is defined more than once", t.Name)
+		}
+		names["vsc/"+t.Name] = true
+	}
+	return templates, nil
+}
+
+// SweepSnapshotClass returns templates of snapshot classes copied from class with every combination of swept
+// parameter values, every sweep is in parameter=value1,value2 format and empty value removes parameter
+func SweepSnapshotClass(from string, sweep []string) ([]SnapshotClassTemplate, error) {
+	variants := []map[string]string{{}}
+	swept := make(map[string]bool)
+	for _, spec := range sweep {
+		key, values, ok := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("sweep %q must be in parameter=value1,value2 format", spec)
+		}
+		if swept[key] {
+			return nil, fmt.Errorf("parameter %s is swept more than once", key)
+		}
+		swept[key] = true
+		var next []map[string]string
+		for _, variant := range variants {
+			for _, value := range strings.Split(values, ",") {
+				parameters := map[string]string{key: strings.TrimSpace(value)}
+				for k, v := range variant {
+					parameters[k] = v
+				}
+				next = append(next, parameters)
+			}
+		}
+		variants = next
+	}
+	if len(swept) == 0 {
+		return nil, errors.New("no snapshot class parameters to sweep")
+	}
+	templates := make([]SnapshotClassTemplate, 0, len(variants))
+	for i, parameters := range variants {
+		templates = append(templates, SnapshotClassTemplate{
+			Name:       fmt.Sprintf("%s-sweep-%d", from, i+1),
+			From:       from,
+			Parameters: parameters,
+		})
+	}
+	return templates, nil
+}
+
+// Overrides returns parameter overrides of template sorted by name, ex. retention=1h, consistency=
+func (t SnapshotClassTemplate) Overrides() string {
+	overrides := make([]string, 0, len(t.Parameters))
+	for k, v := range t.Parameters {
+		overrides = append(overrides, k+"="+v)
+	}
+	sort.Strings(overrides)
+	return strings.Join(overrides, ", ")
+}
+
+// overrideParameters returns copy of parameters with overrides applied, empty override removes parameter
+func overrideParameters(parameters, overrides map[string]string) map[string]string {
+	result := make(map[string]string, len(parameters)+len(overrides))
+	for k, v := range parameters {
+		result[k] = v
+	}
+	for k, v := range overrides {
+		if v == "" {
+			delete(result, k)
+		} else {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// tempClassMeta returns metadata of temporary class, existing class with the name must be a leftover temporary class
+func tempClassMeta(name string, existing *metav1.ObjectMeta) (metav1.ObjectMeta, error) {
+	if existing != nil && existing.Labels[TempClassLabel] != "true" {
+		return metav1.ObjectMeta{}, fmt.Errorf("class %s already exists and wasn't created from template, choose another name", name)
+	}
+	return metav1.ObjectMeta{Name: name, Labels: map[string]string{TempClassLabel: "true"}}, nil
+}
+
+// TempClasses creates storage and snapshot classes from templates and deletes them once run is over
+type TempClasses struct {
+	clientSet   kubernetes.Interface
+	snapshots   snapclient.Interface
+	snapshotAPI SnapshotAPIVersion
+
+	storageClasses  []string
+	snapshotClasses []string
+}
+
+// NewTempClasses creates TempClasses of the cluster, snapshot classes can be created only if snapshot API is served
+func (c *KubeClient) NewTempClasses() (*TempClasses, error) {
+	tc := &TempClasses{clientSet: c.ClientSet}
+	api, err := c.SnapshotAPI()
+	if err != nil {
+		return nil, err
+	}
+	if api != SnapshotAPINone {
+		if tc.snapshots, err = snapclient.NewForConfig(c.Config); err != nil {
+			return nil, err
+		}
+		tc.snapshotAPI = api
+	}
+	return tc, nil
+}
+
+// Create creates every class of templates, storage classes without source are copied from defaultFrom. Classes
+// created before an error are kept, so they are deleted by Delete
+func (tc *TempClasses) Create(ctx context.Context, templates *ClassTemplates, defaultFrom string) error {
+	for _, t := range templates.StorageClasses {
+		if t.From == "" {
+			t.From = defaultFrom
+		}
+		if err := tc.createStorageClass(ctx, t); err != nil {
+			return err
+		}
+	}
+	for _, t := range templates.SnapshotClasses {
+		if err := tc.createSnapshotClass(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StorageClasses returns names of created storage classes
+func (tc *TempClasses) StorageClasses() []string {
+	return tc.storageClasses
+}
+
+func (tc *TempClasses) createStorageClass(ctx context.Context, t StorageClassTemplate) error {
+	classes := tc.clientSet.StorageV1().StorageClasses()
+	source, err := classes.Get(ctx, t.From, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("can't get storage class %s to copy: %w", t.From, err)
+	}
+	var existingMeta *metav1.ObjectMeta
+	existing, err := classes.Get(ctx, t.Name, metav1.GetOptions{})
+	if err == nil {
+		existingMeta = &existing.ObjectMeta
+	} else if !apierrs.IsNotFound(err) {
+		return err
+	}
+	meta, err := tempClassMeta(t.Name, existingMeta)
+	if err != nil {
+		return err
+	}
+	if existingMeta != nil {
+		logrus.Warnf("Replacing storage class %s left by interrupted run", t.Name)
+		if err := classes.Delete(ctx, t.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	sc := source.DeepCopy()
+	sc.ObjectMeta = meta
+	sc.Parameters = overrideParameters(source.Parameters, t.Parameters)
+	if t.VolumeBindingMode != nil {
+		sc.VolumeBindingMode = t.VolumeBindingMode
+	}
+	if t.ReclaimPolicy != nil {
+		sc.ReclaimPolicy = t.ReclaimPolicy
+	}
+	if t.AllowVolumeExpansion != nil {
+		sc.AllowVolumeExpansion = t.AllowVolumeExpansion
+	}
+	if t.MountOptions != nil {
+		sc.MountOptions = t.MountOptions
+	}
+	if _, err := classes.Create(ctx, sc, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("can't create storage class %s: %w", t.Name, err)
+	}
+	tc.storageClasses = append(tc.storageClasses, t.Name)
+	logrus.Infof("Created temporary storage class %s from %s", t.Name, t.From)
+	return nil
+}
+
+func (tc *TempClasses) createSnapshotClass(ctx context.Context, t SnapshotClassTemplate) error {
+	switch tc.snapshotAPI {
+	case SnapshotAPIV1:
+		classes := tc.snapshots.SnapshotV1().VolumeSnapshotClasses()
+		source, err := classes.Get(ctx, t.From, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("can't get snapshot class %s to copy: %w", t.From, err)
+		}
+		var existingMeta *metav1.ObjectMeta
+		if existing, err := classes.Get(ctx, t.Name, metav1.GetOptions{}); err == nil {
+			existingMeta = &existing.ObjectMeta
+		} else if !apierrs.IsNotFound(err) {
+			return err
+		}
+		meta, err := tempClassMeta(t.Name, existingMeta)
+		if err != nil {
+			return err
+		}
+		if existingMeta != nil {
+			logrus.Warnf("Replacing snapshot class %s left by interrupted run", t.Name)
+			if err := classes.Delete(ctx, t.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		vsc := source.DeepCopy()
+		vsc.ObjectMeta = meta
+		vsc.Parameters = overrideParameters(source.Parameters, t.Parameters)
+		if t.DeletionPolicy != "" {
+			vsc.DeletionPolicy = snapv1.DeletionPolicy(t.DeletionPolicy)
+		}
+		if _, err := classes.Create(ctx, vsc, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("can't create snapshot class %s: %w", t.Name, err)
+		}
+	case SnapshotAPIV1beta1:
+		classes := tc.snapshots.SnapshotV1beta1().VolumeSnapshotClasses()
+		source, err := classes.Get(ctx, t.From, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("can't get snapshot class %s to copy: %w", t.From, err)
+		}
+		var existingMeta *metav1.ObjectMeta
+		if existing, err := classes.Get(ctx, t.Name, metav1.GetOptions{}); err == nil {
+			existingMeta = &existing.ObjectMeta
+		} else if !apierrs.IsNotFound(err) {
+			return err
+		}
+		meta, err := tempClassMeta(t.Name, existingMeta)
+		if err != nil {
+			return err
+		}
+		if existingMeta != nil {
+			logrus.Warnf("Replacing snapshot class %s left by interrupted run", t.Name)
+			if err := classes.Delete(ctx, t.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		vsc := source.DeepCopy()
+		vsc.ObjectMeta = meta
+		vsc.Parameters = overrideParameters(source.Parameters, t.Parameters)
+		if t.DeletionPolicy != "" {
+			vsc.DeletionPolicy = snapbeta.DeletionPolicy(t.DeletionPolicy)
+		}
+		if _, err := classes.Create(ctx, vsc, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("can't create snapshot class %s: %w", t.Name, err)
+		}
+	default:
+		return ErrNoSnapshotAPI
+	}
+	tc.snapshotClasses = append(tc.snapshotClasses, t.Name)
+	logrus.Infof("Created temporary snapshot class %s from %s", t.Name, t.From)
+	return nil
+}
+
+// Delete deletes every class created from templates, classes already gone are ignored
+func (tc *TempClasses) Delete(ctx context.Context) error {
+	var errs []error
+	for _, name := range tc.storageClasses {
+		err := tc.clientSet.StorageV1().StorageClasses().Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !apierrs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("can't delete storage class %s: %w", name, err))
+		}
+	}
+	for _, name := range tc.snapshotClasses {
+		var err error
+		if tc.snapshotAPI == SnapshotAPIV1 {
+			err = tc.snapshots.SnapshotV1().VolumeSnapshotClasses().Delete(ctx, name, metav1.DeleteOptions{})
+		} else {
+			err = tc.snapshots.SnapshotV1beta1().VolumeSnapshotClasses().Delete(ctx, name, metav1.DeleteOptions{})
+		}
+		if err != nil && !apierrs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("can't delete snapshot class %s: %w", name, err))
+		}
+	}
+	tc.storageClasses = nil
+	tc.snapshotClasses = nil
+	return errors.Join(errs...)
+}
+
+// DeleteLeftoverClasses deletes storage and snapshot classes left in cluster by runs which didn't get to delete them
+// and returns how many were deleted
+func (c *KubeClient) DeleteLeftoverClasses(ctx context.Context) (int, error) {
+	tc, err := c.NewTempClasses()
+	if err != nil {
+		return 0, err
+	}
+	opts := metav1.ListOptions{LabelSelector: TempClassLabel + "=true"}
+	storageClasses, err := c.ClientSet.StorageV1().StorageClasses().List(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	for _, class := range storageClasses.Items {
+		tc.storageClasses = append(tc.storageClasses, class.Name)
+	}
+	switch tc.snapshotAPI {
+	case SnapshotAPIV1:
+		list, err := tc.snapshots.SnapshotV1().VolumeSnapshotClasses().List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, class := range list.Items {
+			tc.snapshotClasses = append(tc.snapshotClasses, class.Name)
+		}
+	case SnapshotAPIV1beta1:
+		list, err := tc.snapshots.SnapshotV1beta1().VolumeSnapshotClasses().List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, class := range list.Items {
+			tc.snapshotClasses = append(tc.snapshotClasses, class.Name)
+		}
+	}
+	leftovers := len(tc.storageClasses) + len(tc.snapshotClasses)
+	return leftovers, tc.Delete(ctx)
+}
@@ -19,13 +19,53 @@ package k8sclient
 import (
 	"crypto/rand"
 	"fmt"
+	mathrand "math/rand"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 )
 
-// RandomSuffix returns a random suffix to use when naming resources
+var (
+	seededRand *mathrand.Rand
+	randMutex  sync.Mutex
+)
+
+// SetSeed makes RandomSuffix and RandomIntn return a reproducible sequence for provided seed,
+// names given out before are forgotten, so the sequence isn't altered by collisions with them
+func SetSeed(seed int64) {
+	namesMutex.Lock()
+	issuedNames = make(map[string]struct{})
+	namesMutex.Unlock()
+	randMutex.Lock()
+	defer randMutex.Unlock()
+	seededRand = mathrand.New(mathrand.NewSource(seed)) // #nosec G404
+}
+
+// RandomSuffix returns a suffix of the active name generator to use when naming resources,
+// GenerateName should be preferred as generators like the incremental one number names per prefix
 func RandomSuffix() string {
-	b := make([]byte, 4)
+	namesMutex.Lock()
+	defer namesMutex.Unlock()
+	return nextSuffix("")
+}
+
+// UniqueSuffix returns a random suffix which doesn't depend on the seed,
+// should be used for names that must not repeat when a run is replayed (test runs, events)
+func UniqueSuffix() string {
+	return cryptoSuffix(make([]byte, 4))
+}
+
+// RandomIntn returns a random number in [0, n), reproducible if seed was set
+func RandomIntn(n int) int {
+	randMutex.Lock()
+	defer randMutex.Unlock()
+	if seededRand != nil {
+		return seededRand.Intn(n)
+	}
+	return mathrand.Intn(n) // #nosec G404
+}
+
+func cryptoSuffix(b []byte) string {
 	_, err := rand.Read(b)
 	if err != nil {
 		log.Errorf("Can't generate UID; error = %v", err)
@@ -0,0 +1,284 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultImage is the image of load pods, they only have to be scheduled and started
+	DefaultImage = "registry.k8s.io/pause:3.9"
+	// DefaultMaxPods is how many load pods are kept alive if profile doesn't say
+	DefaultMaxPods = 10
+	// configMapsRetained is how many load configmaps exist at once, older ones are deleted
+	configMapsRetained = 10
+)
+
+// Profile describes background load of control plane, objects are created at the rates and the oldest ones deleted
+type Profile struct {
+	Name                string  `json:"name"`
+	ConfigMapsPerSecond float64 `json:"configMapsPerSecond"`
+	PodsPerSecond       float64 `json:"podsPerSecond"`
+	// MaxPods is how many load pods exist at once, older ones are deleted as new ones are created
+	MaxPods int    `json:"maxPods"`
+	Image   string `json:"image,omitempty"`
+}
+
+// Profiles are predefined load profiles, moderate one resembles a shared cluster with a few busy controllers
+var Profiles = map[string]Profile{
+	"light":    {Name: "light", ConfigMapsPerSecond: 2, PodsPerSecond: 0.2, MaxPods: 5},
+	"moderate": {Name: "moderate", ConfigMapsPerSecond: 10, PodsPerSecond: 1, MaxPods: 20},
+	"heavy":    {Name: "heavy", ConfigMapsPerSecond: 50, PodsPerSecond: 5, MaxPods: 100},
+}
+
+// ParseProfile returns predefined profile by name, or custom one in configmaps=10,pods=1,max-pods=20,image=<image>
+// form, where rates are objects per second
+func ParseProfile(spec string) (*Profile, error) {
+	if p, ok := Profiles[spec]; ok {
+		return &p, nil
+	}
+	p := &Profile{Name: "custom"}
+	for _, kv := range strings.Split(spec, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(kv), "=")
+		if !found {
+			return nil, fmt.Errorf("unknown load profile %s, use one of %s or configmaps=<rate>,pods=<rate>,max-pods=<n>", spec, strings.Join(profileNames(), ", "))
+		}
+		var err error
+		switch key {
+		case "configmaps":
+			p.ConfigMapsPerSecond, err = strconv.ParseFloat(value, 64)
+		case "pods":
+			p.PodsPerSecond, err = strconv.ParseFloat(value, 64)
+		case "max-pods":
+			p.MaxPods, err = strconv.Atoi(value)
+		case "image":
+			p.Image = value
+		default:
+			return nil, fmt.Errorf("unknown load profile key %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of load profile: %v", key, err)
+		}
+	}
+	if p.ConfigMapsPerSecond < 0 || p.PodsPerSecond < 0 || p.MaxPods < 0 {
+		return nil, errors.New("load profile rates and max-pods can't be negative")
+	}
+	if p.ConfigMapsPerSecond == 0 && p.PodsPerSecond == 0 {
+		return nil, errors.New("load profile must create configmaps or pods")
+	}
+	if p.PodsPerSecond > 0 && p.MaxPods == 0 {
+		p.MaxPods = DefaultMaxPods
+	}
+	return p, nil
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Counter counts load objects of a kind
+type Counter struct {
+	Created int `json:"created"`
+	Deleted int `json:"deleted"`
+}
+
+// Report is a load profile with load actually generated, it's recorded in run metadata
+type Report struct {
+	Profile    Profile       `json:"profile"`
+	Namespace  string        `json:"namespace"`
+	Duration   time.Duration `json:"duration"`
+	ConfigMaps Counter       `json:"configMaps"`
+	Pods       Counter       `json:"pods"`
+	// Errors is number of requests API server failed, many of them mean load was lower than profile
+	Errors int `json:"errors"`
+}
+
+// String returns short summary of the report, ex. moderate: 1200 configmaps, 120 pods in 2m0s
+func (r Report) String() string {
+	s := fmt.Sprintf("%s: %d configmaps, %d pods in %s", r.Profile.Name, r.ConfigMaps.Created, r.Pods.Created, r.Duration.Round(time.Second))
+	if r.Errors != 0 {
+		s += fmt.Sprintf(", %d errors", r.Errors)
+	}
+	return s
+}
+
+// Generator keeps API server and scheduler busy with configmaps and pods without volumes in its own namespace
+type Generator struct {
+	Profile Profile
+
+	client  *k8sclient.KubeClient
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mutex   sync.Mutex
+	report  Report
+	started time.Time
+}
+
+// NewGenerator is a Generator constructor
+func NewGenerator(client *k8sclient.KubeClient, profile Profile) *Generator {
+	if profile.Image == "" {
+		profile.Image = DefaultImage
+	}
+	return &Generator{Profile: profile, client: client, report: Report{Profile: profile}}
+}
+
+// Start creates namespace of the load and generates load in background until stopped
+func (g *Generator) Start(ctx context.Context) error {
+	ns, err := g.client.CreateNamespaceWithSuffix(ctx, "cert-csi-load")
+	if err != nil {
+		return fmt.Errorf("can't create namespace of background load: %w", err)
+	}
+	g.mutex.Lock()
+	g.report.Namespace = ns.Name
+	g.mutex.Unlock()
+	g.started = time.Now()
+	ctx, g.cancel = context.WithCancel(ctx)
+
+	if g.Profile.ConfigMapsPerSecond > 0 {
+		g.wg.Add(1)
+		go g.churn(ctx, "configmap", g.Profile.ConfigMapsPerSecond, configMapsRetained, g.createConfigMap, g.deleteConfigMap, &g.report.ConfigMaps)
+	}
+	if g.Profile.PodsPerSecond > 0 {
+		g.wg.Add(1)
+		go g.churn(ctx, "pod", g.Profile.PodsPerSecond, g.Profile.MaxPods, g.createPod, g.deletePod, &g.report.Pods)
+	}
+	return nil
+}
+
+// Stop stops generating load, deletes its namespace and returns report of generated load
+func (g *Generator) Stop() Report {
+	if g.cancel == nil {
+		return g.report
+	}
+	g.cancel()
+	g.wg.Wait()
+	g.cancel = nil
+	g.report.Duration = time.Since(g.started)
+	if err := g.client.DeleteNamespace(context.Background(), g.report.Namespace); err != nil {
+		log.Errorf("Can't delete namespace %s of background load; error=%v", g.report.Namespace, err)
+	}
+	return g.report
+}
+
+// Created returns number of objects generator created so far, its namespace included
+func (g *Generator) Created() int64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	created := int64(g.report.ConfigMaps.Created + g.report.Pods.Created)
+	if g.report.Namespace != "" {
+		created++
+	}
+	return created
+}
+
+// churn creates objects at rate and deletes the oldest ones once more than retained exist
+func (g *Generator) churn(ctx context.Context, kind string, rate float64, retained int,
+	create, remove func(ctx context.Context, name string) error, counter *Counter,
+) {
+	defer g.wg.Done()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer ticker.Stop()
+
+	var alive []string
+	for n := 0; ; n++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		name := fmt.Sprintf("load-%s-%d", kind, n)
+		err := create(ctx, name)
+		g.count(err, func() { counter.Created++ })
+		if err == nil {
+			alive = append(alive, name)
+		}
+		for len(alive) > retained {
+			err := remove(ctx, alive[0])
+			g.count(err, func() { counter.Deleted++ })
+			alive = alive[1:]
+		}
+	}
+}
+
+// count updates report with result of a request, requests interrupted by stop aren't errors
+func (g *Generator) count(err error, ok func()) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	switch {
+	case err == nil:
+		ok()
+	case !errors.Is(err, context.Canceled):
+		log.Debugf("Background load request failed; error=%v", err)
+		g.report.Errors++
+	}
+}
+
+func (g *Generator) createConfigMap(ctx context.Context, name string) error {
+	_, err := g.client.ClientSet.CoreV1().ConfigMaps(g.report.Namespace).Create(ctx, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Data:       map[string]string{"payload": strings.Repeat("x", 1024)},
+	}, metav1.CreateOptions{})
+	return err
+}
+
+func (g *Generator) deleteConfigMap(ctx context.Context, name string) error {
+	return g.client.ClientSet.CoreV1().ConfigMaps(g.report.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// createPod creates pod with tiny requests, so scheduler places it without taking capacity from suites
+func (g *Generator) createPod(ctx context.Context, name string) error {
+	grace := int64(0)
+	_, err := g.client.ClientSet.CoreV1().Pods(g.report.Namespace).Create(ctx, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PodSpec{
+			TerminationGracePeriodSeconds: &grace,
+			Containers: []v1.Container{{
+				Name:  "load",
+				Image: g.Profile.Image,
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("1m"),
+					v1.ResourceMemory: resource.MustParse("4Mi"),
+				}},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	return err
+}
+
+func (g *Generator) deletePod(ctx context.Context, name string) error {
+	grace := int64(0)
+	return g.client.ClientSet.CoreV1().Pods(g.report.Namespace).Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &grace})
+}
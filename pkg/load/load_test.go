@@ -0,0 +1,70 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package load
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseProfile(t *testing.T) {
+	p, err := ParseProfile("moderate")
+	assert.NoError(t, err)
+	assert.Equal(t, Profiles["moderate"], *p)
+
+	p, err = ParseProfile("configmaps=5, pods=0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{Name: "custom", ConfigMapsPerSecond: 5, PodsPerSecond: 0.5, MaxPods: DefaultMaxPods}, *p)
+
+	p, err = ParseProfile("configmaps=1,max-pods=3,image=busybox")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, p.MaxPods)
+	assert.Equal(t, "busybox", p.Image)
+
+	for _, spec := range []string{"extreme", "pods=x", "secrets=1", "pods=-1", "max-pods=5"} {
+		_, err = ParseProfile(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+	client := &k8sclient.KubeClient{ClientSet: clientSet, Config: &rest.Config{}}
+	gen := NewGenerator(client, Profile{Name: "test", ConfigMapsPerSecond: 200, PodsPerSecond: 100, MaxPods: 2})
+	assert.Equal(t, DefaultImage, gen.Profile.Image)
+	assert.NoError(t, gen.Start(context.Background()))
+	time.Sleep(200 * time.Millisecond)
+
+	pods, err := clientSet.CoreV1().Pods(gen.report.Namespace).List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(pods.Items), 2)
+
+	report := gen.Stop()
+	assert.Contains(t, report.Namespace, "cert-csi-load-")
+	assert.NotZero(t, report.ConfigMaps.Created)
+	assert.NotZero(t, report.Pods.Created)
+	assert.NotZero(t, report.Pods.Deleted)
+	assert.Zero(t, report.Errors)
+	assert.Equal(t, report, gen.Stop())
+}
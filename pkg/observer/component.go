@@ -0,0 +1,151 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package observer
+
+import (
+	"context"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ComponentEventObserver records Kubernetes events of PVCs and pods, so latency can be attributed to components that emitted them
+type ComponentEventObserver struct {
+	stream
+	finished chan bool
+}
+
+// componentEvent is Kubernetes event waiting for its involved object to be saved by other observers
+type componentEvent struct {
+	uid       types.UID
+	reason    string
+	source    string
+	timestamp time.Time
+}
+
+// EventSource returns the component that emitted the event, newer events set reporting controller only
+func EventSource(e *v1.Event) string {
+	if e.ReportingController != "" {
+		return e.ReportingController
+	}
+	return e.Source.Component
+}
+
+// StartWatching starts watching events of the namespace
+func (ceo *ComponentEventObserver) StartWatching(ctx context.Context, runner *Runner) {
+	defer runner.WaitGroup.Done()
+
+	log.Debugf("%s started watching", ceo.GetName())
+	var client WatchFunc
+	if runner.Clients.PVCClient != nil {
+		client = runner.Clients.PVCClient.ClientSet.CoreV1().Events(runner.Clients.PVCClient.Namespace).Watch
+	}
+	w, watchErr := ceo.open(ctx, client)
+	if watchErr != nil {
+		log.Errorf("Can't watch events; error = %v", watchErr)
+		return
+	}
+	defer w.Stop()
+
+	var received []componentEvent
+	for {
+		select {
+		case <-ctx.Done():
+			ceo.save(runner, received)
+			log.Debugf("%s interrupted", ceo.GetName())
+			return
+		case <-ceo.finished:
+			ceo.save(runner, received)
+			log.Debugf("%s finished watching", ceo.GetName())
+			return
+		case data := <-w.ResultChan():
+			// Repeated events only bump count, first occurrence is the decisive one
+			if data.Type != watch.Added {
+				break
+			}
+			e, ok := data.Object.(*v1.Event)
+			if !ok {
+				break
+			}
+			if e.InvolvedObject.Kind != "PersistentVolumeClaim" && e.InvolvedObject.Kind != "Pod" {
+				break
+			}
+			received = append(received, componentEvent{
+				uid:       e.InvolvedObject.UID,
+				reason:    e.Reason,
+				source:    EventSource(e),
+				timestamp: time.Now(),
+			})
+		}
+	}
+}
+
+// save links events to entities by UID, entities are saved by PVC and pod observers, events of unknown objects are dropped
+func (ceo *ComponentEventObserver) save(runner *Runner, received []componentEvent) {
+	if len(received) == 0 {
+		return
+	}
+	entities, err := runner.Database.GetEntities(store.Conditions{"tc_id": runner.TestCase.ID}, "", 0)
+	if err != nil {
+		log.Errorf("Can't get entities; error=%v", err)
+		return
+	}
+	ids := make(map[types.UID]int64)
+	for _, e := range entities {
+		ids[types.UID(e.K8sUID)] = e.ID
+	}
+
+	var events []*store.Event
+	for _, r := range received {
+		id, ok := ids[r.uid]
+		if !ok {
+			continue
+		}
+		events = append(events, &store.Event{
+			Name:      "event-component-" + k8sclient.UniqueSuffix(),
+			TcID:      runner.TestCase.ID,
+			EntityID:  id,
+			Type:      store.ComponentEvent,
+			Timestamp: r.timestamp,
+			Message:   r.reason,
+			Source:    r.source,
+		})
+	}
+	if err := runner.Database.SaveEvents(events); err != nil {
+		log.Errorf("Error saving events; error=%v", err)
+	}
+}
+
+// StopWatching stops watching events
+func (ceo *ComponentEventObserver) StopWatching() {
+	ceo.finished <- true
+}
+
+// GetName returns name of component event observer
+func (ceo *ComponentEventObserver) GetName() string {
+	return "ComponentEventObserver"
+}
+
+// MakeChannel creates a new channel
+func (ceo *ComponentEventObserver) MakeChannel() {
+	ceo.finished = make(chan bool, 1)
+}
@@ -62,7 +62,7 @@ func (eno *EntityNumberObserver) Interrupted() bool {
 }
 
 // StartWatching watches all entities - pods and pvcs
-func (eno *EntityNumberObserver) StartWatching(_ context.Context, runner *Runner) {
+func (eno *EntityNumberObserver) StartWatching(ctx context.Context, runner *Runner) {
 	defer runner.WaitGroup.Done()
 
 	var nEntities []*store.NumberEntities
@@ -77,7 +77,7 @@ func (eno *EntityNumberObserver) StartWatching(_ context.Context, runner *Runner
 		}
 	}
 
-	pollErr := wait.PollImmediate(EntityNumberPoll, timeout, func() (bool, error) {
+	pollErr := wait.PollUntilContextTimeout(ctx, EntityNumberPoll, timeout, true, func(context.Context) (bool, error) {
 		select {
 		case <-eno.finished:
 			log.Debugf("%s finished watching", eno.GetName())
@@ -87,17 +87,18 @@ func (eno *EntityNumberObserver) StartWatching(_ context.Context, runner *Runner
 		}
 
 		info := &store.NumberEntities{TcID: runner.TestCase.ID}
-		b, e := eno.checkPods(podClient, info)
+		b, e := eno.checkPods(ctx, podClient, info)
 		if e != nil {
 			return b, e
 		}
 
-		i, e := eno.checkPvcs(pvcClient, info)
+		i, e := eno.checkPvcs(ctx, pvcClient, info)
 		if e != nil {
 			return i, e
 		}
 		info.Timestamp = time.Now()
 		nEntities = append(nEntities, info)
+		runner.Progress.UpdateEntities(info)
 
 		return false, nil
 	})
@@ -114,6 +115,7 @@ func (eno *EntityNumberObserver) StartWatching(_ context.Context, runner *Runner
 }
 
 func (eno *EntityNumberObserver) checkPvcs(
+	ctx context.Context,
 	pvcClient *pvc.Client,
 	info *store.NumberEntities,
 ) (bool, error) {
@@ -121,7 +123,7 @@ func (eno *EntityNumberObserver) checkPvcs(
 		return false, nil
 	}
 
-	pvcList, pvcListErr := pvcClient.Interface.List(context.Background(), metav1.ListOptions{})
+	pvcList, pvcListErr := pvcClient.Interface.List(ctx, metav1.ListOptions{})
 	if pvcListErr != nil {
 		return false, pvcListErr
 	}
@@ -143,13 +145,14 @@ func (eno *EntityNumberObserver) checkPvcs(
 }
 
 func (eno *EntityNumberObserver) checkPods(
+	ctx context.Context,
 	podClient *pod.Client,
 	info *store.NumberEntities,
 ) (bool, error) {
 	if podClient == nil {
 		return false, nil
 	}
-	podList, podListErr := podClient.Interface.List(context.Background(), metav1.ListOptions{})
+	podList, podListErr := podClient.Interface.List(ctx, metav1.ListOptions{})
 	if podListErr != nil {
 		return false, podListErr
 	}
@@ -184,5 +187,5 @@ func (eno *EntityNumberObserver) GetName() string {
 
 // MakeChannel makes a new channel
 func (eno *EntityNumberObserver) MakeChannel() {
-	eno.finished = make(chan bool)
+	eno.finished = make(chan bool, 1)
 }
@@ -0,0 +1,112 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ephemeralTracker records NodePublish/NodeUnpublish events of pods that use CSI inline volumes.
+// Publishing starts once pod is scheduled and ends when first container is started,
+// unpublishing starts with pod deletion and ends when pod is gone.
+type ephemeralTracker struct {
+	tracked        map[string]bool
+	publishStarted map[string]bool
+	published      map[string]bool
+	unpublishing   map[string]bool
+}
+
+func newEphemeralTracker() *ephemeralTracker {
+	return &ephemeralTracker{
+		tracked:        make(map[string]bool),
+		publishStarted: make(map[string]bool),
+		published:      make(map[string]bool),
+		unpublishing:   make(map[string]bool),
+	}
+}
+
+// observe returns events caused by the current state of the pod
+func (et *ephemeralTracker) observe(pod *v1.Pod, entityID, tcID int64) []*store.Event {
+	if !et.tracked[pod.Name] {
+		if !hasCSIInlineVolume(pod) {
+			return nil
+		}
+		et.tracked[pod.Name] = true
+	}
+
+	var events []*store.Event
+	if pod.Spec.NodeName != "" && !et.publishStarted[pod.Name] {
+		et.publishStarted[pod.Name] = true
+		events = append(events, newEphemeralEvent(store.EphemeralPublishStarted, entityID, tcID))
+	}
+	if et.publishStarted[pod.Name] && !et.published[pod.Name] && hasStartedContainer(pod) {
+		et.published[pod.Name] = true
+		events = append(events, newEphemeralEvent(store.EphemeralPublishEnded, entityID, tcID))
+	}
+	if pod.DeletionTimestamp != nil && !et.unpublishing[pod.Name] {
+		et.unpublishing[pod.Name] = true
+		events = append(events, newEphemeralEvent(store.EphemeralUnpublishStarted, entityID, tcID))
+	}
+	return events
+}
+
+// deleted returns events caused by deletion of the pod
+func (et *ephemeralTracker) deleted(name string, entityID, tcID int64) []*store.Event {
+	if !et.tracked[name] {
+		return nil
+	}
+	delete(et.tracked, name)
+
+	var events []*store.Event
+	if !et.unpublishing[name] {
+		events = append(events, newEphemeralEvent(store.EphemeralUnpublishStarted, entityID, tcID))
+	}
+	return append(events, newEphemeralEvent(store.EphemeralUnpublishEnded, entityID, tcID))
+}
+
+func newEphemeralEvent(eventType store.EventTypeEnum, entityID, tcID int64) *store.Event {
+	return &store.Event{
+		Name:      "event-ephemeral-" + k8sclient.UniqueSuffix(),
+		TcID:      tcID,
+		EntityID:  entityID,
+		Type:      eventType,
+		Timestamp: time.Now(),
+	}
+}
+
+func hasCSIInlineVolume(pod *v1.Pod) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.CSI != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func hasStartedContainer(pod *v1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running != nil || status.State.Terminated != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Harness runs observers against fake streams and in-memory database, so observers, including custom ones,
+// can be unit tested without cluster
+type Harness struct {
+	Runner  *Runner
+	DB      store.Store
+	streams map[Interface]*FakeStream
+}
+
+// NewHarness creates test run and test case in in-memory database named name, and replaces stream of every
+// streamed observer with FakeStream. Runner has no clients, they can be set before Start
+func NewHarness(name string, observers ...Interface) (*Harness, error) {
+	db := store.NewSQLiteStore(fmt.Sprintf("file:%s.db?cache=shared&mode=memory", name))
+	tr := &store.TestRun{Name: name, StartTimestamp: time.Now(), StorageClass: "fake", ClusterAddress: "localhost"}
+	if err := db.SaveTestRun(tr); err != nil {
+		return nil, err
+	}
+	tc := &store.TestCase{Name: name, StartTimestamp: time.Now(), RunID: tr.ID}
+	if err := db.SaveTestCase(tc); err != nil {
+		return nil, err
+	}
+
+	h := &Harness{
+		Runner:  NewObserverRunner(observers, &k8sclient.Clients{}, db, tc, "", false),
+		DB:      db,
+		streams: make(map[Interface]*FakeStream),
+	}
+	for _, obs := range observers {
+		if streamed, ok := obs.(Streamed); ok {
+			fs := NewFakeStream()
+			streamed.SetWatch(fs.Watch)
+			h.streams[obs] = fs
+		}
+	}
+	return h, nil
+}
+
+// Stream returns fake stream of observer, nil if observer isn't streamed
+func (h *Harness) Stream(obs Interface) *FakeStream {
+	return h.streams[obs]
+}
+
+// Start starts observers
+func (h *Harness) Start(ctx context.Context) error {
+	return h.Runner.Start(ctx)
+}
+
+// Stop stops observers and waits for them to save what they observed
+func (h *Harness) Stop(timeout time.Duration) error {
+	for _, obs := range h.Runner.Observers {
+		obs.StopWatching()
+	}
+	if h.Runner.waitTimeout(timeout) {
+		return fmt.Errorf("observers didn't stop in %s", timeout)
+	}
+	return nil
+}
+
+// Events returns events observers saved, in order they were saved
+func (h *Harness) Events() ([]store.Event, error) {
+	return h.DB.GetEvents(store.Conditions{"tc_id": h.Runner.TestCase.ID}, "id", 0)
+}
+
+// Close closes database of the harness
+func (h *Harness) Close() error {
+	return h.DB.Close()
+}
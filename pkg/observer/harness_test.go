@@ -0,0 +1,169 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func eventTypes(t *testing.T, h *Harness) []store.EventTypeEnum {
+	events, err := h.Events()
+	assert.NoError(t, err)
+	var types []store.EventTypeEnum
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestPvcObserver(t *testing.T) {
+	pvcObs, vaObs, ceo := &PvcObserver{}, &VaObserver{}, &ComponentEventObserver{}
+	h, err := NewHarness("pvc_observer", pvcObs, vaObs, ceo)
+	assert.NoError(t, err)
+	defer h.Close()
+	assert.NoError(t, h.Start(context.Background()))
+
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc1", UID: "pvc1-uid"}}
+	h.Stream(pvcObs).Add(pvc.DeepCopy())
+	h.Stream(ceo).Add(&v1.Event{
+		InvolvedObject:      v1.ObjectReference{Kind: "PersistentVolumeClaim", UID: pvc.UID},
+		Reason:              "Provisioning",
+		ReportingController: "csi-provisioner",
+	})
+
+	bound := pvc.DeepCopy()
+	bound.Spec.VolumeName = "pv1"
+	bound.Status.Phase = v1.ClaimBound
+	h.Stream(pvcObs).Modify(bound)
+
+	pvName := "pv1"
+	va := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "va1"},
+		Spec:       storagev1.VolumeAttachmentSpec{Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName}},
+	}
+	h.Stream(vaObs).Add(va.DeepCopy())
+	va.Status.Attached = true
+	h.Stream(vaObs).Modify(va)
+
+	deleting := bound.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	h.Stream(pvcObs).Modify(deleting)
+	h.Stream(pvcObs).Delete(deleting)
+
+	assert.NoError(t, h.Stop(time.Second))
+	assert.ElementsMatch(t, []store.EventTypeEnum{
+		store.PvcAdded, store.PvcBound, store.PvcDeletingStarted, store.PvcDeletingEnded,
+		store.PvcAttachStarted, store.PvcAttachEnded, store.ComponentEvent,
+	}, eventTypes(t, h))
+}
+
+func TestOutOfOrderEvents(t *testing.T) {
+	pvcObs, podObs := &PvcObserver{}, &PodObserver{}
+	h, err := NewHarness("out_of_order", pvcObs, podObs)
+	assert.NoError(t, err)
+	defer h.Close()
+	assert.NoError(t, h.Start(context.Background()))
+
+	// Events of objects added before stream started are skipped instead of crashing observers
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc1", UID: "pvc1-uid"}}
+	h.Stream(pvcObs).Delete(pvc.DeepCopy())
+	h.Stream(pvcObs).Add(pvc.DeepCopy())
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", UID: "pod1-uid"}}
+	h.Stream(podObs).Modify(pod.DeepCopy())
+	h.Stream(podObs).Delete(pod.DeepCopy())
+
+	assert.NoError(t, h.Stop(time.Second))
+	assert.Equal(t, []store.EventTypeEnum{store.PvcAdded}, eventTypes(t, h))
+}
+
+func TestReplayAfterReconnect(t *testing.T) {
+	podObs, pvcObs := &PodObserver{}, &PvcObserver{}
+	h, err := NewHarness("replay_after_reconnect", podObs, pvcObs)
+	assert.NoError(t, err)
+	defer h.Close()
+	assert.NoError(t, h.Start(context.Background()))
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", UID: "pod1-uid"}}
+	h.Stream(podObs).Add(pod.DeepCopy())
+	ready := pod.DeepCopy()
+	ready.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	h.Stream(podObs).Modify(ready)
+
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc1", UID: "pvc1-uid"}}
+	h.Stream(pvcObs).Add(pvc.DeepCopy())
+
+	// Reopened streams start with Added of every existing object
+	h.Stream(podObs).Reconnect()
+	h.Stream(pvcObs).Reconnect()
+	h.Stream(podObs).Add(ready.DeepCopy())
+	bound := pvc.DeepCopy()
+	bound.Spec.VolumeName = "pv1"
+	bound.Status.Phase = v1.ClaimBound
+	h.Stream(pvcObs).Add(bound)
+	h.Stream(podObs).Delete(ready.DeepCopy())
+
+	assert.NoError(t, h.Stop(time.Second))
+	assert.ElementsMatch(t, []store.EventTypeEnum{
+		store.PodAdded, store.PodReady, store.PodDeleted, store.PvcAdded, store.PvcBound,
+	}, eventTypes(t, h))
+}
+
+func TestSchedulingObserver(t *testing.T) {
+	podObs, so := &PodObserver{}, &SchedulingObserver{}
+	h, err := NewHarness("scheduling_observer", podObs, so)
+	assert.NoError(t, err)
+	defer h.Close()
+	assert.NoError(t, h.Start(context.Background()))
+
+	h.Stream(podObs).Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-0", UID: "sts-0-uid"}})
+	event := func(reason, message string) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: reason},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "sts-0"},
+			Reason:         reason,
+			Message:        message,
+		}
+	}
+	h.Stream(so).Add(event("Scheduled", "Successfully assigned ns/sts-0 to node1"))
+	h.Stream(so).Add(event("Preempted", "Preempted by ns/critical on node node1"))
+	h.Stream(so).Add(event("Scheduled", "Successfully assigned ns/sts-0 to node2"))
+	// Scheduling of pod nothing displaced isn't churn
+	h.Stream(so).Add(event("Scheduled", "Successfully assigned ns/sts-0 to node3"))
+
+	assert.NoError(t, h.Stop(time.Second))
+	events, err := h.Events()
+	assert.NoError(t, err)
+	var churn []store.Event
+	for _, e := range events {
+		if e.Type == store.PodPreempted || e.Type == store.PodRescheduled {
+			churn = append(churn, e)
+		}
+	}
+	assert.Len(t, churn, 2)
+	assert.Equal(t, store.PodPreempted, churn[0].Type)
+	assert.Equal(t, store.PodRescheduled, churn[1].Type)
+	assert.Equal(t, "node1 -> node2", churn[1].Message)
+}
@@ -0,0 +1,266 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DriverHealthPoll is a poll interval for driver health probing
+var DriverHealthPoll = 5 * time.Second
+
+// DriverHealthObserver probes liveness endpoints of driver pods through API server proxy and stores windows
+// they didn't answer in as driver events, so latency spikes can be matched with driver restarts
+type DriverHealthObserver struct {
+	finished chan bool
+
+	interrupted bool
+	mutex       sync.Mutex
+}
+
+// HealthEndpoint is an HTTP liveness probe of driver container
+type HealthEndpoint struct {
+	Pod       string
+	Container string
+	Scheme    string
+	Port      string
+	Path      string
+}
+
+// Key returns name endpoint is stored under
+func (he HealthEndpoint) Key() string {
+	return he.Pod + "/" + he.Container
+}
+
+// driverEndpoint holds entity and availability state of a single health endpoint
+type driverEndpoint struct {
+	entity      *store.Entity
+	unavailable time.Time
+}
+
+// Interrupt interrupts a driver health observer
+func (dho *DriverHealthObserver) Interrupt() {
+	dho.mutex.Lock()
+	defer dho.mutex.Unlock()
+	dho.interrupted = true
+}
+
+// Interrupted checks whether driver health observer is interrupted
+func (dho *DriverHealthObserver) Interrupted() bool {
+	dho.mutex.Lock()
+	defer dho.mutex.Unlock()
+	return dho.interrupted
+}
+
+// StartWatching starts probing driver health endpoints
+func (dho *DriverHealthObserver) StartWatching(ctx context.Context, runner *Runner) {
+	defer runner.WaitGroup.Done()
+	if runner.DriverNamespace == "" {
+		log.Warnf("Driver namespace is not set, %s won't probe driver", dho.GetName())
+		dho.Interrupt()
+		return
+	}
+	client := runner.Clients.PodClient
+	if client == nil {
+		log.Errorf("Pod client can't be nil")
+		dho.Interrupt()
+		return
+	}
+	log.Debugf("%s started watching", dho.GetName())
+
+	endpoints := make(map[string]*driverEndpoint)
+	pollErr := wait.PollUntilContextTimeout(ctx, DriverHealthPoll, time.Duration(WatchTimeout)*time.Second, true, func(context.Context) (bool, error) {
+		select {
+		case <-dho.finished:
+			log.Debugf("%s finished watching", dho.GetName())
+			return true, nil
+		default:
+			break
+		}
+
+		podList, err := client.ClientSet.CoreV1().Pods(runner.DriverNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Debugf("Can't list driver pods; error=%v", err)
+			return false, nil
+		}
+		seen := make(map[string]bool)
+		var events []*store.Event
+		for _, he := range HealthEndpoints(podList.Items) {
+			seen[he.Key()] = true
+			ep := endpoints[he.Key()]
+			if ep == nil {
+				ep = dho.newEndpoint(runner, he)
+				endpoints[he.Key()] = ep
+			}
+			if ep.entity == nil {
+				continue
+			}
+			_, probeErr := client.ClientSet.CoreV1().Pods(runner.DriverNamespace).ProxyGet(he.Scheme, he.Pod, he.Port, he.Path, nil).DoRaw(ctx)
+			if e := ep.transition(probeErr, time.Now(), runner.TestCase.ID); e != nil {
+				events = append(events, e)
+			}
+		}
+		// Window of endpoint whose pod is gone is closed, replacement pod is probed as a new endpoint
+		for key, ep := range endpoints {
+			if seen[key] {
+				continue
+			}
+			if ep.entity != nil && !ep.unavailable.IsZero() {
+				events = append(events, ep.available(time.Now(), runner.TestCase.ID, "pod deleted"))
+			}
+			delete(endpoints, key)
+		}
+		if len(events) != 0 {
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+		}
+		return false, nil
+	})
+
+	if pollErr != nil {
+		log.Errorf("Error with polling; error=%v", pollErr)
+		dho.Interrupt()
+	}
+}
+
+// newEndpoint saves entity of health endpoint
+func (dho *DriverHealthObserver) newEndpoint(runner *Runner, he HealthEndpoint) *driverEndpoint {
+	ep := &driverEndpoint{entity: &store.Entity{
+		Name:   he.Key(),
+		K8sUID: he.Key() + "-" + k8sclient.UniqueSuffix(),
+		TcID:   runner.TestCase.ID,
+		Type:   store.Driver,
+	}}
+	if err := runner.Database.SaveEntities([]*store.Entity{ep.entity}); err != nil {
+		log.Errorf("Can't save entity; error=%v", err)
+		ep.entity = nil
+	}
+	return ep
+}
+
+// transition returns event if probe result changed availability of the endpoint
+func (ep *driverEndpoint) transition(probeErr error, now time.Time, tcID int64) *store.Event {
+	if probeErr != nil && ep.unavailable.IsZero() {
+		ep.unavailable = now
+		log.Warnf("Driver %s is unavailable: %v", ep.entity.Name, probeErr)
+		return &store.Event{
+			Name:      "event-driver-unavailable-" + k8sclient.UniqueSuffix(),
+			TcID:      tcID,
+			EntityID:  ep.entity.ID,
+			Type:      store.DriverUnavailable,
+			Timestamp: now,
+			Message:   probeErr.Error(),
+		}
+	}
+	if probeErr == nil && !ep.unavailable.IsZero() {
+		return ep.available(now, tcID, "")
+	}
+	return nil
+}
+
+// available closes unavailability window of the endpoint
+func (ep *driverEndpoint) available(now time.Time, tcID int64, reason string) *store.Event {
+	msg := fmt.Sprintf("unavailable for %s", now.Sub(ep.unavailable).Round(time.Second))
+	if reason != "" {
+		msg += ", " + reason
+	}
+	log.Infof("Driver %s is available again, %s", ep.entity.Name, msg)
+	ep.unavailable = time.Time{}
+	return &store.Event{
+		Name:      "event-driver-available-" + k8sclient.UniqueSuffix(),
+		TcID:      tcID,
+		EntityID:  ep.entity.ID,
+		Type:      store.DriverAvailable,
+		Timestamp: now,
+		Message:   msg,
+	}
+}
+
+// HealthEndpoints returns HTTP liveness probes of containers of scheduled pods, named ports are resolved
+// against ports of all containers of the pod, since livenessprobe sidecar serves port declared by driver container
+func HealthEndpoints(pods []v1.Pod) []HealthEndpoint {
+	var endpoints []HealthEndpoint
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			if c.LivenessProbe == nil || c.LivenessProbe.HTTPGet == nil {
+				continue
+			}
+			get := c.LivenessProbe.HTTPGet
+			scheme := strings.ToLower(string(get.Scheme))
+			if scheme == "" {
+				scheme = "http"
+			}
+			path := get.Path
+			if path == "" {
+				path = "/"
+			}
+			endpoints = append(endpoints, HealthEndpoint{
+				Pod:       pod.Name,
+				Container: c.Name,
+				Scheme:    scheme,
+				Port:      resolvePort(pod.Spec.Containers, get.Port.String()),
+				Path:      path,
+			})
+		}
+	}
+	return endpoints
+}
+
+// resolvePort returns number of named container port, or port itself if it's not a name of any
+func resolvePort(containers []v1.Container, port string) string {
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			if p.Name == port {
+				return fmt.Sprint(p.ContainerPort)
+			}
+		}
+	}
+	return port
+}
+
+// StopWatching stops probing driver health
+func (dho *DriverHealthObserver) StopWatching() {
+	if !dho.Interrupted() {
+		dho.finished <- true
+	}
+}
+
+// GetName returns name of driver health observer
+func (dho *DriverHealthObserver) GetName() string {
+	return "DriverHealthObserver"
+}
+
+// MakeChannel makes a new channel
+func (dho *DriverHealthObserver) MakeChannel() {
+	dho.finished = make(chan bool, 1)
+}
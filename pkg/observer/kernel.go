@@ -0,0 +1,270 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	kubepod "github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// KernelLogPoll is a poll interval for kernel log scraping
+	KernelLogPoll = 5 * time.Second
+
+	dmesgTimeLayout = "2006-01-02T15:04:05,000000-07:00"
+)
+
+// KernelIOErrorPatterns are kernel log lines treated as block device IO errors
+var KernelIOErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bI/O error\b`),
+	regexp.MustCompile(`blk_update_request`),
+	regexp.MustCompile(`(?i)critical (medium|target) error`),
+	regexp.MustCompile(`(?i)sense key`),
+	regexp.MustCompile(`(?i)nvme.*(timeout|reset|abort)`),
+	regexp.MustCompile(`(?i)multipath.*failing path`),
+	regexp.MustCompile(`(?i)remaining active paths: 0`),
+	regexp.MustCompile(`(?i)rejecting I/O to offline device`),
+	regexp.MustCompile(`(?i)(iscsi|connection\d+:\d+).*(conn error|ping timeout)`),
+	regexp.MustCompile(`EXT4-fs error`),
+	regexp.MustCompile(`XFS.*metadata I/O error`),
+}
+
+// KernelLogObserver scrapes kernel log of nodes running block volume pods and stores IO errors as node events
+type KernelLogObserver struct {
+	Image string
+
+	finished chan bool
+
+	interrupted bool
+	mutex       sync.Mutex
+}
+
+// kernelLogNode holds diagnostic pod and scraping state of a single node
+type kernelLogNode struct {
+	diag     *kubepod.Pod
+	entity   *store.Entity
+	lastSeen time.Time
+}
+
+// Interrupt interrupts a kernel log observer
+func (klo *KernelLogObserver) Interrupt() {
+	klo.mutex.Lock()
+	defer klo.mutex.Unlock()
+	klo.interrupted = true
+}
+
+// Interrupted checks whether kernel log observer is interrupted
+func (klo *KernelLogObserver) Interrupted() bool {
+	klo.mutex.Lock()
+	defer klo.mutex.Unlock()
+	return klo.interrupted
+}
+
+// StartWatching starts scraping kernel log of nodes
+func (klo *KernelLogObserver) StartWatching(ctx context.Context, runner *Runner) {
+	defer runner.WaitGroup.Done()
+
+	client := runner.Clients.PodClient
+	if client == nil {
+		log.Errorf("Pod client can't be nil")
+		klo.Interrupt()
+		return
+	}
+	log.Debugf("%s started watching", klo.GetName())
+
+	started := time.Now()
+	nodes := make(map[string]*kernelLogNode)
+	defer func() {
+		for _, n := range nodes {
+			if n.diag != nil {
+				client.Delete(context.Background(), n.diag.Object)
+			}
+		}
+	}()
+
+	pollErr := wait.PollUntilContextTimeout(ctx, KernelLogPoll, time.Duration(WatchTimeout)*time.Second, true, func(context.Context) (bool, error) {
+		finished := false
+		select {
+		case <-klo.finished:
+			log.Debugf("%s finished watching", klo.GetName())
+			finished = true
+		default:
+			break
+		}
+
+		if !finished {
+			podList, err := client.Interface.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return false, err
+			}
+			for _, pod := range podList.Items {
+				if pod.Spec.NodeName == "" || nodes[pod.Spec.NodeName] != nil || !hasBlockVolume(pod.Spec.Containers) {
+					continue
+				}
+				nodes[pod.Spec.NodeName] = klo.startDiagnostic(ctx, runner, pod.Spec.NodeName, started)
+			}
+		}
+
+		for node, n := range nodes {
+			klo.scan(ctx, runner, node, n)
+		}
+		return finished, nil
+	})
+
+	if pollErr != nil {
+		log.Errorf("Error with polling; error=%v", pollErr)
+		klo.Interrupt()
+	}
+}
+
+// startDiagnostic creates diagnostic pod on the node and saves node entity
+func (klo *KernelLogObserver) startDiagnostic(ctx context.Context, runner *Runner, node string, since time.Time) *kernelLogNode {
+	client := runner.Clients.PodClient
+	n := &kernelLogNode{lastSeen: since}
+
+	diag := client.Create(ctx, client.MakeDiagnosticPod(node, klo.Image))
+	if diag.HasError() {
+		log.Warnf("Can't create diagnostic pod on node %s; error=%v", node, diag.GetError())
+		return n
+	}
+	n.diag = diag
+	if err := diag.WaitForRunning(ctx); err != nil {
+		log.Warnf("Diagnostic pod on node %s is not running; error=%v", node, err)
+		return n
+	}
+
+	n.entity = &store.Entity{
+		Name:   node,
+		K8sUID: node + "-" + k8sclient.UniqueSuffix(),
+		TcID:   runner.TestCase.ID,
+		Type:   store.Node,
+	}
+	if err := runner.Database.SaveEntities([]*store.Entity{n.entity}); err != nil {
+		log.Errorf("Can't save entity; error=%v", err)
+		n.entity = nil
+	}
+	return n
+}
+
+// scan reads kernel log of the node and saves IO errors logged since the previous scan
+func (klo *KernelLogObserver) scan(ctx context.Context, runner *Runner, node string, n *kernelLogNode) {
+	if n.entity == nil {
+		return
+	}
+	var stdout, stderr bytes.Buffer
+	err := runner.Clients.PodClient.Exec(ctx, n.diag.Object, []string{"dmesg", "--time-format", "iso"}, &stdout, &stderr, true)
+	if err != nil {
+		log.Debugf("Can't read kernel log of node %s; error=%v: %s", node, err, stderr.String())
+		return
+	}
+
+	var events []*store.Event
+	lines, last := KernelIOErrors(stdout.String(), n.lastSeen)
+	for _, line := range lines {
+		log.Warnf("Kernel IO error on node %s: %s", node, line.Message)
+		events = append(events, &store.Event{
+			Name:      "event-node-kernel-" + k8sclient.UniqueSuffix(),
+			TcID:      runner.TestCase.ID,
+			EntityID:  n.entity.ID,
+			Type:      store.NodeKernelIOError,
+			Timestamp: line.Timestamp,
+			Message:   line.Message,
+		})
+	}
+	n.lastSeen = last
+	if len(events) == 0 {
+		return
+	}
+	if err := runner.Database.SaveEvents(events); err != nil {
+		log.Errorf("Error saving events; error=%v", err)
+	}
+}
+
+// KernelLogLine is a timestamped kernel log message
+type KernelLogLine struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// KernelIOErrors returns IO error lines of dmesg iso formatted output logged after since, and the latest timestamp seen
+func KernelIOErrors(output string, since time.Time) ([]KernelLogLine, time.Time) {
+	var lines []KernelLogLine
+	last := since
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		stamp, msg, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			continue
+		}
+		ts, err := time.Parse(dmesgTimeLayout, stamp)
+		if err != nil || !ts.After(since) {
+			continue
+		}
+		if ts.After(last) {
+			last = ts
+		}
+		msg = strings.TrimSpace(msg)
+		for _, re := range KernelIOErrorPatterns {
+			if re.MatchString(msg) {
+				lines = append(lines, KernelLogLine{Timestamp: ts, Message: msg})
+				break
+			}
+		}
+	}
+	return lines, last
+}
+
+// hasBlockVolume checks whether any of containers consumes raw block volume
+func hasBlockVolume(containers []v1.Container) bool {
+	for _, c := range containers {
+		if len(c.VolumeDevices) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// StopWatching stops scraping kernel log
+func (klo *KernelLogObserver) StopWatching() {
+	if !klo.Interrupted() {
+		klo.finished <- true
+	}
+}
+
+// GetName returns name of kernel log observer
+func (klo *KernelLogObserver) GetName() string {
+	return "KernelLogObserver"
+}
+
+// MakeChannel makes a new channel
+func (klo *KernelLogObserver) MakeChannel() {
+	klo.finished = make(chan bool, 1)
+}
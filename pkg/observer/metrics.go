@@ -59,7 +59,7 @@ func (cmo *ContainerMetricsObserver) Interrupted() bool {
 }
 
 // StartWatching starts watching container metrics
-func (cmo *ContainerMetricsObserver) StartWatching(_ context.Context, runner *Runner) {
+func (cmo *ContainerMetricsObserver) StartWatching(ctx context.Context, runner *Runner) {
 	defer runner.WaitGroup.Done()
 	if runner.DriverNamespace == "" {
 		cmo.Interrupt()
@@ -77,7 +77,7 @@ func (cmo *ContainerMetricsObserver) StartWatching(_ context.Context, runner *Ru
 		}
 	}
 
-	pollErr := wait.PollImmediate(MetricsPoll, timeout, func() (bool, error) {
+	pollErr := wait.PollUntilContextTimeout(ctx, MetricsPoll, timeout, true, func(context.Context) (bool, error) {
 		select {
 		case <-cmo.finished:
 			log.Debugf("%s finished watching", cmo.GetName())
@@ -86,7 +86,7 @@ func (cmo *ContainerMetricsObserver) StartWatching(_ context.Context, runner *Ru
 			break
 		}
 
-		metricList, err := mc.Interface.MetricsV1beta1().PodMetricses(runner.DriverNamespace).List(context.Background(), metav1.ListOptions{})
+		metricList, err := mc.Interface.MetricsV1beta1().PodMetricses(runner.DriverNamespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
 			log.Errorf("Can't watch metricsClient. error = %v", err)
 			log.Warnf("Please use instruction in README to install metrics-server")
@@ -135,5 +135,5 @@ func (cmo *ContainerMetricsObserver) GetName() string {
 
 // MakeChannel makes a new channel
 func (cmo *ContainerMetricsObserver) MakeChannel() {
-	cmo.finished = make(chan bool)
+	cmo.finished = make(chan bool, 1)
 }
@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackendAttributes are volume attributes drivers identify storage array or system of the volume with,
+// the first one set is used as backend of PVC
+var BackendAttributes = []string{"arrayID", "ArrayID", "systemID", "SYMID", "ClusterName"}
+
+// placementTracker saves where entities were placed, so slow ones can be grouped by node, zone and backend
+type placementTracker struct {
+	recorded map[int64]bool
+	zones    map[string]string
+}
+
+func newPlacementTracker() *placementTracker {
+	return &placementTracker{recorded: make(map[int64]bool), zones: make(map[string]string)}
+}
+
+// record saves placement of entity once, zone is read from labels of node and backend from attributes of the
+// persistent volume, if it's given
+func (pt *placementTracker) record(ctx context.Context, runner *Runner, entity *store.Entity, node, pvName string) {
+	if entity.ID == 0 || node == "" || pt.recorded[entity.ID] {
+		return
+	}
+	pt.recorded[entity.ID] = true
+	placement := &store.EntityPlacement{TcID: runner.TestCase.ID, EntityID: entity.ID, Node: node}
+
+	if runner.Clients.PodClient != nil && runner.Clients.PodClient.ClientSet != nil {
+		clientSet := runner.Clients.PodClient.ClientSet
+		zone, known := pt.zones[node]
+		if !known {
+			if n, err := clientSet.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{}); err == nil {
+				zone = n.Labels[v1.LabelTopologyZone]
+			}
+			pt.zones[node] = zone
+		}
+		placement.Zone = zone
+		if pvName != "" {
+			if pv, err := clientSet.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{}); err == nil && pv.Spec.CSI != nil {
+				for _, attr := range BackendAttributes {
+					if backend := pv.Spec.CSI.VolumeAttributes[attr]; backend != "" {
+						placement.Backend = backend
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if err := runner.Database.SaveEntityPlacements([]*store.EntityPlacement{placement}); err != nil {
+		log.Errorf("Can't save placement of %s; error=%v", entity.Name, err)
+	}
+}
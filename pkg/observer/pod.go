@@ -27,43 +27,49 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
 // PodObserver is used to manage pod observer
 type PodObserver struct {
+	stream
 	finished chan bool
 }
 
 // StartWatching starts watching pod
-func (po *PodObserver) StartWatching(_ context.Context, runner *Runner) {
+func (po *PodObserver) StartWatching(ctx context.Context, runner *Runner) {
 	defer runner.WaitGroup.Done()
 
 	log.Debugf("%s started watching", po.GetName())
-	client := runner.Clients.PodClient
-	if client == nil {
-		log.Errorf("Pod client can't be nil")
-		return
+	var client WatchFunc
+	if runner.Clients.PodClient != nil {
+		client = runner.Clients.PodClient.Interface.Watch
 	}
-	timeout := WatchTimeout
-	w, watchErr := client.Interface.Watch(context.Background(), metav1.ListOptions{
-		TimeoutSeconds: &timeout,
-	})
+	w, watchErr := po.open(ctx, client)
 	if watchErr != nil {
 		log.Errorf("Can't watch podClient; error = %v", watchErr)
 		return
 	}
-	defer w.Stop()
+	defer func() { w.Stop() }()
 
 	var events []*store.Event
 	entities := make(map[string]*store.Entity)
 
 	readyPods := make(map[string]bool)
 	terminatingPods := make(map[string]bool)
+	ephemeral := newEphemeralTracker()
+	restarts := newRestartTracker()
+	placements := newPlacementTracker()
 
 	for {
 		select {
+		case <-ctx.Done():
+			// Run was cancelled, keep what was observed so far
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+			log.Debugf("%s interrupted", po.GetName())
+			return
 		case <-po.finished:
 			err := runner.Database.SaveEvents(events)
 			if err != nil {
@@ -72,7 +78,11 @@ func (po *PodObserver) StartWatching(_ context.Context, runner *Runner) {
 			}
 			log.Debugf("%s finished watching", po.GetName())
 			return
-		case data := <-w.ResultChan():
+		case data, open := <-w.ResultChan():
+			if !open {
+				w = po.reopen(ctx, w, client)
+				break
+			}
 			if data.Object == nil {
 				// ignore nil
 				break
@@ -84,9 +94,20 @@ func (po *PodObserver) StartWatching(_ context.Context, runner *Runner) {
 				break
 			}
 
+			entity, known := entities[pod.Name]
+			if data.Type != watch.Added && !known {
+				// Stream may start after pod was added, its events can't be linked to entity
+				log.Debugf("PodObserver: %s event of unknown pod %s", data.Type, pod.Name)
+				break
+			}
+			if data.Type == watch.Added && known && entity.K8sUID == string(pod.UID) {
+				// Pod replayed by reopened stream, changes missed meanwhile are picked up as modification
+				data.Type = watch.Modified
+			}
+
 			switch data.Type {
 			case watch.Added:
-				entity := &store.Entity{
+				entity = &store.Entity{
 					Name:   pod.Name,
 					K8sUID: string(pod.UID),
 					TcID:   runner.TestCase.ID,
@@ -102,23 +123,30 @@ func (po *PodObserver) StartWatching(_ context.Context, runner *Runner) {
 
 				entities[pod.Name] = entity
 				events = append(events, &store.Event{
-					Name:      "event-pod-added-" + k8sclient.RandomSuffix(),
+					Name:      "event-pod-added-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PodAdded,
 					Timestamp: time.Now(),
+					SourceKey: string(pod.UID),
 				})
+				events = append(events, ephemeral.observe(pod, entity.ID, runner.TestCase.ID)...)
+				events = append(events, restarts.observe(pod, entity.ID, runner.TestCase.ID)...)
 				break
 			case watch.Modified:
+				events = append(events, ephemeral.observe(pod, entity.ID, runner.TestCase.ID)...)
+				events = append(events, restarts.observe(pod, entity.ID, runner.TestCase.ID)...)
 				if !readyPods[pod.Name] && kubepod.IsPodReady(pod) {
 					// Pod is READY, adding event
 					readyPods[pod.Name] = true
+					placements.record(ctx, runner, entity, pod.Spec.NodeName, "")
 					events = append(events, &store.Event{
-						Name:      "event-pod-modified-" + k8sclient.RandomSuffix(),
+						Name:      "event-pod-modified-" + k8sclient.UniqueSuffix(),
 						TcID:      runner.TestCase.ID,
-						EntityID:  entities[pod.Name].ID,
+						EntityID:  entity.ID,
 						Type:      store.PodReady,
 						Timestamp: time.Now(),
+						SourceKey: string(pod.UID),
 					})
 					break
 				}
@@ -126,23 +154,27 @@ func (po *PodObserver) StartWatching(_ context.Context, runner *Runner) {
 					// Pod started deletion
 					terminatingPods[pod.Name] = true
 					events = append(events, &store.Event{
-						Name:      "event-pod-modified-" + k8sclient.RandomSuffix(),
+						Name:      "event-pod-modified-" + k8sclient.UniqueSuffix(),
 						TcID:      runner.TestCase.ID,
-						EntityID:  entities[pod.Name].ID,
+						EntityID:  entity.ID,
 						Type:      store.PodTerminating,
 						Timestamp: time.Now(),
+						SourceKey: string(pod.UID),
 					})
 					break
 				}
 				break
 			case watch.Deleted:
 				events = append(events, &store.Event{
-					Name:      "event-pod-deleted-" + k8sclient.RandomSuffix(),
+					Name:      "event-pod-deleted-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
-					EntityID:  entities[pod.Name].ID,
+					EntityID:  entity.ID,
 					Type:      store.PodDeleted,
 					Timestamp: time.Now(),
+					SourceKey: string(pod.UID),
 				})
+				events = append(events, ephemeral.deleted(pod.Name, entity.ID, runner.TestCase.ID)...)
+				restarts.deleted(pod.Name)
 				break
 			default:
 				log.Errorf("Unexpected event %v", data)
@@ -164,5 +196,5 @@ func (po *PodObserver) GetName() string {
 
 // MakeChannel creates a new channel
 func (po *PodObserver) MakeChannel() {
-	po.finished = make(chan bool)
+	po.finished = make(chan bool, 1)
 }
@@ -52,9 +52,12 @@ func (po *PodListObserver) StartWatching(ctx context.Context, runner *Runner) {
 	addedPods := make(map[string]bool)
 	readyPods := make(map[string]bool)
 	terminatingPods := make(map[string]bool)
+	ephemeral := newEphemeralTracker()
+	restarts := newRestartTracker()
+	placements := newPlacementTracker()
 	previousState := make(map[string]bool)
 
-	pollErr := wait.PollImmediate(1*time.Second, time.Duration(timeout)*time.Second, func() (bool, error) {
+	pollErr := wait.PollUntilContextTimeout(ctx, 1*time.Second, time.Duration(timeout)*time.Second, true, func(context.Context) (bool, error) {
 		select {
 		case <-po.finished:
 			log.Debugf("%s finished watching", po.GetName())
@@ -96,22 +99,28 @@ func (po *PodListObserver) StartWatching(ctx context.Context, runner *Runner) {
 
 				entities[pod.Name] = entity
 				events = append(events, &store.Event{
-					Name:      "event-pod-added-" + k8sclient.RandomSuffix(),
+					Name:      "event-pod-added-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PodAdded,
 					Timestamp: time.Now(),
 				})
+				events = append(events, ephemeral.observe(&podList.Items[i], entity.ID, runner.TestCase.ID)...)
+				events = append(events, restarts.observe(&podList.Items[i], entity.ID, runner.TestCase.ID)...)
 				addedPods[pod.Name] = true
 				continue
 			}
 
+			events = append(events, ephemeral.observe(&podList.Items[i], entities[pod.Name].ID, runner.TestCase.ID)...)
+			events = append(events, restarts.observe(&podList.Items[i], entities[pod.Name].ID, runner.TestCase.ID)...)
+
 			// case watch.Modified event
 			if !readyPods[pod.Name] && kubepod.IsPodReady(&podList.Items[i]) {
 				// Pod is READY, adding event
 				readyPods[pod.Name] = true
+				placements.record(ctx, runner, entities[pod.Name], pod.Spec.NodeName, "")
 				events = append(events, &store.Event{
-					Name:      "event-pod-modified-" + k8sclient.RandomSuffix(),
+					Name:      "event-pod-modified-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entities[pod.Name].ID,
 					Type:      store.PodReady,
@@ -124,7 +133,7 @@ func (po *PodListObserver) StartWatching(ctx context.Context, runner *Runner) {
 				// Pod started deletion
 				terminatingPods[pod.Name] = true
 				events = append(events, &store.Event{
-					Name:      "event-pod-modified-" + k8sclient.RandomSuffix(),
+					Name:      "event-pod-modified-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entities[pod.Name].ID,
 					Type:      store.PodTerminating,
@@ -138,12 +147,14 @@ func (po *PodListObserver) StartWatching(ctx context.Context, runner *Runner) {
 			if !currentState[name] {
 				// case watch.Deleted event
 				events = append(events, &store.Event{
-					Name:      "event-pod-deleted-" + k8sclient.RandomSuffix(),
+					Name:      "event-pod-deleted-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entities[name].ID,
 					Type:      store.PodDeleted,
 					Timestamp: time.Now(),
 				})
+				events = append(events, ephemeral.deleted(name, entities[name].ID, runner.TestCase.ID)...)
+				restarts.deleted(name)
 				delete(previousState, name)
 			}
 		}
@@ -156,6 +167,12 @@ func (po *PodListObserver) StartWatching(ctx context.Context, runner *Runner) {
 	})
 
 	if pollErr != nil {
+		if wait.Interrupted(pollErr) {
+			// Run was cancelled, keep what was observed so far
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+		}
 		log.Errorf("Can't poll podClient; error = %v", pollErr)
 		return
 	}
@@ -173,5 +190,5 @@ func (po *PodListObserver) GetName() string {
 
 // MakeChannel creates a new channel
 func (po *PodListObserver) MakeChannel() {
-	po.finished = make(chan bool)
+	po.finished = make(chan bool, 1)
 }
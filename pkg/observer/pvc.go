@@ -26,43 +26,50 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
+// SelectedNodeAnnotation is set on PVC of WaitForFirstConsumer storage class once its consumer pod is scheduled
+const SelectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+
 // PvcObserver is used to manage PVC Observer
 type PvcObserver struct {
+	stream
 	finished chan bool
 }
 
 // StartWatching starts watching a PVC
-func (obs *PvcObserver) StartWatching(_ context.Context, runner *Runner) {
+func (obs *PvcObserver) StartWatching(ctx context.Context, runner *Runner) {
 	defer runner.WaitGroup.Done()
 
 	log.Debugf("%s started watching", obs.GetName())
-	client := runner.Clients.PVCClient
-	if client == nil {
-		log.Errorf("PVCClient can't be nil")
-		return
+	var client WatchFunc
+	if runner.Clients.PVCClient != nil {
+		client = runner.Clients.PVCClient.Interface.Watch
 	}
-	timeout := WatchTimeout
-	w, watchErr := client.Interface.Watch(context.Background(), metav1.ListOptions{
-		TimeoutSeconds: &timeout,
-	})
+	w, watchErr := obs.open(ctx, client)
 	if watchErr != nil {
 		log.Errorf("Can't watch pvcClient; error = %v", watchErr)
 		return
 	}
-	defer w.Stop()
+	defer func() { w.Stop() }()
 
 	var events []*store.Event
 	entities := make(map[string]*store.Entity)
 
 	boundPVCs := make(map[string]bool)
+	scheduledPVCs := make(map[string]bool)
 	deletingPVCs := make(map[string]bool)
 
 	for {
 		select {
+		case <-ctx.Done():
+			// Run was cancelled, keep what was observed so far
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+			log.Debugf("%s interrupted", obs.GetName())
+			return
 		case <-obs.finished:
 			err := runner.Database.SaveEvents(events)
 			if err != nil {
@@ -71,7 +78,11 @@ func (obs *PvcObserver) StartWatching(_ context.Context, runner *Runner) {
 			}
 			log.Debugf("%s finished watching", obs.GetName())
 			return
-		case data := <-w.ResultChan():
+		case data, open := <-w.ResultChan():
+			if !open {
+				w = obs.reopen(ctx, w, client)
+				break
+			}
 			if data.Object == nil {
 				// ignore nil
 				break
@@ -83,9 +94,20 @@ func (obs *PvcObserver) StartWatching(_ context.Context, runner *Runner) {
 				break
 			}
 
+			entity, known := entities[pvc.Name]
+			if data.Type != watch.Added && !known {
+				// Stream may start after PVC was added, its events can't be linked to entity
+				log.Debugf("PvcObserver: %s event of unknown PVC %s", data.Type, pvc.Name)
+				break
+			}
+			if data.Type == watch.Added && known && entity.K8sUID == string(pvc.UID) {
+				// PVC replayed by reopened stream, changes missed meanwhile are picked up as modification
+				data.Type = watch.Modified
+			}
+
 			switch data.Type {
 			case watch.Added:
-				entity := &store.Entity{
+				entity = &store.Entity{
 					Name:   pvc.Name,
 					K8sUID: string(pvc.UID),
 					TcID:   runner.TestCase.ID,
@@ -100,50 +122,71 @@ func (obs *PvcObserver) StartWatching(_ context.Context, runner *Runner) {
 				}
 
 				entities[pvc.Name] = entity
-				events = append(events, &store.Event{
-					Name:      "event-pvc-added-" + k8sclient.RandomSuffix(),
+				event := &store.Event{
+					Name:      "event-pvc-added-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcAdded,
 					Timestamp: time.Now(),
-				})
+					SourceKey: string(pvc.UID),
+				}
+				events = append(events, event)
+				runner.Progress.Observe(event)
 				break
 			case watch.Modified:
+				if node, ok := pvc.Annotations[SelectedNodeAnnotation]; ok && !scheduledPVCs[pvc.Name] {
+					// Consumer of delayed-binding PVC was scheduled, provisioning starts now
+					scheduledPVCs[pvc.Name] = true
+					events = append(events, &store.Event{
+						Name:      "event-pvc-modified-" + k8sclient.UniqueSuffix(),
+						TcID:      runner.TestCase.ID,
+						EntityID:  entity.ID,
+						Type:      store.PvcNodeSelected,
+						Timestamp: time.Now(),
+						SourceKey: string(pvc.UID),
+						Message:   node,
+					})
+				}
 				if pvc.Status.Phase == v1.ClaimBound && !boundPVCs[pvc.Name] {
 					// PVC BOUNDED, adding event
 					boundPVCs[pvc.Name] = true
-					events = append(events, &store.Event{
-						Name:      "event-pvc-modified-" + k8sclient.RandomSuffix(),
+					event := &store.Event{
+						Name:      "event-pvc-modified-" + k8sclient.UniqueSuffix(),
 						TcID:      runner.TestCase.ID,
-						EntityID:  entities[pvc.Name].ID,
+						EntityID:  entity.ID,
 						Type:      store.PvcBound,
 						Timestamp: time.Now(),
-					})
+						SourceKey: string(pvc.UID),
+					}
+					events = append(events, event)
+					runner.Progress.Observe(event)
 
 					// Share pvc with volumeattachment observer
-					runner.PvcShare.Store(pvc.Spec.VolumeName, entities[pvc.Name])
+					runner.PvcShare.Store(pvc.Spec.VolumeName, entity)
 					break
 				}
 				if pvc.DeletionTimestamp != nil && !deletingPVCs[pvc.Name] {
 					// PVC started deletion
 					deletingPVCs[pvc.Name] = true
 					events = append(events, &store.Event{
-						Name:      "event-pvc-modified-" + k8sclient.RandomSuffix(),
+						Name:      "event-pvc-modified-" + k8sclient.UniqueSuffix(),
 						TcID:      runner.TestCase.ID,
-						EntityID:  entities[pvc.Name].ID,
+						EntityID:  entity.ID,
 						Type:      store.PvcDeletingStarted,
 						Timestamp: time.Now(),
+						SourceKey: string(pvc.UID),
 					})
 					break
 				}
 				break
 			case watch.Deleted:
 				events = append(events, &store.Event{
-					Name:      "event-pvc-deleted-" + k8sclient.RandomSuffix(),
+					Name:      "event-pvc-deleted-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
-					EntityID:  entities[pvc.Name].ID,
+					EntityID:  entity.ID,
 					Type:      store.PvcDeletingEnded,
 					Timestamp: time.Now(),
+					SourceKey: string(pvc.UID),
 				})
 				break
 			default:
@@ -166,5 +209,5 @@ func (*PvcObserver) GetName() string {
 
 // MakeChannel creates a new channel
 func (obs *PvcObserver) MakeChannel() {
-	obs.finished = make(chan bool)
+	obs.finished = make(chan bool, 1)
 }
@@ -52,10 +52,11 @@ func (obs *PvcListObserver) StartWatching(ctx context.Context, runner *Runner) {
 
 	addedPVCs := make(map[string]bool)
 	boundPVCs := make(map[string]bool)
+	scheduledPVCs := make(map[string]bool)
 	deletingPVCs := make(map[string]bool)
 	previousState := make(map[string]bool)
 
-	pollErr := wait.PollImmediate(1*time.Second, time.Duration(timeout)*time.Second, func() (bool, error) {
+	pollErr := wait.PollUntilContextTimeout(ctx, 1*time.Second, time.Duration(timeout)*time.Second, true, func(context.Context) (bool, error) {
 		select {
 		case <-obs.finished:
 			log.Debugf("%s finished watching", obs.GetName())
@@ -96,28 +97,44 @@ func (obs *PvcListObserver) StartWatching(ctx context.Context, runner *Runner) {
 				}
 
 				entities[pvc.Name] = entity
-				events = append(events, &store.Event{
-					Name:      "event-pvc-added-" + k8sclient.RandomSuffix(),
+				event := &store.Event{
+					Name:      "event-pvc-added-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcAdded,
 					Timestamp: time.Now(),
-				})
+				}
+				events = append(events, event)
+				runner.Progress.Observe(event)
 				addedPVCs[pvc.Name] = true
 				continue
 			}
 
 			// case watch.Modified event
+			if node, ok := pvc.Annotations[SelectedNodeAnnotation]; ok && !scheduledPVCs[pvc.Name] {
+				// Consumer of delayed-binding PVC was scheduled, provisioning starts now
+				scheduledPVCs[pvc.Name] = true
+				events = append(events, &store.Event{
+					Name:      "event-pvc-modified-" + k8sclient.UniqueSuffix(),
+					TcID:      runner.TestCase.ID,
+					EntityID:  entities[pvc.Name].ID,
+					Type:      store.PvcNodeSelected,
+					Timestamp: time.Now(),
+					Message:   node,
+				})
+			}
 			if pvc.Status.Phase == v1.ClaimBound && !boundPVCs[pvc.Name] {
 				// PVC BOUNDED, adding event
 				boundPVCs[pvc.Name] = true
-				events = append(events, &store.Event{
-					Name:      "event-pvc-modified-" + k8sclient.RandomSuffix(),
+				event := &store.Event{
+					Name:      "event-pvc-modified-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entities[pvc.Name].ID,
 					Type:      store.PvcBound,
 					Timestamp: time.Now(),
-				})
+				}
+				events = append(events, event)
+				runner.Progress.Observe(event)
 
 				// Share pvc with volumeattachment observer
 				runner.PvcShare.Store(pvc.Spec.VolumeName, entities[pvc.Name])
@@ -127,7 +144,7 @@ func (obs *PvcListObserver) StartWatching(ctx context.Context, runner *Runner) {
 				// PVC started deletion
 				deletingPVCs[pvc.Name] = true
 				events = append(events, &store.Event{
-					Name:      "event-pvc-modified-" + k8sclient.RandomSuffix(),
+					Name:      "event-pvc-modified-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entities[pvc.Name].ID,
 					Type:      store.PvcDeletingStarted,
@@ -141,7 +158,7 @@ func (obs *PvcListObserver) StartWatching(ctx context.Context, runner *Runner) {
 			if !currentState[name] {
 				// case watch.Deleted event
 				events = append(events, &store.Event{
-					Name:      "event-pvc-deleted-" + k8sclient.RandomSuffix(),
+					Name:      "event-pvc-deleted-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entities[name].ID,
 					Type:      store.PvcDeletingEnded,
@@ -159,6 +176,12 @@ func (obs *PvcListObserver) StartWatching(ctx context.Context, runner *Runner) {
 	})
 
 	if pollErr != nil {
+		if wait.Interrupted(pollErr) {
+			// Run was cancelled, keep what was observed so far
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+		}
 		log.Errorf("Can't poll podClient; error = %v", pollErr)
 		return
 	}
@@ -176,5 +199,5 @@ func (*PvcListObserver) GetName() string {
 
 // MakeChannel creates a new channel
 func (obs *PvcListObserver) MakeChannel() {
-	obs.finished = make(chan bool)
+	obs.finished = make(chan bool, 1)
 }
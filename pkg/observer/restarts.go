@@ -0,0 +1,87 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// restartTracker records container restarts, OOM kills and CrashLoopBackOff transitions of pods,
+// so instability of application caused by volume issues isn't hidden behind pod being ready once
+type restartTracker struct {
+	restarts     map[string]map[string]int32
+	crashLooping map[string]map[string]bool
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{
+		restarts:     make(map[string]map[string]int32),
+		crashLooping: make(map[string]map[string]bool),
+	}
+}
+
+// observe returns events caused by changes of container statuses since the previous state of the pod
+func (rt *restartTracker) observe(pod *v1.Pod, entityID, tcID int64) []*store.Event {
+	if rt.restarts[pod.Name] == nil {
+		rt.restarts[pod.Name] = make(map[string]int32)
+		rt.crashLooping[pod.Name] = make(map[string]bool)
+	}
+	restarts := rt.restarts[pod.Name]
+	crashLooping := rt.crashLooping[pod.Name]
+
+	var events []*store.Event
+	for _, status := range pod.Status.ContainerStatuses {
+		// Every restart gets its own event, even if several of them happened between two observations
+		for i := restarts[status.Name]; i < status.RestartCount; i++ {
+			events = append(events, newRestartEvent(store.PodContainerRestarted, status.Name, entityID, tcID))
+		}
+		if status.RestartCount > restarts[status.Name] {
+			if last := status.LastTerminationState.Terminated; last != nil && last.Reason == "OOMKilled" {
+				events = append(events, newRestartEvent(store.PodOOMKilled, status.Name, entityID, tcID))
+			}
+			restarts[status.Name] = status.RestartCount
+		}
+
+		inBackOff := status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff"
+		if inBackOff && !crashLooping[status.Name] {
+			events = append(events, newRestartEvent(store.PodCrashLoopBackOff, status.Name, entityID, tcID))
+		}
+		crashLooping[status.Name] = inBackOff
+	}
+	return events
+}
+
+// deleted forgets the pod, so pod recreated with the same name starts from scratch
+func (rt *restartTracker) deleted(name string) {
+	delete(rt.restarts, name)
+	delete(rt.crashLooping, name)
+}
+
+func newRestartEvent(eventType store.EventTypeEnum, container string, entityID, tcID int64) *store.Event {
+	return &store.Event{
+		Name:      "event-pod-" + container + "-" + k8sclient.UniqueSuffix(),
+		TcID:      tcID,
+		EntityID:  entityID,
+		Type:      eventType,
+		Timestamp: time.Now(),
+	}
+}
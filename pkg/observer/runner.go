@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/progress"
 	"github.com/dell/cert-csi/pkg/store"
 
 	"github.com/sirupsen/logrus"
@@ -54,6 +55,7 @@ type Runner struct {
 	PvcShare        sync.Map
 	DriverNamespace string
 	ShouldClean     bool
+	Progress        *progress.Tracker
 }
 
 // NewObserverRunner returns a Runner instance
@@ -143,10 +145,21 @@ func (runner *Runner) waitTimeout(timeout time.Duration) bool {
 	}
 }
 
-// Interface contains common function definitions
+// Interface contains common function definitions, StartWatching returns once context is cancelled, so StopWatching must not block
 type Interface interface {
 	StartWatching(context.Context, *Runner)
 	StopWatching()
 	GetName() string
 	MakeChannel()
 }
+
+// NotObservable returns what observer can't watch with namespaced permissions only, empty if it doesn't need cluster-scoped access
+func NotObservable(obs Interface) string {
+	switch obs.(type) {
+	case *VaObserver, *VaListObserver:
+		return "VolumeAttachments"
+	case *ContainerMetricsObserver:
+		return "driver container metrics"
+	}
+	return ""
+}
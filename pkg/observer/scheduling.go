@@ -0,0 +1,149 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// churnReasons maps reasons of Kubernetes events pods are displaced from their nodes with to event types
+var churnReasons = map[string]store.EventTypeEnum{
+	"Preempted":            store.PodPreempted,
+	"Descheduled":          store.PodEvicted,
+	"Evicted":              store.PodEvicted,
+	"TaintManagerEviction": store.PodEvicted,
+}
+
+// SchedulingObserver records preemptions, evictions and rescheduling of pods done by scheduler, descheduler
+// or kubelet, so latency of replicas can be attributed to scheduling churn rather than to the driver
+type SchedulingObserver struct {
+	stream
+	finished chan bool
+}
+
+// schedulingTracker follows nodes pods are scheduled to and turns displacements into tags of pods
+type schedulingTracker struct {
+	nodes     map[string]string
+	displaced map[string]bool
+	tags      []*Tag
+}
+
+func newSchedulingTracker() *schedulingTracker {
+	return &schedulingTracker{nodes: make(map[string]string), displaced: make(map[string]bool)}
+}
+
+// observe records event of the pod, scheduling of displaced pod is recorded as rescheduling
+func (st *schedulingTracker) observe(pod, reason, message string) {
+	if t, ok := churnReasons[reason]; ok {
+		st.displaced[pod] = true
+		st.tags = append(st.tags, NewTag(pod, t, message))
+		return
+	}
+	if reason != "Scheduled" {
+		return
+	}
+	// Scheduler reports binding as "Successfully assigned <namespace>/<pod> to <node>"
+	i := strings.LastIndex(message, " to ")
+	if i == -1 {
+		return
+	}
+	node := message[i+len(" to "):]
+	if st.displaced[pod] {
+		msg := fmt.Sprintf("%s -> %s", st.nodes[pod], node)
+		if st.nodes[pod] == "" {
+			msg = "to " + node
+		}
+		st.tags = append(st.tags, NewTag(pod, store.PodRescheduled, msg))
+		st.displaced[pod] = false
+	}
+	st.nodes[pod] = node
+}
+
+// StartWatching starts watching events of pods of the namespace
+func (so *SchedulingObserver) StartWatching(ctx context.Context, runner *Runner) {
+	defer runner.WaitGroup.Done()
+
+	log.Debugf("%s started watching", so.GetName())
+	var client WatchFunc
+	if runner.Clients.PodClient != nil {
+		client = runner.Clients.PodClient.ClientSet.CoreV1().Events(runner.Clients.PodClient.Namespace).Watch
+	}
+	w, watchErr := so.open(ctx, client)
+	if watchErr != nil {
+		log.Errorf("Can't watch events; error = %v", watchErr)
+		return
+	}
+	defer w.Stop()
+
+	tracker := newSchedulingTracker()
+	for {
+		select {
+		case <-ctx.Done():
+			so.save(runner, tracker.tags)
+			log.Debugf("%s interrupted", so.GetName())
+			return
+		case <-so.finished:
+			so.save(runner, tracker.tags)
+			log.Debugf("%s finished watching", so.GetName())
+			return
+		case data := <-w.ResultChan():
+			// Repeated events only bump count, first occurrence is the decisive one
+			if data.Type != watch.Added {
+				break
+			}
+			e, ok := data.Object.(*v1.Event)
+			if !ok || e.InvolvedObject.Kind != "Pod" {
+				break
+			}
+			tracker.observe(e.InvolvedObject.Name, e.Reason, e.Message)
+		}
+	}
+}
+
+// save saves tags as events of pod entities saved by pod observer
+func (so *SchedulingObserver) save(runner *Runner, tags []*Tag) {
+	if len(tags) == 0 {
+		return
+	}
+	log.Infof("Recorded %d preemptions, evictions and reschedulings of pods", len(tags))
+	if err := SaveTags(runner.Database, runner.TestCase, tags); err != nil {
+		log.Warnf("Can't save all scheduling events; error=%v", err)
+	}
+}
+
+// StopWatching stops watching events
+func (so *SchedulingObserver) StopWatching() {
+	so.finished <- true
+}
+
+// GetName returns name of scheduling observer
+func (so *SchedulingObserver) GetName() string {
+	return "SchedulingObserver"
+}
+
+// MakeChannel creates a new channel
+func (so *SchedulingObserver) MakeChannel() {
+	so.finished = make(chan bool, 1)
+}
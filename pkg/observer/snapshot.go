@@ -0,0 +1,236 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	snapv1 "github.com/dell/cert-csi/pkg/k8sclient/resources/volumesnapshot/v1"
+	snapbeta "github.com/dell/cert-csi/pkg/k8sclient/resources/volumesnapshot/v1beta1"
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// SnapshotPoll is a poll interval for snapshot observation
+var SnapshotPoll = 2 * time.Second
+
+// SnapshotObserver records when volume snapshots of test case are added and become ready, snapshots which aren't
+// ready within the deadline snapshot clients wait for get a timeout event with the last error from their status
+type SnapshotObserver struct {
+	finished chan bool
+
+	interrupted bool
+	mutex       sync.Mutex
+}
+
+// snapshotState is a version independent state of volume snapshot
+type snapshotState struct {
+	Name      string
+	UID       string
+	Created   time.Time
+	Ready     bool
+	LastError string
+}
+
+// snapshotTracker holds readiness of observed snapshots
+type snapshotTracker struct {
+	deadline time.Duration
+	entities map[string]*store.Entity
+	added    map[string]time.Time
+	ready    map[string]bool
+	timedOut map[string]bool
+}
+
+func newSnapshotTracker(deadline time.Duration) *snapshotTracker {
+	return &snapshotTracker{
+		deadline: deadline,
+		entities: make(map[string]*store.Entity),
+		added:    make(map[string]time.Time),
+		ready:    make(map[string]bool),
+		timedOut: make(map[string]bool),
+	}
+}
+
+// Interrupt interrupts a snapshot observer
+func (so *SnapshotObserver) Interrupt() {
+	so.mutex.Lock()
+	defer so.mutex.Unlock()
+	so.interrupted = true
+}
+
+// Interrupted checks whether snapshot observer is interrupted
+func (so *SnapshotObserver) Interrupted() bool {
+	so.mutex.Lock()
+	defer so.mutex.Unlock()
+	return so.interrupted
+}
+
+// StartWatching starts polling volume snapshots of test case namespace
+func (so *SnapshotObserver) StartWatching(ctx context.Context, runner *Runner) {
+	defer runner.WaitGroup.Done()
+	ga, beta := runner.Clients.SnapClientGA, runner.Clients.SnapClientBeta
+	var deadline time.Duration
+	switch {
+	case ga != nil:
+		deadline = ga.ReadyTimeout()
+	case beta != nil:
+		deadline = beta.ReadyTimeout()
+	default:
+		log.Errorf("Snapshot client can't be nil")
+		so.Interrupt()
+		return
+	}
+	log.Debugf("%s started watching", so.GetName())
+
+	tracker := newSnapshotTracker(deadline)
+	observe := func() {
+		snapshots, err := listSnapshots(ctx, ga, beta)
+		if err != nil {
+			log.Debugf("Can't list snapshots; error=%v", err)
+			return
+		}
+		var events []*store.Event
+		for _, s := range snapshots {
+			if tracker.entities[s.Name] == nil {
+				entity := &store.Entity{
+					Name:   s.Name,
+					K8sUID: s.UID,
+					TcID:   runner.TestCase.ID,
+					Type:   store.Snapshot,
+				}
+				if err := runner.Database.SaveEntities([]*store.Entity{entity}); err != nil {
+					log.Errorf("Can't save entity; error=%v", err)
+					continue
+				}
+				tracker.entities[s.Name] = entity
+			}
+			events = append(events, tracker.observe(s, time.Now(), runner.TestCase.ID)...)
+		}
+		if len(events) != 0 {
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+		}
+	}
+
+	pollErr := wait.PollUntilContextTimeout(ctx, SnapshotPoll, time.Duration(WatchTimeout)*time.Second, true, func(context.Context) (bool, error) {
+		select {
+		case <-so.finished:
+			// Suite gives up on stuck snapshot right at the deadline, so the last look catches its timeout
+			observe()
+			log.Debugf("%s finished watching", so.GetName())
+			return true, nil
+		default:
+			break
+		}
+		observe()
+		return false, nil
+	})
+
+	if pollErr != nil {
+		log.Errorf("Error with polling; error=%v", pollErr)
+		so.Interrupt()
+	}
+}
+
+// listSnapshots returns states of snapshots listed with GA client, or beta one if GA isn't available
+func listSnapshots(ctx context.Context, ga *snapv1.SnapshotClient, beta *snapbeta.SnapshotClient) ([]snapshotState, error) {
+	var states []snapshotState
+	if ga != nil {
+		list, err := ga.Interface.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i, sn := range list.Items {
+			ready := sn.Status != nil && sn.Status.ReadyToUse != nil && *sn.Status.ReadyToUse
+			states = append(states, snapshotState{sn.Name, string(sn.UID), sn.CreationTimestamp.Time, ready, snapv1.LastError(&list.Items[i])})
+		}
+		return states, nil
+	}
+	list, err := beta.Interface.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i, sn := range list.Items {
+		ready := sn.Status != nil && sn.Status.ReadyToUse != nil && *sn.Status.ReadyToUse
+		states = append(states, snapshotState{sn.Name, string(sn.UID), sn.CreationTimestamp.Time, ready, snapbeta.LastError(&list.Items[i])})
+	}
+	return states, nil
+}
+
+// observe returns events caused by the current state of snapshot, its entity must be tracked already
+func (st *snapshotTracker) observe(s snapshotState, now time.Time, tcID int64) []*store.Event {
+	entity := st.entities[s.Name]
+	newEvent := func(t store.EventTypeEnum, ts time.Time, msg string) *store.Event {
+		return &store.Event{
+			Name:      "event-snapshot-" + k8sclient.UniqueSuffix(),
+			TcID:      tcID,
+			EntityID:  entity.ID,
+			Type:      t,
+			Timestamp: ts,
+			Message:   msg,
+		}
+	}
+
+	var events []*store.Event
+	if _, ok := st.added[s.Name]; !ok {
+		// Deadline is counted from creation, as suites start waiting for snapshot right after creating it
+		added := s.Created
+		if added.IsZero() || added.After(now) {
+			added = now
+		}
+		st.added[s.Name] = added
+		events = append(events, newEvent(store.SnapshotAdded, added, ""))
+	}
+	if s.Ready && !st.ready[s.Name] {
+		st.ready[s.Name] = true
+		events = append(events, newEvent(store.SnapshotReady, now, ""))
+	}
+	if !s.Ready && !st.timedOut[s.Name] && now.Sub(st.added[s.Name]) >= st.deadline {
+		st.timedOut[s.Name] = true
+		msg := s.LastError
+		if msg == "" {
+			msg = "no error reported in status"
+		}
+		log.Warnf("Snapshot %s isn't ready after %s: %s", s.Name, st.deadline, msg)
+		events = append(events, newEvent(store.SnapshotReadyTimeout, now, msg))
+	}
+	return events
+}
+
+// StopWatching stops polling snapshots
+func (so *SnapshotObserver) StopWatching() {
+	if !so.Interrupted() {
+		so.finished <- true
+	}
+}
+
+// GetName returns name of snapshot observer
+func (so *SnapshotObserver) GetName() string {
+	return "SnapshotObserver"
+}
+
+// MakeChannel makes a new channel
+func (so *SnapshotObserver) MakeChannel() {
+	so.finished = make(chan bool, 1)
+}
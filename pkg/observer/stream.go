@@ -0,0 +1,105 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// errNoClient is returned when observer has neither stream override nor client to open stream with
+var errNoClient = errors.New("client can't be nil")
+
+// WatchFunc opens watch stream of objects observer records events of
+type WatchFunc func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+
+// Streamed is implemented by observers consuming watch stream, replacing the stream lets them be tested with FakeStream
+type Streamed interface {
+	Interface
+	SetWatch(WatchFunc)
+}
+
+// stream is embedded into observers consuming watch stream
+type stream struct {
+	watch WatchFunc
+}
+
+// SetWatch replaces stream opened with clients of the runner
+func (s *stream) SetWatch(w WatchFunc) {
+	s.watch = w
+}
+
+// open opens replaced stream if it's set, otherwise the one of client
+func (s *stream) open(ctx context.Context, client WatchFunc) (watch.Interface, error) {
+	timeout := WatchTimeout
+	opts := metav1.ListOptions{TimeoutSeconds: &timeout}
+	if s.watch != nil {
+		return s.watch(ctx, opts)
+	}
+	if client == nil {
+		return nil, errNoClient
+	}
+	return client(ctx, opts)
+}
+
+// reopen stops closed watch w and opens stream again. Objects existing at that moment are replayed as added, so
+// observers treat added events of objects they know as modifications. If stream can't be reopened, returned watch
+// never delivers events and observer just waits to be stopped
+func (s *stream) reopen(ctx context.Context, w watch.Interface, client WatchFunc) watch.Interface {
+	w.Stop()
+	reopened, err := s.open(ctx, client)
+	if err != nil {
+		log.Errorf("Can't reopen watch; error=%v", err)
+		return watch.NewFake()
+	}
+	log.Debugf("Watch was closed by server and reopened")
+	return reopened
+}
+
+// FakeStream is a watch stream events are sent into by test, every event is received by observer before Add,
+// Modify or Delete returns, so sequence of events observer sees is deterministic
+type FakeStream struct {
+	*watch.FakeWatcher
+	mutex sync.Mutex
+}
+
+// NewFakeStream creates FakeStream
+func NewFakeStream() *FakeStream {
+	return &FakeStream{FakeWatcher: watch.NewFake()}
+}
+
+// Watch is a WatchFunc returning the stream
+func (fs *FakeStream) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	return fs.FakeWatcher, nil
+}
+
+// Reconnect closes the stream like API server does when watch times out, observer opens a new one, events sent
+// afterwards go into it
+func (fs *FakeStream) Reconnect() {
+	fs.mutex.Lock()
+	closed := fs.FakeWatcher
+	fs.FakeWatcher = watch.NewFake()
+	fs.mutex.Unlock()
+	closed.Stop()
+}
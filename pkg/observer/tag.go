@@ -0,0 +1,86 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package observer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Tag is an event of registered custom type suite marks its Kubernetes object with
+type Tag struct {
+	// Name of PVC, Pod or Node the event happened to
+	Name      string
+	Type      store.EventTypeEnum
+	Timestamp time.Time
+	Message   string
+}
+
+// NewTag returns tag of object happened now
+func NewTag(name string, t store.EventTypeEnum, message string) *Tag {
+	return &Tag{Name: name, Type: t, Timestamp: time.Now(), Message: message}
+}
+
+// SaveTags saves tags as events of test case entities, tags of unregistered types or unknown objects are skipped
+func SaveTags(db store.Store, tc *store.TestCase, tags []*Tag) error {
+	entities, err := db.GetEntities(store.Conditions{"tc_id": tc.ID}, "", 0)
+	if err != nil {
+		return err
+	}
+
+	var events []*store.Event
+	var skipped []string
+	for _, tag := range tags {
+		info, ok := store.GetEventType(tag.Type)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s (unregistered type %s)", tag.Name, tag.Type))
+			continue
+		}
+		entityID := int64(-1)
+		for _, e := range entities {
+			if e.Name == tag.Name && (info.Entity == store.Unknown || e.Type == info.Entity) {
+				entityID = e.ID
+				break
+			}
+		}
+		if entityID == -1 {
+			skipped = append(skipped, fmt.Sprintf("%s (no %s entity)", tag.Name, info.Entity))
+			continue
+		}
+		events = append(events, &store.Event{
+			Name:      "event-tag-" + k8sclient.UniqueSuffix(),
+			TcID:      tc.ID,
+			EntityID:  entityID,
+			Type:      tag.Type,
+			Timestamp: tag.Timestamp,
+			Message:   tag.Message,
+		})
+	}
+
+	if len(events) != 0 {
+		if err := db.SaveEvents(events); err != nil {
+			return err
+		}
+	}
+	if len(skipped) != 0 {
+		return fmt.Errorf("skipped tags: %v", skipped)
+	}
+	return nil
+}
@@ -25,45 +25,50 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	storagev1 "k8s.io/api/storage/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
 // VaObserver is used to manage volume attachment observer
 type VaObserver struct {
+	stream
 	finished chan bool
 }
 
 // StartWatching starts watching a volume attachment and related events
-func (vao *VaObserver) StartWatching(_ context.Context, runner *Runner) {
+func (vao *VaObserver) StartWatching(ctx context.Context, runner *Runner) {
 	defer runner.WaitGroup.Done()
 
 	log.Debugf("%s started watching", vao.GetName())
-	client := runner.Clients.VaClient
-	if client == nil {
-		log.Errorf("VolumeAttachment client can't be nil")
-		return
+	var client WatchFunc
+	if runner.Clients.VaClient != nil {
+		client = runner.Clients.VaClient.Interface.Watch
 	}
-
-	timeout := WatchTimeout
-	w, watchErr := client.Interface.Watch(context.Background(), metav1.ListOptions{
-		TimeoutSeconds: &timeout,
-	})
+	w, watchErr := vao.open(ctx, client)
 	if watchErr != nil {
 		log.Errorf("Can't watch VolumeAttachment client; error = %v", watchErr)
 		return
 	}
-	defer w.Stop()
+	defer func() { w.Stop() }()
 
 	var events []*store.Event
+	addedVAs := make(map[types.UID]bool)
 	attachedVAs := make(map[string]bool)
 	deletingVAs := make(map[string]bool)
 	deletedVAs := make(map[string]bool)
+	placements := newPlacementTracker()
 
 	var shouldExit bool
 
 	for {
 		select {
+		case <-ctx.Done():
+			// Run was cancelled, keep what was observed so far
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+			log.Debugf("%s interrupted", vao.GetName())
+			return
 		case <-vao.finished:
 			// We can't finish if we haven't received all deletion events
 			if len(attachedVAs) == len(deletedVAs) || !runner.ShouldClean {
@@ -79,7 +84,11 @@ func (vao *VaObserver) StartWatching(_ context.Context, runner *Runner) {
 			log.Info("Waiting for volumeattachments to be deleted")
 			shouldExit = true
 
-		case data := <-w.ResultChan():
+		case data, open := <-w.ResultChan():
+			if !open {
+				w = vao.reopen(ctx, w, client)
+				break
+			}
 			if data.Object == nil {
 				// ignore nil
 				break
@@ -95,38 +104,51 @@ func (vao *VaObserver) StartWatching(_ context.Context, runner *Runner) {
 				break
 			}
 			entity := loaded.(*store.Entity)
+			if data.Type == watch.Added && addedVAs[va.UID] {
+				// Attachment replayed by reopened stream, changes missed meanwhile are picked up as modification
+				data.Type = watch.Modified
+			}
 
 			switch data.Type {
 			case watch.Added:
-				events = append(events, &store.Event{
-					Name:      "event-va-added-" + k8sclient.RandomSuffix(),
+				addedVAs[va.UID] = true
+				placements.record(ctx, runner, entity, va.Spec.NodeName, *va.Spec.Source.PersistentVolumeName)
+				event := &store.Event{
+					Name:      "event-va-added-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcAttachStarted,
 					Timestamp: time.Now(),
-				})
+					SourceKey: string(va.UID),
+				}
+				events = append(events, event)
+				runner.Progress.Observe(event)
 				break
 			case watch.Modified:
 				if va.Status.Attached && !attachedVAs[va.Name] {
 					attachedVAs[va.Name] = true
-					events = append(events, &store.Event{
-						Name:      "event-va-modified-" + k8sclient.RandomSuffix(),
+					event := &store.Event{
+						Name:      "event-va-modified-" + k8sclient.UniqueSuffix(),
 						TcID:      runner.TestCase.ID,
 						EntityID:  entity.ID,
 						Type:      store.PvcAttachEnded,
 						Timestamp: time.Now(),
-					})
+						SourceKey: string(va.UID),
+					}
+					events = append(events, event)
+					runner.Progress.Observe(event)
 					break
 				}
 
 				if va.DeletionTimestamp != nil && !deletingVAs[va.Name] {
 					deletingVAs[va.Name] = true
 					events = append(events, &store.Event{
-						Name:      "event-va-modified-" + k8sclient.RandomSuffix(),
+						Name:      "event-va-modified-" + k8sclient.UniqueSuffix(),
 						TcID:      runner.TestCase.ID,
 						EntityID:  entity.ID,
 						Type:      store.PvcUnattachStarted,
 						Timestamp: time.Now(),
+						SourceKey: string(va.UID),
 					})
 					break
 				}
@@ -134,11 +156,12 @@ func (vao *VaObserver) StartWatching(_ context.Context, runner *Runner) {
 			case watch.Deleted:
 				deletedVAs[va.Name] = true
 				events = append(events, &store.Event{
-					Name:      "event-va-deleted-" + k8sclient.RandomSuffix(),
+					Name:      "event-va-deleted-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcUnattachEnded,
 					Timestamp: time.Now(),
+					SourceKey: string(va.UID),
 				})
 
 				if shouldExit && len(attachedVAs) == len(deletedVAs) {
@@ -171,5 +194,5 @@ func (vao *VaObserver) GetName() string {
 
 // MakeChannel creates a new channel
 func (vao *VaObserver) MakeChannel() {
-	vao.finished = make(chan bool)
+	vao.finished = make(chan bool, 1)
 }
@@ -51,10 +51,11 @@ func (vao *VaListObserver) StartWatching(ctx context.Context, runner *Runner) {
 	attachedVAs := make(map[string]bool)
 	deletingVAs := make(map[string]bool)
 	deletedVAs := make(map[string]bool)
+	placements := newPlacementTracker()
 	previousState := make(map[string]bool)
 	var shouldExit bool
 
-	pollErr := wait.PollImmediate(500*time.Millisecond, time.Duration(timeout)*time.Second, func() (bool, error) {
+	pollErr := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, time.Duration(timeout)*time.Second, true, func(context.Context) (bool, error) {
 		select {
 		case <-vao.finished:
 			if len(attachedVAs) == len(deletedVAs) || !runner.ShouldClean {
@@ -92,13 +93,16 @@ func (vao *VaListObserver) StartWatching(ctx context.Context, runner *Runner) {
 			currentState[*va.Spec.Source.PersistentVolumeName] = true
 
 			if !addedVAs[va.Name] {
-				events = append(events, &store.Event{
-					Name:      "event-va-added-" + k8sclient.RandomSuffix(),
+				placements.record(ctx, runner, entity, va.Spec.NodeName, *va.Spec.Source.PersistentVolumeName)
+				event := &store.Event{
+					Name:      "event-va-added-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcAttachStarted,
 					Timestamp: time.Now(),
-				})
+				}
+				events = append(events, event)
+				runner.Progress.Observe(event)
 				addedVAs[va.Name] = true
 				continue
 			}
@@ -106,20 +110,22 @@ func (vao *VaListObserver) StartWatching(ctx context.Context, runner *Runner) {
 			// case watch.Modified event
 			if va.Status.Attached && !attachedVAs[va.Name] {
 				attachedVAs[va.Name] = true
-				events = append(events, &store.Event{
-					Name:      "event-va-modified-" + k8sclient.RandomSuffix(),
+				event := &store.Event{
+					Name:      "event-va-modified-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcAttachEnded,
 					Timestamp: time.Now(),
-				})
+				}
+				events = append(events, event)
+				runner.Progress.Observe(event)
 				continue
 			}
 
 			if va.DeletionTimestamp != nil && !deletingVAs[va.Name] {
 				deletingVAs[va.Name] = true
 				events = append(events, &store.Event{
-					Name:      "event-va-modified-" + k8sclient.RandomSuffix(),
+					Name:      "event-va-modified-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcUnattachStarted,
@@ -139,7 +145,7 @@ func (vao *VaListObserver) StartWatching(ctx context.Context, runner *Runner) {
 				entity := loaded.(*store.Entity)
 				// case watch.Deleted event
 				events = append(events, &store.Event{
-					Name:      "event-va-deleted-" + k8sclient.RandomSuffix(),
+					Name:      "event-va-deleted-" + k8sclient.UniqueSuffix(),
 					TcID:      runner.TestCase.ID,
 					EntityID:  entity.ID,
 					Type:      store.PvcUnattachEnded,
@@ -168,6 +174,12 @@ func (vao *VaListObserver) StartWatching(ctx context.Context, runner *Runner) {
 	})
 
 	if pollErr != nil {
+		if wait.Interrupted(pollErr) {
+			// Run was cancelled, keep what was observed so far
+			if err := runner.Database.SaveEvents(events); err != nil {
+				log.Errorf("Error saving events; error=%v", err)
+			}
+		}
 		log.Errorf("Can't poll podClient; error = %v", pollErr)
 		return
 	}
@@ -185,5 +197,5 @@ func (vao *VaListObserver) GetName() string {
 
 // MakeChannel creates a new channel
 func (vao *VaListObserver) MakeChannel() {
-	vao.finished = make(chan bool)
+	vao.finished = make(chan bool, 1)
 }
@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package plotter
+
+import (
+	"math"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+// MaxPlotPoints is the maximum number of points drawn per series, longer series are downsampled.
+// Zero or negative value disables downsampling
+var MaxPlotPoints = 2000
+
+// decimate downsamples series to at most max points. Range of X is split into max/2 buckets of equal width and
+// minimum and maximum of every bucket are kept, so spikes stay visible and sparse parts of series keep their points.
+// Points are expected to be sorted by X
+func decimate(xys plotter.XYs, max int) plotter.XYs {
+	if max <= 0 || len(xys) <= max {
+		return xys
+	}
+	buckets := max / 2
+	if buckets == 0 {
+		buckets = 1
+	}
+	minX, maxX := xys[0].X, xys[0].X
+	for _, xy := range xys {
+		minX = math.Min(minX, xy.X)
+		maxX = math.Max(maxX, xy.X)
+	}
+	width := (maxX - minX) / float64(buckets)
+	bucket := func(x float64) int {
+		if width == 0 {
+			return 0
+		}
+		return int(math.Min((x-minX)/width, float64(buckets-1)))
+	}
+
+	out := make(plotter.XYs, 0, 2*buckets)
+	for start := 0; start < len(xys); {
+		b := bucket(xys[start].X)
+		lo, hi := start, start
+		end := start + 1
+		for ; end < len(xys) && bucket(xys[end].X) == b; end++ {
+			if xys[end].Y < xys[lo].Y {
+				lo = end
+			}
+			if xys[end].Y > xys[hi].Y {
+				hi = end
+			}
+		}
+		// Points are kept in their original order so the line doesn't go back in time
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		out = append(out, xys[lo])
+		if hi != lo {
+			out = append(out, xys[hi])
+		}
+		start = end
+	}
+	return out
+}
@@ -25,9 +25,11 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
 
 	log "github.com/sirupsen/logrus"
 	"gonum.org/v1/plot"
@@ -43,27 +45,133 @@ var (
 	UserPath = ""
 	// FolderPath of .cert-csi folder
 	FolderPath = "/.cert-csi/"
+	// ReportLayout defines how reports and plots of different runs are placed inside the reports folder
+	ReportLayout = LayoutPerRun
+	// PlotFormat is the file format plots are saved in
+	PlotFormat = FormatPNG
+
+	// runTimestamp is shared by all reports of the current process, so plots and reports of a run stay together
+	runTimestamp = time.Now().Format("2006-01-02_15_04_05")
+
+	// reportRoots maps run names to directories their reports are generated in instead of UserPath
+	reportRoots sync.Map
+)
+
+// Layout is a layout of report artifacts inside the reports folder
+type Layout string
+
+const (
+	// LayoutPerRun places artifacts of every run into its own subfolder named after the run
+	LayoutPerRun Layout = "per-run"
+	// LayoutFlat places artifacts of all runs directly into the reports folder
+	LayoutFlat Layout = "flat"
+	// LayoutTimestamped places artifacts into subfolder named after the run and the time it was reported,
+	// so repeated reports of the same run don't overwrite each other
+	LayoutTimestamped Layout = "timestamped"
+)
+
+// ParseLayout converts layout name to Layout, empty name means default per-run layout
+func ParseLayout(name string) (Layout, error) {
+	switch l := Layout(name); l {
+	case "":
+		return LayoutPerRun, nil
+	case LayoutPerRun, LayoutFlat, LayoutTimestamped:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unknown report layout %q, must be one of: %s, %s, %s", name, LayoutPerRun, LayoutFlat, LayoutTimestamped)
+	}
+}
+
+// Format is a file format of plots
+type Format string
+
+const (
+	// FormatPNG saves plots as raster PNG images
+	FormatPNG Format = "png"
+	// FormatSVG saves plots as vector SVG images, which stay crisp when scaled and can be styled downstream
+	FormatSVG Format = "svg"
+	// FormatPDF saves plots as vector PDF documents, ex. to be embedded into certification documents
+	FormatPDF Format = "pdf"
 )
 
+// ParseFormat converts format name to Format, empty name means default PNG format
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(name); f {
+	case "":
+		return FormatPNG, nil
+	case FormatPNG, FormatSVG, FormatPDF:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown plot format %q, must be one of: %s, %s, %s", name, FormatPNG, FormatSVG, FormatPDF)
+	}
+}
+
+// PlotFile returns file name of the plot in PlotFormat
+func PlotFile(name string) string {
+	return name + "." + string(PlotFormat)
+}
+
+// newCanvas creates canvas plots with legends beside them are drawn on in PlotFormat
+func newCanvas(w, h vg.Length) vg.CanvasWriterTo {
+	c, err := draw.NewFormattedCanvas(w, h, string(PlotFormat))
+	if err != nil {
+		log.Warnf("Can't create %s canvas, drawing PNG; error=%v", PlotFormat, err)
+		return vgimg.PngCanvas{Canvas: vgimg.New(w, h)}
+	}
+	return c
+}
+
+// SetReportRoot makes reports of the run generated in root instead of UserPath, so runners of one process can
+// keep their reports apart, empty root removes the override
+func SetReportRoot(runName, root string) {
+	if root == "" {
+		reportRoots.Delete(runName)
+		return
+	}
+	reportRoots.Store(runName, root)
+}
+
+// ReportRoot returns directory reports of the run are generated in, empty if it's under UserPath
+func ReportRoot(runName string) string {
+	if root, ok := reportRoots.Load(runName); ok {
+		return root.(string)
+	}
+	return ""
+}
+
 // GetReportPathDir constructs the report path and returns it
 func GetReportPathDir(reportName string) (string, error) {
-	var curUser string
-	if UserPath == "" {
-		var err error
-		curUser, err = os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("can't get User home group %v", err)
+	return GetReportPathDirIn(ReportRoot(reportName), reportName)
+}
+
+// GetReportPathDirIn constructs path of the report inside root, .cert-csi folder of UserPath is used if root is empty
+func GetReportPathDirIn(root, reportName string) (string, error) {
+	curUser := root
+	if root == "" {
+		if UserPath == "" {
+			var err error
+			curUser, err = os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("can't get User home group %v", err)
+			}
+		} else {
+			curUser = UserPath
 		}
-	} else {
-		curUser = UserPath
+		curUser = curUser + FolderPath
 	}
-	curUser = curUser + FolderPath
 	curUserPath, err := filepath.Abs(curUser)
 	if err != nil {
 		return "", fmt.Errorf("can't get abs path %v", err)
 	}
 
-	return filepath.Join(curUserPath, "/reports", reportName), nil
+	switch ReportLayout {
+	case LayoutFlat:
+		return filepath.Join(curUserPath, "/reports"), nil
+	case LayoutTimestamped:
+		return filepath.Join(curUserPath, "/reports", reportName+"-"+runTimestamp), nil
+	default:
+		return filepath.Join(curUserPath, "/reports", reportName), nil
+	}
 }
 
 // PlotStageMetricHistogram creates and saves a histogram of time distributions
@@ -133,7 +241,7 @@ func PlotStageMetricHistogram(tc collector.TestCaseMetrics, stage interface{}, r
 	filePath = fmt.Sprintf("%s/%s", filePath, tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)))
 
 	_ = os.MkdirAll(filePath, 0o750)
-	filePath = filepath.Join(filePath, fmt.Sprintf("%s.png", stage))
+	filePath = filepath.Join(filePath, PlotFile(fmt.Sprint(stage)))
 	if err := p.Save(4*vg.Inch, 4*vg.Inch, filePath); err != nil {
 		log.Errorf("Can't save the histogram; error=%v", err)
 		return nil, err
@@ -189,9 +297,9 @@ func PlotStageBoxPlot(tc collector.TestCaseMetrics, stage interface{}, reportNam
 	_ = os.MkdirAll(filePath, 0o750)
 
 	if isPvc {
-		filePath = filepath.Join(filePath, fmt.Sprintf("%s.png", stage.(collector.PVCStage)+"_boxplot"))
+		filePath = filepath.Join(filePath, PlotFile(fmt.Sprint(stage.(collector.PVCStage))+"_boxplot"))
 	} else {
-		filePath = filepath.Join(filePath, fmt.Sprintf("%s.png", stage.(collector.PodStage)+"_boxplot"))
+		filePath = filepath.Join(filePath, PlotFile(fmt.Sprint(stage.(collector.PodStage))+"_boxplot"))
 	}
 
 	if err := p.Save(4*vg.Inch, 4*vg.Inch, filePath); err != nil {
@@ -247,6 +355,10 @@ func PlotEntityOverTime(tc collector.TestCaseMetrics, reportName string) (*plot.
 			Y: float64(row.PvcTerminating),
 		})
 	}
+	// Rows are already bucketed by time, series are downsampled once more to the drawn limit
+	for _, xys := range []*plotter.XYs{&podsCreating, &podsReady, &podsTerminating, &pvcCreating, &pvcBound, &pvcTerminating} {
+		*xys = decimate(*xys, MaxPlotPoints)
+	}
 
 	p := plot.New()
 
@@ -255,6 +367,9 @@ func PlotEntityOverTime(tc collector.TestCaseMetrics, reportName string) (*plot.
 		return nil, errors.New("can't create new plot")
 	}
 	p.Title.Text = "EntityNumber over time"
+	if tc.TestCase.Rate > 0 {
+		p.Title.Text += fmt.Sprintf(" (paced at %g PVC/s)", tc.TestCase.Rate)
+	}
 	p.Y.Label.Text = "number"
 	p.X.Label.Text = "time"
 	// Draw a grid behind the data
@@ -389,13 +504,14 @@ func PlotEntityOverTime(tc collector.TestCaseMetrics, reportName string) (*plot.
 	l.Top = true
 
 	var k int
-	if len(pvcBound) >= 50 {
-		k = len(pvcBound) / 10
+	// Width follows downsampled series, so huge runs don't produce enormous images
+	if points := len(pvcBound); points >= 50 {
+		k = points / 10
 	} else {
 		k = 5
 	}
 
-	img := vgimg.New(vg.Length(k)*vg.Inch, 4*vg.Inch)
+	img := newCanvas(vg.Length(k)*vg.Inch, 4*vg.Inch)
 
 	dc := draw.New(img)
 	// Calculate the width of the legend.
@@ -411,7 +527,7 @@ func PlotEntityOverTime(tc collector.TestCaseMetrics, reportName string) (*plot.
 
 	_ = os.MkdirAll(filePath, 0o750)
 
-	fileName := fmt.Sprintf("%s.png", "EntityNumberOverTime")
+	fileName := PlotFile("EntityNumberOverTime")
 	filePath = filepath.Join(filePath, fileName)
 
 	w, err := os.Create(filepath.Clean(filePath))
@@ -420,8 +536,375 @@ func PlotEntityOverTime(tc collector.TestCaseMetrics, reportName string) (*plot.
 		return nil, err
 	}
 
-	png := vgimg.PngCanvas{Canvas: img}
-	if _, err = png.WriteTo(w); err != nil {
+	if _, err = img.WriteTo(w); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// EventRateSeries are event types drawn on events per second chart, in drawing order
+var EventRateSeries = []struct {
+	Name string
+	Type store.EventTypeEnum
+}{
+	{"PVC adds", store.PvcAdded},
+	{"PVC binds", store.PvcBound},
+	{"PVC attaches", store.PvcAttachEnded},
+	{"PVC deletes", store.PvcDeletingEnded},
+}
+
+// PlotEventsPerSecond creates and saves a chart of events processed per second, showing load profile of test case
+func PlotEventsPerSecond(tc collector.TestCaseMetrics, reportName string) (*plot.Plot, error) {
+	var first, last int64
+	for _, series := range EventRateSeries {
+		for second := range tc.EventsPerSecond[series.Type] {
+			if first == 0 || second < first {
+				first = second
+			}
+			if second > last {
+				last = second
+			}
+		}
+	}
+	if first == 0 {
+		log.Warnf("no events provided")
+		return nil, fmt.Errorf("no events provided")
+	}
+
+	p := plot.New()
+	if p == nil {
+		log.Error("can't create a new plot")
+		return nil, errors.New("can't create new plot")
+	}
+	p.Title.Text = "Events per second"
+	p.X.Label.Text = "time"
+	p.Y.Label.Text = "events"
+	p.Add(plotter.NewGrid())
+
+	var lines []interface{}
+	for _, series := range EventRateSeries {
+		// Seconds without events are drawn as zero, so spikes stand out
+		var xys plotter.XYs
+		for second := first; second <= last; second++ {
+			xys = append(xys, plotter.XY{
+				X: float64(second - first),
+				Y: float64(tc.EventsPerSecond[series.Type][second]),
+			})
+		}
+		lines = append(lines, series.Name, decimate(xys, MaxPlotPoints))
+	}
+	if tc.TestCase.Rate > 0 {
+		// Configured pacing rate is drawn as a flat line, so actual arrival rate can be compared against it
+		lines = append(lines, "Configured PVC rate", plotter.XYs{
+			{X: 0, Y: tc.TestCase.Rate},
+			{X: float64(last - first), Y: tc.TestCase.Rate},
+		})
+	}
+	if err := plotutil.AddLines(p, lines...); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	p.Legend.Top = true
+
+	filePath, _ := GetReportPathDir(reportName)
+	filePath = fmt.Sprintf("%s/%s", filePath, tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)))
+
+	_ = os.MkdirAll(filePath, 0o750)
+
+	filePath = filepath.Join(filePath, PlotFile("EventsPerSecond"))
+
+	// Save the plot to a PNG file.
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, filePath); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// PlotRampLatency creates and saves a chart of bind latency of each stage of ramp profile against its concurrency
+func PlotRampLatency(tc collector.TestCaseMetrics, reportName string) (*plot.Plot, error) {
+	if len(tc.RampMetrics) == 0 {
+		return nil, fmt.Errorf("no ramp stages provided")
+	}
+
+	var avgBind, maxBind, stageDuration plotter.XYs
+	for _, m := range tc.RampMetrics {
+		x := float64(m.Stage.Concurrency)
+		avgBind = append(avgBind, plotter.XY{X: x, Y: m.PVCBind.Avg.Seconds()})
+		maxBind = append(maxBind, plotter.XY{X: x, Y: m.PVCBind.Max.Seconds()})
+		stageDuration = append(stageDuration, plotter.XY{X: x, Y: m.Stage.Duration().Seconds()})
+	}
+
+	p := plot.New()
+	if p == nil {
+		log.Error("can't create a new plot")
+		return nil, errors.New("can't create new plot")
+	}
+	p.Title.Text = "Latency vs concurrency"
+	p.X.Label.Text = "concurrent volumes"
+	p.Y.Label.Text = "seconds"
+	p.Add(plotter.NewGrid())
+
+	if err := plotutil.AddLinePoints(p,
+		"Avg PVC bind", avgBind,
+		"Max PVC bind", maxBind,
+		"Stage duration", stageDuration); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	filePath, _ := GetReportPathDir(reportName)
+	filePath = fmt.Sprintf("%s/%s", filePath, tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)))
+
+	_ = os.MkdirAll(filePath, 0o750)
+
+	filePath = filepath.Join(filePath, PlotFile("RampLatency"))
+
+	// Save the plot to a PNG file.
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, filePath); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// PlotSLOBurnDown creates and saves a chart of rolling share of operations meeting each SLO,
+// points where the share dropped below target are marked red
+func PlotSLOBurnDown(tc collector.TestCaseMetrics, slos []collector.SLO, window time.Duration, reportName string) (*plot.Plot, error) {
+	p := plot.New()
+	if p == nil {
+		log.Error("can't create a new plot")
+		return nil, errors.New("can't create new plot")
+	}
+	p.Title.Text = fmt.Sprintf("Operations meeting SLO (rolling %s)", window)
+	p.X.Label.Text = "time"
+	p.Y.Label.Text = "%"
+	p.Y.Min = 0
+	p.Y.Max = 100
+	p.Add(plotter.NewGrid())
+
+	var lines []interface{}
+	var degraded plotter.XYs
+	for _, slo := range slos {
+		points := collector.SLOBurnDown(tc, slo, window)
+		if len(points) == 0 {
+			continue
+		}
+		var xys plotter.XYs
+		for _, point := range points {
+			xy := plotter.XY{X: point.Time.Sub(tc.TestCase.StartTimestamp).Seconds(), Y: point.Percent()}
+			xys = append(xys, xy)
+			if point.Percent() < slo.Target {
+				degraded = append(degraded, xy)
+			}
+		}
+		lines = append(lines, slo.String(), decimate(xys, MaxPlotPoints))
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no operations of SLO stages provided")
+	}
+	if err := plotutil.AddLines(p, lines...); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if len(degraded) != 0 {
+		s, err := plotter.NewScatter(decimate(degraded, MaxPlotPoints))
+		if err != nil {
+			return nil, err
+		}
+		s.GlyphStyle.Color = color.RGBA{R: 255, A: 255}
+		s.GlyphStyle.Shape = draw.CircleGlyph{}
+		p.Add(s)
+		p.Legend.Add("below target", s)
+	}
+	p.Legend.Top = false
+	p.Legend.Left = true
+
+	filePath, _ := GetReportPathDir(reportName)
+	filePath = fmt.Sprintf("%s/%s", filePath, tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)))
+
+	_ = os.MkdirAll(filePath, 0o750)
+
+	filePath = filepath.Join(filePath, PlotFile("SLOBurnDown"))
+
+	// Save the plot to a PNG file.
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, filePath); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// PlotStageTimeout creates and saves a plot of stage latencies of every entity against the stage timeout,
+// log scale keeps both fast stages and the timeout readable
+func PlotStageTimeout(tc collector.TestCaseMetrics, timeout time.Duration, reportName string) (*plot.Plot, error) {
+	p := plot.New()
+	if p == nil {
+		log.Error("can't create a new plot")
+		return nil, errors.New("can't create new plot")
+	}
+	p.Title.Text = fmt.Sprintf("Stage latency vs timeout (%s)", timeout)
+	p.X.Label.Text = "time"
+	p.Y.Label.Text = "seconds"
+	p.Y.Scale = plot.LogScale{}
+	p.Y.Tick.Marker = plot.LogTicks{}
+	p.Add(plotter.NewGrid())
+
+	stages := make(map[string]plotter.XYs)
+	add := func(stage string, added time.Time, d time.Duration) {
+		// Log scale can't show stages which never ended
+		if d <= 0 || added.IsZero() {
+			return
+		}
+		stages[stage] = append(stages[stage], plotter.XY{X: added.Sub(tc.TestCase.StartTimestamp).Seconds(), Y: d.Seconds()})
+	}
+	for _, pvc := range tc.PVCs {
+		for stage, d := range pvc.Metrics {
+			add(string(stage), pvc.Added, d)
+		}
+	}
+	for _, pod := range tc.Pods {
+		for stage, d := range pod.Metrics {
+			add(string(stage), pod.Added, d)
+		}
+	}
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("no stage metrics provided")
+	}
+
+	names := make([]string, 0, len(stages))
+	for name := range stages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []interface{}
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, name := range names {
+		xys := stages[name]
+		sort.SliceStable(xys, func(i, j int) bool { return xys[i].X < xys[j].X })
+		xys = decimate(xys, MaxPlotPoints)
+		for _, xy := range xys {
+			minX = math.Min(minX, xy.X)
+			maxX = math.Max(maxX, xy.X)
+		}
+		lines = append(lines, name, xys)
+	}
+	if err := plotutil.AddLinePoints(p, lines...); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	limit, err := plotter.NewLine(plotter.XYs{{X: minX, Y: timeout.Seconds()}, {X: maxX, Y: timeout.Seconds()}})
+	if err != nil {
+		return nil, err
+	}
+	limit.LineStyle.Color = color.RGBA{R: 255, A: 255}
+	limit.LineStyle.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+	p.Add(limit)
+	p.Legend.Add("timeout", limit)
+	p.Legend.Top = false
+	p.Legend.Left = true
+
+	filePath, _ := GetReportPathDir(reportName)
+	filePath = fmt.Sprintf("%s/%s", filePath, tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)))
+
+	_ = os.MkdirAll(filePath, 0o750)
+
+	filePath = filepath.Join(filePath, PlotFile("StageTimeout"))
+
+	// Save the plot to a PNG file.
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, filePath); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// SnapshotReadinessBins is number of bins of snapshot readiness histogram
+const SnapshotReadinessBins = 10
+
+// PlotSnapshotReadiness creates and saves a histogram of snapshot readiness times, snapshots which weren't ready
+// when they were given up on are stacked in red at the time they were waited for, as their readiness time is only
+// known to be longer
+func PlotSnapshotReadiness(tc collector.TestCaseMetrics, reportName string) (*plot.Plot, error) {
+	sr := tc.SnapshotReadiness
+	if sr == nil || sr.Samples() == 0 {
+		return nil, fmt.Errorf("no snapshot readiness provided")
+	}
+
+	var longest time.Duration
+	for _, d := range sr.Ready {
+		if d > longest {
+			longest = d
+		}
+	}
+	for _, c := range sr.Censored {
+		if c.Waited > longest {
+			longest = c.Waited
+		}
+	}
+	width := longest/SnapshotReadinessBins + 1
+	bin := func(d time.Duration) int {
+		if i := int(d / width); i < SnapshotReadinessBins {
+			return i
+		}
+		return SnapshotReadinessBins - 1
+	}
+	ready := make(plotter.Values, SnapshotReadinessBins)
+	censored := make(plotter.Values, SnapshotReadinessBins)
+	for _, d := range sr.Ready {
+		ready[bin(d)]++
+	}
+	for _, c := range sr.Censored {
+		censored[bin(c.Waited)]++
+	}
+	labels := make([]string, SnapshotReadinessBins)
+	for i := range labels {
+		labels[i] = (time.Duration(i) * width).Round(time.Second).String()
+	}
+
+	p := plot.New()
+	if p == nil {
+		log.Error("can't create a new plot")
+		return nil, errors.New("can't create new plot")
+	}
+	p.Title.Text = fmt.Sprintf("Snapshot readiness. Ready=%d, censored=%d", len(sr.Ready), len(sr.Censored))
+	p.X.Label.Text = "time to ready"
+	p.Y.Label.Text = "quantity"
+	p.NominalX(labels...)
+
+	readyBars, err := plotter.NewBarChart(ready, vg.Points(20))
+	if err != nil {
+		return nil, err
+	}
+	readyBars.Color = plotutil.Color(0)
+	censoredBars, err := plotter.NewBarChart(censored, vg.Points(20))
+	if err != nil {
+		return nil, err
+	}
+	censoredBars.Color = color.RGBA{R: 255, A: 255}
+	censoredBars.StackOn(readyBars)
+	p.Add(readyBars, censoredBars)
+	p.Legend.Add("ready", readyBars)
+	p.Legend.Add("not ready when given up on", censoredBars)
+	p.Legend.Top = true
+
+	filePath, _ := GetReportPathDir(reportName)
+	filePath = fmt.Sprintf("%s/%s", filePath, tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)))
+
+	_ = os.MkdirAll(filePath, 0o750)
+
+	filePath = filepath.Join(filePath, PlotFile("SnapshotReadiness"))
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, filePath); err != nil {
 		log.Error(err)
 		return nil, err
 	}
@@ -457,20 +940,22 @@ func PlotMinMaxEntityOverTime(tcMetrics []collector.TestCaseMetrics, reportName
 		return fmt.Errorf("no EntityMetrics provided")
 	}
 
-	min := math.MaxInt32
-	max := 0
+	// Rows are bucketed by time, so test cases are compared by how long they were observed rather than by number of rows
+	min := time.Duration(math.MaxInt64)
+	max := time.Duration(-1)
 
 	for _, tcm := range tcMetrics {
 		if tcm.EntityNumberMetrics == nil || len(tcm.EntityNumberMetrics) == 0 {
 			log.Errorf("MinMaxEoT: No EntityNumberMetrics provided in %s%d", tcm.TestCase.Name, tcm.TestCase.ID)
 			continue
 		}
-		if len(tcm.EntityNumberMetrics) > max {
-			max = len(tcm.EntityNumberMetrics)
+		duration := tcm.EntityNumberMetrics[len(tcm.EntityNumberMetrics)-1].Timestamp.Sub(tcm.EntityNumberMetrics[0].Timestamp)
+		if duration > max {
+			max = duration
 			firstTime := tcm.EntityNumberMetrics[0].Timestamp
 			maxPodsCreating, maxPodsReady, maxPodsTerminating, maxPvcsCreating, maxPvcsBound = getMetrics(tcm, firstTime)
-		} else if len(tcm.EntityNumberMetrics) < min {
-			min = len(tcm.EntityNumberMetrics)
+		} else if duration < min {
+			min = duration
 			firstTime := tcm.EntityNumberMetrics[0].Timestamp
 			minPodsCreating, minPodsReady, minPodsTerminating, minPvcsCreating, minPvcsBound = getMetrics(tcm, firstTime)
 		}
@@ -501,8 +986,8 @@ func PlotMinMaxEntityOverTime(tcMetrics []collector.TestCaseMetrics, reportName
 func PlotResourceUsageOverTime(tcMetrics []collector.TestCaseMetrics, reportName string) error {
 	n := 1
 
-	memMetrics := make(map[string]plotter.XYs)
-	cpuMetrics := make(map[string]plotter.XYs)
+	memSeries := make(map[string]plotter.XYs)
+	cpuSeries := make(map[string]plotter.XYs)
 
 	var firstTime time.Time
 	if len(tcMetrics) == 0 {
@@ -520,23 +1005,31 @@ func PlotResourceUsageOverTime(tcMetrics []collector.TestCaseMetrics, reportName
 			name := fmt.Sprintf("[%s]:%s", row.PodName, row.ContainerName)
 
 			X := row.Timestamp.Sub(firstTime).Seconds()
-			if _, ok := memMetrics[name]; !ok {
-				memMetrics[name] = make(plotter.XYs, n)
+			if _, ok := memSeries[name]; !ok {
+				memSeries[name] = make(plotter.XYs, n)
 			}
-			memMetrics[name] = append(memMetrics[name], plotter.XY{
+			memSeries[name] = append(memSeries[name], plotter.XY{
 				X: X,
 				Y: float64(row.Mem),
 			})
 
-			if _, ok := cpuMetrics[name]; !ok {
-				cpuMetrics[name] = make(plotter.XYs, n)
+			if _, ok := cpuSeries[name]; !ok {
+				cpuSeries[name] = make(plotter.XYs, n)
 			}
-			cpuMetrics[name] = append(cpuMetrics[name], plotter.XY{
+			cpuSeries[name] = append(cpuSeries[name], plotter.XY{
 				X: X,
 				Y: float64(row.CPU),
 			})
 		}
 	}
+	memMetrics := make(map[string]plotter.XYs, len(memSeries))
+	for name, s := range memSeries {
+		memMetrics[name] = decimate(s, MaxPlotPoints)
+	}
+	cpuMetrics := make(map[string]plotter.XYs, len(cpuSeries))
+	for name, s := range cpuSeries {
+		cpuMetrics[name] = decimate(s, MaxPlotPoints)
+	}
 	memMetrics = sortGraphsByKey(memMetrics)
 	cpuMetrics = sortGraphsByKey(cpuMetrics)
 
@@ -597,7 +1090,7 @@ func PlotIterationTimes(tcMetrics []collector.TestCaseMetrics, reportName string
 
 	_ = os.MkdirAll(filePath, 0o750)
 
-	filePath = filepath.Join(filePath, "IterationTimes.png")
+	filePath = filepath.Join(filePath, PlotFile("IterationTimes"))
 
 	// Save the plot to a PNG file.
 	if err := p.Save(6*vg.Inch, 4*vg.Inch, filePath); err != nil {
@@ -664,7 +1157,7 @@ func PlotAvgStageTimeOverIterations(tcMetrics []collector.TestCaseMetrics, repor
 
 		filePath, _ := GetReportPathDir(reportName)
 		_ = os.MkdirAll(filePath, 0o750)
-		fileName := fmt.Sprintf("%sOverIterations.png", name)
+		fileName := PlotFile(name + "OverIterations")
 		filePath = filepath.Join(filePath, fileName)
 
 		if err := p.Save(8*vg.Inch, 4*vg.Inch, filePath); err != nil {
@@ -712,7 +1205,7 @@ func plotMemoryOrCPU(metrics map[string]plotter.XYs, reportName string, name str
 		k = 10
 	}
 
-	img := vgimg.New(vg.Length(k)*vg.Inch, 4*vg.Inch)
+	img := newCanvas(vg.Length(k)*vg.Inch, 4*vg.Inch)
 	dc := draw.New(img)
 	// Calculate the width of the legend.
 	r := l.Rectangle(dc)
@@ -726,15 +1219,14 @@ func plotMemoryOrCPU(metrics map[string]plotter.XYs, reportName string, name str
 
 	filePath, _ := GetReportPathDir(reportName)
 	_ = os.MkdirAll(filePath, 0o750)
-	fileName := fmt.Sprintf("%s.png", name)
+	fileName := PlotFile(name)
 	filePath = filepath.Join(filePath, fileName)
 	w, err := os.Create(filepath.Clean(filePath))
 	if err != nil {
 		log.Error(err)
 		return err
 	}
-	png := vgimg.PngCanvas{Canvas: img}
-	if _, err = png.WriteTo(w); err != nil {
+	if _, err = img.WriteTo(w); err != nil {
 		log.Error(err)
 		return err
 	}
@@ -795,7 +1287,7 @@ func plotMinMax(minMetrics plotter.XYs, maxMetrics plotter.XYs, reportName strin
 	l.Add("Min Line", minLine)
 	l.Add("Max Line", maxLine)
 	l.Top = true
-	img := vgimg.New(5*vg.Inch, 3*vg.Inch)
+	img := newCanvas(5*vg.Inch, 3*vg.Inch)
 	dc := draw.New(img)
 	// Calculate the width of the legend.
 	r := l.Rectangle(dc)
@@ -811,15 +1303,14 @@ func plotMinMax(minMetrics plotter.XYs, maxMetrics plotter.XYs, reportName strin
 
 	_ = os.MkdirAll(filePath, 0o750)
 
-	fileName := fmt.Sprintf("%s%s.png", name, "OverTime")
+	fileName := PlotFile(name + "OverTime")
 	filePath = filepath.Join(filePath, fileName)
 	w, err := os.Create(filepath.Clean(filePath))
 	if err != nil {
 		log.Error(err)
 		return err
 	}
-	png := vgimg.PngCanvas{Canvas: img}
-	if _, err = png.WriteTo(w); err != nil {
+	if _, err = img.WriteTo(w); err != nil {
 		log.Error(err)
 		return err
 	}
@@ -863,6 +1354,10 @@ func getMetrics(tcm collector.TestCaseMetrics, firstTime time.Time) (plotter.XYs
 		})
 	}
 
+	// Rows are already bucketed by time, series are downsampled once more to the drawn limit
+	for _, xys := range []*plotter.XYs{&podsCreating, &podsReady, &podsTerminating, &pvcCreating, &pvcBound} {
+		*xys = decimate(*xys, MaxPlotPoints)
+	}
 	return podsCreating, podsReady, podsTerminating, pvcCreating, pvcBound
 }
 
@@ -28,6 +28,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	"gonum.org/v1/plot"
+	gonumplotter "gonum.org/v1/plot/plotter"
 )
 
 type PlotterTestSuite struct {
@@ -588,6 +589,106 @@ func (suite *PlotterTestSuite) TestPlotEntityOverTime() {
 	}
 }
 
+func (suite *PlotterTestSuite) TestPlotEventsPerSecond() {
+	p, err := PlotEventsPerSecond(collector.TestCaseMetrics{}, "")
+	suite.Error(err)
+	suite.Nil(p)
+
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{ID: 0, Name: "ChurnSuite"},
+		EventsPerSecond: map[store.EventTypeEnum]map[int64]int{
+			store.PvcAdded: {100: 5, 103: 2},
+			store.PvcBound: {101: 7},
+		},
+	}
+	p, err = PlotEventsPerSecond(tc, "test-report")
+	suite.NoError(err)
+	suite.FileExists(suite.filepath + "/reports/test-report/ChurnSuite0/EventsPerSecond.png")
+	suite.Equal("Events per second", p.Title.Text)
+	suite.Equal(float64(3), p.X.Max)
+	suite.Equal(float64(7), p.Y.Max)
+}
+
+func (suite *PlotterTestSuite) TestPlotRampLatency() {
+	p, err := PlotRampLatency(collector.TestCaseMetrics{}, "")
+	suite.Error(err)
+	suite.Nil(p)
+
+	start := time.Now()
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{ID: 0, Name: "VolumeCreationSuite"},
+		RampMetrics: []collector.RampStageMetrics{
+			{Stage: store.RampStage{Concurrency: 10, StartTimestamp: start, EndTimestamp: start.Add(5 * time.Second)}, Volumes: 10, PVCBind: collector.DurationOfStage{Avg: 2 * time.Second, Max: 3 * time.Second}},
+			{Stage: store.RampStage{Concurrency: 50, StartTimestamp: start, EndTimestamp: start.Add(20 * time.Second)}, Volumes: 50, PVCBind: collector.DurationOfStage{Avg: 8 * time.Second, Max: 15 * time.Second}},
+		},
+	}
+	p, err = PlotRampLatency(tc, "test-report")
+	suite.NoError(err)
+	suite.FileExists(suite.filepath + "/reports/test-report/VolumeCreationSuite0/RampLatency.png")
+	suite.Equal(float64(50), p.X.Max)
+	suite.Equal(float64(20), p.Y.Max)
+}
+
+func (suite *PlotterTestSuite) TestPlotSLOBurnDown() {
+	slos := []collector.SLO{{Stage: "PVCBind", Threshold: time.Second, Target: 90}}
+	p, err := PlotSLOBurnDown(collector.TestCaseMetrics{}, slos, time.Minute, "")
+	suite.Error(err)
+	suite.Nil(p)
+
+	start := time.Now()
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{ID: 0, Name: "VolumeCreationSuite", StartTimestamp: start},
+		PVCs: []collector.PVCMetrics{
+			{Added: start.Add(time.Second), Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: 500 * time.Millisecond}},
+			{Added: start.Add(20 * time.Second), Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: 5 * time.Second}},
+		},
+	}
+	p, err = PlotSLOBurnDown(tc, slos, time.Minute, "test-report")
+	suite.NoError(err)
+	suite.FileExists(suite.filepath + "/reports/test-report/VolumeCreationSuite0/SLOBurnDown.png")
+	suite.Equal(float64(100), p.Y.Max)
+}
+
+func (suite *PlotterTestSuite) TestPlotStageTimeout() {
+	p, err := PlotStageTimeout(collector.TestCaseMetrics{}, time.Minute, "")
+	suite.Error(err)
+	suite.Nil(p)
+
+	start := time.Now()
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{ID: 0, Name: "VolumeCreationSuite", StartTimestamp: start},
+		PVCs: []collector.PVCMetrics{
+			{Added: start.Add(time.Second), Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: 500 * time.Millisecond}},
+			{Added: start.Add(20 * time.Second), Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: 5 * time.Second, collector.PVCDeletion: -1}},
+		},
+		Pods: []collector.PodMetrics{
+			{Added: start.Add(2 * time.Second), Metrics: map[collector.PodStage]time.Duration{collector.PodCreation: 10 * time.Second}},
+		},
+	}
+	p, err = PlotStageTimeout(tc, time.Minute, "test-report")
+	suite.NoError(err)
+	suite.FileExists(suite.filepath + "/reports/test-report/VolumeCreationSuite0/StageTimeout.png")
+	suite.Equal("Stage latency vs timeout (1m0s)", p.Title.Text)
+}
+
+func (suite *PlotterTestSuite) TestPlotSnapshotReadiness() {
+	p, err := PlotSnapshotReadiness(collector.TestCaseMetrics{}, "")
+	suite.Error(err)
+	suite.Nil(p)
+
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{ID: 0, Name: "SnapSuite"},
+		SnapshotReadiness: &collector.SnapshotReadiness{
+			Ready:    []time.Duration{2 * time.Second, 3 * time.Second, 8 * time.Second},
+			Censored: []collector.CensoredSnapshot{{Name: "snap-stuck", Waited: 5 * time.Minute, TimedOut: true}},
+		},
+	}
+	p, err = PlotSnapshotReadiness(tc, "test-report")
+	suite.NoError(err)
+	suite.FileExists(suite.filepath + "/reports/test-report/SnapSuite0/SnapshotReadiness.png")
+	suite.Equal("Snapshot readiness. Ready=3, censored=1", p.Title.Text)
+}
+
 func (suite *PlotterTestSuite) TestPlotMinMaxEntityOverTime() {
 	type args struct {
 		tc         []collector.TestCaseMetrics
@@ -1307,6 +1408,123 @@ func (suite *PlotterTestSuite) TestPlotAvgStageTimeOverIterations() {
 	}
 }
 
+func (suite *PlotterTestSuite) TestReportLayout() {
+	defer func() { ReportLayout = LayoutPerRun }()
+
+	layout, err := ParseLayout("")
+	suite.NoError(err)
+	suite.Equal(LayoutPerRun, layout)
+	_, err = ParseLayout("nested")
+	suite.Error(err)
+
+	path, err := GetReportPathDir("run")
+	suite.NoError(err)
+	suite.Equal(filepath.Join(suite.filepath, "reports", "run"), path)
+
+	ReportLayout = LayoutFlat
+	path, err = GetReportPathDir("run")
+	suite.NoError(err)
+	suite.Equal(filepath.Join(suite.filepath, "reports"), path)
+
+	ReportLayout = LayoutTimestamped
+	path, err = GetReportPathDir("run")
+	suite.NoError(err)
+	suite.Equal(filepath.Join(suite.filepath, "reports", "run-"+runTimestamp), path)
+}
+
+func (suite *PlotterTestSuite) TestReportRoot() {
+	root := suite.T().TempDir()
+	SetReportRoot("run", root)
+	suite.Equal(root, ReportRoot("run"))
+	path, err := GetReportPathDir("run")
+	suite.NoError(err)
+	suite.Equal(filepath.Join(root, "reports", "run"), path)
+	path, err = GetReportPathDir("other-run")
+	suite.NoError(err)
+	suite.Equal(filepath.Join(suite.filepath, "reports", "other-run"), path)
+
+	SetReportRoot("run", "")
+	suite.Empty(ReportRoot("run"))
+	path, err = GetReportPathDir("run")
+	suite.NoError(err)
+	suite.Equal(filepath.Join(suite.filepath, "reports", "run"), path)
+}
+
+func (suite *PlotterTestSuite) TestPlotFormat() {
+	defer func() { PlotFormat = FormatPNG }()
+
+	format, err := ParseFormat("")
+	suite.NoError(err)
+	suite.Equal(FormatPNG, format)
+	_, err = ParseFormat("jpeg")
+	suite.Error(err)
+
+	for _, format := range []Format{FormatSVG, FormatPDF} {
+		PlotFormat = format
+		tc := collector.TestCaseMetrics{
+			TestCase: store.TestCase{ID: 0, Name: "ChurnSuite"},
+			EventsPerSecond: map[store.EventTypeEnum]map[int64]int{
+				store.PvcAdded: {100: 5, 103: 2},
+			},
+		}
+		_, err = PlotEventsPerSecond(tc, "test-report")
+		suite.NoError(err)
+		suite.FileExists(suite.filepath + "/reports/test-report/ChurnSuite0/EventsPerSecond." + string(format))
+	}
+}
+
+func (suite *PlotterTestSuite) TestDecimate() {
+	var xys gonumplotter.XYs
+	for i := 0; i < 10000; i++ {
+		xys = append(xys, gonumplotter.XY{X: float64(i), Y: float64(i % 100)})
+	}
+	xys[5000].Y = 1000
+
+	suite.Equal(xys, decimate(xys, 0))
+	suite.Equal(xys[:10], decimate(xys[:10], 100))
+
+	decimated := decimate(xys, 100)
+	suite.LessOrEqual(len(decimated), 100)
+	suite.Contains(decimated, gonumplotter.XY{X: 5000, Y: 1000})
+	for i := 1; i < len(decimated); i++ {
+		suite.Less(decimated[i-1].X, decimated[i].X)
+	}
+
+	// Buckets have the same width, so sparse tail of series isn't merged into dense head
+	var uneven gonumplotter.XYs
+	for i := 0; i < 10000; i++ {
+		uneven = append(uneven, gonumplotter.XY{X: float64(i) / 10000, Y: float64(i % 7)})
+	}
+	for i := 10; i < 30; i++ {
+		uneven = append(uneven, gonumplotter.XY{X: float64(i), Y: float64(i)})
+	}
+	decimated = decimate(uneven, 100)
+	suite.LessOrEqual(len(decimated), 100)
+	suite.Equal(uneven[len(uneven)-20:], decimated[len(decimated)-20:])
+}
+
+func (suite *PlotterTestSuite) TestPlotEntityOverTimeDecimated() {
+	defer func(max int) { MaxPlotPoints = max }(MaxPlotPoints)
+	MaxPlotPoints = 50
+
+	var metrics []store.NumberEntities
+	for i := 0; i < 5000; i++ {
+		metrics = append(metrics, store.NumberEntities{
+			Timestamp:   suite.startTime.Add(time.Duration(i) * time.Second),
+			PvcCreating: i % 7,
+			PodsReady:   i % 11,
+		})
+	}
+	tc := collector.TestCaseMetrics{
+		TestCase:            store.TestCase{ID: 99, Name: "decimated"},
+		EntityNumberMetrics: metrics,
+	}
+	p, err := PlotEntityOverTime(tc, "test-report")
+	suite.NoError(err)
+	suite.NotNil(p)
+	suite.FileExists(suite.filepath + "/reports/test-report/decimated99/EntityNumberOverTime.png")
+}
+
 func TestPlotterTestSuite(t *testing.T) {
 	suite.Run(t, new(PlotterTestSuite))
 }
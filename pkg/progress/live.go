@@ -0,0 +1,194 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	// LiveRedrawInterval is how often live table is redrawn in place on terminal
+	LiveRedrawInterval = time.Second
+	// LiveLineInterval is how often a line per running suite is printed when output isn't a terminal
+	LiveLineInterval = 30 * time.Second
+)
+
+// Live shows rolling statistics of running suites: a table redrawn in place on terminal, plain periodic lines otherwise
+type Live struct {
+	tracker *Tracker
+	out     io.Writer
+	tty     bool
+
+	mutex sync.Mutex
+	// drawn is number of lines of the table currently on terminal
+	drawn int
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewLive creates a Live writing to out, table is drawn in place only if out is a terminal
+func NewLive(tracker *Tracker, out io.Writer) *Live {
+	tty := false
+	if f, ok := out.(*os.File); ok {
+		tty = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+	return &Live{tracker: tracker, out: out, tty: tty}
+}
+
+// Start starts updating output in background until Stop is called
+func (l *Live) Start() {
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	interval := LiveLineInterval
+	if l.tty {
+		interval = LiveRedrawInterval
+	}
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				l.update()
+			}
+		}
+	}()
+}
+
+// Stop stops updating output, last table drawn on terminal is left in place
+func (l *Live) Stop() {
+	if l.stop == nil {
+		return
+	}
+	close(l.stop)
+	<-l.done
+	l.stop = nil
+}
+
+// Writer returns writer for logs, on terminal table is erased before each write and drawn again below it
+func (l *Live) Writer() io.Writer {
+	return liveWriter{l}
+}
+
+type liveWriter struct {
+	live *Live
+}
+
+func (w liveWriter) Write(p []byte) (int, error) {
+	l := w.live
+	if !l.tty {
+		return l.out.Write(p)
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.erase()
+	n, err := l.out.Write(p)
+	l.draw()
+	return n, err
+}
+
+func (l *Live) update() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.tty {
+		l.erase()
+		l.draw()
+		return
+	}
+	status := l.tracker.Status()
+	for _, s := range status.Running {
+		_, _ = fmt.Fprintln(l.out, suiteLine(s))
+	}
+}
+
+// erase moves cursor to the first line of the table and clears everything below
+func (l *Live) erase() {
+	if l.drawn == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(l.out, "\033[%dA\033[J", l.drawn)
+	l.drawn = 0
+}
+
+func (l *Live) draw() {
+	table := renderTable(l.tracker.Status())
+	_, _ = io.WriteString(l.out, table)
+	l.drawn = strings.Count(table, "\n")
+}
+
+// renderTable formats run totals and a row per running suite
+func renderTable(status Status) string {
+	var b bytes.Buffer
+	iterations := "∞"
+	if status.Iterations >= 0 {
+		iterations = fmt.Sprint(status.Iterations)
+	}
+	fmt.Fprintf(&b, "%s iteration %d/%s, elapsed %s, %s, %s\n", color.CyanString("cert-csi"),
+		status.Iteration, iterations, time.Since(status.Started).Round(time.Second),
+		color.GreenString("%d succeeded", status.Succeeded), failedString(status.Failed))
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SUITE\tSTORAGE CLASS\tELAPSED\tBOUND\tATTACHED\tPODS READY\tBIND P95\tATTACH P95")
+	for _, s := range status.Running {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\n", s.Name, s.StorageClass,
+			time.Since(s.Started).Round(time.Second), s.Bound, s.Attached, podsReady(s),
+			latencyString(s.BindP95), latencyString(s.AttachP95))
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
+// suiteLine formats statistics of running suite as a single line
+func suiteLine(s SuiteStatus) string {
+	return fmt.Sprintf("%s %s [%s]: elapsed %s, bound %d, attached %d, pods ready %d, bind p95 %s, attach p95 %s",
+		time.Now().Format("2006-01-02 15:04:05"), s.Name, s.StorageClass, time.Since(s.Started).Round(time.Second),
+		s.Bound, s.Attached, podsReady(s), latencyString(s.BindP95), latencyString(s.AttachP95))
+}
+
+func podsReady(s SuiteStatus) int {
+	if s.Entities == nil {
+		return 0
+	}
+	return s.Entities.PodsReady
+}
+
+func failedString(failed int) string {
+	if failed == 0 {
+		return "0 failed"
+	}
+	return color.RedString("%d failed", failed)
+}
+
+func latencyString(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(time.Millisecond).String()
+}
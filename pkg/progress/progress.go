@@ -0,0 +1,237 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package progress keeps live state of a run and serves it as JSON for external dashboards
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// SuiteStatus contains state of a running suite
+type SuiteStatus struct {
+	Name         string                `json:"name"`
+	StorageClass string                `json:"storageClass"`
+	TestCaseID   int64                 `json:"testCaseId"`
+	Iteration    int                   `json:"iteration"`
+	Started      time.Time             `json:"started"`
+	Entities     *store.NumberEntities `json:"entities,omitempty"`
+	// Bound and Attached are numbers of volumes bound and attached so far, with 95th percentile of their latencies
+	Bound     int           `json:"bound"`
+	Attached  int           `json:"attached"`
+	BindP95   time.Duration `json:"bindP95"`
+	AttachP95 time.Duration `json:"attachP95"`
+
+	bind   []time.Duration
+	attach []time.Duration
+}
+
+// SuiteMetrics contains rolling duration metrics of finished suites with the same name
+type SuiteMetrics struct {
+	Runs         int           `json:"runs"`
+	Failures     int           `json:"failures"`
+	AvgDuration  time.Duration `json:"avgDuration"`
+	LastDuration time.Duration `json:"lastDuration"`
+}
+
+// Status is a snapshot of run progress
+type Status struct {
+	Started    time.Time `json:"started"`
+	Iteration  int       `json:"iteration"`
+	Iterations int       `json:"iterations"`
+	Succeeded  int       `json:"succeeded"`
+	Failed     int       `json:"failed"`
+	// Aborted is the reason run was aborted with, empty if it wasn't
+	Aborted string                  `json:"aborted,omitempty"`
+	Running []SuiteStatus           `json:"running"`
+	Metrics map[string]SuiteMetrics `json:"metrics"`
+}
+
+// Tracker accumulates run progress, all methods are safe to call on nil Tracker
+type Tracker struct {
+	mutex   sync.RWMutex
+	status  Status
+	running map[int64]*SuiteStatus
+	// started are times volumes were added or started attaching, keyed by test case and entity
+	started map[startKey]time.Time
+}
+
+type startKey struct {
+	tcID     int64
+	entityID int64
+	attach   bool
+}
+
+// NewTracker creates a Tracker, iterations is -1 for runs limited by duration
+func NewTracker(iterations int) *Tracker {
+	return &Tracker{
+		status: Status{
+			Started:    time.Now(),
+			Iterations: iterations,
+			Metrics:    make(map[string]SuiteMetrics),
+		},
+		running: make(map[int64]*SuiteStatus),
+		started: make(map[startKey]time.Time),
+	}
+}
+
+// SetIteration marks start of iteration
+func (t *Tracker) SetIteration(iter int) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.status.Iteration = iter
+}
+
+// SuiteStarted marks suite of test case as running
+func (t *Tracker) SuiteStarted(tc *store.TestCase, storageClass string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.running[tc.ID] = &SuiteStatus{
+		Name:         tc.Name,
+		StorageClass: storageClass,
+		TestCaseID:   tc.ID,
+		Iteration:    t.status.Iteration,
+		Started:      tc.StartTimestamp,
+	}
+}
+
+// SuiteFinished removes suite of test case from running ones and updates its metrics
+func (t *Tracker) SuiteFinished(tc *store.TestCase, success bool, elapsed time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.running, tc.ID)
+	for key := range t.started {
+		if key.tcID == tc.ID {
+			delete(t.started, key)
+		}
+	}
+
+	m := t.status.Metrics[tc.Name]
+	m.AvgDuration = (m.AvgDuration*time.Duration(m.Runs) + elapsed) / time.Duration(m.Runs+1)
+	m.LastDuration = elapsed
+	m.Runs++
+	if success {
+		t.status.Succeeded++
+	} else {
+		m.Failures++
+		t.status.Failed++
+	}
+	t.status.Metrics[tc.Name] = m
+}
+
+// UpdateEntities sets current number of entities in each state for test case
+func (t *Tracker) UpdateEntities(info *store.NumberEntities) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if s, ok := t.running[info.TcID]; ok {
+		entities := *info
+		s.Entities = &entities
+	}
+}
+
+// Observe updates bound and attached volumes of running suite with PVC and volume attachment events
+func (t *Tracker) Observe(e *store.Event) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s, ok := t.running[e.TcID]
+	if !ok {
+		return
+	}
+	switch e.Type {
+	case store.PvcAdded:
+		t.started[startKey{e.TcID, e.EntityID, false}] = e.Timestamp
+	case store.PvcAttachStarted:
+		t.started[startKey{e.TcID, e.EntityID, true}] = e.Timestamp
+	case store.PvcBound:
+		s.Bound++
+		key := startKey{e.TcID, e.EntityID, false}
+		if started, ok := t.started[key]; ok {
+			s.bind = append(s.bind, e.Timestamp.Sub(started))
+			s.BindP95 = p95(s.bind)
+			delete(t.started, key)
+		}
+	case store.PvcAttachEnded:
+		s.Attached++
+		key := startKey{e.TcID, e.EntityID, true}
+		if started, ok := t.started[key]; ok {
+			s.attach = append(s.attach, e.Timestamp.Sub(started))
+			s.AttachP95 = p95(s.attach)
+			delete(t.started, key)
+		}
+	}
+}
+
+// p95 returns 95th percentile of latencies using nearest rank
+func p95(latencies []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (len(sorted)*95 + 99) / 100
+	return sorted[rank-1]
+}
+
+// Aborted marks run as aborted with the reason
+func (t *Tracker) Aborted(reason string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.status.Aborted = reason
+}
+
+// Status returns a copy of current progress
+func (t *Tracker) Status() Status {
+	if t == nil {
+		return Status{}
+	}
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	status := t.status
+	status.Running = make([]SuiteStatus, 0, len(t.running))
+	for _, s := range t.running {
+		running := *s
+		running.bind, running.attach = nil, nil
+		status.Running = append(status.Running, running)
+	}
+	sort.Slice(status.Running, func(i, j int) bool {
+		return status.Running[i].TestCaseID < status.Running[j].TestCaseID
+	})
+	status.Metrics = make(map[string]SuiteMetrics, len(t.status.Metrics))
+	for name, m := range t.status.Metrics {
+		status.Metrics[name] = m
+	}
+	return status
+}
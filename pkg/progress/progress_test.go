@@ -0,0 +1,190 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(2)
+	tracker.SetIteration(1)
+
+	tc1 := &store.TestCase{ID: 1, Name: "VolumeIoSuite", StartTimestamp: time.Now()}
+	tc2 := &store.TestCase{ID: 2, Name: "ScalingSuite", StartTimestamp: time.Now()}
+	tracker.SuiteStarted(tc1, "sc")
+	tracker.SuiteStarted(tc2, "sc")
+	tracker.UpdateEntities(&store.NumberEntities{TcID: 1, PvcBound: 3})
+
+	status := tracker.Status()
+	assert.Equal(t, 1, status.Iteration)
+	assert.Equal(t, 2, status.Iterations)
+	assert.Len(t, status.Running, 2)
+	assert.Equal(t, 3, status.Running[0].Entities.PvcBound)
+	assert.Nil(t, status.Running[1].Entities)
+
+	tracker.SuiteFinished(tc1, true, 2*time.Second)
+	tracker.SuiteFinished(&store.TestCase{ID: 3, Name: "VolumeIoSuite"}, false, 4*time.Second)
+
+	status = tracker.Status()
+	assert.Len(t, status.Running, 1)
+	assert.Equal(t, 1, status.Succeeded)
+	assert.Equal(t, 1, status.Failed)
+	assert.Equal(t, SuiteMetrics{Runs: 2, Failures: 1, AvgDuration: 3 * time.Second, LastDuration: 4 * time.Second}, status.Metrics["VolumeIoSuite"])
+
+	var nilTracker *Tracker
+	nilTracker.SuiteStarted(tc1, "sc")
+	assert.Empty(t, nilTracker.Status().Running)
+}
+
+func TestServer(t *testing.T) {
+	tracker := NewTracker(-1)
+	tracker.SuiteStarted(&store.TestCase{ID: 1, Name: "VolumeIoSuite"}, "sc")
+	handler := NewServer(":0", tracker).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/progress/suites", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var running []SuiteStatus
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &running))
+	assert.Equal(t, "VolumeIoSuite", running[0].Name)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/progress", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAbort(t *testing.T) {
+	tracker := NewTracker(1)
+	server := NewServer(":0", tracker)
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/abort", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+
+	var aborted string
+	server.OnAbort("secret", func(run, reason string) error {
+		if run != "" && run != "test-run-1" {
+			return ErrUnknownRun
+		}
+		aborted = reason
+		tracker.Aborted(reason)
+		return nil
+	})
+	addr, err := server.Start()
+	assert.NoError(t, err)
+	defer server.Stop()
+
+	assert.ErrorContains(t, Abort(addr, "", "test-run-1", "no token"), "401")
+	assert.ErrorContains(t, Abort(addr, "guess", "test-run-1", "wrong token"), "401")
+	assert.Empty(t, aborted)
+
+	err = Abort(addr, "secret", "test-run-2", "wrong run")
+	assert.ErrorContains(t, err, "404")
+	assert.Empty(t, aborted)
+
+	// Forms can be posted cross-origin by any web page, only JSON is accepted
+	form := httptest.NewRequest(http.MethodPost, "/abort", strings.NewReader("reason=form"))
+	form.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	form.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, form)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Empty(t, aborted)
+
+	assert.NoError(t, Abort(addr, "secret", "test-run-1", "maintenance window"))
+	assert.Equal(t, "maintenance window", aborted)
+	assert.Equal(t, "maintenance window", tracker.Status().Aborted)
+
+	get := httptest.NewRequest(http.MethodGet, "/abort", nil)
+	get.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, get)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// Progress stays readable without token
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/progress", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	_, err := ReadToken()
+	assert.Error(t, err)
+
+	token, err := Token()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	again, err := ReadToken()
+	assert.NoError(t, err)
+	assert.Equal(t, token, again)
+}
+
+func TestTrackerObserve(t *testing.T) {
+	tracker := NewTracker(1)
+	tracker.SuiteStarted(&store.TestCase{ID: 1, Name: "VolumeIoSuite"}, "sc")
+
+	start := time.Now()
+	for i := int64(1); i <= 20; i++ {
+		tracker.Observe(&store.Event{TcID: 1, EntityID: i, Type: store.PvcAdded, Timestamp: start})
+		tracker.Observe(&store.Event{TcID: 1, EntityID: i, Type: store.PvcBound, Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+	tracker.Observe(&store.Event{TcID: 1, EntityID: 1, Type: store.PvcAttachStarted, Timestamp: start})
+	tracker.Observe(&store.Event{TcID: 1, EntityID: 1, Type: store.PvcAttachEnded, Timestamp: start.Add(time.Second)})
+	// Events of suites which aren't running are ignored
+	tracker.Observe(&store.Event{TcID: 2, EntityID: 1, Type: store.PvcBound, Timestamp: start})
+
+	s := tracker.Status().Running[0]
+	assert.Equal(t, 20, s.Bound)
+	assert.Equal(t, 19*time.Second, s.BindP95)
+	assert.Equal(t, 1, s.Attached)
+	assert.Equal(t, time.Second, s.AttachP95)
+}
+
+func TestLive(t *testing.T) {
+	tracker := NewTracker(3)
+	tracker.SetIteration(2)
+	tracker.SuiteStarted(&store.TestCase{ID: 1, Name: "VolumeIoSuite", StartTimestamp: time.Now()}, "sc")
+	tracker.UpdateEntities(&store.NumberEntities{TcID: 1, PodsReady: 2})
+
+	table := renderTable(tracker.Status())
+	assert.Contains(t, table, "iteration 2/3")
+	assert.Contains(t, table, "BIND P95")
+	assert.Contains(t, table, "VolumeIoSuite")
+	assert.Equal(t, 3, strings.Count(table, "\n"))
+
+	// Output which isn't a terminal gets plain lines and logs pass through untouched
+	var out bytes.Buffer
+	live := NewLive(tracker, &out)
+	_, err := live.Writer().Write([]byte("log line\n"))
+	assert.NoError(t, err)
+	live.update()
+	assert.Equal(t, "log line\n", strings.SplitAfter(out.String(), "\n")[0])
+	assert.Contains(t, out.String(), "VolumeIoSuite [sc]: elapsed 0s, bound 0, attached 0, pods ready 2, bind p95 -, attach p95 -")
+	live.Stop()
+}
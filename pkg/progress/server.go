@@ -0,0 +1,180 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/httpserver"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrUnknownRun is returned by abort handler when the process doesn't execute requested run
+var ErrUnknownRun = errors.New("run is not executed by this process")
+
+// tokenFile holds token of abort endpoint in .cert-csi folder of user home, it's shared by runs of the user
+const tokenFile = "progress-token"
+
+// AbortFunc aborts run with the reason, empty run name means any run of the process
+type AbortFunc func(run, reason string) error
+
+// Server serves progress of tracker over HTTP
+type Server struct {
+	*httpserver.Server
+	tracker *Tracker
+	abort   AbortFunc
+	token   string
+}
+
+// abortRequest is a body of abort request
+type abortRequest struct {
+	Run    string `json:"run,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Token returns token abort requests are authorized with, it's generated on first use and kept in user home
+func Token() (string, error) {
+	path, err := tokenPath()
+	if err != nil {
+		return "", err
+	}
+	return httpserver.Token(path)
+}
+
+// ReadToken returns token abort requests are authorized with, as generated by Token
+func ReadToken() (string, error) {
+	path, err := tokenPath()
+	if err != nil {
+		return "", err
+	}
+	return httpserver.ReadToken(path)
+}
+
+func tokenPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cert-csi", tokenFile), nil
+}
+
+// NewServer creates a Server, address without host is bound to localhost
+func NewServer(address string, tracker *Tracker) *Server {
+	s := &Server{tracker: tracker}
+	s.Server = httpserver.New("Progress", address, s.Handler())
+	return s
+}
+
+// OnAbort enables abort endpoint, requests to it authorized with token call abort
+func (s *Server) OnAbort(token string, abort AbortFunc) {
+	s.token = token
+	s.abort = abort
+}
+
+// Handler returns handler with read-only progress endpoints and abort endpoint requiring the token
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/progress", s.serve(func(st Status) interface{} { return st }))
+	mux.HandleFunc("/progress/suites", s.serve(func(st Status) interface{} { return st.Running }))
+	mux.HandleFunc("/progress/metrics", s.serve(func(st Status) interface{} { return st.Metrics }))
+	mux.HandleFunc("/abort", func(w http.ResponseWriter, r *http.Request) {
+		if s.abort == nil {
+			http.Error(w, "abort is not supported by this process", http.StatusNotImplemented)
+			return
+		}
+		httpserver.Authorize(s.token, http.HandlerFunc(s.serveAbort)).ServeHTTP(w, r)
+	})
+	return mux
+}
+
+// serveAbort aborts run named in JSON body with the reason, run isn't named to abort any run of the process
+func (s *Server) serveAbort(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	var req abortRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+		http.Error(w, "can't parse request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.abort(req.Run, req.Reason); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrUnknownRun) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	log.Warnf("Run aborted via %s: %s", r.RemoteAddr, req.Reason)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Abort asks process serving progress on address to abort run with the reason, request is authorized with token
+func Abort(address, token, run, reason string) error {
+	body, err := json.Marshal(abortRequest{Run: run, Reason: reason})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, httpserver.URL(address)+"/abort", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("abort rejected with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (s *Server) serve(view func(Status) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(view(s.tracker.Status())); err != nil {
+			log.Errorf("Can't encode progress; error=%v", err)
+		}
+	}
+}
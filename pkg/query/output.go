@@ -0,0 +1,104 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/fatih/color"
+)
+
+type jsonEntity struct {
+	TestCase string               `json:"testCase"`
+	Kind     store.EntityTypeEnum `json:"kind"`
+	Name     string               `json:"name"`
+	Failed   bool                 `json:"failed,omitempty"`
+	Stages   map[string]float64   `json:"stagesMs"`
+	Events   []jsonEvent          `json:"events"`
+}
+
+type jsonEvent struct {
+	Type      store.EventTypeEnum `json:"type"`
+	Timestamp time.Time           `json:"timestamp"`
+	Offset    float64             `json:"offsetMs"`
+	Message   string              `json:"message,omitempty"`
+}
+
+// WriteJSON writes entities with their timelines as JSON array, durations are in milliseconds like in JSON report
+func WriteJSON(w io.Writer, entities []Entity) error {
+	out := []jsonEntity{}
+	for _, e := range entities {
+		je := jsonEntity{TestCase: e.TestCase, Kind: e.Kind, Name: e.Name, Failed: e.Failed, Stages: make(map[string]float64)}
+		for s, d := range e.Stages {
+			je.Stages[s] = toMilliseconds(d)
+		}
+		for _, ev := range e.Events {
+			je.Events = append(je.Events, jsonEvent{Type: ev.Type, Timestamp: ev.Timestamp, Offset: toMilliseconds(ev.Offset), Message: ev.Message})
+		}
+		out = append(out, je)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// WriteTable writes a row of every entity followed by its event timeline
+func WriteTable(w io.Writer, entities []Entity) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TEST CASE\tKIND\tNAME\tSTAGES\tRESULT")
+	for _, e := range entities {
+		result := color.GreenString("OK")
+		if e.Failed {
+			result = color.RedString("FAILED")
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.TestCase, e.Kind, e.Name, formatStages(e.Stages), result)
+		for _, ev := range e.Events {
+			_, _ = fmt.Fprintf(tw, "\t\t  +%s\t%s\t%s\n", ev.Offset.Round(time.Millisecond), ev.Type, ev.Message)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d entities\n", len(entities))
+	return err
+}
+
+// formatStages returns stages sorted by name, ex. PVCBind=1.5s, PVCCreation=3s
+func formatStages(stages map[string]time.Duration) string {
+	names := make([]string, 0, len(stages))
+	for s := range stages {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	formatted := make([]string, 0, len(names))
+	for _, s := range names {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", s, stages[s].Round(time.Millisecond)))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
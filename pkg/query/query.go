@@ -0,0 +1,264 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package query
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// Condition is a single filter of entities in field<op>value form, ex. bind_time>30s
+type Condition struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// operators are matched longest first, so >= isn't parsed as >
+var operators = []string{">=", "<=", "!=", ">", "<", "=", "~"}
+
+// stageAliases map fields to stages of PVCs and pods, stages can also be referred to by their names, ex. PVCBind
+var stageAliases = map[string][]string{
+	"bind_time":     {string(collector.PVCBind)},
+	"wait_time":     {string(collector.PVCConsumerWait)},
+	"attach_time":   {string(collector.PVCAttachment)},
+	"detach_time":   {string(collector.PVCUnattachment)},
+	"ready_time":    {string(collector.PodCreation)},
+	"creation_time": {string(collector.PVCCreation), string(collector.PodCreation)},
+	"deletion_time": {string(collector.PVCDeletion), string(collector.PodDeletion)},
+}
+
+// ParseCondition parses condition, operators are >, >=, <, <=, =, != and ~ matching substring
+func ParseCondition(s string) (Condition, error) {
+	for i := range s {
+		for _, op := range operators {
+			if strings.HasPrefix(s[i:], op) {
+				c := Condition{Field: strings.TrimSpace(s[:i]), Op: op, Value: strings.TrimSpace(s[i+len(op):])}
+				if c.Field == "" || c.Value == "" {
+					return Condition{}, fmt.Errorf("condition %q needs both field and value", s)
+				}
+				return c, nil
+			}
+		}
+	}
+	return Condition{}, fmt.Errorf("condition %q has no operator, use one of %s", s, strings.Join(operators, " "))
+}
+
+// Event is a step of entity timeline, offset is time since the first event of the entity
+type Event struct {
+	Type      store.EventTypeEnum
+	Timestamp time.Time
+	Offset    time.Duration
+	Message   string
+}
+
+// Entity is a PVC or pod matching query
+type Entity struct {
+	TestCase string
+	Kind     store.EntityTypeEnum
+	Name     string
+	// Stages are durations of stages the entity finished, stages it started but never finished make it failed
+	Stages map[string]time.Duration
+	Failed bool
+	Events []Event
+}
+
+// Run returns entities of kind, pvc, pod or empty for both, of collected run which match all conditions,
+// ordered by test case, kind and name
+func Run(mc *collector.MetricsCollection, kind string, conditions []Condition) ([]Entity, error) {
+	if kind != "" && !strings.EqualFold(kind, "pvc") && !strings.EqualFold(kind, "pod") {
+		return nil, fmt.Errorf("unknown kind %s, use pvc or pod", kind)
+	}
+	var candidates []Entity
+	for _, tc := range mc.TestCasesMetrics {
+		if kind == "" || strings.EqualFold(kind, "pvc") {
+			for _, pvc := range tc.PVCs {
+				stages := make(map[string]time.Duration)
+				for s, d := range pvc.Metrics {
+					stages[string(s)] = d
+				}
+				candidates = append(candidates, newEntity(tc.TestCase.Name, store.Pvc, pvc.PVC.Name, stages, pvc.Events))
+			}
+		}
+		if kind == "" || strings.EqualFold(kind, "pod") {
+			for _, pod := range tc.Pods {
+				stages := make(map[string]time.Duration)
+				for s, d := range pod.Metrics {
+					stages[string(s)] = d
+				}
+				candidates = append(candidates, newEntity(tc.TestCase.Name, store.Pod, pod.Pod.Name, stages, pod.Events))
+			}
+		}
+	}
+	for _, c := range conditions {
+		if err := validate(c, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	var matched []Entity
+	for _, e := range candidates {
+		ok := true
+		for _, c := range conditions {
+			if ok = matches(e, c); !ok {
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, e)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].TestCase != matched[j].TestCase {
+			return matched[i].TestCase < matched[j].TestCase
+		}
+		if matched[i].Kind != matched[j].Kind {
+			return matched[i].Kind > matched[j].Kind
+		}
+		return matched[i].Name < matched[j].Name
+	})
+	return matched, nil
+}
+
+func newEntity(testCase string, kind store.EntityTypeEnum, name string, stages map[string]time.Duration, events []store.Event) Entity {
+	e := Entity{TestCase: testCase, Kind: kind, Name: name, Stages: make(map[string]time.Duration)}
+	for s, d := range stages {
+		switch {
+		case d < 0:
+			e.Failed = true
+		case d > 0:
+			e.Stages[s] = d
+		}
+	}
+	sorted := append([]store.Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	for _, ev := range sorted {
+		e.Events = append(e.Events, Event{Type: ev.Type, Timestamp: ev.Timestamp, Offset: ev.Timestamp.Sub(sorted[0].Timestamp), Message: ev.Message})
+	}
+	return e
+}
+
+// stageNames returns stages field refers to
+func stageNames(field string) []string {
+	if stages, ok := stageAliases[strings.ToLower(field)]; ok {
+		return stages
+	}
+	return []string{field}
+}
+
+// validate checks that field of condition exists and value fits it, stage fields must be a stage of some entity
+func validate(c Condition, entities []Entity) error {
+	switch strings.ToLower(c.Field) {
+	case "name", "test_case":
+		if c.Op != "=" && c.Op != "!=" && c.Op != "~" {
+			return fmt.Errorf("%s can only be compared with =, != or ~", c.Field)
+		}
+		return nil
+	case "failed":
+		if _, err := strconv.ParseBool(c.Value); err != nil || (c.Op != "=" && c.Op != "!=") {
+			return errors.New("failed can only be compared with = or != to true or false")
+		}
+		return nil
+	}
+	if c.Op == "~" {
+		return errors.New("durations can't be compared with ~")
+	}
+	if _, err := time.ParseDuration(c.Value); err != nil {
+		return fmt.Errorf("%s must be compared with duration, ex. 30s: %v", c.Field, err)
+	}
+	if _, ok := stageAliases[strings.ToLower(c.Field)]; ok {
+		return nil
+	}
+	for _, e := range entities {
+		for s := range e.Stages {
+			if strings.EqualFold(s, c.Field) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unknown field %s, use name, test_case, failed, a stage name or one of %s", c.Field, strings.Join(aliasNames(), ", "))
+}
+
+func aliasNames() []string {
+	var names []string
+	for name := range stageAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matches checks entity against validated condition, stages entity didn't finish never match
+func matches(e Entity, c Condition) bool {
+	switch strings.ToLower(c.Field) {
+	case "name":
+		return matchString(e.Name, c)
+	case "test_case":
+		return matchString(e.TestCase, c)
+	case "failed":
+		want, _ := strconv.ParseBool(c.Value)
+		return (e.Failed == want) == (c.Op == "=")
+	}
+	value, _ := time.ParseDuration(c.Value)
+	for _, stage := range stageNames(c.Field) {
+		for s, d := range e.Stages {
+			if strings.EqualFold(s, stage) {
+				return compare(d, c.Op, value)
+			}
+		}
+	}
+	return false
+}
+
+func matchString(s string, c Condition) bool {
+	switch c.Op {
+	case "=":
+		return s == c.Value
+	case "!=":
+		return s != c.Value
+	case "~":
+		return strings.Contains(s, c.Value)
+	}
+	return false
+}
+
+func compare(a time.Duration, op string, b time.Duration) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	return false
+}
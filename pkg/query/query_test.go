@@ -0,0 +1,121 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCondition(t *testing.T) {
+	c, err := ParseCondition("bind_time>=30s")
+	assert.NoError(t, err)
+	assert.Equal(t, Condition{Field: "bind_time", Op: ">=", Value: "30s"}, c)
+	c, err = ParseCondition("name ~ vol")
+	assert.NoError(t, err)
+	assert.Equal(t, Condition{Field: "name", Op: "~", Value: "vol"}, c)
+
+	for _, invalid := range []string{"bind_time", ">30s", "bind_time>"} {
+		_, err = ParseCondition(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mc := &collector.MetricsCollection{TestCasesMetrics: []collector.TestCaseMetrics{{
+		TestCase: store.TestCase{Name: "VolumeIoSuite"},
+		PVCs: []collector.PVCMetrics{
+			{
+				PVC:     store.Entity{Name: "vol-slow"},
+				Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: 45 * time.Second, collector.PVCAttachment: -1},
+				Events: []store.Event{
+					{Type: store.PvcBound, Timestamp: start.Add(45 * time.Second)},
+					{Type: store.PvcAdded, Timestamp: start},
+				},
+			},
+			{PVC: store.Entity{Name: "vol-fast"}, Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: time.Second}},
+		},
+		Pods: []collector.PodMetrics{
+			{Pod: store.Entity{Name: "iowriter"}, Metrics: map[collector.PodStage]time.Duration{collector.PodCreation: 40 * time.Second, "Mount": 2 * time.Second}},
+		},
+	}}}
+
+	entities, err := Run(mc, "pvc", []Condition{{Field: "bind_time", Op: ">", Value: "30s"}})
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, "vol-slow", entities[0].Name)
+	assert.True(t, entities[0].Failed)
+	assert.Equal(t, []store.EventTypeEnum{store.PvcAdded, store.PvcBound}, []store.EventTypeEnum{entities[0].Events[0].Type, entities[0].Events[1].Type})
+	assert.Equal(t, 45*time.Second, entities[0].Events[1].Offset)
+
+	// Stages are matched by alias or name, entities without the stage never match
+	entities, err = Run(mc, "", []Condition{{Field: "creation_time", Op: ">", Value: "30s"}})
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, "iowriter", entities[0].Name)
+	entities, err = Run(mc, "", []Condition{{Field: "mount", Op: "<", Value: "5s"}, {Field: "test_case", Op: "=", Value: "VolumeIoSuite"}})
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+
+	entities, err = Run(mc, "", []Condition{{Field: "failed", Op: "!=", Value: "true"}, {Field: "name", Op: "~", Value: "vol"}})
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, "vol-fast", entities[0].Name)
+
+	for _, invalid := range [][]Condition{
+		{{Field: "unknown", Op: ">", Value: "1s"}},
+		{{Field: "bind_time", Op: ">", Value: "fast"}},
+		{{Field: "bind_time", Op: "~", Value: "1s"}},
+		{{Field: "failed", Op: ">", Value: "true"}},
+		{{Field: "name", Op: ">", Value: "vol"}},
+	} {
+		_, err = Run(mc, "", invalid)
+		assert.Error(t, err, invalid[0].Field)
+	}
+	_, err = Run(mc, "node", nil)
+	assert.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	entities := []Entity{{
+		TestCase: "VolumeIoSuite",
+		Kind:     store.Pvc,
+		Name:     "vol-slow",
+		Stages:   map[string]time.Duration{"PVCBind": 1500 * time.Millisecond},
+		Events:   []Event{{Type: store.PvcAdded}, {Type: store.PvcBound, Offset: 1500 * time.Millisecond}},
+	}}
+
+	var table bytes.Buffer
+	assert.NoError(t, WriteTable(&table, entities))
+	assert.Contains(t, table.String(), "PVCBind=1.5s")
+	assert.Contains(t, table.String(), "+1.5s")
+	assert.Contains(t, table.String(), "1 entities")
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteJSON(&out, entities))
+	var decoded []jsonEntity
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, 1500.0, decoded[0].Stages["PVCBind"])
+	assert.Equal(t, 1500.0, decoded[0].Events[1].Offset)
+}
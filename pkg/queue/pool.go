@@ -0,0 +1,122 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+)
+
+// PollInterval is how often idle workers look for entries submitted by other processes
+var PollInterval = 5 * time.Second
+
+// Job runs a single queue entry in its directory
+type Job func(ctx context.Context, e *Entry, dir string) error
+
+// Pool executes queued entries with at most Workers of them running at once
+type Pool struct {
+	Queue   *Queue
+	Workers int
+	Job     Job
+
+	// wake is signalled when entry is submitted through the pool, so workers don't wait for the next poll
+	wake chan struct{}
+}
+
+// NewPool is a Pool constructor, pool has at least one worker
+func NewPool(q *Queue, workers int, job Job) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Pool{Queue: q, Workers: workers, Job: job, wake: make(chan struct{}, workers)}
+}
+
+// Submit appends run to queue and wakes up an idle worker
+func (p *Pool) Submit(args []string) (*Entry, error) {
+	e, err := p.Queue.Submit(args)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return e, nil
+}
+
+// Run recovers entries interrupted by previous daemon and executes queued ones until context is cancelled,
+// running entries are waited for before returning. Queue has to be locked by Queue.Lock
+func (p *Pool) Run(ctx context.Context) error {
+	recovered, err := p.Queue.Recover()
+	if err != nil {
+		return err
+	}
+	if recovered != 0 {
+		log.Warnf("Requeued %d runs interrupted by previous daemon", recovered)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// work executes entries one by one until context is cancelled
+func (p *Pool) work(ctx context.Context) {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		e, err := p.Queue.claim()
+		if err != nil {
+			log.Errorf("Can't claim queued run; error=%v", err)
+		}
+		if e == nil {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			case <-p.wake:
+			}
+			continue
+		}
+
+		log.Infof("Starting queued run %s: %v", color.CyanString(e.ID), e.Args)
+		runErr := p.Job(ctx, e, p.Queue.EntryDir(e.ID))
+		if runErr != nil && ctx.Err() != nil {
+			// Run interrupted by daemon shutdown stays running, so it's requeued when daemon starts again
+			log.Warnf("Queued run %s interrupted", e.ID)
+			return
+		}
+		if err := p.Queue.finish(e, runErr); err != nil {
+			log.Errorf("Can't save result of queued run %s; error=%v", e.ID, err)
+		}
+		if runErr != nil {
+			log.Errorf("Queued run %s failed after %s; error=%v", e.ID, e.Finished.Sub(e.Started).Round(time.Second), runErr)
+		} else {
+			log.Infof("Queued run %s finished in %s", color.CyanString(e.ID), e.Finished.Sub(e.Started).Round(time.Second))
+		}
+	}
+}
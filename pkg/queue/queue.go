@@ -0,0 +1,316 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/httpserver"
+)
+
+const (
+	// idPrefix is a prefix of entry directories, entries are numbered job-0001, job-0002...
+	idPrefix = "job-"
+	// entryFile holds entry state inside its directory
+	entryFile = "job.json"
+	// tokenFile holds token of queue API, readable by the queue owner only
+	tokenFile = "token"
+	// lockFile is locked by daemon executing entries and holds its pid
+	lockFile = "daemon.lock"
+)
+
+// allowedCommands are cert-csi commands runs can be queued with
+var allowedCommands = map[string]bool{"test": true, "certify": true, "functional-test": true}
+
+// forbiddenFlags execute commands on the daemon host, flags containing "hook" are rejected as well
+var forbiddenFlags = map[string]bool{"sh": true, "rh": true, "fh": true, "backend-config": true}
+
+// State of queue entry
+type State string
+
+const (
+	// Queued entry waits for a free worker
+	Queued State = "queued"
+	// Running entry is executed by a worker
+	Running State = "running"
+	// Succeeded entry finished without error
+	Succeeded State = "succeeded"
+	// Failed entry finished with error
+	Failed State = "failed"
+)
+
+// ErrUnknownEntry is returned when queue has no entry with requested id
+var ErrUnknownEntry = errors.New("queue has no such entry")
+
+// ErrLocked is returned by Lock when another daemon executes entries of the queue
+var ErrLocked = errors.New("queue is locked by another daemon")
+
+// Entry is a cert-csi run submitted to queue, its databases, logs and reports are written into its directory
+type Entry struct {
+	ID        string    `json:"id"`
+	Args      []string  `json:"args"`
+	State     State     `json:"state"`
+	Submitted time.Time `json:"submitted"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Queue keeps entries in Dir, one directory per entry, so entries survive restarts and can be submitted
+// by other processes
+type Queue struct {
+	Dir string
+
+	mutex sync.Mutex
+}
+
+// New creates queue in dir, the directory is created if it doesn't exist
+func New(dir string) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &Queue{Dir: dir}, nil
+}
+
+// Lock takes exclusive lock of the queue for daemon executing its entries, so a second daemon on the same directory
+// can't requeue and run entries of the first one. Lock is held until release is called, or the daemon exits
+func (q *Queue) Lock() (release func() error, err error) {
+	f, err := os.OpenFile(filepath.Join(q.Dir, lockFile), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		defer f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			pid, _ := io.ReadAll(f)
+			return nil, fmt.Errorf("%w: %s is used by daemon with pid %s", ErrLocked, q.Dir, strings.TrimSpace(string(pid)))
+		}
+		return nil, err
+	}
+	if err := f.Truncate(0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.WriteString(strconv.Itoa(os.Getpid())); err != nil {
+		f.Close()
+		return nil, err
+	}
+	// Closing the file releases the lock
+	return f.Close, nil
+}
+
+// EntryDir returns directory of entry
+func (q *Queue) EntryDir(id string) string {
+	return filepath.Join(q.Dir, id)
+}
+
+// Submit appends run with cert-csi args to queue. Creating entry directory reserves its id,
+// so concurrent submitters never get the same one
+func (q *Queue) Submit(args []string) (*Entry, error) {
+	if err := ValidateArgs(args); err != nil {
+		return nil, err
+	}
+	numbers, err := q.numbers()
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if len(numbers) != 0 {
+		next = numbers[len(numbers)-1] + 1
+	}
+	for ; ; next++ {
+		id := fmt.Sprintf("%s%04d", idPrefix, next)
+		err := os.Mkdir(q.EntryDir(id), 0o750)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := &Entry{ID: id, Args: args, State: Queued, Submitted: time.Now()}
+		return e, q.save(e)
+	}
+}
+
+// ValidateArgs checks that args run one of test commands and don't pass hooks, which would let
+// submitters execute arbitrary commands on the daemon host
+func ValidateArgs(args []string) error {
+	if len(args) == 0 {
+		return errors.New("cert-csi arguments are required")
+	}
+	if !allowedCommands[args[0]] {
+		return fmt.Errorf("command %q can't be queued, only test, certify and functional-test runs are allowed", args[0])
+	}
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.Contains(name, "hook") || forbiddenFlags[name] {
+			return fmt.Errorf("flag %s can't be used in queued runs, it executes commands on the daemon host", arg)
+		}
+	}
+	return nil
+}
+
+// Token returns token of queue API, it's generated on first use and kept in queue directory
+func (q *Queue) Token() (string, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return httpserver.Token(filepath.Join(q.Dir, tokenFile))
+}
+
+// ReadToken returns token of queue API kept in queue directory dir
+func ReadToken(dir string) (string, error) {
+	return httpserver.ReadToken(filepath.Join(dir, tokenFile))
+}
+
+// Get returns entry with id
+func (q *Queue) Get(id string) (*Entry, error) {
+	if !strings.HasPrefix(id, idPrefix) || strings.ContainsAny(id, `/\`) {
+		return nil, ErrUnknownEntry
+	}
+	data, err := os.ReadFile(filepath.Join(q.EntryDir(id), entryFile))
+	if os.IsNotExist(err) {
+		return nil, ErrUnknownEntry
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("can't parse entry %s: %w", id, err)
+	}
+	return &e, nil
+}
+
+// List returns entries in submission order, directories whose entry isn't written yet are skipped
+func (q *Queue) List() ([]*Entry, error) {
+	numbers, err := q.numbers()
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for _, n := range numbers {
+		e, err := q.Get(fmt.Sprintf("%s%04d", idPrefix, n))
+		if errors.Is(err, ErrUnknownEntry) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Recover puts entries left running by a stopped daemon back into queue, returns how many were recovered. Queue has
+// to be locked, otherwise entries of a running daemon are requeued
+func (q *Queue) Recover() (int, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	entries, err := q.List()
+	if err != nil {
+		return 0, err
+	}
+	recovered := 0
+	for _, e := range entries {
+		if e.State != Running {
+			continue
+		}
+		e.State, e.Started = Queued, time.Time{}
+		if err := q.save(e); err != nil {
+			return recovered, err
+		}
+		recovered++
+	}
+	return recovered, nil
+}
+
+// claim marks the oldest queued entry as running, nil if nothing is queued
+func (q *Queue) claim() (*Entry, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	entries, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.State != Queued {
+			continue
+		}
+		e.State, e.Started = Running, time.Now()
+		return e, q.save(e)
+	}
+	return nil, nil
+}
+
+// finish records result of entry run
+func (q *Queue) finish(e *Entry, runErr error) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	e.State, e.Finished = Succeeded, time.Now()
+	if runErr != nil {
+		e.State, e.Error = Failed, runErr.Error()
+	}
+	return q.save(e)
+}
+
+// save writes entry into temporary file renamed over the old one, so readers never see it half-written
+func (q *Queue) save(e *Entry) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(q.EntryDir(e.ID), entryFile)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// numbers returns numbers of entry directories in ascending order
+func (q *Queue) numbers() ([]int, error) {
+	dirs, err := os.ReadDir(q.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, d := range dirs {
+		if !d.IsDir() || !strings.HasPrefix(d.Name(), idPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(d.Name(), idPrefix))
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
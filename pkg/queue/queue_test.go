@@ -0,0 +1,208 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	dir := t.TempDir()
+	q, err := New(dir)
+	assert.NoError(t, err)
+
+	_, err = q.Submit(nil)
+	assert.Error(t, err)
+	_, err = q.Submit([]string{"cleanup", "--yes"})
+	assert.Error(t, err)
+	_, err = q.Submit([]string{"test", "vio", "--sc", "a", "--start-hook=./hook.sh"})
+	assert.Error(t, err)
+	_, err = q.Submit([]string{"certify", "-sh", "./hook.sh"})
+	assert.Error(t, err)
+	_, err = q.Submit([]string{"test", "vio", "--sc", "a", "--backend-config", "backend.yaml"})
+	assert.Error(t, err)
+
+	// Directory reserved by another submitter which hasn't written its entry yet
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "job-0001"), 0o750))
+	first, err := q.Submit([]string{"test", "vio", "--sc", "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-0002", first.ID)
+	second, err := q.Submit([]string{"test", "vio", "--sc", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-0003", second.ID)
+
+	entries, err := q.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	claimed, err := q.claim()
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, claimed.ID)
+	assert.Equal(t, Running, claimed.State)
+	assert.NoError(t, q.finish(claimed, errors.New("exit status 1")))
+
+	got, err := q.Get(first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, Failed, got.State)
+	assert.Equal(t, "exit status 1", got.Error)
+
+	// Run left running by stopped daemon is requeued
+	claimed, err = q.claim()
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, claimed.ID)
+	recovered, err := q.Recover()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, recovered)
+	got, err = q.Get(second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, Queued, got.State)
+
+	_, err = q.Get("job-9999")
+	assert.ErrorIs(t, err, ErrUnknownEntry)
+	_, err = q.Get("../job-0002")
+	assert.ErrorIs(t, err, ErrUnknownEntry)
+}
+
+func TestPool(t *testing.T) {
+	q, err := New(t.TempDir())
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err := q.Submit([]string{"test", "vio"})
+		assert.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mutex sync.Mutex
+	running, maxRunning, done := 0, 0, 0
+	pool := NewPool(q, 2, func(_ context.Context, e *Entry, dir string) error {
+		assert.DirExists(t, dir)
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mutex.Lock()
+		defer mutex.Unlock()
+		running--
+		done++
+		if done == 5 {
+			cancel()
+		}
+		if e.ID == "job-0003" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	})
+	assert.ErrorIs(t, pool.Run(ctx), context.Canceled)
+	assert.Equal(t, 2, maxRunning)
+
+	entries, err := q.List()
+	assert.NoError(t, err)
+	for _, e := range entries {
+		if e.ID == "job-0003" {
+			assert.Equal(t, Failed, e.State)
+		} else {
+			assert.Equal(t, Succeeded, e.State, e.ID)
+		}
+	}
+}
+
+func TestLock(t *testing.T) {
+	dir := t.TempDir()
+	first, err := New(dir)
+	assert.NoError(t, err)
+	release, err := first.Lock()
+	assert.NoError(t, err)
+
+	// Second daemon on the same directory must not start
+	second, err := New(dir)
+	assert.NoError(t, err)
+	_, err = second.Lock()
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.ErrorContains(t, err, strconv.Itoa(os.Getpid()))
+
+	assert.NoError(t, release())
+	release, err = second.Lock()
+	assert.NoError(t, err)
+	assert.NoError(t, release())
+}
+
+func TestServer(t *testing.T) {
+	q, err := New(t.TempDir())
+	assert.NoError(t, err)
+	token, err := q.Token()
+	assert.NoError(t, err)
+	again, err := ReadToken(q.Dir)
+	assert.NoError(t, err)
+	assert.Equal(t, token, again)
+	server := NewServer("localhost:0", token, NewPool(q, 1, nil))
+	addr, err := server.Start()
+	assert.NoError(t, err)
+	defer server.Stop()
+
+	e, err := SubmitTo(addr, token, []string{"test", "snap", "--sc", "sc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-0001", e.ID)
+	assert.Equal(t, Queued, e.State)
+
+	_, err = SubmitTo(addr, token, nil)
+	assert.Error(t, err)
+	_, err = SubmitTo(addr, token, []string{"test", "snap", "--sc", "sc", "--fh", "./hook.sh"})
+	assert.Error(t, err)
+	_, err = SubmitTo(addr, "wrong", []string{"test", "snap", "--sc", "sc"})
+	assert.Error(t, err)
+
+	// Simple cross-site form post carries neither the token nor JSON content type
+	resp, err := http.Post("http://"+addr+"/runs", "text/plain", strings.NewReader(`{"args":["test","snap"]}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	req, err := http.NewRequest(http.MethodPost, "http://"+addr+"/runs", strings.NewReader(`{"args":["test","snap"]}`))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	get := func(path string) int {
+		req, err := http.NewRequest(http.MethodGet, "http://"+addr+path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, get("/runs/job-0001"))
+	assert.Equal(t, http.StatusNotFound, get("/runs/job-0002"))
+}
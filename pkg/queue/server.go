@@ -0,0 +1,152 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package queue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/httpserver"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// submitRequest is a body of run submission
+type submitRequest struct {
+	Args []string `json:"args"`
+}
+
+// Server exposes queue of the pool over HTTP: POST /runs submits run, GET /runs lists entries and
+// GET /runs/<id> returns a single one. Every request must carry the token as bearer authorization
+type Server struct {
+	*httpserver.Server
+	pool  *Pool
+	token string
+}
+
+// NewServer creates a Server accepting requests authorized with token, address without host is bound to localhost
+func NewServer(address, token string, pool *Pool) *Server {
+	s := &Server{pool: pool, token: token}
+	s.Server = httpserver.New("Queue", address, s.Handler())
+	return s
+}
+
+// Handler returns handler of queue endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/runs", s.serveRuns)
+	mux.HandleFunc("/runs/", s.serveEntry)
+	return httpserver.Authorize(s.token, mux)
+}
+
+func (s *Server) serveRuns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		entries, err := s.pool.Queue.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, entries)
+	case http.MethodPost:
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		var req submitRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			http.Error(w, "can't parse request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := ValidateArgs(req.Args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		e, err := s.pool.Submit(req.Args)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Infof("Run %s submitted via %s: %v", e.ID, r.RemoteAddr, e.Args)
+		writeJSON(w, http.StatusCreated, e)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	e, err := s.pool.Queue.Get(strings.TrimPrefix(r.URL.Path, "/runs/"))
+	if errors.Is(err, ErrUnknownEntry) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, e)
+}
+
+// SubmitTo submits run with cert-csi args to daemon serving queue on address, authorized with token
+func SubmitTo(address, token string, args []string) (*Entry, error) {
+	body, err := json.Marshal(submitRequest{Args: args})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, httpserver.URL(address)+"/runs", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("submission rejected with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var e Entry
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("Can't encode response; error=%v", err)
+	}
+}
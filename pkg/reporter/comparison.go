@@ -0,0 +1,216 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ComparisonReport represents report comparing runs of storage classes of different drivers side by side
+const ComparisonReport ReportType = "COMPARISON"
+
+// ComparedRun is a run of a single storage class in comparison
+type ComparedRun struct {
+	Name         string
+	StorageClass string
+	// Driver is provisioner of storage class as probed when run started, empty if capabilities weren't probed
+	Driver string
+	Passed int
+	Failed int
+	// Wins is number of stages run had the lowest average latency in
+	Wins int
+}
+
+// ComparedStage holds average latency of stage in every run, zero if run didn't go through it
+type ComparedStage struct {
+	Stage string
+	Avg   []time.Duration
+	// Best is index of run with the lowest latency, -1 if only one run went through stage
+	Best int
+}
+
+// ComparedSuite is a suite planned with the same parameters for every run, iterations are averaged
+type ComparedSuite struct {
+	Name       string
+	Parameters string
+	Passed     []int
+	Failed     []int
+	Duration   []time.Duration
+	Stages     []ComparedStage
+}
+
+// DriverComparison compares runs of the same suites with different storage classes
+type DriverComparison struct {
+	Runs   []ComparedRun
+	Suites []ComparedSuite
+	// Skipped lists suites which didn't run with every storage class or ran with different parameters
+	Skipped []string
+}
+
+// suiteRuns accumulates test cases of a suite in every run
+type suiteRuns struct {
+	suite    *ComparedSuite
+	runs     []int
+	duration []time.Duration
+	stages   map[string][]time.Duration
+	counts   map[string][]int
+}
+
+// getDriverComparison compares test cases of suites which ran with the same parameters in every run
+func getDriverComparison(mcs []*collector.MetricsCollection) *DriverComparison {
+	comparison := &DriverComparison{}
+	bySuite := make(map[string]*suiteRuns)
+	var order []string
+	for i, mc := range mcs {
+		run := ComparedRun{Name: mc.Run.Name, StorageClass: mc.Run.StorageClass}
+		var capabilities struct {
+			Driver string `json:"driver"`
+		}
+		if json.Unmarshal([]byte(mc.Run.Capabilities), &capabilities) == nil {
+			run.Driver = capabilities.Driver
+		}
+		comparison.Runs = append(comparison.Runs, run)
+
+		for _, tc := range mc.TestCasesMetrics {
+			if tc.TestCase.Skipped {
+				continue
+			}
+			key := tc.TestCase.Name + " " + tc.TestCase.Parameters
+			sr, ok := bySuite[key]
+			if !ok {
+				sr = &suiteRuns{
+					suite: &ComparedSuite{
+						Name:       tc.TestCase.Name,
+						Parameters: tc.TestCase.Parameters,
+						Passed:     make([]int, len(mcs)),
+						Failed:     make([]int, len(mcs)),
+					},
+					runs:     make([]int, len(mcs)),
+					duration: make([]time.Duration, len(mcs)),
+					stages:   make(map[string][]time.Duration),
+					counts:   make(map[string][]int),
+				}
+				bySuite[key] = sr
+				order = append(order, key)
+			}
+			sr.runs[i]++
+			if tc.TestCase.Success {
+				sr.suite.Passed[i]++
+				comparison.Runs[i].Passed++
+			} else {
+				sr.suite.Failed[i]++
+				comparison.Runs[i].Failed++
+			}
+			sr.duration[i] += tc.TestCase.EndTimestamp.Sub(tc.TestCase.StartTimestamp)
+			for stage, d := range tc.StageMetrics {
+				name := fmt.Sprint(stage)
+				if _, ok := sr.stages[name]; !ok {
+					sr.stages[name] = make([]time.Duration, len(mcs))
+					sr.counts[name] = make([]int, len(mcs))
+				}
+				sr.stages[name][i] += d.Avg
+				sr.counts[name][i]++
+			}
+		}
+	}
+
+	for _, key := range order {
+		sr := bySuite[key]
+		comparable := true
+		for _, n := range sr.runs {
+			comparable = comparable && n != 0
+		}
+		if !comparable {
+			comparison.Skipped = append(comparison.Skipped, sr.suite.Name+" "+sr.suite.Parameters)
+			continue
+		}
+		for i, n := range sr.runs {
+			sr.suite.Duration = append(sr.suite.Duration, (sr.duration[i] / time.Duration(n)).Round(time.Second))
+		}
+		var stages []string
+		for stage := range sr.stages {
+			stages = append(stages, stage)
+		}
+		sort.Strings(stages)
+		for _, stage := range stages {
+			cs := ComparedStage{Stage: stage, Best: -1}
+			ran := 0
+			for i, total := range sr.stages[stage] {
+				var avg time.Duration
+				if n := sr.counts[stage][i]; n != 0 {
+					avg = total / time.Duration(n)
+					ran++
+				}
+				cs.Avg = append(cs.Avg, avg)
+				if avg != 0 && (cs.Best == -1 || avg < cs.Avg[cs.Best]) {
+					cs.Best = i
+				}
+			}
+			if ran < 2 {
+				cs.Best = -1
+			}
+			if cs.Best != -1 {
+				comparison.Runs[cs.Best].Wins++
+			}
+			sr.suite.Stages = append(sr.suite.Stages, cs)
+		}
+		comparison.Suites = append(comparison.Suites, *sr.suite)
+	}
+	return comparison
+}
+
+// ComparisonReporter is used to generate report comparing runs of different drivers
+type ComparisonReporter struct{}
+
+// MultiGenerate generates comparison report of metrics collections of storage classes
+func (cr *ComparisonReporter) MultiGenerate(mcs []*collector.MetricsCollection) error {
+	comparison := getDriverComparison(mcs)
+	for _, run := range comparison.Runs {
+		log.Infof("%s had the lowest latency in %d stages, %d suites passed and %d failed", run.StorageClass, run.Wins, run.Passed, run.Failed)
+	}
+
+	templateData, err := embedFS.ReadFile("templates/comparison-template.html")
+	if err != nil {
+		return err
+	}
+	report, err := template.New("comparison-template").Funcs(template.FuncMap{"inc": inc}).Parse(string(templateData))
+	if err != nil {
+		return err
+	}
+
+	htmlFile, _, err := getReportFileIn(reportRoot(mcs), "comparison", "html")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := htmlFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+	if err := addPathToFile("report.path", "COMPARISON_REPORT_PATH", htmlFile.Name()); err != nil {
+		return err
+	}
+	return report.Execute(htmlFile, comparison)
+}
@@ -18,6 +18,7 @@ package reporter
 
 import (
 	"html/template"
+	"io"
 
 	"github.com/dell/cert-csi/pkg/collector"
 )
@@ -29,15 +30,50 @@ type HTMLReporter struct{}
 
 // Generate generates a HTML report
 func (hr *HTMLReporter) Generate(runName string, mc *collector.MetricsCollection) error {
+	htmlFile, _, err := getReportFile(runName, "html")
+	if err != nil {
+		return err
+	}
+
+	err = addPathToFile("report.path", "HTML_REPORT_PATH", htmlFile.Name())
+	if err != nil {
+		return err
+	}
+
+	return hr.render(htmlFile, mc)
+}
+
+// render writes HTML report of metrics collection to w, plots are referenced relative to report directory
+func (hr *HTMLReporter) render(w io.Writer, mc *collector.MetricsCollection) error {
 	fm := template.FuncMap{
 		"formatName":                      formatName,
 		"inc":                             inc,
 		"getResultStatus":                 hr.getResultStatus,
+		"formatDifference":                formatDifference,
 		"getColorResultStatus":            hr.getColorResultStatus,
 		"shouldBeIncluded":                shouldBeIncluded,
+		"getUnstablePods":                 getUnstablePods,
+		"getLatencyDistributions":         getLatencyDistributions,
+		"summarizeExpansions":             collector.SummarizeExpansions,
+		"getStageHeadrooms":               getStageHeadrooms,
+		"formatBytes":                     formatBytes,
+		"getClusterStatePaths":            getClusterStatePaths,
+		"getPlotStageTimeoutPath":         getPlotStageTimeoutPath,
+		"getPlotSnapshotReadinessPath":    getPlotSnapshotReadinessPath,
+		"getSummary":                      getSummary,
+		"getSnapClassSweep":               getSnapClassSweep,
+		"getBackgroundLoad":               getBackgroundLoad,
+		"getEntityTimelines":              getEntityTimelines,
+		"getOmittedEntities":              getOmittedEntities,
+		"getMergedTimeline":               getMergedTimeline,
+		"entityAnchor":                    entityAnchor,
 		"getPlotStageMetricHistogramPath": getPlotStageMetricHistogramPath,
 		"getPlotStageBoxPath":             getPlotStageBoxPath,
 		"getPlotEntityOverTimePath":       getPlotEntityOverTimePath,
+		"getPlotEventsPerSecondPath":      getPlotEventsPerSecondPath,
+		"getPlotRampLatencyPath":          getPlotRampLatencyPath,
+		"getSLOSummaries":                 getSLOSummaries,
+		"getPlotSLOBurnDownPath":          getPlotSLOBurnDownPath,
 		"getMinMaxEntityOverTimePaths":    getMinMaxEntityOverTimePaths,
 		"getDriverResourceUsage":          getDriverResourceUsage,
 		"getAvgStageTimeOverIterations":   getAvgStageTimeOverIterations,
@@ -54,21 +90,7 @@ func (hr *HTMLReporter) Generate(runName string, mc *collector.MetricsCollection
 		return err
 	}
 
-	htmlFile, _, err := getReportFile(runName, "html")
-	if err != nil {
-		return err
-	}
-
-	err = addPathToFile("report.path", "HTML_REPORT_PATH", htmlFile.Name())
-	if err != nil {
-		return err
-	}
-
-	if err := report.Execute(htmlFile, mc); err != nil {
-		return err
-	}
-
-	return nil
+	return report.Execute(w, mc)
 }
 
 func (hr *HTMLReporter) getResultStatus(result bool) string {
@@ -0,0 +1,169 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/load"
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// JSONReport represents machine readable JSON report
+const JSONReport ReportType = "JSON"
+
+// JSONReporter is used to create and manage JSON report
+type JSONReporter struct{}
+
+type jsonReport struct {
+	Run            store.TestRun       `json:"run"`
+	Summary        Summary             `json:"summary"`
+	TestCases      []jsonTestCase      `json:"testCases"`
+	BackendLeaks   []store.BackendLeak `json:"backendLeaks,omitempty"`
+	Capabilities   []jsonCapability    `json:"capabilities,omitempty"`
+	BackgroundLoad *load.Report        `json:"backgroundLoad,omitempty"`
+}
+
+type jsonCapability struct {
+	Name     string `json:"name"`
+	Declared string `json:"declared"`
+	Observed string `json:"observed,omitempty"`
+	Mismatch bool   `json:"mismatch,omitempty"`
+}
+
+type jsonTestCase struct {
+	Name         string                  `json:"name"`
+	Parameters   string                  `json:"parameters"`
+	Success      bool                    `json:"success"`
+	Skipped      bool                    `json:"skipped,omitempty"`
+	ErrorMessage string                  `json:"errorMessage,omitempty"`
+	Start        time.Time               `json:"start"`
+	End          time.Time               `json:"end"`
+	Stages       []jsonStage             `json:"stages"`
+	Assertions   []store.AssertionResult `json:"assertions,omitempty"`
+	Comparisons  []store.Comparison      `json:"comparisons,omitempty"`
+	// Entities are never sampled, unlike html report of huge runs
+	Entities []jsonEntity `json:"entities,omitempty"`
+}
+
+// jsonEntity holds full timeline of PVC or pod
+type jsonEntity struct {
+	Kind     store.EntityTypeEnum `json:"kind"`
+	Name     string               `json:"name"`
+	Failed   bool                 `json:"failed,omitempty"`
+	Duration float64              `json:"durationMs"`
+	Events   []jsonEvent          `json:"events"`
+}
+
+type jsonEvent struct {
+	Type      store.EventTypeEnum `json:"type"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// jsonStage holds stage durations in milliseconds
+type jsonStage struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"minMs"`
+	Max  float64 `json:"maxMs"`
+	Avg  float64 `json:"avgMs"`
+}
+
+// Generate writes JSON report of metrics collection
+func (jr *JSONReporter) Generate(runName string, mc *collector.MetricsCollection) error {
+	jsonFile, _, err := getReportFile(runName, "json")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := jsonFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	return jr.render(jsonFile, mc)
+}
+
+// render writes JSON report of metrics collection to w
+func (jr *JSONReporter) render(w io.Writer, mc *collector.MetricsCollection) error {
+	report := jsonReport{
+		Run:            mc.Run,
+		Summary:        getSummary(mc),
+		BackendLeaks:   mc.BackendLeaks,
+		BackgroundLoad: getBackgroundLoad(mc.Run),
+	}
+	for _, c := range mc.Capabilities {
+		report.Capabilities = append(report.Capabilities, jsonCapability(c))
+	}
+	for _, tc := range mc.TestCasesMetrics {
+		report.TestCases = append(report.TestCases, jsonTestCase{
+			Name:         tc.TestCase.Name,
+			Parameters:   tc.TestCase.Parameters,
+			Success:      tc.TestCase.Success,
+			Skipped:      tc.TestCase.Skipped,
+			ErrorMessage: tc.TestCase.ErrorMessage,
+			Start:        tc.TestCase.StartTimestamp,
+			End:          tc.TestCase.EndTimestamp,
+			Stages:       getJSONStages(tc),
+			Assertions:   tc.AssertionResults,
+			Comparisons:  tc.Comparisons,
+			Entities:     getJSONEntities(tc),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// getJSONStages returns stage metrics sorted by stage name, as stage metrics map has interface keys
+func getJSONStages(tc collector.TestCaseMetrics) []jsonStage {
+	stages := []jsonStage{}
+	for stage, d := range tc.StageMetrics {
+		stages = append(stages, jsonStage{
+			Name: fmt.Sprint(stage),
+			Min:  toMilliseconds(d.Min),
+			Max:  toMilliseconds(d.Max),
+			Avg:  toMilliseconds(d.Avg),
+		})
+	}
+	sort.Slice(stages, func(i, j int) bool {
+		return stages[i].Name < stages[j].Name
+	})
+	return stages
+}
+
+// getJSONEntities returns timelines of all PVCs and pods of test case
+func getJSONEntities(tc collector.TestCaseMetrics) []jsonEntity {
+	var entities []jsonEntity
+	for _, et := range getAllEntityTimelines(tc) {
+		entity := jsonEntity{Kind: et.Kind, Name: et.Name, Failed: et.Failed, Duration: toMilliseconds(et.Duration())}
+		for _, step := range et.Steps {
+			entity.Events = append(entity.Events, jsonEvent{Type: step.Event.Type, Timestamp: step.Event.Timestamp})
+		}
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,185 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/collector"
+)
+
+// Redact replaces cluster, node and namespace names and IP addresses in reports with pseudonyms,
+// so reports of customer environments can be shared without manual scrubbing
+var Redact bool
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern = regexp.MustCompile(`(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}`)
+	// Names are only taken from contexts Kubernetes and cert-csi quote them in, bare words are too ambiguous
+	namespacePattern = regexp.MustCompile(`(?:namespace "|namespaces/|namespace=|"namespace":")([a-z0-9][-a-z0-9]*)`)
+	nodePattern      = regexp.MustCompile(`(?:node "|nodes/|node=|"nodeName":")([a-z0-9][-a-z0-9.]*)`)
+)
+
+// builtinNamespaces exist in every cluster, so they don't identify it
+var builtinNamespaces = map[string]bool{"default": true, "kube-system": true, "kube-public": true, "kube-node-lease": true}
+
+// redactor assigns pseudonyms to identifying values, the same value gets the same pseudonym in every
+// collection redacted by it, so reports of different runs can still be correlated
+type redactor struct {
+	pseudonyms map[string]string
+	counts     map[string]int
+}
+
+func newRedactor() *redactor {
+	return &redactor{pseudonyms: make(map[string]string), counts: make(map[string]int)}
+}
+
+// redact replaces identifying values in every exported string of the collection in place
+func (r *redactor) redact(mc *collector.MetricsCollection) {
+	found := map[string]map[string]bool{"cluster": {}, "node": {}, "namespace": {}, "ip": {}}
+	walkStrings(reflect.ValueOf(mc), "", func(field, s string) string {
+		switch field {
+		case "ClusterAddress":
+			if host := clusterHost(s); host != "" {
+				found["cluster"][host] = true
+			}
+		case "Node", "NodeName":
+			if s != "" {
+				found["node"][s] = true
+			}
+		}
+		for _, m := range namespacePattern.FindAllStringSubmatch(s, -1) {
+			if !builtinNamespaces[m[1]] {
+				found["namespace"][m[1]] = true
+			}
+		}
+		for _, m := range nodePattern.FindAllStringSubmatch(s, -1) {
+			found["node"][m[1]] = true
+		}
+		for _, candidate := range append(ipv4Pattern.FindAllString(s, -1), ipv6Pattern.FindAllString(s, -1)...) {
+			if ip := net.ParseIP(candidate); ip != nil && strings.ContainsAny(candidate, "0123456789abcdefABCDEF") {
+				found["ip"][candidate] = true
+			}
+		}
+		return s
+	})
+
+	// Cluster host may be an address too, it keeps pseudonym of the cluster
+	for _, kind := range []string{"cluster", "node", "namespace", "ip"} {
+		values := make([]string, 0, len(found[kind]))
+		for v := range found[kind] {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			r.assign(kind, v)
+		}
+	}
+
+	// Longer values go first, so a value which is a part of another one doesn't break its replacement
+	values := make([]string, 0, len(r.pseudonyms))
+	for v := range r.pseudonyms {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	pairs := make([]string, 0, 2*len(values))
+	for _, v := range values {
+		pairs = append(pairs, v, r.pseudonyms[v])
+	}
+	replacer := strings.NewReplacer(pairs...)
+	walkStrings(reflect.ValueOf(mc), "", func(_, s string) string {
+		return replacer.Replace(s)
+	})
+}
+
+// assign gives value pseudonym of its kind unless it already has one, addresses get ones of reserved ranges
+func (r *redactor) assign(kind, value string) {
+	if _, ok := r.pseudonyms[value]; ok {
+		return
+	}
+	if kind == "ip" && strings.Contains(value, ":") {
+		kind = "ipv6"
+	}
+	r.counts[kind]++
+	n := r.counts[kind]
+	switch kind {
+	case "ipv6":
+		r.pseudonyms[value] = fmt.Sprintf("2001:db8::%x", n)
+	case "ip":
+		r.pseudonyms[value] = fmt.Sprintf("198.18.%d.%d", n/256, n%256)
+	default:
+		r.pseudonyms[value] = fmt.Sprintf("%s-%d", kind, n)
+	}
+}
+
+// clusterHost returns host of cluster address, address may be a URL or a bare host with port
+func clusterHost(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// walkStrings calls fn for every settable string reachable from v and sets it to the result,
+// field is the name of struct field string belongs to
+func walkStrings(v reflect.Value, field string, fn func(field, s string) string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(fn(field, v.String()))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkStrings(v.Elem(), field, fn)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkStrings(v.Field(i), v.Type().Field(i).Name, fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkStrings(v.Index(i), field, fn)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String && v.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			walkStrings(elem, field, fn)
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	reportersMutex sync.RWMutex
+	reporters      = map[ReportType]Reporter{
+		HTMLReport:  &HTMLReporter{},
+		TextReport:  &TextReporter{},
+		JSONReport:  &JSONReporter{},
+		SARIFReport: &SARIFReporter{},
+	}
+	// reporterOrder keeps registration order of types generated by default, so reports of all types are generated in a stable order.
+	// Built-in JSON and SARIF reports are generated only on request
+	reporterOrder  = []ReportType{HTMLReport, TextReport}
+	multiReporters = map[ReportType]MultiReporter{
+		TabularReport:    &TabularReporter{},
+		XMLReport:        &XMLReporter{},
+		ComparisonReport: &ComparisonReporter{},
+	}
+	// reportTypeAliases are names of report types accepted by ParseReportType in addition to type names
+	reportTypeAliases = map[string]ReportType{
+		"TXT":     TextReport,
+		"TABULAR": TabularReport,
+		"JUNIT":   XMLReport,
+	}
+)
+
+// Register makes reporter available under given report type, so programs embedding cert-csi can add their own formats.
+// Registered reporters are invoked by GenerateAllReports, registering existing type replaces its reporter
+func Register(reportType ReportType, r Reporter) {
+	reportersMutex.Lock()
+	defer reportersMutex.Unlock()
+	if _, ok := reporters[reportType]; !ok {
+		reporterOrder = append(reporterOrder, reportType)
+	}
+	reporters[reportType] = r
+}
+
+// RegisterMulti makes reporter of multiple test runs available under given report type
+func RegisterMulti(reportType ReportType, r MultiReporter) {
+	reportersMutex.Lock()
+	defer reportersMutex.Unlock()
+	multiReporters[reportType] = r
+}
+
+// RegisteredReportTypes returns types of all registered single run reporters in registration order
+func RegisteredReportTypes() []ReportType {
+	reportersMutex.RLock()
+	defer reportersMutex.RUnlock()
+	return append([]ReportType(nil), reporterOrder...)
+}
+
+func getReporter(reportType ReportType) (Reporter, error) {
+	reportersMutex.RLock()
+	defer reportersMutex.RUnlock()
+	r, ok := reporters[reportType]
+	if !ok {
+		return nil, fmt.Errorf("no reporter registered for %s report type", reportType)
+	}
+	return r, nil
+}
+
+func getMultiReporter(reportType ReportType) (MultiReporter, error) {
+	reportersMutex.RLock()
+	defer reportersMutex.RUnlock()
+	r, ok := multiReporters[reportType]
+	if !ok {
+		return nil, fmt.Errorf("no multi reporter registered for %s report type", reportType)
+	}
+	return r, nil
+}
+
+// ParseReportType finds registered report type by case insensitive name or alias (txt, tabular, junit),
+// multi is true if the type is generated from multiple test runs at once
+func ParseReportType(name string) (reportType ReportType, multi bool, err error) {
+	reportType = ReportType(strings.ToUpper(strings.TrimSpace(name)))
+	if alias, ok := reportTypeAliases[string(reportType)]; ok {
+		reportType = alias
+	}
+	reportersMutex.RLock()
+	defer reportersMutex.RUnlock()
+	if _, ok := reporters[reportType]; ok {
+		return reportType, false, nil
+	}
+	if _, ok := multiReporters[reportType]; ok {
+		return reportType, true, nil
+	}
+	return "", false, fmt.Errorf("unknown report format %q", name)
+}
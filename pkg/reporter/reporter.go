@@ -19,11 +19,13 @@ package reporter
 import (
 	"embed"
 	"fmt"
+	"html"
 	"html/template"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dell/cert-csi/pkg/collector"
 	"github.com/dell/cert-csi/pkg/plotter"
@@ -49,6 +51,13 @@ var embedFS embed.FS
 // PathReport represent path of the report file
 var PathReport string
 
+var (
+	// SLOs are latency objectives shown on SLO burn-down chart of every test case, chart is skipped if empty
+	SLOs []collector.SLO
+	// SLOWindow is rolling window of SLO burn-down chart
+	SLOWindow = time.Minute
+)
+
 // Reporter is an interface for generating reports
 type Reporter interface {
 	Generate(runName string, mc *collector.MetricsCollection) error
@@ -59,36 +68,40 @@ type MultiReporter interface {
 	MultiGenerate(mc []*collector.MetricsCollection) error
 }
 
-// GenerateAllReports generates reports of types HTML and Text
+// GenerateAllReports generates reports of all registered types, HTML and Text unless more were registered
 func GenerateAllReports(dbs []*store.StorageClassDB) error {
-	reportTypes := []ReportType{
-		HTMLReport,
-		TextReport,
-	}
-	return GenerateReports(reportTypes, dbs)
+	return GenerateReports(RegisteredReportTypes(), dbs)
 }
 
 // GenerateReportsFromMultipleDBs generates reports from multiple DBs
 func GenerateReportsFromMultipleDBs(reportTypes []ReportType, scDBs []*store.StorageClassDB) error {
-	funcMap := map[ReportType]MultiReporter{
-		TabularReport: &TabularReporter{},
-		XMLReport:     &XMLReporter{},
+	var selected []MultiReporter
+	for _, reportType := range reportTypes {
+		r, err := getMultiReporter(reportType)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, r)
 	}
 
 	log.Infof("Started generating reports...")
 
 	var mcs []*collector.MetricsCollection
+	redactor := newRedactor()
 	for _, scDB := range scDBs {
 		c := collector.NewMetricsCollector(scDB.DB)
 		metricsCollection, err := c.Collect(scDB.TestRun.Name)
 		if err != nil {
 			return err
 		}
+		if Redact {
+			redactor.redact(metricsCollection)
+		}
 		mcs = append(mcs, metricsCollection)
 	}
 
-	for _, reportType := range reportTypes {
-		if err := funcMap[reportType].MultiGenerate(mcs); err != nil {
+	for _, r := range selected {
+		if err := r.MultiGenerate(mcs); err != nil {
 			return err
 		}
 	}
@@ -98,13 +111,18 @@ func GenerateReportsFromMultipleDBs(reportTypes []ReportType, scDBs []*store.Sto
 
 // GenerateReports generates reports of type HTML and Text
 func GenerateReports(reportTypes []ReportType, dbs []*store.StorageClassDB) error {
-	funcMap := map[ReportType]Reporter{
-		HTMLReport: &HTMLReporter{},
-		TextReport: &TextReporter{},
+	var selected []Reporter
+	for _, reportType := range reportTypes {
+		r, err := getReporter(reportType)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, r)
 	}
 	log.Infof("Started generating reports...")
 
 	invalidTestRuns := []string{}
+	redactor := newRedactor()
 	// Checking availability of all the test runs in every database
 	for i := 0; i < len(dbs); i++ {
 		mc := collector.NewMetricsCollector(dbs[i].DB)
@@ -114,11 +132,14 @@ func GenerateReports(reportTypes []ReportType, dbs []*store.StorageClassDB) erro
 			invalidTestRuns = append(invalidTestRuns, dbs[i].TestRun.Name)
 			continue
 		}
+		if Redact {
+			redactor.redact(metricsCollection)
+		}
 
 		generatePlots(dbs[i].TestRun.Name, metricsCollection)
 
-		for _, reportType := range reportTypes {
-			if err := funcMap[reportType].Generate(dbs[i].TestRun.Name, metricsCollection); err != nil {
+		for _, r := range selected {
+			if err := r.Generate(dbs[i].TestRun.Name, metricsCollection); err != nil {
 				return err
 			}
 		}
@@ -155,6 +176,34 @@ func generatePlots(runName string, mc *collector.MetricsCollection) {
 		if err != nil {
 			log.Error(err)
 		}
+		_, err = plotter.PlotEventsPerSecond(tcMetrics, runName)
+		if err != nil {
+			log.Error(err)
+		}
+		if len(getSLOSummaries(tcMetrics)) != 0 {
+			_, err = plotter.PlotSLOBurnDown(tcMetrics, SLOs, SLOWindow, runName)
+			if err != nil {
+				log.Error(err)
+			}
+		}
+		if len(getStageHeadrooms(tcMetrics, mc.Run)) != 0 {
+			_, err = plotter.PlotStageTimeout(tcMetrics, collector.StageTimeout(mc.Run), runName)
+			if err != nil {
+				log.Error(err)
+			}
+		}
+		if tcMetrics.SnapshotReadiness != nil {
+			_, err = plotter.PlotSnapshotReadiness(tcMetrics, runName)
+			if err != nil {
+				log.Error(err)
+			}
+		}
+		if len(tcMetrics.RampMetrics) != 0 {
+			_, err = plotter.PlotRampLatency(tcMetrics, runName)
+			if err != nil {
+				log.Error(err)
+			}
+		}
 	}
 	if bar != nil {
 		bar.Finish()
@@ -194,12 +243,21 @@ func (pp *PlotPath) HTML() template.URL {
 	return template.URL(strings.ReplaceAll(pp.Path, "\\", "/")) // #nosec G203
 }
 
+// Embed returns html element showing the plot, PDF plots browsers can't show as images are embedded as objects
+func (pp *PlotPath) Embed(alt string) template.HTML {
+	src := html.EscapeString(strings.ReplaceAll(pp.Path, "\\", "/"))
+	if strings.HasSuffix(pp.Path, "."+string(plotter.FormatPDF)) {
+		return template.HTML(fmt.Sprintf(`<object data="%s" type="application/pdf" class="plot-pdf"><a href="%s">%s</a></object>`, src, src, html.EscapeString(alt))) // #nosec G203
+	}
+	return template.HTML(fmt.Sprintf(`<img src="%s" alt="%s">`, src, html.EscapeString(alt))) // #nosec G203
+}
+
 func getPlotStageMetricHistogramPath(tc collector.TestCaseMetrics, stage interface{}, reportName string) *PlotPath {
 	return &PlotPath{
 		Path: filepath.Join(
 			".",
 			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
-			fmt.Sprintf("%s.png", stage),
+			plotter.PlotFile(fmt.Sprint(stage)),
 		),
 		ReportName: reportName,
 	}
@@ -209,9 +267,9 @@ func getPlotStageBoxPath(tc collector.TestCaseMetrics, stage interface{}, report
 	var fileName string
 	switch stage.(type) {
 	case collector.PVCStage:
-		fileName = fmt.Sprintf("%s.png", stage.(collector.PVCStage)+"_boxplot")
+		fileName = plotter.PlotFile(string(stage.(collector.PVCStage) + "_boxplot"))
 	default:
-		fileName = fmt.Sprintf("%s.png", stage.(collector.PodStage)+"_boxplot")
+		fileName = plotter.PlotFile(string(stage.(collector.PodStage) + "_boxplot"))
 	}
 	return &PlotPath{
 		Path:       filepath.Join(".", tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)), fileName),
@@ -224,17 +282,104 @@ func getPlotEntityOverTimePath(tc collector.TestCaseMetrics, reportName string)
 		Path: filepath.Join(
 			".",
 			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
-			"EntityNumberOverTime.png",
+			plotter.PlotFile("EntityNumberOverTime"),
+		),
+		ReportName: reportName,
+	}
+}
+
+func getPlotEventsPerSecondPath(tc collector.TestCaseMetrics, reportName string) *PlotPath {
+	return &PlotPath{
+		Path: filepath.Join(
+			".",
+			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
+			plotter.PlotFile("EventsPerSecond"),
+		),
+		ReportName: reportName,
+	}
+}
+
+func getPlotRampLatencyPath(tc collector.TestCaseMetrics, reportName string) *PlotPath {
+	return &PlotPath{
+		Path: filepath.Join(
+			".",
+			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
+			plotter.PlotFile("RampLatency"),
+		),
+		ReportName: reportName,
+	}
+}
+
+func getPlotSLOBurnDownPath(tc collector.TestCaseMetrics, reportName string) *PlotPath {
+	return &PlotPath{
+		Path: filepath.Join(
+			".",
+			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
+			plotter.PlotFile("SLOBurnDown"),
 		),
 		ReportName: reportName,
 	}
 }
 
+func getPlotStageTimeoutPath(tc collector.TestCaseMetrics, reportName string) *PlotPath {
+	return &PlotPath{
+		Path: filepath.Join(
+			".",
+			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
+			plotter.PlotFile("StageTimeout"),
+		),
+		ReportName: reportName,
+	}
+}
+
+func getPlotSnapshotReadinessPath(tc collector.TestCaseMetrics, reportName string) *PlotPath {
+	return &PlotPath{
+		Path: filepath.Join(
+			".",
+			tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)),
+			plotter.PlotFile("SnapshotReadiness"),
+		),
+		ReportName: reportName,
+	}
+}
+
+// ClusterStateFolder is a folder of test case report files cluster state captured at its failure are saved to
+const ClusterStateFolder = "cluster-state"
+
+// ClusterStateDir returns folder cluster state of failed test case of the run is captured into
+func ClusterStateDir(runName string, tc *store.TestCase) (string, error) {
+	dir, err := plotter.GetReportPathDir(runName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, tc.Name+strconv.Itoa(int(tc.ID)), ClusterStateFolder), nil
+}
+
+// getClusterStatePaths returns files of cluster state captured when test case failed, nil if nothing was captured
+func getClusterStatePaths(tc collector.TestCaseMetrics, reportName string) []*PlotPath {
+	dir, err := ClusterStateDir(reportName, &tc.TestCase)
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []*PlotPath
+	for _, e := range entries {
+		paths = append(paths, &PlotPath{
+			Path:       filepath.Join(".", tc.TestCase.Name+strconv.Itoa(int(tc.TestCase.ID)), ClusterStateFolder, e.Name()),
+			ReportName: reportName,
+		})
+	}
+	return paths
+}
+
 func getIterationTimes(reportName string) *PlotPath {
 	return &PlotPath{
 		Path: filepath.Join(
 			".",
-			"IterationTimes.png",
+			plotter.PlotFile("IterationTimes"),
 		),
 		ReportName: reportName,
 	}
@@ -242,10 +387,10 @@ func getIterationTimes(reportName string) *PlotPath {
 
 func getDriverResourceUsage(reportName string) []*PlotPath {
 	var plotPath []*PlotPath
-	cpuUsage := "CpuUsageOverTime.png"
-	memUsage := "MemUsageOverTime.png"
-	filePath := filepath.Dir(PathReport)
-	if fileExists(fmt.Sprintf("%s/%s/%s", filePath, reportName, cpuUsage)) {
+	cpuUsage := plotter.PlotFile("CpuUsageOverTime")
+	memUsage := plotter.PlotFile("MemUsageOverTime")
+	filePath, _ := plotter.GetReportPathDir(reportName)
+	if fileExists(filepath.Join(filePath, cpuUsage)) {
 		plotPath = append(plotPath,
 			&PlotPath{
 				Path: filepath.Join(
@@ -256,7 +401,7 @@ func getDriverResourceUsage(reportName string) []*PlotPath {
 			})
 	}
 
-	if fileExists(fmt.Sprintf("%s/%s/%s", filePath, reportName, memUsage)) {
+	if fileExists(filepath.Join(filePath, memUsage)) {
 		plotPath = append(plotPath,
 			&PlotPath{
 				Path: filepath.Join(
@@ -272,18 +417,20 @@ func getDriverResourceUsage(reportName string) []*PlotPath {
 func getAvgStageTimeOverIterations(reportName string) []*PlotPath {
 	var plotPath []*PlotPath
 	names := []string{
-		"PodCreationOverIterations.png",
-		"PodDeletionOverIterations.png",
-		"PVCAttachmentOverIterations.png",
-		"PVCBindOverIterations.png",
-		"PVCCreationOverIterations.png",
-		"PVCDeletionOverIterations.png",
-		"PVCUnattachmentOverIterations.png",
+		plotter.PlotFile("PodCreationOverIterations"),
+		plotter.PlotFile("PodDeletionOverIterations"),
+		plotter.PlotFile("PVCAttachmentOverIterations"),
+		plotter.PlotFile("PVCBindOverIterations"),
+		plotter.PlotFile("PVCCreationOverIterations"),
+		plotter.PlotFile("PVCDeletionOverIterations"),
+		plotter.PlotFile("PVCUnattachmentOverIterations"),
+		plotter.PlotFile("EphemeralPublishOverIterations"),
+		plotter.PlotFile("EphemeralUnpublishOverIterations"),
 	}
 
-	filePath := filepath.Dir(PathReport)
+	filePath, _ := plotter.GetReportPathDir(reportName)
 	for _, name := range names {
-		if fileExists(fmt.Sprintf("%s/%s/%s", filePath, reportName, name)) {
+		if fileExists(filepath.Join(filePath, name)) {
 			plotPath = append(plotPath,
 				&PlotPath{
 					Path: filepath.Join(
@@ -302,35 +449,35 @@ func getMinMaxEntityOverTimePaths(reportName string) []*PlotPath {
 		{
 			Path: filepath.Join(
 				".",
-				"PodsCreatingOverTime.png",
+				plotter.PlotFile("PodsCreatingOverTime"),
 			),
 			ReportName: reportName,
 		},
 		{
 			Path: filepath.Join(
 				".",
-				"PodsReadyOverTime.png",
+				plotter.PlotFile("PodsReadyOverTime"),
 			),
 			ReportName: reportName,
 		},
 		{
 			Path: filepath.Join(
 				".",
-				"PodsTerminatingOverTime.png",
+				plotter.PlotFile("PodsTerminatingOverTime"),
 			),
 			ReportName: reportName,
 		},
 		{
 			Path: filepath.Join(
 				".",
-				"PvcsCreatingOverTime.png",
+				plotter.PlotFile("PvcsCreatingOverTime"),
 			),
 			ReportName: reportName,
 		},
 		{
 			Path: filepath.Join(
 				".",
-				"PvcsBoundOverTime.png",
+				plotter.PlotFile("PvcsBoundOverTime"),
 			),
 			ReportName: reportName,
 		},
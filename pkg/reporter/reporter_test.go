@@ -17,12 +17,17 @@
 package reporter
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dell/cert-csi/pkg/collector"
 	"github.com/dell/cert-csi/pkg/plotter"
@@ -67,14 +72,20 @@ type ReporterTestSuite struct {
 
 func (suite *ReporterTestSuite) SetupSuite() {
 	plotter.FolderPath = "/.cert-csi/tmp/report-tests/"
-	suite.db = store.NewSQLiteStore("file:testdata/reporter_test.db")
+	// Stores migrate schema of the database they open, so fixture is copied to keep it intact
+	fixture, err := os.ReadFile("testdata/reporter_test.db")
+	suite.Require().NoError(err)
+	dbPath := filepath.Join(suite.T().TempDir(), "reporter_test.db")
+	suite.Require().NoError(os.WriteFile(dbPath, fixture, 0o600))
+	dsn := "file:" + dbPath
+	suite.db = store.NewSQLiteStore(dsn)
 
 	// When the test run is not present in the database
-	noRunIndbsdbs := &store.StorageClassDB{DB: store.NewSQLiteStore("file:testdata/reporter_test.db")}
+	noRunIndbsdbs := &store.StorageClassDB{DB: store.NewSQLiteStore(dsn)}
 
 	// When a test run is present in the database
 	successRunIndbs := &store.StorageClassDB{
-		DB: store.NewSQLiteStore("file:testdata/reporter_test.db"),
+		DB: store.NewSQLiteStore(dsn),
 		TestRun: store.TestRun{
 			Name: "test-run-d6d1f7c8",
 		},
@@ -82,7 +93,7 @@ func (suite *ReporterTestSuite) SetupSuite() {
 
 	// When unsuccessful test run is found in the database
 	unsuccessfulRunIndbs := &store.StorageClassDB{
-		DB: store.NewSQLiteStore("file:testdata/reporter_test.db"),
+		DB: store.NewSQLiteStore(dsn),
 		TestRun: store.TestRun{
 			Name: "unsuccessful-test-run",
 		},
@@ -194,6 +205,445 @@ func (suite *ReporterTestSuite) TestGenerateAllReports() {
 	}
 }
 
+type fakeReporter struct {
+	runNames []string
+}
+
+func (fr *fakeReporter) Generate(runName string, _ *collector.MetricsCollection) error {
+	fr.runNames = append(fr.runNames, runName)
+	return nil
+}
+
+func (suite *ReporterTestSuite) TestRegister() {
+	fake := &fakeReporter{}
+	Register("FAKE", fake)
+	types := RegisteredReportTypes()
+	suite.Equal([]ReportType{HTMLReport, TextReport, "FAKE"}, types)
+
+	err := GenerateReports([]ReportType{"FAKE"}, suite.successRunIndbs)
+	suite.NoError(err)
+	suite.Equal([]string{suite.runName}, fake.runNames)
+
+	err = GenerateReports([]ReportType{"UNKNOWN"}, suite.successRunIndbs)
+	suite.Error(err)
+	err = GenerateReportsFromMultipleDBs([]ReportType{"UNKNOWN"}, suite.successRunIndbs)
+	suite.Error(err)
+}
+
+func (suite *ReporterTestSuite) TestGenerateJSONReport() {
+	err := GenerateReports([]ReportType{JSONReport}, suite.successRunIndbs)
+	suite.NoError(err)
+
+	data, err := os.ReadFile(filepath.Join(PathReport, formatName(suite.runName)+".json"))
+	suite.NoError(err)
+	var report jsonReport
+	suite.NoError(json.Unmarshal(data, &report))
+	suite.Equal(suite.runName, report.Run.Name)
+	suite.Equal(report.Summary.Total, len(report.TestCases))
+}
+
+func (suite *ReporterTestSuite) TestSARIFReport() {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mc := &collector.MetricsCollection{
+		Run: store.TestRun{Name: "run-1", StorageClass: "powerstore", ClusterAddress: "https://10.0.0.1:6443", StartTimestamp: start},
+		TestCasesMetrics: []collector.TestCaseMetrics{
+			{TestCase: store.TestCase{Name: "VolumeIoSuite", Success: true, StartTimestamp: start, EndTimestamp: start.Add(time.Minute)}},
+			{
+				TestCase: store.TestCase{Name: "SnapSuite", ErrorMessage: "snapshot isn't ready", StartTimestamp: start, EndTimestamp: start.Add(2 * time.Minute)},
+				Comparisons: []store.Comparison{
+					{Metric: "Avg PVCBind", BaselineValue: time.Second, CandidateValue: 2 * time.Second, Threshold: 20},
+				},
+			},
+			{TestCase: store.TestCase{Name: "CloneVolumeSuite", Skipped: true, ErrorMessage: "SnapSuite failed"}},
+		},
+		Capabilities: []collector.CapabilityCheck{{Name: "attachRequired", Declared: "false", Observed: "VolumeAttachments created", Mismatch: true}},
+		BackendLeaks: []store.BackendLeak{{Kind: "volume", Name: "csivol-1"}},
+	}
+
+	var buf bytes.Buffer
+	suite.NoError((&SARIFReporter{}).render(&buf, mc))
+	var report sarifLog
+	suite.NoError(json.Unmarshal(buf.Bytes(), &report))
+	suite.Equal("2.1.0", report.Version)
+	suite.Len(report.Runs, 1)
+	run := report.Runs[0]
+	suite.False(run.Invocations[0].ExecutionSuccessful)
+	suite.Equal("2024-05-01T10:02:00.000Z", run.Invocations[0].EndTimeUTC)
+
+	var kinds, levels []string
+	for _, r := range run.Results {
+		kinds = append(kinds, r.RuleID+":"+r.Kind)
+		levels = append(levels, r.Level)
+		suite.Equal("powerstore", r.Locations[0].LogicalLocations[0].Name)
+	}
+	suite.Equal([]string{
+		"VolumeIoSuite:pass", "SnapSuite:fail", "latency-regression:fail", "CloneVolumeSuite:notApplicable",
+		"capability-mismatch:fail", "backend-leak:fail",
+	}, kinds)
+	suite.Equal([]string{"none", "error", "warning", "none", "warning", "error"}, levels)
+	suite.Len(run.Tool.Driver.Rules, 6)
+	suite.Equal("CloneVolumeSuite", run.Tool.Driver.Rules[0].ID)
+}
+
+func (suite *ReporterTestSuite) TestParseReportType() {
+	reportType, multi, err := ParseReportType("json")
+	suite.NoError(err)
+	suite.Equal(JSONReport, reportType)
+	suite.False(multi)
+
+	reportType, multi, err = ParseReportType(" junit")
+	suite.NoError(err)
+	suite.Equal(XMLReport, reportType)
+	suite.True(multi)
+
+	reportType, _, err = ParseReportType("txt")
+	suite.NoError(err)
+	suite.Equal(TextReport, reportType)
+
+	_, _, err = ParseReportType("pdf")
+	suite.Error(err)
+}
+
+func (suite *ReporterTestSuite) TestGetSLOSummaries() {
+	defer func() { SLOs = nil }()
+	start := time.Now()
+	tc := collector.TestCaseMetrics{
+		PVCs: []collector.PVCMetrics{
+			{Added: start, Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: time.Second}},
+			{Added: start.Add(time.Second), Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: time.Minute}},
+		},
+	}
+	suite.Nil(getSLOSummaries(tc))
+
+	SLOs = []collector.SLO{{Stage: "PodCreation", Threshold: time.Second, Target: 99}}
+	suite.Nil(getSLOSummaries(tc))
+
+	SLOs = append(SLOs, collector.SLO{Stage: "PVCBind", Threshold: 10 * time.Second, Target: 99})
+	summaries := getSLOSummaries(tc)
+	suite.Equal(2, len(summaries))
+	suite.Equal(50.0, summaries[1].Percent())
+	suite.True(summaries[1].Degraded)
+}
+
+func (suite *ReporterTestSuite) TestFormatBytes() {
+	suite.Equal("512", formatBytes(512))
+	suite.Equal("1.5Ki", formatBytes(1536))
+	suite.Equal("256.0Mi", formatBytes(256<<20))
+	suite.Equal("3.0Gi", formatBytes(3<<30))
+}
+
+func (suite *ReporterTestSuite) TestGetEntityTimelines() {
+	start := time.Now()
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{ID: 7},
+		PVCs: []collector.PVCMetrics{{
+			PVC: store.Entity{ID: 3, Name: "pvc-1"},
+			Events: []store.Event{
+				{Type: store.PvcBound, Timestamp: start.Add(5 * time.Second)},
+				{Type: store.PvcAdded, Timestamp: start},
+				{Type: store.PvcAttachStarted, Timestamp: start.Add(6 * time.Second)},
+			},
+		}},
+		Pods:         []collector.PodMetrics{{Pod: store.Entity{ID: 4, Name: "pod-1"}}},
+		AuditEntries: []store.AuditEntry{{EntityID: 3, Verb: "delete", Resource: "persistentvolumes", Name: "pvc-uid"}},
+	}
+
+	timelines := getEntityTimelines(tc)
+	suite.Equal(2, len(timelines))
+	pvc := timelines[0]
+	suite.Equal("entity-7-pvc-1", pvc.Anchor)
+	suite.Equal(store.PvcAdded, pvc.Steps[0].Event.Type)
+	suite.Equal(5*time.Second, pvc.Steps[1].Gap)
+	suite.True(pvc.Steps[1].Longest)
+	suite.Equal(6*time.Second, pvc.Duration())
+	suite.Equal("persistentvolumes", pvc.Audit[0].Resource)
+	suite.Equal(time.Duration(0), timelines[1].Duration())
+	suite.Empty(timelines[1].Audit)
+}
+
+func (suite *ReporterTestSuite) TestSampleEntityTimelines() {
+	start := time.Now()
+	pvc := func(name string, d time.Duration, failed bool) collector.PVCMetrics {
+		m := collector.PVCMetrics{
+			PVC:     store.Entity{Name: name},
+			Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: d},
+			Events:  []store.Event{{Type: store.PvcAdded, Timestamp: start}, {Type: store.PvcBound, Timestamp: start.Add(d)}},
+		}
+		if failed {
+			m.Metrics[collector.PVCBind] = -1
+		}
+		return m
+	}
+	tc := collector.TestCaseMetrics{PVCs: []collector.PVCMetrics{
+		pvc("pvc-1", time.Second, false),
+		pvc("pvc-2", 5*time.Second, false),
+		pvc("pvc-3", 0, true),
+		pvc("pvc-4", 3*time.Second, false),
+		pvc("pvc-5", 2*time.Second, false),
+	}}
+
+	MaxDetailedEntities = 2
+	defer func() { MaxDetailedEntities = 0 }()
+	timelines := getEntityTimelines(tc)
+	suite.Len(timelines, 2)
+	suite.Equal("pvc-2", timelines[0].Name)
+	suite.Equal("pvc-3", timelines[1].Name)
+	suite.True(timelines[1].Failed)
+	suite.Equal(3, getOmittedEntities(tc))
+
+	// JSON export keeps every entity
+	suite.Len(getJSONEntities(tc), 5)
+}
+
+func (suite *ReporterTestSuite) TestGetMergedTimeline() {
+	start := time.Now()
+	tc := collector.TestCaseMetrics{
+		TestCase: store.TestCase{StartTimestamp: start},
+		Timeline: []store.TimelineEntry{
+			{Timestamp: start.Add(-time.Second), Source: store.TimelineAudit, Corrected: true},
+			{Timestamp: start.Add(2 * time.Second), Source: store.TimelineObserver},
+		},
+	}
+	steps := getMergedTimeline(tc)
+	suite.Len(steps, 2)
+	suite.Equal(-time.Second, steps[0].Offset)
+	suite.Equal(2*time.Second, steps[1].Offset)
+	suite.Empty(getMergedTimeline(collector.TestCaseMetrics{}))
+}
+
+func (suite *ReporterTestSuite) TestGetSummary() {
+	mc := &collector.MetricsCollection{
+		TestCasesMetrics: []collector.TestCaseMetrics{
+			{
+				TestCase: store.TestCase{Name: "ProvisioningSuite", Success: true},
+				Comparisons: []store.Comparison{
+					{Metric: "Avg PVCBind", BaselineValue: time.Second, CandidateValue: 2 * time.Second, Threshold: 20},
+					{Metric: "Avg PVCAttachment", BaselineValue: time.Second, CandidateValue: time.Second, Threshold: 20},
+				},
+				PVCs: []collector.PVCMetrics{
+					{PVC: store.Entity{Name: "pvc-fast"}, Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: time.Second}},
+					{PVC: store.Entity{Name: "pvc-slow"}, Metrics: map[collector.PVCStage]time.Duration{collector.PVCBind: time.Minute}},
+				},
+			},
+			{
+				TestCase: store.TestCase{Name: "VolumeIoSuite", ErrorMessage: "timed out", Iteration: 7},
+				PVCs: []collector.PVCMetrics{
+					{PVC: store.Entity{Name: "pvc-stuck"}, Metrics: map[collector.PVCStage]time.Duration{collector.PVCAttachment: -time.Second}},
+				},
+				Pods: []collector.PodMetrics{
+					{Pod: store.Entity{Name: "pod-steady"}},
+					{Pod: store.Entity{Name: "pod-crashing"}, Restarts: 3, CrashLoops: 1},
+				},
+			},
+			{
+				TestCase: store.TestCase{Name: "SnapSuite", Skipped: true, ErrorMessage: "skipped because VolumeIoSuite failed"},
+			},
+		},
+	}
+
+	summary := getSummary(mc)
+	suite.Equal(3, summary.Total)
+	suite.Equal(1, summary.Failed)
+	suite.Equal(1, summary.Skipped)
+	suite.Equal(1, getSkippedCountFromMC(mc))
+	suite.Equal(1, getFailedCountFromMC(mc))
+	suite.Equal("timed out", summary.Failures[0].Reason)
+	suite.Contains(summary.Failures[0].Entity, "pvc-stuck")
+	suite.Equal("pvc-slow", summary.WorstLatencies[0].Entity)
+	suite.Equal(1, summary.UnstablePods)
+	suite.Equal(1, summary.Regressions)
+	suite.Equal(7, summary.Failures[0].Iteration)
+	suite.Equal("7", summary.FailedIterations)
+}
+
+func (suite *ReporterTestSuite) TestGetSnapClassSweep() {
+	mc := &collector.MetricsCollection{
+		TestCasesMetrics: []collector.TestCaseMetrics{
+			{
+				TestCase:          store.TestCase{Name: "SnapSuite/snapclass", Success: true, Parameters: "{snapshots: 3, volumeSize; 3Gi, variant: base}"},
+				SnapshotReadiness: &collector.SnapshotReadiness{Ready: []time.Duration{time.Second, 3 * time.Second}},
+			},
+			{
+				TestCase:          store.TestCase{Name: "SnapSuite/snapclass-sweep-1", ErrorMessage: "hashes don't match", Parameters: "{snapshots: 3, volumeSize; 3Gi, variant: retention=1h}"},
+				SnapshotReadiness: &collector.SnapshotReadiness{Censored: []collector.CensoredSnapshot{{Name: "snap", TimedOut: true}}},
+			},
+			{
+				TestCase: store.TestCase{Name: "VolumeIoSuite", Success: true},
+			},
+		},
+	}
+
+	sweep := getSnapClassSweep(mc)
+	suite.Len(sweep, 2)
+	suite.Equal("snapclass", sweep[0].SnapClass)
+	suite.Equal("base", sweep[0].Variant)
+	suite.True(sweep[0].Restored)
+	suite.Equal(2*time.Second, sweep[0].Avg)
+	suite.Equal("retention=1h", sweep[1].Variant)
+	suite.False(sweep[1].Restored)
+	suite.Equal(1, sweep[1].TimedOut)
+
+	// Snapshot suite without sweep isn't compared with anything
+	suite.Nil(getSnapClassSweep(&collector.MetricsCollection{TestCasesMetrics: mc.TestCasesMetrics[:1]}))
+}
+
+func (suite *ReporterTestSuite) TestDriverComparison() {
+	start := time.Now()
+	newCase := func(name, params string, success bool, bind time.Duration) collector.TestCaseMetrics {
+		return collector.TestCaseMetrics{
+			TestCase:     store.TestCase{Name: name, Parameters: params, Success: success, StartTimestamp: start, EndTimestamp: start.Add(time.Minute)},
+			StageMetrics: map[interface{}]collector.DurationOfStage{collector.PVCBind: {Avg: bind}},
+		}
+	}
+	first := &collector.MetricsCollection{
+		Run: store.TestRun{Name: "run-a", StorageClass: "powerstore", Capabilities: `{"driver":"csi-powerstore.dellemc.com"}`},
+		TestCasesMetrics: []collector.TestCaseMetrics{
+			newCase("VolumeIoSuite", "{volumes: 2}", true, 2*time.Second),
+			newCase("VolumeIoSuite", "{volumes: 2}", true, 4*time.Second),
+			newCase("SnapSuite", "{snapshots: 3}", true, time.Second),
+		},
+	}
+	second := &collector.MetricsCollection{
+		Run: store.TestRun{Name: "run-b", StorageClass: "powerflex"},
+		TestCasesMetrics: []collector.TestCaseMetrics{
+			newCase("VolumeIoSuite", "{volumes: 2}", false, 2*time.Second),
+			newCase("SnapSuite", "{snapshots: 5}", true, time.Second),
+		},
+	}
+
+	comparison := getDriverComparison([]*collector.MetricsCollection{first, second})
+	suite.Equal("csi-powerstore.dellemc.com", comparison.Runs[0].Driver)
+	suite.Equal(3, comparison.Runs[0].Passed)
+	suite.Equal(1, comparison.Runs[1].Failed)
+	suite.Len(comparison.Suites, 1)
+	io := comparison.Suites[0]
+	suite.Equal([]int{2, 0}, io.Passed)
+	suite.Equal([]time.Duration{time.Minute, time.Minute}, io.Duration)
+	suite.Equal([]time.Duration{3 * time.Second, 2 * time.Second}, io.Stages[0].Avg)
+	suite.Equal(1, io.Stages[0].Best)
+	suite.Equal(1, comparison.Runs[1].Wins)
+	suite.Equal([]string{"SnapSuite {snapshots: 3}", "SnapSuite {snapshots: 5}"}, comparison.Skipped)
+
+	suite.NoError((&ComparisonReporter{}).MultiGenerate([]*collector.MetricsCollection{first, second}))
+}
+
+func (suite *ReporterTestSuite) TestRedact() {
+	newCollection := func() *collector.MetricsCollection {
+		return &collector.MetricsCollection{
+			Run: store.TestRun{Name: "run", ClusterAddress: "https://api.acme.corp:6443", StorageClass: "powerstore"},
+			TestCasesMetrics: []collector.TestCaseMetrics{
+				{
+					TestCase:     store.TestCase{Name: "VolumeIoSuite", ErrorMessage: `pods "pod-1" is forbidden: node "worker-12" in namespace "acme-prod" unreachable at 10.1.2.3 and fd00::12`},
+					MountChecks:  []store.MountCheck{{Node: "worker-1", Message: "mounted on worker-1"}},
+					NodeWarnings: []collector.NodeWarning{{Node: "worker-12", Message: "I/O error from 10.1.2.3"}},
+				},
+			},
+		}
+	}
+	r := newRedactor()
+	mc := newCollection()
+	r.redact(mc)
+
+	suite.Equal("https://cluster-1:6443", mc.Run.ClusterAddress)
+	suite.Equal("powerstore", mc.Run.StorageClass)
+	tc := mc.TestCasesMetrics[0]
+	suite.Equal(`pods "pod-1" is forbidden: node "node-2" in namespace "namespace-1" unreachable at 198.18.0.1 and 2001:db8::1`, tc.TestCase.ErrorMessage)
+	suite.Equal("node-1", tc.MountChecks[0].Node)
+	suite.Equal("mounted on node-1", tc.MountChecks[0].Message)
+	suite.Equal("node-2", tc.NodeWarnings[0].Node)
+	suite.Equal("I/O error from 198.18.0.1", tc.NodeWarnings[0].Message)
+
+	// Pseudonyms are stable across collections of the same report
+	other := newCollection()
+	r.redact(other)
+	suite.Equal(mc, other)
+}
+
+func (suite *ReporterTestSuite) TestMultiTabularContention() {
+	mc, err := collector.NewMetricsCollector(suite.db).Collect(suite.runName)
+	suite.NoError(err)
+	other := *mc
+	other.Run.StorageClass = "other-" + mc.Run.StorageClass
+
+	tr := &TabularReporter{}
+	suite.NoError(tr.MultiGenerate([]*collector.MetricsCollection{mc, &other}))
+}
+
+func (suite *ReporterTestSuite) TestServer() {
+	server := httptest.NewServer(NewServer(":0", suite.db).Handler())
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		suite.NoError(err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		suite.NoError(err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/")
+	suite.Equal(http.StatusOK, code)
+	suite.Contains(body, "runs/"+suite.runName+"/")
+	suite.NotContains(body, "database")
+
+	code, body = get("/runs/" + suite.runName + "/report.json")
+	suite.Equal(http.StatusOK, code)
+	suite.Contains(body, suite.runName)
+
+	code, _ = get("/runs/" + suite.runName + "/")
+	suite.Equal(http.StatusOK, code)
+
+	code, _ = get("/runs/no-such-run/")
+	suite.Equal(http.StatusNotFound, code)
+
+	code, _ = get("/runs/" + suite.runName + "/../../../etc/passwd")
+	suite.NotEqual(http.StatusOK, code)
+
+	code, _ = get("/database")
+	suite.Equal(http.StatusNotFound, code)
+}
+
+func (suite *ReporterTestSuite) TestSeries() {
+	started := time.Now()
+	newCollection := func(run string, start time.Time, declared string, bind time.Duration) *collector.MetricsCollection {
+		return &collector.MetricsCollection{
+			Run:          store.TestRun{Name: run, StartTimestamp: start},
+			Capabilities: []collector.CapabilityCheck{{Name: "AllowVolumeExpansion", Declared: declared}},
+			TestCasesMetrics: []collector.TestCaseMetrics{
+				{
+					TestCase:     store.TestCase{Name: "ProvisioningSuite", Success: true},
+					StageMetrics: map[interface{}]collector.DurationOfStage{collector.PVCBind: {Avg: bind}},
+				},
+				{TestCase: store.TestCase{Name: "VolumeIoSuite", ErrorMessage: "timed out"}},
+			},
+		}
+	}
+	// Versions are ordered by start of their runs, not by order they were linked in
+	series := newSeries("powerstore", []string{"2.10.0", "2.9.0"}, []*collector.MetricsCollection{
+		newCollection("run-2", started.Add(time.Hour), "true", 3*time.Second),
+		newCollection("run-1", started, "false", 2*time.Second),
+	})
+
+	suite.Equal("2.9.0", series.Versions[0].DriverVersion)
+	suite.Equal("run-2", series.Versions[1].Run.Name)
+	suite.Equal(1, series.Versions[0].Passed)
+	suite.Equal(1, series.Versions[0].Failed)
+	suite.Equal([]string{"false", "true"}, series.Capabilities[0].Declared)
+	suite.True(series.Capabilities[0].Changed)
+	suite.Len(series.Metrics, 1)
+	suite.Equal([]time.Duration{2 * time.Second, 3 * time.Second}, series.Metrics[0].Values)
+	suite.InDelta(50.0, series.Metrics[0].Change, 0.001)
+
+	var b bytes.Buffer
+	suite.NoError(renderSeries(&b, series))
+	suite.Contains(b.String(), "AllowVolumeExpansion (changed)")
+	suite.Contains(b.String(), "50.0%")
+
+	_, err := CollectSeries(suite.db, "no-such-series")
+	suite.ErrorIs(err, ErrUnknownSeries)
+}
+
 func TestReporterTestSuite(t *testing.T) {
 	suite.Run(t, new(ReporterTestSuite))
 }
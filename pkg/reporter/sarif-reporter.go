@@ -0,0 +1,238 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dell/cert-csi/pkg/collector"
+)
+
+// SARIFReport represents results in SARIF 2.1.0 format consumed by security and compliance platforms
+const SARIFReport ReportType = "SARIF"
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifResultsVersion is version of properties cert-csi adds to SARIF objects, bumped when they change incompatibly
+	sarifResultsVersion = "1"
+)
+
+// ToolVersion is version of cert-csi reported as version of the tool that produced results
+var ToolVersion = "dev"
+
+// SARIFReporter is used to create and manage SARIF report, every test case is a result of a rule named after its
+// suite, capability mismatches, regressions and backend leaks are reported as results of their own rules
+type SARIFReporter struct{}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool              `json:"tool"`
+	AutomationDetails sarifAutomationDetails `json:"automationDetails"`
+	Invocations       []sarifInvocation      `json:"invocations"`
+	Results           []sarifResult          `json:"results"`
+	Properties        map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	StartTimeUTC        string `json:"startTimeUtc,omitempty"`
+	EndTimeUTC          string `json:"endTimeUtc,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// Rules of results which aren't test cases
+const (
+	capabilityRule = "capability-mismatch"
+	regressionRule = "latency-regression"
+	leakRule       = "backend-leak"
+)
+
+var sarifRuleDescriptions = map[string]string{
+	capabilityRule: "Capability declared by driver matches behavior observed during run",
+	regressionRule: "Stage latency doesn't regress compared to baseline run",
+	leakRule:       "Volumes and snapshots created by run are removed from storage backend",
+}
+
+// Generate writes SARIF report of metrics collection
+func (sr *SARIFReporter) Generate(runName string, mc *collector.MetricsCollection) error {
+	sarifFile, _, err := getReportFile(runName, "sarif")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := sarifFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	return sr.render(sarifFile, mc)
+}
+
+// render writes SARIF report of metrics collection to w
+func (sr *SARIFReporter) render(w io.Writer, mc *collector.MetricsCollection) error {
+	location := []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+		Name:               mc.Run.StorageClass,
+		FullyQualifiedName: mc.Run.ClusterAddress + "/storageclasses/" + mc.Run.StorageClass,
+		Kind:               "resource",
+	}}}}
+	rules := make(map[string]string)
+	var results []sarifResult
+	add := func(rule, description, kind, level, message string, properties map[string]interface{}) {
+		if _, ok := rules[rule]; !ok {
+			rules[rule] = description
+		}
+		results = append(results, sarifResult{
+			RuleID:     rule,
+			Kind:       kind,
+			Level:      level,
+			Message:    sarifMessage{Text: message},
+			Locations:  location,
+			Properties: properties,
+		})
+	}
+
+	successful := true
+	end := mc.Run.StartTimestamp
+	for _, tc := range mc.TestCasesMetrics {
+		if tc.TestCase.EndTimestamp.After(end) {
+			end = tc.TestCase.EndTimestamp
+		}
+		properties := map[string]interface{}{
+			"parameters": tc.TestCase.Parameters,
+			"durationMs": toMilliseconds(tc.TestCase.EndTimestamp.Sub(tc.TestCase.StartTimestamp)),
+		}
+		description := "Suite " + tc.TestCase.Name + " succeeds"
+		switch {
+		case tc.TestCase.Skipped:
+			add(tc.TestCase.Name, description, "notApplicable", "none", "Skipped: "+tc.TestCase.ErrorMessage, properties)
+		case tc.TestCase.Success:
+			add(tc.TestCase.Name, description, "pass", "none", "Suite succeeded", properties)
+		default:
+			successful = false
+			add(tc.TestCase.Name, description, "fail", "error", "Suite failed: "+tc.TestCase.ErrorMessage, properties)
+		}
+		for _, c := range tc.Comparisons {
+			if c.Regressed() {
+				add(regressionRule, sarifRuleDescriptions[regressionRule], "fail", "warning",
+					fmt.Sprintf("%s of %s regressed by %s", c.Metric, tc.TestCase.Name, formatDifference(c)), nil)
+			}
+		}
+	}
+	for _, c := range mc.Capabilities {
+		if c.Mismatch {
+			add(capabilityRule, sarifRuleDescriptions[capabilityRule], "fail", "warning",
+				fmt.Sprintf("%s declared as %s, observed %s", c.Name, c.Declared, c.Observed), nil)
+		}
+	}
+	for _, leak := range mc.BackendLeaks {
+		add(leakRule, sarifRuleDescriptions[leakRule], "fail", "error", fmt.Sprintf("%s %s left behind on backend", leak.Kind, leak.Name), nil)
+	}
+	if results == nil {
+		results = []sarifResult{}
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for id := range rules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	sarifRules := make([]sarifRule, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		sarifRules = append(sarifRules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: rules[id]}})
+	}
+
+	invocation := sarifInvocation{ExecutionSuccessful: successful && !mc.Run.Aborted()}
+	if !mc.Run.StartTimestamp.IsZero() {
+		invocation.StartTimeUTC = mc.Run.StartTimestamp.UTC().Format("2006-01-02T15:04:05.000Z")
+		invocation.EndTimeUTC = end.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+	report := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "cert-csi",
+				Version:        ToolVersion,
+				InformationURI: "https://github.com/dell/cert-csi",
+				Rules:          sarifRules,
+			}},
+			AutomationDetails: sarifAutomationDetails{ID: "cert-csi/" + mc.Run.Name},
+			Invocations:       []sarifInvocation{invocation},
+			Results:           results,
+			Properties: map[string]interface{}{
+				"resultsVersion": sarifResultsVersion,
+				"storageClass":   mc.Run.StorageClass,
+				"cluster":        mc.Run.ClusterAddress,
+				"seed":           mc.Run.Seed,
+			},
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,258 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// SeriesChangeThreshold is percent stage latency may change by across series before the change is highlighted
+var SeriesChangeThreshold = 20.0
+
+// ErrUnknownSeries is returned when certification series has no runs linked into it
+var ErrUnknownSeries = errors.New("unknown certification series")
+
+// SeriesVersion is a run of certification series testing a single driver version
+type SeriesVersion struct {
+	DriverVersion string
+	Run           store.TestRun
+	Passed        int
+	Failed        int
+	Skipped       int
+}
+
+// SeriesCapability is a capability driver declared with every version of the series, empty if version didn't declare it
+type SeriesCapability struct {
+	Name     string
+	Declared []string
+	Changed  bool
+	// Mismatch marks versions whose observed behavior didn't match declared capability
+	Mismatch []bool
+}
+
+// SeriesMetric is average latency of a stage of a suite with every version of the series, zero if version didn't measure it
+type SeriesMetric struct {
+	Suite  string
+	Stage  string
+	Values []time.Duration
+	// Change is percent last measured value differs from the first one
+	Change float64
+}
+
+// Series shows how capabilities and metrics of a driver evolved across versions tested in certification series
+type Series struct {
+	Name         string
+	Versions     []SeriesVersion
+	Capabilities []SeriesCapability
+	Metrics      []SeriesMetric
+}
+
+// CollectSeries collects metrics of runs linked into certification series, versions are ordered by start of their runs
+func CollectSeries(db store.Store, name string) (*Series, error) {
+	links, err := db.GetSeriesRuns(store.Conditions{"series": name}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSeries, name)
+	}
+	c := collector.NewMetricsCollector(db)
+	versions := make([]string, 0, len(links))
+	mcs := make([]*collector.MetricsCollection, 0, len(links))
+	for _, link := range links {
+		runs, err := db.GetTestRuns(store.Conditions{"id": link.RunID}, "", 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 {
+			return nil, fmt.Errorf("run %d of series %s doesn't exist", link.RunID, name)
+		}
+		mc, err := c.Collect(runs[0].Name)
+		if err != nil {
+			return nil, fmt.Errorf("can't collect metrics of run %s: %w", runs[0].Name, err)
+		}
+		versions = append(versions, link.DriverVersion)
+		mcs = append(mcs, mc)
+	}
+	return newSeries(name, versions, mcs), nil
+}
+
+// newSeries builds series of metrics collections of runs testing driver versions, ordered by start of their runs
+func newSeries(name string, versions []string, mcs []*collector.MetricsCollection) *Series {
+	order := make([]int, len(mcs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return mcs[order[i]].Run.StartTimestamp.Before(mcs[order[j]].Run.StartTimestamp)
+	})
+
+	s := &Series{Name: name}
+	capabilities := make(map[string]*SeriesCapability)
+	var capabilityNames []string
+	metrics := make(map[[2]string]*SeriesMetric)
+	var metricKeys [][2]string
+	for v, i := range order {
+		mc := mcs[i]
+		s.Versions = append(s.Versions, SeriesVersion{
+			DriverVersion: versions[i],
+			Run:           mc.Run,
+			Passed:        getPassedCountFromMC(mc),
+			Failed:        getFailedCountFromMC(mc),
+			Skipped:       getSkippedCountFromMC(mc),
+		})
+
+		for _, check := range mc.Capabilities {
+			c, ok := capabilities[check.Name]
+			if !ok {
+				c = &SeriesCapability{Name: check.Name, Declared: make([]string, len(mcs)), Mismatch: make([]bool, len(mcs))}
+				capabilities[check.Name] = c
+				capabilityNames = append(capabilityNames, check.Name)
+			}
+			c.Declared[v] = check.Declared
+			c.Mismatch[v] = check.Mismatch
+		}
+
+		for key, avg := range averageStages(mc.TestCasesMetrics) {
+			m, ok := metrics[key]
+			if !ok {
+				m = &SeriesMetric{Suite: key[0], Stage: key[1], Values: make([]time.Duration, len(mcs))}
+				metrics[key] = m
+				metricKeys = append(metricKeys, key)
+			}
+			m.Values[v] = avg
+		}
+	}
+
+	for _, name := range capabilityNames {
+		c := capabilities[name]
+		for _, declared := range c.Declared {
+			c.Changed = c.Changed || declared != c.Declared[0]
+		}
+		s.Capabilities = append(s.Capabilities, *c)
+	}
+
+	sort.Slice(metricKeys, func(i, j int) bool {
+		if metricKeys[i][0] != metricKeys[j][0] {
+			return metricKeys[i][0] < metricKeys[j][0]
+		}
+		return metricKeys[i][1] < metricKeys[j][1]
+	})
+	for _, key := range metricKeys {
+		m := metrics[key]
+		var first, last time.Duration
+		for _, value := range m.Values {
+			if value == 0 {
+				continue
+			}
+			if first == 0 {
+				first = value
+			}
+			last = value
+		}
+		if first != 0 {
+			m.Change = float64(last-first) / float64(first) * 100
+		}
+		s.Metrics = append(s.Metrics, *m)
+	}
+	return s
+}
+
+// averageStages averages stage latencies of successful test cases by suite and stage name
+func averageStages(testCases []collector.TestCaseMetrics) map[[2]string]time.Duration {
+	sums := make(map[[2]string]time.Duration)
+	counts := make(map[[2]string]int)
+	for _, tc := range testCases {
+		if !tc.TestCase.Success {
+			continue
+		}
+		for stage, d := range tc.StageMetrics {
+			key := [2]string{tc.TestCase.Name, fmt.Sprint(stage)}
+			sums[key] += d.Avg
+			counts[key]++
+		}
+	}
+	averages := make(map[[2]string]time.Duration, len(sums))
+	for key, sum := range sums {
+		averages[key] = sum / time.Duration(counts[key])
+	}
+	return averages
+}
+
+// GenerateSeriesReport generates HTML report of certification series showing capability and metric evolution
+// across its driver versions
+func GenerateSeriesReport(db store.Store, name string) error {
+	series, err := CollectSeries(db, name)
+	if err != nil {
+		return err
+	}
+	htmlFile, _, err := getReportFile("series-"+name, "html")
+	if err != nil {
+		return err
+	}
+	defer htmlFile.Close()
+
+	if err := addPathToFile("report.path", "SERIES_REPORT_PATH", htmlFile.Name()); err != nil {
+		return err
+	}
+	return renderSeries(htmlFile, series)
+}
+
+// renderSeries writes HTML report of series to w
+func renderSeries(w io.Writer, series *Series) error {
+	fm := template.FuncMap{
+		"formatDuration": formatSeriesDuration,
+		"changeColor":    changeColor,
+	}
+
+	templateData, err := embedFS.ReadFile("templates/series-template.html")
+	if err != nil {
+		return err
+	}
+
+	report, err := template.New("series-template").Funcs(fm).Parse(string(templateData))
+	if err != nil {
+		return err
+	}
+	return report.Execute(w, series)
+}
+
+func formatSeriesDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// changeColor returns color of stage latency change, slowdowns over threshold are red and speedups over it green
+func changeColor(change float64) string {
+	switch {
+	case change > SeriesChangeThreshold:
+		return "red"
+	case change < -SeriesChangeThreshold:
+		return "green"
+	}
+	return "black"
+}
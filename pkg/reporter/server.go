@@ -0,0 +1,173 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package reporter
+
+import (
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/httpserver"
+	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Server renders reports of runs stored in database over HTTP
+type Server struct {
+	*httpserver.Server
+	db store.Store
+	// DatabasePath is a file database can be downloaded from, download is disabled if empty
+	DatabasePath string
+
+	// Plots of all runs are written to report directories, only one report is rendered at a time
+	mutex sync.Mutex
+}
+
+// NewServer creates a Server, address without host is bound to localhost
+func NewServer(address string, db store.Store) *Server {
+	s := &Server{db: db}
+	s.Server = httpserver.New("Report", address, s.Handler())
+	return s
+}
+
+// Handler returns handler serving index of runs, their reports, plots and raw data
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.serveIndex)
+	mux.HandleFunc("GET /runs/{run}/{$}", s.serveRun(s.serveHTML))
+	mux.HandleFunc("GET /runs/{run}/report.json", s.serveRun(s.serveJSON))
+	mux.HandleFunc("GET /runs/{run}/{file...}", s.serveRun(s.servePlot))
+	mux.HandleFunc("GET /database", s.serveDatabase)
+	return mux
+}
+
+// runs returns all runs of database, most recent first
+func (s *Server) runs() ([]store.TestRun, error) {
+	runs, err := s.db.GetTestRuns(store.Conditions{}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTimestamp.After(runs[j].StartTimestamp)
+	})
+	return runs, nil
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, _ *http.Request) {
+	runs, err := s.runs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	templateData, err := embedFS.ReadFile("templates/server-index.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	index, err := template.New("index.html").Parse(string(templateData))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	data := struct {
+		Runs     []store.TestRun
+		Database bool
+	}{runs, s.DatabasePath != ""}
+	if err := index.Execute(w, data); err != nil {
+		log.Errorf("Can't render index; error=%v", err)
+	}
+}
+
+// serveRun looks run named by request path up, names are matched in memory, so they never reach SQL
+func (s *Server) serveRun(serve func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		runs, err := s.runs()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		name := r.PathValue("run")
+		for _, run := range runs {
+			if run.Name == name {
+				serve(w, r, name)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// collect gathers metrics of the run, metrics are collected on every request so runs in progress are up to date
+func (s *Server) collect(runName string) (*collector.MetricsCollection, error) {
+	return collector.NewMetricsCollector(s.db).Collect(runName)
+}
+
+func (s *Server) serveHTML(w http.ResponseWriter, _ *http.Request, runName string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	mc, err := s.collect(runName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	generatePlots(runName, mc)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	hr := &HTMLReporter{}
+	if err := hr.render(w, mc); err != nil {
+		log.Errorf("Can't render report of %s; error=%v", runName, err)
+	}
+}
+
+func (s *Server) serveJSON(w http.ResponseWriter, _ *http.Request, runName string) {
+	mc, err := s.collect(runName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	jr := &JSONReporter{}
+	if err := jr.render(w, mc); err != nil {
+		log.Errorf("Can't render JSON report of %s; error=%v", runName, err)
+	}
+}
+
+// servePlot serves file from report directory of the run, paths can't escape it
+func (s *Server) servePlot(w http.ResponseWriter, r *http.Request, runName string) {
+	dir, err := plotter.GetReportPathDir(runName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	http.ServeFile(w, r, filepath.Join(dir, filepath.Clean("/"+r.PathValue("file"))))
+}
+
+func (s *Server) serveDatabase(w http.ResponseWriter, r *http.Request) {
+	if s.DatabasePath == "" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename="+filepath.Base(s.DatabasePath))
+	http.ServeFile(w, r, s.DatabasePath)
+}
@@ -0,0 +1,168 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+)
+
+// worstLatenciesNumber is how many slowest entity stages are listed in summary
+const worstLatenciesNumber = 3
+
+// Summary is a failure-first overview of a run, shown before details of every test case
+type Summary struct {
+	Total  int
+	Failed int
+	// Skipped test cases aren't failures, suites they depend on are
+	Skipped        int
+	Failures       []FailureSummary
+	WorstLatencies []LatencySummary
+	// UnstablePods is number of pods whose containers restarted, even if test case passed
+	UnstablePods int
+	// Regressions is number of stage latencies which regressed compared to baseline run
+	Regressions int
+	// FailedIterations are numbers of iterations with failed test cases, formatted the way --only takes them
+	FailedIterations string
+}
+
+// FailureSummary describes why a test case failed
+type FailureSummary struct {
+	TestCase  string
+	Iteration int
+	Reason    string
+	// Entity is the first PVC or Pod which didn't finish one of its stages, empty if all of them did
+	Entity string
+}
+
+// LatencySummary is a duration of a single stage of an entity
+type LatencySummary struct {
+	TcID     int64
+	TestCase string
+	Entity   string
+	Stage    string
+	Duration time.Duration
+}
+
+func getSummary(mc *collector.MetricsCollection) Summary {
+	var s Summary
+	for _, tc := range mc.TestCasesMetrics {
+		s.Total++
+		if tc.TestCase.Skipped {
+			s.Skipped++
+		} else if !tc.TestCase.Success {
+			s.Failed++
+			s.Failures = append(s.Failures, FailureSummary{
+				TestCase:  tc.TestCase.Name,
+				Iteration: tc.TestCase.Iteration,
+				Reason:    failureReason(tc),
+				Entity:    unfinishedEntity(tc),
+			})
+		}
+
+		for _, pvc := range tc.PVCs {
+			for stage, d := range pvc.Metrics {
+				s.WorstLatencies = append(s.WorstLatencies, LatencySummary{tc.TestCase.ID, tc.TestCase.Name, pvc.PVC.Name, string(stage), d})
+			}
+		}
+		s.UnstablePods += len(getUnstablePods(tc))
+		for _, cmp := range tc.Comparisons {
+			if cmp.Regressed() {
+				s.Regressions++
+			}
+		}
+		for _, pod := range tc.Pods {
+			for stage, d := range pod.Metrics {
+				s.WorstLatencies = append(s.WorstLatencies, LatencySummary{tc.TestCase.ID, tc.TestCase.Name, pod.Pod.Name, string(stage), d})
+			}
+		}
+	}
+
+	s.FailedIterations = failedIterations(s.Failures)
+	sort.SliceStable(s.WorstLatencies, func(i, j int) bool {
+		return s.WorstLatencies[i].Duration > s.WorstLatencies[j].Duration
+	})
+	if len(s.WorstLatencies) > worstLatenciesNumber {
+		s.WorstLatencies = s.WorstLatencies[:worstLatenciesNumber]
+	}
+	return s
+}
+
+// failedIterations returns sorted numbers of iterations failures happened in, test cases of older runs have no iteration
+func failedIterations(failures []FailureSummary) string {
+	seen := make(map[int]bool)
+	var iterations []int
+	for _, f := range failures {
+		if f.Iteration > 0 && !seen[f.Iteration] {
+			seen[f.Iteration] = true
+			iterations = append(iterations, f.Iteration)
+		}
+	}
+	sort.Ints(iterations)
+	fields := make([]string, 0, len(iterations))
+	for _, iter := range iterations {
+		fields = append(fields, strconv.Itoa(iter))
+	}
+	return strings.Join(fields, ",")
+}
+
+func failureReason(tc collector.TestCaseMetrics) string {
+	if tc.TestCase.ErrorMessage != "" {
+		return tc.TestCase.ErrorMessage
+	}
+	for _, a := range tc.AssertionResults {
+		if !a.Passed {
+			return fmt.Sprintf("assertion %s failed: actual %s, expected %s", a.Name, a.Actual, a.Expected)
+		}
+	}
+	return "unknown"
+}
+
+// unfinishedEntity finds an entity whose stage has started but never ended,
+// such stages have negative duration as end timestamp is zero
+func unfinishedEntity(tc collector.TestCaseMetrics) string {
+	for _, pvc := range tc.PVCs {
+		var stages []string
+		for stage, d := range pvc.Metrics {
+			if d < 0 {
+				stages = append(stages, string(stage))
+			}
+		}
+		if len(stages) != 0 {
+			sort.Strings(stages)
+			return fmt.Sprintf("PVC %s (%s)", pvc.PVC.Name, stages[0])
+		}
+	}
+	for _, pod := range tc.Pods {
+		var stages []string
+		for stage, d := range pod.Metrics {
+			if d < 0 {
+				stages = append(stages, string(stage))
+			}
+		}
+		if len(stages) != 0 {
+			sort.Strings(stages)
+			return fmt.Sprintf("Pod %s (%s)", pod.Pod.Name, stages[0])
+		}
+	}
+	return ""
+}
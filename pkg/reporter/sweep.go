@@ -0,0 +1,71 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+)
+
+// SnapClassVariant is a test case of snapshot class sweep, compared with test cases of other snapshot classes
+type SnapClassVariant struct {
+	TcID      int64
+	SnapClass string
+	// Variant lists parameter overrides of snapshot class, base for the class sweep copies were made from
+	Variant string
+	// Restored is set if snapshot was restored with data test case wrote, otherwise Error says what failed
+	Restored bool
+	Error    string
+	Ready    int
+	TimedOut int
+	Avg      time.Duration
+	Max      time.Duration
+}
+
+// getSnapClassSweep returns test cases of snapshot class sweep in order they ran, nil if run swept no snapshot classes
+func getSnapClassSweep(mc *collector.MetricsCollection) []SnapClassVariant {
+	var variants []SnapClassVariant
+	for _, tc := range mc.TestCasesMetrics {
+		snapClass, ok := strings.CutPrefix(tc.TestCase.Name, suites.SnapSweepPrefix)
+		if !ok || tc.TestCase.Skipped {
+			continue
+		}
+		v := SnapClassVariant{
+			TcID:      tc.TestCase.ID,
+			SnapClass: snapClass,
+			Restored:  tc.TestCase.Success,
+			Error:     tc.TestCase.ErrorMessage,
+		}
+		if _, variant, found := strings.Cut(tc.TestCase.Parameters, "variant: "); found {
+			v.Variant = strings.TrimSuffix(variant, "}")
+		}
+		if sr := tc.SnapshotReadiness; sr != nil {
+			v.Ready = len(sr.Ready)
+			v.TimedOut = sr.TimedOut()
+			v.Avg = sr.Avg()
+			v.Max = sr.Max()
+		}
+		variants = append(variants, v)
+	}
+	if len(variants) < 2 {
+		return nil
+	}
+	return variants
+}
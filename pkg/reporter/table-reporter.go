@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/plotter"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -44,21 +45,23 @@ var (
 // MultiGenerate generates reports for multiple metrics collections
 func (tr *TabularReporter) MultiGenerate(mcs []*collector.MetricsCollection) error {
 	fm := template.FuncMap{
-		"formatName":           formatName,
-		"getResultStatus":      tr.getResultStatus,
-		"getColorResultStatus": tr.getColorResultStatus,
-		"getCurrentDate":       tr.getCurrentDate,
-		"getSlNo":              tr.getSlNo,
-		"getCustomReportName":  tr.getCustomReportName,
-		"getPassedCount":       tr.getPassedCount,
-		"getFailedCount":       tr.getFailedCount,
-		"getSkippedCount":      tr.getSkippedCount,
-		"getBuildName":         tr.getBuildName,
-		"getArrays":            tr.getArrays,
-		"inc":                  inc,
-		"getTestDuration":      getTestDuration,
-		"getFailedCountFromMC": getFailedCountFromMC,
-		"getPassedCountFromMC": getPassedCountFromMC,
+		"formatName":            formatName,
+		"getResultStatus":       tr.getResultStatus,
+		"getColorResultStatus":  tr.getColorResultStatus,
+		"getCurrentDate":        tr.getCurrentDate,
+		"getSlNo":               tr.getSlNo,
+		"getCustomReportName":   tr.getCustomReportName,
+		"getPassedCount":        tr.getPassedCount,
+		"getFailedCount":        tr.getFailedCount,
+		"getSkippedCount":       tr.getSkippedCount,
+		"getBuildName":          tr.getBuildName,
+		"getArrays":             tr.getArrays,
+		"inc":                   inc,
+		"getTestDuration":       getTestDuration,
+		"getFailedCountFromMC":  getFailedCountFromMC,
+		"getSkippedCountFromMC": getSkippedCountFromMC,
+		"getPassedCountFromMC":  getPassedCountFromMC,
+		"getContention":         collector.GetContention,
 	}
 
 	templateData, err := embedFS.ReadFile("templates/multi-tabular-html-template.html")
@@ -71,7 +74,7 @@ func (tr *TabularReporter) MultiGenerate(mcs []*collector.MetricsCollection) err
 		return err
 	}
 
-	htmlFile, _, err := getReportFile("tabular", "html")
+	htmlFile, _, err := getReportFileIn(reportRoot(mcs), "tabular", "html")
 	if err != nil {
 		return err
 	}
@@ -129,7 +132,7 @@ func (tr *TabularReporter) Generate(_ string, mc *collector.MetricsCollection) e
 	// Write a report to file
 	currentTime := time.Now()
 
-	htmFile, _, err := getReportFile("tabular-report-"+currentTime.Format("2006-01-02_15_04_05"), "html")
+	htmFile, _, err := getReportFileIn(plotter.ReportRoot(mc.Run.Name), "tabular-report-"+currentTime.Format("2006-01-02_15_04_05"), "html")
 	if err != nil {
 		return err
 	}
@@ -150,10 +153,10 @@ func updateTestCounts(mc *collector.MetricsCollection) {
 	for i := 0; i < len(mc.TestCasesMetrics); i++ {
 		if mc.TestCasesMetrics[i].TestCase.Success {
 			passedCount++
-		} else if !mc.TestCasesMetrics[i].TestCase.Success {
-			failedCount++
-		} else {
+		} else if mc.TestCasesMetrics[i].TestCase.Skipped {
 			skippedCount++
+		} else {
+			failedCount++
 		}
 	}
 }
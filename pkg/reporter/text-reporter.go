@@ -38,12 +38,28 @@ func (tr *TextReporter) Generate(runName string, mc *collector.MetricsCollection
 		"formatName":                      formatName,
 		"inc":                             inc,
 		"getResultStatus":                 tr.getResultStatus,
+		"formatDifference":                formatDifference,
+		"getSummary":                      getSummary,
+		"getSnapClassSweep":               getSnapClassSweep,
+		"getBackgroundLoad":               getBackgroundLoad,
+		"severity":                        severity,
 		"shouldBeIncluded":                shouldBeIncluded,
+		"getUnstablePods":                 getUnstablePods,
+		"getLatencyDistributions":         getLatencyDistributions,
+		"summarizeExpansions":             collector.SummarizeExpansions,
+		"getStageHeadrooms":               getStageHeadrooms,
+		"formatBytes":                     formatBytes,
+		"getClusterStatePaths":            getClusterStatePaths,
+		"getPlotStageTimeoutPath":         getPlotStageTimeoutPath,
+		"getPlotSnapshotReadinessPath":    getPlotSnapshotReadinessPath,
 		"colorYellow":                     colorYellow,
 		"colorCyan":                       colorCyan,
+		"colorRed":                        colorRed,
 		"getPlotStageMetricHistogramPath": getPlotStageMetricHistogramPath,
 		"getPlotStageBoxPath":             getPlotStageBoxPath,
 		"getPlotEntityOverTimePath":       getPlotEntityOverTimePath,
+		"getPlotEventsPerSecondPath":      getPlotEventsPerSecondPath,
+		"getPlotRampLatencyPath":          getPlotRampLatencyPath,
 		"getMinMaxEntityOverTimePaths":    getMinMaxEntityOverTimePaths,
 	}
 
@@ -78,6 +94,10 @@ func (tr *TextReporter) Generate(runName string, mc *collector.MetricsCollection
 		return err
 	}
 
+	// Colors are only meant for terminal, keep the file readable with any viewer
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
 	if err := report.Execute(txtFile, mc); err != nil {
 		return err
 	}
@@ -92,10 +112,22 @@ func (tr *TextReporter) getResultStatus(result bool) string {
 	return color.RedString("FAILURE")
 }
 
+// severity returns a marker which makes failures easy to spot when scanning the report
+func severity(passed bool) string {
+	if passed {
+		return color.GreenString("[ OK ]")
+	}
+	return color.RedString("[FAIL]")
+}
+
 func colorYellow(colorable interface{}) string {
 	return color.HiYellowString(fmt.Sprint(colorable))
 }
 
+func colorRed(colorable interface{}) string {
+	return color.HiRedString(fmt.Sprint(colorable))
+}
+
 func colorCyan(colorable interface{}) string {
 	return color.CyanString(fmt.Sprint(colorable))
 }
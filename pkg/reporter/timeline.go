@@ -0,0 +1,167 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// TimelineStep is an event of entity with time passed since the first and the previous events of that entity
+type TimelineStep struct {
+	Event  store.Event
+	Offset time.Duration
+	Gap    time.Duration
+	// Longest marks the step which waited the most after the previous one
+	Longest bool
+}
+
+// MaxDetailedEntities limits entity timelines of html report to failed entities and the slowest ones of every
+// test case, keeping reports of huge runs manageable, 0 includes all of them. JSON export is never sampled
+var MaxDetailedEntities = 0
+
+// EntityTimeline is a full sequence of events of a single PVC or pod
+type EntityTimeline struct {
+	Kind   store.EntityTypeEnum
+	Name   string
+	Anchor string
+	Steps  []TimelineStep
+	// Failed marks entity which started a stage it never finished
+	Failed bool
+	// Audit are API server requests which changed the entity, its volume or snapshot content, if audit log was ingested
+	Audit []store.AuditEntry
+}
+
+// Duration returns time between the first and the last events
+func (et EntityTimeline) Duration() time.Duration {
+	if len(et.Steps) == 0 {
+		return 0
+	}
+	return et.Steps[len(et.Steps)-1].Offset
+}
+
+// entityAnchor returns id of entity timeline in html report, so it can be linked from other tables
+func entityAnchor(tcID int64, name string) string {
+	return fmt.Sprintf("entity-%d-%s", tcID, name)
+}
+
+// getEntityTimelines returns timelines of PVCs and pods of test case ordered by kind and name,
+// sampled down to MaxDetailedEntities if set
+func getEntityTimelines(tc collector.TestCaseMetrics) []EntityTimeline {
+	timelines := sampleTimelines(getAllEntityTimelines(tc), MaxDetailedEntities)
+	sort.SliceStable(timelines, func(i, j int) bool {
+		if timelines[i].Kind != timelines[j].Kind {
+			return timelines[i].Kind > timelines[j].Kind
+		}
+		return timelines[i].Name < timelines[j].Name
+	})
+	return timelines
+}
+
+// getAllEntityTimelines returns timelines of all PVCs and pods of test case
+func getAllEntityTimelines(tc collector.TestCaseMetrics) []EntityTimeline {
+	audit := make(map[int64][]store.AuditEntry)
+	for _, e := range tc.AuditEntries {
+		audit[e.EntityID] = append(audit[e.EntityID], e)
+	}
+	var timelines []EntityTimeline
+	for _, pvc := range tc.PVCs {
+		et := newEntityTimeline(tc.TestCase.ID, store.Pvc, pvc.PVC.Name, pvc.Events)
+		for _, d := range pvc.Metrics {
+			et.Failed = et.Failed || d < 0
+		}
+		et.Audit = audit[pvc.PVC.ID]
+		timelines = append(timelines, et)
+	}
+	for _, pod := range tc.Pods {
+		et := newEntityTimeline(tc.TestCase.ID, store.Pod, pod.Pod.Name, pod.Events)
+		for _, d := range pod.Metrics {
+			et.Failed = et.Failed || d < 0
+		}
+		et.Audit = audit[pod.Pod.ID]
+		timelines = append(timelines, et)
+	}
+	return timelines
+}
+
+// sampleTimelines keeps failed timelines and the slowest ones up to max, all of them if max is 0
+func sampleTimelines(timelines []EntityTimeline, max int) []EntityTimeline {
+	if max <= 0 || len(timelines) <= max {
+		return timelines
+	}
+	sort.SliceStable(timelines, func(i, j int) bool {
+		if timelines[i].Failed != timelines[j].Failed {
+			return timelines[i].Failed
+		}
+		return timelines[i].Duration() > timelines[j].Duration()
+	})
+	// Failed entities are never dropped, even if there are more of them than max
+	n := max
+	for n < len(timelines) && timelines[n].Failed {
+		n++
+	}
+	return timelines[:n]
+}
+
+// getOmittedEntities returns number of PVCs and pods left out of html report by sampling
+func getOmittedEntities(tc collector.TestCaseMetrics) int {
+	return len(tc.PVCs) + len(tc.Pods) - len(getEntityTimelines(tc))
+}
+
+func newEntityTimeline(tcID int64, kind store.EntityTypeEnum, name string, events []store.Event) EntityTimeline {
+	sorted := append([]store.Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	et := EntityTimeline{Kind: kind, Name: name, Anchor: entityAnchor(tcID, name)}
+	longest := -1
+	for i, e := range sorted {
+		step := TimelineStep{Event: e}
+		if i > 0 {
+			step.Offset = e.Timestamp.Sub(sorted[0].Timestamp)
+			step.Gap = e.Timestamp.Sub(sorted[i-1].Timestamp)
+			if longest < 0 || step.Gap > et.Steps[longest].Gap {
+				longest = i
+			}
+		}
+		et.Steps = append(et.Steps, step)
+	}
+	if longest > 0 {
+		et.Steps[longest].Longest = true
+	}
+	return et
+}
+
+// MergedStep is an entry of merged timeline of test case with time passed since test case started
+type MergedStep struct {
+	Entry  store.TimelineEntry
+	Offset time.Duration
+}
+
+// getMergedTimeline returns merged timeline of test case, entries before test case started have negative offsets
+func getMergedTimeline(tc collector.TestCaseMetrics) []MergedStep {
+	steps := make([]MergedStep, 0, len(tc.Timeline))
+	for _, e := range tc.Timeline {
+		steps = append(steps, MergedStep{Entry: e, Offset: e.Timestamp.Sub(tc.TestCase.StartTimestamp)})
+	}
+	return steps
+}
@@ -17,12 +17,15 @@
 package reporter
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/load"
 	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/store"
 )
 
 func formatName(runName string) string {
@@ -33,6 +36,81 @@ func inc(i int) int {
 	return i + 1
 }
 
+// formatDifference formats how much candidate of comparison differs from baseline, e.g. +1.5s (+30.0%)
+func formatDifference(c store.Comparison) string {
+	sign := "+"
+	if c.Difference() < 0 {
+		sign = ""
+	}
+	return fmt.Sprintf("%s%s (%s%.1f%%)", sign, c.Difference(), sign, c.DifferencePercent())
+}
+
+// getUnstablePods returns pods of test case whose containers restarted or got into CrashLoopBackOff
+func getUnstablePods(tc collector.TestCaseMetrics) []collector.PodMetrics {
+	var unstable []collector.PodMetrics
+	for _, pod := range tc.Pods {
+		if pod.IsUnstable() {
+			unstable = append(unstable, pod)
+		}
+	}
+	return unstable
+}
+
+// getLatencyDistributions returns distributions of latencies measured by the suite of test case
+func getLatencyDistributions(tc collector.TestCaseMetrics) []collector.LatencyDistribution {
+	return collector.DistributeLatencies(tc.LatencySamples)
+}
+
+// getStageHeadrooms returns how close stages of test case came to the timeout of the run
+func getStageHeadrooms(tc collector.TestCaseMetrics, run store.TestRun) []collector.StageHeadroom {
+	return collector.StageHeadrooms(tc, collector.StageTimeout(run))
+}
+
+// getBackgroundLoad returns background load generated during run, nil if run had none
+func getBackgroundLoad(run store.TestRun) *load.Report {
+	if run.Load == "" {
+		return nil
+	}
+	report := &load.Report{}
+	if err := json.Unmarshal([]byte(run.Load), report); err != nil {
+		return nil
+	}
+	return report
+}
+
+// formatBytes formats size in bytes with binary unit suffix, ex. 256.0Mi
+func formatBytes(b int64) string {
+	units := []string{"Ki", "Mi", "Gi", "Ti"}
+	if b < 1024 && b > -1024 {
+		return fmt.Sprintf("%d", b)
+	}
+	size := float64(b) / 1024
+	unit := units[0]
+	for _, u := range units[1:] {
+		if size < 1024 && size > -1024 {
+			break
+		}
+		size /= 1024
+		unit = u
+	}
+	return fmt.Sprintf("%.1f%s", size, unit)
+}
+
+// getSLOSummaries evaluates configured SLOs over test case, nil if none of its operations is covered by them
+func getSLOSummaries(tc collector.TestCaseMetrics) []collector.SLOSummary {
+	var summaries []collector.SLOSummary
+	covered := false
+	for _, slo := range SLOs {
+		s := collector.SummarizeSLO(tc, slo, SLOWindow)
+		covered = covered || s.Total != 0
+		summaries = append(summaries, s)
+	}
+	if !covered {
+		return nil
+	}
+	return summaries
+}
+
 func shouldBeIncluded(metric collector.DurationOfStage) bool {
 	if (metric.Max < 0 || metric.Min < 0 || metric.Avg < 0) || (metric.Max == 0 && metric.Min == 0 && metric.Avg == 0) {
 		return false
@@ -41,7 +119,12 @@ func shouldBeIncluded(metric collector.DurationOfStage) bool {
 }
 
 func getReportFile(runName, format string) (*os.File, string, error) {
-	pathReportsDir, err := plotter.GetReportPathDir(runName)
+	return getReportFileIn(plotter.ReportRoot(runName), runName, format)
+}
+
+// getReportFileIn creates report file in report directory inside root, see plotter.GetReportPathDirIn
+func getReportFileIn(root, runName, format string) (*os.File, string, error) {
+	pathReportsDir, err := plotter.GetReportPathDirIn(root, runName)
 	PathReport = pathReportsDir
 	if err != nil {
 		return nil, "", err
@@ -57,3 +140,11 @@ func getReportFile(runName, format string) (*os.File, string, error) {
 	}
 	return f, pathReportsDir, nil
 }
+
+// reportRoot returns directory reports of metrics collections are generated in, runs reported together share it
+func reportRoot(mcs []*collector.MetricsCollection) string {
+	if len(mcs) == 0 {
+		return ""
+	}
+	return plotter.ReportRoot(mcs[0].Run.Name)
+}
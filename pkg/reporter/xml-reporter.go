@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/plotter"
 	"github.com/dell/cert-csi/pkg/store"
 )
 
@@ -30,11 +31,12 @@ type XMLReporter struct{}
 // MultiGenerate generates report from multiple metrics collection
 func (xr *XMLReporter) MultiGenerate(mcs []*collector.MetricsCollection) error {
 	fm := template.FuncMap{
-		"formatName":           formatName,
-		"getResultStatus":      xr.getResultStatus,
-		"getCustomReportName":  xr.getCustomReportName,
-		"getFailedCountFromMC": getFailedCountFromMC,
-		"getTestDuration":      getTestDuration,
+		"formatName":            formatName,
+		"getResultStatus":       xr.getResultStatus,
+		"getCustomReportName":   xr.getCustomReportName,
+		"getFailedCountFromMC":  getFailedCountFromMC,
+		"getSkippedCountFromMC": getSkippedCountFromMC,
+		"getTestDuration":       getTestDuration,
 	}
 
 	templateData, err := embedFS.ReadFile("templates/multi-xml-template.xml")
@@ -47,7 +49,7 @@ func (xr *XMLReporter) MultiGenerate(mcs []*collector.MetricsCollection) error {
 		return err
 	}
 
-	xmlFile, _, err := getReportFile("junit", "xml")
+	xmlFile, _, err := getReportFileIn(reportRoot(mcs), "junit", "xml")
 	if err != nil {
 		return err
 	}
@@ -100,7 +102,7 @@ func (xr *XMLReporter) Generate(_ string, mc *collector.MetricsCollection) error
 	// Write a report to file
 	currentTime := time.Now()
 
-	xmlFile, _, err := getReportFile("xml-report-"+currentTime.Format("2006-01-02_15_04_05"), "xml")
+	xmlFile, _, err := getReportFileIn(plotter.ReportRoot(mc.Run.Name), "xml-report-"+currentTime.Format("2006-01-02_15_04_05"), "xml")
 	if err != nil {
 		return err
 	}
@@ -139,7 +141,7 @@ func (xr *XMLReporter) getFailedCount() int {
 func getFailedCountFromMC(mc *collector.MetricsCollection) int {
 	failed := 0
 	for i := 0; i < len(mc.TestCasesMetrics); i++ {
-		if !mc.TestCasesMetrics[i].TestCase.Success {
+		if !mc.TestCasesMetrics[i].TestCase.Success && !mc.TestCasesMetrics[i].TestCase.Skipped {
 			failed++
 		}
 	}
@@ -156,6 +158,16 @@ func getPassedCountFromMC(mc *collector.MetricsCollection) int {
 	return passed
 }
 
+func getSkippedCountFromMC(mc *collector.MetricsCollection) int {
+	skipped := 0
+	for i := 0; i < len(mc.TestCasesMetrics); i++ {
+		if mc.TestCasesMetrics[i].TestCase.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
+
 func (xr *XMLReporter) getSkippedCount() int {
 	return skippedCount
 }
@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package schedule
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "*/15 8-18 * * mon-fri", "0 0 1,15 * *", "5 4 * jan,jul 7", "@daily", "30 2-10/4 * * *"} {
+		_, err := ParseCron(expr)
+		assert.NoError(t, err, expr)
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Monday
+	start := time.Date(2023, time.January, 2, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2023, time.January, 2, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2023, time.January, 2, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2023, time.January, 3, 2, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2023, time.January, 7, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		// Both days restricted, either matches
+		{"0 0 15 * 3", time.Date(2023, time.January, 4, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, c.Next(start), tt.expr)
+	}
+
+	never, err := ParseCron("0 0 31 2 *")
+	assert.NoError(t, err)
+	assert.True(t, never.Next(start).IsZero())
+}
+
+func TestScheduler(t *testing.T) {
+	dir := t.TempDir()
+	// Runs left by previous scheduler process
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "run-0002"), 0o750))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "other"), 0o750))
+
+	cron, err := ParseCron("*/5 * * * *")
+	assert.NoError(t, err)
+	now := time.Date(2023, time.January, 2, 10, 0, 0, 0, time.UTC)
+	var tags []string
+	var waits []time.Duration
+	s := NewScheduler(cron, dir, 2, 3, func(_ context.Context, tag, runDir string) error {
+		tags = append(tags, tag)
+		assert.DirExists(t, runDir)
+		now = now.Add(7 * time.Minute)
+		return nil
+	})
+	s.now = func() time.Time { return now }
+	s.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	assert.NoError(t, s.Run(context.Background()))
+	assert.Equal(t, []string{"run-0003", "run-0004", "run-0005"}, tags)
+	// Trigger missed while run was in progress is skipped
+	assert.Equal(t, []time.Duration{5 * time.Minute, 3 * time.Minute, 3 * time.Minute}, waits)
+
+	numbers, err := s.tags()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 5}, numbers)
+	assert.DirExists(t, filepath.Join(dir, "other"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.sleep = sleepContext
+	assert.ErrorIs(t, s.Run(ctx), context.Canceled)
+}
@@ -0,0 +1,175 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+)
+
+// tagPrefix is a prefix of run directories, runs are tagged run-0001, run-0002...
+const tagPrefix = "run-"
+
+// Job runs a single scheduled run in its directory
+type Job func(ctx context.Context, tag, dir string) error
+
+// Scheduler triggers job at times matching cron expression, each run gets its own directory in Dir
+// tagged with auto-incremented number, only the last Retain of them are kept
+type Scheduler struct {
+	Cron *Cron
+	Dir  string
+	// Retain is number of run directories to keep, all are kept if zero
+	Retain int
+	// MaxRuns stops scheduler after that many runs, runs forever if zero
+	MaxRuns int
+	Job     Job
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewScheduler is a Scheduler constructor
+func NewScheduler(cron *Cron, dir string, retain, maxRuns int, job Job) *Scheduler {
+	return &Scheduler{
+		Cron:    cron,
+		Dir:     dir,
+		Retain:  retain,
+		MaxRuns: maxRuns,
+		Job:     job,
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Run triggers runs until context is cancelled or MaxRuns is reached. Runs never overlap,
+// triggers missed while a run is in progress are skipped
+func (s *Scheduler) Run(ctx context.Context) error {
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return err
+	}
+	for runs := 0; s.MaxRuns == 0 || runs < s.MaxRuns; runs++ {
+		next := s.Cron.Next(s.now())
+		if next.IsZero() {
+			return fmt.Errorf("cron expression never matches")
+		}
+		log.Infof("Next run is scheduled at %s", color.CyanString(next.Format(time.RFC3339)))
+		if err := s.sleep(ctx, next.Sub(s.now())); err != nil {
+			return err
+		}
+
+		tag, err := s.nextTag()
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(s.Dir, tag)
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return err
+		}
+		log.Infof("Starting scheduled run %s", color.CyanString(tag))
+		start := s.now()
+		if err := s.Job(ctx, tag, dir); err != nil {
+			log.Errorf("Scheduled run %s failed after %s; error=%v", tag, s.now().Sub(start).Round(time.Second), err)
+		} else {
+			log.Infof("Scheduled run %s finished in %s", color.CyanString(tag), s.now().Sub(start).Round(time.Second))
+		}
+		if err := s.prune(); err != nil {
+			log.Errorf("Can't prune old runs; error=%v", err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// tags returns numbers of existing run directories in ascending order
+func (s *Scheduler) tags() ([]int, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), tagPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(e.Name(), tagPrefix))
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// nextTag continues numbering of existing runs, so restarted scheduler doesn't overwrite them
+func (s *Scheduler) nextTag() (string, error) {
+	numbers, err := s.tags()
+	if err != nil {
+		return "", err
+	}
+	next := 1
+	if len(numbers) != 0 {
+		next = numbers[len(numbers)-1] + 1
+	}
+	return formatTag(next), nil
+}
+
+func formatTag(n int) string {
+	return fmt.Sprintf("%s%04d", tagPrefix, n)
+}
+
+// prune removes the oldest run directories beyond retention
+func (s *Scheduler) prune() error {
+	if s.Retain <= 0 {
+		return nil
+	}
+	numbers, err := s.tags()
+	if err != nil {
+		return err
+	}
+	for len(numbers) > s.Retain {
+		dir := filepath.Join(s.Dir, formatTag(numbers[0]))
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		log.Infof("Pruned run %s", formatTag(numbers[0]))
+		numbers = numbers[1:]
+	}
+	return nil
+}
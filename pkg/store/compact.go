@@ -0,0 +1,208 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CompactResult describes what Compact removed from the store
+type CompactResult struct {
+	DuplicateEvents  int
+	OrphanedEntities int64
+	// OrphanedEvents are events of entities which no longer exist
+	OrphanedEvents int64
+	SizeBefore     int64
+	SizeAfter      int64
+}
+
+// Reclaimed returns number of bytes compaction freed
+func (cr CompactResult) Reclaimed() int64 {
+	return cr.SizeBefore - cr.SizeAfter
+}
+
+// compactedEvent is an event as far as deduplication is concerned
+type compactedEvent struct {
+	id        int64
+	entityID  int64
+	eventType string
+	message   string
+	timestamp time.Time
+	keyed     bool
+}
+
+// Compact deduplicates events, removes entities which test case or events are gone and vacuums database.
+// Events of the same type and message of an entity within window of the first one are duplicates, e.g. ones
+// saved again after watch reconnected, window shorter than EventDedupBucket is widened to it. Events saved before
+// deduplication keys were introduced get their keys, so later duplicates aren't inserted. Nothing is changed
+// if dryRun is set, result says what would be removed
+func (ss *SQLiteStore) Compact(window time.Duration, dryRun bool) (*CompactResult, error) {
+	if window < EventDedupBucket {
+		window = EventDedupBucket
+	}
+	res := &CompactResult{}
+	var err error
+	if res.SizeBefore, err = ss.Size(); err != nil {
+		return nil, err
+	}
+
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	tcIDs, err := compactEvents(tx, window, res)
+	if err == nil {
+		err = removeOrphans(tx, tcIDs, res)
+	}
+	if err == nil {
+		err = invalidateMetricsCache(tx, tcIDs)
+	}
+	if err != nil || dryRun {
+		_ = tx.Rollback()
+		if err != nil {
+			return nil, err
+		}
+		res.SizeAfter = res.SizeBefore
+		return res, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if _, err := ss.db.Exec("VACUUM"); err != nil {
+		return nil, err
+	}
+	if res.SizeAfter, err = ss.Size(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// compactEvents deletes duplicate events, keeping the earliest one, and returns test cases which events changed
+func compactEvents(tx *sql.Tx, window time.Duration, res *CompactResult) (map[int64]struct{}, error) {
+	rows, err := tx.Query(`
+	SELECT id, tc_id, entity_id, type, COALESCE(message, ''), timestamp, dedup_key IS NOT NULL
+	FROM events ORDER BY entity_id, type, COALESCE(message, ''), timestamp, id`)
+	if err != nil {
+		return nil, err
+	}
+	var duplicates, unkeyed []compactedEvent
+	tcIDs := make(map[int64]struct{})
+	var kept *compactedEvent
+	for rows.Next() {
+		var e compactedEvent
+		var tcID int64
+		if err := rows.Scan(&e.id, &tcID, &e.entityID, &e.eventType, &e.message, &e.timestamp, &e.keyed); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if kept != nil && kept.entityID == e.entityID && kept.eventType == e.eventType && kept.message == e.message &&
+			e.timestamp.Sub(kept.timestamp) < window {
+			duplicates = append(duplicates, e)
+			tcIDs[tcID] = struct{}{}
+			continue
+		}
+		kept = &e
+		if !e.keyed {
+			unkeyed = append(unkeyed, e)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range duplicates {
+		if _, err := tx.Exec("DELETE FROM events WHERE id = ?", e.id); err != nil {
+			return nil, err
+		}
+	}
+	res.DuplicateEvents = len(duplicates)
+
+	// Kept events are at least window apart, so their keys differ, key taken by event of another type
+	// of the same message is left alone
+	for _, e := range unkeyed {
+		key := eventDedupKey(&Event{EntityID: e.entityID, Type: EventTypeEnum(e.eventType), Timestamp: e.timestamp, Message: e.message})
+		if _, err := tx.Exec("UPDATE OR IGNORE events SET dedup_key = ? WHERE id = ?", key, e.id); err != nil {
+			return nil, err
+		}
+	}
+	return tcIDs, nil
+}
+
+// removeOrphans deletes events of missing entities and entities of missing test cases or without any rows referring
+// to them, test cases which data changed are added to tcIDs
+func removeOrphans(tx *sql.Tx, tcIDs map[int64]struct{}, res *CompactResult) error {
+	orphanedEvents := "entity_id NOT IN (SELECT id FROM entities)"
+	if err := collectTestCases(tx, "events", orphanedEvents, tcIDs); err != nil {
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM events WHERE " + orphanedEvents)
+	if err != nil {
+		return err
+	}
+	if res.OrphanedEvents, err = result.RowsAffected(); err != nil {
+		return err
+	}
+
+	orphanedEntities := `tc_id NOT IN (SELECT id FROM test_cases) OR (
+		id NOT IN (SELECT entity_id FROM events) AND
+		id NOT IN (SELECT entity_id FROM entity_placements) AND
+		id NOT IN (SELECT entity_id FROM audit_entries) AND
+		id NOT IN (SELECT entity_id1 FROM entities_relations) AND
+		id NOT IN (SELECT entity_id2 FROM entities_relations))`
+	if err := collectTestCases(tx, "entities", orphanedEntities, tcIDs); err != nil {
+		return err
+	}
+	// Rows referring to entities of missing test cases go along with them
+	for _, stmt := range []string{
+		"DELETE FROM events WHERE entity_id IN (SELECT id FROM entities WHERE tc_id NOT IN (SELECT id FROM test_cases))",
+		"DELETE FROM entity_placements WHERE entity_id IN (SELECT id FROM entities WHERE tc_id NOT IN (SELECT id FROM test_cases))",
+		"DELETE FROM audit_entries WHERE entity_id IN (SELECT id FROM entities WHERE tc_id NOT IN (SELECT id FROM test_cases))",
+		`DELETE FROM entities_relations WHERE
+			entity_id1 IN (SELECT id FROM entities WHERE tc_id NOT IN (SELECT id FROM test_cases)) OR
+			entity_id2 IN (SELECT id FROM entities WHERE tc_id NOT IN (SELECT id FROM test_cases))`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	result, err = tx.Exec("DELETE FROM entities WHERE " + orphanedEntities)
+	if err != nil {
+		return err
+	}
+	res.OrphanedEntities, err = result.RowsAffected()
+	return err
+}
+
+// collectTestCases adds test cases of rows of table matching condition to tcIDs
+func collectTestCases(tx *sql.Tx, table, condition string, tcIDs map[int64]struct{}) error {
+	rows, err := tx.Query("SELECT DISTINCT tc_id FROM " + table + " WHERE " + condition) // #nosec G202
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tcID int64
+		if err := rows.Scan(&tcID); err != nil {
+			return err
+		}
+		tcIDs[tcID] = struct{}{}
+	}
+	return rows.Err()
+}
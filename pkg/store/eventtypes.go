@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// EventCategory groups event types by what they mean for metrics
+type EventCategory string
+
+const (
+	// LifecycleCategory marks start or end of entity stage
+	LifecycleCategory EventCategory = "LIFECYCLE"
+	// WarningCategory marks problems reported next to test case results
+	WarningCategory EventCategory = "WARNING"
+	// InfoCategory marks events kept for timelines only
+	InfoCategory EventCategory = "INFO"
+)
+
+// EventTypeInfo describes event type and entity it happens to
+type EventTypeInfo struct {
+	Type     EventTypeEnum
+	Category EventCategory
+	Entity   EntityTypeEnum
+}
+
+var (
+	eventTypesMutex sync.RWMutex
+	eventTypes      = map[EventTypeEnum]EventTypeInfo{}
+)
+
+func init() {
+	for _, info := range []EventTypeInfo{
+		{PvcAdded, LifecycleCategory, Pvc},
+		{PvcBound, LifecycleCategory, Pvc},
+		{PvcNodeSelected, LifecycleCategory, Pvc},
+		{PvcAttachStarted, LifecycleCategory, Pvc},
+		{PvcAttachEnded, LifecycleCategory, Pvc},
+		{PvcUnattachStarted, LifecycleCategory, Pvc},
+		{PvcUnattachEnded, LifecycleCategory, Pvc},
+		{PvcDeletingStarted, LifecycleCategory, Pvc},
+		{PvcDeletingEnded, LifecycleCategory, Pvc},
+		{PodAdded, LifecycleCategory, Pod},
+		{PodReady, LifecycleCategory, Pod},
+		{PodTerminating, LifecycleCategory, Pod},
+		{PodDeleted, LifecycleCategory, Pod},
+		{EphemeralPublishStarted, LifecycleCategory, Pod},
+		{EphemeralPublishEnded, LifecycleCategory, Pod},
+		{EphemeralUnpublishStarted, LifecycleCategory, Pod},
+		{EphemeralUnpublishEnded, LifecycleCategory, Pod},
+		{PodContainerRestarted, WarningCategory, Pod},
+		{PodOOMKilled, WarningCategory, Pod},
+		{PodCrashLoopBackOff, WarningCategory, Pod},
+		{NodeKernelIOError, WarningCategory, Node},
+		{DriverUnavailable, WarningCategory, Driver},
+		{DriverAvailable, InfoCategory, Driver},
+		{SnapshotAdded, LifecycleCategory, Snapshot},
+		{SnapshotReady, LifecycleCategory, Snapshot},
+		{SnapshotReadyTimeout, WarningCategory, Snapshot},
+		{FaultInjected, InfoCategory, Pod},
+		{FaultCleared, InfoCategory, Pod},
+		{PodPreempted, InfoCategory, Pod},
+		{PodEvicted, InfoCategory, Pod},
+		{PodRescheduled, InfoCategory, Pod},
+		{ComponentEvent, InfoCategory, Unknown},
+		{WaitConditionMet, InfoCategory, Unknown},
+		{WaitConditionTimedOut, WarningCategory, Unknown},
+	} {
+		eventTypes[info.Type] = info
+	}
+}
+
+// RegisterEventType adds event type suites and plugins can save events of.
+// Registering the same type again is allowed as long as category and entity don't change
+func RegisterEventType(name string, category EventCategory, entity EntityTypeEnum) (EventTypeEnum, error) {
+	if name == "" {
+		return "", fmt.Errorf("event type name can't be empty")
+	}
+	switch category {
+	case LifecycleCategory, WarningCategory, InfoCategory:
+	default:
+		return "", fmt.Errorf("unknown category %q of event type %s", category, name)
+	}
+
+	eventTypesMutex.Lock()
+	defer eventTypesMutex.Unlock()
+
+	t := EventTypeEnum(name)
+	info := EventTypeInfo{Type: t, Category: category, Entity: entity}
+	if registered, ok := eventTypes[t]; ok && registered != info {
+		return "", fmt.Errorf("event type %s is already registered as %s event of %s", name, registered.Category, registered.Entity)
+	}
+	eventTypes[t] = info
+	return t, nil
+}
+
+// GetEventType returns description of registered event type
+func GetEventType(t EventTypeEnum) (EventTypeInfo, bool) {
+	eventTypesMutex.RLock()
+	defer eventTypesMutex.RUnlock()
+	info, ok := eventTypes[t]
+	return info, ok
+}
+
+// GetEventTypes returns registered event types of category sorted by name, all of them if category is empty
+func GetEventTypes(category EventCategory) []EventTypeInfo {
+	eventTypesMutex.RLock()
+	defer eventTypesMutex.RUnlock()
+	var infos []EventTypeInfo
+	for _, info := range eventTypes {
+		if category == "" || info.Category == category {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Type < infos[j].Type
+	})
+	return infos
+}
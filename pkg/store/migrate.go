@@ -0,0 +1,394 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	// lib/pq registers postgres driver used as migration target
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// migrationSkippedTables hold state of the process using database, which means nothing to another one
+var migrationSkippedTables = map[string]bool{
+	"run_lock":      true,
+	"metrics_cache": true,
+}
+
+// postgresTypes translate SQLite column types into Postgres ones, SQLite doesn't enforce VARCHAR length so it's dropped
+var postgresTypes = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)^INTEGER$`), "BIGINT"},
+	{regexp.MustCompile(`(?i)^(DATETIME|TIMESTAMP)$`), "TIMESTAMPTZ"},
+	{regexp.MustCompile(`(?i)^BLOB$`), "BYTEA"},
+	{regexp.MustCompile(`(?i)^VARCHAR\(\d+\)$`), "VARCHAR"},
+}
+
+// createStatement matches beginning of table or index definition, so it can be made idempotent
+var createStatement = regexp.MustCompile(`(?is)^\s*CREATE\s+(UNIQUE\s+)?(TABLE|INDEX)\s+(IF\s+NOT\s+EXISTS\s+)?`)
+
+// postgresTable is a Postgres definition of SQLite table
+type postgresTable struct {
+	Create string
+	// Identity is the column rows are numbered by, its sequence has to follow copied ids
+	Identity string
+	// ForeignKeys are added once rows are copied, since SQLite tables may reference tables created after them
+	ForeignKeys []string
+}
+
+// MigratedTable is a number of rows copied from a table by Migrate
+type MigratedTable struct {
+	Name string
+	Rows int64
+	// IDOffset is added to ids of copied rows, and to columns referencing them, when target already had their ids
+	IDOffset int64
+}
+
+// IsPostgresDSN checks whether dsn points to Postgres database rather than SQLite one
+func IsPostgresDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// Migrate copies all rows of the store to database at target dsn, keeping their ids so relations between rows hold.
+// Target is either Postgres URL or SQLite dsn, its tables and indexes are created if they don't exist. If target
+// already has ids of a table, e.g. history of another team was migrated into it, copied rows of the table get ids
+// past the ones in target and columns referencing them follow. Either everything is copied or nothing is. Postgres
+// copy is meant for querying runs with other tools, cert-csi itself reads SQLite databases only.
+func (ss *SQLiteStore) Migrate(target string) ([]MigratedTable, error) {
+	var db *sql.DB
+	postgres := IsPostgresDSN(target)
+	if postgres {
+		var err error
+		if db, err = sql.Open("postgres", target); err != nil {
+			return nil, err
+		}
+		defer db.Close()
+	} else {
+		targetStore := NewSQLiteStore(target)
+		defer targetStore.Close()
+		db = targetStore.db
+	}
+
+	tables, indexes, err := ss.schema()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logrus.Errorf("Can't roll back migration; error=%v", err)
+		}
+	}()
+
+	pgTables := make(map[string]postgresTable)
+	if postgres {
+		for _, ddl := range append(tables, indexes...) {
+			var exists bool
+			if err := tx.QueryRow("SELECT to_regclass($1) IS NOT NULL", ddl[0]).Scan(&exists); err != nil {
+				return nil, err
+			}
+			pgTable := postgresDDL(ddl[1])
+			if _, err := tx.Exec(pgTable.Create); err != nil {
+				return nil, fmt.Errorf("can't create %s in target: %w", ddl[0], err)
+			}
+			if exists {
+				// Foreign keys were added by migration which created the table
+				pgTable.ForeignKeys = nil
+			}
+			pgTables[ddl[0]] = pgTable
+		}
+	}
+
+	offsets := make(map[string]int64)
+	for _, table := range tables {
+		if offsets[table[0]], err = ss.idOffset(tx, table[0]); err != nil {
+			return nil, fmt.Errorf("can't check ids of %s in target: %w", table[0], err)
+		}
+	}
+
+	var migrated []MigratedTable
+	for _, table := range tables {
+		shifts, err := ss.idShifts(table[0], offsets)
+		if err != nil {
+			return nil, err
+		}
+		n, err := ss.copyTable(tx, table[0], postgres, shifts)
+		if err != nil {
+			return nil, fmt.Errorf("can't copy table %s: %w", table[0], err)
+		}
+		migrated = append(migrated, MigratedTable{Name: table[0], Rows: n, IDOffset: offsets[table[0]]})
+	}
+
+	for _, table := range tables {
+		pgTable := pgTables[table[0]]
+		for _, fk := range pgTable.ForeignKeys {
+			if _, err := tx.Exec(fk); err != nil {
+				return nil, fmt.Errorf("can't add foreign key of %s in target: %w", table[0], err)
+			}
+		}
+		if pgTable.Identity != "" {
+			// Rows added to target later must not get ids of copied ones
+			setval := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), MAX(%s)) FROM %s HAVING MAX(%s) IS NOT NULL", // #nosec
+				table[0], pgTable.Identity, pgTable.Identity, table[0], pgTable.Identity)
+			if _, err := tx.Exec(setval); err != nil {
+				return nil, fmt.Errorf("can't advance ids of %s in target: %w", table[0], err)
+			}
+		}
+	}
+	return migrated, tx.Commit()
+}
+
+// schema returns names and definitions of tables in order they were created in, followed by indexes
+func (ss *SQLiteStore) schema() (tables, indexes [][2]string, err error) {
+	rows, err := ss.db.Query(`SELECT type, name, sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY rowid`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var kind, name, ddl string
+		if err := rows.Scan(&kind, &name, &ddl); err != nil {
+			return nil, nil, err
+		}
+		switch kind {
+		case "table":
+			if !migrationSkippedTables[name] {
+				tables = append(tables, [2]string{name, ddl})
+			}
+		case "index":
+			indexes = append(indexes, [2]string{name, ddl})
+		}
+	}
+	return tables, indexes, rows.Err()
+}
+
+// identity returns INTEGER PRIMARY KEY column rows of the table are numbered by, empty if it has none
+func (ss *SQLiteStore) identity(table string) (string, error) {
+	rows, err := ss.db.Query("SELECT name, type FROM pragma_table_info(?) WHERE pk > 0", table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var keys, types []string
+	for rows.Next() {
+		var name, columnType string
+		if err := rows.Scan(&name, &columnType); err != nil {
+			return "", err
+		}
+		keys, types = append(keys, name), append(types, columnType)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(keys) != 1 || !strings.EqualFold(types[0], "INTEGER") {
+		return "", nil
+	}
+	return keys[0], nil
+}
+
+// idOffset returns number added to ids of the table copied into target, so they don't collide with ids target
+// already has. Ids are kept if they don't collide
+func (ss *SQLiteStore) idOffset(tx *sql.Tx, table string) (int64, error) {
+	id, err := ss.identity(table)
+	if err != nil || id == "" {
+		return 0, err
+	}
+	var sourceMin, targetMax sql.NullInt64
+	if err := ss.db.QueryRow(fmt.Sprintf("SELECT MIN(%s) FROM %s", id, table)).Scan(&sourceMin); err != nil { // #nosec
+		return 0, err
+	}
+	if err := tx.QueryRow(fmt.Sprintf("SELECT MAX(%s) FROM %s", id, table)).Scan(&targetMax); err != nil { // #nosec
+		return 0, err
+	}
+	if !sourceMin.Valid || !targetMax.Valid || targetMax.Int64 < sourceMin.Int64 {
+		return 0, nil
+	}
+	return targetMax.Int64 - sourceMin.Int64 + 1, nil
+}
+
+// idShifts returns offsets added to columns of the table when it's copied, its own ids and columns referencing
+// shifted ids of other tables
+func (ss *SQLiteStore) idShifts(table string, offsets map[string]int64) (map[string]int64, error) {
+	shifts := make(map[string]int64)
+	id, err := ss.identity(table)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" && offsets[table] != 0 {
+		shifts[id] = offsets[table]
+	}
+	rows, err := ss.db.Query(`SELECT "from", "table" FROM pragma_foreign_key_list(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column, referenced string
+		if err := rows.Scan(&column, &referenced); err != nil {
+			return nil, err
+		}
+		if offsets[referenced] != 0 {
+			shifts[column] = offsets[referenced]
+		}
+	}
+	return shifts, rows.Err()
+}
+
+// postgresDDL translates SQLite table or index definition into Postgres one, only types following column names
+// are translated, so columns named after types keep their names. Definitions don't fail if table or index exists
+func postgresDDL(ddl string) postgresTable {
+	ddl = createStatement.ReplaceAllString(ddl, "CREATE ${1}${2} IF NOT EXISTS ")
+	open, end := strings.Index(ddl, "("), strings.LastIndex(ddl, ")")
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(ddl)), "CREATE TABLE") || open < 0 || end < open {
+		return postgresTable{Create: ddl}
+	}
+	head := strings.Fields(ddl[:open])
+	table := head[len(head)-1]
+
+	res := postgresTable{}
+	var defs []string
+	for _, def := range splitDefinitions(ddl[open+1 : end]) {
+		upper := strings.ToUpper(def)
+		switch {
+		case strings.HasPrefix(upper, "FOREIGN KEY"):
+			res.ForeignKeys = append(res.ForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s", table, def))
+			continue
+		case strings.HasPrefix(upper, "PRIMARY KEY"), strings.HasPrefix(upper, "UNIQUE"),
+			strings.HasPrefix(upper, "CHECK"), strings.HasPrefix(upper, "CONSTRAINT"):
+			defs = append(defs, def)
+			continue
+		}
+
+		fields := strings.Fields(def)
+		if len(fields) < 2 {
+			defs = append(defs, def)
+			continue
+		}
+		column, columnType, constraints := fields[0], fields[1], fields[2:]
+		if strings.EqualFold(columnType, "INTEGER") && strings.HasPrefix(strings.ToUpper(strings.Join(constraints, " ")), "PRIMARY KEY") {
+			// Explicit ids are copied, so identity has to accept them
+			res.Identity = column
+			columnType = "BIGINT GENERATED BY DEFAULT AS IDENTITY"
+			constraints = slices.DeleteFunc(constraints, func(c string) bool {
+				return strings.EqualFold(c, "AUTOINCREMENT")
+			})
+		} else {
+			for _, t := range postgresTypes {
+				if t.re.MatchString(columnType) {
+					columnType = t.replacement
+					break
+				}
+			}
+		}
+		defs = append(defs, strings.Join(append([]string{quoteIdent(column), columnType}, constraints...), " "))
+	}
+	res.Create = fmt.Sprintf("%s(\n\t%s)", strings.TrimSpace(ddl[:open]), strings.Join(defs, ",\n\t"))
+	return res
+}
+
+// quoteIdent quotes column name for Postgres, so columns named after its reserved words, e.g. user, can be created
+func quoteIdent(name string) string {
+	return `"` + strings.Trim(name, "`\"[]") + `"`
+}
+
+// splitDefinitions splits body of CREATE TABLE into column and constraint definitions
+func splitDefinitions(body string) []string {
+	var defs []string
+	depth, start := 0, 0
+	for i, r := range body {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				defs = append(defs, strings.TrimSpace(body[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(body[start:]); last != "" {
+		defs = append(defs, last)
+	}
+	return defs
+}
+
+// copyTable inserts every row of the table into target with all its columns, shifts are added to integer columns
+// they name. Returns number of copied rows
+func (ss *SQLiteStore) copyTable(tx *sql.Tx, table string, postgres bool, shifts map[string]int64) (int64, error) {
+	rows, err := ss.db.Query(fmt.Sprintf("SELECT * FROM %s ORDER BY rowid", table)) // #nosec
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	placeholders := make([]string, len(columns))
+	for i := range placeholders {
+		placeholders[i] = "?"
+		if postgres {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		}
+	}
+	names := slices.Clone(columns)
+	if postgres {
+		for i := range names {
+			names[i] = quoteIdent(names[i])
+		}
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s)", table, strings.Join(names, ", "), strings.Join(placeholders, ", "))) // #nosec
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var n int64
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return n, err
+		}
+		for i, column := range columns {
+			if v, ok := values[i].(int64); ok && shifts[column] != 0 {
+				values[i] = v + shifts[column]
+			}
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, rows.Err()
+}
@@ -36,6 +36,12 @@ const (
 	Pod EntityTypeEnum = "POD"
 	// StatefulSet represents entity of type StatefulSet
 	StatefulSet EntityTypeEnum = "STATEFULSET"
+	// Node represents entity of type Node
+	Node EntityTypeEnum = "NODE"
+	// Driver represents entity of type Driver, a health endpoint of driver pod container
+	Driver EntityTypeEnum = "DRIVER"
+	// Snapshot represents entity of type VolumeSnapshot
+	Snapshot EntityTypeEnum = "SNAPSHOT"
 	// Unknown represents entity of Unknown type
 	Unknown EntityTypeEnum = "UNKNOWN"
 
@@ -43,6 +49,8 @@ const (
 	PvcAdded EventTypeEnum = "PVC_ADDED"
 	// PvcBound represents PVC_BOUND event type
 	PvcBound EventTypeEnum = "PVC_BOUND"
+	// PvcNodeSelected represents PVC_NODE_SELECTED event type, scheduler picked node of delayed-binding PVC consumer
+	PvcNodeSelected EventTypeEnum = "PVC_NODE_SELECTED"
 	// PvcAttachStarted represents PVC_ATTACH_STARTED event type
 	PvcAttachStarted EventTypeEnum = "PVC_ATTACH_STARTED"
 	// PvcAttachEnded represents PVC_ATTACH_ENDED event type
@@ -63,6 +71,51 @@ const (
 	PodTerminating EventTypeEnum = "POD_TERMINATING"
 	// PodDeleted represents POD_DELETED event type
 	PodDeleted EventTypeEnum = "POD_DELETED"
+	// EphemeralPublishStarted represents EPHEMERAL_PUBLISH_STARTED event type
+	EphemeralPublishStarted EventTypeEnum = "EPHEMERAL_PUBLISH_STARTED"
+	// EphemeralPublishEnded represents EPHEMERAL_PUBLISH_ENDED event type
+	EphemeralPublishEnded EventTypeEnum = "EPHEMERAL_PUBLISH_ENDED"
+	// EphemeralUnpublishStarted represents EPHEMERAL_UNPUBLISH_STARTED event type
+	EphemeralUnpublishStarted EventTypeEnum = "EPHEMERAL_UNPUBLISH_STARTED"
+	// EphemeralUnpublishEnded represents EPHEMERAL_UNPUBLISH_ENDED event type
+	EphemeralUnpublishEnded EventTypeEnum = "EPHEMERAL_UNPUBLISH_ENDED"
+	// PodContainerRestarted represents POD_CONTAINER_RESTARTED event type
+	PodContainerRestarted EventTypeEnum = "POD_CONTAINER_RESTARTED"
+	// PodOOMKilled represents POD_OOM_KILLED event type
+	PodOOMKilled EventTypeEnum = "POD_OOM_KILLED"
+	// PodCrashLoopBackOff represents POD_CRASHLOOP_BACKOFF event type
+	PodCrashLoopBackOff EventTypeEnum = "POD_CRASHLOOP_BACKOFF"
+	// NodeKernelIOError represents NODE_KERNEL_IO_ERROR warning event type
+	NodeKernelIOError EventTypeEnum = "NODE_KERNEL_IO_ERROR"
+	// DriverUnavailable represents DRIVER_UNAVAILABLE warning event type, driver health endpoint stopped answering
+	DriverUnavailable EventTypeEnum = "DRIVER_UNAVAILABLE"
+	// DriverAvailable represents DRIVER_AVAILABLE event type, driver health endpoint answers again
+	DriverAvailable EventTypeEnum = "DRIVER_AVAILABLE"
+	// SnapshotAdded represents SNAPSHOT_ADDED event type
+	SnapshotAdded EventTypeEnum = "SNAPSHOT_ADDED"
+	// SnapshotReady represents SNAPSHOT_READY event type
+	SnapshotReady EventTypeEnum = "SNAPSHOT_READY"
+	// SnapshotReadyTimeout represents SNAPSHOT_READY_TIMEOUT warning event type, snapshot wasn't ready within
+	// the deadline, message holds the last error from its status
+	SnapshotReadyTimeout EventTypeEnum = "SNAPSHOT_READY_TIMEOUT"
+	// FaultInjected represents FAULT_INJECTED event type, fault hook disrupted node of the pod, ex. blocked its storage network
+	FaultInjected EventTypeEnum = "FAULT_INJECTED"
+	// FaultCleared represents FAULT_CLEARED event type, fault hook cleared fault of node of the pod
+	FaultCleared EventTypeEnum = "FAULT_CLEARED"
+	// PodPreempted represents POD_PREEMPTED event type, scheduler evicted the pod to make room for pod of higher priority
+	PodPreempted EventTypeEnum = "POD_PREEMPTED"
+	// PodEvicted represents POD_EVICTED event type, pod was evicted by descheduler, node pressure or taint manager
+	PodEvicted EventTypeEnum = "POD_EVICTED"
+	// PodRescheduled represents POD_RESCHEDULED event type, pod displaced by preemption or eviction was scheduled again,
+	// message holds the nodes it moved between
+	PodRescheduled EventTypeEnum = "POD_RESCHEDULED"
+	// WaitConditionMet represents WAIT_CONDITION_MET event type, object satisfied custom wait condition of the suite,
+	// message holds the condition and time it took
+	WaitConditionMet EventTypeEnum = "WAIT_CONDITION_MET"
+	// WaitConditionTimedOut represents WAIT_CONDITION_TIMED_OUT event type, object didn't satisfy custom wait condition in time
+	WaitConditionTimedOut EventTypeEnum = "WAIT_CONDITION_TIMED_OUT"
+	// ComponentEvent represents COMPONENT_EVENT event type, a Kubernetes event emitted about the entity
+	ComponentEvent EventTypeEnum = "COMPONENT_EVENT"
 )
 
 // Value returns type of entity
@@ -111,6 +164,13 @@ type Event struct {
 	EntityID  int64
 	Type      EventTypeEnum
 	Timestamp time.Time
+	// Message describes warning events, empty for lifecycle events
+	Message string
+	// Source is the component that emitted Kubernetes event, empty for events observed by cert-csi itself
+	Source string
+	// SourceKey identifies the object which transition produced event, ex. its UID, so transition replayed after
+	// watch reconnects is saved once however late it's observed. It's a part of deduplication key only
+	SourceKey string
 }
 
 // Entity struct
@@ -122,6 +182,17 @@ type Entity struct {
 	Type   EntityTypeEnum
 }
 
+// EntityPlacement is where entity was placed, empty fields weren't known to observers
+type EntityPlacement struct {
+	ID       int64
+	TcID     int64
+	EntityID int64
+	Node     string
+	Zone     string
+	// Backend is storage array or system volume of PVC was provisioned on
+	Backend string
+}
+
 // NumberEntities struct
 type NumberEntities struct {
 	ID              int64
@@ -146,6 +217,404 @@ type ResourceUsage struct {
 	Mem           int64
 }
 
+// AssertionResult contains result of a single test case assertion
+type AssertionResult struct {
+	ID       int64
+	TcID     int64
+	Name     string
+	Expected string
+	Actual   string
+	Passed   bool
+}
+
+// Orphan describes a resource left behind after test case teardown
+type Orphan struct {
+	ID        int64
+	TcID      int64
+	Kind      string
+	Name      string
+	Details   string
+	Timestamp time.Time
+}
+
+// Comparison contains the same metric of a test case measured in two configurations
+type Comparison struct {
+	ID             int64
+	TcID           int64
+	Metric         string
+	Baseline       string
+	BaselineValue  time.Duration
+	Candidate      string
+	CandidateValue time.Duration
+	// Threshold is percent candidate may exceed baseline by before it is a regression, 0 if not checked
+	Threshold float64
+}
+
+// RampStage is a part of test case run with fixed number of concurrently created volumes
+type RampStage struct {
+	ID             int64
+	TcID           int64
+	Concurrency    int
+	StartTimestamp time.Time
+	EndTimestamp   time.Time
+}
+
+// Duration returns how long the stage took
+func (rs RampStage) Duration() time.Duration {
+	return rs.EndTimestamp.Sub(rs.StartTimestamp)
+}
+
+// MountCheck is a result of validating how volume of a pod is mounted on its node
+type MountCheck struct {
+	ID          int64
+	TcID        int64
+	Pod         string
+	PVC         string
+	Node        string
+	Path        string
+	Options     string
+	Propagation string
+	Valid       bool
+	Message     string
+}
+
+// VolumeStat is usage of volume reported by kubelet after suite wrote known amount of data to it, sizes are in bytes
+type VolumeStat struct {
+	ID        int64
+	TcID      int64
+	PVC       string
+	Node      string
+	Capacity  int64
+	Used      int64
+	Available int64
+	Written   int64
+	Valid     bool
+	Message   string
+}
+
+// MountOptionOutcomeEnum specifies how volume of storage class with a mount option set ended up
+type MountOptionOutcomeEnum string
+
+const (
+	// MountOptionsApplied means volume was mounted with all options of the set
+	MountOptionsApplied MountOptionOutcomeEnum = "APPLIED"
+	// MountOptionsIgnored means volume was mounted but some options of the set are missing on node
+	MountOptionsIgnored MountOptionOutcomeEnum = "IGNORED"
+	// MountOptionsRejected means volume wasn't mounted and its pod and claim were deleted cleanly
+	MountOptionsRejected MountOptionOutcomeEnum = "REJECTED"
+	// MountOptionsStuck means volume wasn't mounted and its pod or claim couldn't be deleted
+	MountOptionsStuck MountOptionOutcomeEnum = "STUCK"
+)
+
+// MountOptionResult is an outcome of mounting volume of storage class clone with a set of mount options
+type MountOptionResult struct {
+	ID      int64
+	TcID    int64
+	Set     string
+	Options string
+	Outcome MountOptionOutcomeEnum
+	// ExpectFailure is true if set is invalid and volume is expected to be rejected
+	ExpectFailure bool
+	// Applied are options volume was actually mounted with, empty if it wasn't mounted
+	Applied string
+	Passed  bool
+	Message string
+}
+
+// CapacityFillResult is an outcome of provisioning volumes until backend pool is exhausted
+type CapacityFillResult struct {
+	ID         int64
+	TcID       int64
+	VolumeSize string
+	// Bound is number of volumes provisioned before pool was exhausted
+	Bound     int
+	Exhausted bool
+	// ErrorCode is gRPC code driver failed provisioning with, ResourceExhausted is expected
+	ErrorCode string
+	// Message is the last provisioning failure reported in events of the rejected claim
+	Message string
+	// Healthy is number of bound volumes still writable once pool was exhausted
+	Healthy int
+	// Restored is true if a volume could be provisioned again once test volumes were deleted
+	Restored bool
+	Passed   bool
+}
+
+// MountRecoveryResult is how pod started while its volume couldn't be staged or published due to injected fault
+// recovered once fault was cleared
+type MountRecoveryResult struct {
+	ID   int64
+	TcID int64
+	Pod  string
+	Node string
+	// Retries is number of failed attach and mount attempts kubelet reported for the pod
+	Retries int
+	// FirstFailure is the first attach or mount failure reported for the pod, empty if fault didn't disrupt it
+	FirstFailure string
+	// Recovery is time from fault being cleared until pod was ready, Total is time since pod creation
+	Recovery  time.Duration
+	Total     time.Duration
+	Recovered bool
+}
+
+// Modes of concurrent write results
+const (
+	// ConcurrentWriteDistinct is a mode where every writer appends to its own file
+	ConcurrentWriteDistinct = "distinct"
+	// ConcurrentWriteShared is a mode where all writers append to one shared file, its semantics are only recorded
+	ConcurrentWriteShared = "shared"
+)
+
+// ConcurrentWriteResult is what validator found in file RWX writers on different nodes appended records to
+type ConcurrentWriteResult struct {
+	ID   int64
+	TcID int64
+	Mode string
+	File string
+	// Writers are pods which appended to the file, Nodes are nodes they ran on
+	Writers string
+	Nodes   string
+	// Expected is number of records appended, Intact is number of distinct records found complete
+	Expected int
+	Intact   int
+	// Lost records weren't found at all, Corrupted are lines which aren't complete records, ex. torn or interleaved
+	Lost         int
+	Corrupted    int
+	Size         int64
+	ExpectedSize int64
+	Passed       bool
+}
+
+// ControllerFailoverResult is how controller Deployment of the driver recovered from disruption during provisioning
+type ControllerFailoverResult struct {
+	ID         int64
+	TcID       int64
+	Deployment string
+	// Disruption is how controller was disrupted, ex. leader pod deleted or Deployment scaled down and up
+	Disruption string
+	Replicas   int
+	// Leases are leader election leases which had to change hands, Lease is the one of provisioner
+	Leases    string
+	Lease     string
+	OldLeader string
+	NewLeader string
+	// Handover is time from disruption until every lease was held by a running controller pod
+	Handover   time.Duration
+	HandedOver bool
+	// Stall is the longest window after disruption without a volume being provisioned
+	Stall       time.Duration
+	Volumes     int
+	Provisioned int
+	Passed      bool
+}
+
+// DetachProbeResult is what was left of volume on the node it was detached from and whether data was consistent
+// once volume was attached elsewhere
+type DetachProbeResult struct {
+	ID      int64
+	TcID    int64
+	PVC     string
+	OldNode string
+	NewNode string
+	// Device is major:minor of block device backing the volume on old node, empty if volume isn't backed by one
+	Device string
+	// DeviceState is whether device was gone, present but unreadable or still readable on old node after detach
+	DeviceState string
+	// StaleMounts are mount points of the volume left in host mount table of old node
+	StaleMounts string
+	// Detach is time from deletion of the pod until volume was detached from old node
+	Detach     time.Duration
+	Consistent bool
+	Passed     bool
+}
+
+// FaultWindow is a fault fault hook injected on node at a step of fault plan and how IO of writers on the node
+// behaved during and after it
+type FaultWindow struct {
+	ID    int64
+	TcID  int64
+	Step  int
+	Node  string
+	Start time.Time
+	End   time.Time
+	// Ops and Errors count IO operations writers started during the fault, stalled operations are errors
+	Ops    int
+	Errors int
+	// OpsAfter and ErrorsAfter count operations started after fault was cleared until the next step or end of suite
+	OpsAfter    int
+	ErrorsAfter int
+	// Recovery is time from clearing the fault until every writer completed an operation again
+	Recovery  time.Duration
+	Recovered bool
+}
+
+// SeriesRun links test run into a certification series, runs of a series test the same cluster and storage class
+// with successive driver versions
+type SeriesRun struct {
+	ID             int64
+	Series         string
+	RunID          int64
+	DriverVersion  string
+	AddedTimestamp time.Time
+}
+
+// LatencySample is a single latency measured by a suite outside of entity events, ex. time content took to reach a reader
+type LatencySample struct {
+	ID     int64
+	TcID   int64
+	Metric string
+	// Source is what the latency was measured on, ex. reader pod
+	Source    string
+	Value     time.Duration
+	Timestamp time.Time
+}
+
+const (
+	// IPv6ConnectMetric samples connections from node plugin network to controller over IPv6
+	IPv6ConnectMetric = "IPv6 node-controller connect"
+	// IPv6ConnectFailedMetric samples connections which weren't established, value is time until giving up
+	IPv6ConnectFailedMetric = "IPv6 node-controller connect failed"
+)
+
+// ExpansionOutcomeEnum specifies how far expansion of a volume got
+type ExpansionOutcomeEnum string
+
+const (
+	// ExpansionCompleted means volume was expanded on controller and node
+	ExpansionCompleted ExpansionOutcomeEnum = "COMPLETED"
+	// ExpansionControllerPending means controller never expanded volume
+	ExpansionControllerPending ExpansionOutcomeEnum = "CONTROLLER_PENDING"
+	// ExpansionControllerOnly means volume was resized on controller only and node expansion never started
+	ExpansionControllerOnly ExpansionOutcomeEnum = "CONTROLLER_ONLY"
+	// ExpansionNodePending means node expansion was pending or in progress when suite stopped waiting
+	ExpansionNodePending ExpansionOutcomeEnum = "NODE_PENDING"
+	// ExpansionInfeasible means controller or node failed expansion with terminal error
+	ExpansionInfeasible ExpansionOutcomeEnum = "INFEASIBLE"
+)
+
+// ExpansionOutcome is how expansion of a single volume ended, durations of phases it didn't finish are zero
+type ExpansionOutcome struct {
+	ID      int64
+	TcID    int64
+	PVC     string
+	Outcome ExpansionOutcomeEnum
+	// Controller is time from resize request until volume was expanded by controller
+	Controller time.Duration
+	// Node is time from controller expansion until new size was reported in PVC status
+	Node    time.Duration
+	Message string
+}
+
+// Difference returns how much candidate value exceeds baseline one
+func (c Comparison) Difference() time.Duration {
+	return c.CandidateValue - c.BaselineValue
+}
+
+// DifferencePercent returns difference relative to baseline value, zero if baseline is zero
+func (c Comparison) DifferencePercent() float64 {
+	if c.BaselineValue == 0 {
+		return 0
+	}
+	return float64(c.Difference()) / float64(c.BaselineValue) * 100
+}
+
+// Regressed checks whether candidate exceeded baseline by more than the threshold
+func (c Comparison) Regressed() bool {
+	return c.Threshold > 0 && c.DifferencePercent() > c.Threshold
+}
+
+// BackendLeak describes a volume or snapshot which appeared on storage backend during test run and wasn't removed
+type BackendLeak struct {
+	ID        int64
+	RunID     int64
+	Kind      string
+	Name      string
+	Timestamp time.Time
+}
+
+// TeardownLatency contains time objects of a single kind took to disappear after test case teardown started
+type TeardownLatency struct {
+	ID        int64
+	TcID      int64
+	Kind      string
+	Count     int
+	Remaining int
+	Avg       time.Duration
+	Max       time.Duration
+}
+
+// AuditEntry is a request from API server audit log which changed an entity of test case
+type AuditEntry struct {
+	ID       int64
+	TcID     int64
+	EntityID int64
+	// AuditID identifies request in audit log, entries ingested again are skipped
+	AuditID     string
+	Timestamp   time.Time
+	Verb        string
+	Resource    string
+	Subresource string
+	Name        string
+	User        string
+	UserAgent   string
+	Code        int
+}
+
+// Sources of timeline entries
+const (
+	// TimelineObserver entries are events recorded by cert-csi observers
+	TimelineObserver = "observer"
+	// TimelineKubernetes entries are Kubernetes events emitted by control-plane components and driver sidecars
+	TimelineKubernetes = "kubernetes"
+	// TimelineMetrics entries are notable changes of resource usage of driver containers
+	TimelineMetrics = "metrics"
+	// TimelineAudit entries are API server requests from ingested audit log
+	TimelineAudit = "audit"
+	// TimelineFault entries are faults injected on nodes and their clearing
+	TimelineFault = "fault"
+)
+
+// TimelineEntry is a single entry of merged timeline of test case, timestamps of all sources are on runner host clock
+type TimelineEntry struct {
+	ID        int64
+	TcID      int64
+	Timestamp time.Time
+	Source    string
+	// Object is kind and name of object entry is about, empty if it isn't about a single object
+	Object  string
+	Message string
+	// Corrected marks entries taken from cluster clock shifted by clock skew of test case
+	Corrected bool
+}
+
+// TeardownTier describes deletion of a single tier of test case namespace, tiers are deleted in order
+type TeardownTier struct {
+	ID       int64
+	TcID     int64
+	Tier     string
+	Position int
+	Duration time.Duration
+	// Deleted is number of objects tier had when its deletion started, Remaining is number of them left after timeout
+	Deleted   int
+	Remaining int
+	Message   string
+}
+
+// MetricsCache contains serialized metrics of a single test case
+type MetricsCache struct {
+	TcID             int64
+	Data             []byte
+	UpdatedTimestamp time.Time
+}
+
+// RunLock describes process holding advisory lock of db
+type RunLock struct {
+	Owner             string
+	Pid               int
+	Hostname          string
+	AcquiredTimestamp time.Time
+}
+
 // TestRun struct
 type TestRun struct {
 	ID             int64
@@ -154,6 +623,44 @@ type TestRun struct {
 	StartTimestamp time.Time
 	StorageClass   string
 	ClusterAddress string
+	Seed           int64
+	// Metadata is JSON of extra annotations and labels applied to PVCs and pods
+	Metadata string
+	// AbortReason is the reason operator aborted run with, empty if run wasn't aborted
+	AbortReason string
+	// Timeout is the wait timeout of the run stages, zero if defaults of resource clients were used
+	Timeout time.Duration
+	// ClassSpecs is JSON of storage and snapshot class specs taken when run started
+	ClassSpecs string
+	// ClassDrift lists changes of the classes made while run was in progress, empty if they weren't changed
+	ClassDrift string
+	// NotObservable lists what run couldn't observe because it was restricted to namespaced permissions
+	NotObservable string
+	// Capabilities is JSON of capabilities declared by driver of the storage class when run started
+	Capabilities string
+	// Baseline marks run later runs of the same storage class are compared with
+	Baseline bool
+	// Load is JSON of background load profile run was under and load actually generated, empty if there was none
+	Load string
+	// RetryOf is name of the run whose Iterations were re-run by this one, empty if it isn't a retry run
+	RetryOf    string
+	Iterations string
+	// TruncateReason is the budget run exceeded and stopped launching iterations for, empty if it ran to the end
+	TruncateReason string
+	// NameGenerator is the kind of generator resource names of the run were generated with
+	NameGenerator string
+	// NameCollisions is how many generated names repeated a taken name and were regenerated
+	NameCollisions int
+}
+
+// Aborted checks whether run was aborted by operator
+func (tr TestRun) Aborted() bool {
+	return tr.AbortReason != ""
+}
+
+// Truncated checks whether run stopped early because it exceeded its budget
+func (tr TestRun) Truncated() bool {
+	return tr.TruncateReason != ""
 }
 
 // TestCase struct
@@ -166,4 +673,77 @@ type TestCase struct {
 	Success        bool
 	ErrorMessage   string
 	RunID          int64
+	// Rate is number of volumes per second the suite was paced at, zero if it wasn't paced
+	Rate float64
+	// Skipped test cases didn't run because suites they depend on failed, ErrorMessage says which
+	Skipped bool
+	// Iteration is number of the iteration test case ran in, it's what is passed to --only to re-run it
+	Iteration int
+	// ClockSkew is how far cluster clock was ahead of runner host clock when test case started
+	ClockSkew time.Duration
+}
+
+// SetTcID sets test case the Comparison belongs to
+func (c *Comparison) SetTcID(id int64) {
+	c.TcID = id
+}
+
+// SetTcID sets test case the RampStage belongs to
+func (rs *RampStage) SetTcID(id int64) {
+	rs.TcID = id
+}
+
+// SetTcID sets test case the MountCheck belongs to
+func (mc *MountCheck) SetTcID(id int64) {
+	mc.TcID = id
+}
+
+// SetTcID sets test case the VolumeStat belongs to
+func (vs *VolumeStat) SetTcID(id int64) {
+	vs.TcID = id
+}
+
+// SetTcID sets test case the MountOptionResult belongs to
+func (mr *MountOptionResult) SetTcID(id int64) {
+	mr.TcID = id
+}
+
+// SetTcID sets test case the CapacityFillResult belongs to
+func (cr *CapacityFillResult) SetTcID(id int64) {
+	cr.TcID = id
+}
+
+// SetTcID sets test case the MountRecoveryResult belongs to
+func (mr *MountRecoveryResult) SetTcID(id int64) {
+	mr.TcID = id
+}
+
+// SetTcID sets test case the ConcurrentWriteResult belongs to
+func (cw *ConcurrentWriteResult) SetTcID(id int64) {
+	cw.TcID = id
+}
+
+// SetTcID sets test case the ControllerFailoverResult belongs to
+func (cf *ControllerFailoverResult) SetTcID(id int64) {
+	cf.TcID = id
+}
+
+// SetTcID sets test case the DetachProbeResult belongs to
+func (dp *DetachProbeResult) SetTcID(id int64) {
+	dp.TcID = id
+}
+
+// SetTcID sets test case the FaultWindow belongs to
+func (fw *FaultWindow) SetTcID(id int64) {
+	fw.TcID = id
+}
+
+// SetTcID sets test case the LatencySample belongs to
+func (ls *LatencySample) SetTcID(id int64) {
+	ls.TcID = id
+}
+
+// SetTcID sets test case the ExpansionOutcome belongs to
+func (eo *ExpansionOutcome) SetTcID(id int64) {
+	eo.TcID = id
 }
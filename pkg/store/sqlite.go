@@ -19,6 +19,7 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -36,7 +37,8 @@ type StorageClassDB struct {
 
 // SQLiteStore implements the Store interface, used for storing objects to sqlite database
 type SQLiteStore struct {
-	db *sql.DB
+	db   *sql.DB
+	lock *RunLock
 }
 
 // NewSQLiteStore creates a new SQLiteStore
@@ -70,12 +72,74 @@ func (ss *SQLiteStore) createTables() error {
 		longevity BOOLEAN DEFAULT false,
 		start_timestamp DATETIME,
 		storage_class VARCHAR(50) NOT NULL,
-		cluster_address VARCHAR(50) NOT NULL)
+		cluster_address VARCHAR(50) NOT NULL,
+		seed INTEGER DEFAULT 0,
+		metadata VARCHAR DEFAULT '',
+		abort_reason VARCHAR DEFAULT '',
+		timeout INTEGER DEFAULT 0,
+		class_specs VARCHAR DEFAULT '',
+		class_drift VARCHAR DEFAULT '',
+		not_observable VARCHAR DEFAULT '',
+		capabilities VARCHAR DEFAULT '',
+		baseline BOOLEAN DEFAULT false,
+		load VARCHAR DEFAULT '',
+		retry_of VARCHAR DEFAULT '',
+		iterations VARCHAR DEFAULT '',
+		truncate_reason VARCHAR DEFAULT '',
+		name_generator VARCHAR DEFAULT '',
+		name_collisions INTEGER DEFAULT 0)
 		`)
 	if err != nil {
 		return err
 	}
 
+	// Databases created by older versions don't have the seed column
+	if err = ss.addColumnIfNotExists("test_runs", "seed", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "metadata", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "abort_reason", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "timeout", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "class_specs", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "class_drift", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "not_observable", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "capabilities", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "baseline", "BOOLEAN DEFAULT false"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "load", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "retry_of", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "iterations", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "truncate_reason", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "name_generator", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_runs", "name_collisions", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
 	_, err = ss.db.Exec(`
 	CREATE TABLE IF NOT EXISTS test_cases(
 		id INTEGER PRIMARY KEY,
@@ -86,12 +150,30 @@ func (ss *SQLiteStore) createTables() error {
 		success BOOLEAN,
  		error_msg VARCHAR(250),
 		run_id INTEGER NOT NULL,
+		rate REAL DEFAULT 0,
+		skipped BOOLEAN DEFAULT false,
+		iteration INTEGER DEFAULT 0,
+		clock_skew INTEGER DEFAULT 0,
 		FOREIGN KEY(run_id) REFERENCES test_runs(id))
 		`)
 	if err != nil {
 		return err
 	}
 
+	// Databases created by older versions don't have the rate column
+	if err = ss.addColumnIfNotExists("test_cases", "rate", "REAL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_cases", "skipped", "BOOLEAN DEFAULT false"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_cases", "iteration", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("test_cases", "clock_skew", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
 	_, err = ss.db.Exec(`
 	CREATE TABLE IF NOT EXISTS events(
 		id INTEGER PRIMARY KEY,
@@ -107,6 +189,27 @@ func (ss *SQLiteStore) createTables() error {
 		return err
 	}
 
+	if err = ss.addColumnIfNotExists("events", "dedup_key", "VARCHAR"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("events", "message", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = ss.addColumnIfNotExists("events", "source", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Events saved before deduplication was introduced have NULL keys and are kept as is
+	_, err = ss.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS events_dedup_key ON events(dedup_key)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`CREATE INDEX IF NOT EXISTS events_tc_id ON events(tc_id)`)
+	if err != nil {
+		return err
+	}
+
 	_, err = ss.db.Exec(`
 	CREATE TABLE IF NOT EXISTS entities(
 		id INTEGER PRIMARY KEY,
@@ -153,477 +256,2917 @@ func (ss *SQLiteStore) createTables() error {
 	}
 
 	_, err = ss.db.Exec(`
-	CREATE TABLE IF NOT EXISTS entities_relations(
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		entity_id1 INTEGER NOT NULL,
-		entity_id2 INTEGER NOT NULL,
-		FOREIGN KEY(entity_id1) REFERENCES entities(id),
-		FOREIGN KEY(entity_id2) REFERENCES entities(id))
+	CREATE TABLE IF NOT EXISTS assertion_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		name VARCHAR NOT NULL,
+		expected VARCHAR,
+		actual VARCHAR,
+		passed BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
 		`)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS orphans(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		kind VARCHAR NOT NULL,
+		name VARCHAR NOT NULL,
+		details VARCHAR,
+		timestamp TIMESTAMP,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
 
-// SaveTestRun saves test run result in db
-func (ss *SQLiteStore) SaveTestRun(tr *TestRun) error {
-	result, err := ss.db.Exec(`
-	INSERT INTO test_runs(
-		name, start_timestamp, storage_class, cluster_address
-	)VALUES ($1, $2, $3, $4)`,
-		tr.Name, tr.StartTimestamp, tr.StorageClass, tr.ClusterAddress)
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS comparisons(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		metric VARCHAR NOT NULL,
+		baseline VARCHAR,
+		baseline_value INTEGER,
+		candidate VARCHAR,
+		candidate_value INTEGER,
+		threshold REAL DEFAULT 0,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
 	if err != nil {
 		return err
 	}
-	if tr.ID, err = result.LastInsertId(); err != nil {
+	if err = ss.addColumnIfNotExists("comparisons", "threshold", "REAL DEFAULT 0"); err != nil {
 		return err
 	}
-	return nil
-}
 
-// GetTestRuns queries test run information from db
-func (ss *SQLiteStore) GetTestRuns(whereConditions Conditions, orderBy string, limit int) ([]TestRun, error) {
-	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "test_runs")
-	rows, err := ss.db.Query(sqlStmt)
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS ramp_stages(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		concurrency INTEGER NOT NULL,
+		start_timestamp DATETIME,
+		end_timestamp DATETIME,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	var testRuns []TestRun
-
-	for rows.Next() {
-		tr := TestRun{}
-		if err = rows.Scan(
-			&tr.ID, &tr.Name, &tr.Longevity, &tr.StartTimestamp, &tr.StorageClass, &tr.ClusterAddress); err == nil {
-			testRuns = append(testRuns, tr)
-		}
-	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS mount_checks(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		pod VARCHAR NOT NULL,
+		pvc VARCHAR NOT NULL,
+		node VARCHAR,
+		path VARCHAR,
+		options VARCHAR,
+		propagation VARCHAR,
+		valid BOOLEAN,
+		message VARCHAR,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
 	}
 
-	return testRuns, nil
-}
-
-// SaveEvents saves events into db
-func (ss *SQLiteStore) SaveEvents(events []*Event) error {
-	sqlAddEvent := `
-	INSERT INTO events(
-		name, tc_id, entity_id, type, timestamp
-	) VALUES (?, ?, ?, ?, ?)
-	`
-
-	stmt, err := ss.db.Prepare(sqlAddEvent)
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS volume_stats(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		pvc VARCHAR NOT NULL,
+		node VARCHAR,
+		capacity INTEGER,
+		used INTEGER,
+		available INTEGER,
+		written INTEGER,
+		valid BOOLEAN,
+		message VARCHAR,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	for _, e := range events {
-		result, err := stmt.Exec(e.Name, e.TcID, e.EntityID, e.Type, e.Timestamp)
-		if err != nil {
-			return err
-		}
-		if e.ID, err = result.LastInsertId(); err != nil {
-			return err
-		}
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS mount_option_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		option_set VARCHAR NOT NULL,
+		options VARCHAR,
+		outcome VARCHAR,
+		expect_failure BOOLEAN,
+		applied VARCHAR,
+		passed BOOLEAN,
+		message VARCHAR,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
-
-func (ss *SQLiteStore) prepareSQLSelectStmt(
-	whereConditions Conditions,
-	orderBy string,
-	limit int,
-	tableName string,
-) string {
-	var b strings.Builder
-	b.WriteString(fmt.Sprintf("SELECT * FROM %s", tableName)) // #nosec
-
-	if len(whereConditions) > 0 {
-		b.WriteString(" WHERE") // #nosec
-		for k, v := range whereConditions {
-			switch v.(type) {
-			case string, EntityTypeEnum, EventTypeEnum:
-				b.WriteString(fmt.Sprintf(" %s='%s' AND", k, v)) // #nosec
-			case int64:
-				b.WriteString(fmt.Sprintf(" %s=%d AND", k, v)) // #nosec
-			case bool:
-				b.WriteString(fmt.Sprintf(" %s=%t AND", k, v)) // #nosec
-			default:
-				continue
-			}
-		}
-		b.WriteString(" 1=1") // #nosec
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS capacity_fill_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		volume_size VARCHAR,
+		bound INTEGER,
+		exhausted BOOLEAN,
+		error_code VARCHAR,
+		message VARCHAR,
+		healthy INTEGER,
+		restored BOOLEAN,
+		passed BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
 	}
 
-	if orderBy != "" {
-		b.WriteString(fmt.Sprintf(" ORDER BY %s", orderBy)) // #nosec
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS mount_recovery_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		pod VARCHAR,
+		node VARCHAR,
+		retries INTEGER,
+		first_failure VARCHAR,
+		recovery INTEGER,
+		total INTEGER,
+		recovered BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
 	}
 
-	if limit > 0 {
-		b.WriteString(fmt.Sprintf(" LIMIT %d", limit)) // #nosec
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS concurrent_write_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		mode VARCHAR,
+		file VARCHAR,
+		writers VARCHAR,
+		nodes VARCHAR,
+		expected INTEGER,
+		intact INTEGER,
+		lost INTEGER,
+		corrupted INTEGER,
+		size INTEGER,
+		expected_size INTEGER,
+		passed BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
 	}
 
-	return b.String()
-}
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS entity_placements(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		entity_id INTEGER NOT NULL,
+		node VARCHAR,
+		zone VARCHAR,
+		backend VARCHAR,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id),
+		FOREIGN KEY(entity_id) REFERENCES entities(id))
+		`)
+	if err != nil {
+		return err
+	}
 
-// GetEvents queries events from db
-func (ss *SQLiteStore) GetEvents(whereConditions Conditions, orderBy string, limit int) ([]Event, error) {
-	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "events")
-	rows, err := ss.db.Query(sqlStmt)
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS controller_failover_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		deployment VARCHAR,
+		disruption VARCHAR,
+		replicas INTEGER,
+		leases VARCHAR,
+		lease VARCHAR,
+		old_leader VARCHAR,
+		new_leader VARCHAR,
+		handover INTEGER,
+		handed_over BOOLEAN,
+		stall INTEGER,
+		volumes INTEGER,
+		provisioned INTEGER,
+		passed BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	var events []Event
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS detach_probe_results(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		pvc VARCHAR,
+		old_node VARCHAR,
+		new_node VARCHAR,
+		device VARCHAR,
+		device_state VARCHAR,
+		stale_mounts VARCHAR,
+		detach INTEGER,
+		consistent BOOLEAN,
+		passed BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
 
-	for rows.Next() {
-		event := Event{}
-		if err = rows.Scan(
-			&event.ID, &event.Name, &event.TcID, &event.EntityID, &event.Type, &event.Timestamp); err == nil {
-			events = append(events, event)
-		}
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS fault_windows(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		step INTEGER,
+		node VARCHAR,
+		start_timestamp DATETIME,
+		end_timestamp DATETIME,
+		ops INTEGER,
+		errors INTEGER,
+		ops_after INTEGER,
+		errors_after INTEGER,
+		recovery INTEGER,
+		recovered BOOLEAN,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
 	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS series_runs(
+		id INTEGER PRIMARY KEY,
+		series VARCHAR NOT NULL,
+		run_id INTEGER NOT NULL,
+		driver_version VARCHAR,
+		added_timestamp DATETIME,
+		UNIQUE(series, run_id),
+		FOREIGN KEY(run_id) REFERENCES test_runs(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS latency_samples(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		metric VARCHAR NOT NULL,
+		source VARCHAR,
+		value INTEGER,
+		timestamp DATETIME,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS expansion_outcomes(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		pvc VARCHAR NOT NULL,
+		outcome VARCHAR NOT NULL,
+		controller INTEGER,
+		node INTEGER,
+		message VARCHAR,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS backend_leaks(
+		id INTEGER PRIMARY KEY,
+		run_id INTEGER NOT NULL,
+		kind VARCHAR NOT NULL,
+		name VARCHAR NOT NULL,
+		timestamp TIMESTAMP,
+		FOREIGN KEY(run_id) REFERENCES test_runs(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS teardown_latencies(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		kind VARCHAR NOT NULL,
+		count INTEGER,
+		remaining INTEGER,
+		avg INTEGER,
+		max INTEGER,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS teardown_tiers(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		tier VARCHAR NOT NULL,
+		position INTEGER,
+		duration INTEGER,
+		deleted INTEGER,
+		remaining INTEGER,
+		message VARCHAR,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS audit_entries(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		entity_id INTEGER NOT NULL,
+		audit_id VARCHAR NOT NULL,
+		timestamp DATETIME NOT NULL,
+		verb VARCHAR NOT NULL,
+		resource VARCHAR,
+		subresource VARCHAR,
+		name VARCHAR,
+		user VARCHAR,
+		user_agent VARCHAR,
+		code INTEGER,
+		UNIQUE(entity_id, audit_id),
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id),
+		FOREIGN KEY(entity_id) REFERENCES entities(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS timeline_entries(
+		id INTEGER PRIMARY KEY,
+		tc_id INTEGER NOT NULL,
+		timestamp DATETIME NOT NULL,
+		source VARCHAR NOT NULL,
+		object VARCHAR,
+		message VARCHAR,
+		corrected BOOLEAN DEFAULT false,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS metrics_cache(
+		tc_id INTEGER PRIMARY KEY,
+		data BLOB NOT NULL,
+		updated_timestamp TIMESTAMP,
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS run_lock(
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		owner VARCHAR NOT NULL,
+		pid INTEGER,
+		hostname VARCHAR,
+		acquired_timestamp TIMESTAMP)
+		`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(`
+	CREATE TABLE IF NOT EXISTS entities_relations(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		entity_id1 INTEGER NOT NULL,
+		entity_id2 INTEGER NOT NULL,
+		FOREIGN KEY(entity_id1) REFERENCES entities(id),
+		FOREIGN KEY(entity_id2) REFERENCES entities(id))
+		`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addColumnIfNotExists appends column to an already existing table, so that databases
+// created by older versions of cert-csi can still be used
+func (ss *SQLiteStore) addColumnIfNotExists(table, column, definition string) error {
+	rows, err := ss.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table)) // #nosec
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid      int
+			name     string
+			cType    string
+			notNull  bool
+			dflValue sql.NullString
+			pk       int
+		)
+		if err = rows.Scan(&cid, &name, &cType, &notNull, &dflValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = ss.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)) // #nosec
+	return err
+}
+
+// SaveTestRun saves test run result in db
+func (ss *SQLiteStore) SaveTestRun(tr *TestRun) error {
+	result, err := ss.db.Exec(`
+	INSERT INTO test_runs(
+		name, start_timestamp, storage_class, cluster_address, seed, metadata, timeout, class_specs, not_observable, capabilities, load, retry_of, iterations, name_generator
+	)VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		tr.Name, tr.StartTimestamp, tr.StorageClass, tr.ClusterAddress, tr.Seed, tr.Metadata, tr.Timeout, tr.ClassSpecs, tr.NotObservable, tr.Capabilities, tr.Load, tr.RetryOf, tr.Iterations, tr.NameGenerator)
+	if err != nil {
+		return err
+	}
+	if tr.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AbortedTestRun marks test run as aborted by operator with the reason
+func (ss *SQLiteStore) AbortedTestRun(tr *TestRun, reason string) error {
+	if _, err := ss.db.Exec("UPDATE test_runs SET abort_reason=? WHERE id=?", reason, tr.ID); err != nil {
+		return err
+	}
+	tr.AbortReason = reason
+	return nil
+}
+
+// TruncatedTestRun marks test run as stopped early because it exceeded its budget
+func (ss *SQLiteStore) TruncatedTestRun(tr *TestRun, reason string) error {
+	if _, err := ss.db.Exec("UPDATE test_runs SET truncate_reason=? WHERE id=?", reason, tr.ID); err != nil {
+		return err
+	}
+	tr.TruncateReason = reason
+	return nil
+}
+
+// SaveNameCollisions records how many generated resource names of test run collided with names already taken
+func (ss *SQLiteStore) SaveNameCollisions(tr *TestRun, collisions int) error {
+	if _, err := ss.db.Exec("UPDATE test_runs SET name_collisions=? WHERE id=?", collisions, tr.ID); err != nil {
+		return err
+	}
+	tr.NameCollisions = collisions
+	return nil
+}
+
+// SaveClassDrift records changes of storage or snapshot classes made while test run was in progress
+func (ss *SQLiteStore) SaveClassDrift(tr *TestRun, drift string) error {
+	if _, err := ss.db.Exec("UPDATE test_runs SET class_drift=? WHERE id=?", drift, tr.ID); err != nil {
+		return err
+	}
+	tr.ClassDrift = drift
+	return nil
+}
+
+// SaveRunLoad records background load test run was under, once it's known how much load was actually generated
+func (ss *SQLiteStore) SaveRunLoad(tr *TestRun, load string) error {
+	if _, err := ss.db.Exec("UPDATE test_runs SET load=? WHERE id=?", load, tr.ID); err != nil {
+		return err
+	}
+	tr.Load = load
+	return nil
+}
+
+// MarkBaselineRun marks test run as the baseline later runs of its storage class are compared with,
+// the previous baseline of the storage class is unmarked
+func (ss *SQLiteStore) MarkBaselineRun(tr *TestRun) error {
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE test_runs SET baseline=false WHERE storage_class=?", tr.StorageClass); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("UPDATE test_runs SET baseline=true WHERE id=?", tr.ID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	tr.Baseline = true
+	return nil
+}
+
+// GetTestRuns queries test run information from db
+func (ss *SQLiteStore) GetTestRuns(whereConditions Conditions, orderBy string, limit int) ([]TestRun, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "test_runs")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var testRuns []TestRun
+
+	for rows.Next() {
+		tr := TestRun{}
+		if err = rows.Scan(
+			&tr.ID, &tr.Name, &tr.Longevity, &tr.StartTimestamp, &tr.StorageClass, &tr.ClusterAddress, &tr.Seed, &tr.Metadata, &tr.AbortReason, &tr.Timeout, &tr.ClassSpecs, &tr.ClassDrift, &tr.NotObservable, &tr.Capabilities, &tr.Baseline, &tr.Load, &tr.RetryOf, &tr.Iterations, &tr.TruncateReason, &tr.NameGenerator, &tr.NameCollisions); err == nil {
+			testRuns = append(testRuns, tr)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return testRuns, nil
+}
+
+// EventDedupBucket is the shortest window Compact treats events of the same type and entity as duplicates within
+const EventDedupBucket = time.Second
+
+// eventDedupKey returns natural key of event. Events with source key are the same transition of the same object
+// whenever they're observed, other events are duplicates only if they have the same timestamp, so retried saves
+// don't produce duplicates while distinct transitions close to each other are never merged
+func eventDedupKey(e *Event) string {
+	if e.SourceKey != "" {
+		return fmt.Sprintf("%d/%s/source/%s", e.TcID, e.Type, e.SourceKey)
+	}
+	key := fmt.Sprintf("%d/%s/%d", e.EntityID, e.Type, e.Timestamp.UnixNano())
+	if e.Message != "" {
+		key += "/" + e.Message
+	}
+	return key
+}
+
+// SaveEvents saves events into db in a single transaction, duplicates of already saved
+// events are not inserted and get ID of the saved ones
+func (ss *SQLiteStore) SaveEvents(events []*Event) error {
+	sqlAddEvent := `
+	INSERT INTO events(
+		name, tc_id, entity_id, type, timestamp, dedup_key, message, source
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(dedup_key) DO UPDATE SET name = events.name
+	RETURNING id
+	`
+
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(sqlAddEvent)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, e := range events {
+		if err := stmt.QueryRow(e.Name, e.TcID, e.EntityID, e.Type, e.Timestamp, eventDedupKey(e), e.Message, e.Source).Scan(&e.ID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		tcIDs[e.TcID] = struct{}{}
+	}
+
+	if err := invalidateMetricsCache(tx, tcIDs); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// invalidateMetricsCache drops cached metrics of test cases, which data has changed
+func invalidateMetricsCache(db execer, tcIDs map[int64]struct{}) error {
+	for tcID := range tcIDs {
+		if _, err := db.Exec("DELETE FROM metrics_cache WHERE tc_id = ?", tcID); err != nil {
+			logrus.Errorf("Can't invalidate metrics cache of test case %d", tcID)
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *SQLiteStore) prepareSQLSelectStmt(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+	tableName string,
+) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("SELECT * FROM %s", tableName)) // #nosec
+
+	if len(whereConditions) > 0 {
+		b.WriteString(" WHERE") // #nosec
+		for k, v := range whereConditions {
+			switch v.(type) {
+			case string, EntityTypeEnum, EventTypeEnum:
+				b.WriteString(fmt.Sprintf(" %s='%s' AND", k, v)) // #nosec
+			case int64:
+				b.WriteString(fmt.Sprintf(" %s=%d AND", k, v)) // #nosec
+			case bool:
+				b.WriteString(fmt.Sprintf(" %s=%t AND", k, v)) // #nosec
+			default:
+				continue
+			}
+		}
+		b.WriteString(" 1=1") // #nosec
+	}
+
+	if orderBy != "" {
+		b.WriteString(fmt.Sprintf(" ORDER BY %s", orderBy)) // #nosec
+	}
+
+	if limit > 0 {
+		b.WriteString(fmt.Sprintf(" LIMIT %d", limit)) // #nosec
+	}
+
+	return b.String()
+}
+
+// GetEvents queries events from db
+func (ss *SQLiteStore) GetEvents(whereConditions Conditions, orderBy string, limit int) ([]Event, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "events")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+
+	for rows.Next() {
+		event := Event{}
+		var dedupKey sql.NullString
+		if err = rows.Scan(
+			&event.ID, &event.Name, &event.TcID, &event.EntityID, &event.Type, &event.Timestamp, &dedupKey, &event.Message, &event.Source); err == nil {
+			events = append(events, event)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return events, nil
 }
 
-// GetEntitiesWithEventsByTestCaseAndEntityType queries entities with events and
-// returns the results sorted by testcase and entity
-func (ss *SQLiteStore) GetEntitiesWithEventsByTestCaseAndEntityType(
-	tc *TestCase,
-	eType EntityTypeEnum,
-) (map[Entity][]Event, error) {
-	sqlStmt := `
-		SELECT et.*, ev.*
-		FROM entities et JOIN events ev
-			ON et.id = ev.entity_id
-		WHERE et.type = ? AND et.tc_id= ?
-		ORDER BY et.id`
+// GetEntitiesWithEventsByTestCaseAndEntityType queries entities with events and
+// returns the results sorted by testcase and entity
+func (ss *SQLiteStore) GetEntitiesWithEventsByTestCaseAndEntityType(
+	tc *TestCase,
+	eType EntityTypeEnum,
+) (map[Entity][]Event, error) {
+	sqlStmt := `
+		SELECT et.*, ev.*
+		FROM entities et JOIN events ev
+			ON et.id = ev.entity_id
+		WHERE et.type = ? AND et.tc_id= ?
+		ORDER BY et.id`
+
+	stmt, err := ss.db.Prepare(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(eType, tc.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ewe := make(map[Entity][]Event)
+	for rows.Next() {
+		en := Entity{}
+		ev := Event{}
+		var dedupKey sql.NullString
+		if err = rows.Scan(
+			&en.ID, &en.Name, &en.K8sUID, &en.TcID, &en.Type,
+			&ev.ID, &ev.Name, &ev.TcID, &ev.EntityID, &ev.Type, &ev.Timestamp, &dedupKey, &ev.Message, &ev.Source); err == nil {
+			if events, ok := ewe[en]; ok {
+				ewe[en] = append(events, ev)
+			} else {
+				ewe[en] = []Event{ev}
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ewe, nil
+}
+
+// SaveTestCase saves testcases in db
+func (ss *SQLiteStore) SaveTestCase(ts *TestCase) error {
+	sqlStmt := `
+	INSERT INTO test_cases(name, parameters, start_timestamp, end_timestamp, success, error_msg, run_id, rate, skipped, iteration)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	stmt, err := ss.db.Prepare(sqlStmt)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(ts.Name, ts.Parameters, ts.StartTimestamp, ts.EndTimestamp, ts.Success, ts.ErrorMessage, ts.RunID, ts.Rate, ts.Skipped, ts.Iteration)
+	if err != nil {
+		return err
+	}
+	if ts.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetTestCases queries testcases from db
+func (ss *SQLiteStore) GetTestCases(whereConditions Conditions, orderBy string, limit int) ([]TestCase, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "test_cases")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var testCases []TestCase
+
+	for rows.Next() {
+		tc := TestCase{}
+		if err = rows.Scan(
+			&tc.ID, &tc.Name, &tc.Parameters, &tc.StartTimestamp, &tc.EndTimestamp, &tc.Success, &tc.ErrorMessage, &tc.RunID, &tc.Rate, &tc.Skipped, &tc.Iteration, &tc.ClockSkew); err == nil {
+			testCases = append(testCases, tc)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return testCases, nil
+}
+
+func (ss *SQLiteStore) updateStatusTestCase(ts *TestCase) error {
+	_, err := ss.db.Exec(
+		"UPDATE test_cases SET success=?, end_timestamp=?, error_msg=?, skipped=? WHERE id=?",
+		ts.Success, ts.EndTimestamp, ts.ErrorMessage, ts.Skipped, ts.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SuccessfulTestCase updates testcase status as success
+func (ss *SQLiteStore) SuccessfulTestCase(ts *TestCase, endTimestamp time.Time) error {
+	ts.Success = true
+	ts.EndTimestamp = endTimestamp
+	if err := ss.updateStatusTestCase(ts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FailedTestCase updates testcase status as failure
+func (ss *SQLiteStore) FailedTestCase(ts *TestCase, endTimestamp time.Time, errMsg string) error {
+	ts.Success = false
+	ts.EndTimestamp = endTimestamp
+	ts.ErrorMessage = errMsg
+	if err := ss.updateStatusTestCase(ts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SaveClockSkew records how far cluster clock was ahead of runner host clock during test case
+func (ss *SQLiteStore) SaveClockSkew(ts *TestCase, skew time.Duration) error {
+	if _, err := ss.db.Exec("UPDATE test_cases SET clock_skew=? WHERE id=?", int64(skew), ts.ID); err != nil {
+		return err
+	}
+	ts.ClockSkew = skew
+	return nil
+}
+
+// SkippedTestCase updates testcase status as skipped with the reason it didn't run
+func (ss *SQLiteStore) SkippedTestCase(ts *TestCase, endTimestamp time.Time, reason string) error {
+	ts.Success = false
+	ts.Skipped = true
+	ts.EndTimestamp = endTimestamp
+	ts.ErrorMessage = reason
+	if err := ss.updateStatusTestCase(ts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SaveEntities saves entities in db
+func (ss *SQLiteStore) SaveEntities(entities []*Entity) error {
+	sqlAddEvent := `
+	INSERT INTO entities(name, k8s_uid, tc_id, type
+	) VALUES (?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAddEvent)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range entities {
+		result, err := stmt.Exec(e.Name, e.K8sUID, e.TcID, e.Type)
+		if err != nil {
+			return err
+		}
+		if e.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetEntities queries entities from db
+func (ss *SQLiteStore) GetEntities(whereConditions Conditions, orderBy string, limit int) ([]Entity, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "entities")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []Entity
+
+	for rows.Next() {
+		entity := Entity{}
+		if err = rows.Scan(
+			&entity.ID, &entity.Name, &entity.K8sUID, &entity.TcID, &entity.Type); err == nil {
+			entities = append(entities, entity)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+// SaveNumberEntities saves NumberEntities in db
+func (ss *SQLiteStore) SaveNumberEntities(nEntities []*NumberEntities) error {
+	sqlAdd := `
+	INSERT INTO number_entities(
+		tc_id,
+		timestamp,
+		pods_creating,
+		pods_ready,
+		pods_terminating,
+		pvc_creating,
+		pvc_bound,
+		pvc_terminating
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, e := range nEntities {
+		tcIDs[e.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			e.TcID,
+			e.Timestamp,
+			e.PodsCreating,
+			e.PodsReady,
+			e.PodsTerminating,
+			e.PvcCreating,
+			e.PvcBound,
+			e.PvcTerminating)
+		if err != nil {
+			return err
+		}
+		if e.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetNumberEntities queries NumberEntities from db
+func (ss *SQLiteStore) GetNumberEntities(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]NumberEntities, error) {
+	var nEntities []NumberEntities
+	err := ss.eachNumberEntities(whereConditions, orderBy, limit, func(e NumberEntities) {
+		nEntities = append(nEntities, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nEntities, nil
+}
+
+// EachNumberEntities calls fn with every NumberEntities queried from db, rows are read one by one so they don't
+// have to be held in memory at once
+func (ss *SQLiteStore) EachNumberEntities(whereConditions Conditions, orderBy string, fn func(NumberEntities)) error {
+	return ss.eachNumberEntities(whereConditions, orderBy, 0, fn)
+}
+
+func (ss *SQLiteStore) eachNumberEntities(whereConditions Conditions, orderBy string, limit int, fn func(NumberEntities)) error {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "number_entities")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := NumberEntities{}
+		if err = rows.Scan(
+			&e.ID,
+			&e.TcID,
+			&e.Timestamp,
+			&e.PodsCreating,
+			&e.PodsReady,
+			&e.PodsTerminating,
+			&e.PvcCreating,
+			&e.PvcBound,
+			&e.PvcTerminating); err == nil {
+			fn(e)
+		}
+	}
+	return rows.Err()
+}
+
+// SaveResourceUsage saves resource usage in db
+func (ss *SQLiteStore) SaveResourceUsage(resUsages []*ResourceUsage) error {
+	sqlAdd := `
+	INSERT INTO resource_usage(
+		tc_id,
+		timestamp,
+		pod_name,
+		container_name,
+		cpu_value,
+		mem_value
+	) VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, e := range resUsages {
+		tcIDs[e.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			e.TcID,
+			e.Timestamp,
+			e.PodName,
+			e.ContainerName,
+			e.CPU,
+			e.Mem,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if e.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetResourceUsage queries resource usage from db
+func (ss *SQLiteStore) GetResourceUsage(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]ResourceUsage, error) {
+	var resUsage []ResourceUsage
+	err := ss.eachResourceUsage(whereConditions, orderBy, limit, func(e ResourceUsage) {
+		resUsage = append(resUsage, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resUsage, nil
+}
+
+// EachResourceUsage calls fn with every resource usage queried from db, rows are read one by one so they don't
+// have to be held in memory at once
+func (ss *SQLiteStore) EachResourceUsage(whereConditions Conditions, orderBy string, fn func(ResourceUsage)) error {
+	return ss.eachResourceUsage(whereConditions, orderBy, 0, fn)
+}
+
+func (ss *SQLiteStore) eachResourceUsage(whereConditions Conditions, orderBy string, limit int, fn func(ResourceUsage)) error {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "resource_usage")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := ResourceUsage{}
+		if err = rows.Scan(
+			&e.ID,
+			&e.TcID,
+			&e.Timestamp,
+			&e.PodName,
+			&e.ContainerName,
+			&e.CPU,
+			&e.Mem); err == nil {
+			fn(e)
+		}
+	}
+	return rows.Err()
+}
+
+// SaveAssertionResults saves assertion results in db
+func (ss *SQLiteStore) SaveAssertionResults(results []*AssertionResult) error {
+	sqlAdd := `
+	INSERT INTO assertion_results(
+		tc_id,
+		name,
+		expected,
+		actual,
+		passed
+	) VALUES (?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, r := range results {
+		tcIDs[r.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			r.TcID,
+			r.Name,
+			r.Expected,
+			r.Actual,
+			r.Passed,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if r.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetAssertionResults queries assertion results from db
+func (ss *SQLiteStore) GetAssertionResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]AssertionResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "assertion_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []AssertionResult
+
+	for rows.Next() {
+		r := AssertionResult{}
+		if err = rows.Scan(
+			&r.ID,
+			&r.TcID,
+			&r.Name,
+			&r.Expected,
+			&r.Actual,
+			&r.Passed); err == nil {
+			results = append(results, r)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveOrphans adds resources left behind by test cases to db
+func (ss *SQLiteStore) SaveOrphans(orphans []*Orphan) error {
+	sqlAdd := `
+	INSERT INTO orphans(
+		tc_id,
+		kind,
+		name,
+		details,
+		timestamp
+	) VALUES (?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, o := range orphans {
+		tcIDs[o.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			o.TcID,
+			o.Kind,
+			o.Name,
+			o.Details,
+			o.Timestamp,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if o.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetOrphans queries orphaned resources from db
+func (ss *SQLiteStore) GetOrphans(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]Orphan, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "orphans")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orphans []Orphan
+
+	for rows.Next() {
+		o := Orphan{}
+		if err = rows.Scan(
+			&o.ID,
+			&o.TcID,
+			&o.Kind,
+			&o.Name,
+			&o.Details,
+			&o.Timestamp); err == nil {
+			orphans = append(orphans, o)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// SaveComparisons adds metrics compared between two configurations of test cases to db
+func (ss *SQLiteStore) SaveComparisons(comparisons []*Comparison) error {
+	sqlAdd := `
+	INSERT INTO comparisons(
+		tc_id,
+		metric,
+		baseline,
+		baseline_value,
+		candidate,
+		candidate_value,
+		threshold
+	) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, c := range comparisons {
+		tcIDs[c.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			c.TcID,
+			c.Metric,
+			c.Baseline,
+			c.BaselineValue,
+			c.Candidate,
+			c.CandidateValue,
+			c.Threshold,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if c.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetComparisons queries compared metrics from db
+func (ss *SQLiteStore) GetComparisons(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]Comparison, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "comparisons")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comparisons []Comparison
+
+	for rows.Next() {
+		c := Comparison{}
+		if err = rows.Scan(
+			&c.ID,
+			&c.TcID,
+			&c.Metric,
+			&c.Baseline,
+			&c.BaselineValue,
+			&c.Candidate,
+			&c.CandidateValue,
+			&c.Threshold); err == nil {
+			comparisons = append(comparisons, c)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return comparisons, nil
+}
+
+// SaveRampStages adds stages of ramp profile to db
+func (ss *SQLiteStore) SaveRampStages(stages []*RampStage) error {
+	sqlAdd := `
+	INSERT INTO ramp_stages(
+		tc_id,
+		concurrency,
+		start_timestamp,
+		end_timestamp
+	) VALUES (?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, rs := range stages {
+		tcIDs[rs.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			rs.TcID,
+			rs.Concurrency,
+			rs.StartTimestamp,
+			rs.EndTimestamp,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if rs.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetRampStages queries stages of ramp profile from db
+func (ss *SQLiteStore) GetRampStages(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]RampStage, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "ramp_stages")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stages []RampStage
+
+	for rows.Next() {
+		rs := RampStage{}
+		if err = rows.Scan(
+			&rs.ID,
+			&rs.TcID,
+			&rs.Concurrency,
+			&rs.StartTimestamp,
+			&rs.EndTimestamp); err == nil {
+			stages = append(stages, rs)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// SaveMountChecks adds results of mount validation to db
+func (ss *SQLiteStore) SaveMountChecks(checks []*MountCheck) error {
+	sqlAdd := `
+	INSERT INTO mount_checks(
+		tc_id,
+		pod,
+		pvc,
+		node,
+		path,
+		options,
+		propagation,
+		valid,
+		message
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, mc := range checks {
+		tcIDs[mc.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			mc.TcID,
+			mc.Pod,
+			mc.PVC,
+			mc.Node,
+			mc.Path,
+			mc.Options,
+			mc.Propagation,
+			mc.Valid,
+			mc.Message,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if mc.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetMountChecks queries results of mount validation from db
+func (ss *SQLiteStore) GetMountChecks(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]MountCheck, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "mount_checks")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []MountCheck
+
+	for rows.Next() {
+		mc := MountCheck{}
+		if err = rows.Scan(
+			&mc.ID,
+			&mc.TcID,
+			&mc.Pod,
+			&mc.PVC,
+			&mc.Node,
+			&mc.Path,
+			&mc.Options,
+			&mc.Propagation,
+			&mc.Valid,
+			&mc.Message); err == nil {
+			checks = append(checks, mc)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+// SaveVolumeStats adds volume usage reported by kubelet to db
+func (ss *SQLiteStore) SaveVolumeStats(stats []*VolumeStat) error {
+	sqlAdd := `
+	INSERT INTO volume_stats(
+		tc_id,
+		pvc,
+		node,
+		capacity,
+		used,
+		available,
+		written,
+		valid,
+		message
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, vs := range stats {
+		tcIDs[vs.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			vs.TcID,
+			vs.PVC,
+			vs.Node,
+			vs.Capacity,
+			vs.Used,
+			vs.Available,
+			vs.Written,
+			vs.Valid,
+			vs.Message,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if vs.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetVolumeStats queries volume usage reported by kubelet from db
+func (ss *SQLiteStore) GetVolumeStats(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]VolumeStat, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "volume_stats")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []VolumeStat
+
+	for rows.Next() {
+		vs := VolumeStat{}
+		if err = rows.Scan(
+			&vs.ID,
+			&vs.TcID,
+			&vs.PVC,
+			&vs.Node,
+			&vs.Capacity,
+			&vs.Used,
+			&vs.Available,
+			&vs.Written,
+			&vs.Valid,
+			&vs.Message); err == nil {
+			stats = append(stats, vs)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// SaveMountOptionResults adds outcomes of mount option sets to db
+func (ss *SQLiteStore) SaveMountOptionResults(results []*MountOptionResult) error {
+	sqlAdd := `
+	INSERT INTO mount_option_results(
+		tc_id,
+		option_set,
+		options,
+		outcome,
+		expect_failure,
+		applied,
+		passed,
+		message
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, mr := range results {
+		tcIDs[mr.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			mr.TcID,
+			mr.Set,
+			mr.Options,
+			mr.Outcome,
+			mr.ExpectFailure,
+			mr.Applied,
+			mr.Passed,
+			mr.Message,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if mr.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetMountOptionResults queries outcomes of mount option sets from db
+func (ss *SQLiteStore) GetMountOptionResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]MountOptionResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "mount_option_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []MountOptionResult
+
+	for rows.Next() {
+		mr := MountOptionResult{}
+		if err = rows.Scan(
+			&mr.ID,
+			&mr.TcID,
+			&mr.Set,
+			&mr.Options,
+			&mr.Outcome,
+			&mr.ExpectFailure,
+			&mr.Applied,
+			&mr.Passed,
+			&mr.Message); err == nil {
+			results = append(results, mr)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveCapacityFillResults adds outcomes of backend pool exhaustion to db
+func (ss *SQLiteStore) SaveCapacityFillResults(results []*CapacityFillResult) error {
+	sqlAdd := `
+	INSERT INTO capacity_fill_results(
+		tc_id,
+		volume_size,
+		bound,
+		exhausted,
+		error_code,
+		message,
+		healthy,
+		restored,
+		passed
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, cr := range results {
+		tcIDs[cr.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			cr.TcID,
+			cr.VolumeSize,
+			cr.Bound,
+			cr.Exhausted,
+			cr.ErrorCode,
+			cr.Message,
+			cr.Healthy,
+			cr.Restored,
+			cr.Passed,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if cr.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetCapacityFillResults queries outcomes of backend pool exhaustion from db
+func (ss *SQLiteStore) GetCapacityFillResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]CapacityFillResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "capacity_fill_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []CapacityFillResult
+
+	for rows.Next() {
+		cr := CapacityFillResult{}
+		if err = rows.Scan(
+			&cr.ID,
+			&cr.TcID,
+			&cr.VolumeSize,
+			&cr.Bound,
+			&cr.Exhausted,
+			&cr.ErrorCode,
+			&cr.Message,
+			&cr.Healthy,
+			&cr.Restored,
+			&cr.Passed); err == nil {
+			results = append(results, cr)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveMountRecoveryResults adds outcomes of mount failure recovery to db
+func (ss *SQLiteStore) SaveMountRecoveryResults(results []*MountRecoveryResult) error {
+	sqlAdd := `
+	INSERT INTO mount_recovery_results(
+		tc_id,
+		pod,
+		node,
+		retries,
+		first_failure,
+		recovery,
+		total,
+		recovered
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, mr := range results {
+		tcIDs[mr.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			mr.TcID,
+			mr.Pod,
+			mr.Node,
+			mr.Retries,
+			mr.FirstFailure,
+			mr.Recovery,
+			mr.Total,
+			mr.Recovered,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if mr.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetMountRecoveryResults queries outcomes of mount failure recovery from db
+func (ss *SQLiteStore) GetMountRecoveryResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]MountRecoveryResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "mount_recovery_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []MountRecoveryResult
+
+	for rows.Next() {
+		mr := MountRecoveryResult{}
+		if err = rows.Scan(
+			&mr.ID,
+			&mr.TcID,
+			&mr.Pod,
+			&mr.Node,
+			&mr.Retries,
+			&mr.FirstFailure,
+			&mr.Recovery,
+			&mr.Total,
+			&mr.Recovered); err == nil {
+			results = append(results, mr)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveConcurrentWriteResults adds what validator found in files concurrently appended to by RWX writers to db
+func (ss *SQLiteStore) SaveConcurrentWriteResults(results []*ConcurrentWriteResult) error {
+	sqlAdd := `
+	INSERT INTO concurrent_write_results(
+		tc_id,
+		mode,
+		file,
+		writers,
+		nodes,
+		expected,
+		intact,
+		lost,
+		corrupted,
+		size,
+		expected_size,
+		passed
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, cw := range results {
+		tcIDs[cw.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			cw.TcID,
+			cw.Mode,
+			cw.File,
+			cw.Writers,
+			cw.Nodes,
+			cw.Expected,
+			cw.Intact,
+			cw.Lost,
+			cw.Corrupted,
+			cw.Size,
+			cw.ExpectedSize,
+			cw.Passed,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if cw.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetConcurrentWriteResults queries what validator found in files concurrently appended to by RWX writers from db
+func (ss *SQLiteStore) GetConcurrentWriteResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]ConcurrentWriteResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "concurrent_write_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []ConcurrentWriteResult
+
+	for rows.Next() {
+		cw := ConcurrentWriteResult{}
+		if err = rows.Scan(
+			&cw.ID,
+			&cw.TcID,
+			&cw.Mode,
+			&cw.File,
+			&cw.Writers,
+			&cw.Nodes,
+			&cw.Expected,
+			&cw.Intact,
+			&cw.Lost,
+			&cw.Corrupted,
+			&cw.Size,
+			&cw.ExpectedSize,
+			&cw.Passed); err == nil {
+			results = append(results, cw)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveEntityPlacements adds nodes, zones and backends entities were placed on to db
+func (ss *SQLiteStore) SaveEntityPlacements(placements []*EntityPlacement) error {
+	sqlAdd := `
+	INSERT INTO entity_placements(
+		tc_id,
+		entity_id,
+		node,
+		zone,
+		backend
+	) VALUES (?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, ep := range placements {
+		tcIDs[ep.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			ep.TcID,
+			ep.EntityID,
+			ep.Node,
+			ep.Zone,
+			ep.Backend,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if ep.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetEntityPlacements queries nodes, zones and backends entities were placed on from db
+func (ss *SQLiteStore) GetEntityPlacements(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]EntityPlacement, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "entity_placements")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var placements []EntityPlacement
+
+	for rows.Next() {
+		ep := EntityPlacement{}
+		if err = rows.Scan(
+			&ep.ID,
+			&ep.TcID,
+			&ep.EntityID,
+			&ep.Node,
+			&ep.Zone,
+			&ep.Backend); err == nil {
+			placements = append(placements, ep)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return placements, nil
+}
+
+// SaveControllerFailoverResults adds how driver controller recovered from disruptions to db
+func (ss *SQLiteStore) SaveControllerFailoverResults(results []*ControllerFailoverResult) error {
+	sqlAdd := `
+	INSERT INTO controller_failover_results(
+		tc_id,
+		deployment,
+		disruption,
+		replicas,
+		leases,
+		lease,
+		old_leader,
+		new_leader,
+		handover,
+		handed_over,
+		stall,
+		volumes,
+		provisioned,
+		passed
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
 
-	stmt, err := ss.db.Prepare(sqlStmt)
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, cf := range results {
+		tcIDs[cf.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			cf.TcID,
+			cf.Deployment,
+			cf.Disruption,
+			cf.Replicas,
+			cf.Leases,
+			cf.Lease,
+			cf.OldLeader,
+			cf.NewLeader,
+			cf.Handover,
+			cf.HandedOver,
+			cf.Stall,
+			cf.Volumes,
+			cf.Provisioned,
+			cf.Passed,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if cf.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetControllerFailoverResults queries how driver controller recovered from disruptions from db
+func (ss *SQLiteStore) GetControllerFailoverResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]ControllerFailoverResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "controller_failover_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []ControllerFailoverResult
+
+	for rows.Next() {
+		cf := ControllerFailoverResult{}
+		if err = rows.Scan(
+			&cf.ID,
+			&cf.TcID,
+			&cf.Deployment,
+			&cf.Disruption,
+			&cf.Replicas,
+			&cf.Leases,
+			&cf.Lease,
+			&cf.OldLeader,
+			&cf.NewLeader,
+			&cf.Handover,
+			&cf.HandedOver,
+			&cf.Stall,
+			&cf.Volumes,
+			&cf.Provisioned,
+			&cf.Passed); err == nil {
+			results = append(results, cf)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveDetachProbeResults adds what was left of volumes on nodes they were detached from to db
+func (ss *SQLiteStore) SaveDetachProbeResults(results []*DetachProbeResult) error {
+	sqlAdd := `
+	INSERT INTO detach_probe_results(
+		tc_id,
+		pvc,
+		old_node,
+		new_node,
+		device,
+		device_state,
+		stale_mounts,
+		detach,
+		consistent,
+		passed
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
 	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, dp := range results {
+		tcIDs[dp.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			dp.TcID,
+			dp.PVC,
+			dp.OldNode,
+			dp.NewNode,
+			dp.Device,
+			dp.DeviceState,
+			dp.StaleMounts,
+			dp.Detach,
+			dp.Consistent,
+			dp.Passed,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if dp.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetDetachProbeResults queries what was left of volumes on nodes they were detached from from db
+func (ss *SQLiteStore) GetDetachProbeResults(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]DetachProbeResult, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "detach_probe_results")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []DetachProbeResult
+
+	for rows.Next() {
+		dp := DetachProbeResult{}
+		if err = rows.Scan(
+			&dp.ID,
+			&dp.TcID,
+			&dp.PVC,
+			&dp.OldNode,
+			&dp.NewNode,
+			&dp.Device,
+			&dp.DeviceState,
+			&dp.StaleMounts,
+			&dp.Detach,
+			&dp.Consistent,
+			&dp.Passed); err == nil {
+			results = append(results, dp)
+		}
+	}
+	if err = rows.Err(); err != nil {
 		return nil, err
 	}
+	return results, nil
+}
+
+// SaveFaultWindows adds faults injected by fault hook and IO of writers during them to db
+func (ss *SQLiteStore) SaveFaultWindows(windows []*FaultWindow) error {
+	sqlAdd := `
+	INSERT INTO fault_windows(
+		tc_id,
+		step,
+		node,
+		start_timestamp,
+		end_timestamp,
+		ops,
+		errors,
+		ops_after,
+		errors_after,
+		recovery,
+		recovered
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(eType, tc.ID)
+	tcIDs := make(map[int64]struct{})
+	for _, fw := range windows {
+		tcIDs[fw.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			fw.TcID,
+			fw.Step,
+			fw.Node,
+			fw.Start,
+			fw.End,
+			fw.Ops,
+			fw.Errors,
+			fw.OpsAfter,
+			fw.ErrorsAfter,
+			fw.Recovery,
+			fw.Recovered,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if fw.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetFaultWindows queries faults injected by fault hook from db
+func (ss *SQLiteStore) GetFaultWindows(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]FaultWindow, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "fault_windows")
+	rows, err := ss.db.Query(sqlStmt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	ewe := make(map[Entity][]Event)
+	var windows []FaultWindow
+
+	for rows.Next() {
+		fw := FaultWindow{}
+		if err = rows.Scan(
+			&fw.ID,
+			&fw.TcID,
+			&fw.Step,
+			&fw.Node,
+			&fw.Start,
+			&fw.End,
+			&fw.Ops,
+			&fw.Errors,
+			&fw.OpsAfter,
+			&fw.ErrorsAfter,
+			&fw.Recovery,
+			&fw.Recovered); err == nil {
+			windows = append(windows, fw)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// SaveSeriesRuns links test runs into certification series, run linked again replaces its previous link
+func (ss *SQLiteStore) SaveSeriesRuns(runs []*SeriesRun) error {
+	sqlAdd := `
+	INSERT OR REPLACE INTO series_runs(
+		series,
+		run_id,
+		driver_version,
+		added_timestamp
+	) VALUES (?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	for _, sr := range runs {
+		result, err := stmt.Exec(
+			sr.Series,
+			sr.RunID,
+			sr.DriverVersion,
+			sr.AddedTimestamp,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if sr.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSeriesRuns queries links of test runs into certification series from db
+func (ss *SQLiteStore) GetSeriesRuns(whereConditions Conditions, orderBy string, limit int) ([]SeriesRun, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "series_runs")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []SeriesRun
+
+	for rows.Next() {
+		sr := SeriesRun{}
+		if err = rows.Scan(
+			&sr.ID,
+			&sr.Series,
+			&sr.RunID,
+			&sr.DriverVersion,
+			&sr.AddedTimestamp); err == nil {
+			runs = append(runs, sr)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// SaveLatencySamples adds latencies measured by suites to db
+func (ss *SQLiteStore) SaveLatencySamples(samples []*LatencySample) error {
+	sqlAdd := `
+	INSERT INTO latency_samples(
+		tc_id,
+		metric,
+		source,
+		value,
+		timestamp
+	) VALUES (?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, ls := range samples {
+		tcIDs[ls.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			ls.TcID,
+			ls.Metric,
+			ls.Source,
+			ls.Value,
+			ls.Timestamp,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if ls.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetLatencySamples queries latencies measured by suites from db
+func (ss *SQLiteStore) GetLatencySamples(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]LatencySample, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "latency_samples")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []LatencySample
+
+	for rows.Next() {
+		ls := LatencySample{}
+		if err = rows.Scan(
+			&ls.ID,
+			&ls.TcID,
+			&ls.Metric,
+			&ls.Source,
+			&ls.Value,
+			&ls.Timestamp); err == nil {
+			samples = append(samples, ls)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// SaveExpansionOutcomes adds outcomes of volume expansions to db
+func (ss *SQLiteStore) SaveExpansionOutcomes(outcomes []*ExpansionOutcome) error {
+	sqlAdd := `
+	INSERT INTO expansion_outcomes(
+		tc_id,
+		pvc,
+		outcome,
+		controller,
+		node,
+		message
+	) VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
+	if err != nil {
+		logrus.Errorf("Can't prepare statement")
+		return err
+	}
+	defer stmt.Close()
+
+	tcIDs := make(map[int64]struct{})
+	for _, eo := range outcomes {
+		tcIDs[eo.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			eo.TcID,
+			eo.PVC,
+			string(eo.Outcome),
+			eo.Controller,
+			eo.Node,
+			eo.Message,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if eo.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
+	}
+
+	return invalidateMetricsCache(ss.db, tcIDs)
+}
+
+// GetExpansionOutcomes queries outcomes of volume expansions from db
+func (ss *SQLiteStore) GetExpansionOutcomes(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]ExpansionOutcome, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "expansion_outcomes")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outcomes []ExpansionOutcome
+
 	for rows.Next() {
-		en := Entity{}
-		ev := Event{}
+		eo := ExpansionOutcome{}
+		var outcome string
 		if err = rows.Scan(
-			&en.ID, &en.Name, &en.K8sUID, &en.TcID, &en.Type,
-			&ev.ID, &ev.Name, &ev.TcID, &ev.EntityID, &ev.Type, &ev.Timestamp); err == nil {
-			if events, ok := ewe[en]; ok {
-				ewe[en] = append(events, ev)
-			} else {
-				ewe[en] = []Event{ev}
-			}
+			&eo.ID,
+			&eo.TcID,
+			&eo.PVC,
+			&outcome,
+			&eo.Controller,
+			&eo.Node,
+			&eo.Message); err == nil {
+			eo.Outcome = ExpansionOutcomeEnum(outcome)
+			outcomes = append(outcomes, eo)
 		}
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-
-	return ewe, nil
+	return outcomes, nil
 }
 
-// SaveTestCase saves testcases in db
-func (ss *SQLiteStore) SaveTestCase(ts *TestCase) error {
-	sqlStmt := `
-	INSERT INTO test_cases(name, parameters, start_timestamp, end_timestamp, success, error_msg, run_id)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+// SaveBackendLeaks adds backend objects left behind by test run to db
+func (ss *SQLiteStore) SaveBackendLeaks(leaks []*BackendLeak) error {
+	sqlAdd := `
+	INSERT INTO backend_leaks(
+		run_id,
+		kind,
+		name,
+		timestamp
+	) VALUES (?, ?, ?, ?)
 	`
-	stmt, err := ss.db.Prepare(sqlStmt)
+
+	stmt, err := ss.db.Prepare(sqlAdd)
 	if err != nil {
+		logrus.Errorf("Can't prepare statement")
 		return err
 	}
 	defer stmt.Close()
 
-	result, err := stmt.Exec(ts.Name, ts.Parameters, ts.StartTimestamp, ts.EndTimestamp, ts.Success, ts.ErrorMessage, ts.RunID)
-	if err != nil {
-		return err
-	}
-	if ts.ID, err = result.LastInsertId(); err != nil {
-		return err
+	for _, l := range leaks {
+		result, err := stmt.Exec(
+			l.RunID,
+			l.Kind,
+			l.Name,
+			l.Timestamp,
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if l.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
 	}
+
 	return nil
 }
 
-// GetTestCases queries testcases from db
-func (ss *SQLiteStore) GetTestCases(whereConditions Conditions, orderBy string, limit int) ([]TestCase, error) {
-	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "test_cases")
+// GetBackendLeaks queries backend objects left behind by test runs from db
+func (ss *SQLiteStore) GetBackendLeaks(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]BackendLeak, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "backend_leaks")
 	rows, err := ss.db.Query(sqlStmt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var testCases []TestCase
+	var leaks []BackendLeak
 
 	for rows.Next() {
-		tc := TestCase{}
+		l := BackendLeak{}
 		if err = rows.Scan(
-			&tc.ID, &tc.Name, &tc.Parameters, &tc.StartTimestamp, &tc.EndTimestamp, &tc.Success, &tc.ErrorMessage, &tc.RunID); err == nil {
-			testCases = append(testCases, tc)
+			&l.ID,
+			&l.RunID,
+			&l.Kind,
+			&l.Name,
+			&l.Timestamp); err == nil {
+			leaks = append(leaks, l)
 		}
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-
-	return testCases, nil
+	return leaks, nil
 }
 
-func (ss *SQLiteStore) updateStatusTestCase(ts *TestCase) error {
-	_, err := ss.db.Exec(
-		"UPDATE test_cases SET success=?, end_timestamp=?, error_msg=? WHERE id=?",
-		ts.Success, ts.EndTimestamp, ts.ErrorMessage, ts.ID)
+// SaveTeardownLatencies adds teardown latencies of test cases to db
+func (ss *SQLiteStore) SaveTeardownLatencies(latencies []*TeardownLatency) error {
+	sqlAdd := `
+	INSERT INTO teardown_latencies(
+		tc_id,
+		kind,
+		count,
+		remaining,
+		avg,
+		max
+	) VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := ss.db.Prepare(sqlAdd)
 	if err != nil {
+		logrus.Errorf("Can't prepare statement")
 		return err
 	}
-	return nil
-}
+	defer stmt.Close()
 
-// SuccessfulTestCase updates testcase status as success
-func (ss *SQLiteStore) SuccessfulTestCase(ts *TestCase, endTimestamp time.Time) error {
-	ts.Success = true
-	ts.EndTimestamp = endTimestamp
-	if err := ss.updateStatusTestCase(ts); err != nil {
-		return err
+	tcIDs := make(map[int64]struct{})
+	for _, l := range latencies {
+		tcIDs[l.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			l.TcID,
+			l.Kind,
+			l.Count,
+			l.Remaining,
+			int64(l.Avg),
+			int64(l.Max),
+		)
+		if err != nil {
+			logrus.Errorf("Can't execute statement")
+			return err
+		}
+		if l.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
+			return err
+		}
 	}
-	return nil
+
+	return invalidateMetricsCache(ss.db, tcIDs)
 }
 
-// FailedTestCase updates testcase status as failure
-func (ss *SQLiteStore) FailedTestCase(ts *TestCase, endTimestamp time.Time, errMsg string) error {
-	ts.Success = false
-	ts.EndTimestamp = endTimestamp
-	ts.ErrorMessage = errMsg
-	if err := ss.updateStatusTestCase(ts); err != nil {
-		return err
+// GetTeardownLatencies queries teardown latencies from db
+func (ss *SQLiteStore) GetTeardownLatencies(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]TeardownLatency, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "teardown_latencies")
+	rows, err := ss.db.Query(sqlStmt)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	defer rows.Close()
+
+	var latencies []TeardownLatency
+
+	for rows.Next() {
+		l := TeardownLatency{}
+		if err = rows.Scan(
+			&l.ID,
+			&l.TcID,
+			&l.Kind,
+			&l.Count,
+			&l.Remaining,
+			&l.Avg,
+			&l.Max); err == nil {
+			latencies = append(latencies, l)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return latencies, nil
 }
 
-// SaveEntities saves entities in db
-func (ss *SQLiteStore) SaveEntities(entities []*Entity) error {
-	sqlAddEvent := `
-	INSERT INTO entities(name, k8s_uid, tc_id, type
-	) VALUES (?, ?, ?, ?)
+// SaveTeardownTiers adds teardown tiers of test cases to db
+func (ss *SQLiteStore) SaveTeardownTiers(tiers []*TeardownTier) error {
+	sqlAdd := `
+	INSERT INTO teardown_tiers(
+		tc_id,
+		tier,
+		position,
+		duration,
+		deleted,
+		remaining,
+		message
+	) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	stmt, err := ss.db.Prepare(sqlAddEvent)
+	stmt, err := ss.db.Prepare(sqlAdd)
 	if err != nil {
+		logrus.Errorf("Can't prepare statement")
 		return err
 	}
 	defer stmt.Close()
 
-	for _, e := range entities {
-		result, err := stmt.Exec(e.Name, e.K8sUID, e.TcID, e.Type)
+	tcIDs := make(map[int64]struct{})
+	for _, t := range tiers {
+		tcIDs[t.TcID] = struct{}{}
+		result, err := stmt.Exec(
+			t.TcID,
+			t.Tier,
+			t.Position,
+			int64(t.Duration),
+			t.Deleted,
+			t.Remaining,
+			t.Message,
+		)
 		if err != nil {
+			logrus.Errorf("Can't execute statement")
 			return err
 		}
-		if e.ID, err = result.LastInsertId(); err != nil {
+		if t.ID, err = result.LastInsertId(); err != nil {
+			logrus.Errorf("Can't find last insert id")
 			return err
 		}
 	}
 
-	return nil
+	return invalidateMetricsCache(ss.db, tcIDs)
 }
 
-// GetEntities queries entities from db
-func (ss *SQLiteStore) GetEntities(whereConditions Conditions, orderBy string, limit int) ([]Entity, error) {
-	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "entities")
+// GetTeardownTiers queries teardown tiers from db
+func (ss *SQLiteStore) GetTeardownTiers(
+	whereConditions Conditions,
+	orderBy string,
+	limit int,
+) ([]TeardownTier, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "teardown_tiers")
 	rows, err := ss.db.Query(sqlStmt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var entities []Entity
+	var tiers []TeardownTier
 
 	for rows.Next() {
-		entity := Entity{}
+		t := TeardownTier{}
 		if err = rows.Scan(
-			&entity.ID, &entity.Name, &entity.K8sUID, &entity.TcID, &entity.Type); err == nil {
-			entities = append(entities, entity)
+			&t.ID,
+			&t.TcID,
+			&t.Tier,
+			&t.Position,
+			&t.Duration,
+			&t.Deleted,
+			&t.Remaining,
+			&t.Message); err == nil {
+			tiers = append(tiers, t)
 		}
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-
-	return entities, nil
+	return tiers, nil
 }
 
-// SaveNumberEntities saves NumberEntities in db
-func (ss *SQLiteStore) SaveNumberEntities(nEntities []*NumberEntities) error {
+// SaveAuditEntries adds audit log entries of entities to db, entries already saved for the entity are skipped,
+// it returns number of added entries
+func (ss *SQLiteStore) SaveAuditEntries(entries []*AuditEntry) (int, error) {
 	sqlAdd := `
-	INSERT INTO number_entities(
+	INSERT OR IGNORE INTO audit_entries(
 		tc_id,
+		entity_id,
+		audit_id,
 		timestamp,
-		pods_creating,
-		pods_ready,
-		pods_terminating,
-		pvc_creating,
-		pvc_bound,
-		pvc_terminating
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		verb,
+		resource,
+		subresource,
+		name,
+		user,
+		user_agent,
+		code
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, err := ss.db.Prepare(sqlAdd)
 	if err != nil {
-		return err
+		logrus.Errorf("Can't prepare statement")
+		return 0, err
 	}
 	defer stmt.Close()
 
-	for _, e := range nEntities {
+	added := 0
+	tcIDs := make(map[int64]struct{})
+	for _, e := range entries {
 		result, err := stmt.Exec(
 			e.TcID,
+			e.EntityID,
+			e.AuditID,
 			e.Timestamp,
-			e.PodsCreating,
-			e.PodsReady,
-			e.PodsTerminating,
-			e.PvcCreating,
-			e.PvcBound,
-			e.PvcTerminating)
+			e.Verb,
+			e.Resource,
+			e.Subresource,
+			e.Name,
+			e.User,
+			e.UserAgent,
+			e.Code,
+		)
 		if err != nil {
-			return err
+			logrus.Errorf("Can't execute statement")
+			return added, err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			continue
 		}
 		if e.ID, err = result.LastInsertId(); err != nil {
-			return err
+			logrus.Errorf("Can't find last insert id")
+			return added, err
 		}
+		tcIDs[e.TcID] = struct{}{}
+		added++
 	}
 
-	return nil
+	return added, invalidateMetricsCache(ss.db, tcIDs)
 }
 
-// GetNumberEntities queries NumberEntities from db
-func (ss *SQLiteStore) GetNumberEntities(
+// GetAuditEntries queries audit log entries from db
+func (ss *SQLiteStore) GetAuditEntries(
 	whereConditions Conditions,
 	orderBy string,
 	limit int,
-) ([]NumberEntities, error) {
-	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "number_entities")
+) ([]AuditEntry, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "audit_entries")
 	rows, err := ss.db.Query(sqlStmt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var nEntities []NumberEntities
+	var entries []AuditEntry
 
 	for rows.Next() {
-		e := NumberEntities{}
+		e := AuditEntry{}
 		if err = rows.Scan(
 			&e.ID,
 			&e.TcID,
+			&e.EntityID,
+			&e.AuditID,
 			&e.Timestamp,
-			&e.PodsCreating,
-			&e.PodsReady,
-			&e.PodsTerminating,
-			&e.PvcCreating,
-			&e.PvcBound,
-			&e.PvcTerminating); err == nil {
-			nEntities = append(nEntities, e)
+			&e.Verb,
+			&e.Resource,
+			&e.Subresource,
+			&e.Name,
+			&e.User,
+			&e.UserAgent,
+			&e.Code); err == nil {
+			entries = append(entries, e)
 		}
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	return nEntities, nil
+	return entries, nil
 }
 
-// SaveResourceUsage saves resource usage in db
-func (ss *SQLiteStore) SaveResourceUsage(resUsages []*ResourceUsage) error {
-	sqlAdd := `
-	INSERT INTO resource_usage(
+// SaveTimeline replaces merged timeline of test case with entries
+func (ss *SQLiteStore) SaveTimeline(tcID int64, entries []*TimelineEntry) error {
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM timeline_entries WHERE tc_id = ?", tcID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare(`
+	INSERT INTO timeline_entries(
 		tc_id,
 		timestamp,
-		pod_name,
-		container_name,
-		cpu_value,
-		mem_value
+		source,
+		object,
+		message,
+		corrected
 	) VALUES (?, ?, ?, ?, ?, ?)
-	`
-
-	stmt, err := ss.db.Prepare(sqlAdd)
+	`)
 	if err != nil {
+		_ = tx.Rollback()
 		logrus.Errorf("Can't prepare statement")
 		return err
 	}
 	defer stmt.Close()
 
-	for _, e := range resUsages {
-		result, err := stmt.Exec(
-			e.TcID,
-			e.Timestamp,
-			e.PodName,
-			e.ContainerName,
-			e.CPU,
-			e.Mem,
-		)
+	for _, e := range entries {
+		e.TcID = tcID
+		result, err := stmt.Exec(e.TcID, e.Timestamp, e.Source, e.Object, e.Message, e.Corrected)
 		if err != nil {
+			_ = tx.Rollback()
 			logrus.Errorf("Can't execute statement")
 			return err
 		}
 		if e.ID, err = result.LastInsertId(); err != nil {
+			_ = tx.Rollback()
 			logrus.Errorf("Can't find last insert id")
 			return err
 		}
 	}
-
-	return nil
+	if err := invalidateMetricsCache(tx, map[int64]struct{}{tcID: {}}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
-// GetResourceUsage queries resource usage from db
-func (ss *SQLiteStore) GetResourceUsage(
+// GetTimelineEntries queries merged timeline entries from db
+func (ss *SQLiteStore) GetTimelineEntries(
 	whereConditions Conditions,
 	orderBy string,
 	limit int,
-) ([]ResourceUsage, error) {
-	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "resource_usage")
+) ([]TimelineEntry, error) {
+	sqlStmt := ss.prepareSQLSelectStmt(whereConditions, orderBy, limit, "timeline_entries")
 	rows, err := ss.db.Query(sqlStmt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var resUsage []ResourceUsage
+	var entries []TimelineEntry
 
 	for rows.Next() {
-		e := ResourceUsage{}
+		e := TimelineEntry{}
 		if err = rows.Scan(
 			&e.ID,
 			&e.TcID,
 			&e.Timestamp,
-			&e.PodName,
-			&e.ContainerName,
-			&e.CPU,
-			&e.Mem); err == nil {
-			resUsage = append(resUsage, e)
+			&e.Source,
+			&e.Object,
+			&e.Message,
+			&e.Corrected); err == nil {
+			entries = append(entries, e)
 		}
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	return resUsage, nil
+	return entries, nil
+}
+
+// SaveMetricsCache adds or replaces cached metrics of test case in db
+func (ss *SQLiteStore) SaveMetricsCache(cache *MetricsCache) error {
+	sqlAdd := `
+	INSERT OR REPLACE INTO metrics_cache(
+		tc_id,
+		data,
+		updated_timestamp
+	) VALUES (?, ?, ?)
+	`
+	if _, err := ss.db.Exec(sqlAdd, cache.TcID, cache.Data, cache.UpdatedTimestamp); err != nil {
+		logrus.Errorf("Can't execute statement")
+		return err
+	}
+	return nil
+}
+
+// GetMetricsCache queries cached metrics of test case from db, returns nil if there are none
+func (ss *SQLiteStore) GetMetricsCache(tcID int64) (*MetricsCache, error) {
+	cache := &MetricsCache{}
+	err := ss.db.QueryRow("SELECT tc_id, data, updated_timestamp FROM metrics_cache WHERE tc_id = ?", tcID).Scan(
+		&cache.TcID,
+		&cache.Data,
+		&cache.UpdatedTimestamp,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cache, nil
 }
 
 // CreateEntitiesRelation adds EntitiesRelation to db
@@ -673,8 +3216,94 @@ func (ss *SQLiteStore) GetEntityRelations(entity Entity) ([]Entity, error) {
 	return entities, nil
 }
 
+// RunLockedError is returned when db is locked by another cert-csi process
+type RunLockedError struct {
+	Lock RunLock
+}
+
+func (e *RunLockedError) Error() string {
+	return fmt.Sprintf("database is locked by process %d on %s since %s, use --force-unlock if that run is no longer alive",
+		e.Lock.Pid, e.Lock.Hostname, e.Lock.AcquiredTimestamp.Format(time.RFC3339))
+}
+
+// AcquireRunLock takes advisory lock of db, so that two cert-csi processes can't interleave writes of their runs.
+// Lock held by another process is taken over if force is true
+func (ss *SQLiteStore) AcquireRunLock(force bool) error {
+	if ss.lock != nil {
+		return nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	lock := &RunLock{
+		Owner:             fmt.Sprintf("%s/%d/%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		Pid:               os.Getpid(),
+		Hostname:          hostname,
+		AcquiredTimestamp: time.Now(),
+	}
+
+	sqlAdd := "INSERT INTO run_lock(id, owner, pid, hostname, acquired_timestamp) VALUES (1, ?, ?, ?, ?)"
+	if force {
+		sqlAdd = "INSERT OR REPLACE INTO run_lock(id, owner, pid, hostname, acquired_timestamp) VALUES (1, ?, ?, ?, ?)"
+	}
+
+	if _, err := ss.db.Exec(sqlAdd, lock.Owner, lock.Pid, lock.Hostname, lock.AcquiredTimestamp); err != nil {
+		holder := RunLock{}
+		row := ss.db.QueryRow("SELECT owner, pid, hostname, acquired_timestamp FROM run_lock WHERE id = 1")
+		if scanErr := row.Scan(&holder.Owner, &holder.Pid, &holder.Hostname, &holder.AcquiredTimestamp); scanErr != nil {
+			return err
+		}
+		return &RunLockedError{holder}
+	}
+	if force {
+		logrus.Warnf("Forcibly took over run lock of database")
+	}
+
+	ss.lock = lock
+	return nil
+}
+
+// ReleaseRunLock releases advisory lock of db if it is held by this store
+func (ss *SQLiteStore) ReleaseRunLock() error {
+	if ss.lock == nil {
+		return nil
+	}
+	if _, err := ss.db.Exec("DELETE FROM run_lock WHERE owner = ?", ss.lock.Owner); err != nil {
+		return err
+	}
+	ss.lock = nil
+	return nil
+}
+
+// AcquireRunLocks takes run locks of dbs of all storage classes, locks already taken are released if one can't be taken
+func AcquireRunLocks(scDBs []*StorageClassDB, force bool) error {
+	for i, scDB := range scDBs {
+		if err := scDB.DB.AcquireRunLock(force); err != nil {
+			for _, locked := range scDBs[:i] {
+				if relErr := locked.DB.ReleaseRunLock(); relErr != nil {
+					logrus.Errorf("Can't release run lock; error=%v", relErr)
+				}
+			}
+			return fmt.Errorf("can't use database of storage class %s: %w", scDB.StorageClass, err)
+		}
+	}
+	return nil
+}
+
+// Size returns size of db in bytes
+func (ss *SQLiteStore) Size() (int64, error) {
+	var size int64
+	err := ss.db.QueryRow("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&size)
+	return size, err
+}
+
 // Close closes db handle
 func (ss *SQLiteStore) Close() error {
+	if err := ss.ReleaseRunLock(); err != nil {
+		logrus.Errorf("Can't release run lock; error=%v", err)
+	}
 	if err := ss.db.Close(); err != nil {
 		return err
 	}
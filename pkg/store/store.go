@@ -30,20 +30,79 @@ type Conditions map[string]interface{}
 type Store interface {
 	SaveTestRun(tr *TestRun) error
 	GetTestRuns(whereConditions Conditions, orderBy string, limit int) ([]TestRun, error)
+	AbortedTestRun(tr *TestRun, reason string) error
+	TruncatedTestRun(tr *TestRun, reason string) error
+	SaveNameCollisions(tr *TestRun, collisions int) error
+	SaveClassDrift(tr *TestRun, drift string) error
+	SaveRunLoad(tr *TestRun, load string) error
+	MarkBaselineRun(tr *TestRun) error
 	SaveEvents(events []*Event) error
 	GetEvents(whereConditions Conditions, orderBy string, limit int) ([]Event, error)
 	SaveTestCase(ts *TestCase) error
 	GetTestCases(whereConditions Conditions, orderBy string, limit int) ([]TestCase, error)
 	SuccessfulTestCase(ts *TestCase, endTimestamp time.Time) error
 	FailedTestCase(ts *TestCase, endTimestamp time.Time, errMsg string) error
+	SkippedTestCase(ts *TestCase, endTimestamp time.Time, reason string) error
+	SaveClockSkew(ts *TestCase, skew time.Duration) error
 	SaveEntities(entities []*Entity) error
 	GetEntities(whereConditions Conditions, orderBy string, limit int) ([]Entity, error)
 	GetEntitiesWithEventsByTestCaseAndEntityType(tc *TestCase, eType EntityTypeEnum) (map[Entity][]Event, error)
 	SaveNumberEntities(nEntities []*NumberEntities) error
 	GetNumberEntities(whereConditions Conditions, orderBy string, limit int) ([]NumberEntities, error)
+	EachNumberEntities(whereConditions Conditions, orderBy string, fn func(NumberEntities)) error
 	SaveResourceUsage(resUsages []*ResourceUsage) error
 	GetResourceUsage(whereConditions Conditions, orderBy string, limit int) ([]ResourceUsage, error)
+	EachResourceUsage(whereConditions Conditions, orderBy string, fn func(ResourceUsage)) error
+	SaveAssertionResults(results []*AssertionResult) error
+	GetAssertionResults(whereConditions Conditions, orderBy string, limit int) ([]AssertionResult, error)
+	SaveOrphans(orphans []*Orphan) error
+	GetOrphans(whereConditions Conditions, orderBy string, limit int) ([]Orphan, error)
+	SaveComparisons(comparisons []*Comparison) error
+	GetComparisons(whereConditions Conditions, orderBy string, limit int) ([]Comparison, error)
+	SaveRampStages(stages []*RampStage) error
+	GetRampStages(whereConditions Conditions, orderBy string, limit int) ([]RampStage, error)
+	SaveMountChecks(checks []*MountCheck) error
+	GetMountChecks(whereConditions Conditions, orderBy string, limit int) ([]MountCheck, error)
+	SaveVolumeStats(stats []*VolumeStat) error
+	GetVolumeStats(whereConditions Conditions, orderBy string, limit int) ([]VolumeStat, error)
+	SaveMountOptionResults(results []*MountOptionResult) error
+	GetMountOptionResults(whereConditions Conditions, orderBy string, limit int) ([]MountOptionResult, error)
+	SaveCapacityFillResults(results []*CapacityFillResult) error
+	GetCapacityFillResults(whereConditions Conditions, orderBy string, limit int) ([]CapacityFillResult, error)
+	SaveMountRecoveryResults(results []*MountRecoveryResult) error
+	GetMountRecoveryResults(whereConditions Conditions, orderBy string, limit int) ([]MountRecoveryResult, error)
+	SaveConcurrentWriteResults(results []*ConcurrentWriteResult) error
+	GetConcurrentWriteResults(whereConditions Conditions, orderBy string, limit int) ([]ConcurrentWriteResult, error)
+	SaveEntityPlacements(placements []*EntityPlacement) error
+	GetEntityPlacements(whereConditions Conditions, orderBy string, limit int) ([]EntityPlacement, error)
+	SaveControllerFailoverResults(results []*ControllerFailoverResult) error
+	GetControllerFailoverResults(whereConditions Conditions, orderBy string, limit int) ([]ControllerFailoverResult, error)
+	SaveDetachProbeResults(results []*DetachProbeResult) error
+	GetDetachProbeResults(whereConditions Conditions, orderBy string, limit int) ([]DetachProbeResult, error)
+	SaveFaultWindows(windows []*FaultWindow) error
+	GetFaultWindows(whereConditions Conditions, orderBy string, limit int) ([]FaultWindow, error)
+	SaveSeriesRuns(runs []*SeriesRun) error
+	GetSeriesRuns(whereConditions Conditions, orderBy string, limit int) ([]SeriesRun, error)
+	SaveLatencySamples(samples []*LatencySample) error
+	GetLatencySamples(whereConditions Conditions, orderBy string, limit int) ([]LatencySample, error)
+	SaveExpansionOutcomes(outcomes []*ExpansionOutcome) error
+	GetExpansionOutcomes(whereConditions Conditions, orderBy string, limit int) ([]ExpansionOutcome, error)
+	SaveBackendLeaks(leaks []*BackendLeak) error
+	GetBackendLeaks(whereConditions Conditions, orderBy string, limit int) ([]BackendLeak, error)
+	SaveTeardownLatencies(latencies []*TeardownLatency) error
+	GetTeardownLatencies(whereConditions Conditions, orderBy string, limit int) ([]TeardownLatency, error)
+	SaveTimeline(tcID int64, entries []*TimelineEntry) error
+	GetTimelineEntries(whereConditions Conditions, orderBy string, limit int) ([]TimelineEntry, error)
+	SaveTeardownTiers(tiers []*TeardownTier) error
+	GetTeardownTiers(whereConditions Conditions, orderBy string, limit int) ([]TeardownTier, error)
+	SaveAuditEntries(entries []*AuditEntry) (int, error)
+	GetAuditEntries(whereConditions Conditions, orderBy string, limit int) ([]AuditEntry, error)
+	SaveMetricsCache(cache *MetricsCache) error
+	GetMetricsCache(tcID int64) (*MetricsCache, error)
 	CreateEntitiesRelation(entity1, entity2 Entity) error
 	GetEntityRelations(event Entity) ([]Entity, error)
+	AcquireRunLock(force bool) error
+	ReleaseRunLock() error
+	Size() (int64, error)
 	Close() error
 }
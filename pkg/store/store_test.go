@@ -17,7 +17,10 @@
 package store
 
 import (
+	"database/sql"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -51,10 +54,72 @@ func (suite *StoreTestSuite) TestAllStores() {
 			StartTimestamp: time.Now(),
 			StorageClass:   "default",
 			ClusterAddress: "localhost",
+			Seed:           42,
+			Metadata:       `{"pvc":{"labels":{"team":"storage"}}}`,
+			Timeout:        5 * time.Minute,
+			ClassSpecs:     `{"StorageClass/default":{"provisioner":"csi.dell.com"}}`,
+			NotObservable:  "VolumeAttachments",
+			Capabilities:   `{"driver":"csi.dell.com","attachRequired":true}`,
+			Load:           `{"profile":{"name":"moderate"}}`,
+			RetryOf:        "test run 0",
+			Iterations:     "12,37",
+			NameGenerator:  "incremental",
 		}
 		err := store.SaveTestRun(sourceTestRun)
 		suite.NoError(err)
 
+		runs, err := store.GetTestRuns(Conditions{"name": "test run 1"}, "", 1)
+		suite.NoError(err)
+		suite.Equal(int64(42), runs[0].Seed)
+		suite.Equal(`{"pvc":{"labels":{"team":"storage"}}}`, runs[0].Metadata)
+		suite.Equal(5*time.Minute, runs[0].Timeout)
+		suite.Equal(sourceTestRun.ClassSpecs, runs[0].ClassSpecs)
+		suite.Equal("VolumeAttachments", runs[0].NotObservable)
+		suite.Equal(sourceTestRun.Capabilities, runs[0].Capabilities)
+		suite.Equal(sourceTestRun.Load, runs[0].Load)
+		suite.Equal("test run 0", runs[0].RetryOf)
+		suite.Equal("12,37", runs[0].Iterations)
+		suite.Equal("incremental", runs[0].NameGenerator)
+		suite.False(runs[0].Aborted())
+
+		suite.NoError(store.AbortedTestRun(sourceTestRun, "maintenance window"))
+		runs, err = store.GetTestRuns(Conditions{"name": "test run 1"}, "", 1)
+		suite.NoError(err)
+		suite.True(runs[0].Aborted())
+		suite.Equal("maintenance window", runs[0].AbortReason)
+
+		suite.False(runs[0].Truncated())
+		suite.NoError(store.TruncatedTestRun(sourceTestRun, "wall-clock budget of 1h0m0s exceeded"))
+		runs, err = store.GetTestRuns(Conditions{"name": "test run 1"}, "", 1)
+		suite.NoError(err)
+		suite.True(runs[0].Truncated())
+		suite.Equal("wall-clock budget of 1h0m0s exceeded", runs[0].TruncateReason)
+		size, err := store.Size()
+		suite.NoError(err)
+		suite.Positive(size)
+
+		suite.NoError(store.SaveClassDrift(sourceTestRun, `StorageClass/default: reclaimPolicy: "Delete" -> "Retain"`))
+		runs, err = store.GetTestRuns(Conditions{"name": "test run 1"}, "", 1)
+		suite.NoError(err)
+		suite.Equal(`StorageClass/default: reclaimPolicy: "Delete" -> "Retain"`, runs[0].ClassDrift)
+
+		suite.NoError(store.SaveNameCollisions(sourceTestRun, 3))
+		runs, err = store.GetTestRuns(Conditions{"name": "test run 1"}, "", 1)
+		suite.NoError(err)
+		suite.Equal(3, runs[0].NameCollisions)
+
+		suite.NoError(store.SaveRunLoad(sourceTestRun, `{"profile":{"name":"moderate"},"errors":1}`))
+		runs, err = store.GetTestRuns(Conditions{"name": "test run 1"}, "", 1)
+		suite.NoError(err)
+		suite.Equal(`{"profile":{"name":"moderate"},"errors":1}`, runs[0].Load)
+
+		suite.False(runs[0].Baseline)
+		suite.NoError(store.MarkBaselineRun(sourceTestRun))
+		runs, err = store.GetTestRuns(Conditions{"storage_class": sourceTestRun.StorageClass, "baseline": true}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(runs))
+		suite.Equal(sourceTestRun.ID, runs[0].ID)
+
 		sourceTestCase := &TestCase{
 			Name:           "test case",
 			Parameters:     "{size: 3GI}",
@@ -64,6 +129,16 @@ func (suite *StoreTestSuite) TestAllStores() {
 		err = store.SaveTestCase(sourceTestCase)
 		suite.NoError(err)
 
+		skippedTestCase := &TestCase{Name: "skipped test case", StartTimestamp: time.Now(), RunID: sourceTestRun.ID, Iteration: 12}
+		suite.NoError(store.SaveTestCase(skippedTestCase))
+		suite.NoError(store.SkippedTestCase(skippedTestCase, time.Now(), "skipped because VolumeIoSuite failed"))
+		skipped, err := store.GetTestCases(Conditions{"id": skippedTestCase.ID}, "", 1)
+		suite.NoError(err)
+		suite.True(skipped[0].Skipped)
+		suite.False(skipped[0].Success)
+		suite.Equal("skipped because VolumeIoSuite failed", skipped[0].ErrorMessage)
+		suite.Equal(12, skipped[0].Iteration)
+
 		sourceEntityPVC := &Entity{
 			Name:   "pvc1",
 			K8sUID: "b0dac67e-c9a2-11e9-ad06-00505691819d",
@@ -116,6 +191,20 @@ func (suite *StoreTestSuite) TestAllStores() {
 		})
 		suite.EqualError(err, "UNIQUE constraint failed: entities.k8s_uid")
 
+		// Retried save of the same events must not produce duplicates
+		retried := []*Event{
+			{
+				Name:      "test event 1 retry",
+				TcID:      sourceTestCase.ID,
+				EntityID:  sourceEntityPVC.ID,
+				Type:      PvcAdded,
+				Timestamp: sourceEvents[0].Timestamp,
+			},
+		}
+		err = store.SaveEvents(retried)
+		suite.NoError(err)
+		suite.Equal(sourceEvents[0].ID, retried[0].ID)
+
 		events, err := store.GetEvents(Conditions{"name": "test event 1"}, "", 0)
 		suite.Nil(err, "able to get event by name")
 		suite.Equal(len(events), 1, fmt.Sprintf("able to get event by name using %s store", key))
@@ -134,6 +223,29 @@ func (suite *StoreTestSuite) TestAllStores() {
 		suite.Nil(err, "able to get events by entity id")
 		suite.Equal(len(events), 1, fmt.Sprintf("able to get events by entity id using %s store", key))
 
+		sourceEntityNode := &Entity{Name: "worker-1", K8sUID: "node-worker-1", TcID: sourceTestCase.ID, Type: Node}
+		suite.NoError(store.SaveEntities([]*Entity{sourceEntityNode}))
+		warnedAt := time.Now()
+		warnings := []*Event{
+			{Name: "kernel warning 1", TcID: sourceTestCase.ID, EntityID: sourceEntityNode.ID, Type: NodeKernelIOError, Timestamp: warnedAt, Message: "blk_update_request: I/O error, dev sdb"},
+			{Name: "kernel warning 2", TcID: sourceTestCase.ID, EntityID: sourceEntityNode.ID, Type: NodeKernelIOError, Timestamp: warnedAt, Message: "device-mapper: multipath: Failing path 8:16."},
+		}
+		suite.NoError(store.SaveEvents(warnings))
+		// Warnings with different messages aren't duplicates even within the same second
+		suite.NotEqual(warnings[0].ID, warnings[1].ID)
+		nodeEvents, err := store.GetEvents(Conditions{"entity_id": sourceEntityNode.ID}, "id", 0)
+		suite.NoError(err)
+		suite.Len(nodeEvents, 2)
+		suite.Equal("device-mapper: multipath: Failing path 8:16.", nodeEvents[1].Message)
+
+		provisioned := &Event{Name: "component event", TcID: sourceTestCase.ID, EntityID: sourceEntityPod.ID, Type: ComponentEvent,
+			Timestamp: warnedAt, Message: "ProvisioningSucceeded", Source: "csi-vxflexos.dellemc.com_csi-provisioner"}
+		suite.NoError(store.SaveEvents([]*Event{provisioned}))
+		componentEvents, err := store.GetEvents(Conditions{"type": ComponentEvent}, "", 0)
+		suite.NoError(err)
+		suite.Len(componentEvents, 1)
+		suite.Equal("csi-vxflexos.dellemc.com_csi-provisioner", componentEvents[0].Source)
+
 		tcs, err := store.GetTestCases(Conditions{"name": "test case"}, "", 0)
 		suite.Nil(err, "able to get test case by uid")
 		suite.Equal(len(tcs), 1, fmt.Sprintf("able to get test case by uid using %s store", key))
@@ -190,6 +302,268 @@ func (suite *StoreTestSuite) TestAllStores() {
 		suite.Equal(len(events), 1)
 		suite.Equal(ne[0].PodsReady, 2)
 
+		err = store.SaveAssertionResults([]*AssertionResult{
+			{TcID: sourceTestCase.ID, Name: "MaxAvgBindTime", Expected: "<= 1s", Actual: "2s", Passed: false},
+			{TcID: sourceTestCase.ID, Name: "ZeroFailedAttaches", Expected: "0", Actual: "0", Passed: true},
+		})
+		suite.NoError(err)
+
+		assertionResults, err := store.GetAssertionResults(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(2, len(assertionResults))
+		suite.False(assertionResults[0].Passed)
+		suite.True(assertionResults[1].Passed)
+
+		err = store.SaveOrphans([]*Orphan{
+			{TcID: sourceTestCase.ID, Kind: "VolumeAttachment", Name: "csi-123", Details: "attached to node1", Timestamp: time.Now()},
+		})
+		suite.NoError(err)
+
+		orphans, err := store.GetOrphans(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(orphans))
+		suite.Equal("csi-123", orphans[0].Name)
+
+		err = store.SaveTeardownLatencies([]*TeardownLatency{
+			{TcID: sourceTestCase.ID, Kind: "PersistentVolumeClaim", Count: 2, Avg: 3 * time.Second, Max: 4 * time.Second},
+		})
+		suite.NoError(err)
+
+		latencies, err := store.GetTeardownLatencies(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(latencies))
+		suite.Equal(4*time.Second, latencies[0].Max)
+
+		err = store.SaveTeardownTiers([]*TeardownTier{
+			{TcID: sourceTestCase.ID, Tier: "pods", Position: 0, Duration: 2 * time.Second, Deleted: 3},
+			{TcID: sourceTestCase.ID, Tier: "pvcs", Position: 1, Duration: time.Minute, Deleted: 3, Remaining: 1, Message: "timed out"},
+		})
+		suite.NoError(err)
+
+		tiers, err := store.GetTeardownTiers(Conditions{"tc_id": sourceTestCase.ID}, "position", 0)
+		suite.NoError(err)
+		suite.Equal(2, len(tiers))
+		suite.Equal("pvcs", tiers[1].Tier)
+		suite.Equal(1, tiers[1].Remaining)
+		suite.Equal(time.Minute, tiers[1].Duration)
+
+		audit := []*AuditEntry{
+			{TcID: sourceTestCase.ID, EntityID: 1, AuditID: "a1", Timestamp: time.Now(), Verb: "delete", Resource: "persistentvolumes", Name: "pvc-1", User: "admin", Code: 200},
+			{TcID: sourceTestCase.ID, EntityID: 1, AuditID: "a2", Timestamp: time.Now(), Verb: "patch", Resource: "persistentvolumes", Name: "pvc-1", User: "csi-attacher", Code: 200},
+		}
+		added, err := store.SaveAuditEntries(audit)
+		suite.NoError(err)
+		suite.Equal(2, added)
+		// Ingesting the same log again adds nothing
+		added, err = store.SaveAuditEntries(audit[:1])
+		suite.NoError(err)
+		suite.Equal(0, added)
+
+		entries, err := store.GetAuditEntries(Conditions{"tc_id": sourceTestCase.ID}, "timestamp", 0)
+		suite.NoError(err)
+		suite.Equal(2, len(entries))
+		suite.Equal("admin", entries[0].User)
+		suite.Equal("csi-attacher", entries[1].User)
+
+		suite.NoError(store.SaveClockSkew(sourceTestCase, -1500*time.Millisecond))
+		testCases, err := store.GetTestCases(Conditions{"id": sourceTestCase.ID}, "", 1)
+		suite.NoError(err)
+		suite.Equal(-1500*time.Millisecond, testCases[0].ClockSkew)
+
+		suite.NoError(store.SaveTimeline(sourceTestCase.ID, []*TimelineEntry{
+			{Timestamp: time.Now(), Source: TimelineObserver, Object: "PVC/pvc-1", Message: "PVC_ADDED"},
+		}))
+		// Timeline merged again replaces the previous one
+		suite.NoError(store.SaveTimeline(sourceTestCase.ID, []*TimelineEntry{
+			{Timestamp: time.Now(), Source: TimelineObserver, Object: "PVC/pvc-1", Message: "PVC_ADDED"},
+			{Timestamp: time.Now(), Source: TimelineAudit, Object: "persistentvolumes/pvc-1", Message: "delete by admin", Corrected: true},
+		}))
+		timeline, err := store.GetTimelineEntries(Conditions{"tc_id": sourceTestCase.ID}, "timestamp", 0)
+		suite.NoError(err)
+		suite.Equal(2, len(timeline))
+		suite.True(timeline[1].Corrected)
+
+		err = store.SaveComparisons([]*Comparison{
+			{TcID: sourceTestCase.ID, Metric: "Avg pod ready", Baseline: "Immediate", BaselineValue: 2 * time.Second, Candidate: "WaitForFirstConsumer", CandidateValue: 3 * time.Second, Threshold: 20},
+		})
+		suite.NoError(err)
+
+		comparisons, err := store.GetComparisons(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(comparisons))
+		suite.Equal(time.Second, comparisons[0].Difference())
+		suite.Equal(50.0, comparisons[0].DifferencePercent())
+		suite.True(comparisons[0].Regressed())
+
+		start := time.Now()
+		err = store.SaveRampStages([]*RampStage{
+			{TcID: sourceTestCase.ID, Concurrency: 10, StartTimestamp: start, EndTimestamp: start.Add(5 * time.Second)},
+			{TcID: sourceTestCase.ID, Concurrency: 50, StartTimestamp: start.Add(10 * time.Second), EndTimestamp: start.Add(30 * time.Second)},
+		})
+		suite.NoError(err)
+
+		stages, err := store.GetRampStages(Conditions{"tc_id": sourceTestCase.ID}, "concurrency", 0)
+		suite.NoError(err)
+		suite.Equal(2, len(stages))
+		suite.Equal(50, stages[1].Concurrency)
+		suite.Equal(20*time.Second, stages[1].Duration())
+
+		err = store.SaveMountChecks([]*MountCheck{
+			{TcID: sourceTestCase.ID, Pod: "pod-1", PVC: "pvc-1", Node: "node-1", Options: "rw,noatime", Propagation: "shared", Valid: true},
+			{TcID: sourceTestCase.ID, Pod: "pod-1", PVC: "pvc-2", Node: "node-1", Options: "rw", Propagation: "private", Message: "missing mount options: noatime"},
+		})
+		suite.NoError(err)
+
+		checks, err := store.GetMountChecks(Conditions{"tc_id": sourceTestCase.ID, "valid": false}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(checks))
+		suite.Equal("pvc-2", checks[0].PVC)
+		suite.Equal("missing mount options: noatime", checks[0].Message)
+
+		err = store.SaveVolumeStats([]*VolumeStat{
+			{TcID: sourceTestCase.ID, PVC: "pvc-1", Node: "node-1", Capacity: 3 << 30, Used: 256 << 20, Available: 2 << 30, Written: 256 << 20, Valid: true},
+			{TcID: sourceTestCase.ID, PVC: "pvc-2", Node: "node-1", Written: 256 << 20, Message: "used is zero"},
+		})
+		suite.NoError(err)
+
+		stats, err := store.GetVolumeStats(Conditions{"tc_id": sourceTestCase.ID, "valid": false}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(stats))
+		suite.Equal("pvc-2", stats[0].PVC)
+		suite.Equal(int64(256<<20), stats[0].Written)
+
+		err = store.SaveMountOptionResults([]*MountOptionResult{
+			{TcID: sourceTestCase.ID, Set: "noatime", Options: "noatime", Outcome: MountOptionsApplied, Applied: "rw,noatime", Passed: true},
+			{TcID: sourceTestCase.ID, Set: "bogus", Options: "bogus-option", Outcome: MountOptionsStuck, ExpectFailure: true, Message: "pod wasn't deleted"},
+		})
+		suite.NoError(err)
+
+		optionResults, err := store.GetMountOptionResults(Conditions{"tc_id": sourceTestCase.ID, "passed": false}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(optionResults))
+		suite.Equal("bogus", optionResults[0].Set)
+		suite.Equal(MountOptionsStuck, optionResults[0].Outcome)
+		suite.True(optionResults[0].ExpectFailure)
+
+		err = store.SaveCapacityFillResults([]*CapacityFillResult{
+			{TcID: sourceTestCase.ID, VolumeSize: "500Gi", Bound: 4, Exhausted: true, ErrorCode: "ResourceExhausted", Message: "pool is full", Healthy: 4, Restored: true, Passed: true},
+		})
+		suite.NoError(err)
+
+		fillResults, err := store.GetCapacityFillResults(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(fillResults))
+		suite.Equal(4, fillResults[0].Bound)
+		suite.Equal("ResourceExhausted", fillResults[0].ErrorCode)
+		suite.True(fillResults[0].Restored)
+
+		err = store.SaveMountRecoveryResults([]*MountRecoveryResult{
+			{TcID: sourceTestCase.ID, Pod: "recovery-1", Node: "node-1", Retries: 3, FirstFailure: "MountVolume.MountDevice failed", Recovery: 20 * time.Second, Total: 80 * time.Second, Recovered: true},
+			{TcID: sourceTestCase.ID, Pod: "recovery-2", Node: "node-1"},
+		})
+		suite.NoError(err)
+
+		recoveryResults, err := store.GetMountRecoveryResults(Conditions{"tc_id": sourceTestCase.ID, "recovered": true}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(recoveryResults))
+		suite.Equal(3, recoveryResults[0].Retries)
+		suite.Equal(20*time.Second, recoveryResults[0].Recovery)
+		suite.Equal("MountVolume.MountDevice failed", recoveryResults[0].FirstFailure)
+
+		err = store.SaveConcurrentWriteResults([]*ConcurrentWriteResult{
+			{TcID: sourceTestCase.ID, Mode: ConcurrentWriteDistinct, File: "/data0/writer-0", Writers: "writer-0", Nodes: "node-1", Expected: 100, Intact: 100, Size: 6400, ExpectedSize: 6400, Passed: true},
+			{TcID: sourceTestCase.ID, Mode: ConcurrentWriteShared, File: "/data0/shared", Writers: "writer-0,writer-1", Nodes: "node-1,node-2", Expected: 200, Intact: 190, Lost: 10, Corrupted: 4, Size: 12544, ExpectedSize: 12800},
+		})
+		suite.NoError(err)
+
+		writeResults, err := store.GetConcurrentWriteResults(Conditions{"tc_id": sourceTestCase.ID, "mode": ConcurrentWriteShared}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(writeResults))
+		suite.Equal(10, writeResults[0].Lost)
+		suite.Equal(int64(12544), writeResults[0].Size)
+		suite.False(writeResults[0].Passed)
+
+		err = store.SaveEntityPlacements([]*EntityPlacement{
+			{TcID: sourceTestCase.ID, EntityID: 1, Node: "worker-1", Zone: "zone-a", Backend: "array-1"},
+			{TcID: sourceTestCase.ID, EntityID: 2, Node: "worker-2", Zone: "zone-b"},
+		})
+		suite.NoError(err)
+
+		placements, err := store.GetEntityPlacements(Conditions{"tc_id": sourceTestCase.ID, "node": "worker-1"}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(placements))
+		suite.Equal("zone-a", placements[0].Zone)
+		suite.Equal("array-1", placements[0].Backend)
+
+		err = store.SaveControllerFailoverResults([]*ControllerFailoverResult{
+			{TcID: sourceTestCase.ID, Deployment: "csi-controller", Disruption: "delete-leader", Replicas: 2, Leases: "csi-driver-com,external-attacher-leader-csi-driver-com", Lease: "csi-driver-com", OldLeader: "csi-controller-0", NewLeader: "csi-controller-1", Handover: 15 * time.Second, HandedOver: true, Stall: 20 * time.Second, Volumes: 10, Provisioned: 10, Passed: true},
+		})
+		suite.NoError(err)
+
+		failoverResults, err := store.GetControllerFailoverResults(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(failoverResults))
+		suite.Equal("csi-controller-1", failoverResults[0].NewLeader)
+		suite.Equal(20*time.Second, failoverResults[0].Stall)
+		suite.True(failoverResults[0].HandedOver)
+
+		err = store.SaveDetachProbeResults([]*DetachProbeResult{
+			{TcID: sourceTestCase.ID, PVC: "pvc-1", OldNode: "worker-1", NewNode: "worker-2", Device: "8:16", DeviceState: "gone", Detach: 12 * time.Second, Consistent: true, Passed: true},
+			{TcID: sourceTestCase.ID, PVC: "pvc-2", OldNode: "worker-1", NewNode: "worker-2", Device: "8:32", DeviceState: "readable", StaleMounts: "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-2/globalmount"},
+		})
+		suite.NoError(err)
+
+		detachProbes, err := store.GetDetachProbeResults(Conditions{"tc_id": sourceTestCase.ID, "passed": false}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(detachProbes))
+		suite.Equal("readable", detachProbes[0].DeviceState)
+		suite.Equal("8:32", detachProbes[0].Device)
+
+		faultStart := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+		err = store.SaveFaultWindows([]*FaultWindow{
+			{TcID: sourceTestCase.ID, Step: 1, Node: "worker-1", Start: faultStart, End: faultStart.Add(time.Minute), Ops: 40, Errors: 32, OpsAfter: 60, Recovery: 8 * time.Second, Recovered: true},
+		})
+		suite.NoError(err)
+
+		faultWindows, err := store.GetFaultWindows(Conditions{"tc_id": sourceTestCase.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(faultWindows))
+		suite.Equal(32, faultWindows[0].Errors)
+		suite.Equal(8*time.Second, faultWindows[0].Recovery)
+		suite.True(faultWindows[0].Start.Equal(faultStart))
+
+		err = store.SaveSeriesRuns([]*SeriesRun{{Series: "powerstore-2.x", RunID: sourceTestRun.ID, DriverVersion: "2.9.0", AddedTimestamp: time.Now()}})
+		suite.NoError(err)
+		// Linking run again replaces its driver version
+		err = store.SaveSeriesRuns([]*SeriesRun{{Series: "powerstore-2.x", RunID: sourceTestRun.ID, DriverVersion: "2.10.0", AddedTimestamp: time.Now()}})
+		suite.NoError(err)
+
+		seriesRuns, err := store.GetSeriesRuns(Conditions{"series": "powerstore-2.x"}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(seriesRuns))
+		suite.Equal("2.10.0", seriesRuns[0].DriverVersion)
+
+		err = store.SaveLatencySamples([]*LatencySample{
+			{TcID: sourceTestCase.ID, Metric: "Propagation", Source: "reader-0", Value: 150 * time.Millisecond, Timestamp: time.Now()},
+			{TcID: sourceTestCase.ID, Metric: "Propagation", Source: "reader-1", Value: 300 * time.Millisecond, Timestamp: time.Now()},
+		})
+		suite.NoError(err)
+
+		samples, err := store.GetLatencySamples(Conditions{"tc_id": sourceTestCase.ID, "source": "reader-1"}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(samples))
+		suite.Equal(300*time.Millisecond, samples[0].Value)
+
+		err = store.SaveBackendLeaks([]*BackendLeak{
+			{RunID: sourceTestRun.ID, Kind: "Volume", Name: "vol-123", Timestamp: time.Now()},
+		})
+		suite.NoError(err)
+
+		leaks, err := store.GetBackendLeaks(Conditions{"run_id": sourceTestRun.ID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(leaks))
+		suite.Equal("vol-123", leaks[0].Name)
+
 		podWithEvents, err := store.GetEntitiesWithEventsByTestCaseAndEntityType(&tc, Pod)
 		suite.NoError(err)
 		suite.Equal(len(podWithEvents), 1)
@@ -197,6 +571,347 @@ func (suite *StoreTestSuite) TestAllStores() {
 	}
 }
 
+func (suite *StoreTestSuite) TestRunLock() {
+	dsn := "file:lock.db?cache=shared&mode=memory"
+	first := NewSQLiteStore(dsn)
+	defer first.Close()
+	second := NewSQLiteStore(dsn)
+	defer second.Close()
+
+	suite.NoError(first.AcquireRunLock(false))
+	suite.NoError(first.AcquireRunLock(false), "lock is reentrant for its holder")
+
+	err := second.AcquireRunLock(false)
+	var lockedErr *RunLockedError
+	suite.ErrorAs(err, &lockedErr)
+	suite.Equal(first.lock.Owner, lockedErr.Lock.Owner)
+
+	suite.NoError(second.AcquireRunLock(true))
+
+	// Former holder must not release lock taken over by another store
+	suite.NoError(first.ReleaseRunLock())
+	suite.Error(first.AcquireRunLock(false))
+
+	suite.NoError(second.ReleaseRunLock())
+	suite.NoError(first.AcquireRunLock(false))
+}
+
+func (suite *StoreTestSuite) TestAcquireRunLocks() {
+	free := NewSQLiteStore("file:free.db?cache=shared&mode=memory")
+	defer free.Close()
+	locked := NewSQLiteStore("file:locked.db?cache=shared&mode=memory")
+	defer locked.Close()
+	holder := NewSQLiteStore("file:locked.db?cache=shared&mode=memory")
+	defer holder.Close()
+	suite.NoError(holder.AcquireRunLock(false))
+
+	scDBs := []*StorageClassDB{{StorageClass: "free", DB: free}, {StorageClass: "locked", DB: locked}}
+	suite.ErrorContains(AcquireRunLocks(scDBs, false), "storage class locked")
+	suite.Nil(free.lock, "lock taken before failure is released")
+
+	suite.NoError(holder.ReleaseRunLock())
+	suite.NoError(AcquireRunLocks(scDBs, false))
+	suite.NotNil(free.lock)
+	suite.NotNil(locked.lock)
+}
+
+func (suite *StoreTestSuite) TestPostgresDDL() {
+	events := postgresDDL(`CREATE TABLE events(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(30) NOT NULL,
+		tc_id INTEGER NOT NULL,
+		timestamp DATETIME NOT NULL, payload BLOB, type VARCHAR(50) NOT NULL DEFAULT 'UNKNOWN',
+		UNIQUE(tc_id, name),
+		FOREIGN KEY(tc_id) REFERENCES test_cases(id))`)
+	suite.Equal(`CREATE TABLE IF NOT EXISTS events(
+	"id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" VARCHAR NOT NULL,
+	"tc_id" BIGINT NOT NULL,
+	"timestamp" TIMESTAMPTZ NOT NULL,
+	"payload" BYTEA,
+	"type" VARCHAR NOT NULL DEFAULT 'UNKNOWN',
+	UNIQUE(tc_id, name))`, events.Create)
+	suite.Equal("id", events.Identity)
+	suite.Equal([]string{"ALTER TABLE events ADD FOREIGN KEY(tc_id) REFERENCES test_cases(id)"}, events.ForeignKeys)
+
+	index := postgresDDL("CREATE INDEX events_tc_id ON events(tc_id)")
+	suite.Equal("CREATE INDEX IF NOT EXISTS events_tc_id ON events(tc_id)", index.Create)
+	index = postgresDDL("CREATE UNIQUE INDEX IF NOT EXISTS events_dedup_key ON events(dedup_key)")
+	suite.Equal("CREATE UNIQUE INDEX IF NOT EXISTS events_dedup_key ON events(dedup_key)", index.Create)
+	suite.Empty(index.ForeignKeys)
+
+	// Every table of the store translates into definitions Postgres accepts
+	db := NewSQLiteStore("file:postgres_ddl.db?cache=shared&mode=memory")
+	defer db.Close()
+	tables, _, err := db.schema()
+	suite.NoError(err)
+	for _, table := range tables {
+		pgTable := postgresDDL(table[1])
+		suite.NotContains(pgTable.Create, "TIMESTAMPTZ TIMESTAMPTZ", table[0])
+		suite.NotContains(pgTable.Create, "FOREIGN KEY", table[0])
+		suite.NotContains(pgTable.Create, "DATETIME", table[0])
+		suite.NotContains(pgTable.Create, "AUTOINCREMENT", table[0])
+		suite.Equal("id", pgTable.Identity, table[0])
+	}
+}
+
+func (suite *StoreTestSuite) TestMigrate() {
+	source := NewSQLiteStore("file:migrate_source.db?cache=shared&mode=memory")
+	defer source.Close()
+
+	run := &TestRun{Name: "migrated run", StartTimestamp: time.Now(), StorageClass: "default", ClusterAddress: "localhost"}
+	suite.NoError(source.SaveTestRun(run))
+	// Gap in ids checks they are kept rather than reassigned
+	skipped := &TestCase{Name: "skipped case", StartTimestamp: time.Now(), RunID: run.ID}
+	suite.NoError(source.SaveTestCase(skipped))
+	tc := &TestCase{Name: "migrated case", StartTimestamp: time.Now(), RunID: run.ID}
+	suite.NoError(source.SaveTestCase(tc))
+	entity := &Entity{Name: "pvc1", K8sUID: "migrated-pvc1", TcID: tc.ID, Type: Pvc}
+	suite.NoError(source.SaveEntities([]*Entity{entity}))
+	suite.NoError(source.SaveEvents([]*Event{{Name: "added", TcID: tc.ID, EntityID: entity.ID, Type: PvcAdded, Timestamp: time.Now()}}))
+	_, err := source.db.Exec("DELETE FROM test_cases WHERE id = ?", skipped.ID)
+	suite.NoError(err)
+
+	targetDSN := "file:migrate_target.db?cache=shared&mode=memory"
+	target := NewSQLiteStore(targetDSN)
+	defer target.Close()
+
+	migrated, err := source.Migrate(targetDSN)
+	suite.NoError(err)
+	counts := make(map[string]int64)
+	for _, m := range migrated {
+		counts[m.Name] = m.Rows
+	}
+	suite.Equal(int64(1), counts["test_runs"])
+	suite.Equal(int64(1), counts["test_cases"])
+	suite.Equal(int64(1), counts["events"])
+	suite.NotContains(counts, "run_lock")
+
+	cases, err := target.GetTestCases(Conditions{"name": "migrated case"}, "", 0)
+	suite.NoError(err)
+	suite.Equal(1, len(cases))
+	suite.Equal(tc.ID, cases[0].ID)
+	entities, err := target.GetEntitiesWithEventsByTestCaseAndEntityType(&cases[0], Pvc)
+	suite.NoError(err)
+	suite.Equal(1, len(entities))
+
+	// Copying the same rows again must fail without partial writes
+	_, err = source.Migrate(targetDSN)
+	suite.Error(err)
+	runs, err := target.GetTestRuns(Conditions{}, "", 0)
+	suite.NoError(err)
+	suite.Equal(1, len(runs))
+}
+
+// migrationSource creates database with a run of a single event, ids of its rows are the same in every database
+func (suite *StoreTestSuite) migrationSource(name string) *SQLiteStore {
+	source := NewSQLiteStore(fmt.Sprintf("file:%s.db?cache=shared&mode=memory", name))
+	run := &TestRun{Name: name + " run", StartTimestamp: time.Now(), StorageClass: "default", ClusterAddress: "localhost"}
+	suite.NoError(source.SaveTestRun(run))
+	tc := &TestCase{Name: name + " case", StartTimestamp: time.Now(), RunID: run.ID}
+	suite.NoError(source.SaveTestCase(tc))
+	entity := &Entity{Name: "pvc1", K8sUID: name + "-pvc1", TcID: tc.ID, Type: Pvc}
+	suite.NoError(source.SaveEntities([]*Entity{entity}))
+	suite.NoError(source.SaveEvents([]*Event{{Name: "added", TcID: tc.ID, EntityID: entity.ID, Type: PvcAdded, Timestamp: time.Now(), SourceKey: entity.K8sUID}}))
+	return source
+}
+
+func (suite *StoreTestSuite) TestMigrateCollidingIDs() {
+	targetDSN := "file:migrate_merged.db?cache=shared&mode=memory"
+	target := NewSQLiteStore(targetDSN)
+	defer target.Close()
+
+	first := suite.migrationSource("migrate_team_a")
+	defer first.Close()
+	migrated, err := first.Migrate(targetDSN)
+	suite.NoError(err)
+	for _, m := range migrated {
+		suite.Zero(m.IDOffset, m.Name)
+	}
+
+	// History of another team has the same ids, they are moved past the ones in target with relations kept
+	second := suite.migrationSource("migrate_team_b")
+	defer second.Close()
+	migrated, err = second.Migrate(targetDSN)
+	suite.NoError(err)
+	offsets := make(map[string]int64)
+	for _, m := range migrated {
+		offsets[m.Name] = m.IDOffset
+	}
+	suite.Equal(int64(1), offsets["test_runs"])
+	suite.Equal(int64(1), offsets["events"])
+
+	for _, team := range []string{"migrate_team_a", "migrate_team_b"} {
+		cases, err := target.GetTestCases(Conditions{"name": team + " case"}, "", 0)
+		suite.NoError(err)
+		suite.Equal(1, len(cases))
+		runs, err := target.GetTestRuns(Conditions{"id": cases[0].RunID}, "", 0)
+		suite.NoError(err)
+		suite.Equal(team+" run", runs[0].Name)
+		entities, err := target.GetEntitiesWithEventsByTestCaseAndEntityType(&cases[0], Pvc)
+		suite.NoError(err)
+		suite.Equal(1, len(entities))
+		for entity, events := range entities {
+			suite.Equal(team+"-pvc1", entity.K8sUID)
+			suite.Equal(1, len(events))
+		}
+	}
+}
+
+// TestMigratePostgres migrates histories of two teams into Postgres at CERT_CSI_TEST_POSTGRES_DSN, each run gets
+// its own schema which is dropped afterwards
+func (suite *StoreTestSuite) TestMigratePostgres() {
+	dsn := os.Getenv("CERT_CSI_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		suite.T().Skip("CERT_CSI_TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	suite.Require().NoError(err)
+	defer db.Close()
+	schema := fmt.Sprintf("cert_csi_migrate_%d", time.Now().UnixNano())
+	_, err = db.Exec("CREATE SCHEMA " + schema)
+	suite.Require().NoError(err)
+	defer func() {
+		_, err := db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		suite.NoError(err)
+	}()
+	target := dsn + "?search_path=" + schema
+	if strings.Contains(dsn, "?") {
+		target = dsn + "&search_path=" + schema
+	}
+
+	first := suite.migrationSource("postgres_team_a")
+	defer first.Close()
+	_, err = first.Migrate(target)
+	suite.Require().NoError(err)
+	// Tables and indexes exist already
+	second := suite.migrationSource("postgres_team_b")
+	defer second.Close()
+	_, err = second.Migrate(target)
+	suite.Require().NoError(err)
+
+	for _, team := range []string{"postgres_team_a", "postgres_team_b"} {
+		var events int
+		suite.NoError(db.QueryRow(`SELECT COUNT(*) FROM `+schema+`.events e
+			JOIN `+schema+`.entities en ON e.entity_id = en.id
+			JOIN `+schema+`.test_cases tc ON e.tc_id = tc.id AND en.tc_id = tc.id
+			JOIN `+schema+`.test_runs r ON tc.run_id = r.id
+			WHERE r.name = $1 AND en.k8s_uid = $2`, team+" run", team+"-pvc1").Scan(&events))
+		suite.Equal(1, events, team)
+	}
+	var foreignKeys int
+	suite.NoError(db.QueryRow(`SELECT COUNT(*) FROM information_schema.table_constraints
+		WHERE table_schema = $1 AND table_name = 'events' AND constraint_type = 'FOREIGN KEY'`, schema).Scan(&foreignKeys))
+	suite.Equal(2, foreignKeys, "foreign keys aren't added again")
+}
+
+func (suite *StoreTestSuite) TestCompact() {
+	store := NewSQLiteStore("file:compact.db?cache=shared&mode=memory")
+	defer store.Close()
+
+	run := &TestRun{Name: "compacted run", StartTimestamp: time.Now(), StorageClass: "default", ClusterAddress: "localhost"}
+	suite.NoError(store.SaveTestRun(run))
+	tc := &TestCase{Name: "compacted case", StartTimestamp: time.Now(), RunID: run.ID}
+	suite.NoError(store.SaveTestCase(tc))
+	pvc := &Entity{Name: "pvc1", K8sUID: "compact-pvc1", TcID: tc.ID, Type: Pvc}
+	orphan := &Entity{Name: "pvc2", K8sUID: "compact-pvc2", TcID: tc.ID, Type: Pvc}
+	suite.NoError(store.SaveEntities([]*Entity{pvc, orphan}))
+
+	start := time.Now().Truncate(time.Second)
+	suite.NoError(store.SaveEvents([]*Event{
+		{Name: "added", TcID: tc.ID, EntityID: pvc.ID, Type: PvcAdded, Timestamp: start},
+		// Replayed after watch reconnect
+		{Name: "added", TcID: tc.ID, EntityID: pvc.ID, Type: PvcAdded, Timestamp: start.Add(3 * time.Second)},
+		{Name: "bound", TcID: tc.ID, EntityID: pvc.ID, Type: PvcBound, Timestamp: start.Add(3 * time.Second)},
+		{Name: "bound", TcID: tc.ID, EntityID: 9999, Type: PvcBound, Timestamp: start},
+	}))
+	// Legacy duplicate saved without key
+	_, err := store.db.Exec("UPDATE events SET dedup_key = NULL WHERE type = ?", PvcBound)
+	suite.NoError(err)
+	suite.NoError(store.SaveEvents([]*Event{{Name: "bound", TcID: tc.ID, EntityID: pvc.ID, Type: PvcBound, Timestamp: start.Add(3 * time.Second)}}))
+
+	res, err := store.Compact(5*time.Second, true)
+	suite.NoError(err)
+	suite.Equal(2, res.DuplicateEvents)
+	suite.Equal(int64(1), res.OrphanedEvents)
+	suite.Equal(int64(1), res.OrphanedEntities)
+	events, err := store.GetEvents(Conditions{"tc_id": tc.ID}, "", 0)
+	suite.NoError(err)
+	suite.Equal(5, len(events), "dry run changes nothing")
+
+	res, err = store.Compact(5*time.Second, false)
+	suite.NoError(err)
+	suite.Equal(2, res.DuplicateEvents)
+	events, err = store.GetEvents(Conditions{"tc_id": tc.ID}, "", 0)
+	suite.NoError(err)
+	suite.Equal(2, len(events))
+	entities, err := store.GetEntities(Conditions{"tc_id": tc.ID}, "", 0)
+	suite.NoError(err)
+	suite.Equal(1, len(entities))
+
+	var unkeyed int
+	suite.NoError(store.db.QueryRow("SELECT COUNT(*) FROM events WHERE dedup_key IS NULL").Scan(&unkeyed))
+	suite.Equal(0, unkeyed)
+
+	res, err = store.Compact(0, false)
+	suite.NoError(err)
+	suite.Equal(0, res.DuplicateEvents)
+	suite.Equal(int64(0), res.OrphanedEntities)
+}
+
+func (suite *StoreTestSuite) TestReplayedEvents() {
+	store := NewSQLiteStore("file:replayed.db?cache=shared&mode=memory")
+	defer store.Close()
+
+	run := &TestRun{Name: "replayed run", StartTimestamp: time.Now(), StorageClass: "default", ClusterAddress: "localhost"}
+	suite.NoError(store.SaveTestRun(run))
+	tc := &TestCase{Name: "replayed case", StartTimestamp: time.Now(), RunID: run.ID}
+	suite.NoError(store.SaveTestCase(tc))
+	pod := &Entity{Name: "pod1", K8sUID: "replayed-pod1", TcID: tc.ID, Type: Pod}
+	suite.NoError(store.SaveEntities([]*Entity{pod}))
+
+	start := time.Now()
+	suite.NoError(store.SaveEvents([]*Event{
+		{Name: "added", TcID: tc.ID, EntityID: pod.ID, Type: PodAdded, Timestamp: start, SourceKey: pod.K8sUID},
+		{Name: "ready", TcID: tc.ID, EntityID: pod.ID, Type: PodReady, Timestamp: start.Add(time.Second), SourceKey: pod.K8sUID},
+		// Distinct warnings within the same second are kept
+		{Name: "oom", TcID: tc.ID, EntityID: pod.ID, Type: PodOOMKilled, Timestamp: start},
+		{Name: "oom", TcID: tc.ID, EntityID: pod.ID, Type: PodOOMKilled, Timestamp: start.Add(time.Millisecond)},
+	}))
+
+	// Transitions replayed after watch reconnect, observed much later
+	replayed := []*Event{
+		{Name: "added", TcID: tc.ID, EntityID: pod.ID, Type: PodAdded, Timestamp: start.Add(time.Minute), SourceKey: pod.K8sUID},
+		{Name: "ready", TcID: tc.ID, EntityID: pod.ID, Type: PodReady, Timestamp: start.Add(time.Minute), SourceKey: pod.K8sUID},
+	}
+	suite.NoError(store.SaveEvents(replayed))
+
+	events, err := store.GetEvents(Conditions{"tc_id": tc.ID}, "", 0)
+	suite.NoError(err)
+	suite.Equal(4, len(events))
+	suite.Equal(events[0].ID, replayed[0].ID)
+	suite.Equal(events[1].ID, replayed[1].ID)
+	suite.True(events[1].Timestamp.Equal(start.Add(time.Second)), "first observed timestamp is kept")
+}
+
+func (suite *StoreTestSuite) TestRegisterEventType() {
+	info, ok := GetEventType(NodeKernelIOError)
+	suite.True(ok)
+	suite.Equal(WarningCategory, info.Category)
+
+	_, err := RegisterEventType("", InfoCategory, Pod)
+	suite.Error(err)
+	_, err = RegisterEventType("POD_TAGGED", "NOISE", Pod)
+	suite.Error(err)
+	_, err = RegisterEventType(string(PvcBound), WarningCategory, Pvc)
+	suite.Error(err)
+
+	tagged, err := RegisterEventType("POD_TAGGED", InfoCategory, Pod)
+	suite.NoError(err)
+	suite.Contains(GetEventTypes(InfoCategory), EventTypeInfo{tagged, InfoCategory, Pod})
+	suite.NotContains(GetEventTypes(LifecycleCategory), EventTypeInfo{tagged, InfoCategory, Pod})
+}
+
 func TestStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(StoreTestSuite))
 }
@@ -17,15 +17,14 @@
 package testcore
 
 import (
-	"crypto/rand"
-	"fmt"
-
+	"github.com/dell/cert-csi/pkg/k8sclient"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pvc"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/statefulset"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/volumegroupsnapshot"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Image represents an image configuration.
@@ -123,6 +122,36 @@ func IoWritePodConfig(pvcNames []string, podName string, containerImage string)
 	}
 }
 
+// FSGroupPodConfig config to use in fsgroup suite
+func FSGroupPodConfig(pvcNames []string, securityContext *v1.PodSecurityContext, containerImage string) *pod.Config {
+	return &pod.Config{
+		NamePrefix:      "fsgroup-test-",
+		PvcNames:        pvcNames,
+		VolumeName:      "vol",
+		MountPath:       "/data",
+		ContainerName:   "fsgroup",
+		ContainerImage:  containerImage,
+		Command:         []string{`/bin/bash`},
+		Args:            []string{"-c", " trap 'exit 0' SIGTERM;while true; do sleep 1; done"},
+		SecurityContext: securityContext,
+	}
+}
+
+// EvictionPodConfig config to use in disk pressure eviction suite
+func EvictionPodConfig(pvcNames []string, ephemeralStorageLimit string, containerImage string) *pod.Config {
+	return &pod.Config{
+		NamePrefix:     "eviction-test-",
+		PvcNames:       pvcNames,
+		VolumeName:     "vol",
+		MountPath:      "/data",
+		ContainerName:  "eviction",
+		ContainerImage: containerImage,
+		Command:        []string{`/bin/bash`},
+		Args:           []string{"-c", " trap 'exit 0' SIGTERM;while true; do sleep 1; done"},
+		Limits:         v1.ResourceList{v1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorageLimit)},
+	}
+}
+
 // BlockSnapPodConfig config to use in blocksnap suite
 func BlockSnapPodConfig(pvcNames []string, containerImage string) *pod.Config {
 	return &pod.Config{
@@ -241,8 +270,7 @@ func GetAccessMode(AccessMode string) []v1.PersistentVolumeAccessMode {
 func VolumeGroupSnapConfig(vgsName, driver, reclaimPolicy, snapClass, volumeLabel, namespace string) *volumegroupsnapshot.Config {
 	if vgsName == "" {
 		// we will generate random name
-		p, _ := rand.Prime(rand.Reader, 16)
-		vgsName = fmt.Sprintf("%s%d", "vgs-test-", p)
+		vgsName = k8sclient.GenerateName("vgs-test-")
 	}
 	return &volumegroupsnapshot.Config{
 		Name:          vgsName,
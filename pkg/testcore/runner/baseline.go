@@ -0,0 +1,109 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recordBaselineCase remembers test case of current iteration, so it can be compared with its baseline counterpart
+func (sr *SuiteRunner) recordBaselineCase(storageClass string, num int, testCase *store.TestCase) {
+	if sr.BaselineStorageClass == "" {
+		return
+	}
+	sr.Lock()
+	defer sr.Unlock()
+	if sr.baselineCases == nil {
+		sr.baselineCases = make(map[string]map[int]*store.TestCase)
+	}
+	if sr.baselineCases[storageClass] == nil {
+		sr.baselineCases[storageClass] = make(map[int]*store.TestCase)
+	}
+	sr.baselineCases[storageClass][num] = testCase
+}
+
+// compareWithBaseline saves stage latencies of baseline test cases as comparisons of test cases of the same suites
+// run with other storage classes in current iteration, and forgets test cases of the iteration
+func (sr *SuiteRunner) compareWithBaseline() {
+	sr.Lock()
+	cases := sr.baselineCases
+	sr.baselineCases = nil
+	sr.Unlock()
+
+	var baselineDB *store.StorageClassDB
+	for _, scDB := range sr.ScDBs {
+		if scDB.StorageClass == sr.BaselineStorageClass {
+			baselineDB = scDB
+		}
+	}
+	if baselineDB == nil {
+		return
+	}
+	baselineCollector := collector.NewMetricsCollector(baselineDB.DB)
+
+	for _, scDB := range sr.ScDBs {
+		if scDB == baselineDB {
+			continue
+		}
+		c := collector.NewMetricsCollector(scDB.DB)
+		var comparisons []*store.Comparison
+		for num, tc := range cases[scDB.StorageClass] {
+			baseline, ok := cases[baselineDB.StorageClass][num]
+			if !ok || !baseline.Success || !tc.Success || baseline.Name != tc.Name {
+				continue
+			}
+			comparisons = append(comparisons, baselineComparisons(
+				tc.ID, baselineDB.StorageClass, baselineCollector.CollectTestCase(baseline),
+				scDB.StorageClass, c.CollectTestCase(tc))...)
+		}
+		if len(comparisons) == 0 {
+			continue
+		}
+		if err := scDB.DB.SaveComparisons(comparisons); err != nil {
+			logrus.Errorf("Can't save comparisons with baseline; error=%v", err)
+		}
+	}
+}
+
+// baselineComparisons compares average latencies of stages both test cases went through
+func baselineComparisons(tcID int64, baselineSC string, baseline collector.TestCaseMetrics, candidateSC string, candidate collector.TestCaseMetrics) []*store.Comparison {
+	var comparisons []*store.Comparison
+	for stage, d := range candidate.StageMetrics {
+		b, ok := baseline.StageMetrics[stage]
+		if !ok {
+			continue
+		}
+		comparisons = append(comparisons, &store.Comparison{
+			TcID:           tcID,
+			Metric:         fmt.Sprintf("Avg %v", stage),
+			Baseline:       baselineSC + " (baseline)",
+			BaselineValue:  b.Avg,
+			Candidate:      candidateSC,
+			CandidateValue: d.Avg,
+		})
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].Metric < comparisons[j].Metric
+	})
+	return comparisons
+}
@@ -0,0 +1,126 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Budget limits what a run may use. Once any limit is exceeded run stops launching iterations, suites in progress
+// finish with their observations and run is marked truncated. Zero limits are disabled
+type Budget struct {
+	WallClock time.Duration
+	// Objects is number of objects run may create in the cluster, counted by k8sclient.Created. Objects of
+	// background load aren't counted
+	Objects int64
+	// DBSize is size in bytes database of any storage class may grow to
+	DBSize int64
+}
+
+// ParseBudget parses comma separated limit=value pairs, ex. wall-clock=6h,objects=5000,db-size=2Gi, nil if s is empty
+func ParseBudget(s string) (*Budget, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b := &Budget{}
+	for _, pair := range strings.Split(s, ",") {
+		limit, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("budget limit %q must be limit=value", pair)
+		}
+		switch limit {
+		case "wall-clock":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid wall-clock budget %q", value)
+			}
+			b.WallClock = d
+		case "objects":
+			q, err := resource.ParseQuantity(value)
+			if err != nil || q.Value() <= 0 {
+				return nil, fmt.Errorf("invalid objects budget %q", value)
+			}
+			b.Objects = q.Value()
+		case "db-size":
+			q, err := resource.ParseQuantity(value)
+			if err != nil || q.Value() <= 0 {
+				return nil, fmt.Errorf("invalid db-size budget %q", value)
+			}
+			b.DBSize = q.Value()
+		default:
+			return nil, fmt.Errorf("unknown budget limit %s, expected one of wall-clock, objects, db-size", limit)
+		}
+	}
+	return b, nil
+}
+
+// budgetExceeded returns the budget run exceeded, empty if it's within all limits or has no budget
+func (sr *SuiteRunner) budgetExceeded() string {
+	b := sr.Budget
+	if b == nil {
+		return ""
+	}
+	if b.WallClock > 0 {
+		if elapsed := time.Since(sr.progress.Status().Started); elapsed > b.WallClock {
+			return fmt.Sprintf("wall-clock budget of %s exceeded after %s", b.WallClock, elapsed.Round(time.Second))
+		}
+	}
+	if created := sr.createdObjects(); b.Objects > 0 && created > b.Objects {
+		return fmt.Sprintf("objects budget of %d exceeded with %d objects created", b.Objects, created)
+	}
+	if b.DBSize > 0 {
+		for _, scDB := range sr.ScDBs {
+			size, err := scDB.DB.Size()
+			if err != nil {
+				logrus.Errorf("Can't get size of database of %s; error=%v", scDB.StorageClass, err)
+				continue
+			}
+			if size > b.DBSize {
+				return fmt.Sprintf("db-size budget of %s exceeded by database of %s with %s", resource.NewQuantity(b.DBSize, resource.BinarySI),
+					scDB.StorageClass, resource.NewQuantity(size, resource.BinarySI))
+			}
+		}
+	}
+	return ""
+}
+
+// createdObjects returns number of objects run created, objects load generator created through the same config are
+// subtracted
+func (sr *SuiteRunner) createdObjects() int64 {
+	created := k8sclient.Created.Count()
+	if sr.loadGen != nil {
+		created -= sr.loadGen.Created()
+	}
+	return created
+}
+
+// truncate marks runs of all storage classes as truncated by exceeded budget
+func (sr *SuiteRunner) truncate(reason string) {
+	logrus.Warnf("Not launching further iterations: %s", color.YellowString(reason))
+	for _, scDB := range sr.ScDBs {
+		if err := scDB.DB.TruncatedTestRun(&scDB.TestRun, reason); err != nil {
+			logrus.Errorf("Can't mark run %s truncated; error=%v", scDB.TestRun.Name, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/load"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// apiServer accepts every create by echoing created object and answers everything else with not found
+func apiServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			var obj map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&obj)
+			if meta, ok := obj["metadata"].(map[string]interface{}); ok && meta["name"] == nil {
+				meta["name"] = fmt.Sprintf("%sx", meta["generateName"])
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(obj)
+		case http.MethodDelete:
+			_ = json.NewEncoder(w).Encode(metav1.Status{Status: metav1.StatusSuccess})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound})
+		}
+	}))
+}
+
+func TestObjectsBudgetWithBackgroundLoad(t *testing.T) {
+	server := apiServer()
+	defer server.Close()
+	k8sclient.Created = k8sclient.NewCreateCounter()
+	defer func() { k8sclient.Created = nil }()
+
+	config := &rest.Config{Host: server.URL}
+	config.Wrap(k8sclient.Created.Wrap)
+	clientSet, err := kubernetes.NewForConfig(config)
+	assert.NoError(t, err)
+	sr := &SuiteRunner{
+		Runner:         &Runner{Config: config, KubeClient: &k8sclient.KubeClient{ClientSet: clientSet, Config: config}},
+		Budget:         &Budget{Objects: 3},
+		BackgroundLoad: &load.Profile{Name: "test", ConfigMapsPerSecond: 200},
+	}
+	sr.startBackgroundLoad()
+	defer sr.finishBackgroundLoad()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Greater(t, k8sclient.Created.Count(), sr.Budget.Objects, "load objects went through counted config")
+	assert.Empty(t, sr.budgetExceeded())
+
+	for i := 0; i < 4; i++ {
+		_, err := clientSet.CoreV1().ConfigMaps("suite").Create(context.Background(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("suite-%d", i)},
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	assert.Contains(t, sr.budgetExceeded(), "objects budget of 3 exceeded")
+}
@@ -0,0 +1,147 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CanaryAlertEnv is environment variable alert hook gets alert message in
+const CanaryAlertEnv = "CERT_CSI_CANARY_ALERT"
+
+// Canary configures always-on mode, where tiny suite runs every interval and appends test cases to rolling run
+type Canary struct {
+	Interval time.Duration
+	// Baseline is number of latest successful test cases latency of canary is compared with
+	Baseline int
+	// Factor is how many times canary latency has to exceed baseline to alert
+	Factor float64
+	// AlertHook is run on every alert, alerts are only logged if empty
+	AlertHook string
+}
+
+// canaryStages are stages canary latency is compared with baseline in
+var canaryStages = []interface{}{collector.PVCBind, collector.PodCreation}
+
+// CanaryRunName returns name of rolling run canary of storage class appends to
+func CanaryRunName(storageClass string) string {
+	return "canary-" + storageClass
+}
+
+// resumeCanaryRun makes test run continue rolling run of its storage class, returns false if database has none yet
+func resumeCanaryRun(scDB *store.StorageClassDB) bool {
+	name := CanaryRunName(scDB.StorageClass)
+	scDB.TestRun.Name = name
+	runs, err := scDB.DB.GetTestRuns(store.Conditions{}, "", 0)
+	if err != nil {
+		logrus.Errorf("Can't get test runs; error=%v", err)
+		return false
+	}
+	// storage class names come from user, so runs are matched here instead of in query
+	for _, run := range runs {
+		if run.Name == name {
+			scDB.TestRun = run
+			return true
+		}
+	}
+	return false
+}
+
+// checkCanary alerts if canary failed or its latency exceeded baseline of previous canaries
+func (sr *SuiteRunner) checkCanary(ctx context.Context, db store.Store, testCase *store.TestCase, succeeded bool) {
+	log := utils.GetLoggerFromContext(ctx)
+	if !succeeded {
+		sr.canaryAlert(log, fmt.Sprintf("canary %s of run %d failed", testCase.Name, testCase.RunID))
+		return
+	}
+
+	previous, err := db.GetTestCases(store.Conditions{"run_id": testCase.RunID}, "id DESC", 0)
+	if err != nil {
+		log.Errorf("Can't get previous canaries; error=%v", err)
+		return
+	}
+	mc := collector.NewMetricsCollector(db)
+	baselines := make(map[interface{}][]time.Duration)
+	n := 0
+	for i := range previous {
+		if n == sr.Canary.Baseline {
+			break
+		}
+		if previous[i].ID == testCase.ID || !previous[i].Success {
+			continue
+		}
+		metrics := mc.CollectTestCase(&previous[i])
+		for _, stage := range canaryStages {
+			if d, ok := metrics.StageMetrics[stage]; ok {
+				baselines[stage] = append(baselines[stage], d.Avg)
+			}
+		}
+		n++
+	}
+
+	current := mc.CollectTestCase(testCase)
+	for _, stage := range canaryStages {
+		d, ok := current.StageMetrics[stage]
+		if !ok || len(baselines[stage]) == 0 {
+			continue
+		}
+		var sum time.Duration
+		for _, b := range baselines[stage] {
+			sum += b
+		}
+		baseline := sum / time.Duration(len(baselines[stage]))
+		if baseline > 0 && float64(d.Avg) > sr.Canary.Factor*float64(baseline) {
+			sr.canaryAlert(log, fmt.Sprintf("canary %s took %s, %.1fx baseline of %s", stage, d.Avg, float64(d.Avg)/float64(baseline), baseline))
+		} else {
+			log.Infof("Canary %s took %s, baseline is %s", stage, d.Avg, baseline)
+		}
+	}
+}
+
+// canaryAlert logs alert and passes it to alert hook
+func (sr *SuiteRunner) canaryAlert(log *logrus.Entry, alert string) {
+	log.Warnf("ALERT: %s", alert)
+	if sr.Canary.AlertHook == "" {
+		return
+	}
+	cmdPath, err := filepath.Abs(sr.Canary.AlertHook)
+	if err != nil {
+		log.Errorf("Can't run alert hook; error=%v", err)
+		return
+	}
+	var cmd *exec.Cmd
+	if filepath.Ext(cmdPath) == ".sh" {
+		cmd = exec.Command("bash", cmdPath) // #nosec
+	} else {
+		cmd = exec.Command(cmdPath) // #nosec
+	}
+	cmd.Env = append(os.Environ(), CanaryAlertEnv+"="+alert)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.Errorf("Alert hook failed; error=%v, output=%s", err, out)
+	}
+}
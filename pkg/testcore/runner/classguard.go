@@ -0,0 +1,229 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ClassGuardMode defines what happens if storage or snapshot class used by run changes while it's in progress
+type ClassGuardMode string
+
+const (
+	// ClassGuardOff doesn't watch classes
+	ClassGuardOff ClassGuardMode = "off"
+	// ClassGuardWarn records the change and lets run continue
+	ClassGuardWarn ClassGuardMode = "warn"
+	// ClassGuardFail records the change and aborts run
+	ClassGuardFail ClassGuardMode = "fail"
+)
+
+// ClassGuardInterval is how often classes are compared with their specs taken when run started
+var ClassGuardInterval = 30 * time.Second
+
+// ParseClassGuardMode parses class guard mode, empty mode is warn
+func ParseClassGuardMode(mode string) (ClassGuardMode, error) {
+	switch m := ClassGuardMode(mode); m {
+	case "":
+		return ClassGuardWarn, nil
+	case ClassGuardOff, ClassGuardWarn, ClassGuardFail:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown class guard mode %q, should be one of off, warn, fail", mode)
+}
+
+// guardedClass is a storage or snapshot class with its spec taken when run started
+type guardedClass struct {
+	kind string
+	name string
+	spec k8sclient.ClassSpec
+	// storageClasses are storage classes whose runs use the class
+	storageClasses []string
+}
+
+func (gc *guardedClass) key() string {
+	return gc.kind + "/" + gc.name
+}
+
+// classGuard watches classes used by run and records changes made to them into runs using them
+type classGuard struct {
+	sr      *SuiteRunner
+	mode    ClassGuardMode
+	classes []*guardedClass
+	drift   map[string][]string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// newClassGuard takes specs of storage classes of the run and snapshot classes used by its suites
+func newClassGuard(ctx context.Context, sr *SuiteRunner, mode ClassGuardMode, ss map[string][]suites.Interface) *classGuard {
+	g := &classGuard{sr: sr, mode: mode, drift: make(map[string][]string)}
+	snapClasses := make(map[string]*guardedClass)
+	for _, scDB := range sr.ScDBs {
+		spec, err := sr.KubeClient.StorageClassSpec(ctx, scDB.StorageClass)
+		if err != nil {
+			logrus.Errorf("Can't get spec of storage class %s, it won't be guarded; error=%v", scDB.StorageClass, err)
+		} else {
+			g.classes = append(g.classes, &guardedClass{kind: "StorageClass", name: scDB.StorageClass, spec: spec, storageClasses: []string{scDB.StorageClass}})
+		}
+
+		for _, s := range ss[scDB.StorageClass] {
+			user, ok := s.(suites.SnapshotClassUser)
+			if !ok || user.GetSnapClass() == "" {
+				continue
+			}
+			if gc, ok := snapClasses[user.GetSnapClass()]; ok {
+				if !containsString(gc.storageClasses, scDB.StorageClass) {
+					gc.storageClasses = append(gc.storageClasses, scDB.StorageClass)
+				}
+				continue
+			}
+			spec, err := sr.KubeClient.SnapshotClassSpec(ctx, user.GetSnapClass())
+			if err != nil {
+				logrus.Errorf("Can't get spec of snapshot class %s, it won't be guarded; error=%v", user.GetSnapClass(), err)
+				continue
+			}
+			gc := &guardedClass{kind: "VolumeSnapshotClass", name: user.GetSnapClass(), spec: spec, storageClasses: []string{scDB.StorageClass}}
+			snapClasses[gc.name] = gc
+			g.classes = append(g.classes, gc)
+		}
+	}
+	return g
+}
+
+// specsJSON returns specs of classes used by run of storage class, keyed by kind/name
+func (g *classGuard) specsJSON(storageClass string) string {
+	specs := make(map[string]k8sclient.ClassSpec)
+	for _, gc := range g.classes {
+		if containsString(gc.storageClasses, storageClass) {
+			specs[gc.key()] = gc.spec
+		}
+	}
+	if len(specs) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(specs)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// check compares classes with their last known specs, changes are saved into runs using the classes,
+// run is aborted on change in fail mode if abort is true
+func (g *classGuard) check(ctx context.Context, abort bool) {
+	var changed []string
+	for _, gc := range g.classes {
+		var spec k8sclient.ClassSpec
+		var err error
+		if gc.kind == "StorageClass" {
+			spec, err = g.sr.KubeClient.StorageClassSpec(ctx, gc.name)
+		} else {
+			spec, err = g.sr.KubeClient.SnapshotClassSpec(ctx, gc.name)
+		}
+		var diff []string
+		switch {
+		case apierrs.IsNotFound(err):
+			diff = []string{"deleted"}
+			spec = k8sclient.ClassSpec{}
+		case err != nil:
+			logrus.Debugf("Can't check %s; error=%v", gc.key(), err)
+			continue
+		default:
+			diff = gc.spec.Diff(spec)
+		}
+		if len(diff) == 0 {
+			continue
+		}
+		// Later changes are compared with the latest spec, so every change is recorded once
+		gc.spec = spec
+		change := fmt.Sprintf("%s: %s", gc.key(), strings.Join(diff, ", "))
+		logrus.Warnf("%s changed while run is in progress: %s", color.YellowString(gc.key()), strings.Join(diff, ", "))
+		changed = append(changed, gc.key())
+		for _, sc := range gc.storageClasses {
+			g.drift[sc] = append(g.drift[sc], change)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	for _, scDB := range g.sr.ScDBs {
+		drift, ok := g.drift[scDB.StorageClass]
+		if !ok {
+			continue
+		}
+		if err := scDB.DB.SaveClassDrift(&scDB.TestRun, strings.Join(drift, "; ")); err != nil {
+			logrus.Errorf("Can't save class drift of run %s; error=%v", scDB.TestRun.Name, err)
+		}
+	}
+	if abort && g.mode == ClassGuardFail {
+		sort.Strings(changed)
+		if err := g.sr.Abort("", fmt.Sprintf("%s changed mid-run", strings.Join(changed, ", "))); err != nil {
+			logrus.Errorf("Can't abort run; error=%v", err)
+		}
+	}
+}
+
+// start checks classes every ClassGuardInterval until guard is stopped
+func (g *classGuard) start() {
+	g.stop = make(chan struct{})
+	g.done = make(chan struct{})
+	go func() {
+		defer close(g.done)
+		ticker := time.NewTicker(ClassGuardInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-g.stop:
+				return
+			case <-ticker.C:
+				g.check(context.Background(), true)
+			}
+		}
+	}()
+}
+
+// finish stops periodic checks and checks classes the last time, so changes made at the end of run are recorded too,
+// run which has already finished isn't aborted
+func (g *classGuard) finish() {
+	if g.stop != nil {
+		close(g.stop)
+		<-g.done
+	}
+	g.check(context.Background(), false)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/reporter"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+
+	"github.com/fatih/color"
+)
+
+// captureState saves objects of failed test case and their events into its report folder before namespace
+// is cleaned up, so post-mortem doesn't depend on cluster still being in that state
+func (sr *SuiteRunner) captureState(ctx context.Context, db *store.SQLiteStore, testCase *store.TestCase, namespace string) {
+	log := utils.GetLoggerFromContext(ctx)
+	runName := ""
+	for _, scDB := range sr.ScDBs {
+		if scDB.DB == db {
+			runName = scDB.TestRun.Name
+		}
+	}
+	dir, err := reporter.ClusterStateDir(runName, testCase)
+	if err != nil {
+		log.Warnf("Can't capture cluster state of failed %s; error=%v", testCase.Name, err)
+		return
+	}
+	// Snapshots aren't captured without dynamic client, but everything else still is
+	dynamicClient, err := sr.KubeClient.CreateDynamicClient()
+	if err != nil {
+		log.Warnf("Can't create client of volume snapshots; error=%v", err)
+	}
+	files, err := k8sclient.CaptureState(ctx, sr.KubeClient.ClientSet, dynamicClient, namespace, dir)
+	if err != nil {
+		log.Warnf("Cluster state of failed %s captured partially; error=%v", testCase.Name, err)
+	}
+	if len(files) != 0 {
+		log.Infof("Captured state of %d objects of failed %s to %s", len(files), testCase.Name, color.CyanString(dir))
+	}
+}
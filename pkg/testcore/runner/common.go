@@ -17,6 +17,7 @@
 package runner
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -52,10 +53,19 @@ type Runner struct {
 }
 
 func getSuiteRunner(configPath, driverNs, observerType string, timeout int, noCleanup, noCleanupOnFail bool, noreport bool) *Runner {
+	r, err := newRunner(configPath, driverNs, observerType, timeout, noCleanup, noCleanupOnFail, noreport)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return r
+}
+
+// newRunner loads kubernetes config and creates client of the runner
+func newRunner(configPath, driverNs, observerType string, timeout int, noCleanup, noCleanupOnFail bool, noreport bool) (*Runner, error) {
 	t := strings.ToUpper(observerType)
 	correctType := (t == string(observer.EVENT)) || (t == string(observer.LIST))
 	if !correctType {
-		log.Fatal("Incorrect observer type")
+		return nil, fmt.Errorf("incorrect observer type %s", observerType)
 	}
 
 	obsType := observer.Type(t)
@@ -64,13 +74,13 @@ func getSuiteRunner(configPath, driverNs, observerType string, timeout int, noCl
 	// Loading config
 	config, err := k8sclient.GetConfig(configPath)
 	if err != nil {
-		log.Error(err)
+		return nil, err
 	}
 
 	// Connecting to host and creating new Kubernetes Client
 	kubeClient, kubeErr := k8sclient.NewKubeClient(config, timeout)
 	if kubeErr != nil {
-		log.Errorf("Couldn't create new kubernetes client. Error = %v", kubeErr)
+		return nil, fmt.Errorf("couldn't create new kubernetes client: %w", kubeErr)
 	}
 
 	return &Runner{
@@ -82,12 +92,12 @@ func getSuiteRunner(configPath, driverNs, observerType string, timeout int, noCl
 		ObserverType:    obsType,
 		noCleaning:      noCleanup,
 		noreport:        noreport,
-	}
+	}, nil
 }
 
 func generateTestRunDetails(scDB *store.StorageClassDB, _ *k8sclient.KubeClient, host string) {
 	scDB.TestRun = store.TestRun{
-		Name:           "test-run-" + k8sclient.RandomSuffix(),
+		Name:           "test-run-" + k8sclient.UniqueSuffix(),
 		StartTimestamp: time.Now(),
 		StorageClass:   scDB.StorageClass,
 		ClusterAddress: host,
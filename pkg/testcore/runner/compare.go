@@ -0,0 +1,94 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+)
+
+// CommonSuites keeps suites planned with the same parameters for every storage class, so storage classes of
+// different drivers are compared on equal terms. Suites are ordered as planned for the first storage class in order,
+// names of dropped suites are returned
+func CommonSuites(order []string, planned map[string][]suites.Interface) (map[string][]suites.Interface, []string) {
+	key := func(s suites.Interface) string {
+		return s.GetName() + " " + s.Parameters()
+	}
+	counts := make(map[string]int)
+	for _, sc := range order {
+		seen := make(map[string]bool)
+		for _, s := range planned[sc] {
+			if !seen[key(s)] {
+				seen[key(s)] = true
+				counts[key(s)]++
+			}
+		}
+	}
+
+	common := make(map[string][]suites.Interface)
+	var dropped []string
+	for _, sc := range order {
+		bySuite := make(map[string]suites.Interface)
+		for _, s := range planned[sc] {
+			if counts[key(s)] == len(order) {
+				bySuite[key(s)] = s
+			} else {
+				dropped = append(dropped, s.GetName()+" of "+sc)
+			}
+		}
+		for _, s := range planned[order[0]] {
+			if shared, ok := bySuite[key(s)]; ok {
+				common[sc] = append(common[sc], shared)
+				delete(bySuite, key(s))
+			}
+		}
+	}
+	return common, dropped
+}
+
+// runSideBySide runs suites position by position, suite of every storage class at the same position runs at once,
+// so all of them share cluster conditions, next position starts once all of them finished
+func (sr *SuiteRunner) runSideBySide(iterCtx context.Context, planned map[string][]suites.Interface, c chan os.Signal) {
+	positions := 0
+	for _, scDB := range sr.ScDBs {
+		positions = max(positions, len(planned[scDB.StorageClass]))
+	}
+	for i := 0; i < positions && !sr.IsStopped(); i++ {
+		if first := planned[sr.ScDBs[0].StorageClass]; i < len(first) {
+			logrus.Infof("Running %s with every storage class side by side", color.CyanString(first[i].GetName()))
+		}
+		var wg sync.WaitGroup
+		for _, scDB := range sr.ScDBs {
+			if i >= len(planned[scDB.StorageClass]) {
+				continue
+			}
+			suite := planned[scDB.StorageClass][i]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ExecuteSuite(iterCtx, i, planned, suite, sr, scDB, c)
+			}()
+		}
+		wg.Wait()
+	}
+}
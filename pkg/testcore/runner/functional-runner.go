@@ -17,7 +17,6 @@
 package runner
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
@@ -29,6 +28,7 @@ import (
 	"github.com/dell/cert-csi/pkg/observer"
 	"github.com/dell/cert-csi/pkg/store"
 	"github.com/dell/cert-csi/pkg/testcore/suites"
+	"github.com/dell/cert-csi/pkg/utils"
 
 	"github.com/fatih/color"
 	log "github.com/sirupsen/logrus"
@@ -160,13 +160,7 @@ func runFunctionalSuite(suite suites.Interface, sr *FunctionalSuiteRunner, testC
 			return
 		}
 		log.Infof("Received termination signal, exiting asap")
-		fmt.Printf("Do you want to cleanup namespace? (Y/n)\n")
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("-> ")
-		char, _, err := reader.ReadRune()
-		if err != nil {
-			log.Error(err)
-		}
+		char := utils.Prompt("Do you want to cleanup namespace? (Y/n)", 'y')
 
 		if char == 'n' || char == 'N' {
 			sr.NoCleaning()
@@ -0,0 +1,67 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dell/cert-csi/pkg/load"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+)
+
+// startBackgroundLoad starts load generator of the profile, run has no generator if it has no background load or
+// it can't be started
+func (sr *SuiteRunner) startBackgroundLoad() {
+	if sr.BackgroundLoad == nil {
+		return
+	}
+	gen := load.NewGenerator(sr.KubeClient, *sr.BackgroundLoad)
+	if err := gen.Start(context.Background()); err != nil {
+		logrus.Errorf("Running without background load; error=%v", err)
+		return
+	}
+	logrus.Infof("Generating %s background load: %.1f configmaps/s, %.1f pods/s, up to %d pods",
+		color.CyanString(gen.Profile.Name), gen.Profile.ConfigMapsPerSecond, gen.Profile.PodsPerSecond, gen.Profile.MaxPods)
+	sr.loadGen = gen
+}
+
+// loadJSON returns JSON of background load report, empty if there is no background load
+func loadJSON(report load.Report) string {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// finishBackgroundLoad stops load generator and records load it generated in every run
+func (sr *SuiteRunner) finishBackgroundLoad() {
+	if sr.loadGen == nil {
+		return
+	}
+	report := sr.loadGen.Stop()
+	sr.loadGen = nil
+	logrus.Infof("Background load %s", report)
+	for _, scDB := range sr.ScDBs {
+		if err := scDB.DB.SaveRunLoad(&scDB.TestRun, loadJSON(report)); err != nil {
+			logrus.Errorf("Can't save background load of run %s; error=%v", scDB.TestRun.Name, err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// OrphanKindPV is kind of PersistentVolume left after teardown
+	OrphanKindPV = "PersistentVolume"
+	// OrphanKindVA is kind of VolumeAttachment left after teardown
+	OrphanKindVA = "VolumeAttachment"
+	// OrphanKindBackendVolume is kind of backend volume left after teardown, found by backend verifier
+	OrphanKindBackendVolume = "BackendVolume"
+)
+
+var (
+	// OrphanGracePeriod is how long driver is given to clean up volumes after namespace deletion
+	OrphanGracePeriod = 2 * time.Minute
+	// OrphanPoll is the poll interval of orphan detection
+	OrphanPoll = 5 * time.Second
+)
+
+// claimedVolumes returns names of PVs bound to claims of namespace, which are expected to be deleted with it,
+// mapped to their CSI volume handles
+func (sr *SuiteRunner) claimedVolumes(ctx context.Context, namespace string) (map[string]string, error) {
+	pvList, err := sr.KubeClient.ClientSet.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	volumes := make(map[string]string)
+	for _, pv := range pvList.Items {
+		if pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != namespace {
+			continue
+		}
+		// Retained volumes outlive their claims by design
+		if pv.Spec.PersistentVolumeReclaimPolicy == v1.PersistentVolumeReclaimRetain {
+			continue
+		}
+		volumes[pv.Name] = ""
+		if pv.Spec.CSI != nil {
+			volumes[pv.Name] = pv.Spec.CSI.VolumeHandle
+		}
+	}
+	return volumes, nil
+}
+
+// findOrphans returns PVs and VolumeAttachments of volumes, that are still present after grace period, and volumes
+// still present on backend if backend verifier is configured
+func (sr *SuiteRunner) findOrphans(ctx context.Context, testCase *store.TestCase, volumes map[string]string) ([]*store.Orphan, error) {
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+
+	var orphans []*store.Orphan
+	pollErr := wait.PollUntilContextTimeout(ctx, OrphanPoll, OrphanGracePeriod, true, func(context.Context) (bool, error) {
+		orphans = nil
+
+		pvList, err := sr.KubeClient.ClientSet.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, pv := range pvList.Items {
+			if _, ok := volumes[pv.Name]; !ok {
+				continue
+			}
+			// Claim reference of released volume may have been cleared
+			details := fmt.Sprintf("phase %s, no claim", pv.Status.Phase)
+			if pv.Spec.ClaimRef != nil {
+				details = fmt.Sprintf("phase %s, claim %s/%s", pv.Status.Phase, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+			}
+			orphans = append(orphans, &store.Orphan{
+				TcID:      testCase.ID,
+				Kind:      OrphanKindPV,
+				Name:      pv.Name,
+				Details:   details,
+				Timestamp: time.Now(),
+			})
+		}
+
+		vaList, err := sr.KubeClient.ClientSet.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, va := range vaList.Items {
+			if va.Spec.Source.PersistentVolumeName == nil {
+				continue
+			}
+			if _, ok := volumes[*va.Spec.Source.PersistentVolumeName]; !ok {
+				continue
+			}
+			orphans = append(orphans, &store.Orphan{
+				TcID: testCase.ID,
+				Kind: OrphanKindVA,
+				Name: va.Name,
+				Details: fmt.Sprintf("volume %s on node %s, attached %t", *va.Spec.Source.PersistentVolumeName,
+					va.Spec.NodeName, va.Status.Attached),
+				Timestamp: time.Now(),
+			})
+		}
+
+		backendOrphans, err := sr.backendOrphans(ctx, testCase, volumes)
+		if err != nil {
+			// Orphans found in cluster are still reported if backend can't be listed
+			utils.GetLoggerFromContext(ctx).Warnf("Can't list backend inventory; error=%v", err)
+		}
+		orphans = append(orphans, backendOrphans...)
+
+		return len(orphans) == 0, nil
+	})
+	if pollErr != nil && !wait.Interrupted(pollErr) {
+		return nil, pollErr
+	}
+	return orphans, nil
+}
+
+// backendOrphans returns volumes still present on backend, they are matched by CSI volume handle or PV name
+func (sr *SuiteRunner) backendOrphans(ctx context.Context, testCase *store.TestCase, volumes map[string]string) ([]*store.Orphan, error) {
+	if sr.Backend == nil {
+		return nil, nil
+	}
+	inventory, err := sr.Backend.Inventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(inventory.Volumes))
+	for _, name := range inventory.Volumes {
+		existing[name] = true
+	}
+
+	var orphans []*store.Orphan
+	for pv, handle := range volumes {
+		name := pv
+		if handle != "" && existing[handle] {
+			name = handle
+		} else if !existing[pv] {
+			continue
+		}
+		orphans = append(orphans, &store.Orphan{
+			TcID:      testCase.ID,
+			Kind:      OrphanKindBackendVolume,
+			Name:      name,
+			Details:   fmt.Sprintf("volume of %s still exists on backend %s", pv, sr.Backend.Name()),
+			Timestamp: time.Now(),
+		})
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Name < orphans[j].Name
+	})
+	return orphans, nil
+}
+
+// checkOrphans records resources of namespace volumes that weren't cleaned up, returns error if any were found
+func (sr *SuiteRunner) checkOrphans(ctx context.Context, db store.Store, testCase *store.TestCase, volumes map[string]string) error {
+	log := utils.GetLoggerFromContext(ctx)
+
+	orphans, err := sr.findOrphans(ctx, testCase, volumes)
+	if err != nil {
+		log.Warnf("Can't check for orphaned resources; error=%v", err)
+		return nil
+	}
+	if len(orphans) == 0 {
+		return nil
+	}
+
+	for _, o := range orphans {
+		log.Errorf("Orphaned %s %s: %s", o.Kind, o.Name, o.Details)
+	}
+	if err := db.SaveOrphans(orphans); err != nil {
+		log.Errorf("Can't save orphans; error=%v", err)
+	}
+	return fmt.Errorf("found %d orphaned resources after teardown", len(orphans))
+}
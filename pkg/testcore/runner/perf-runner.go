@@ -17,27 +17,37 @@
 package runner
 
 import (
-	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/dell/cert-csi/pkg/backend"
+	"github.com/dell/cert-csi/pkg/collector"
 	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/load"
 	"github.com/dell/cert-csi/pkg/observer"
+	"github.com/dell/cert-csi/pkg/plotter"
+	"github.com/dell/cert-csi/pkg/progress"
 	"github.com/dell/cert-csi/pkg/reporter"
 	"github.com/dell/cert-csi/pkg/store"
 	"github.com/dell/cert-csi/pkg/testcore/suites"
 	"github.com/dell/cert-csi/pkg/utils"
+	"github.com/dell/cert-csi/pkg/waitcondition"
 
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // SuiteRunner contains configuration to run performance test suite
@@ -54,6 +64,86 @@ type SuiteRunner struct {
 	IterationNum          int
 	Duration              time.Duration
 	ScDBs                 []*store.StorageClassDB
+	// Seed drives random names and ordering decisions, iteration N uses Seed+N-1
+	Seed int64
+	// NameGenerator generates suffixes of resource names, random hex suffixes are used if nil
+	NameGenerator k8sclient.NameGenerator
+	// Assertions are evaluated after suite with metrics of its test case, keyed by storage class and suite name
+	Assertions map[string]map[string]*collector.Assertions
+	// ProgressAddress is an address to serve live progress on, disabled if empty
+	ProgressAddress string
+	progress        *progress.Tracker
+	// Backend lists storage backend inventory before and after run to find leaked volumes and snapshots, disabled if nil
+	Backend backend.Verifier
+	// ExtraMetadata holds annotations and labels added to every PVC and pod, nil if there are none
+	ExtraMetadata *k8sclient.ExtraMetadata
+	// RBACAuditPath is a file minimal ClusterRole of the run is saved to, permissions are recorded by k8sclient.Audit
+	RBACAuditPath string
+	abortReason   string
+	cancelIter    context.CancelFunc
+	// ClassGuard defines what happens if storage or snapshot classes used by run change while it's in progress
+	ClassGuard ClassGuardMode
+	// KernelLogScan enables scraping kernel log of nodes running block volumes for IO errors
+	KernelLogScan bool
+	// TeardownTimeouts limit how long each teardown tier of suite namespace is waited for
+	TeardownTimeouts k8sclient.TierTimeouts
+	// DriverHealthProbe enables probing liveness endpoints of driver pods for unavailability windows
+	DriverHealthProbe bool
+	// Namespaces are pre-created namespaces suites run in with namespaced permissions only, suites create their own if empty
+	Namespaces    []string
+	namespacePool chan string
+	// RunTimeout aborts the run once exceeded, cancelling watches and waits of in-flight suites, disabled if 0
+	RunTimeout time.Duration
+	// Canary runs suites in always-on mode, appending to rolling run, disabled if nil
+	Canary *Canary
+	// Fairness runs suites of every storage class alone in the first iteration, so latencies under shared load
+	// of later iterations can be compared with isolated ones
+	Fairness bool
+	// Dependencies are names of suites a suite depends on by storage class and suite name,
+	// suite is skipped if any of them failed or was skipped in the same iteration
+	Dependencies map[string]map[string][]string
+	// Compare runs the same suite with every storage class at once, suite by suite, so storage classes of different
+	// drivers share cluster conditions, and generates report comparing them
+	Compare bool
+	// WaitConditions are extra conditions objects must satisfy after suite, keyed by storage class and suite name
+	WaitConditions map[string]map[string][]*waitcondition.Condition
+	// MarkBaseline marks runs as baselines later runs of their storage classes are compared with
+	MarkBaseline bool
+	// RegressionThreshold is percent stage latency may exceed baseline run by, DefaultRegressionThreshold if 0
+	RegressionThreshold float64
+	// FailOnRegression makes run fail if any stage latency regressed compared to baseline run
+	FailOnRegression bool
+	regressions      int
+	// BaselineStorageClass is storage class without CSI overhead, e.g. local-path, suites run with it alongside
+	// others and stage latencies of other storage classes are compared with its ones
+	BaselineStorageClass string
+	// BackgroundLoad keeps control plane busy with configmaps and pods during run, disabled if nil
+	BackgroundLoad *load.Profile
+	loadGen        *load.Generator
+	// CapabilityExpectations are capabilities driver profile expects, driver isn't cross-checked with profile if nil
+	CapabilityExpectations *k8sclient.CapabilityExpectations
+	// Only are numbers of iterations to run out of IterationNum, all of them run if empty. Iteration keeps its
+	// seed, so with seed of the original run failing iterations are re-run without repeating the others
+	Only []int
+	// RetryOf is name of the run Only iterations are re-run from, run is recorded as its retry run
+	RetryOf string
+	// TempClasses are storage and snapshot classes created from templates for the run, deleted once it's over
+	TempClasses *k8sclient.TempClasses
+	// Live shows rolling statistics of running suites in place on terminal, as periodic lines otherwise
+	Live bool
+	// Budget stops run from launching iterations once any of its limits is exceeded, disabled if nil
+	Budget *Budget
+	// IgnoreSignals leaves interrupt signals to the embedding program, run is stopped by cancelling context of Run
+	IgnoreSignals bool
+	// Status reports suites and result of run as Kubernetes Events of cert-csi pod when it runs in cluster, disabled if nil
+	Status *k8sclient.RunnerStatus
+	// ReportPath is directory reports of runs are generated in, .cert-csi folder of plotter.UserPath is used if empty
+	ReportPath string
+
+	plan          *planState
+	baselineCases map[string]map[int]*store.TestCase
+	// iteration is number of the iteration in progress
+	iteration int
 }
 
 // TestResult stores test result
@@ -68,24 +158,43 @@ const (
 	Threshold = 0.9
 )
 
-func checkValidNamespace(driverNs string, runner *Runner) {
+func checkValidNamespace(driverNs string, runner *Runner) error {
 	// Check if driver namespace exists
 	if driverNs != "" {
 		nsEx, nsErr := runner.KubeClient.NamespaceExists(context.Background(), driverNs)
+		if apierrs.IsForbidden(nsErr) {
+			// Restricted runs aren't allowed to get namespaces
+			logrus.Warnf("Not allowed to check existence of namespace %s, assuming it exists", driverNs)
+			return nil
+		}
 		if nsErr != nil {
 			logrus.Errorf("Can't check existence of namespace; error=%v", nsErr)
 		}
 		if !nsEx {
-			logrus.Fatalf("Can't find namespace %s", driverNs)
+			return fmt.Errorf("can't find namespace %s", driverNs)
 		}
 	}
+	return nil
 }
 
-// NewSuiteRunner creates and returns SuiteRunner
+// NewSuiteRunner creates and returns SuiteRunner, it exits if cluster can't be reached or storage classes and
+// namespaces don't exist
 func NewSuiteRunner(configPath, driverNs, startHook, readyHook, finishHook, observerType, longevity string, driverNSHealthMetrics string,
 	timeout int, cooldown int, sequentialExecution, noCleanup, noCleanupOnFail, noMetrics bool, noReport bool, scDBs []*store.StorageClassDB,
 ) *SuiteRunner {
-	runner := getSuiteRunner(
+	sr, err := NewSuiteRunnerE(configPath, driverNs, startHook, readyHook, finishHook, observerType, longevity, driverNSHealthMetrics,
+		timeout, cooldown, sequentialExecution, noCleanup, noCleanupOnFail, noMetrics, noReport, scDBs)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	return sr
+}
+
+// NewSuiteRunnerE creates SuiteRunner like NewSuiteRunner, but returns error instead of exiting
+func NewSuiteRunnerE(configPath, driverNs, startHook, readyHook, finishHook, observerType, longevity string, driverNSHealthMetrics string,
+	timeout int, cooldown int, sequentialExecution, noCleanup, noCleanupOnFail, noMetrics bool, noReport bool, scDBs []*store.StorageClassDB,
+) (*SuiteRunner, error) {
+	runner, err := newRunner(
 		configPath,
 		driverNs,
 		observerType,
@@ -94,14 +203,22 @@ func NewSuiteRunner(configPath, driverNs, startHook, readyHook, finishHook, obse
 		noCleanupOnFail,
 		noReport,
 	)
+	if err != nil {
+		return nil, err
+	}
 	for _, scDB := range scDBs {
 		// Checking storage if storageClass exists
 		scEx, scErr := runner.KubeClient.StorageClassExists(context.Background(), scDB.StorageClass)
+		if apierrs.IsForbidden(scErr) {
+			// Restricted runs aren't allowed to get cluster-scoped storage classes
+			logrus.Warnf("Not allowed to check existence of storage class %s, assuming it exists", scDB.StorageClass)
+			scEx, scErr = true, nil
+		}
 		if scErr != nil {
 			logrus.Errorf("Can't check existence of storageClass; error=%v", scErr)
 		}
 		if !scEx {
-			logrus.Fatalf("Can't find storage class %s", scDB.StorageClass)
+			return nil, fmt.Errorf("can't find storage class %s", scDB.StorageClass)
 		}
 		generateTestRunDetails(scDB, runner.KubeClient, runner.Config.Host)
 	}
@@ -125,11 +242,15 @@ func NewSuiteRunner(configPath, driverNs, startHook, readyHook, finishHook, obse
 		logrus.Infof("Running longevity for %d week(s) %d day(s) %d hour(s) %d minute(s) %d second(s)", extendedDur.Weeks, extendedDur.Days, extendedDur.Hours, extendedDur.Minutes, extendedDur.Seconds)
 	}
 
-	checkValidNamespace(driverNs, runner)
-	checkValidNamespace(driverNSHealthMetrics, runner)
+	if err := checkValidNamespace(driverNs, runner); err != nil {
+		return nil, err
+	}
+	if err := checkValidNamespace(driverNSHealthMetrics, runner); err != nil {
+		return nil, err
+	}
 
 	return &SuiteRunner{
-		&Runner{
+		Runner: &Runner{
 			Config:          runner.Config,
 			DriverNamespace: runner.DriverNamespace,
 			KubeClient:      runner.KubeClient,
@@ -139,17 +260,124 @@ func NewSuiteRunner(configPath, driverNs, startHook, readyHook, finishHook, obse
 			noCleaning:      runner.noCleaning,
 			noreport:        runner.noreport,
 		},
-		cooldown,
-		startHook,
-		readyHook,
-		finishHook,
-		driverNSHealthMetrics,
-		sequentialExecution,
-		noMetrics,
-		noReport,
-		iterNum,
-		duration,
-		scDBs,
+		CoolDownPeriod:        cooldown,
+		StartHookPath:         startHook,
+		ReadyHookPath:         readyHook,
+		FinishHookPath:        finishHook,
+		DriverNSHealthMetrics: driverNSHealthMetrics,
+		sequentialExecution:   sequentialExecution,
+		NoMetrics:             noMetrics,
+		NoReport:              noReport,
+		IterationNum:          iterNum,
+		Duration:              duration,
+		ScDBs:                 scDBs,
+		ClassGuard:            ClassGuardWarn,
+	}, nil
+}
+
+// SelectIterations restricts run to iterations listed in comma separated numbers, ex. 12,37
+func (sr *SuiteRunner) SelectIterations(only string) error {
+	if only == "" {
+		return nil
+	}
+	if sr.IterationNum <= 0 {
+		return errors.New("iterations can be selected only if longevity is a number of iterations")
+	}
+	selected := make(map[int]bool)
+	for _, field := range strings.Split(only, ",") {
+		iter, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return fmt.Errorf("invalid iteration %q: %w", field, err)
+		}
+		if iter < 1 || iter > sr.IterationNum {
+			return fmt.Errorf("iteration %d is out of range of %d iterations", iter, sr.IterationNum)
+		}
+		if !selected[iter] {
+			selected[iter] = true
+			sr.Only = append(sr.Only, iter)
+		}
+	}
+	sort.Ints(sr.Only)
+	return nil
+}
+
+// suiteRate returns volume creation rate of paced suites, zero for others
+func suiteRate(suite suites.Interface) float64 {
+	if paced, ok := suite.(suites.Paced); ok {
+		return paced.GetRate()
+	}
+	return 0
+}
+
+// saveSuiteResults saves everything the suite collected besides events, as far as the suite collects it
+func saveSuiteResults(ctx context.Context, suite suites.Interface, db store.Store, testCase *store.TestCase) {
+	if comparer, ok := suite.(suites.Comparer); ok {
+		saveResults(ctx, testCase, comparer.GetComparisons(), db.SaveComparisons, "comparisons")
+	}
+	if ramped, ok := suite.(suites.Ramped); ok {
+		saveResults(ctx, testCase, ramped.GetRampStages(), db.SaveRampStages, "ramp stages")
+	}
+	if validator, ok := suite.(suites.MountValidator); ok {
+		saveResults(ctx, testCase, validator.GetMountChecks(), db.SaveMountChecks, "mount checks")
+	}
+	if validator, ok := suite.(suites.VolumeStatsValidator); ok {
+		saveResults(ctx, testCase, validator.GetVolumeStats(), db.SaveVolumeStats, "volume stats")
+	}
+	if tester, ok := suite.(suites.MountOptionTester); ok {
+		saveResults(ctx, testCase, tester.GetMountOptionResults(), db.SaveMountOptionResults, "mount option results")
+	}
+	if filler, ok := suite.(suites.CapacityFiller); ok {
+		saveResults(ctx, testCase, filler.GetCapacityFillResults(), db.SaveCapacityFillResults, "capacity fill results")
+	}
+	if tester, ok := suite.(suites.MountRecoveryTester); ok {
+		saveResults(ctx, testCase, tester.GetMountRecoveryResults(), db.SaveMountRecoveryResults, "mount recovery results")
+	}
+	if validator, ok := suite.(suites.ConcurrentWriteValidator); ok {
+		saveResults(ctx, testCase, validator.GetConcurrentWriteResults(), db.SaveConcurrentWriteResults, "concurrent write results")
+	}
+	if tester, ok := suite.(suites.ControllerFailoverTester); ok {
+		saveResults(ctx, testCase, tester.GetControllerFailoverResults(), db.SaveControllerFailoverResults, "controller failover results")
+	}
+	if prober, ok := suite.(suites.DetachProber); ok {
+		saveResults(ctx, testCase, prober.GetDetachProbeResults(), db.SaveDetachProbeResults, "detach probe results")
+	}
+	if injector, ok := suite.(suites.FaultInjector); ok {
+		saveResults(ctx, testCase, injector.GetFaultWindows(), db.SaveFaultWindows, "fault windows")
+	}
+	if sampler, ok := suite.(suites.Sampler); ok {
+		saveResults(ctx, testCase, sampler.GetLatencySamples(), db.SaveLatencySamples, "latency samples")
+	}
+	if validator, ok := suite.(suites.ExpansionValidator); ok {
+		saveResults(ctx, testCase, validator.GetExpansionOutcomes(), db.SaveExpansionOutcomes, "expansion outcomes")
+	}
+	saveTags(ctx, suite, db, testCase)
+}
+
+// saveResults assigns results to the test case and saves them, what names the results in logged error
+func saveResults[T interface{ SetTcID(int64) }](ctx context.Context, testCase *store.TestCase, results []T, save func([]T) error, what string) {
+	if len(results) == 0 {
+		return
+	}
+	for _, r := range results {
+		r.SetTcID(testCase.ID)
+	}
+	if err := save(results); err != nil {
+		utils.GetLoggerFromContext(ctx).Errorf("Can't save %s; error=%v", what, err)
+	}
+}
+
+// saveTags saves events suite tagged its objects with, if it tags any
+func saveTags(ctx context.Context, suite suites.Interface, db store.Store, testCase *store.TestCase) {
+	tagger, ok := suite.(suites.Tagger)
+	if !ok {
+		return
+	}
+	tags := tagger.GetTags()
+	if len(tags) == 0 {
+		return
+	}
+	if err := observer.SaveTags(db, testCase, tags); err != nil {
+		utils.GetLoggerFromContext(ctx).Errorf("Can't save tags; error=%v", err)
 	}
 }
 
@@ -171,24 +399,56 @@ func ExecuteSuite(iterCtx context.Context, num int, suites map[string][]suites.I
 	ctx := context.WithValue(iterCtx, utils.LoggerContextKey, logger)
 	log := utils.GetLoggerFromContext(ctx)
 
+	failedDeps := sr.plan.wait(scDB.StorageClass, sr.dependencies(scDB.StorageClass, suite))
+
 	// Create and save current test case
 	testCase := &store.TestCase{
 		Name:           suite.GetName(),
 		Parameters:     suite.Parameters(),
 		StartTimestamp: time.Now(),
 		RunID:          scDB.TestRun.ID,
+		Rate:           suiteRate(suite),
+		Iteration:      sr.iteration,
 	}
 	if dbErr := db.SaveTestCase(testCase); dbErr != nil {
 		log.Errorf("Can't save test case to database; error=%v", dbErr)
 	}
+	sr.progress.SuiteStarted(testCase, scDB.StorageClass)
+	sr.recordBaselineCase(scDB.StorageClass, num, testCase)
+
+	if len(failedDeps) != 0 {
+		reason := fmt.Sprintf("skipped because %s failed", strings.Join(failedDeps, ", "))
+		log.Warnf("%s: %s %s", color.YellowString("SKIPPED"), color.CyanString(suite.GetName()), reason)
+		if saveErr := db.SkippedTestCase(testCase, time.Now(), reason); saveErr != nil {
+			log.Errorf("Can't save test case; error=%v", saveErr)
+		}
+		sr.plan.finish(scDB.StorageClass, suite.GetName(), false)
+		sr.progress.SuiteFinished(testCase, false, 0)
+		return
+	}
 
 	log.Infof("Starting %s with %s storage class", color.CyanString(suite.GetName()), color.CyanString(scDB.StorageClass))
+	sr.Status.SuiteStarted(ctx, suite.GetName(), scDB.StorageClass)
 	startTime := time.Now()
 
 	testResult, err := runSuite(ctx, suite, sr, testCase, db, scDB.StorageClass, c)
 	if err != nil {
 		log.Error(err)
 	}
+	saveSuiteResults(ctx, suite, db, testCase)
+	if !sr.NoMetrics {
+		mergeTimeline(ctx, db, testCase)
+	}
+
+	if assertErr := sr.checkAssertions(ctx, suite, scDB, testCase); assertErr != nil && testResult == SUCCESS {
+		testResult = FAILURE
+		err = assertErr
+		log.Error(err)
+	}
+
+	if sr.Canary != nil {
+		sr.checkCanary(ctx, db, testCase, testResult == SUCCESS)
+	}
 
 	var result string
 	if testResult == SUCCESS {
@@ -201,12 +461,18 @@ func ExecuteSuite(iterCtx context.Context, num int, suites map[string][]suites.I
 		if err == nil {
 			err = fmt.Errorf("unknown error encountered")
 		}
+		if reason := sr.AbortReason(); reason != "" {
+			err = fmt.Errorf("run aborted (%s): %w", reason, err)
+		}
 		if saveErr := db.FailedTestCase(testCase, time.Now(), err.Error()); saveErr != nil {
 			log.Errorf("Can't save test case; error=%v", saveErr)
 		}
 		result = color.RedString(string(testResult))
 	}
 	elapsed := time.Since(startTime)
+	sr.plan.finish(scDB.StorageClass, suite.GetName(), testResult == SUCCESS)
+	sr.progress.SuiteFinished(testCase, testResult == SUCCESS, elapsed)
+	sr.Status.SuiteFinished(ctx, suite.GetName(), scDB.StorageClass, testResult == SUCCESS, elapsed, testCase.ErrorMessage)
 
 	log.Infof("%s: %s in %s", result,
 		color.CyanString(suite.GetName()), color.HiYellowString(fmt.Sprint(elapsed)))
@@ -233,9 +499,143 @@ func ExecuteSuite(iterCtx context.Context, num int, suites map[string][]suites.I
 	}
 }
 
-// RunSuites runs test suites
+// checkAssertions evaluates assertions declared for suite and saves their results
+func (sr *SuiteRunner) checkAssertions(ctx context.Context, suite suites.Interface, scDB *store.StorageClassDB, testCase *store.TestCase) error {
+	var assertions *collector.Assertions
+	// config keys are case-insensitive
+	for name, a := range sr.Assertions[scDB.StorageClass] {
+		if strings.EqualFold(name, suite.GetName()) {
+			assertions = a
+		}
+	}
+	if assertions.IsEmpty() {
+		return nil
+	}
+	log := utils.GetLoggerFromContext(ctx)
+	if sr.NoMetrics {
+		log.Warnf("Metrics are disabled, skipping assertions of %s", suite.GetName())
+		return nil
+	}
+
+	tcMetrics := collector.NewMetricsCollector(scDB.DB).CollectTestCase(testCase)
+	results := assertions.Evaluate(&tcMetrics, time.Since(testCase.StartTimestamp))
+	if err := scDB.DB.SaveAssertionResults(results); err != nil {
+		log.Errorf("Can't save assertion results; error=%v", err)
+	}
+
+	var failed []string
+	for _, r := range results {
+		if r.Passed {
+			log.Infof("Assertion %s passed: %s (expected %s)", r.Name, r.Actual, r.Expected)
+		} else {
+			log.Errorf("Assertion %s failed: %s (expected %s)", r.Name, r.Actual, r.Expected)
+			failed = append(failed, r.Name)
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("assertions failed: %v", failed)
+	}
+	return nil
+}
+
+// backendInventory lists backend objects existing before run, nil if there is no backend verifier or listing failed
+func (sr *SuiteRunner) backendInventory() *backend.Inventory {
+	if sr.Backend == nil {
+		return nil
+	}
+	inventory, err := sr.Backend.Inventory(context.Background())
+	if err != nil {
+		logrus.Errorf("Can't list backend inventory, leaks won't be checked; error=%v", err)
+		return nil
+	}
+	logrus.Infof("Backend %s has %d volumes and %d snapshots before run", sr.Backend.Name(), len(inventory.Volumes), len(inventory.Snapshots))
+	return inventory
+}
+
+// probeCapabilities returns JSON of capabilities declared by driver of storage class, empty if they can't be probed
+func (sr *SuiteRunner) probeCapabilities(storageClass string) string {
+	caps, err := sr.KubeClient.DriverCapabilities(context.Background(), storageClass)
+	if err != nil {
+		logrus.Warnf("Can't probe capabilities declared by driver of storage class %s; error=%v", storageClass, err)
+		return ""
+	}
+	caps.Expected = sr.CapabilityExpectations
+	if unexpected := caps.Unexpected(); len(unexpected) != 0 {
+		logrus.Warnf("Driver of storage class %s doesn't declare %s as %s profile expects, check driver and storage class configuration",
+			storageClass, strings.Join(unexpected, ", "), caps.Expected.Profile)
+	}
+	data, err := json.Marshal(caps)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// checkBackendLeaks compares backend inventory with the one listed before run and saves objects left behind
+func (sr *SuiteRunner) checkBackendLeaks(before *backend.Inventory) {
+	if before == nil {
+		return
+	}
+	after, err := sr.Backend.Inventory(context.Background())
+	if err != nil {
+		logrus.Errorf("Can't list backend inventory; error=%v", err)
+		return
+	}
+	leaked := backend.Leaked(before, after)
+	if leaked.IsEmpty() {
+		logrus.Infof("No objects left behind on backend %s", sr.Backend.Name())
+		return
+	}
+
+	now := time.Now()
+	var leaks []*store.BackendLeak
+	for _, name := range leaked.Volumes {
+		logrus.Errorf("Volume %s left behind on backend", color.RedString(name))
+		leaks = append(leaks, &store.BackendLeak{Kind: "Volume", Name: name, Timestamp: now})
+	}
+	for _, name := range leaked.Snapshots {
+		logrus.Errorf("Snapshot %s left behind on backend", color.RedString(name))
+		leaks = append(leaks, &store.BackendLeak{Kind: "Snapshot", Name: name, Timestamp: now})
+	}
+	// Backend is shared by all storage classes of the run, so every test run gets the leaks
+	for _, scDB := range sr.ScDBs {
+		runLeaks := make([]*store.BackendLeak, 0, len(leaks))
+		for _, l := range leaks {
+			leak := *l
+			leak.RunID = scDB.TestRun.ID
+			runLeaks = append(runLeaks, &leak)
+		}
+		if err := scDB.DB.SaveBackendLeaks(runLeaks); err != nil {
+			logrus.Errorf("Can't save backend leaks; error=%v", err)
+		}
+	}
+}
+
+// saveRBACAudit saves role with permissions used by the run, if they were recorded
+func (sr *SuiteRunner) saveRBACAudit() {
+	if k8sclient.Audit == nil || sr.RBACAuditPath == "" {
+		return
+	}
+	if err := k8sclient.Audit.WriteRole(sr.RBACAuditPath, "cert-csi"); err != nil {
+		logrus.Errorf("Can't save RBAC audit; error=%v", err)
+		return
+	}
+	logrus.Infof("Saved minimal ClusterRole of the run to %s", color.CyanString(sr.RBACAuditPath))
+}
+
+// RunSuites runs test suites, it exits if too few suites succeeded or latencies regressed with FailOnRegression
 func (sr *SuiteRunner) RunSuites(suites map[string][]suites.Interface) {
+	if err := sr.Run(context.Background(), suites); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// Run runs test suites of storage classes, cancelling ctx aborts the run. Error is returned if too few suites
+// succeeded or latencies regressed with FailOnRegression
+func (sr *SuiteRunner) Run(ctx context.Context, suites map[string][]suites.Interface) (err error) {
 	sr.SucceededSuites = 0.0
+	var inventory *backend.Inventory
+	var guard *classGuard
 	defer func() {
 		totalNumberOfSuites := 0
 		for _, v := range suites {
@@ -243,26 +643,135 @@ func (sr *SuiteRunner) RunSuites(suites map[string][]suites.Interface) {
 		}
 
 		sr.SucceededSuites = sr.SucceededSuites / float64(totalNumberOfSuites*sr.IterationNum)
-		sr.Close()
+		if guard != nil {
+			guard.finish()
+		}
+		sr.finishBackgroundLoad()
+		sr.checkBackendLeaks(inventory)
+		sr.deleteTempClasses()
+		sr.saveRBACAudit()
+		sr.compareWithBaselineRun()
+		sr.saveNameCollisions()
+		k8sclient.ResetNames()
+		err = sr.close()
+		if len(sr.ScDBs) != 0 {
+			sr.Status.RunFinished(context.Background(), sr.ScDBs[0].TestRun.Name, err)
+		}
 	}()
 
+	if sr.Seed == 0 {
+		sr.Seed = time.Now().UnixNano()
+	}
+	logrus.Infof("Using seed %s, pass it with --seed to replay this run", color.CyanString(strconv.FormatInt(sr.Seed, 10)))
+	if sr.NameGenerator == nil {
+		sr.NameGenerator, _ = k8sclient.NewNameGenerator(k8sclient.RandomNames)
+	}
+	k8sclient.SetNameGenerator(sr.NameGenerator)
+	k8sclient.ResetNames()
+	logrus.Infof("Naming resources with %s generator", color.CyanString(sr.NameGenerator.Kind()))
+
+	if sr.restricted() {
+		sr.initNamespacePool()
+		logrus.Infof("Running in namespaces %s with namespaced permissions only", color.CyanString(strings.Join(sr.Namespaces, ", ")))
+	}
+	if sr.ClassGuard != ClassGuardOff {
+		guard = newClassGuard(context.Background(), sr, sr.ClassGuard, suites)
+	}
+	sr.startBackgroundLoad()
 	for _, scDB := range sr.ScDBs {
+		if sr.Canary != nil && resumeCanaryRun(scDB) {
+			logrus.Infof("Appending canaries to rolling run %s", color.CyanString(scDB.TestRun.Name))
+			continue
+		}
+		if guard != nil {
+			scDB.TestRun.ClassSpecs = guard.specsJSON(scDB.StorageClass)
+		}
+		scDB.TestRun.Seed = sr.Seed
+		scDB.TestRun.NameGenerator = sr.NameGenerator.Kind()
+		scDB.TestRun.RetryOf = sr.RetryOf
+		scDB.TestRun.Iterations = joinIterations(sr.Only)
+		scDB.TestRun.Metadata = sr.ExtraMetadata.String()
+		scDB.TestRun.Timeout = time.Duration(sr.Timeout) * time.Second
+		scDB.TestRun.NotObservable = sr.notObservable(suites)
+		scDB.TestRun.Capabilities = sr.probeCapabilities(scDB.StorageClass)
+		if sr.loadGen != nil {
+			scDB.TestRun.Load = loadJSON(load.Report{Profile: sr.loadGen.Profile})
+		}
 		tempTestRun := scDB
 		trErr := scDB.DB.SaveTestRun(&tempTestRun.TestRun)
 		if trErr != nil {
 			logrus.Errorf("Can't save test run; error=%v", trErr)
 		}
 	}
+	sr.setReportRoots(sr.ReportPath)
+	if guard != nil {
+		guard.start()
+	}
+	inventory = sr.backendInventory()
+	if len(sr.Only) != 0 {
+		logrus.Infof("Running iterations %s of %d", color.CyanString(joinIterations(sr.Only)), sr.IterationNum)
+		sr.progress = progress.NewTracker(len(sr.Only))
+	} else {
+		sr.progress = progress.NewTracker(sr.IterationNum)
+	}
+	stopWatch := context.AfterFunc(ctx, func() {
+		if err := sr.Abort("", "run context cancelled: "+context.Cause(ctx).Error()); err != nil {
+			logrus.Errorf("Can't abort run; error=%v", err)
+		}
+	})
+	defer stopWatch()
+	if sr.ProgressAddress != "" {
+		server := progress.NewServer(sr.ProgressAddress, sr.progress)
+		if token, err := progress.Token(); err != nil {
+			logrus.Errorf("Run can't be aborted via progress address; error=%v", err)
+		} else {
+			server.OnAbort(token, sr.Abort)
+		}
+		addr, err := server.Start()
+		if err != nil {
+			logrus.Errorf("Can't serve progress; error=%v", err)
+		} else {
+			logrus.Infof("Serving progress on %s", color.CyanString("http://"+addr+"/progress"))
+			defer server.Stop()
+		}
+	}
+	if sr.Live {
+		live := progress.NewLive(sr.progress, os.Stderr)
+		logrus.SetOutput(live.Writer())
+		live.Start()
+		defer func() {
+			live.Stop()
+			logrus.SetOutput(os.Stderr)
+		}()
+	}
+
 	if sr.Duration.Nanoseconds() > 0 {
 		time.AfterFunc(sr.Duration, func() {
 			sr.stop = true
 		})
 	}
+	if sr.RunTimeout > 0 {
+		timer := time.AfterFunc(sr.RunTimeout, func() {
+			if err := sr.Abort("", fmt.Sprintf("run timeout of %s exceeded", sr.RunTimeout)); err != nil {
+				logrus.Errorf("Can't abort run; error=%v", err)
+			}
+		})
+		defer timer.Stop()
+	}
+
+	if sr.Fairness && len(sr.ScDBs) > 1 && sr.IterationNum == 1 {
+		logrus.Infof("Fairness needs isolated and shared iterations, running 2 iterations")
+		sr.IterationNum = 2
+	}
 
 	var iterCtx context.Context
 	var c chan os.Signal
 	iterCtx, c = sr.runFlowManagementGoroutine()
 	iter := 1
+	if len(sr.Only) != 0 {
+		iter = sr.Only[0]
+	}
+	ran := 0
 
 	var charExecution byte
 	if sr.sequentialExecution {
@@ -283,8 +792,25 @@ func (sr *SuiteRunner) RunSuites(suites map[string][]suites.Interface) {
 			}
 
 			logrus.Infof(color.HiYellowString("\t*** ITERATION NUMBER %d ***\t"), iter)
+			sr.progress.SetIteration(iter)
+			sr.iteration = iter
+			iterSeed := sr.Seed + int64(iter-1)
+			k8sclient.SetSeed(iterSeed)
+			logrus.Debugf("Iteration %d seed: %d", iter, iterSeed)
 
-			switch charExecution {
+			if len(sr.Dependencies) != 0 {
+				sr.plan = newPlanState(suites)
+			}
+
+			mode := charExecution
+			if sr.Compare {
+				mode = 'c'
+			}
+			if sr.Fairness && iter == 1 {
+				logrus.Infof("Running suites of every storage class alone to measure isolated latencies")
+				mode = 's'
+			}
+			switch mode {
 			case 'p':
 				scErrs := errgroup.Group{}
 				for _, scDB := range sr.ScDBs {
@@ -311,6 +837,9 @@ func (sr *SuiteRunner) RunSuites(suites map[string][]suites.Interface) {
 					break
 				}
 
+			case 'c':
+				sr.runSideBySide(iterCtx, suites, c)
+
 			case 's':
 				for _, scDB := range sr.ScDBs {
 					scDB := scDB // https://golang.org/doc/faq#closures_and_goroutines
@@ -323,11 +852,32 @@ func (sr *SuiteRunner) RunSuites(suites map[string][]suites.Interface) {
 				}
 			}
 
-			if sr.IterationNum > 0 {
+			if sr.BaselineStorageClass != "" {
+				sr.compareWithBaseline()
+			}
+
+			ran++
+			if len(sr.Only) != 0 {
+				if ran >= len(sr.Only) {
+					break
+				}
+			} else if sr.IterationNum > 0 {
 				if iter >= sr.IterationNum {
 					break
 				}
 			}
+			if reason := sr.budgetExceeded(); reason != "" {
+				sr.truncate(reason)
+				break
+			}
+
+			if sr.Canary != nil {
+				logrus.Infof("Next canary in %s", sr.Canary.Interval)
+				select {
+				case <-time.After(sr.Canary.Interval):
+				case <-iterCtx.Done():
+				}
+			}
 
 			var kubeClient *k8sclient.KubeClient
 			for {
@@ -341,16 +891,51 @@ func (sr *SuiteRunner) RunSuites(suites map[string][]suites.Interface) {
 					break
 				}
 			}
+			kubeClient.ExtraMetadata = sr.ExtraMetadata
 			sr.KubeClient = kubeClient
-			iter++
+			if len(sr.Only) != 0 {
+				iter = sr.Only[ran]
+			} else {
+				iter++
+			}
 		}
 	}()
-	sr.IterationNum = iter
+	if len(sr.Only) != 0 {
+		sr.IterationNum = ran
+	} else {
+		sr.IterationNum = iter
+	}
+	return nil
+}
+
+// deleteTempClasses deletes classes created from templates for the run, if there are any
+func (sr *SuiteRunner) deleteTempClasses() {
+	if sr.TempClasses == nil {
+		return
+	}
+	if err := sr.TempClasses.Delete(context.Background()); err != nil {
+		logrus.Errorf("Can't delete temporary classes; error=%v", err)
+	}
+}
+
+// joinIterations formats iteration numbers the way they are passed to --only
+func joinIterations(iterations []int) string {
+	fields := make([]string, 0, len(iterations))
+	for _, iter := range iterations {
+		fields = append(fields, strconv.Itoa(iter))
+	}
+	return strings.Join(fields, ",")
 }
 
 func (sr *SuiteRunner) runFlowManagementGoroutine() (context.Context, chan os.Signal) {
 	iterCtx, cancelIter := context.WithCancel(context.Background())
+	sr.Lock()
+	sr.cancelIter = cancelIter
+	sr.Unlock()
 	c := make(chan os.Signal, 1)
+	if sr.IgnoreSignals {
+		return iterCtx, c
+	}
 	signal.Notify(c, os.Interrupt,
 		syscall.SIGTERM, // "the normal way to politely ask a program to terminate"
 		syscall.SIGINT,  // Ctrl+C
@@ -361,21 +946,8 @@ func (sr *SuiteRunner) runFlowManagementGoroutine() (context.Context, chan os.Si
 			return
 		}
 		logrus.Infof("Received termination signal, exiting asap")
-		fmt.Println("Do you want to stop test run or current iteration? (R)un/(i)teration")
-		readerStop := bufio.NewReader(os.Stdin)
-		fmt.Print("-> ")
-		charStop, _, err := readerStop.ReadRune()
-		if err != nil {
-			logrus.Error(err)
-		}
-
-		fmt.Println("Do you want cleanup namespace? (Y)es/(n)o")
-		readerCleanup := bufio.NewReader(os.Stdin)
-		fmt.Print("-> ")
-		charCleanup, _, err := readerCleanup.ReadRune()
-		if err != nil {
-			logrus.Error(err)
-		}
+		charStop := utils.Prompt("Do you want to stop test run or current iteration? (R)un/(i)teration", 'r')
+		charCleanup := utils.Prompt("Do you want cleanup namespace? (Y)es/(n)o", 'y')
 		switch charCleanup {
 		case 'n', 'N':
 			sr.NoCleaning()
@@ -410,11 +982,15 @@ func runSuite(ctx context.Context, suite suites.Interface, sr *SuiteRunner, test
 
 	var delFunc func() error
 
-	// Creating new namespace
-	namespace, nsErr := sr.KubeClient.CreateNamespaceWithSuffix(ctx, suite.GetNamespace())
+	// Creating new namespace, or taking one of pre-created in restricted mode
+	namespace, nsErr := sr.acquireNamespace(ctx, suite)
 	if nsErr != nil {
 		return FAILURE, fmt.Errorf("can't create namespace; error=%s", nsErr.Error())
 	}
+	defer sr.releaseNamespace(namespace.Name)
+	if !sr.NoMetrics {
+		sr.measureClockSkew(ctx, db, testCase, namespace.Name)
+	}
 
 	// Get needed clients for the current suite
 	clients, clientErr := suite.GetClients(namespace.Name, sr.KubeClient)
@@ -425,8 +1001,18 @@ func runSuite(ctx context.Context, suite suites.Interface, sr *SuiteRunner, test
 	var obs *observer.Runner
 	if !sr.NoMetrics {
 		// Create new observer runner, using list of important observers
-		observers := suite.GetObservers(sr.ObserverType)
+		observers := sr.observable(suite.GetObservers(sr.ObserverType))
+		if sr.KernelLogScan {
+			observers = append(observers, &observer.KernelLogObserver{})
+		}
+		if sr.DriverHealthProbe {
+			observers = append(observers, &observer.DriverHealthObserver{})
+		}
+		if _, ok := suite.(suites.SnapshotClassUser); ok && (clients.SnapClientGA != nil || clients.SnapClientBeta != nil) {
+			observers = append(observers, &observer.SnapshotObserver{})
+		}
 		obs = observer.NewObserverRunner(observers, clients, db, testCase, sr.DriverNamespace, sr.ShouldClean(SUCCESS))
+		obs.Progress = sr.progress
 		if obsErr := obs.Start(ctx); obsErr != nil {
 			return FAILURE, fmt.Errorf("can't create observer; error=%s", obsErr.Error())
 		}
@@ -451,12 +1037,28 @@ func runSuite(ctx context.Context, suite suites.Interface, sr *SuiteRunner, test
 			cancel()
 		}(sr)
 
+		if res != SUCCESS {
+			sr.captureState(ctx, db, testCase, namespace.Name)
+		}
+
 		// Cleanup after test
 		shouldClean := sr.ShouldClean(res)
 		if shouldClean {
+			var volumes map[string]string
+			if !sr.restricted() {
+				var err error
+				volumes, err = sr.claimedVolumes(ctx, namespace.Name)
+				if err != nil {
+					log.Warnf("Can't list volumes of namespace %s; error=%v", namespace.Name, err)
+				}
+			}
+
+			teardown := sr.trackTeardown(ctx, namespace.Name, volumes, clients)
+			defer teardown.Stop(testCase.ID)
+
 			log.Infof("Deleting all resources in namespace %s", namespace.Name)
 			delTime := time.Now()
-			if nsErr = sr.KubeClient.DeleteNamespace(ctx, namespace.Name); nsErr != nil {
+			if nsErr = sr.cleanNamespace(ctx, db, testCase.ID, namespace.Name); nsErr != nil {
 				res = FAILURE
 				resErr = fmt.Errorf("can't delete namespace; error=%v", nsErr.Error())
 				sr.delTime += time.Since(delTime)
@@ -473,6 +1075,23 @@ func runSuite(ctx context.Context, suite suites.Interface, sr *SuiteRunner, test
 				}
 			}
 			sr.delTime += time.Since(delTime)
+
+			if err := sr.checkOrphans(ctx, db, testCase, volumes); err != nil {
+				if resErr != nil {
+					err = fmt.Errorf("%v; %v", resErr, err)
+				}
+				res = FAILURE
+				resErr = err
+			}
+
+			latencies := teardown.Stop(testCase.ID)
+			for _, l := range latencies {
+				log.Infof("%s teardown took %s on average, %s max", l.Kind, color.HiYellowString(l.Avg.String()),
+					color.HiYellowString(l.Max.String()))
+			}
+			if err := db.SaveTeardownLatencies(latencies); err != nil {
+				log.Errorf("Can't save teardown latencies; error=%v", err)
+			}
 		}
 		if !sr.NoMetrics {
 			obs.ShouldClean = shouldClean
@@ -504,6 +1123,10 @@ func runSuite(ctx context.Context, suite suites.Interface, sr *SuiteRunner, test
 	}
 	sr.runTime += time.Since(runTime)
 
+	if err := sr.waitConditions(ctx, suite, storageClass, namespace.Name, db, testCase, runTime); err != nil {
+		return FAILURE, err
+	}
+
 	if err := runHook(sr.ReadyHookPath, "Ready Hook"); err != nil {
 		return FAILURE, fmt.Errorf("can't run ready hook; error=%s", err.Error())
 	}
@@ -559,14 +1182,90 @@ func (sr *SuiteRunner) Stop() {
 	sr.stop = true
 }
 
-// Close closes all databases
+// Abort stops the run on operator request: runs are marked aborted with the reason, current iteration is cancelled
+// and suites clean up the same way they do on interrupt. Empty run name matches runs of any storage class
+func (sr *SuiteRunner) Abort(run, reason string) error {
+	var matched bool
+	for _, scDB := range sr.ScDBs {
+		if run == "" || scDB.TestRun.Name == run {
+			matched = true
+		}
+	}
+	if !matched {
+		return fmt.Errorf("%w: %s", progress.ErrUnknownRun, run)
+	}
+	if reason == "" {
+		reason = "aborted by operator"
+	}
+
+	sr.Lock()
+	sr.abortReason = reason
+	cancelIter := sr.cancelIter
+	sr.Unlock()
+
+	// Suites of all storage classes share iteration, so runs of all of them are aborted
+	for _, scDB := range sr.ScDBs {
+		if err := scDB.DB.AbortedTestRun(&scDB.TestRun, reason); err != nil {
+			logrus.Errorf("Can't mark run %s aborted; error=%v", scDB.TestRun.Name, err)
+		}
+	}
+	sr.progress.Aborted(reason)
+	logrus.Warnf("Aborting run: %s", color.YellowString(reason))
+	sr.Stop()
+	if cancelIter != nil {
+		cancelIter()
+	}
+	return nil
+}
+
+// AbortReason returns the reason run was aborted with, empty if it wasn't aborted
+func (sr *SuiteRunner) AbortReason() string {
+	sr.RLock()
+	defer sr.RUnlock()
+	return sr.abortReason
+}
+
+// Close closes all databases, it exits if run failed
 func (sr *SuiteRunner) Close() {
+	if err := sr.close(); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// setReportRoots makes reports of runs generated in root, empty root puts them back under plotter.UserPath
+func (sr *SuiteRunner) setReportRoots(root string) {
+	if sr.ReportPath == "" {
+		return
+	}
+	for _, scDB := range sr.ScDBs {
+		plotter.SetReportRoot(scDB.TestRun.Name, root)
+	}
+}
+
+// saveNameCollisions records generated names which collided with taken ones, so generators which repeat
+// themselves often can be spotted
+func (sr *SuiteRunner) saveNameCollisions() {
+	collisions := k8sclient.NameCollisions()
+	if collisions == 0 {
+		return
+	}
+	logrus.Warnf("%d generated resource names collided with taken names and were regenerated", collisions)
+	for _, scDB := range sr.ScDBs {
+		if err := scDB.DB.SaveNameCollisions(&scDB.TestRun, collisions); err != nil {
+			logrus.Errorf("Can't save name collisions of run %s; error=%v", scDB.TestRun.Name, err)
+		}
+	}
+}
+
+// close generates reports and closes all databases, error is returned if run failed
+func (sr *SuiteRunner) close() error {
 	// Closing all databases
 	if !sr.noreport {
-		err := reporter.GenerateReportsFromMultipleDBs([]reporter.ReportType{
-			reporter.XMLReport,
-			reporter.TabularReport,
-		}, sr.ScDBs)
+		multiTypes := []reporter.ReportType{reporter.XMLReport, reporter.TabularReport}
+		if sr.Compare && !sr.NoMetrics {
+			multiTypes = append(multiTypes, reporter.ComparisonReport)
+		}
+		err := reporter.GenerateReportsFromMultipleDBs(multiTypes, sr.ScDBs)
 		if err != nil {
 			logrus.Errorf("Can't generate reports; error=%v", err)
 		}
@@ -585,13 +1284,17 @@ func (sr *SuiteRunner) Close() {
 			logrus.Errorf("Can't close database; error=%v", err)
 		}
 	}
+	sr.setReportRoots("")
 
 	logrus.Infof("Avg time of a run:\t %.2fs", sr.runTime.Seconds()/float64(sr.runNum))
 	logrus.Infof("Avg time of a del:\t %.2fs", sr.delTime.Seconds()/float64(sr.runNum))
 	logrus.Infof("Avg time of all:\t %.2fs", sr.allTime.Seconds()/float64(sr.runNum))
-	if sr.SucceededSuites > Threshold {
-		logrus.Infof("During this run %.1f%% of suites succeeded", sr.SucceededSuites*100)
-	} else {
-		logrus.Fatalf("During this run %.1f%% of suites succeeded", sr.SucceededSuites*100)
+	if sr.SucceededSuites <= Threshold {
+		return fmt.Errorf("during this run %.1f%% of suites succeeded", sr.SucceededSuites*100)
+	}
+	logrus.Infof("During this run %.1f%% of suites succeeded", sr.SucceededSuites*100)
+	if sr.regressions != 0 && sr.FailOnRegression {
+		return fmt.Errorf("%d stage latencies regressed compared to baseline run", sr.regressions)
 	}
+	return nil
 }
@@ -0,0 +1,126 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+)
+
+// ValidateDependencies checks that suites named in dependencies are planned and every suite a suite depends on runs before it.
+// Dependencies are keyed by suite name, names are case-insensitive
+func ValidateDependencies(planned []suites.Interface, dependencies map[string][]string) error {
+	first := make(map[string]int)
+	last := make(map[string]int)
+	for i, s := range planned {
+		name := strings.ToLower(s.GetName())
+		if _, ok := first[name]; !ok {
+			first[name] = i
+		}
+		last[name] = i
+	}
+	for suite, deps := range dependencies {
+		pos, ok := first[strings.ToLower(suite)]
+		if !ok {
+			return fmt.Errorf("suite %s has dependencies but isn't planned", suite)
+		}
+		for _, dep := range deps {
+			depPos, ok := last[strings.ToLower(dep)]
+			if !ok {
+				return fmt.Errorf("suite %s depends on %s which isn't planned", suite, dep)
+			}
+			if depPos >= pos {
+				return fmt.Errorf("suite %s depends on %s which must be planned before it", suite, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// planState tracks results of suites in current iteration, so suites can wait for suites they depend on
+type planState struct {
+	mutex sync.Mutex
+	cond  *sync.Cond
+	// pending is number of suites not finished yet by storage class and lowercase name
+	pending map[string]map[string]int
+	// failed holds suites which failed or were skipped by storage class and lowercase name
+	failed map[string]map[string]bool
+}
+
+func newPlanState(planned map[string][]suites.Interface) *planState {
+	p := &planState{
+		pending: make(map[string]map[string]int),
+		failed:  make(map[string]map[string]bool),
+	}
+	p.cond = sync.NewCond(&p.mutex)
+	for sc, ss := range planned {
+		p.pending[sc] = make(map[string]int)
+		p.failed[sc] = make(map[string]bool)
+		for _, s := range ss {
+			p.pending[sc][strings.ToLower(s.GetName())]++
+		}
+	}
+	return p
+}
+
+// wait blocks until every suite of storage class named in dependencies finished, returns those failed or skipped
+func (p *planState) wait(sc string, dependencies []string) []string {
+	if p == nil || len(dependencies) == 0 {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var failed []string
+	for _, dep := range dependencies {
+		name := strings.ToLower(dep)
+		for p.pending[sc][name] > 0 {
+			p.cond.Wait()
+		}
+		if p.failed[sc][name] {
+			failed = append(failed, dep)
+		}
+	}
+	return failed
+}
+
+// finish records result of suite and wakes up suites waiting for it
+func (p *planState) finish(sc, name string, succeeded bool) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	name = strings.ToLower(name)
+	p.pending[sc][name]--
+	if !succeeded {
+		p.failed[sc][name] = true
+	}
+	p.cond.Broadcast()
+}
+
+// dependencies returns suites the suite depends on with storage class, config keys are case-insensitive
+func (sr *SuiteRunner) dependencies(sc string, suite suites.Interface) []string {
+	for name, deps := range sr.Dependencies[sc] {
+		if strings.EqualFold(name, suite.GetName()) {
+			return deps
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRegressionThreshold is percent stage latency may exceed baseline run by before it is a regression
+const DefaultRegressionThreshold = 20.0
+
+// compareWithBaselineRun compares stage latencies of every run with the baseline run of its storage class,
+// saves them as comparisons and counts regressions, then marks runs as new baselines if requested
+func (sr *SuiteRunner) compareWithBaselineRun() {
+	for _, scDB := range sr.ScDBs {
+		runs, err := scDB.DB.GetTestRuns(store.Conditions{"storage_class": scDB.StorageClass, "baseline": true}, "", 1)
+		if err != nil {
+			logrus.Errorf("Can't find baseline run of storage class %s; error=%v", scDB.StorageClass, err)
+		} else if len(runs) != 0 && runs[0].ID != scDB.TestRun.ID {
+			sr.regressions += sr.saveRunComparisons(scDB, runs[0])
+		}
+
+		if sr.MarkBaseline {
+			if err := scDB.DB.MarkBaselineRun(&scDB.TestRun); err != nil {
+				logrus.Errorf("Can't mark run %s as baseline; error=%v", scDB.TestRun.Name, err)
+				continue
+			}
+			logrus.Infof("Run %s is the baseline of storage class %s now", color.CyanString(scDB.TestRun.Name), scDB.StorageClass)
+		}
+	}
+}
+
+// saveRunComparisons compares test cases of run with baseline run test cases of the same name and returns number of regressions
+func (sr *SuiteRunner) saveRunComparisons(scDB *store.StorageClassDB, baselineRun store.TestRun) int {
+	c := collector.NewMetricsCollector(scDB.DB)
+	baseline, err := c.Collect(baselineRun.Name)
+	if err != nil {
+		logrus.Errorf("Can't collect metrics of baseline run %s; error=%v", baselineRun.Name, err)
+		return 0
+	}
+	current, err := c.Collect(scDB.TestRun.Name)
+	if err != nil {
+		logrus.Errorf("Can't collect metrics of run %s; error=%v", scDB.TestRun.Name, err)
+		return 0
+	}
+
+	threshold := sr.RegressionThreshold
+	if threshold <= 0 {
+		threshold = DefaultRegressionThreshold
+	}
+	baselines := averageStageMetrics(baseline.TestCasesMetrics)
+	regressions := 0
+	var comparisons []*store.Comparison
+	for _, tc := range current.TestCasesMetrics {
+		b, ok := baselines[tc.TestCase.Name]
+		if !ok || !tc.TestCase.Success {
+			continue
+		}
+		for _, cmp := range baselineComparisons(tc.TestCase.ID, fmt.Sprintf("%s (baseline run)", baselineRun.Name), b, "this run", tc) {
+			cmp.Threshold = threshold
+			if cmp.Regressed() {
+				regressions++
+				logrus.Warnf("%s of %s regressed: %s, baseline run %s", cmp.Metric, tc.TestCase.Name,
+					color.RedString("%s (%+.1f%%)", cmp.CandidateValue, cmp.DifferencePercent()), cmp.BaselineValue)
+			}
+			comparisons = append(comparisons, cmp)
+		}
+	}
+	if len(comparisons) == 0 {
+		return 0
+	}
+	if err := scDB.DB.SaveComparisons(comparisons); err != nil {
+		logrus.Errorf("Can't save comparisons with baseline run; error=%v", err)
+	}
+	return regressions
+}
+
+// averageStageMetrics averages stage latencies of successful test cases by test case name
+func averageStageMetrics(testCases []collector.TestCaseMetrics) map[string]collector.TestCaseMetrics {
+	sums := make(map[string]map[interface{}][]time.Duration)
+	for _, tc := range testCases {
+		if !tc.TestCase.Success {
+			continue
+		}
+		if sums[tc.TestCase.Name] == nil {
+			sums[tc.TestCase.Name] = make(map[interface{}][]time.Duration)
+		}
+		for stage, d := range tc.StageMetrics {
+			sums[tc.TestCase.Name][stage] = append(sums[tc.TestCase.Name][stage], d.Avg)
+		}
+	}
+
+	averages := make(map[string]collector.TestCaseMetrics)
+	for name, stages := range sums {
+		tc := collector.TestCaseMetrics{StageMetrics: make(map[interface{}]collector.DurationOfStage)}
+		for stage, durations := range stages {
+			var total time.Duration
+			for _, d := range durations {
+				total += d
+			}
+			tc.StageMetrics[stage] = collector.DurationOfStage{Avg: total / time.Duration(len(durations))}
+		}
+		averages[name] = tc
+	}
+	return averages
+}
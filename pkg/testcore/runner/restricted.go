@@ -0,0 +1,111 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/observer"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// notObservableVolumes describes what isn't checked when PersistentVolumes can't be listed
+const notObservableVolumes = "PersistentVolumes (orphans, teardown)"
+
+// restricted checks whether suites run in pre-created namespaces with namespaced permissions only
+func (sr *SuiteRunner) restricted() bool {
+	return len(sr.Namespaces) != 0
+}
+
+// initNamespacePool makes every pre-created namespace available to suites
+func (sr *SuiteRunner) initNamespacePool() {
+	sr.namespacePool = make(chan string, len(sr.Namespaces))
+	for _, ns := range sr.Namespaces {
+		sr.namespacePool <- ns
+	}
+}
+
+// acquireNamespace creates namespace for the suite, or waits for one of pre-created namespaces to be free in restricted mode
+func (sr *SuiteRunner) acquireNamespace(ctx context.Context, suite suites.Interface) (*v1.Namespace, error) {
+	if !sr.restricted() {
+		return sr.KubeClient.CreateNamespaceWithSuffix(ctx, suite.GetNamespace())
+	}
+
+	var name string
+	select {
+	case name = <-sr.namespacePool:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// Listing pods is the cheapest way to verify that namespace exists and we are allowed to work in it
+	if _, err := sr.KubeClient.ClientSet.CoreV1().Pods(name).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		sr.releaseNamespace(name)
+		return nil, fmt.Errorf("can't use namespace %s; error=%v", name, err)
+	}
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+}
+
+// releaseNamespace returns pre-created namespace to the pool, it does nothing outside of restricted mode
+func (sr *SuiteRunner) releaseNamespace(name string) {
+	if sr.restricted() {
+		sr.namespacePool <- name
+	}
+}
+
+// observable drops observers which need cluster-scoped permissions in restricted mode
+func (sr *SuiteRunner) observable(observers []observer.Interface) []observer.Interface {
+	if !sr.restricted() {
+		return observers
+	}
+	var kept []observer.Interface
+	for _, obs := range observers {
+		if what := observer.NotObservable(obs); what != "" {
+			logrus.Debugf("Skipping %s, %s are not observable in restricted mode", obs.GetName(), what)
+			continue
+		}
+		kept = append(kept, obs)
+	}
+	return kept
+}
+
+// notObservable lists what suites of the run can't observe in restricted mode
+func (sr *SuiteRunner) notObservable(suites map[string][]suites.Interface) string {
+	if !sr.restricted() {
+		return ""
+	}
+	unique := map[string]bool{notObservableVolumes: true}
+	for _, ss := range suites {
+		for _, suite := range ss {
+			for _, obs := range suite.GetObservers(sr.ObserverType) {
+				if what := observer.NotObservable(obs); what != "" {
+					unique[what] = true
+				}
+			}
+		}
+	}
+	var list []string
+	for what := range unique {
+		list = append(list, what)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
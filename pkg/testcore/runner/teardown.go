@@ -0,0 +1,258 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+
+	"github.com/fatih/color"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TeardownPoll is the poll interval of teardown latency tracking
+var TeardownPoll = time.Second
+
+// listFunc returns names of objects of a single kind which still exist
+type listFunc func(ctx context.Context) (map[string]bool, error)
+
+// teardownTracker measures how long objects of each kind take to disappear after namespace deletion,
+// including time spent waiting for finalizers
+type teardownTracker struct {
+	start     time.Time
+	kinds     map[string]listFunc
+	pending   map[string]map[string]bool
+	latencies map[string][]time.Duration
+	stop      chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+	mutex     sync.Mutex
+}
+
+// trackTeardown records objects of namespace that must be gone after teardown and starts polling for them
+func (sr *SuiteRunner) trackTeardown(ctx context.Context, namespace string, volumes map[string]string, clients *k8sclient.Clients) *teardownTracker {
+	log := utils.GetLoggerFromContext(ctx)
+	cs := sr.KubeClient.ClientSet
+
+	kinds := map[string]listFunc{
+		"Namespace": func(ctx context.Context) (map[string]bool, error) {
+			if _, err := cs.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+				if apierrs.IsNotFound(err) {
+					return map[string]bool{}, nil
+				}
+				return nil, err
+			}
+			return map[string]bool{namespace: true}, nil
+		},
+		"PersistentVolumeClaim": func(ctx context.Context) (map[string]bool, error) {
+			list, err := cs.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			names := make(map[string]bool)
+			for _, item := range list.Items {
+				names[item.Name] = true
+			}
+			return names, nil
+		},
+		"PersistentVolume": func(ctx context.Context) (map[string]bool, error) {
+			list, err := cs.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			names := make(map[string]bool)
+			for _, item := range list.Items {
+				if _, ok := volumes[item.Name]; ok {
+					names[item.Name] = true
+				}
+			}
+			return names, nil
+		},
+	}
+	if sr.restricted() {
+		// Namespace outlives the suite and PersistentVolumes can't be listed
+		delete(kinds, "Namespace")
+		delete(kinds, "PersistentVolume")
+	}
+	if clients != nil && clients.SnapClientGA != nil {
+		kinds["VolumeSnapshot"] = func(ctx context.Context) (map[string]bool, error) {
+			list, err := clients.SnapClientGA.Interface.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			names := make(map[string]bool)
+			for _, item := range list.Items {
+				names[item.Name] = true
+			}
+			return names, nil
+		}
+	} else if clients != nil && clients.SnapClientBeta != nil {
+		kinds["VolumeSnapshot"] = func(ctx context.Context) (map[string]bool, error) {
+			list, err := clients.SnapClientBeta.Interface.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			names := make(map[string]bool)
+			for _, item := range list.Items {
+				names[item.Name] = true
+			}
+			return names, nil
+		}
+	}
+
+	tt := &teardownTracker{
+		start:     time.Now(),
+		kinds:     kinds,
+		pending:   make(map[string]map[string]bool),
+		latencies: make(map[string][]time.Duration),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for kind, list := range kinds {
+		names, err := list(ctx)
+		if err != nil {
+			log.Debugf("Can't list %s objects to track teardown; error=%v", kind, err)
+			continue
+		}
+		tt.pending[kind] = names
+	}
+
+	go tt.poll(ctx)
+	return tt
+}
+
+func (tt *teardownTracker) poll(ctx context.Context) {
+	defer close(tt.done)
+	ticker := time.NewTicker(TeardownPoll)
+	defer ticker.Stop()
+	for {
+		if tt.check(ctx) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tt.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check records latencies of objects that are gone, returns true if nothing is pending anymore
+func (tt *teardownTracker) check(ctx context.Context) bool {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	left := 0
+	for kind, names := range tt.pending {
+		if len(names) == 0 {
+			continue
+		}
+		existing, err := tt.kinds[kind](ctx)
+		if err != nil {
+			left += len(names)
+			continue
+		}
+		for name := range names {
+			if !existing[name] {
+				tt.latencies[kind] = append(tt.latencies[kind], time.Since(tt.start))
+				delete(names, name)
+			}
+		}
+		left += len(names)
+	}
+	return left == 0
+}
+
+// Stop stops tracking and returns teardown latency of every tracked kind, it is safe to call it multiple times
+func (tt *teardownTracker) Stop(tcID int64) []*store.TeardownLatency {
+	tt.stopOnce.Do(func() { close(tt.stop) })
+	<-tt.done
+
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	var kinds []string
+	for kind := range tt.pending {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var result []*store.TeardownLatency
+	for _, kind := range kinds {
+		latencies := tt.latencies[kind]
+		if len(latencies) == 0 && len(tt.pending[kind]) == 0 {
+			continue
+		}
+		l := &store.TeardownLatency{
+			TcID:      tcID,
+			Kind:      kind,
+			Count:     len(latencies),
+			Remaining: len(tt.pending[kind]),
+		}
+		var total time.Duration
+		for _, d := range latencies {
+			total += d
+			if d > l.Max {
+				l.Max = d
+			}
+		}
+		if len(latencies) != 0 {
+			l.Avg = total / time.Duration(len(latencies))
+		}
+		result = append(result, l)
+	}
+	return result
+}
+
+// cleanNamespace deletes resources of suite namespace tier by tier and saves how long every tier took,
+// namespace itself is kept in restricted mode
+func (sr *SuiteRunner) cleanNamespace(ctx context.Context, db store.Store, tcID int64, name string) error {
+	log := utils.GetLoggerFromContext(ctx)
+	results, err := sr.KubeClient.TeardownNamespace(ctx, name, sr.restricted(), sr.TeardownTimeouts)
+
+	tiers := make([]*store.TeardownTier, 0, len(results))
+	for i, r := range results {
+		tier := &store.TeardownTier{
+			TcID:      tcID,
+			Tier:      r.Tier,
+			Position:  i,
+			Duration:  r.Duration,
+			Deleted:   r.Deleted,
+			Remaining: r.Remaining,
+		}
+		if r.Err != nil {
+			tier.Message = r.Err.Error()
+		}
+		if r.Deleted != 0 {
+			log.Infof("Teardown tier %s of %d objects took %s", r.Tier, r.Deleted, color.HiYellowString(r.Duration.Round(time.Millisecond).String()))
+		}
+		tiers = append(tiers, tier)
+	}
+	if saveErr := db.SaveTeardownTiers(tiers); saveErr != nil {
+		log.Errorf("Can't save teardown tiers; error=%v", saveErr)
+	}
+	return err
+}
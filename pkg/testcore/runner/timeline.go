@@ -0,0 +1,47 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+
+	"github.com/dell/cert-csi/pkg/collector"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+)
+
+// measureClockSkew records how far cluster clock is ahead of runner host, so timestamps of API server can be put
+// on one timeline with ones observed by the runner. Skew stays zero if it can't be measured
+func (sr *SuiteRunner) measureClockSkew(ctx context.Context, db store.Store, testCase *store.TestCase, namespace string) {
+	log := utils.GetLoggerFromContext(ctx)
+	skew, err := sr.KubeClient.ClockSkew(ctx, namespace)
+	if err != nil {
+		log.Warnf("Can't measure clock skew between runner and cluster, timeline isn't corrected for it; error=%v", err)
+		return
+	}
+	log.Debugf("Cluster clock is %s ahead of runner", skew)
+	if err := db.SaveClockSkew(testCase, skew); err != nil {
+		log.Errorf("Can't save clock skew; error=%v", err)
+	}
+}
+
+// mergeTimeline merges everything recorded about test case into a single timeline
+func mergeTimeline(ctx context.Context, db store.Store, testCase *store.TestCase) {
+	if _, err := collector.MergeTimeline(db, testCase); err != nil {
+		utils.GetLoggerFromContext(ctx).Errorf("Can't merge timeline; error=%v", err)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/testcore/suites"
+	"github.com/dell/cert-csi/pkg/utils"
+	"github.com/dell/cert-csi/pkg/waitcondition"
+
+	"github.com/fatih/color"
+)
+
+// ValidateWaitConditions checks that suites wait conditions are declared for are planned and their expressions parse.
+// Conditions are keyed by suite name, names are case-insensitive
+func ValidateWaitConditions(planned []suites.Interface, conditions map[string][]*waitcondition.Condition) error {
+	for suite, conds := range conditions {
+		found := false
+		for _, s := range planned {
+			found = found || strings.EqualFold(s.GetName(), suite)
+		}
+		if !found {
+			return fmt.Errorf("suite %s has wait conditions but isn't planned", suite)
+		}
+		for _, cond := range conds {
+			if err := cond.Validate(); err != nil {
+				return fmt.Errorf("invalid wait condition of %s: %w", suite, err)
+			}
+		}
+	}
+	return nil
+}
+
+// waitConditions waits for extra conditions of suite in parallel and records outcome of every object as events,
+// since is when suite started running
+func (sr *SuiteRunner) waitConditions(ctx context.Context, suite suites.Interface, sc, namespace string,
+	db *store.SQLiteStore, testCase *store.TestCase, since time.Time,
+) error {
+	var conditions []*waitcondition.Condition
+	// config keys are case-insensitive
+	for name, conds := range sr.WaitConditions[sc] {
+		if strings.EqualFold(name, suite.GetName()) {
+			conditions = conds
+		}
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	log := utils.GetLoggerFromContext(ctx)
+	client, err := sr.KubeClient.CreateDynamicClient()
+	if err != nil {
+		return fmt.Errorf("can't create client of wait conditions; error=%v", err)
+	}
+
+	errs := make([]error, len(conditions))
+	var wg sync.WaitGroup
+	for i, cond := range conditions {
+		wg.Add(1)
+		go func(i int, cond *waitcondition.Condition) {
+			defer wg.Done()
+			log.Infof("Waiting for %s: %s", color.CyanString(cond.GetName()), cond.Expression)
+			results, err := cond.Wait(ctx, client, namespace, since)
+			if saveErr := saveWaitConditionResults(db, testCase, cond, results); saveErr != nil {
+				log.Errorf("Can't save results of wait condition %s; error=%v", cond.GetName(), saveErr)
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			var slowest time.Duration
+			for _, res := range results {
+				if res.Elapsed > slowest {
+					slowest = res.Elapsed
+				}
+			}
+			log.Infof("Wait condition %s met by %d objects, slowest in %s", color.CyanString(cond.GetName()), len(results),
+				color.HiYellowString(slowest.Round(time.Millisecond).String()))
+		}(i, cond)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// saveWaitConditionResults saves outcome of condition for every object as event of its entity, objects not observed
+// by observers get entities of unknown type
+func saveWaitConditionResults(db *store.SQLiteStore, testCase *store.TestCase, cond *waitcondition.Condition, results []*waitcondition.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+	entities, err := db.GetEntities(store.Conditions{"tc_id": testCase.ID}, "", 0)
+	if err != nil {
+		return err
+	}
+	entityIDs := make(map[string]int64)
+	for _, e := range entities {
+		entityIDs[e.K8sUID] = e.ID
+	}
+
+	var events []*store.Event
+	for _, res := range results {
+		entityID, ok := entityIDs[res.UID]
+		if !ok {
+			// Object may be cluster-scoped one shared with other test cases, so UID alone isn't unique
+			entity := &store.Entity{Name: res.Object, K8sUID: res.UID + "-" + k8sclient.UniqueSuffix(), TcID: testCase.ID, Type: store.Unknown}
+			if err := db.SaveEntities([]*store.Entity{entity}); err != nil {
+				return err
+			}
+			entityID = entity.ID
+			entityIDs[res.UID] = entityID
+		}
+		event := &store.Event{
+			Name:      "wait-condition-" + k8sclient.UniqueSuffix(),
+			TcID:      testCase.ID,
+			EntityID:  entityID,
+			Type:      store.WaitConditionMet,
+			Timestamp: res.ObservedAt,
+			Message:   fmt.Sprintf("%s: %s after %s", cond.GetName(), cond.Expression, res.Elapsed.Round(time.Millisecond)),
+		}
+		if !res.Met {
+			event.Type = store.WaitConditionTimedOut
+			event.Message = fmt.Sprintf("%s: %s not satisfied", cond.GetName(), cond.Expression)
+		}
+		events = append(events, event)
+	}
+	return db.SaveEvents(events)
+}
@@ -0,0 +1,126 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pvc"
+	"github.com/dell/cert-csi/pkg/store"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// CapacityFillTimeout is how long claim is given to bind before backend pool is considered exhausted
+var CapacityFillTimeout = 2 * time.Minute
+
+// grpcCodePattern matches status code of gRPC error driver failed with, as external provisioner formats it
+var grpcCodePattern = regexp.MustCompile(`code = (\w+)`)
+
+// waitClaimBound waits for claim to bind, false if it didn't bind within timeout
+func waitClaimBound(ctx context.Context, pvcClient *pvc.Client, name string, timeout time.Duration) (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, pvc.Poll, timeout, commonparams.Watch(pvcClient.Interface, commonparams.NameSelector(name)), func(context.Context) (bool, error) {
+		claim, err := pvcClient.Interface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return claim.Status.Phase == v1.ClaimBound, nil
+	})
+	if pollErr != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if wait.Interrupted(pollErr) {
+			return false, nil
+		}
+		return false, pollErr
+	}
+	return true, nil
+}
+
+// provisioningFailure returns the latest provisioning failure reported in events of the claim
+func provisioningFailure(ctx context.Context, pvcClient *pvc.Client, name string) string {
+	eventList, err := pvcClient.ClientSet.CoreV1().Events(pvcClient.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=PersistentVolumeClaim", name),
+	})
+	if err != nil {
+		return ""
+	}
+	var latest *v1.Event
+	for i, event := range eventList.Items {
+		if event.Reason != "ProvisioningFailed" {
+			continue
+		}
+		if latest == nil || !event.LastTimestamp.Before(&latest.LastTimestamp) {
+			latest = &eventList.Items[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// grpcCode returns gRPC status code in provisioning failure message, empty if message has none
+func grpcCode(message string) string {
+	match := grpcCodePattern.FindStringSubmatch(message)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// volumeWritable checks that pod is ready and data can still be written to its volume
+func volumeWritable(ctx context.Context, podClient *pod.Client, name, file string) bool {
+	actual, err := podClient.Interface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil || !pod.IsPodReady(actual) {
+		return false
+	}
+	dd := []string{"dd", "if=/dev/zero", "of=" + file, "bs=1M", "count=1", "oflag=sync"}
+	var stderr strings.Builder
+	return podClient.Exec(ctx, actual, dd, &stderr, &stderr, false) == nil
+}
+
+// capacityFillProblems returns error describing what driver did wrong when backend pool was exhausted, nil if nothing
+func capacityFillProblems(res *store.CapacityFillResult, maxVolumes int) error {
+	var problems []string
+	switch {
+	case !res.Exhausted:
+		problems = append(problems, fmt.Sprintf("backend pool wasn't exhausted by %d volumes of %s", maxVolumes, res.VolumeSize))
+	case res.Message == "":
+		problems = append(problems, "rejected claim has no provisioning failure event")
+	case res.ErrorCode != "ResourceExhausted":
+		problems = append(problems, fmt.Sprintf("driver failed provisioning with %q instead of ResourceExhausted", res.ErrorCode))
+	}
+	if res.Healthy != res.Bound {
+		problems = append(problems, fmt.Sprintf("%d of %d bound volumes aren't writable once pool was exhausted", res.Bound-res.Healthy, res.Bound))
+	}
+	if res.Exhausted && !res.Restored {
+		problems = append(problems, "volume couldn't be provisioned after test volumes were deleted")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("capacity-full behavior is invalid: %s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,94 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/store"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrentWriteRecordSize is size of a single appended record in bytes, newline included
+var ConcurrentWriteRecordSize = 64
+
+// concurrentWriterLabel labels writers of concurrent write suite, so anti-affinity spreads them over nodes
+const concurrentWriterLabel = "rwx-concurrent-writer"
+
+// spreadWriters adds anti-affinity keeping writer off nodes other writers run on
+func spreadWriters(p *v1.Pod) {
+	if p.Labels == nil {
+		p.Labels = make(map[string]string)
+	}
+	p.Labels["app"] = concurrentWriterLabel
+	p.Spec.Affinity = &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": concurrentWriterLabel}},
+			TopologyKey:   v1.LabelHostname,
+		}},
+	}}
+}
+
+// appendScript returns shell script appending records of writer to file one by one, every append opens the file
+// again so writes of different nodes can interleave
+func appendScript(writer, file string, records int) string {
+	return fmt.Sprintf(`for n in $(seq 1 %d); do printf '%%-%ds\n' "%s $n" >> %s || exit 1; done && sync`,
+		records, ConcurrentWriteRecordSize-1, writer, file)
+}
+
+// validateRecords checks content of file writers appended records to, every writer is expected to append records
+// numbered from 1 to records exactly once
+func validateRecords(res *store.ConcurrentWriteResult, content []byte, writers []string, records int) {
+	res.Expected = len(writers) * records
+	res.ExpectedSize = int64(res.Expected * ConcurrentWriteRecordSize)
+	res.Size = int64(len(content))
+
+	expected := make(map[string]bool, len(writers))
+	for _, w := range writers {
+		expected[w] = true
+	}
+	found := make(map[string]bool)
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.Fields(string(line))
+		if len(line) != ConcurrentWriteRecordSize || line[len(line)-1] != '\n' || len(fields) != 2 || !expected[fields[0]] {
+			res.Corrupted++
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 || n > records {
+			res.Corrupted++
+			continue
+		}
+		found[string(line)] = true
+	}
+	res.Intact = len(found)
+	res.Lost = res.Expected - res.Intact
+	res.Passed = res.Lost == 0 && res.Corrupted == 0 && res.Size == res.ExpectedSize
+}
+
+// describeWriteResult formats what validator found in file
+func describeWriteResult(res *store.ConcurrentWriteResult) string {
+	return fmt.Sprintf("%s: %d/%d records intact, %d lost, %d corrupted lines, %d/%d bytes",
+		res.File, res.Intact, res.Expected, res.Lost, res.Corrupted, res.Size, res.ExpectedSize)
+}
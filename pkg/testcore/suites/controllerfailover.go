@@ -0,0 +1,245 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ControllerDeleteLeader is a disruption deleting pod holding provisioner lease, so a standby has to take over
+	ControllerDeleteLeader = "delete-leader"
+	// ControllerScale is a disruption scaling controller Deployment down to zero and back up
+	ControllerScale = "scale"
+	// provisioningSucceeded is a reason of event external-provisioner reports once volume of claim is provisioned
+	provisioningSucceeded = "ProvisioningSucceeded"
+)
+
+var (
+	// ControllerFailoverPoll is an interval between checks of leases and controller pods after disruption
+	ControllerFailoverPoll = 2 * time.Second
+	// LeaderHandoverTimeout is how long controller is given to elect new leaders and become ready after disruption
+	LeaderHandoverTimeout = 5 * time.Minute
+)
+
+// leaseNameSanitizer matches characters leader election replaces with dashes when deriving lease name from driver name
+var leaseNameSanitizer = regexp.MustCompile("[^a-zA-Z0-9-]")
+
+// controllerDeployment returns Deployment with the name, or the only Deployment of namespace running csi-provisioner
+func controllerDeployment(ctx context.Context, clientSet kubernetes.Interface, namespace, name string) (*appsv1.Deployment, error) {
+	if name != "" {
+		return clientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	list, err := clientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var found []*appsv1.Deployment
+	for i := range list.Items {
+		for _, c := range list.Items[i].Spec.Template.Spec.Containers {
+			if strings.Contains(c.Name, "provisioner") || strings.Contains(c.Image, "csi-provisioner") {
+				found = append(found, &list.Items[i])
+				break
+			}
+		}
+	}
+	if len(found) != 1 {
+		return nil, fmt.Errorf("found %d deployments running csi-provisioner in namespace %s, specify controller deployment", len(found), namespace)
+	}
+	return found[0], nil
+}
+
+// deploymentPods returns running pods of the Deployment by name
+func deploymentPods(ctx context.Context, clientSet kubernetes.Interface, d *appsv1.Deployment) (map[string]*v1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	list, err := clientSet.CoreV1().Pods(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	pods := make(map[string]*v1.Pod)
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.Status.Phase == v1.PodRunning && p.DeletionTimestamp == nil {
+			pods[p.Name] = p
+		}
+	}
+	return pods, nil
+}
+
+// leaseHolder returns pod of the set holding lease with identity, sidecars use pod name optionally followed by
+// underscore and random suffix as identity
+func leaseHolder(identity string, pods map[string]*v1.Pod) string {
+	for name := range pods {
+		if identity == name || strings.HasPrefix(identity, name+"_") {
+			return name
+		}
+	}
+	return ""
+}
+
+// controllerLeases returns holder identities of leases of namespace held by pods of the set by lease name
+func controllerLeases(ctx context.Context, clientSet kubernetes.Interface, namespace string, pods map[string]*v1.Pod) (map[string]string, error) {
+	list, err := clientSet.CoordinationV1().Leases(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	leases := make(map[string]string)
+	for _, l := range list.Items {
+		if l.Spec.HolderIdentity == nil || leaseHolder(*l.Spec.HolderIdentity, pods) == "" {
+			continue
+		}
+		leases[l.Name] = *l.Spec.HolderIdentity
+	}
+	return leases, nil
+}
+
+// provisionerLease picks lease of external-provisioner, its name is derived from driver name, or the first lease
+// with provisioner in its name if driver name doesn't match any
+func provisionerLease(leases map[string]string, driver string) (string, error) {
+	if len(leases) == 0 {
+		return "", errors.New("no lease is held by controller pods, controller doesn't use leader election")
+	}
+	if name := leaseNameSanitizer.ReplaceAllString(driver, "-"); leases[name] != "" {
+		return name, nil
+	}
+	names := make([]string, 0, len(leases))
+	for name := range leases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.Contains(name, "provisioner") {
+			return name, nil
+		}
+	}
+	return names[0], nil
+}
+
+// waitHandedOver waits until every lease is held by a running controller pod other than its previous holder and
+// returns new holders. Holders are returned even on timeout, so report shows which leases were left behind
+func waitHandedOver(ctx context.Context, clientSet kubernetes.Interface, d *appsv1.Deployment, old map[string]string) (map[string]string, error) {
+	current := make(map[string]string)
+	leases := clientSet.CoordinationV1().Leases(d.Namespace)
+	err := commonparams.PollUntil(ctx, ControllerFailoverPoll, LeaderHandoverTimeout, commonparams.Watch(leases, metav1.ListOptions{}), func(context.Context) (bool, error) {
+		pods, err := deploymentPods(ctx, clientSet, d)
+		if err != nil {
+			return false, nil
+		}
+		held, err := controllerLeases(ctx, clientSet, d.Namespace, pods)
+		if err != nil {
+			return false, nil
+		}
+		done := true
+		for name, holder := range old {
+			current[name] = held[name]
+			if held[name] == "" || held[name] == holder {
+				done = false
+			}
+		}
+		return done, nil
+	})
+	return current, err
+}
+
+// waitDeploymentReady waits until every replica of the Deployment is updated and ready
+func waitDeploymentReady(ctx context.Context, clientSet kubernetes.Interface, namespace, name string) error {
+	deployments := clientSet.AppsV1().Deployments(namespace)
+	return commonparams.PollUntil(ctx, ControllerFailoverPoll, LeaderHandoverTimeout, commonparams.Watch(deployments, commonparams.NameSelector(name)), func(context.Context) (bool, error) {
+		d, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		return d.Status.ObservedGeneration >= d.Generation && d.Status.UpdatedReplicas == replicas &&
+			d.Status.ReadyReplicas == replicas && d.Status.Replicas == replicas, nil
+	})
+}
+
+// scaleDeployment sets number of replicas of the Deployment
+func scaleDeployment(ctx context.Context, clientSet kubernetes.Interface, namespace, name string, replicas int32) error {
+	deployments := clientSet.AppsV1().Deployments(namespace)
+	scale, err := deployments.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	scale.Spec.Replicas = replicas
+	_, err = deployments.UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+	return err
+}
+
+// provisionedAt returns when volumes of claims in namespace were reported provisioned
+func provisionedAt(ctx context.Context, clientSet kubernetes.Interface, namespace string) ([]time.Time, error) {
+	eventList, err := clientSet.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=PersistentVolumeClaim,reason=" + provisioningSucceeded,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var times []time.Time
+	for _, event := range eventList.Items {
+		switch {
+		case !event.EventTime.IsZero():
+			times = append(times, event.EventTime.Time)
+		case !event.FirstTimestamp.IsZero():
+			times = append(times, event.FirstTimestamp.Time)
+		}
+	}
+	return times, nil
+}
+
+// provisioningStall returns the longest window since disruption without a volume being provisioned, provisioning
+// which hadn't resumed by until stalls until then. Events have second precision, so disruption is truncated to seconds
+func provisioningStall(provisioned []time.Time, disrupted, until time.Time) time.Duration {
+	disrupted = disrupted.Truncate(time.Second)
+	var after []time.Time
+	for _, t := range provisioned {
+		if !t.Before(disrupted) {
+			after = append(after, t)
+		}
+	}
+	sort.Slice(after, func(i, j int) bool { return after[i].Before(after[j]) })
+	stall := time.Duration(0)
+	last := disrupted
+	for _, t := range after {
+		if gap := t.Sub(last); gap > stall {
+			stall = gap
+		}
+		last = t
+	}
+	if len(after) == 0 {
+		stall = until.Sub(disrupted)
+	}
+	return stall
+}
@@ -0,0 +1,150 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+)
+
+// FaultIOStall is how long IO operation of partition writers may take before it's counted as an error
+var FaultIOStall = 5 * time.Second
+
+// FaultStep is a step of fault plan: fault hook injects fault At after writers started and clears it after Duration
+type FaultStep struct {
+	At       time.Duration
+	Duration time.Duration
+}
+
+// DefaultFaultPlan injects a single fault for a minute once writers have been running for 30 seconds
+var DefaultFaultPlan = []FaultStep{{At: 30 * time.Second, Duration: time.Minute}}
+
+// ParseFaultPlan parses comma separated at:duration steps, ex. 30s:60s,5m:2m. Steps must not overlap
+func ParseFaultPlan(plan string) ([]FaultStep, error) {
+	if strings.TrimSpace(plan) == "" {
+		return nil, nil
+	}
+	var steps []FaultStep
+	for _, field := range strings.Split(plan, ",") {
+		at, duration, ok := strings.Cut(strings.TrimSpace(field), ":")
+		if !ok {
+			return nil, fmt.Errorf("fault step %q isn't in at:duration format", field)
+		}
+		var step FaultStep
+		var err error
+		if step.At, err = time.ParseDuration(at); err != nil {
+			return nil, fmt.Errorf("invalid start of fault step %q: %w", field, err)
+		}
+		if step.Duration, err = time.ParseDuration(duration); err != nil {
+			return nil, fmt.Errorf("invalid duration of fault step %q: %w", field, err)
+		}
+		if step.At < 0 || step.Duration <= 0 {
+			return nil, fmt.Errorf("fault step %q must start at non-negative offset and last a positive duration", field)
+		}
+		steps = append(steps, step)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].At < steps[j].At })
+	for i := 1; i < len(steps); i++ {
+		if steps[i-1].At+steps[i-1].Duration > steps[i].At {
+			return nil, errors.New("fault steps overlap, fault must be cleared before the next one is injected")
+		}
+	}
+	return steps, nil
+}
+
+// faultWriterScript writes a block to the file every second and logs start, end and result of every write,
+// writes which hang in kernel are logged once they return
+func faultWriterScript(file, log string) string {
+	return fmt.Sprintf(`trap 'exit 0' SIGTERM; while true; do s=$(date +%%s.%%N); `+
+		`if timeout -k 1 %[3]d dd if=/dev/urandom of=%[1]s bs=4k count=1 oflag=direct conv=notrunc,fsync 2>/dev/null; `+
+		`then r=ok; else r=err; fi; echo "$s $(date +%%s.%%N) $r" >> %[2]s; sleep 1; done`,
+		file, log, int(FaultIOStall.Seconds())*2)
+}
+
+// ioOp is a single write of partition writer
+type ioOp struct {
+	Start time.Time
+	End   time.Time
+	OK    bool
+}
+
+// failed checks whether write failed or stalled
+func (op ioOp) failed() bool {
+	return !op.OK || op.End.Sub(op.Start) > FaultIOStall
+}
+
+// parseIOLog parses writer log lines of start and end unix timestamps and result, malformed lines are skipped
+func parseIOLog(data string) []ioOp {
+	var ops []ioOp
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		start, err1 := strconv.ParseFloat(fields[0], 64)
+		end, err2 := strconv.ParseFloat(fields[1], 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		ops = append(ops, ioOp{Start: unixTime(start), End: unixTime(end), OK: fields[2] == "ok"})
+	}
+	return ops
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+// fillFaultWindow counts writes of every writer during window and until until, and sets time writers took to
+// complete a write once fault was cleared
+func fillFaultWindow(fw *store.FaultWindow, writers [][]ioOp, until time.Time) {
+	fw.Recovered = true
+	for _, ops := range writers {
+		var recovered *ioOp
+		for i, op := range ops {
+			switch {
+			case op.Start.Before(fw.Start):
+				continue
+			case op.Start.Before(fw.End):
+				fw.Ops++
+				if op.failed() {
+					fw.Errors++
+				}
+			case op.Start.Before(until):
+				fw.OpsAfter++
+				if op.failed() {
+					fw.ErrorsAfter++
+				} else if recovered == nil {
+					recovered = &ops[i]
+				}
+			}
+		}
+		if recovered == nil {
+			fw.Recovered = false
+			continue
+		}
+		if recovery := recovered.End.Sub(fw.End); recovery > fw.Recovery {
+			fw.Recovery = recovery
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/dell/cert-csi/pkg/k8sclient"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/va"
 	"github.com/dell/cert-csi/pkg/observer"
 	"github.com/dell/cert-csi/pkg/testcore"
 
@@ -34,8 +35,10 @@ import (
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // DeletionStruct is used by volume deletion suite
@@ -345,9 +348,14 @@ func (sds *SnapshotDeletionSuite) Run(ctx context.Context, _ string, clients *k8
 	}
 
 	log.Infof("Deleting snapshot with name:%s", color.YellowString(sds.Name))
-	snapClient := clients.SnapClientGA
-	snapObj, _ := snapClient.Interface.Get(ctx, sds.Name, metav1.GetOptions{})
-	err := snapClient.Delete(ctx, snapObj).Sync(ctx).GetError()
+	var err error
+	if clients.SnapClientGA != nil {
+		snapObj, _ := clients.SnapClientGA.Interface.Get(ctx, sds.Name, metav1.GetOptions{})
+		err = clients.SnapClientGA.Delete(ctx, snapObj).Sync(ctx).GetError()
+	} else {
+		snapObj, _ := clients.SnapClientBeta.Interface.Get(ctx, sds.Name, metav1.GetOptions{})
+		err = clients.SnapClientBeta.Delete(ctx, snapObj).Sync(ctx).GetError()
+	}
 	if err != nil {
 		return delFunc, err
 	}
@@ -432,24 +440,7 @@ func (sds *SnapshotDeletionSuite) GetClients(namespace string, client *k8sclient
 	if mcErr != nil {
 		return nil, mcErr
 	}
-	if client.Minor > 17 {
-		snapClient, snErr := client.CreateSnapshotGAClient(namespace)
-		if snErr != nil {
-			return nil, snErr
-		}
-
-		return &k8sclient.Clients{
-			PVCClient:         pvcClient,
-			PodClient:         podClient,
-			VaClient:          vaClient,
-			StatefulSetClient: nil,
-			MetricsClient:     metricsClient,
-			SnapClientGA:      snapClient,
-			SnapClientBeta:    nil,
-		}, nil
-	}
-
-	snapClient, snErr := client.CreateSnapshotBetaClient(namespace)
+	snapGA, snapBeta, snErr := GetSnapshotClient(namespace, client)
 	if snErr != nil {
 		return nil, snErr
 	}
@@ -460,8 +451,8 @@ func (sds *SnapshotDeletionSuite) GetClients(namespace string, client *k8sclient
 		VaClient:          vaClient,
 		StatefulSetClient: nil,
 		MetricsClient:     metricsClient,
-		SnapClientGA:      nil,
-		SnapClientBeta:    snapClient,
+		SnapClientGA:      snapGA,
+		SnapClientBeta:    snapBeta,
 	}, nil
 }
 
@@ -500,6 +491,15 @@ func (ep *EphemeralVolumeSuite) Run(ctx context.Context, _ string, clients *k8sc
 		log.Infof("Using default image: %s", ep.Image)
 	}
 
+	if err := checkEphemeralLifecycleSupport(ctx, clients.KubeClient, ep.Driver); err != nil {
+		return delFunc, err
+	}
+
+	attachedBefore, err := driverAttachments(ctx, clients.VaClient, ep.Driver)
+	if err != nil {
+		return delFunc, err
+	}
+
 	log.Infof("Creating %s pods, each with 1 volumes", color.YellowString(strconv.Itoa(ep.PodNumber)))
 
 	csiVolSrc := v1.CSIVolumeSource{
@@ -559,11 +559,90 @@ func (ep *EphemeralVolumeSuite) Run(ctx context.Context, _ string, clients *k8sc
 			return delFunc, fmt.Errorf("hashes don't match")
 		}
 
+		// mark volume with owner pod name to check isolation after all pods are written
+		owner := fmt.Sprintf("%s/owner", podConf.MountPath)
+		if err := podClient.Exec(ctx, ephPod.Object, []string{"/bin/bash", "-c", "echo " + ephPod.Object.GetName() + " > " + owner}, os.Stdout, os.Stderr, false); err != nil {
+			return delFunc, err
+		}
+	}
+
+	// Each pod must see only its own data
+	for _, ephPod := range ephPods {
+		owner := bytes.NewBufferString("")
+		if err := podClient.Exec(ctx, ephPod.Object, []string{"cat", fmt.Sprintf("%s/owner", podConf.MountPath)}, owner, os.Stderr, false); err != nil {
+			return delFunc, err
+		}
+		if strings.TrimSpace(owner.String()) != ephPod.Object.GetName() {
+			return delFunc, fmt.Errorf("volume of pod %s is not isolated, found data of %s", ephPod.Object.GetName(), strings.TrimSpace(owner.String()))
+		}
+	}
+	log.Info("Ephemeral volumes are isolated per pod")
+
+	// Ephemeral volumes are only published on node, no claims or attachments are expected
+	if clients.PVCClient != nil {
+		pvcList, err := clients.PVCClient.Interface.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		if len(pvcList.Items) != 0 {
+			return delFunc, fmt.Errorf("found %d PVCs in namespace, ephemeral volumes must not create claims", len(pvcList.Items))
+		}
+	}
+	attachedAfter, err := driverAttachments(ctx, clients.VaClient, ep.Driver)
+	if err != nil {
+		return delFunc, err
+	}
+	for name := range attachedAfter {
+		if !attachedBefore[name] {
+			return delFunc, fmt.Errorf("found volume attachment %s, ephemeral volumes must not be attached", name)
+		}
+	}
+
+	log.Info("Deleting pods with ephemeral volumes")
+	for _, ephPod := range ephPods {
+		if del := podClient.Delete(ctx, ephPod.Object).Sync(ctx); del.HasError() {
+			return delFunc, del.GetError()
+		}
 	}
 
 	return delFunc, nil
 }
 
+// checkEphemeralLifecycleSupport checks that CSIDriver object of driver allows Ephemeral volume lifecycle mode
+func checkEphemeralLifecycleSupport(ctx context.Context, kubeClient *k8sclient.KubeClient, driver string) error {
+	if kubeClient == nil {
+		return nil
+	}
+	csiDriver, err := kubeClient.ClientSet.StorageV1().CSIDrivers().Get(ctx, driver, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("can't get CSIDriver %s; error=%v", driver, err)
+	}
+	for _, mode := range csiDriver.Spec.VolumeLifecycleModes {
+		if mode == storagev1.VolumeLifecycleEphemeral {
+			return nil
+		}
+	}
+	return fmt.Errorf("driver %s doesn't support %s volume lifecycle mode", driver, storagev1.VolumeLifecycleEphemeral)
+}
+
+// driverAttachments returns names of volume attachments handled by driver
+func driverAttachments(ctx context.Context, vaClient *va.Client, driver string) (map[string]bool, error) {
+	names := make(map[string]bool)
+	if vaClient == nil {
+		return names, nil
+	}
+	vaList, err := vaClient.Interface.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, attachment := range vaList.Items {
+		if attachment.Spec.Attacher == driver {
+			names[attachment.Name] = true
+		}
+	}
+	return names, nil
+}
+
 // GetObservers returns pod, va, containermetrics observers
 func (*EphemeralVolumeSuite) GetObservers(obsType observer.Type) []observer.Interface {
 	if obsType == observer.EVENT {
@@ -576,13 +655,18 @@ func (*EphemeralVolumeSuite) GetObservers(obsType observer.Type) []observer.Inte
 	return []observer.Interface{}
 }
 
-// GetClients creates and returns pod, va, metrics clients
+// GetClients creates and returns pod, pvc, va, metrics clients
 func (*EphemeralVolumeSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
 	podClient, podErr := client.CreatePodClient(namespace)
 	if podErr != nil {
 		return nil, podErr
 	}
 
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
 	vaClient, vaErr := client.CreateVaClient(namespace)
 	if vaErr != nil {
 		return nil, vaErr
@@ -595,8 +679,10 @@ func (*EphemeralVolumeSuite) GetClients(namespace string, client *k8sclient.Kube
 
 	return &k8sclient.Clients{
 		PodClient:     podClient,
+		PVCClient:     pvcClient,
 		VaClient:      vaClient,
 		MetricsClient: metricsClient,
+		KubeClient:    client,
 	}, nil
 }
 
@@ -854,7 +940,7 @@ func (cts *CapacityTrackingSuite) Run(ctx context.Context, storageClass string,
 	}
 
 	// Create new storage class
-	tempScName := "capacity-tracking-" + k8sclient.RandomSuffix()
+	tempScName := k8sclient.GenerateName("capacity-tracking-")
 	log.Infof("Creating %s storage class", color.YellowString(tempScName))
 	tempScTmpl := clients.SCClient.DuplicateStorageClass(tempScName, sc.Object)
 	err = clients.SCClient.Create(ctx, tempScTmpl)
@@ -892,8 +978,8 @@ func (cts *CapacityTrackingSuite) Run(ctx context.Context, storageClass string,
 		return delFunc, err
 	}
 
-	pvcName := "capacity-tracking-pvc-" + k8sclient.RandomSuffix()
-	podName := "capacity-tracking-pod-" + k8sclient.RandomSuffix()
+	pvcName := k8sclient.GenerateName("capacity-tracking-pvc-")
+	podName := k8sclient.GenerateName("capacity-tracking-pod-")
 	log.Infof("Creating %s pod using %s storage class", color.YellowString(podName), color.YellowString(storageClass))
 
 	pvcConf := testcore.VolumeCreationConfig(storageClass, cts.VolumeSize, pvcName, "ReadWriteOnce")
@@ -1029,3 +1115,191 @@ func (cts *CapacityTrackingSuite) GetClients(namespace string, client *k8sclient
 		CSISCClient:   csiScClient,
 	}, nil
 }
+
+// InvalidSCParamsSuite is used to manage invalid storage class parameters test suite, it clones storage class with
+// every set of invalid parameters and checks that provisioning fails fast with an event and leaves nothing behind
+type InvalidSCParamsSuite struct {
+	ParameterSets []InvalidParameterSet
+	VolumeSize    string
+	// FailTimeout is how long provisioning is given to fail before it's considered hung
+	FailTimeout time.Duration
+}
+
+// Run executes invalid storage class parameters test suite
+func (ips *InvalidSCParamsSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	if len(ips.ParameterSets) == 0 {
+		log.Info("Using default parameter sets")
+		ips.ParameterSets = DefaultInvalidParameterSets
+	}
+	if ips.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		ips.VolumeSize = "3Gi"
+	}
+	if ips.FailTimeout <= 0 {
+		ips.FailTimeout = InvalidParamsTimeout
+	}
+
+	source := clients.SCClient.Get(ctx, storageClass)
+	if source.HasError() {
+		return delFunc, source.GetError()
+	}
+
+	// Storage classes are cluster-scoped, so they don't go away with namespace
+	var created []string
+	delFunc = func() error {
+		for _, name := range created {
+			if err := clients.SCClient.Delete(context.Background(), name); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var results []*invalidParamsResult
+	for i, set := range ips.ParameterSets {
+		name := k8sclient.GenerateName(fmt.Sprintf("%s-ip%d-", storageClass, i))
+		clone := clients.SCClient.DuplicateStorageClass(name, source.Object)
+		clone.Parameters = applyParameters(source.Object.Parameters, set)
+		// Provisioning must start without consumer pod
+		immediate := storagev1.VolumeBindingImmediate
+		clone.VolumeBindingMode = &immediate
+		if err := clients.SCClient.Create(ctx, clone); err != nil {
+			return delFunc, err
+		}
+		created = append(created, name)
+
+		log.Infof("Provisioning volume with parameter set %s", color.CyanString(set.Name))
+		res, err := ips.provisionWith(ctx, name, set, clients)
+		if err != nil {
+			return delFunc, err
+		}
+		if res.passed() {
+			log.Infof("Parameter set %s: %s in %s: %s", set.Name, color.GreenString(res.Outcome), res.Elapsed.Round(time.Second), res.Message)
+		} else {
+			log.Errorf("Parameter set %s: %s %s", color.CyanString(set.Name), color.RedString(res.Outcome), res.Message)
+		}
+		results = append(results, res)
+	}
+
+	return delFunc, failedParameterSets(results)
+}
+
+// provisionWith creates claim of storage class, waits for provisioning to fail and deletes the claim checking
+// that no volume was left behind for it
+func (ips *InvalidSCParamsSuite) provisionWith(ctx context.Context, storageClass string, set InvalidParameterSet, clients *k8sclient.Clients) (*invalidParamsResult, error) {
+	pvcClient := clients.PVCClient
+	res := &invalidParamsResult{Set: set.Name}
+
+	started := time.Now()
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, ips.VolumeSize, "", "")))
+	if claim.HasError() {
+		return nil, claim.GetError()
+	}
+
+	res.Outcome = ParamsHung
+	pollErr := wait.PollUntilContextTimeout(ctx, InvalidParamsPoll, ips.FailTimeout, false, func(context.Context) (bool, error) {
+		if msg := provisioningFailure(ctx, pvcClient, claim.Object.Name); msg != "" {
+			res.Outcome, res.Message, res.Elapsed = ParamsRejected, msg, time.Since(started)
+			return true, nil
+		}
+		current, err := pvcClient.Interface.Get(ctx, claim.Object.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		if current.Status.Phase == v1.ClaimBound {
+			res.Outcome, res.Message = ParamsProvisioned, "bound to "+current.Spec.VolumeName
+			return true, nil
+		}
+		return false, nil
+	})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if pollErr != nil {
+		res.Message = fmt.Sprintf("no ProvisioningFailed event in %s", ips.FailTimeout)
+	}
+
+	if deleted := pvcClient.Delete(ctx, claim.Object).Sync(ctx); deleted.HasError() {
+		res.Leaked = append(res.Leaked, "pvc/"+claim.Object.Name)
+	}
+	// Provisioner may still be retrying when claim is deleted, volume it creates must be deleted right after
+	var leaked []string
+	_ = wait.PollUntilContextTimeout(ctx, InvalidParamsPoll, InvalidParamsLeakWait, true, func(context.Context) (bool, error) {
+		var err error
+		leaked, err = leakedVolumes(ctx, pvcClient.ClientSet, claim.Object.UID)
+		return err == nil && len(leaked) == 0, nil
+	})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	for _, pv := range leaked {
+		res.Leaked = append(res.Leaked, "pv/"+pv)
+	}
+	if len(res.Leaked) != 0 {
+		res.Message = strings.TrimPrefix(res.Message+"; left behind: "+strings.Join(res.Leaked, ", "), "; ")
+	}
+	return res, nil
+}
+
+// GetObservers returns pvc, entitynumber, container and component event observers, failure events of claims
+// end up on their timelines
+func (*InvalidSCParamsSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	if obsType == observer.EVENT {
+		return []observer.Interface{
+			&observer.PvcObserver{},
+			&observer.EntityNumberObserver{},
+			&observer.ContainerMetricsObserver{},
+			&observer.ComponentEventObserver{},
+		}
+	} else if obsType == observer.LIST {
+		return []observer.Interface{
+			&observer.PvcListObserver{},
+			&observer.EntityNumberObserver{},
+			&observer.ContainerMetricsObserver{},
+		}
+	}
+	return []observer.Interface{}
+}
+
+// GetClients returns pvc, metrics and storage class clients
+func (*InvalidSCParamsSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	scClient, scErr := client.CreateSCClient()
+	if scErr != nil {
+		return nil, scErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:     pvcClient,
+		MetricsClient: metricsClient,
+		SCClient:      scClient,
+	}, nil
+}
+
+// GetNamespace returns invalid storage class parameters suite namespace
+func (*InvalidSCParamsSuite) GetNamespace() string {
+	return "invalid-params-test"
+}
+
+// GetName returns invalid storage class parameters suite name
+func (*InvalidSCParamsSuite) GetName() string {
+	return "InvalidSCParamsSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (ips *InvalidSCParamsSuite) Parameters() string {
+	names := make([]string, 0, len(ips.ParameterSets))
+	for _, set := range ips.ParameterSets {
+		names = append(names, set.Name)
+	}
+	return fmt.Sprintf("{parameterSets: [%s], size: %s, failTimeout: %s}", strings.Join(names, ", "), ips.VolumeSize, ips.FailTimeout)
+}
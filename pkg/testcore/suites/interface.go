@@ -21,6 +21,7 @@ import (
 
 	"github.com/dell/cert-csi/pkg/k8sclient"
 	"github.com/dell/cert-csi/pkg/observer"
+	"github.com/dell/cert-csi/pkg/store"
 )
 
 // Interface contains common function specifications
@@ -32,3 +33,99 @@ type Interface interface {
 	GetNamespace() string
 	Parameters() string
 }
+
+// Paced is implemented by suites which can create volumes at configured rate
+type Paced interface {
+	// GetRate returns number of volumes per second suite creates, zero if creation isn't paced
+	GetRate() float64
+}
+
+// Comparer is implemented by suites which measure the same metrics in two configurations
+type Comparer interface {
+	// GetComparisons returns metrics compared during the last run, test case id is set by runner
+	GetComparisons() []*store.Comparison
+}
+
+// Ramped is implemented by suites which can run in stages of increasing concurrency
+type Ramped interface {
+	// GetRampStages returns stages of the last run, empty if suite didn't ramp, test case id is set by runner
+	GetRampStages() []*store.RampStage
+}
+
+// MountValidator is implemented by suites which can validate how volumes are mounted on nodes
+type MountValidator interface {
+	// GetMountChecks returns results of mount validation of the last run, test case id is set by runner
+	GetMountChecks() []*store.MountCheck
+}
+
+// VolumeStatsValidator is implemented by suites which validate volume usage reported by kubelet
+type VolumeStatsValidator interface {
+	// GetVolumeStats returns volume usage sampled during the last run, test case id is set by runner
+	GetVolumeStats() []*store.VolumeStat
+}
+
+// MountOptionTester is implemented by suites which mount volumes with different sets of mount options
+type MountOptionTester interface {
+	// GetMountOptionResults returns outcome of every option set of the last run, test case id is set by runner
+	GetMountOptionResults() []*store.MountOptionResult
+}
+
+// CapacityFiller is implemented by suites which exhaust backend pool
+type CapacityFiller interface {
+	// GetCapacityFillResults returns outcome of pool exhaustion of the last run, test case id is set by runner
+	GetCapacityFillResults() []*store.CapacityFillResult
+}
+
+// MountRecoveryTester is implemented by suites which verify pods recover from attach and mount failures
+type MountRecoveryTester interface {
+	// GetMountRecoveryResults returns how every pod of the last run recovered, test case id is set by runner
+	GetMountRecoveryResults() []*store.MountRecoveryResult
+}
+
+// ConcurrentWriteValidator is implemented by suites which validate files appended to by writers on different nodes
+type ConcurrentWriteValidator interface {
+	// GetConcurrentWriteResults returns what validator found in every file of the last run, test case id is set by runner
+	GetConcurrentWriteResults() []*store.ConcurrentWriteResult
+}
+
+// ControllerFailoverTester is implemented by suites which disrupt driver controller during provisioning
+type ControllerFailoverTester interface {
+	// GetControllerFailoverResults returns how controller recovered from disruptions of the last run, test case id is set by runner
+	GetControllerFailoverResults() []*store.ControllerFailoverResult
+}
+
+// DetachProber is implemented by suites which probe nodes volumes were detached from for stale mounts and devices
+type DetachProber interface {
+	// GetDetachProbeResults returns what was left of volumes on old nodes in the last run, test case id is set by runner
+	GetDetachProbeResults() []*store.DetachProbeResult
+}
+
+// FaultInjector is implemented by suites which inject faults with fault hook while IO is running
+type FaultInjector interface {
+	// GetFaultWindows returns faults injected in the last run with IO they disrupted, test case id is set by runner
+	GetFaultWindows() []*store.FaultWindow
+}
+
+// ExpansionValidator is implemented by suites which classify how volume expansions ended
+type ExpansionValidator interface {
+	// GetExpansionOutcomes returns outcomes of expansions of the last run, test case id is set by runner
+	GetExpansionOutcomes() []*store.ExpansionOutcome
+}
+
+// Sampler is implemented by suites which measure latencies not covered by entity events
+type Sampler interface {
+	// GetLatencySamples returns latencies measured during the last run, test case id is set by runner
+	GetLatencySamples() []*store.LatencySample
+}
+
+// SnapshotClassUser is implemented by suites which take snapshots with volume snapshot class
+type SnapshotClassUser interface {
+	// GetSnapClass returns name of volume snapshot class suite uses
+	GetSnapClass() string
+}
+
+// Tagger is implemented by suites which mark their objects with events of custom types registered in store
+type Tagger interface {
+	// GetTags returns tags of the last run, they are saved once observers stopped
+	GetTags() []*observer.Tag
+}
@@ -0,0 +1,162 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// InvalidParamsTimeout is how long provisioning with invalid parameters is given to fail by default
+var InvalidParamsTimeout = 2 * time.Minute
+
+// InvalidParamsLeakWait is how long volumes provisioned for deleted claim are given to go away
+var InvalidParamsLeakWait = 30 * time.Second
+
+// InvalidParamsPoll is how often claim with invalid parameters is checked
+var InvalidParamsPoll = 2 * time.Second
+
+// Outcomes of provisioning with invalid parameters
+const (
+	// ParamsRejected means provisioning failed with an event in time
+	ParamsRejected = "Rejected"
+	// ParamsProvisioned means volume was provisioned despite invalid parameters
+	ParamsProvisioned = "Provisioned"
+	// ParamsHung means provisioning neither failed nor succeeded in time
+	ParamsHung = "Hung"
+)
+
+// invalidParamsResult is an outcome of provisioning volume with a set of invalid parameters
+type invalidParamsResult struct {
+	Set     string
+	Outcome string
+	Message string
+	// Elapsed is time from claim creation to the first provisioning failure
+	Elapsed time.Duration
+	// Leaked are persistent volumes or claims left behind once claim was deleted
+	Leaked []string
+}
+
+// passed checks that provisioning failed fast and nothing was left behind
+func (res *invalidParamsResult) passed() bool {
+	return res.Outcome == ParamsRejected && len(res.Leaked) == 0
+}
+
+// InvalidParameterSet is a set of parameters storage class is cloned with, provisioning with them must fail
+type InvalidParameterSet struct {
+	Name string `json:"name"`
+	// Parameters replace parameters of storage class with the same keys, empty value removes parameter
+	Parameters map[string]string `json:"parameters"`
+}
+
+// invalidParamsConfig is a format of invalid parameters config
+type invalidParamsConfig struct {
+	ParameterSets []InvalidParameterSet `json:"parameterSets"`
+}
+
+// DefaultInvalidParameterSets are used when no config is given, they are rejected by external provisioner
+// before driver is called, so they are valid for any driver
+var DefaultInvalidParameterSets = []InvalidParameterSet{
+	{Name: "unknown-reserved-key", Parameters: map[string]string{"csi.storage.k8s.io/cert-csi-invalid": "true"}},
+	{Name: "missing-provisioner-secret", Parameters: map[string]string{
+		"csi.storage.k8s.io/provisioner-secret-name":      "cert-csi-missing-secret",
+		"csi.storage.k8s.io/provisioner-secret-namespace": "cert-csi-missing-secret",
+	}},
+}
+
+// LoadInvalidParameterSets reads parameter sets of invalid parameters suite from yaml file
+func LoadInvalidParameterSets(path string) ([]InvalidParameterSet, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("can't read invalid parameters config: %v", err)
+	}
+	config := &invalidParamsConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("can't parse invalid parameters config: %v", err)
+	}
+	if len(config.ParameterSets) == 0 {
+		return nil, errors.New("invalid parameters config has no parameter sets")
+	}
+	names := make(map[string]bool)
+	for _, set := range config.ParameterSets {
+		if set.Name == "" {
+			return nil, errors.New("every parameter set must have a name")
+		}
+		if len(set.Parameters) == 0 {
+			return nil, fmt.Errorf("parameter set %s has no parameters", set.Name)
+		}
+		if names[set.Name] {
+			return nil, fmt.Errorf("parameter set %s is defined more than once", set.Name)
+		}
+		names[set.Name] = true
+	}
+	return config.ParameterSets, nil
+}
+
+// applyParameters returns parameters of storage class with parameters of the set applied
+func applyParameters(params map[string]string, set InvalidParameterSet) map[string]string {
+	applied := make(map[string]string, len(params)+len(set.Parameters))
+	for k, v := range params {
+		applied[k] = v
+	}
+	for k, v := range set.Parameters {
+		if v == "" {
+			delete(applied, k)
+			continue
+		}
+		applied[k] = v
+	}
+	return applied
+}
+
+// leakedVolumes returns names of persistent volumes still provisioned for the claim
+func leakedVolumes(ctx context.Context, clientSet kubernetes.Interface, claimUID types.UID) ([]string, error) {
+	pvList, err := clientSet.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var leaked []string
+	for _, pv := range pvList.Items {
+		if pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.UID == claimUID {
+			leaked = append(leaked, pv.Name)
+		}
+	}
+	return leaked, nil
+}
+
+// failedParameterSets returns error describing parameter sets which weren't rejected cleanly, nil if all passed
+func failedParameterSets(results []*invalidParamsResult) error {
+	var failed []string
+	for _, res := range results {
+		if !res.passed() {
+			failed = append(failed, fmt.Sprintf("%s: %s", res.Set, res.Outcome))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("parameter sets weren't rejected cleanly: %s", strings.Join(failed, ", "))
+}
@@ -0,0 +1,111 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// IPv6ConnectTimeout is how long probe waits for connection to controller before giving up
+var IPv6ConnectTimeout = 5 * time.Second
+
+// ipv6Endpoint is an IPv6 address and TCP port controller pod listens on
+type ipv6Endpoint struct {
+	pod  string
+	addr string
+	port int32
+}
+
+func (e ipv6Endpoint) String() string {
+	return e.pod + "/" + net.JoinHostPort(e.addr, strconv.Itoa(int(e.port)))
+}
+
+// ipv6Addresses returns IPv6 addresses of pod, single-stack clusters may only fill the legacy PodIP
+func ipv6Addresses(p *v1.Pod) []string {
+	var addrs []string
+	for _, ip := range p.Status.PodIPs {
+		if k8sclient.IPFamilyOf(ip.IP) == v1.IPv6Protocol {
+			addrs = append(addrs, ip.IP)
+		}
+	}
+	if len(addrs) == 0 && k8sclient.IPFamilyOf(p.Status.PodIP) == v1.IPv6Protocol {
+		addrs = append(addrs, p.Status.PodIP)
+	}
+	return addrs
+}
+
+// driverPods returns running controller and node plugin pods of driver namespace, node plugins are ones
+// owned by a DaemonSet
+func driverPods(ctx context.Context, clientSet kubernetes.Interface, namespace string) (controllers, nodePlugins []*v1.Pod, err error) {
+	list, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.Status.Phase != v1.PodRunning {
+			continue
+		}
+		owner := metav1.GetControllerOf(p)
+		if owner != nil && owner.Kind == "DaemonSet" {
+			nodePlugins = append(nodePlugins, p)
+		} else {
+			controllers = append(controllers, p)
+		}
+	}
+	return controllers, nodePlugins, nil
+}
+
+// controllerEndpoints returns IPv6 endpoints of TCP ports declared by containers of controller pod
+func controllerEndpoints(p *v1.Pod) []ipv6Endpoint {
+	var endpoints []ipv6Endpoint
+	for _, addr := range ipv6Addresses(p) {
+		for _, c := range p.Spec.Containers {
+			for _, port := range c.Ports {
+				if port.Protocol == "" || port.Protocol == v1.ProtocolTCP {
+					endpoints = append(endpoints, ipv6Endpoint{pod: p.Name, addr: addr, port: port.ContainerPort})
+				}
+			}
+		}
+	}
+	return endpoints
+}
+
+// connectCommand opens TCP connection to endpoint with bash, so test image needs no probe tools
+func connectCommand(e ipv6Endpoint) []string {
+	return []string{
+		"timeout", strconv.Itoa(int(IPv6ConnectTimeout.Seconds())),
+		"bash", "-c", fmt.Sprintf("exec 3<>/dev/tcp/%s/%d", e.addr, e.port),
+	}
+}
+
+// ipv6ProbePod makes probe pod share network of node plugin pod, it's placed on the same node with the same
+// tolerations and host network setting
+func ipv6ProbePod(probe *v1.Pod, nodePlugin *v1.Pod) {
+	pinToNode(probe, nodePlugin.Spec.NodeName)
+	probe.Spec.Tolerations = nodePlugin.Spec.Tolerations
+	probe.Spec.HostNetwork = nodePlugin.Spec.HostNetwork
+}
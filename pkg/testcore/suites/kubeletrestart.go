@@ -0,0 +1,128 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/node"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// KubeletRestartNodeEnv is environment variable restart hook gets name of the node in
+	KubeletRestartNodeEnv = "CERT_CSI_NODE"
+	// kubeletRestartFile is a file every pod writes its name to before kubelet restart
+	kubeletRestartFile = "kubelet-restart.data"
+)
+
+var (
+	// KubeletRestartPoll is an interval between kubelet health checks while it restarts
+	KubeletRestartPoll = time.Second
+	// KubeletRestartTimeout is how long kubelet is given to come back after restart
+	KubeletRestartTimeout = 5 * time.Minute
+)
+
+// restartKubelet restarts kubelet of the node with the hook, or with systemctl in host namespaces of privileged
+// diagnostic pod if no hook is given. Restart from pod is detached, so exec isn't cut by kubelet going down
+func restartKubelet(ctx context.Context, podClient *pod.Client, diag *v1.Pod, hook, nodeName string) error {
+	if hook == "" {
+		restart := "nohup nsenter -t 1 -m -u -i -n -p -- bash -c 'sleep 1; systemctl restart kubelet' >/dev/null 2>&1 &"
+		var stderr bytes.Buffer
+		if err := podClient.Exec(ctx, diag, []string{"bash", "-c", restart}, &stderr, &stderr, true); err != nil {
+			return fmt.Errorf("can't restart kubelet of node %s: %w: %s", nodeName, err, stderr.String())
+		}
+		return nil
+	}
+
+	cmdPath, err := filepath.Abs(hook)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	if filepath.Ext(cmdPath) == ".sh" {
+		cmd = exec.CommandContext(ctx, "bash", cmdPath) // #nosec
+	} else {
+		cmd = exec.CommandContext(ctx, cmdPath) // #nosec
+	}
+	cmd.Env = append(os.Environ(), KubeletRestartNodeEnv+"="+nodeName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("kubelet restart hook failed: %w, output=%s", err, out)
+	}
+	return nil
+}
+
+// waitKubeletRestarted polls kubelet of the node until it's back after restart and returns downtime since restart
+// was triggered. Kubelet is back once it's healthy after being seen down, or, if it restarted between polls,
+// once it's healthy and reported node status after the restart
+func waitKubeletRestarted(ctx context.Context, nodeClient *node.Client, nodeName string, triggered time.Time) (time.Duration, error) {
+	down := false
+	pollErr := wait.PollUntilContextTimeout(ctx, KubeletRestartPoll, KubeletRestartTimeout, false, func(context.Context) (bool, error) {
+		if err := nodeClient.KubeletHealthy(ctx, nodeName); err != nil {
+			down = true
+			return false, nil
+		}
+		if down {
+			return true, nil
+		}
+		n, err := nodeClient.Interface.Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		for _, c := range n.Status.Conditions {
+			if c.Type == v1.NodeReady {
+				return c.Status == v1.ConditionTrue && c.LastHeartbeatTime.After(triggered), nil
+			}
+		}
+		return false, nil
+	})
+	if pollErr != nil {
+		return 0, fmt.Errorf("kubelet of node %s isn't back after %s: %w", nodeName, KubeletRestartTimeout, pollErr)
+	}
+	return time.Since(triggered), nil
+}
+
+// countMounts returns number of mounts of kubelet path, stacked mounts of the same path mean node publish
+// isn't idempotent
+func countMounts(entries []mountEntry, suffix string) int {
+	n := 0
+	for _, e := range entries {
+		if strings.HasSuffix(e.MountPoint, suffix) {
+			n++
+		}
+	}
+	return n
+}
+
+// podRestarts returns restarts of all containers of the pod
+func podRestarts(p *v1.Pod) int32 {
+	var restarts int32
+	for _, cs := range p.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return restarts
+}
@@ -0,0 +1,216 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+	"github.com/fatih/color"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mountEntry is a line of /proc/<pid>/mountinfo
+type mountEntry struct {
+	MountPoint  string
+	Options     []string
+	Propagation string
+}
+
+// parseMountInfo parses mount table in /proc/<pid>/mountinfo format, both per mount and superblock options are kept
+func parseMountInfo(data string) []mountEntry {
+	var entries []mountEntry
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		entry := mountEntry{
+			MountPoint:  fields[4],
+			Options:     strings.Split(fields[5], ","),
+			Propagation: "private",
+		}
+		i := 6
+		for ; i < len(fields) && fields[i] != "-"; i++ {
+			switch {
+			case strings.HasPrefix(fields[i], "shared:"):
+				entry.Propagation = "shared"
+			case strings.HasPrefix(fields[i], "master:") && entry.Propagation == "private":
+				entry.Propagation = "slave"
+			case fields[i] == "unbindable":
+				entry.Propagation = "unbindable"
+			}
+		}
+		// Separator is followed by filesystem type, source and superblock options
+		if i+3 < len(fields) {
+			entry.Options = append(entry.Options, strings.Split(fields[i+3], ",")...)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// publishPathSuffix is a part of kubelet publish path of CSI volume which doesn't depend on kubelet root dir
+func publishPathSuffix(podUID, pvName string) string {
+	return fmt.Sprintf("/pods/%s/volumes/kubernetes.io~csi/%s/mount", podUID, pvName)
+}
+
+// checkMount validates mount of the volume against expected mount options, check is invalid if it isn't mounted at all
+func checkMount(entries []mountEntry, mc *store.MountCheck, suffix string, expectedOptions []string) {
+	var entry *mountEntry
+	for i := range entries {
+		if strings.HasSuffix(entries[i].MountPoint, suffix) {
+			entry = &entries[i]
+		}
+	}
+	if entry == nil {
+		mc.Message = "publish path isn't mounted on node"
+		return
+	}
+	mc.Path = entry.MountPoint
+	mc.Options = strings.Join(entry.Options, ",")
+	mc.Propagation = entry.Propagation
+
+	var missing []string
+	for _, expected := range expectedOptions {
+		if !containsMountOption(entry.Options, expected) {
+			missing = append(missing, expected)
+		}
+	}
+	var problems []string
+	if len(missing) != 0 {
+		problems = append(problems, "missing mount options: "+strings.Join(missing, ","))
+	}
+	if entry.Propagation == "private" || entry.Propagation == "unbindable" {
+		problems = append(problems, fmt.Sprintf("mount propagation is %s, kubelet dir must be shared", entry.Propagation))
+	}
+	mc.Valid = len(problems) == 0
+	mc.Message = strings.Join(problems, "; ")
+}
+
+// containsMountOption checks option presence, options which have defaults, like nfsvers=4 reported as vers=4,
+// are matched by value too
+func containsMountOption(options []string, expected string) bool {
+	for _, o := range options {
+		if o == expected {
+			return true
+		}
+	}
+	key, value, found := strings.Cut(expected, "=")
+	if !found {
+		return false
+	}
+	for _, o := range options {
+		k, v, ok := strings.Cut(o, "=")
+		if ok && v == value && (strings.HasSuffix(key, k) || strings.HasSuffix(k, key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateMounts reads host mount table of every node running the pods via privileged diagnostic pod,
+// and checks that kubelet publish path of each filesystem volume has mount options of the storage class
+func validateMounts(ctx context.Context, clients *k8sclient.Clients, pods []*v1.Pod, mountOptions []string, image string) ([]*store.MountCheck, error) {
+	log := utils.GetLoggerFromContext(ctx)
+	podClient := clients.PodClient
+	mountTables := make(map[string][]mountEntry)
+	var checks []*store.MountCheck
+
+	for _, p := range pods {
+		actual, err := podClient.Interface.Get(ctx, p.Name, metav1.GetOptions{})
+		if err != nil {
+			return checks, err
+		}
+		node := actual.Spec.NodeName
+		if _, ok := mountTables[node]; !ok {
+			table, err := readMountTable(ctx, clients, node, image)
+			if err != nil {
+				return checks, fmt.Errorf("can't read mount table of node %s: %w", node, err)
+			}
+			mountTables[node] = table
+		}
+
+		for _, volume := range actual.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			claim := clients.PVCClient.Get(ctx, volume.PersistentVolumeClaim.ClaimName)
+			if claim.HasError() {
+				return checks, claim.GetError()
+			}
+			if claim.Object.Spec.VolumeMode != nil && *claim.Object.Spec.VolumeMode == v1.PersistentVolumeBlock {
+				continue
+			}
+			mc := &store.MountCheck{Pod: actual.Name, PVC: claim.Object.Name, Node: node}
+			checkMount(mountTables[node], mc, publishPathSuffix(string(actual.UID), claim.Object.Spec.VolumeName), mountOptions)
+			if mc.Valid {
+				log.Debugf("Volume %s of pod %s is mounted with %s", mc.PVC, mc.Pod, mc.Options)
+			} else {
+				log.Errorf("Volume %s of pod %s is mounted incorrectly: %s", color.CyanString(mc.PVC), color.CyanString(mc.Pod), mc.Message)
+			}
+			checks = append(checks, mc)
+		}
+	}
+	return checks, nil
+}
+
+// readMountTable runs diagnostic pod on the node and reads mount table of host init process
+func readMountTable(ctx context.Context, clients *k8sclient.Clients, node, image string) ([]mountEntry, error) {
+	podClient := clients.PodClient
+	diag := podClient.Create(ctx, podClient.MakeDiagnosticPod(node, image))
+	if diag.HasError() {
+		return nil, diag.GetError()
+	}
+	defer func() {
+		podClient.Delete(context.Background(), diag.Object)
+	}()
+	if err := diag.WaitForRunning(ctx); err != nil {
+		return nil, err
+	}
+	return readDiagMountTable(ctx, podClient, diag.Object)
+}
+
+// readDiagMountTable reads mount table of host init process from running diagnostic pod
+func readDiagMountTable(ctx context.Context, podClient *pod.Client, diag *v1.Pod) ([]mountEntry, error) {
+	var stdout, stderr bytes.Buffer
+	if err := podClient.Exec(ctx, diag, []string{"cat", "/proc/1/mountinfo"}, &stdout, &stderr, true); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return parseMountInfo(stdout.String()), nil
+}
+
+// invalidMounts returns error describing checks which failed, nil if all passed
+func invalidMounts(checks []*store.MountCheck) error {
+	var invalid []string
+	for _, mc := range checks {
+		if !mc.Valid {
+			invalid = append(invalid, fmt.Sprintf("%s/%s: %s", mc.Pod, mc.PVC, mc.Message))
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return fmt.Errorf("volumes are mounted incorrectly: %s", strings.Join(invalid, ", "))
+}
@@ -0,0 +1,142 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	"github.com/dell/cert-csi/pkg/store"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubelet/events"
+	"sigs.k8s.io/yaml"
+)
+
+// MountOptionTimeout is how long pod of an option set is given to start by default
+var MountOptionTimeout = 3 * time.Minute
+
+// MountOptionSet is a set of mount options storage class is cloned with
+type MountOptionSet struct {
+	Name    string   `json:"name"`
+	Options []string `json:"options,omitempty"`
+	// ExpectFailure marks sets driver must refuse to mount with, set passes only if mount fails cleanly
+	ExpectFailure bool `json:"expectFailure,omitempty"`
+}
+
+// mountOptionConfig is a format of mount option matrix config
+type mountOptionConfig struct {
+	OptionSets []MountOptionSet `json:"optionSets"`
+}
+
+// DefaultMountOptionSets are used when no config is given: no options, an option every filesystem supports
+// and an option no filesystem supports
+var DefaultMountOptionSets = []MountOptionSet{
+	{Name: "default"},
+	{Name: "noatime", Options: []string{"noatime"}},
+	{Name: "invalid", Options: []string{"cert-csi-invalid-option"}, ExpectFailure: true},
+}
+
+// LoadMountOptionSets reads option sets of mount option matrix from yaml file
+func LoadMountOptionSets(path string) ([]MountOptionSet, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("can't read mount options config: %v", err)
+	}
+	config := &mountOptionConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("can't parse mount options config: %v", err)
+	}
+	if len(config.OptionSets) == 0 {
+		return nil, errors.New("mount options config has no option sets")
+	}
+	names := make(map[string]bool)
+	for _, set := range config.OptionSets {
+		if set.Name == "" {
+			return nil, errors.New("every option set must have a name")
+		}
+		if names[set.Name] {
+			return nil, fmt.Errorf("option set %s is defined more than once", set.Name)
+		}
+		names[set.Name] = true
+	}
+	return config.OptionSets, nil
+}
+
+// classifyMountOptions sets outcome of option set whose volume was mounted from actual mount options on the node
+func classifyMountOptions(res *store.MountOptionResult, set MountOptionSet, mc *store.MountCheck) {
+	res.Applied = mc.Options
+	if mc.Path == "" {
+		res.Outcome = store.MountOptionsIgnored
+		res.Message = mc.Message
+		return
+	}
+	var missing []string
+	for _, o := range set.Options {
+		if !containsMountOption(strings.Split(mc.Options, ","), o) {
+			missing = append(missing, o)
+		}
+	}
+	if len(missing) != 0 {
+		res.Outcome = store.MountOptionsIgnored
+		res.Message = "options not applied on node: " + strings.Join(missing, ",")
+		return
+	}
+	res.Outcome = store.MountOptionsApplied
+}
+
+// mountFailure returns the latest mount or attach failure kubelet reported for the pod
+func mountFailure(ctx context.Context, podClient *pod.Client, p *v1.Pod) string {
+	eventList, err := podClient.ClientSet.CoreV1().Events(p.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=Pod", p.Name),
+	})
+	if err != nil {
+		return ""
+	}
+	var latest *v1.Event
+	for i, event := range eventList.Items {
+		if event.Reason != events.FailedMountVolume && event.Reason != events.FailedAttachVolume {
+			continue
+		}
+		if latest == nil || !event.LastTimestamp.Before(&latest.LastTimestamp) {
+			latest = &eventList.Items[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// failedMountOptionSets returns error describing option sets which didn't end as expected, nil if all passed
+func failedMountOptionSets(results []*store.MountOptionResult) error {
+	var failed []string
+	for _, res := range results {
+		if !res.Passed {
+			failed = append(failed, fmt.Sprintf("%s: %s", res.Set, res.Outcome))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("option sets didn't end as expected: %s", strings.Join(failed, ", "))
+}
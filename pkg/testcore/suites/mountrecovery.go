@@ -0,0 +1,122 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	"github.com/dell/cert-csi/pkg/store"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubelet/events"
+)
+
+const (
+	// MountFaultEnv is environment variable fault hook gets action in, inject or recover
+	MountFaultEnv = "CERT_CSI_FAULT"
+	// MountFaultInject asks fault hook to make node unable to reach backend, ex. by blocking its portal
+	MountFaultInject = "inject"
+	// MountFaultRecover asks fault hook to undo the injected fault
+	MountFaultRecover = "recover"
+)
+
+var (
+	// MountRecoveryPoll is an interval between readiness checks of pods recovering from mount failures
+	MountRecoveryPoll = 2 * time.Second
+	// MountRecoveryTimeout is how long pods are given to become ready once fault is cleared
+	MountRecoveryTimeout = 10 * time.Minute
+)
+
+// runFaultHook runs fault hook with the action for the node, node name is passed like to kubelet restart hook
+func runFaultHook(ctx context.Context, hook, action, nodeName string) error {
+	cmdPath, err := filepath.Abs(hook)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	if filepath.Ext(cmdPath) == ".sh" {
+		cmd = exec.CommandContext(ctx, "bash", cmdPath) // #nosec
+	} else {
+		cmd = exec.CommandContext(ctx, cmdPath) // #nosec
+	}
+	cmd.Env = append(os.Environ(), MountFaultEnv+"="+action, KubeletRestartNodeEnv+"="+nodeName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fault hook failed to %s fault: %w, output=%s", action, err, out)
+	}
+	return nil
+}
+
+// mountRetries returns number of failed attach and mount attempts kubelet reported for the pod with the first failure
+func mountRetries(ctx context.Context, podClient *pod.Client, p *v1.Pod) (int, string) {
+	eventList, err := podClient.ClientSet.CoreV1().Events(p.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=Pod", p.Name),
+	})
+	if err != nil {
+		return 0, ""
+	}
+	retries := 0
+	var first *v1.Event
+	for i, event := range eventList.Items {
+		if event.Reason != events.FailedMountVolume && event.Reason != events.FailedAttachVolume {
+			continue
+		}
+		// Repeated failures are aggregated into a single event with count of occurrences
+		if event.Count > 1 {
+			retries += int(event.Count)
+		} else {
+			retries++
+		}
+		if first == nil || event.FirstTimestamp.Before(&first.FirstTimestamp) {
+			first = &eventList.Items[i]
+		}
+	}
+	if first == nil {
+		return 0, ""
+	}
+	return retries, first.Message
+}
+
+// waitRecovered waits until pods are ready and sets total and recovery time of their results, results of pods
+// which didn't become ready in time stay not recovered
+func waitRecovered(ctx context.Context, podClient *pod.Client, results map[string]*store.MountRecoveryResult,
+	created map[string]time.Time, cleared time.Time,
+) {
+	_ = commonparams.PollUntil(ctx, MountRecoveryPoll, MountRecoveryTimeout, commonparams.Watch(podClient.Interface, metav1.ListOptions{}), func(context.Context) (bool, error) {
+		pending := 0
+		for name, res := range results {
+			if res.Recovered {
+				continue
+			}
+			p, err := podClient.Interface.Get(ctx, name, metav1.GetOptions{})
+			if err != nil || !pod.IsPodReady(p) {
+				pending++
+				continue
+			}
+			res.Recovered = true
+			res.Recovery = time.Since(cleared)
+			res.Total = time.Since(created[name])
+		}
+		return pending == 0, nil
+	})
+}
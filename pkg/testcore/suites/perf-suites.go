@@ -21,10 +21,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
-	"math/rand"
 	"os"
 	"os/exec"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -37,14 +39,21 @@ import (
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/pvc"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/replicationgroup"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/sc"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/statefulset"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/va"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/velero"
 	"github.com/dell/cert-csi/pkg/k8sclient/resources/volumesnapshot"
 	"github.com/dell/cert-csi/pkg/observer"
+	"github.com/dell/cert-csi/pkg/store"
 	"github.com/dell/cert-csi/pkg/testcore"
 	"github.com/dell/cert-csi/pkg/utils"
 
+	appsv1 "k8s.io/api/apps/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/kubernetes/pkg/kubelet/events"
 
 	snapv1client "github.com/dell/cert-csi/pkg/k8sclient/resources/volumesnapshot/v1"
@@ -75,6 +84,12 @@ type VolumeCreationSuite struct {
 	CustomName   string
 	AccessMode   string
 	RawBlock     bool
+	// Rate is number of volumes created per second, all volumes are created at once if zero
+	Rate float64
+	// Ramp is a list of volume numbers created at once in consecutive stages, VolumeNumber is used if empty
+	Ramp []int
+
+	rampStages []*store.RampStage
 }
 
 // Run executes volume creation test suite
@@ -88,7 +103,8 @@ func (vcs *VolumeCreationSuite) Run(ctx context.Context, storageClass string, cl
 		log.Info("Using default volume size")
 		vcs.VolumeSize = "3Gi"
 	}
-	result := validateCustomName(vcs.CustomName, vcs.VolumeNumber)
+	vcs.rampStages = nil
+	result := validateCustomName(vcs.CustomName, vcs.VolumeNumber) && len(vcs.Ramp) == 0
 	if result {
 		log.Infof("using custom pvc-name:%s", vcs.CustomName)
 	} else {
@@ -112,10 +128,39 @@ func (vcs *VolumeCreationSuite) Run(ctx context.Context, storageClass string, cl
 	}
 	tmpl := pvcClient.MakePVC(vcconf)
 
-	// Making API call to create `VolumeNumber` of PVCS
-	createErr := pvcClient.CreateMultiple(ctx, tmpl, vcs.VolumeNumber, vcs.VolumeSize)
-	if createErr != nil {
-		return delFunc, createErr
+	if len(vcs.Ramp) != 0 {
+		var rampErr error
+		vcs.rampStages, rampErr = runRamp(ctx, vcs.Ramp, func(concurrency int) (int, error) {
+			if err := pvcClient.CreateMultiple(ctx, tmpl, concurrency, vcs.VolumeSize); err != nil {
+				return concurrency, err
+			}
+			if firstConsumer {
+				return concurrency, nil
+			}
+			return concurrency, pvcClient.WaitForAllToBeBound(ctx)
+		}, func() error {
+			return pvcClient.DeleteAll(ctx)
+		})
+		return delFunc, rampErr
+	}
+
+	if vcs.Rate > 0 {
+		log.Infof("Pacing creation at %s volumes per second", color.YellowString(strconv.FormatFloat(vcs.Rate, 'f', -1, 64)))
+		p := newPacer(vcs.Rate)
+		for i := 0; i < vcs.VolumeNumber; i++ {
+			if err := p.Wait(ctx, 1); err != nil {
+				return delFunc, err
+			}
+			if created := pvcClient.Create(ctx, tmpl); created.HasError() {
+				return delFunc, created.GetError()
+			}
+		}
+	} else {
+		// Making API call to create `VolumeNumber` of PVCS
+		createErr := pvcClient.CreateMultiple(ctx, tmpl, vcs.VolumeNumber, vcs.VolumeSize)
+		if createErr != nil {
+			return delFunc, createErr
+		}
 	}
 
 	// Wait until all PVCs will be bound
@@ -198,7 +243,18 @@ func (*VolumeCreationSuite) GetNamespace() string {
 
 // Parameters returns formatted string of parameters
 func (vcs *VolumeCreationSuite) Parameters() string {
-	return fmt.Sprintf("{number: %d, size: %s, raw-block: %s}", vcs.VolumeNumber, vcs.VolumeSize, strconv.FormatBool(vcs.RawBlock))
+	return fmt.Sprintf("{number: %d, size: %s, raw-block: %s%s%s}", vcs.VolumeNumber, vcs.VolumeSize, strconv.FormatBool(vcs.RawBlock),
+		rateParameter(vcs.Rate), rampParameter(vcs.Ramp))
+}
+
+// GetRate returns number of volumes created per second
+func (vcs *VolumeCreationSuite) GetRate() float64 {
+	return vcs.Rate
+}
+
+// GetRampStages returns stages of ramp profile of the last run
+func (vcs *VolumeCreationSuite) GetRampStages() []*store.RampStage {
+	return vcs.rampStages
 }
 
 // ProvisioningSuite is used to manage provisioning test suite
@@ -212,6 +268,12 @@ type ProvisioningSuite struct {
 	VolAccessMode string
 	ROFlag        bool
 	Image         string
+	// Rate is number of volumes created per second, all volumes are created at once if zero
+	Rate float64
+	// ValidateMounts checks mount options and propagation of volumes on nodes once pods are ready
+	ValidateMounts bool
+
+	mountChecks []*store.MountCheck
 }
 
 // Run executes provisioning test suite
@@ -236,13 +298,22 @@ func (ps *ProvisioningSuite) Run(ctx context.Context, storageClass string, clien
 		log.Infof("Using default image: %s", ps.Image)
 	}
 	ps.validateCustomPodName()
+	ps.mountChecks = nil
 
 	log.Infof("Creating %s pods, each with %s volumes", color.YellowString(strconv.Itoa(ps.PodNumber)),
 		color.YellowString(strconv.Itoa(ps.VolumeNumber)))
+	if ps.Rate > 0 {
+		log.Infof("Pacing creation at %s volumes per second", color.YellowString(strconv.FormatFloat(ps.Rate, 'f', -1, 64)))
+	}
+	p := newPacer(ps.Rate)
 
+	var pods []*v1.Pod
 	for i := 0; i < ps.PodNumber; i++ {
 		var pvcNameList []string
 		for j := 0; j < ps.VolumeNumber; j++ {
+			if err := p.Wait(ctx, 1); err != nil {
+				return delFunc, err
+			}
 			// Create PVCs
 			var volumeName string
 			if ps.PodCustomName != "" {
@@ -278,6 +349,7 @@ func (ps *ProvisioningSuite) Run(ctx context.Context, storageClass string, clien
 		if pod.HasError() {
 			return delFunc, pod.GetError()
 		}
+		pods = append(pods, pod.Object)
 	}
 
 	readyErr := podClient.WaitForAllToBeReady(ctx)
@@ -285,6 +357,20 @@ func (ps *ProvisioningSuite) Run(ctx context.Context, storageClass string, clien
 		return delFunc, readyErr
 	}
 
+	if ps.ValidateMounts && !ps.RawBlock {
+		log.Info("Validating mounts of volumes on nodes")
+		scObject := clients.SCClient.Get(ctx, storageClass)
+		if scObject.HasError() {
+			return delFunc, scObject.GetError()
+		}
+		checks, err := validateMounts(ctx, clients, pods, scObject.Object.MountOptions, ps.Image)
+		ps.mountChecks = checks
+		if err != nil {
+			return delFunc, err
+		}
+		return delFunc, invalidMounts(checks)
+	}
+
 	return delFunc, nil
 }
 
@@ -293,7 +379,7 @@ func (*ProvisioningSuite) GetObservers(obsType observer.Type) []observer.Interfa
 	return getAllObservers(obsType)
 }
 
-// GetClients returns pvc, pod, va, metrics clients
+// GetClients returns pvc, pod, va, metrics and sc clients
 func (*ProvisioningSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
 	pvcClient, pvcErr := client.CreatePVCClient(namespace)
 	if pvcErr != nil {
@@ -315,12 +401,18 @@ func (*ProvisioningSuite) GetClients(namespace string, client *k8sclient.KubeCli
 		return nil, mcErr
 	}
 
+	scClient, scErr := client.CreateSCClient()
+	if scErr != nil {
+		return nil, scErr
+	}
+
 	return &k8sclient.Clients{
 		PVCClient:         pvcClient,
 		PodClient:         podClient,
 		VaClient:          vaClient,
 		StatefulSetClient: nil,
 		MetricsClient:     metricsClient,
+		SCClient:          scClient,
 	}, nil
 }
 
@@ -339,7 +431,17 @@ func (ps *ProvisioningSuite) GetName() string {
 
 // Parameters returns formatted string of parameters
 func (ps *ProvisioningSuite) Parameters() string {
-	return fmt.Sprintf("{pods: %d, volumes: %d, volumeSize: %s}", ps.PodNumber, ps.VolumeNumber, ps.VolumeSize)
+	return fmt.Sprintf("{pods: %d, volumes: %d, volumeSize: %s%s}", ps.PodNumber, ps.VolumeNumber, ps.VolumeSize, rateParameter(ps.Rate))
+}
+
+// GetRate returns number of volumes created per second
+func (ps *ProvisioningSuite) GetRate() float64 {
+	return ps.Rate
+}
+
+// GetMountChecks returns results of mount validation, empty if mounts weren't validated
+func (ps *ProvisioningSuite) GetMountChecks() []*store.MountCheck {
+	return ps.mountChecks
 }
 
 func (ps *ProvisioningSuite) validateCustomPodName() {
@@ -586,9 +688,13 @@ func (rrps *RemoteReplicationProvisioningSuite) Run(ctx context.Context, storage
 				log.Warnf("error when deleting remote RG: %s", deletedRemoteRG.GetError().Error())
 			}
 
-			log.Info("Sleeping for 1 minute...") // TODO: maybe do some polling instead, idk rn
-			// Sleeping for 1 minutes
-			time.Sleep(1 * time.Minute)
+			log.Info("Waiting for RGs to be deleted")
+			if err := deletedLocalRG.WaitUntilGone(context.Background()); err != nil {
+				log.Warnf("local RG wasn't deleted: %v", err)
+			}
+			if err := deletedRemoteRG.WaitUntilGone(context.Background()); err != nil {
+				log.Warnf("remote RG wasn't deleted: %v", err)
+			}
 
 			return nil
 		}
@@ -880,6 +986,12 @@ type ScalingSuite struct {
 	PodPolicy        string
 	VolumeSize       string
 	Image            string
+	// Rate is number of volumes created per second, replicas are added one by one to keep it if not zero
+	Rate float64
+	// Ramp is a list of replica numbers statefulset is scaled to from zero in consecutive stages, ReplicaNumber is used if empty
+	Ramp []int
+
+	rampStages []*store.RampStage
 }
 
 // Run executes scaling test suite
@@ -922,10 +1034,33 @@ func (ss *ScalingSuite) Run(ctx context.Context, storageClass string, clients *k
 		return delFunc, sts.GetError()
 	}
 
+	ss.rampStages = nil
+	if len(ss.Ramp) != 0 {
+		return delFunc, ss.runRamp(ctx, sts, clients)
+	}
+
 	// Scaling to needed number of replicas
-	sts = stsClient.Scale(ctx, sts.Set, int32(ss.ReplicaNumber)) // #nosec G115
-	if sts.HasError() {
-		return delFunc, sts.GetError()
+	if ss.Rate > 0 {
+		log.Infof("Pacing scale up at %s volumes per second", color.YellowString(strconv.FormatFloat(ss.Rate, 'f', -1, 64)))
+		p := newPacer(ss.Rate)
+		// First replica was created along with statefulset, so the next one waits for its volumes
+		if err := p.Wait(ctx, ss.VolumeNumber); err != nil {
+			return delFunc, err
+		}
+		for i := 2; i <= ss.ReplicaNumber; i++ {
+			if err := p.Wait(ctx, ss.VolumeNumber); err != nil {
+				return delFunc, err
+			}
+			sts = stsClient.Scale(ctx, sts.Set, int32(i)) // #nosec G115
+			if sts.HasError() {
+				return delFunc, sts.GetError()
+			}
+		}
+	} else {
+		sts = stsClient.Scale(ctx, sts.Set, int32(ss.ReplicaNumber)) // #nosec G115
+		if sts.HasError() {
+			return delFunc, sts.GetError()
+		}
 	}
 	sts.Sync(ctx)
 
@@ -950,6 +1085,52 @@ func (ss *ScalingSuite) Run(ctx context.Context, storageClass string, clients *k
 	return delFunc, nil
 }
 
+// runRamp scales statefulset from zero to every number of replicas of ramp profile,
+// volumes are deleted between stages because statefulset would reuse them otherwise
+func (ss *ScalingSuite) runRamp(ctx context.Context, sts *statefulset.StatefulSet, clients *k8sclient.Clients) error {
+	stsClient := clients.StatefulSetClient
+	scaleDown := func() error {
+		sts = stsClient.Scale(ctx, sts.Set, 0)
+		if sts.HasError() {
+			return sts.GetError()
+		}
+		sts = sts.Sync(ctx)
+		if sts.HasError() {
+			return sts.GetError()
+		}
+		return clients.PVCClient.DeleteAll(ctx)
+	}
+
+	// First replica was created along with statefulset, so the first stage has to start from zero as well
+	if err := scaleDown(); err != nil {
+		return err
+	}
+	var err error
+	ss.rampStages, err = runRamp(ctx, ss.Ramp, func(replicas int) (int, error) {
+		volumes := replicas * ss.VolumeNumber
+		sts = stsClient.Scale(ctx, sts.Set, int32(replicas)) // #nosec G115
+		if sts.HasError() {
+			return volumes, sts.GetError()
+		}
+		sts = sts.Sync(ctx)
+		return volumes, sts.GetError()
+	}, scaleDown)
+	if err != nil {
+		return err
+	}
+	sts = stsClient.Scale(ctx, sts.Set, 0)
+	if sts.HasError() {
+		return sts.GetError()
+	}
+	sts.Sync(ctx)
+	return nil
+}
+
+// GetRampStages returns stages of ramp profile of the last run
+func (ss *ScalingSuite) GetRampStages() []*store.RampStage {
+	return ss.rampStages
+}
+
 // GetName returns scaling test suite name
 func (ss *ScalingSuite) GetName() string {
 	return "ScalingSuite"
@@ -957,12 +1138,19 @@ func (ss *ScalingSuite) GetName() string {
 
 // Parameters returns formatted string of parameters
 func (ss *ScalingSuite) Parameters() string {
-	return fmt.Sprintf("{replicas: %d, volumes: %d, volumeSize: %s}", ss.ReplicaNumber, ss.VolumeNumber, ss.VolumeSize)
+	return fmt.Sprintf("{replicas: %d, volumes: %d, volumeSize: %s%s%s}", ss.ReplicaNumber, ss.VolumeNumber, ss.VolumeSize,
+		rateParameter(ss.Rate), rampParameter(ss.Ramp))
 }
 
-// GetObservers returns all observers
+// GetRate returns number of volumes created per second
+func (ss *ScalingSuite) GetRate() float64 {
+	return ss.Rate
+}
+
+// GetObservers returns all observers and scheduling observer, so replicas moved by scheduler or descheduler
+// are told apart from slow ones
 func (ss *ScalingSuite) GetObservers(obsType observer.Type) []observer.Interface {
-	return getAllObservers(obsType)
+	return append(getAllObservers(obsType), &observer.SchedulingObserver{})
 }
 
 // GetClients creates and returns pvc, pod, va, statefulset, metrics clients
@@ -1105,35 +1293,4661 @@ func (vis *VolumeIoSuite) Run(ctx context.Context, storageClass string, clients
 					return err
 				}
 
-				log.Debug(ddRes.String())
-				if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", "sha512sum " + file + " > " + sum}, os.Stdout, os.Stderr, false); err != nil {
-					return err
-				}
-				podClient.Delete(ctx, writerPod.Object).Sync(errCtx)
-				if writerPod.HasError() {
-					return writerPod.GetError()
-				}
+				log.Debug(ddRes.String())
+				if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", "sha512sum " + file + " > " + sum}, os.Stdout, os.Stderr, false); err != nil {
+					return err
+				}
+				podClient.Delete(ctx, writerPod.Object).Sync(errCtx)
+				if writerPod.HasError() {
+					return writerPod.GetError()
+				}
+
+				// WAIT FOR VA TO BE DELETED
+				err := vaClient.WaitUntilVaGone(ctx, pvName)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return delFunc, errs.Wait()
+}
+
+// GetObservers returns all observers
+func (*VolumeIoSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics clients
+func (*VolumeIoSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+	}, nil
+}
+
+// GetNamespace returns volume IO test suite namespace
+func (*VolumeIoSuite) GetNamespace() string {
+	return "volumeio-test"
+}
+
+// GetName returns volume IO test suite name
+func (*VolumeIoSuite) GetName() string {
+	return "VolumeIoSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (vis *VolumeIoSuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, volumeSize: %s chains: %d-%d}", vis.VolumeNumber, vis.VolumeSize,
+		vis.ChainNumber, vis.ChainLength)
+}
+
+// FSGroupSuite is used to manage fsGroup and SecurityContext test suite
+type FSGroupSuite struct {
+	VolumeSize string
+	FSGroup    int64
+	RunAsUser  int64
+	FileNumber int
+	Image      string
+}
+
+// fsGroupCase describes pod SecurityContext to validate
+type fsGroupCase struct {
+	name         string
+	runAsNonRoot bool
+	policy       v1.PodFSGroupChangePolicy
+}
+
+// Run executes fsGroup test suite
+func (fgs *FSGroupSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+	vaClient := clients.VaClient
+
+	if fgs.FSGroup <= 0 {
+		log.Info("Using default fsGroup")
+		fgs.FSGroup = 2000
+	}
+
+	if fgs.RunAsUser <= 0 {
+		log.Info("Using default user")
+		fgs.RunAsUser = 1000
+	}
+
+	if fgs.FileNumber <= 0 {
+		log.Info("Using default number of files")
+		fgs.FileNumber = 1000
+	}
+
+	if fgs.Image == "" {
+		fgs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", fgs.Image)
+	}
+
+	pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, fgs.VolumeSize, "", "")))
+	if pvc.HasError() {
+		return delFunc, pvc.GetError()
+	}
+
+	// Populate volume with root owned files, so fsGroup has something to change recursively
+	podconf := testcore.FSGroupPodConfig([]string{pvc.Object.Name}, nil, fgs.Image)
+	populatePod := podClient.Create(ctx, podClient.MakePod(podconf)).Sync(ctx)
+	if populatePod.HasError() {
+		return delFunc, populatePod.GetError()
+	}
+	dir := podconf.MountPath + "0"
+	log.Infof("Populating volume with %s files", color.YellowString(strconv.Itoa(fgs.FileNumber)))
+	populate := fmt.Sprintf("mkdir -p %[1]s/populated && for i in $(seq 1 %[2]d); do echo $i > %[1]s/populated/file-$i; done && chown -R 0:0 %[1]s && chmod -R 755 %[1]s", dir, fgs.FileNumber)
+	if err := podClient.Exec(ctx, populatePod.Object, []string{"/bin/bash", "-c", populate}, os.Stdout, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	gotPvc, err := pvcClient.Interface.Get(ctx, pvc.Object.Name, metav1.GetOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+	pvName := gotPvc.Spec.VolumeName
+
+	if deleted := podClient.Delete(ctx, populatePod.Object).Sync(ctx); deleted.HasError() {
+		return delFunc, deleted.GetError()
+	}
+	if err := vaClient.WaitUntilVaGone(ctx, pvName); err != nil {
+		return delFunc, err
+	}
+
+	cases := []fsGroupCase{
+		{name: "root-always", runAsNonRoot: false, policy: v1.FSGroupChangeAlways},
+		{name: "nonroot-always", runAsNonRoot: true, policy: v1.FSGroupChangeAlways},
+		{name: "nonroot-onrootmismatch", runAsNonRoot: true, policy: v1.FSGroupChangeOnRootMismatch},
+	}
+	for _, fc := range cases {
+		fc := fc
+		securityContext := &v1.PodSecurityContext{
+			FSGroup:             &fgs.FSGroup,
+			FSGroupChangePolicy: &fc.policy,
+		}
+		if fc.runAsNonRoot {
+			securityContext.RunAsNonRoot = &fc.runAsNonRoot
+			securityContext.RunAsUser = &fgs.RunAsUser
+		}
+
+		// Recursive chown happens on mount, so it is accounted in time to get pod running
+		start := time.Now()
+		casePod := podClient.Create(ctx, podClient.MakePod(testcore.FSGroupPodConfig([]string{pvc.Object.Name}, securityContext, fgs.Image))).Sync(ctx)
+		if casePod.HasError() {
+			return delFunc, casePod.GetError()
+		}
+		log.Infof("Pod with %s security context is running in %s", color.CyanString(fc.name), color.HiYellowString(fmt.Sprint(time.Since(start))))
+
+		if err := fgs.validateOwnership(ctx, podClient, casePod.Object, dir, fc); err != nil {
+			return delFunc, fmt.Errorf("%s: %v", fc.name, err)
+		}
+
+		if deleted := podClient.Delete(ctx, casePod.Object).Sync(ctx); deleted.HasError() {
+			return delFunc, deleted.GetError()
+		}
+		if err := vaClient.WaitUntilVaGone(ctx, pvName); err != nil {
+			return delFunc, err
+		}
+	}
+
+	return delFunc, nil
+}
+
+// validateOwnership checks ownership and permissions of volume content from inside the pod
+func (fgs *FSGroupSuite) validateOwnership(ctx context.Context, podClient *pod.Client, p *v1.Pod, dir string, fc fsGroupCase) error {
+	out := bytes.NewBufferString("")
+	check := fmt.Sprintf("id -u; stat -c '%%g %%A' %[1]s; stat -c '%%g' %[1]s/populated/file-%[2]d", dir, fgs.FileNumber)
+	if err := podClient.Exec(ctx, p, []string{"/bin/bash", "-c", check}, out, os.Stderr, false); err != nil {
+		return err
+	}
+	fields := strings.Fields(out.String())
+	if len(fields) != 4 {
+		return fmt.Errorf("unexpected output of ownership check: %q", out.String())
+	}
+	uid, rootGid, rootMode, fileGid := fields[0], fields[1], fields[2], fields[3]
+	fsGroup := strconv.FormatInt(fgs.FSGroup, 10)
+
+	if fc.runAsNonRoot && uid != strconv.FormatInt(fgs.RunAsUser, 10) {
+		return fmt.Errorf("container runs as user %s, expected %d", uid, fgs.RunAsUser)
+	}
+	if rootGid != fsGroup {
+		return fmt.Errorf("volume root is owned by group %s, expected %s", rootGid, fsGroup)
+	}
+	// setgid bit makes new files inherit fsGroup
+	if len(rootMode) < 7 || (rootMode[6] != 's' && rootMode[6] != 'S') {
+		return fmt.Errorf("volume root has mode %s, expected setgid bit", rootMode)
+	}
+	if fileGid != fsGroup {
+		return fmt.Errorf("pre-populated file is owned by group %s, expected %s", fileGid, fsGroup)
+	}
+
+	// new files must be writable and inherit fsGroup
+	written := bytes.NewBufferString("")
+	write := fmt.Sprintf("echo %[2]s > %[1]s/%[2]s && stat -c '%%g' %[1]s/%[2]s", dir, fc.name)
+	if err := podClient.Exec(ctx, p, []string{"/bin/bash", "-c", write}, written, os.Stderr, false); err != nil {
+		return err
+	}
+	if strings.TrimSpace(written.String()) != fsGroup {
+		return fmt.Errorf("new file is owned by group %s, expected %s", strings.TrimSpace(written.String()), fsGroup)
+	}
+	return nil
+}
+
+// GetObservers returns all observers
+func (*FSGroupSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics clients
+func (*FSGroupSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:     pvcClient,
+		PodClient:     podClient,
+		VaClient:      vaClient,
+		MetricsClient: metricsClient,
+	}, nil
+}
+
+// GetNamespace returns fsGroup test suite namespace
+func (*FSGroupSuite) GetNamespace() string {
+	return "fsgroup-test"
+}
+
+// GetName returns fsGroup test suite name
+func (*FSGroupSuite) GetName() string {
+	return "FSGroupSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (fgs *FSGroupSuite) Parameters() string {
+	return fmt.Sprintf("{volumeSize: %s, fsGroup: %d, runAsUser: %d, files: %d}", fgs.VolumeSize, fgs.FSGroup,
+		fgs.RunAsUser, fgs.FileNumber)
+}
+
+// DiskPressureEvictionSuite is used to manage disk pressure eviction test suite
+type DiskPressureEvictionSuite struct {
+	PodNumber             int
+	VolumeSize            string
+	EphemeralStorageLimit string
+	Image                 string
+}
+
+// Run executes disk pressure eviction test suite
+func (des *DiskPressureEvictionSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+	vaClient := clients.VaClient
+
+	if des.PodNumber <= 0 {
+		log.Info("Using default number of pods")
+		des.PodNumber = 1
+	}
+	if des.EphemeralStorageLimit == "" {
+		log.Info("Using default ephemeral storage limit 64Mi")
+		des.EphemeralStorageLimit = "64Mi"
+	}
+	if des.Image == "" {
+		des.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", des.Image)
+	}
+	limit, err := resource.ParseQuantity(des.EphemeralStorageLimit)
+	if err != nil {
+		return delFunc, fmt.Errorf("wrong ephemeral storage limit %s; error=%v", des.EphemeralStorageLimit, err)
+	}
+
+	log.Infof("Creating %s pods with ephemeral storage limited to %s", color.YellowString(strconv.Itoa(des.PodNumber)),
+		color.YellowString(des.EphemeralStorageLimit))
+
+	var pvcNames []string
+	var pods []*pod.Pod
+	for i := 0; i < des.PodNumber; i++ {
+		pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, des.VolumeSize, "", "")))
+		if pvc.HasError() {
+			return delFunc, pvc.GetError()
+		}
+		pvcNames = append(pvcNames, pvc.Object.Name)
+
+		p := podClient.Create(ctx, podClient.MakePod(testcore.EvictionPodConfig([]string{pvc.Object.Name}, des.EphemeralStorageLimit, des.Image)))
+		if p.HasError() {
+			return delFunc, p.GetError()
+		}
+		pods = append(pods, p)
+	}
+
+	if err := podClient.WaitForAllToBeReady(ctx); err != nil {
+		return delFunc, err
+	}
+
+	pvNames := make(map[string]string)
+	for i, p := range pods {
+		gotPvc, err := pvcClient.Interface.Get(ctx, pvcNames[i], metav1.GetOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		pvNames[pvcNames[i]] = gotPvc.Spec.VolumeName
+
+		// Data written to the volume must survive eviction
+		writeData := "echo " + pvcNames[i] + " > /data0/eviction.data && sync"
+		if err := podClient.Exec(ctx, p.Object, []string{"/bin/bash", "-c", writeData}, os.Stdout, os.Stderr, false); err != nil {
+			return delFunc, err
+		}
+	}
+
+	// Exceed container ephemeral storage limit, so kubelet evicts pods
+	fillMiB := limit.Value()/(1024*1024)*2 + 1
+	var evictionTimes, detachTimes, recoveryTimes []time.Duration
+	for i, p := range pods {
+		start := time.Now()
+		log.Infof("Filling ephemeral storage of pod %s", p.Object.Name)
+		fill := fmt.Sprintf("dd if=/dev/zero of=/tmp/fill bs=1M count=%d", fillMiB)
+		// exec is interrupted when pod gets evicted, so its error is expected
+		if err := podClient.Exec(ctx, p.Object, []string{"/bin/bash", "-c", fill}, io.Discard, io.Discard, true); err != nil {
+			log.Debugf("Filling ephemeral storage of pod %s stopped; error=%v", p.Object.Name, err)
+		}
+		if err := p.WaitForEviction(ctx); err != nil {
+			return delFunc, fmt.Errorf("pod %s wasn't evicted; error=%v", p.Object.Name, err)
+		}
+		evictionTimes = append(evictionTimes, time.Since(start))
+
+		detachStart := time.Now()
+		if err := vaClient.WaitUntilVaGone(ctx, pvNames[pvcNames[i]]); err != nil {
+			return delFunc, fmt.Errorf("volume of evicted pod %s wasn't detached; error=%v", p.Object.Name, err)
+		}
+		detachTimes = append(detachTimes, time.Since(detachStart))
+
+		if deleted := podClient.Delete(ctx, p.Object).Sync(ctx); deleted.HasError() {
+			return delFunc, deleted.GetError()
+		}
+
+		recoveryStart := time.Now()
+		podconf := testcore.EvictionPodConfig([]string{pvcNames[i]}, des.EphemeralStorageLimit, des.Image)
+		replacement := podClient.Create(ctx, podClient.MakePod(podconf)).Sync(ctx)
+		if replacement.HasError() {
+			return delFunc, replacement.GetError()
+		}
+		recoveryTimes = append(recoveryTimes, time.Since(recoveryStart))
+
+		out := bytes.NewBufferString("")
+		if err := podClient.Exec(ctx, replacement.Object, []string{"cat", "/data0/eviction.data"}, out, os.Stderr, false); err != nil {
+			return delFunc, err
+		}
+		if strings.TrimSpace(out.String()) != pvcNames[i] {
+			return delFunc, fmt.Errorf("data of volume %s doesn't match after eviction", pvcNames[i])
+		}
+	}
+
+	// No attachments of evicted pods must be left behind
+	vaList, err := vaClient.Interface.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+	attached := make(map[string]int)
+	for _, va := range vaList.Items {
+		if va.Spec.Source.PersistentVolumeName != nil {
+			attached[*va.Spec.Source.PersistentVolumeName]++
+		}
+	}
+	for pvcName, pvName := range pvNames {
+		if attached[pvName] > 1 {
+			return delFunc, fmt.Errorf("found %d volume attachments of volume %s, expected one", attached[pvName], pvcName)
+		}
+	}
+
+	log.Infof("Eviction took %s, detach took %s, recovery took %s on average",
+		color.HiYellowString(fmt.Sprint(averageDuration(evictionTimes))),
+		color.HiYellowString(fmt.Sprint(averageDuration(detachTimes))),
+		color.HiYellowString(fmt.Sprint(averageDuration(recoveryTimes))))
+
+	return delFunc, nil
+}
+
+func averageDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+	return sum / time.Duration(len(durations))
+}
+
+// GetObservers returns all observers
+func (*DiskPressureEvictionSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics clients
+func (*DiskPressureEvictionSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:     pvcClient,
+		PodClient:     podClient,
+		VaClient:      vaClient,
+		MetricsClient: metricsClient,
+	}, nil
+}
+
+// GetNamespace returns disk pressure eviction test suite namespace
+func (*DiskPressureEvictionSuite) GetNamespace() string {
+	return "eviction-test"
+}
+
+// GetName returns disk pressure eviction test suite name
+func (*DiskPressureEvictionSuite) GetName() string {
+	return "DiskPressureEvictionSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (des *DiskPressureEvictionSuite) Parameters() string {
+	return fmt.Sprintf("{pods: %d, volumeSize: %s, ephemeralStorageLimit: %s}", des.PodNumber, des.VolumeSize,
+		des.EphemeralStorageLimit)
+}
+
+// WorkloadChurnSuite is used to manage workload churn test suite
+type WorkloadChurnSuite struct {
+	VolumeNumber int
+	ChurnPercent int
+	Duration     string
+	VolumeSize   string
+	Image        string
+}
+
+type churnSlot struct {
+	pvc *v1.PersistentVolumeClaim
+	pod *v1.Pod
+}
+
+// Run executes workload churn test suite
+func (wcs *WorkloadChurnSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	if wcs.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		wcs.VolumeNumber = 10
+	}
+	if wcs.ChurnPercent <= 0 || wcs.ChurnPercent > 100 {
+		log.Info("Using default churn percent")
+		wcs.ChurnPercent = 10
+	}
+	if wcs.Duration == "" {
+		log.Info("Using default churn duration")
+		wcs.Duration = "10m"
+	}
+	if wcs.VolumeSize == "" {
+		log.Info("Using default volume size")
+		wcs.VolumeSize = "3Gi"
+	}
+	if wcs.Image == "" {
+		wcs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", wcs.Image)
+	}
+	duration, err := time.ParseDuration(wcs.Duration)
+	if err != nil {
+		return delFunc, fmt.Errorf("wrong churn duration %s; error=%v", wcs.Duration, err)
+	}
+
+	createSlot := func() (*churnSlot, error) {
+		vcconf := testcore.VolumeCreationConfig(storageClass, wcs.VolumeSize, "", "")
+		createdPVC := pvcClient.Create(ctx, pvcClient.MakePVC(vcconf))
+		if createdPVC.HasError() {
+			return nil, createdPVC.GetError()
+		}
+		podconf := testcore.ProvisioningPodConfig([]string{createdPVC.Object.Name}, "", wcs.Image)
+		createdPod := podClient.Create(ctx, podClient.MakePod(podconf)).Sync(ctx)
+		if createdPod.HasError() {
+			return nil, createdPod.GetError()
+		}
+		return &churnSlot{createdPVC.Object, createdPod.Object}, nil
+	}
+
+	deleteSlot := func(slot *churnSlot) error {
+		if deleted := podClient.Delete(ctx, slot.pod).Sync(ctx); deleted.HasError() {
+			return deleted.GetError()
+		}
+		if deleted := pvcClient.Delete(ctx, slot.pvc).Sync(ctx); deleted.HasError() {
+			return deleted.GetError()
+		}
+		return nil
+	}
+
+	log.Infof("Creating steady state of %s volumes with pods", color.YellowString(strconv.Itoa(wcs.VolumeNumber)))
+	slots := make([]*churnSlot, wcs.VolumeNumber)
+	for i := range slots {
+		if slots[i], err = createSlot(); err != nil {
+			return delFunc, err
+		}
+	}
+
+	churnNumber := int(math.Ceil(float64(wcs.VolumeNumber*wcs.ChurnPercent) / 100))
+	log.Infof("Recreating %s volumes every minute for %s", color.YellowString(strconv.Itoa(churnNumber)),
+		color.YellowString(duration.String()))
+
+	// Latency of each recreation window shows whether driver degrades over time
+	var windowLatencies []time.Duration
+	deadline := time.Now().Add(duration)
+	for window := 1; time.Now().Before(deadline); window++ {
+		windowStart := time.Now()
+		var latencies []time.Duration
+		for i := 0; i < churnNumber; i++ {
+			n := k8sclient.RandomIntn(len(slots))
+			if err := deleteSlot(slots[n]); err != nil {
+				return delFunc, err
+			}
+			start := time.Now()
+			if slots[n], err = createSlot(); err != nil {
+				return delFunc, err
+			}
+			latencies = append(latencies, time.Since(start))
+		}
+		avg := averageDuration(latencies)
+		windowLatencies = append(windowLatencies, avg)
+		log.Infof("Churn window %d: recreated %d volumes, average latency %s", window, churnNumber,
+			color.HiYellowString(avg.String()))
+
+		select {
+		case <-ctx.Done():
+			return delFunc, ctx.Err()
+		case <-time.After(time.Until(windowStart.Add(time.Minute))):
+		}
+	}
+
+	if len(windowLatencies) > 1 && windowLatencies[0] > 0 {
+		first, last := windowLatencies[0], windowLatencies[len(windowLatencies)-1]
+		drift := float64(last-first) / float64(first) * 100
+		log.Infof("Latency drift over %d windows: %s -> %s (%s)", len(windowLatencies), first, last,
+			color.HiYellowString("%+.1f%%", drift))
+	}
+
+	return delFunc, nil
+}
+
+// GetObservers returns all observers
+func (*WorkloadChurnSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics clients
+func (*WorkloadChurnSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:     pvcClient,
+		PodClient:     podClient,
+		VaClient:      vaClient,
+		MetricsClient: metricsClient,
+	}, nil
+}
+
+// GetNamespace returns workload churn test suite namespace
+func (*WorkloadChurnSuite) GetNamespace() string {
+	return "churn-test"
+}
+
+// GetName returns workload churn test suite name
+func (*WorkloadChurnSuite) GetName() string {
+	return "WorkloadChurnSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (wcs *WorkloadChurnSuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, churnPercent: %d, duration: %s, volumeSize: %s}", wcs.VolumeNumber,
+		wcs.ChurnPercent, wcs.Duration, wcs.VolumeSize)
+}
+
+// ObservabilitySuite is used to manage CSM Observability validation test suite
+type ObservabilitySuite struct {
+	VolumeNumber int
+	VolumeSize   string
+	// Namespace, Service, Port and Scheme locate metrics endpoint of the otel collector
+	Namespace string
+	Service   string
+	Port      string
+	Scheme    string
+	// Timeout is how long to wait for every volume to show up in exported metrics
+	Timeout string
+	Image   string
+}
+
+// ObservabilityPoll is an interval between scrapes of the otel collector
+var ObservabilityPoll = 15 * time.Second
+
+// Run executes CSM Observability validation test suite
+func (obs *ObservabilitySuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if obs.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		obs.VolumeNumber = 3
+	}
+	if obs.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		obs.VolumeSize = "3Gi"
+	}
+	if obs.Image == "" {
+		obs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", obs.Image)
+	}
+	if obs.Namespace == "" {
+		obs.Namespace = "karavi"
+	}
+	if obs.Service == "" {
+		obs.Service = "otel-collector"
+	}
+	if obs.Port == "" {
+		obs.Port = "8443"
+	}
+	if obs.Scheme == "" {
+		obs.Scheme = "https"
+	}
+	if obs.Timeout == "" {
+		obs.Timeout = "10m"
+	}
+	timeout, err := time.ParseDuration(obs.Timeout)
+	if err != nil {
+		return delFunc, fmt.Errorf("can't parse timeout %s: %v", obs.Timeout, err)
+	}
+
+	// Fail fast if observability isn't installed instead of waiting for the whole timeout
+	if _, err := obs.scrape(ctx, clients.KubeClient); err != nil {
+		return delFunc, fmt.Errorf("can't scrape otel collector %s/%s, is CSM Observability installed? error: %v", obs.Namespace, obs.Service, err)
+	}
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	log.Infof("Creating pod with %s volumes", color.YellowString(strconv.Itoa(obs.VolumeNumber)))
+	var pvcNameList []string
+	for i := 0; i < obs.VolumeNumber; i++ {
+		pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, obs.VolumeSize, "", "")))
+		if pvc.HasError() {
+			return delFunc, pvc.GetError()
+		}
+		pvcNameList = append(pvcNameList, pvc.Object.Name)
+	}
+
+	pod := podClient.Create(ctx, podClient.MakePod(testcore.ProvisioningPodConfig(pvcNameList, "", obs.Image)))
+	if pod.HasError() {
+		return delFunc, pod.GetError()
+	}
+	if err := pod.Sync(ctx).GetError(); err != nil {
+		return delFunc, err
+	}
+
+	// Generate some I/O so array performance metrics are exported along with topology ones
+	for i := range pvcNameList {
+		file := fmt.Sprintf("/data%d/observability.data", i)
+		if err := podClient.Exec(ctx, pod.Object, []string{"dd", "if=/dev/urandom", "of=" + file, "bs=1M", "count=16", "oflag=sync"}, io.Discard, io.Discard, true); err != nil {
+			return delFunc, err
+		}
+	}
+
+	volumes := make(map[string]string)
+	for _, name := range pvcNameList {
+		pvc, err := pvcClient.Interface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		volumes[pvc.Spec.VolumeName] = name
+	}
+
+	log.Infof("Waiting up to %s for volumes to show up in metrics of %s", color.YellowString(timeout.String()), color.YellowString(obs.Service))
+	start := time.Now()
+	pollErr := commonparams.PollUntil(ctx, ObservabilityPoll, timeout, nil, func(context.Context) (bool, error) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+		metrics, err := obs.scrape(ctx, clients.KubeClient)
+		if err != nil {
+			log.Warnf("Can't scrape otel collector: %v", err)
+			return false, nil
+		}
+		for pvName, pvcName := range volumes {
+			// Metrics carry volume name as a label value, e.g. PersistentVolumeName="k8s-123"
+			if strings.Contains(metrics, fmt.Sprintf("=%q", pvName)) {
+				log.Infof("Metrics of %s (%s) exported after %s", pvName, pvcName, time.Since(start).Round(time.Second))
+				delete(volumes, pvName)
+			}
+		}
+		return len(volumes) == 0, nil
+	})
+	if pollErr != nil {
+		var missing []string
+		for pvName := range volumes {
+			missing = append(missing, pvName)
+		}
+		return delFunc, fmt.Errorf("volumes %v didn't show up in exported metrics within %s: %v", missing, timeout, pollErr)
+	}
+
+	log.Infof("All %d volumes are reported by CSM Observability", obs.VolumeNumber)
+	return delFunc, nil
+}
+
+// scrape returns metrics exposed by the otel collector, requested through API server service proxy
+// so collector doesn't have to be exposed outside of the cluster
+func (obs *ObservabilitySuite) scrape(ctx context.Context, client *k8sclient.KubeClient) (string, error) {
+	raw, err := client.ClientSet.CoreV1().Services(obs.Namespace).ProxyGet(obs.Scheme, obs.Service, obs.Port, "/metrics", nil).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// GetObservers returns all observers
+func (*ObservabilitySuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and kube clients
+func (*ObservabilitySuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		KubeClient:        client,
+	}, nil
+}
+
+// GetNamespace returns observability suite namespace
+func (*ObservabilitySuite) GetNamespace() string {
+	return "obs-test"
+}
+
+// GetName returns observability suite name
+func (*ObservabilitySuite) GetName() string {
+	return "ObservabilitySuite"
+}
+
+// Parameters returns formatted string of parameters
+func (obs *ObservabilitySuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, size: %s, collector: %s/%s, timeout: %s}", obs.VolumeNumber, obs.VolumeSize, obs.Namespace, obs.Service, obs.Timeout)
+}
+
+// BindingModeComparisonSuite is used to manage binding mode comparison test suite
+type BindingModeComparisonSuite struct {
+	VolumeNumber int
+	VolumeSize   string
+	Image        string
+
+	comparisons []*store.Comparison
+}
+
+// BindingModePoll is an interval between checks of volumes and pods in binding mode comparison
+var BindingModePoll = 250 * time.Millisecond
+
+// bindingModeResult contains latencies measured with a single binding mode
+type bindingModeResult struct {
+	avgBind  time.Duration
+	avgReady time.Duration
+	total    time.Duration
+}
+
+// Run executes binding mode comparison test suite
+func (bmc *BindingModeComparisonSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if bmc.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		bmc.VolumeNumber = 5
+	}
+	if bmc.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		bmc.VolumeSize = "3Gi"
+	}
+	if bmc.Image == "" {
+		bmc.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", bmc.Image)
+	}
+	bmc.comparisons = nil
+
+	source := clients.SCClient.Get(ctx, storageClass)
+	if source.HasError() {
+		return delFunc, source.GetError()
+	}
+
+	// Storage classes are cluster-scoped, so they don't go away with namespace
+	var created []string
+	delFunc = func() error {
+		for _, name := range created {
+			if err := clients.SCClient.Delete(context.Background(), name); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	modes := []storagev1.VolumeBindingMode{storagev1.VolumeBindingImmediate, storagev1.VolumeBindingWaitForFirstConsumer}
+	shortNames := map[storagev1.VolumeBindingMode]string{
+		storagev1.VolumeBindingImmediate:            "immediate",
+		storagev1.VolumeBindingWaitForFirstConsumer: "wffc",
+	}
+	results := make(map[storagev1.VolumeBindingMode]bindingModeResult)
+	for _, mode := range modes {
+		mode := mode
+		name := k8sclient.GenerateName(fmt.Sprintf("%s-%s-", storageClass, shortNames[mode]))
+		clone := clients.SCClient.DuplicateStorageClass(name, source.Object)
+		clone.VolumeBindingMode = &mode
+		if err := clients.SCClient.Create(ctx, clone); err != nil {
+			return delFunc, err
+		}
+		created = append(created, name)
+
+		log.Infof("Provisioning %s volumes with %s binding mode", color.YellowString(strconv.Itoa(bmc.VolumeNumber)), color.CyanString(string(mode)))
+		res, err := bmc.measure(ctx, name, clients)
+		if err != nil {
+			return delFunc, err
+		}
+		log.Infof("%s: avg bind %s, avg pod ready %s, total %s", mode, res.avgBind, res.avgReady, res.total)
+		results[mode] = res
+	}
+
+	baseline, candidate := results[storagev1.VolumeBindingImmediate], results[storagev1.VolumeBindingWaitForFirstConsumer]
+	for _, m := range []struct {
+		metric              string
+		baseline, candidate time.Duration
+	}{
+		{"Avg PVC bind", baseline.avgBind, candidate.avgBind},
+		{"Avg pod ready", baseline.avgReady, candidate.avgReady},
+		{"Total", baseline.total, candidate.total},
+	} {
+		c := &store.Comparison{
+			Metric:         m.metric,
+			Baseline:       string(storagev1.VolumeBindingImmediate),
+			BaselineValue:  m.baseline,
+			Candidate:      string(storagev1.VolumeBindingWaitForFirstConsumer),
+			CandidateValue: m.candidate,
+		}
+		log.Infof("%s overhead of %s: %s", m.metric, c.Candidate, color.YellowString(c.Difference().String()))
+		bmc.comparisons = append(bmc.comparisons, c)
+	}
+
+	return delFunc, nil
+}
+
+// measure provisions volumes of storage class with a pod for each and measures how long it takes for them to become usable
+func (bmc *BindingModeComparisonSuite) measure(ctx context.Context, storageClass string, clients *k8sclient.Clients) (bindingModeResult, error) {
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+	start := time.Now()
+
+	pvcCreated := make(map[string]time.Time)
+	podCreated := make(map[string]time.Time)
+	for i := 0; i < bmc.VolumeNumber; i++ {
+		pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, bmc.VolumeSize, "", "")))
+		if pvc.HasError() {
+			return bindingModeResult{}, pvc.GetError()
+		}
+		pvcCreated[pvc.Object.Name] = time.Now()
+
+		pod := podClient.Create(ctx, podClient.MakePod(testcore.ProvisioningPodConfig([]string{pvc.Object.Name}, "", bmc.Image)))
+		if pod.HasError() {
+			return bindingModeResult{}, pod.GetError()
+		}
+		podCreated[pod.Object.Name] = time.Now()
+	}
+
+	bound := make(map[string]time.Duration)
+	ready := make(map[string]time.Duration)
+	timeout := pvc.Timeout
+	if pvcClient.Timeout != 0 {
+		timeout = time.Duration(pvcClient.Timeout) * time.Second
+	}
+	pollErr := commonparams.PollUntil(ctx, BindingModePoll, timeout, commonparams.Watch(pvcClient.Interface, metav1.ListOptions{}), func(context.Context) (bool, error) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+		pvcList, err := pvcClient.Interface.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, p := range pvcList.Items {
+			created, ok := pvcCreated[p.Name]
+			if _, seen := bound[p.Name]; ok && !seen && p.Status.Phase == v1.ClaimBound {
+				bound[p.Name] = time.Since(created)
+			}
+		}
+		podList, err := podClient.Interface.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for i := range podList.Items {
+			p := &podList.Items[i]
+			created, ok := podCreated[p.Name]
+			if _, seen := ready[p.Name]; ok && !seen && pod.IsPodReady(p) {
+				ready[p.Name] = time.Since(created)
+			}
+		}
+		return len(bound) == bmc.VolumeNumber && len(ready) == bmc.VolumeNumber, nil
+	})
+	if pollErr != nil {
+		return bindingModeResult{}, fmt.Errorf("%d of %d volumes bound and %d of %d pods ready: %v", len(bound), bmc.VolumeNumber, len(ready), bmc.VolumeNumber, pollErr)
+	}
+	res := bindingModeResult{total: time.Since(start)}
+
+	var bindTimes, readyTimes []time.Duration
+	for _, d := range bound {
+		bindTimes = append(bindTimes, d)
+	}
+	for _, d := range ready {
+		readyTimes = append(readyTimes, d)
+	}
+	res.avgBind = averageDuration(bindTimes)
+	res.avgReady = averageDuration(readyTimes)
+
+	// Free backend capacity before the next binding mode
+	if err := podClient.DeleteAll(ctx); err != nil {
+		return res, err
+	}
+	if err := pvcClient.DeleteAll(ctx); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetComparisons returns latencies of WaitForFirstConsumer binding mode compared to Immediate one
+func (bmc *BindingModeComparisonSuite) GetComparisons() []*store.Comparison {
+	return bmc.comparisons
+}
+
+// GetObservers returns all observers
+func (*BindingModeComparisonSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and storage class clients
+func (*BindingModeComparisonSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	scClient, scErr := client.CreateSCClient()
+	if scErr != nil {
+		return nil, scErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		SCClient:          scClient,
+	}, nil
+}
+
+// GetNamespace returns binding mode comparison suite namespace
+func (*BindingModeComparisonSuite) GetNamespace() string {
+	return "bmc-test"
+}
+
+// GetName returns binding mode comparison suite name
+func (*BindingModeComparisonSuite) GetName() string {
+	return "BindingModeComparisonSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (bmc *BindingModeComparisonSuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, size: %s}", bmc.VolumeNumber, bmc.VolumeSize)
+}
+
+// SnapshotScheduleSuite is used to manage snapshot schedule test suite, it emulates backup tools
+// taking snapshots of busy volumes periodically and deleting the ones out of retention
+type SnapshotScheduleSuite struct {
+	VolumeNumber int
+	VolumeSize   string
+	SnapClass    string
+	// Interval is time between snapshot rounds, Duration is how long the schedule runs
+	Interval string
+	Duration string
+	// Retention is number of snapshots kept for every volume
+	Retention int
+	Image     string
+
+	comparisons []*store.Comparison
+}
+
+// scheduleRound contains latencies measured during a single snapshot round
+type scheduleRound struct {
+	creations        []time.Duration
+	deletions        []time.Duration
+	creationFailures int
+	deletionFailures int
+}
+
+// Run executes snapshot schedule test suite
+func (sss *SnapshotScheduleSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	if sss.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		sss.VolumeNumber = 3
+	}
+	if sss.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		sss.VolumeSize = "3Gi"
+	}
+	if sss.Interval == "" {
+		log.Info("Using default snapshot interval")
+		sss.Interval = "5m"
+	}
+	if sss.Duration == "" {
+		log.Info("Using default schedule duration")
+		sss.Duration = "1h"
+	}
+	if sss.Retention <= 0 {
+		log.Info("Using default snapshot retention")
+		sss.Retention = 3
+	}
+	if sss.Image == "" {
+		sss.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", sss.Image)
+	}
+	sss.comparisons = nil
+	interval, err := time.ParseDuration(sss.Interval)
+	if err != nil {
+		return delFunc, fmt.Errorf("wrong snapshot interval %s; error=%v", sss.Interval, err)
+	}
+	duration, err := time.ParseDuration(sss.Duration)
+	if err != nil {
+		return delFunc, fmt.Errorf("wrong schedule duration %s; error=%v", sss.Duration, err)
+	}
+	if clients.SnapClientGA == nil && clients.SnapClientBeta == nil {
+		return delFunc, fmt.Errorf("can't get ga or beta snapshot client")
+	}
+
+	// Pods keep writing to volumes, so snapshots are taken of volumes under load
+	log.Infof("Creating %s busy volumes", color.YellowString(strconv.Itoa(sss.VolumeNumber)))
+	var pvcNames []string
+	for i := 0; i < sss.VolumeNumber; i++ {
+		createdPVC := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, sss.VolumeSize, "", "")))
+		if createdPVC.HasError() {
+			return delFunc, createdPVC.GetError()
+		}
+		podconf := testcore.IoWritePodConfig([]string{createdPVC.Object.Name}, "", sss.Image)
+		podconf.Args = []string{"-c", fmt.Sprintf(" trap 'exit 0' SIGTERM;while true; do dd if=/dev/urandom of=%s0/busy.data bs=1M count=16 oflag=sync 2>/dev/null; sleep 1; done", podconf.MountPath)}
+		if createdPod := podClient.Create(ctx, podClient.MakePod(podconf)).Sync(ctx); createdPod.HasError() {
+			return delFunc, createdPod.GetError()
+		}
+		pvcNames = append(pvcNames, createdPVC.Object.Name)
+	}
+
+	createSnap := func(name, pvcName string) error {
+		var snap volumesnapshot.Interface
+		if clients.SnapClientGA != nil {
+			snap = clients.SnapClientGA.Create(ctx, &snapv1.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: snapv1.VolumeSnapshotSpec{
+					Source:                  snapv1.VolumeSnapshotSource{PersistentVolumeClaimName: &pvcName},
+					VolumeSnapshotClassName: &sss.SnapClass,
+				},
+			})
+		} else {
+			snap = clients.SnapClientBeta.Create(ctx, &snapbeta.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: snapbeta.VolumeSnapshotSpec{
+					Source:                  snapbeta.VolumeSnapshotSource{PersistentVolumeClaimName: &pvcName},
+					VolumeSnapshotClassName: &sss.SnapClass,
+				},
+			})
+		}
+		if snap.HasError() {
+			return snap.GetError()
+		}
+		return snap.WaitForRunning(ctx)
+	}
+	deleteSnap := func(name string) error {
+		if clients.SnapClientGA != nil {
+			snapObj, err := clients.SnapClientGA.Interface.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			return clients.SnapClientGA.Delete(ctx, snapObj).Sync(ctx).GetError()
+		}
+		snapObj, err := clients.SnapClientBeta.Interface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		return clients.SnapClientBeta.Delete(ctx, snapObj).Sync(ctx).GetError()
+	}
+
+	log.Infof("Taking snapshots every %s for %s, keeping %s per volume", color.YellowString(interval.String()),
+		color.YellowString(duration.String()), color.YellowString(strconv.Itoa(sss.Retention)))
+	retained := make(map[string][]string)
+	var rounds []scheduleRound
+	deadline := time.Now().Add(duration)
+	for round := 1; time.Now().Before(deadline); round++ {
+		roundStart := time.Now()
+		var r scheduleRound
+		for _, pvcName := range pvcNames {
+			name := fmt.Sprintf("%s-%d", pvcName, round)
+			start := time.Now()
+			if err := createSnap(name, pvcName); err != nil {
+				// Failed snapshots are part of the result, schedule goes on as backup tools do
+				log.Errorf("Can't take snapshot %s; error=%v", name, err)
+				r.creationFailures++
+				continue
+			}
+			r.creations = append(r.creations, time.Since(start))
+			retained[pvcName] = append(retained[pvcName], name)
+
+			for len(retained[pvcName]) > sss.Retention {
+				oldest := retained[pvcName][0]
+				retained[pvcName] = retained[pvcName][1:]
+				start := time.Now()
+				if err := deleteSnap(oldest); err != nil {
+					log.Errorf("Can't delete snapshot %s; error=%v", oldest, err)
+					r.deletionFailures++
+					continue
+				}
+				r.deletions = append(r.deletions, time.Since(start))
+			}
+		}
+		rounds = append(rounds, r)
+		log.Infof("Snapshot round %d: avg creation %s, avg deletion %s, failures %d", round,
+			color.HiYellowString(averageDuration(r.creations).String()), color.HiYellowString(averageDuration(r.deletions).String()),
+			r.creationFailures+r.deletionFailures)
+
+		select {
+		case <-ctx.Done():
+			return delFunc, ctx.Err()
+		case <-time.After(time.Until(roundStart.Add(interval))):
+		}
+	}
+
+	sss.comparisons = scheduleDrift(rounds)
+	for _, c := range sss.comparisons {
+		log.Infof("%s drift: %s -> %s (%s)", c.Metric, c.BaselineValue, c.CandidateValue, color.HiYellowString("%+.1f%%", c.DifferencePercent()))
+	}
+
+	var created, creationFailures, deleted, deletionFailures int
+	for _, r := range rounds {
+		created += len(r.creations)
+		creationFailures += r.creationFailures
+		deleted += len(r.deletions)
+		deletionFailures += r.deletionFailures
+	}
+	if creationFailures != 0 || deletionFailures != 0 {
+		return delFunc, fmt.Errorf("%d of %d snapshot creations and %d of %d deletions failed", creationFailures,
+			created+creationFailures, deletionFailures, deleted+deletionFailures)
+	}
+	return delFunc, nil
+}
+
+// scheduleDrift compares latencies of the first round against the last one,
+// deletions are compared from the first round which deleted anything
+func scheduleDrift(rounds []scheduleRound) []*store.Comparison {
+	if len(rounds) < 2 {
+		return nil
+	}
+	first, last := rounds[0], rounds[len(rounds)-1]
+	comparisons := []*store.Comparison{{
+		Metric:         "Avg snapshot creation",
+		Baseline:       "First round",
+		BaselineValue:  averageDuration(first.creations),
+		Candidate:      fmt.Sprintf("Round %d", len(rounds)),
+		CandidateValue: averageDuration(last.creations),
+	}}
+	for i, r := range rounds[:len(rounds)-1] {
+		if len(r.deletions) != 0 {
+			comparisons = append(comparisons, &store.Comparison{
+				Metric:         "Avg snapshot deletion",
+				Baseline:       fmt.Sprintf("Round %d", i+1),
+				BaselineValue:  averageDuration(r.deletions),
+				Candidate:      fmt.Sprintf("Round %d", len(rounds)),
+				CandidateValue: averageDuration(last.deletions),
+			})
+			break
+		}
+	}
+	return comparisons
+}
+
+// GetComparisons returns snapshot latencies of the first round compared to the last one
+func (sss *SnapshotScheduleSuite) GetComparisons() []*store.Comparison {
+	return sss.comparisons
+}
+
+// GetObservers returns all observers
+func (*SnapshotScheduleSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients creates and returns pvc, pod, va, metrics, snapshot clients
+func (sss *SnapshotScheduleSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	if ok, err := client.SnapshotClassExists(sss.SnapClass); !ok {
+		return nil, fmt.Errorf("snapshotclass class doesn't exist; error = %v", err)
+	}
+
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	snapGA, snapBeta, snErr := GetSnapshotClient(namespace, client)
+	if snErr != nil {
+		return nil, snErr
+	}
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		SnapClientGA:      snapGA,
+		SnapClientBeta:    snapBeta,
+	}, nil
+}
+
+// GetNamespace returns snapshot schedule test suite namespace
+func (*SnapshotScheduleSuite) GetNamespace() string {
+	return "snap-schedule-test"
+}
+
+// GetName returns snapshot schedule test suite name
+func (*SnapshotScheduleSuite) GetName() string {
+	return "SnapshotScheduleSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (sss *SnapshotScheduleSuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, interval: %s, duration: %s, retention: %d, volumeSize: %s}", sss.VolumeNumber,
+		sss.Interval, sss.Duration, sss.Retention, sss.VolumeSize)
+}
+
+// GetSnapClass returns volume snapshot class suite takes snapshots with
+func (sss *SnapshotScheduleSuite) GetSnapClass() string {
+	return sss.SnapClass
+}
+
+// AttachPingPongSuite is used to manage attach ping-pong test suite, it moves a pod with RWO volume
+// between two nodes back and forth to reproduce attacher races
+type AttachPingPongSuite struct {
+	Moves      int
+	VolumeSize string
+	Image      string
+	// MaxSlowdown is the highest allowed ratio of average latencies of the last and the first third of moves, not checked if zero
+	MaxSlowdown float64
+
+	comparisons []*store.Comparison
+}
+
+// PingPongPoll is an interval between checks of volume attachments in attach ping-pong suite
+var PingPongPoll = 250 * time.Millisecond
+
+// pingPongMove contains latencies of moving volume to another node
+type pingPongMove struct {
+	detach time.Duration
+	attach time.Duration
+	ready  time.Duration
+}
+
+// Run executes attach ping-pong test suite
+func (pps *AttachPingPongSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if pps.Moves <= 0 {
+		log.Info("Using default number of moves")
+		pps.Moves = 10
+	}
+	if pps.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		pps.VolumeSize = "3Gi"
+	}
+	if pps.Image == "" {
+		pps.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", pps.Image)
+	}
+	pps.comparisons = nil
+
+	nodes, err := schedulableNodes(ctx, clients.NodeClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if len(nodes) < 2 {
+		return delFunc, fmt.Errorf("attach ping-pong needs at least 2 schedulable nodes, found %d", len(nodes))
+	}
+	nodes = nodes[:2]
+	log.Infof("Moving volume between %s and %s %s times", color.CyanString(nodes[0]), color.CyanString(nodes[1]), color.YellowString(strconv.Itoa(pps.Moves)))
+
+	pvcClient := clients.PVCClient
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, pps.VolumeSize, "", "ReadWriteOnce")))
+	if claim.HasError() {
+		return delFunc, claim.GetError()
+	}
+
+	// The first pod only attaches the volume, so it isn't counted as a move
+	current, err := pps.startPod(ctx, clients, claim.Object.Name, nodes[0])
+	if err != nil {
+		return delFunc, err
+	}
+	bound := pvcClient.Get(ctx, claim.Object.Name)
+	if bound.HasError() {
+		return delFunc, bound.GetError()
+	}
+	pvName := bound.Object.Spec.VolumeName
+
+	var moves []pingPongMove
+	for i := 1; i <= pps.Moves; i++ {
+		from, to := nodes[(i-1)%2], nodes[i%2]
+		var move pingPongMove
+
+		start := time.Now()
+		if del := clients.PodClient.Delete(ctx, current); del.HasError() {
+			return delFunc, del.GetError()
+		}
+		if err := waitForAttachment(ctx, clients.VaClient, pvName, from, false); err != nil {
+			return delFunc, fmt.Errorf("move %d: volume stuck attached to %s: %w", i, from, err)
+		}
+		move.detach = time.Since(start)
+
+		start = time.Now()
+		next, err := pps.startPod(ctx, clients, claim.Object.Name, to)
+		if err != nil {
+			return delFunc, fmt.Errorf("move %d: %w", i, err)
+		}
+		move.ready = time.Since(start)
+		if err := waitForAttachment(ctx, clients.VaClient, pvName, to, true); err != nil {
+			return delFunc, fmt.Errorf("move %d: volume isn't attached to %s: %w", i, to, err)
+		}
+		move.attach = time.Since(start)
+		if move.attach > move.ready {
+			// Attachment is seen on the next poll, pod can't be ready before volume is attached
+			move.attach = move.ready
+		}
+
+		log.Infof("Move %d/%d %s -> %s: detach %s, attach %s, pod ready %s", i, pps.Moves, from, to, move.detach, move.attach, move.ready)
+		moves = append(moves, move)
+		current = next
+	}
+
+	pps.comparisons = pingPongDrift(moves)
+	if pps.MaxSlowdown > 0 {
+		for _, c := range pps.comparisons {
+			if c.BaselineValue > 0 && float64(c.CandidateValue)/float64(c.BaselineValue) > pps.MaxSlowdown {
+				return delFunc, fmt.Errorf("%s slowed down from %s to %s over %d moves", c.Metric, c.BaselineValue, c.CandidateValue, pps.Moves)
+			}
+		}
+	}
+	return delFunc, nil
+}
+
+// startPod creates pod with the volume on the node and waits for it to become ready
+func (pps *AttachPingPongSuite) startPod(ctx context.Context, clients *k8sclient.Clients, pvcName, node string) (*v1.Pod, error) {
+	podTmpl := clients.PodClient.MakePod(testcore.ProvisioningPodConfig([]string{pvcName}, "", pps.Image))
+	// Node selector keeps scheduler in the loop, so volumes with WaitForFirstConsumer binding mode work too
+	podTmpl.Spec.NodeSelector = map[string]string{v1.LabelHostname: node}
+	p := clients.PodClient.Create(ctx, podTmpl)
+	if p.HasError() {
+		return nil, p.GetError()
+	}
+	if err := p.WaitForRunning(ctx); err != nil {
+		return nil, err
+	}
+	return p.Object, nil
+}
+
+// pingPongDrift compares average latencies of the first and the last third of moves
+func pingPongDrift(moves []pingPongMove) []*store.Comparison {
+	n := len(moves) / 3
+	if n == 0 {
+		return nil
+	}
+	avg := func(part []pingPongMove, latency func(pingPongMove) time.Duration) time.Duration {
+		var durations []time.Duration
+		for _, m := range part {
+			durations = append(durations, latency(m))
+		}
+		return averageDuration(durations)
+	}
+	first, last := moves[:n], moves[len(moves)-n:]
+	var comparisons []*store.Comparison
+	for _, m := range []struct {
+		metric  string
+		latency func(pingPongMove) time.Duration
+	}{
+		{"Avg detach", func(m pingPongMove) time.Duration { return m.detach }},
+		{"Avg attach", func(m pingPongMove) time.Duration { return m.attach }},
+		{"Avg pod ready", func(m pingPongMove) time.Duration { return m.ready }},
+	} {
+		comparisons = append(comparisons, &store.Comparison{
+			Metric:         m.metric,
+			Baseline:       fmt.Sprintf("first %d moves", n),
+			BaselineValue:  avg(first, m.latency),
+			Candidate:      fmt.Sprintf("last %d moves", n),
+			CandidateValue: avg(last, m.latency),
+		})
+	}
+	return comparisons
+}
+
+// waitForAttachment waits until volume attachment of the persistent volume to the node is attached, or is gone if attached is false
+func waitForAttachment(ctx context.Context, vaClient *va.Client, pvName, node string, attached bool) error {
+	timeout := va.Timeout
+	if vaClient.Timeout != 0 {
+		timeout = time.Duration(vaClient.Timeout) * time.Second
+	}
+	return commonparams.PollUntil(ctx, PingPongPoll, timeout, nil, func(context.Context) (bool, error) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+		vaList, err := vaClient.Interface.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, attachment := range vaList.Items {
+			if attachment.Spec.Source.PersistentVolumeName == nil || *attachment.Spec.Source.PersistentVolumeName != pvName ||
+				attachment.Spec.NodeName != node {
+				continue
+			}
+			return attached && attachment.Status.Attached, nil
+		}
+		return !attached, nil
+	})
+}
+
+// schedulableNodes returns names of ready nodes which accept regular pods
+func schedulableNodes(ctx context.Context, nodeClient *node.Client) ([]string, error) {
+	nodeList, err := nodeClient.Interface.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, n := range nodeList.Items {
+		if n.Spec.Unschedulable {
+			continue
+		}
+		tainted := false
+		for _, taint := range n.Spec.Taints {
+			if taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute {
+				tainted = true
+			}
+		}
+		ready := false
+		for _, c := range n.Status.Conditions {
+			if c.Type == v1.NodeReady && c.Status == v1.ConditionTrue {
+				ready = true
+			}
+		}
+		if ready && !tainted {
+			names = append(names, n.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetComparisons returns latencies of the first moves compared to the last ones
+func (pps *AttachPingPongSuite) GetComparisons() []*store.Comparison {
+	return pps.comparisons
+}
+
+// GetObservers returns all observers
+func (*AttachPingPongSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*AttachPingPongSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns attach ping-pong suite namespace
+func (*AttachPingPongSuite) GetNamespace() string {
+	return "ping-pong-test"
+}
+
+// GetName returns attach ping-pong suite name
+func (*AttachPingPongSuite) GetName() string {
+	return "AttachPingPongSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (pps *AttachPingPongSuite) Parameters() string {
+	return fmt.Sprintf("{moves: %d, size: %s}", pps.Moves, pps.VolumeSize)
+}
+
+// RWXSharingSuite is used to manage RWX sharing test suite, a writer Deployment and reader pods behind
+// a headless service share RWX volume, and time content written by the writer takes to reach every reader is measured
+type RWXSharingSuite struct {
+	Readers    int
+	Writes     int
+	VolumeSize string
+	Image      string
+
+	samples []*store.LatencySample
+}
+
+// PropagationTimeout is the longest time reader of RWX sharing suite waits for content written by the writer
+var PropagationTimeout = time.Minute
+
+const (
+	rwxServiceName = "rwx-readers"
+	rwxReaderLabel = "rwx-reader"
+	rwxWriterLabel = "rwx-writer"
+)
+
+// Run executes RWX sharing test suite
+func (rss *RWXSharingSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if rss.Readers <= 0 {
+		log.Info("Using default number of readers")
+		rss.Readers = 3
+	}
+	if rss.Writes <= 0 {
+		log.Info("Using default number of writes")
+		rss.Writes = 10
+	}
+	if rss.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		rss.VolumeSize = "3Gi"
+	}
+	if rss.Image == "" {
+		rss.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", rss.Image)
+	}
+	rss.samples = nil
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+	namespace := podClient.Namespace
+
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, rss.VolumeSize, "", "ReadWriteMany")))
+	if claim.HasError() {
+		return delFunc, claim.GetError()
+	}
+
+	// Headless service gives readers stable DNS names, dual-stack is preferred so both families get endpoints where available
+	dualStack := v1.IPFamilyPolicyPreferDualStack
+	service, err := podClient.ClientSet.CoreV1().Services(namespace).Create(ctx, &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: rwxServiceName},
+		Spec: v1.ServiceSpec{
+			ClusterIP:      v1.ClusterIPNone,
+			Selector:       map[string]string{"app": rwxReaderLabel},
+			IPFamilyPolicy: &dualStack,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+	log.Infof("Created headless service %s with IP families %v", color.CyanString(service.Name), service.Spec.IPFamilies)
+
+	writerTmpl := podClient.MakePod(testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", rss.Image))
+	replicas := int32(1)
+	labels := map[string]string{"app": rwxWriterLabel}
+	_, err = podClient.ClientSet.AppsV1().Deployments(namespace).Create(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: rwxWriterLabel},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       writerTmpl.Spec,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+
+	readerErrs := errgroup.Group{}
+	for i := 0; i < rss.Readers; i++ {
+		readerTmpl := podClient.MakePod(testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", rss.Image))
+		readerTmpl.Labels = map[string]string{"app": rwxReaderLabel}
+		readerTmpl.Spec.Hostname = fmt.Sprintf("reader-%d", i)
+		readerTmpl.Spec.Subdomain = rwxServiceName
+		reader := podClient.Create(ctx, readerTmpl)
+		if reader.HasError() {
+			return delFunc, reader.GetError()
+		}
+		readerErrs.Go(func() error {
+			return reader.WaitForRunning(ctx)
+		})
+	}
+	if err := readerErrs.Wait(); err != nil {
+		return delFunc, err
+	}
+
+	writer, err := rss.writerPod(ctx, podClient)
+	if err != nil {
+		return delFunc, err
+	}
+	readers, err := rss.serviceReaders(ctx, podClient)
+	if err != nil {
+		return delFunc, err
+	}
+	log.Infof("Writer %s shares volume with %s readers", color.CyanString(writer.Name), color.YellowString(strconv.Itoa(len(readers))))
+
+	mountPath := writer.Spec.Containers[0].VolumeMounts[0].MountPath
+	for w := 1; w <= rss.Writes; w++ {
+		file := fmt.Sprintf("%s/content-%d", mountPath, w)
+		seen := make([]time.Time, len(readers))
+		waits := errgroup.Group{}
+		// Readers start waiting before content is written, so exec setup isn't counted as propagation
+		for i, r := range readers {
+			i, r := i, r
+			waits.Go(func() error {
+				wait := fmt.Sprintf("until [ -s %s ]; do sleep 0.05; done", file)
+				cmd := []string{"timeout", strconv.Itoa(int(PropagationTimeout.Seconds())), "/bin/bash", "-c", wait}
+				if err := podClient.Exec(ctx, r, cmd, io.Discard, io.Discard, true); err != nil {
+					return fmt.Errorf("reader %s didn't see write %d within %s: %w", r.Name, w, PropagationTimeout, err)
+				}
+				seen[i] = time.Now()
+				return nil
+			})
+		}
+
+		// Content is renamed into place, so readers never see partially written file
+		write := fmt.Sprintf("echo %d > %s.tmp && mv %s.tmp %s", w, file, file, file)
+		if err := podClient.Exec(ctx, writer, []string{"/bin/bash", "-c", write}, io.Discard, io.Discard, true); err != nil {
+			return delFunc, fmt.Errorf("write %d failed: %w", w, err)
+		}
+		written := time.Now()
+		if err := waits.Wait(); err != nil {
+			return delFunc, err
+		}
+
+		var latencies []time.Duration
+		for i, r := range readers {
+			latency := seen[i].Sub(written)
+			if latency < 0 {
+				// Reader noticed content before writer's exec returned
+				latency = 0
+			}
+			latencies = append(latencies, latency)
+			rss.samples = append(rss.samples, &store.LatencySample{
+				Metric:    "Propagation",
+				Source:    r.Name,
+				Value:     latency,
+				Timestamp: written,
+			})
+		}
+		log.Infof("Write %d/%d reached all readers, avg %s", w, rss.Writes, averageDuration(latencies))
+	}
+	return delFunc, nil
+}
+
+// writerPod waits for pod of writer Deployment to become ready
+func (rss *RWXSharingSuite) writerPod(ctx context.Context, podClient *pod.Client) (*v1.Pod, error) {
+	var writer *v1.Pod
+	writerPods := metav1.ListOptions{LabelSelector: "app=" + rwxWriterLabel}
+	err := commonparams.PollUntil(ctx, time.Second, rwxTimeout(podClient), commonparams.Watch(podClient.Interface, writerPods), func(context.Context) (bool, error) {
+		podList, err := podClient.Interface.List(ctx, writerPods)
+		if err != nil {
+			return false, err
+		}
+		if len(podList.Items) == 0 {
+			return false, nil
+		}
+		writer = &podList.Items[0]
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("writer deployment didn't create pod: %w", err)
+	}
+	p := &pod.Pod{Client: podClient, Object: writer}
+	if err := p.WaitForRunning(ctx); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// serviceReaders discovers readers through endpoints of headless service, waiting until all of them are ready
+func (rss *RWXSharingSuite) serviceReaders(ctx context.Context, podClient *pod.Client) ([]*v1.Pod, error) {
+	var readers []*v1.Pod
+	slicesClient := podClient.ClientSet.DiscoveryV1().EndpointSlices(podClient.Namespace)
+	serviceSlices := metav1.ListOptions{LabelSelector: discoveryv1.LabelServiceName + "=" + rwxServiceName}
+	err := commonparams.PollUntil(ctx, time.Second, rwxTimeout(podClient), commonparams.Watch(slicesClient, serviceSlices), func(context.Context) (bool, error) {
+		slices, err := slicesClient.List(ctx, serviceSlices)
+		if err != nil {
+			return false, err
+		}
+		names := make(map[string]struct{})
+		for _, slice := range slices.Items {
+			for _, endpoint := range slice.Endpoints {
+				ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+				if ready && endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+					names[endpoint.TargetRef.Name] = struct{}{}
+				}
+			}
+		}
+		if len(names) < rss.Readers {
+			return false, nil
+		}
+		readers = nil
+		for name := range names {
+			reader, err := podClient.Interface.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			readers = append(readers, reader)
+		}
+		sort.Slice(readers, func(i, j int) bool { return readers[i].Name < readers[j].Name })
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("headless service %s doesn't expose all %d readers: %w", rwxServiceName, rss.Readers, err)
+	}
+	return readers, nil
+}
+
+// rwxTimeout returns timeout of waiting for pods of RWX sharing suite
+func rwxTimeout(podClient *pod.Client) time.Duration {
+	if podClient.Timeout != 0 {
+		return time.Duration(podClient.Timeout) * time.Second
+	}
+	return pod.Timeout
+}
+
+// GetLatencySamples returns time content took to reach each reader
+func (rss *RWXSharingSuite) GetLatencySamples() []*store.LatencySample {
+	return rss.samples
+}
+
+// GetObservers returns all observers
+func (*RWXSharingSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va and metrics clients
+func (*RWXSharingSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+	}, nil
+}
+
+// GetNamespace returns RWX sharing suite namespace
+func (*RWXSharingSuite) GetNamespace() string {
+	return "rwx-sharing-test"
+}
+
+// GetName returns RWX sharing suite name
+func (*RWXSharingSuite) GetName() string {
+	return "RWXSharingSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (rss *RWXSharingSuite) Parameters() string {
+	return fmt.Sprintf("{readers: %d, writes: %d, size: %s}", rss.Readers, rss.Writes, rss.VolumeSize)
+}
+
+// VeleroBackupSuite is used to manage Velero backup and restore test suite
+type VeleroBackupSuite struct {
+	// VeleroNamespace is the namespace Velero is installed to
+	VeleroNamespace string
+	VolumeSize      string
+	Image           string
+
+	samples []*store.LatencySample
+}
+
+// Run executes Velero backup and restore test suite
+func (vbs *VeleroBackupSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if vbs.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		vbs.VolumeSize = "3Gi"
+	}
+	if vbs.Image == "" {
+		vbs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", vbs.Image)
+	}
+	vbs.samples = nil
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+	veleroClient := clients.VeleroClient
+
+	pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, vbs.VolumeSize, "", "")))
+	if pvc.HasError() {
+		return delFunc, pvc.GetError()
+	}
+	namespace := pvc.Object.Namespace
+
+	podconf := testcore.IoWritePodConfig([]string{pvc.Object.Name}, "", vbs.Image)
+	file := fmt.Sprintf("%s0/writer-%d.data", podconf.MountPath, 0)
+	sum := fmt.Sprintf("%s0/writer-%d.sha512", podconf.MountPath, 0)
+	writerPod := podClient.Create(ctx, podClient.MakePod(podconf)).Sync(ctx)
+	if writerPod.HasError() {
+		return delFunc, writerPod.GetError()
+	}
+	if err := podClient.Exec(ctx, writerPod.Object, []string{"dd", "if=/dev/urandom", "of=" + file, "bs=1M", "count=64", "oflag=sync"}, io.Discard, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", "sha512sum " + file + " > " + sum}, os.Stdout, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	// Volume is backed up unattached, so nothing is left in page cache of the node
+	podClient.Delete(ctx, writerPod.Object).Sync(ctx)
+	if writerPod.HasError() {
+		return delFunc, writerPod.GetError()
+	}
+
+	backupStart := time.Now()
+	backup, err := veleroClient.CreateBackup(ctx, namespace+"-backup", namespace)
+	if err != nil {
+		return delFunc, err
+	}
+	restoreNamespace := namespace + "-restore"
+	var restore *velero.Operation
+	// Restored namespace isn't managed by runner, so it's deleted together with the backup
+	delFunc = func() error {
+		var errs []string
+		if restore != nil {
+			if err := clients.KubeClient.DeleteNamespace(context.Background(), restoreNamespace); err != nil && !apierrs.IsNotFound(err) {
+				errs = append(errs, err.Error())
+			}
+			if err := restore.Delete(context.Background()); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if err := veleroClient.DeleteBackup(context.Background(), backup.Name()); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if len(errs) != 0 {
+			return fmt.Errorf("can't clean up Velero backup %s: %s", backup.Name(), strings.Join(errs, "; "))
+		}
+		return nil
+	}
+	if err := backup.WaitForCompletion(ctx); err != nil {
+		return delFunc, err
+	}
+	vbs.addSample("Backup", backup.Name(), time.Since(backupStart))
+
+	restoreStart := time.Now()
+	restore, err = veleroClient.CreateRestore(ctx, namespace+"-restore", backup.Name(), namespace, restoreNamespace)
+	if err != nil {
+		return delFunc, err
+	}
+	if err := restore.WaitForCompletion(ctx); err != nil {
+		return delFunc, err
+	}
+	vbs.addSample("Restore", restore.Name(), time.Since(restoreStart))
+
+	restoredPodClient, err := clients.KubeClient.CreatePodClient(restoreNamespace)
+	if err != nil {
+		return delFunc, err
+	}
+	checkerStart := time.Now()
+	checkerPod := restoredPodClient.Create(ctx, restoredPodClient.MakePod(testcore.IoWritePodConfig([]string{pvc.Object.Name}, "", vbs.Image))).Sync(ctx)
+	if checkerPod.HasError() {
+		return delFunc, fmt.Errorf("restored volume can't be used: %w", checkerPod.GetError())
+	}
+	vbs.addSample("RestoredVolumeReady", restore.Name(), time.Since(checkerStart))
+	log.Infof("Backup and restore of %s took %s", namespace, color.HiYellowString(time.Since(backupStart).String()))
+
+	checkRes := bytes.NewBufferString("")
+	if err := restoredPodClient.Exec(ctx, checkerPod.Object, []string{"/bin/bash", "-c", "sha512sum -c " + sum}, checkRes, os.Stderr, false); err != nil {
+		return delFunc, fmt.Errorf("restored data doesn't match backed up data: %w", err)
+	}
+	if !strings.Contains(checkRes.String(), "OK") {
+		return delFunc, fmt.Errorf("restored data doesn't match backed up data")
+	}
+	log.Info("Hashes match")
+	return delFunc, nil
+}
+
+func (vbs *VeleroBackupSuite) addSample(metric, source string, d time.Duration) {
+	vbs.samples = append(vbs.samples, &store.LatencySample{Metric: metric, Source: source, Value: d, Timestamp: time.Now()})
+}
+
+// GetLatencySamples returns durations of backup, restore and attaching of restored volume
+func (vbs *VeleroBackupSuite) GetLatencySamples() []*store.LatencySample {
+	return vbs.samples
+}
+
+// GetObservers returns all observers
+func (*VeleroBackupSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and Velero clients
+func (vbs *VeleroBackupSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	if vbs.VeleroNamespace == "" {
+		vbs.VeleroNamespace = velero.DefaultNamespace
+	}
+	veleroClient, veleroErr := client.CreateVeleroClient(vbs.VeleroNamespace)
+	if veleroErr != nil {
+		return nil, veleroErr
+	}
+	if err := veleroClient.Installed(context.Background()); err != nil {
+		return nil, err
+	}
+
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		VeleroClient:      veleroClient,
+		KubeClient:        client,
+	}, nil
+}
+
+// GetNamespace returns Velero backup suite namespace
+func (*VeleroBackupSuite) GetNamespace() string {
+	return "velero-backup-test"
+}
+
+// GetName returns Velero backup suite name
+func (*VeleroBackupSuite) GetName() string {
+	return "VeleroBackupSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (vbs *VeleroBackupSuite) Parameters() string {
+	return fmt.Sprintf("{veleroNamespace: %s, size: %s}", vbs.VeleroNamespace, vbs.VolumeSize)
+}
+
+// VolumeStatsSuite is used to manage kubelet volume stats validation test suite
+type VolumeStatsSuite struct {
+	VolumeNumber int
+	VolumeSize   string
+	// WriteSize is amount of data in MiB written to every volume before stats are sampled
+	WriteSize int
+	Image     string
+
+	stats []*store.VolumeStat
+}
+
+// Run executes kubelet volume stats validation test suite
+func (vss *VolumeStatsSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if vss.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		vss.VolumeNumber = 1
+	}
+	if vss.WriteSize <= 0 {
+		log.Info("Using default write size 256Mi")
+		vss.WriteSize = 256
+	}
+	if vss.Image == "" {
+		vss.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", vss.Image)
+	}
+	vss.stats = nil
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	var pods []*pod.Pod
+	var mountPath string
+	for i := 0; i < vss.VolumeNumber; i++ {
+		pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, vss.VolumeSize, "", "")))
+		if pvc.HasError() {
+			return delFunc, pvc.GetError()
+		}
+		podconf := testcore.IoWritePodConfig([]string{pvc.Object.Name}, "", vss.Image)
+		mountPath = podconf.MountPath
+		writerPod := podClient.Create(ctx, podClient.MakePod(podconf))
+		if writerPod.HasError() {
+			return delFunc, writerPod.GetError()
+		}
+		pods = append(pods, writerPod)
+	}
+	if err := podClient.WaitForAllToBeReady(ctx); err != nil {
+		return delFunc, err
+	}
+
+	claims := make(map[string]string)
+	for _, writerPod := range pods {
+		actual, err := podClient.Interface.Get(ctx, writerPod.Object.Name, metav1.GetOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		file := fmt.Sprintf("%s0/stats.data", mountPath)
+		dd := []string{"dd", "if=/dev/urandom", "of=" + file, "bs=1M", fmt.Sprintf("count=%d", vss.WriteSize), "oflag=sync"}
+		if err := podClient.Exec(ctx, actual, dd, io.Discard, os.Stderr, false); err != nil {
+			return delFunc, err
+		}
+		claims[actual.Spec.Volumes[0].PersistentVolumeClaim.ClaimName] = actual.Spec.NodeName
+	}
+
+	log.Infof("Waiting for kubelet to report usage of %d volumes", len(claims))
+	stats, err := sampleVolumeStats(ctx, clients, claims, int64(vss.WriteSize)<<20)
+	vss.stats = stats
+	if err != nil {
+		return delFunc, err
+	}
+	return delFunc, invalidVolumeStats(stats)
+}
+
+// GetVolumeStats returns volume usage reported by kubelet during the last run
+func (vss *VolumeStatsSuite) GetVolumeStats() []*store.VolumeStat {
+	return vss.stats
+}
+
+// GetObservers returns all observers
+func (*VolumeStatsSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*VolumeStatsSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns volume stats suite namespace
+func (*VolumeStatsSuite) GetNamespace() string {
+	return "volume-stats-test"
+}
+
+// GetName returns volume stats suite name
+func (*VolumeStatsSuite) GetName() string {
+	return "VolumeStatsSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (vss *VolumeStatsSuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, size: %s, write: %dMi}", vss.VolumeNumber, vss.VolumeSize, vss.WriteSize)
+}
+
+// MountOptionMatrixSuite is used to manage mount option matrix test suite, it clones storage class with every
+// option set and checks that volume is mounted with the options, or that mount fails cleanly if set is expected to fail
+type MountOptionMatrixSuite struct {
+	OptionSets []MountOptionSet
+	VolumeSize string
+	Image      string
+	// MountTimeout is how long pod of an option set is given to start before mount is considered failed
+	MountTimeout time.Duration
+
+	results []*store.MountOptionResult
+}
+
+// Run executes mount option matrix test suite
+func (mos *MountOptionMatrixSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if len(mos.OptionSets) == 0 {
+		log.Info("Using default option sets")
+		mos.OptionSets = DefaultMountOptionSets
+	}
+	if mos.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		mos.VolumeSize = "3Gi"
+	}
+	if mos.Image == "" {
+		mos.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", mos.Image)
+	}
+	if mos.MountTimeout <= 0 {
+		mos.MountTimeout = MountOptionTimeout
+	}
+	mos.results = nil
+
+	source := clients.SCClient.Get(ctx, storageClass)
+	if source.HasError() {
+		return delFunc, source.GetError()
+	}
+
+	// Storage classes are cluster-scoped, so they don't go away with namespace
+	var created []string
+	delFunc = func() error {
+		for _, name := range created {
+			if err := clients.SCClient.Delete(context.Background(), name); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i, set := range mos.OptionSets {
+		name := k8sclient.GenerateName(fmt.Sprintf("%s-mo%d-", storageClass, i))
+		clone := clients.SCClient.DuplicateStorageClass(name, source.Object)
+		clone.MountOptions = set.Options
+		if err := clients.SCClient.Create(ctx, clone); err != nil {
+			return delFunc, err
+		}
+		created = append(created, name)
+
+		log.Infof("Mounting volume with option set %s: %s", color.CyanString(set.Name), strings.Join(set.Options, ","))
+		res, err := mos.mountWith(ctx, name, set, clients)
+		if err != nil {
+			return delFunc, err
+		}
+		if res.Passed {
+			log.Infof("Option set %s: %s", set.Name, color.GreenString(string(res.Outcome)))
+		} else {
+			log.Errorf("Option set %s: %s %s", color.CyanString(set.Name), color.RedString(string(res.Outcome)), res.Message)
+		}
+		mos.results = append(mos.results, res)
+	}
+
+	return delFunc, failedMountOptionSets(mos.results)
+}
+
+// mountWith mounts volume of storage class in a pod and classifies outcome, volume is deleted before the next set,
+// so mount which failed must not leave pod or claim behind
+func (mos *MountOptionMatrixSuite) mountWith(ctx context.Context, storageClass string, set MountOptionSet, clients *k8sclient.Clients) (*store.MountOptionResult, error) {
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+	res := &store.MountOptionResult{Set: set.Name, Options: strings.Join(set.Options, ","), ExpectFailure: set.ExpectFailure}
+
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, mos.VolumeSize, "", "")))
+	if claim.HasError() {
+		return nil, claim.GetError()
+	}
+	mounter := podClient.Create(ctx, podClient.MakePod(testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", mos.Image)))
+	if mounter.HasError() {
+		return nil, mounter.GetError()
+	}
+
+	mountCtx, cancel := context.WithTimeout(ctx, mos.MountTimeout)
+	readyErr := mounter.WaitForRunning(mountCtx)
+	cancel()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if readyErr == nil {
+		checks, err := validateMounts(ctx, clients, []*v1.Pod{mounter.Object}, set.Options, mos.Image)
+		if err != nil {
+			return nil, err
+		}
+		if len(checks) == 0 {
+			return nil, fmt.Errorf("pod %s has no filesystem volume", mounter.Object.Name)
+		}
+		classifyMountOptions(res, set, checks[0])
+	} else {
+		res.Outcome = store.MountOptionsRejected
+		res.Message = mountFailure(ctx, podClient, mounter.Object)
+		if res.Message == "" {
+			res.Message = readyErr.Error()
+		}
+	}
+
+	var cleanupErr error
+	if deleted := podClient.Delete(ctx, mounter.Object).Sync(ctx); deleted.HasError() {
+		cleanupErr = fmt.Errorf("pod %s wasn't deleted: %v", mounter.Object.Name, deleted.GetError())
+	} else if deleted := pvcClient.Delete(ctx, claim.Object).Sync(ctx); deleted.HasError() {
+		cleanupErr = fmt.Errorf("pvc %s wasn't deleted: %v", claim.Object.Name, deleted.GetError())
+	}
+	if cleanupErr != nil {
+		if res.Outcome == store.MountOptionsRejected {
+			res.Outcome = store.MountOptionsStuck
+		}
+		res.Message = strings.TrimPrefix(res.Message+"; "+cleanupErr.Error(), "; ")
+	}
+
+	expected := store.MountOptionsApplied
+	if set.ExpectFailure {
+		expected = store.MountOptionsRejected
+	}
+	res.Passed = res.Outcome == expected && cleanupErr == nil
+	return res, nil
+}
+
+// GetMountOptionResults returns outcome of every option set of the last run
+func (mos *MountOptionMatrixSuite) GetMountOptionResults() []*store.MountOptionResult {
+	return mos.results
+}
+
+// GetObservers returns all observers
+func (*MountOptionMatrixSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and storage class clients
+func (*MountOptionMatrixSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	scClient, scErr := client.CreateSCClient()
+	if scErr != nil {
+		return nil, scErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		SCClient:          scClient,
+	}, nil
+}
+
+// GetNamespace returns mount option matrix suite namespace
+func (*MountOptionMatrixSuite) GetNamespace() string {
+	return "mount-options-test"
+}
+
+// GetName returns mount option matrix suite name
+func (*MountOptionMatrixSuite) GetName() string {
+	return "MountOptionMatrixSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (mos *MountOptionMatrixSuite) Parameters() string {
+	names := make([]string, 0, len(mos.OptionSets))
+	for _, set := range mos.OptionSets {
+		names = append(names, set.Name)
+	}
+	return fmt.Sprintf("{optionSets: [%s], size: %s}", strings.Join(names, ", "), mos.VolumeSize)
+}
+
+// CapacityFullSuite is used to manage capacity-full behavior test suite, it provisions volumes until backend pool
+// is exhausted and checks that driver rejects provisioning properly without harming volumes it already provisioned
+type CapacityFullSuite struct {
+	// MaxVolumes bounds number of volumes provisioned while trying to exhaust the pool
+	MaxVolumes int
+	VolumeSize string
+	Image      string
+	// BindTimeout is how long claim is given to bind before pool is considered exhausted
+	BindTimeout time.Duration
+
+	results []*store.CapacityFillResult
+}
+
+// Run executes capacity-full behavior test suite
+func (cfs *CapacityFullSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if cfs.MaxVolumes <= 0 {
+		log.Info("Using default maximum number of volumes")
+		cfs.MaxVolumes = 20
+	}
+	if cfs.VolumeSize == "" {
+		log.Info("Using default volume size 100Gi")
+		cfs.VolumeSize = "100Gi"
+	}
+	if cfs.Image == "" {
+		cfs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", cfs.Image)
+	}
+	if cfs.BindTimeout <= 0 {
+		cfs.BindTimeout = CapacityFillTimeout
+	}
+	res := &store.CapacityFillResult{VolumeSize: cfs.VolumeSize}
+	cfs.results = []*store.CapacityFillResult{res}
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	// Every claim gets a pod, so claims bind with WaitForFirstConsumer binding mode too
+	provision := func() (*v1.PersistentVolumeClaim, *v1.Pod, bool, error) {
+		claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, cfs.VolumeSize, "", "")))
+		if claim.HasError() {
+			return nil, nil, false, claim.GetError()
+		}
+		user := podClient.Create(ctx, podClient.MakePod(testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", cfs.Image)))
+		if user.HasError() {
+			return nil, nil, false, user.GetError()
+		}
+		bound, err := waitClaimBound(ctx, pvcClient, claim.Object.Name, cfs.BindTimeout)
+		return claim.Object, user.Object, bound, err
+	}
+
+	var pods []string
+	for i := 0; i < cfs.MaxVolumes; i++ {
+		claim, user, bound, err := provision()
+		if err != nil {
+			return delFunc, err
+		}
+		if bound {
+			res.Bound++
+			pods = append(pods, user.Name)
+			continue
+		}
+
+		res.Exhausted = true
+		res.Message = provisioningFailure(ctx, pvcClient, claim.Name)
+		res.ErrorCode = grpcCode(res.Message)
+		log.Infof("Pool exhausted after %s volumes of %s: %s", color.YellowString(strconv.Itoa(res.Bound)), cfs.VolumeSize, res.Message)
+		// Rejected claim keeps retrying, so it must not grab capacity freed by cleanup
+		if deleted := podClient.Delete(ctx, user).Sync(ctx); deleted.HasError() {
+			return delFunc, deleted.GetError()
+		}
+		if deleted := pvcClient.Delete(ctx, claim).Sync(ctx); deleted.HasError() {
+			return delFunc, deleted.GetError()
+		}
+		break
+	}
+
+	if res.Bound != 0 {
+		if err := podClient.WaitForAllToBeReady(ctx); err != nil {
+			log.Errorf("Not every pod of bound volumes is ready; error=%v", err)
+		}
+	}
+	mountPath := testcore.ProvisioningPodConfig(nil, "", "").MountPath
+	for _, name := range pods {
+		if volumeWritable(ctx, podClient, name, mountPath+"0/capacity-check") {
+			res.Healthy++
+		} else {
+			log.Errorf("Volume of pod %s isn't writable once pool was exhausted", color.CyanString(name))
+		}
+	}
+
+	if res.Exhausted {
+		log.Infof("Deleting %d volumes and provisioning a new one", res.Bound)
+		if err := podClient.DeleteAll(ctx); err != nil {
+			return delFunc, err
+		}
+		if err := pvcClient.DeleteAll(ctx); err != nil {
+			return delFunc, err
+		}
+		_, _, bound, err := provision()
+		if err != nil {
+			return delFunc, err
+		}
+		res.Restored = bound
+	}
+
+	problems := capacityFillProblems(res, cfs.MaxVolumes)
+	res.Passed = problems == nil
+	return delFunc, problems
+}
+
+// GetCapacityFillResults returns outcome of pool exhaustion of the last run
+func (cfs *CapacityFullSuite) GetCapacityFillResults() []*store.CapacityFillResult {
+	return cfs.results
+}
+
+// GetObservers returns all observers
+func (*CapacityFullSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va and metrics clients
+func (*CapacityFullSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+	}, nil
+}
+
+// GetNamespace returns capacity-full suite namespace
+func (*CapacityFullSuite) GetNamespace() string {
+	return "capacity-full-test"
+}
+
+// GetName returns capacity-full suite name
+func (*CapacityFullSuite) GetName() string {
+	return "CapacityFullSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (cfs *CapacityFullSuite) Parameters() string {
+	return fmt.Sprintf("{maxVolumes: %d, size: %s}", cfs.MaxVolumes, cfs.VolumeSize)
+}
+
+// QoSClassSuite is used to manage QoS class test suite, it mounts and writes to volumes from pods of every QoS class
+// on the same node and compares latencies with Guaranteed pods
+type QoSClassSuite struct {
+	Iterations int
+	VolumeSize string
+	// WriteSize is amount of data in MiB every pod writes to its volume
+	WriteSize int
+	// PressureWorkers is number of BestEffort pods burning CPU on the node while latencies are measured
+	PressureWorkers int
+	Image           string
+
+	comparisons []*store.Comparison
+	samples     []*store.LatencySample
+}
+
+// Run executes QoS class test suite
+func (qcs *QoSClassSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if qcs.Iterations <= 0 {
+		log.Info("Using default number of iterations")
+		qcs.Iterations = 3
+	}
+	if qcs.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		qcs.VolumeSize = "3Gi"
+	}
+	if qcs.WriteSize <= 0 {
+		log.Info("Using default write size 64Mi")
+		qcs.WriteSize = 64
+	}
+	if qcs.Image == "" {
+		qcs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", qcs.Image)
+	}
+	qcs.comparisons, qcs.samples = nil, nil
+	podClient := clients.PodClient
+
+	// Node is picked by the first pod scheduled, the rest are pinned to it so classes compete for the same resources
+	node := ""
+	for i := 0; i < qcs.PressureWorkers; i++ {
+		conf := testcore.ProvisioningPodConfig(nil, "", qcs.Image)
+		conf.NamePrefix = "qos-pressure-"
+		conf.Args = []string{"-c", "trap 'exit 0' SIGTERM;while true; do :; done"}
+		worker := podClient.MakePod(conf)
+		pinToNode(worker, node)
+		created := podClient.Create(ctx, worker)
+		if created.HasError() {
+			return delFunc, created.GetError()
+		}
+		ready, err := waitPodReady(ctx, podClient, created.Object.Name)
+		if err != nil {
+			return delFunc, err
+		}
+		node = ready.Spec.NodeName
+	}
+	if qcs.PressureWorkers > 0 {
+		log.Infof("Started %d pressure workers on node %s", qcs.PressureWorkers, color.CyanString(node))
+	}
+
+	readyTimes := make(map[v1.PodQOSClass][]time.Duration)
+	writeTimes := make(map[v1.PodQOSClass][]time.Duration)
+	writeMetric := fmt.Sprintf("Write %dMi", qcs.WriteSize)
+	// Classes are interleaved, so drift of backend or node load during the run affects all of them alike
+	for i := 0; i < qcs.Iterations; i++ {
+		for _, class := range QoSClasses {
+			p, ready, write, err := qcs.measure(ctx, storageClass, class, node, clients)
+			if err != nil {
+				return delFunc, err
+			}
+			node = p.Spec.NodeName
+			log.Infof("%s pod %s: ready in %s, wrote %dMi in %s", class, p.Name, ready, qcs.WriteSize, write)
+			readyTimes[class] = append(readyTimes[class], ready)
+			writeTimes[class] = append(writeTimes[class], write)
+			qcs.samples = append(qcs.samples,
+				&store.LatencySample{Metric: fmt.Sprintf("Pod ready (%s)", class), Source: p.Name, Value: ready, Timestamp: time.Now()},
+				&store.LatencySample{Metric: fmt.Sprintf("%s (%s)", writeMetric, class), Source: p.Name, Value: write, Timestamp: time.Now()},
+			)
+		}
+	}
+
+	baseline := QoSClasses[0]
+	for _, class := range QoSClasses[1:] {
+		for _, m := range []struct {
+			metric string
+			values map[v1.PodQOSClass][]time.Duration
+		}{
+			{"Avg pod ready", readyTimes},
+			{"Avg " + strings.ToLower(writeMetric), writeTimes},
+		} {
+			c := &store.Comparison{
+				Metric:         m.metric,
+				Baseline:       string(baseline),
+				BaselineValue:  averageDuration(m.values[baseline]),
+				Candidate:      string(class),
+				CandidateValue: averageDuration(m.values[class]),
+			}
+			log.Infof("%s overhead of %s: %s", m.metric, c.Candidate, color.YellowString(c.Difference().String()))
+			qcs.comparisons = append(qcs.comparisons, c)
+		}
+	}
+
+	return delFunc, nil
+}
+
+// measure mounts new volume in pod of the QoS class and writes to it, volume and pod are deleted afterwards,
+// so every measurement includes attach and mount
+func (qcs *QoSClassSuite) measure(ctx context.Context, storageClass string, class v1.PodQOSClass, node string, clients *k8sclient.Clients) (*v1.Pod, time.Duration, time.Duration, error) {
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, qcs.VolumeSize, "", "")))
+	if claim.HasError() {
+		return nil, 0, 0, claim.GetError()
+	}
+	conf := testcore.IoWritePodConfig([]string{claim.Object.Name}, "", qcs.Image)
+	conf.NamePrefix = fmt.Sprintf("qos-%s-", strings.ToLower(string(class)))
+	conf.Requests, conf.Limits = qosResources(class)
+	writer := podClient.MakePod(conf)
+	pinToNode(writer, node)
+
+	start := time.Now()
+	created := podClient.Create(ctx, writer)
+	if created.HasError() {
+		return nil, 0, 0, created.GetError()
+	}
+	ready, err := waitPodReady(ctx, podClient, created.Object.Name)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	readyTime := time.Since(start)
+	// LimitRange of the namespace may have changed resources of the pod
+	if ready.Status.QOSClass != class {
+		return nil, 0, 0, fmt.Errorf("pod %s got %s QoS class instead of %s", ready.Name, ready.Status.QOSClass, class)
+	}
+
+	writeTime, err := timedWrite(ctx, podClient, ready, conf.MountPath+"0/qos.data", qcs.WriteSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if deleted := podClient.Delete(ctx, ready).Sync(ctx); deleted.HasError() {
+		return nil, 0, 0, deleted.GetError()
+	}
+	if deleted := pvcClient.Delete(ctx, claim.Object).Sync(ctx); deleted.HasError() {
+		return nil, 0, 0, deleted.GetError()
+	}
+	return ready, readyTime, writeTime, nil
+}
+
+// GetComparisons returns latencies of Burstable and BestEffort pods compared to Guaranteed ones
+func (qcs *QoSClassSuite) GetComparisons() []*store.Comparison {
+	return qcs.comparisons
+}
+
+// GetLatencySamples returns pod ready and write latencies of every measured pod
+func (qcs *QoSClassSuite) GetLatencySamples() []*store.LatencySample {
+	return qcs.samples
+}
+
+// GetObservers returns all observers
+func (*QoSClassSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va and metrics clients
+func (*QoSClassSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+	}, nil
+}
+
+// GetNamespace returns QoS class suite namespace
+func (*QoSClassSuite) GetNamespace() string {
+	return "qos-class-test"
+}
+
+// GetName returns QoS class suite name
+func (*QoSClassSuite) GetName() string {
+	return "QoSClassSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (qcs *QoSClassSuite) Parameters() string {
+	return fmt.Sprintf("{iterations: %d, size: %s, write: %dMi, pressureWorkers: %d}", qcs.Iterations, qcs.VolumeSize, qcs.WriteSize, qcs.PressureWorkers)
+}
+
+// IPv6Suite is used to manage IPv6 test suite, it checks that node plugins of the driver reach its controller over
+// IPv6 and that volumes are provisioned and mounted on IPv6 cluster
+type IPv6Suite struct {
+	DriverNamespace string
+	VolumeSize      string
+	Image           string
+
+	samples []*store.LatencySample
+}
+
+// Run executes IPv6 test suite
+func (is *IPv6Suite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if is.DriverNamespace == "" {
+		return delFunc, errors.New("driver namespace is required to find node plugin and controller pods")
+	}
+	if is.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		is.VolumeSize = "3Gi"
+	}
+	if is.Image == "" {
+		is.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", is.Image)
+	}
+	is.samples = nil
+	podClient := clients.PodClient
+	pvcClient := clients.PVCClient
+
+	families, err := clients.KubeClient.NodeIPFamilies(ctx)
+	if err != nil {
+		return delFunc, err
+	}
+	log.Infof("Cluster nodes have %v addresses", families)
+	if !slices.Contains(families, string(v1.IPv6Protocol)) {
+		return delFunc, fmt.Errorf("cluster nodes have no IPv6 addresses, IP families: %v", families)
+	}
+
+	controllers, nodePlugins, err := driverPods(ctx, podClient.ClientSet, is.DriverNamespace)
+	if err != nil {
+		return delFunc, err
+	}
+	if len(controllers) == 0 || len(nodePlugins) == 0 {
+		return delFunc, fmt.Errorf("found %d controller and %d node plugin pods running in namespace %s", len(controllers), len(nodePlugins), is.DriverNamespace)
+	}
+	var endpoints []ipv6Endpoint
+	for _, p := range controllers {
+		if len(ipv6Addresses(p)) == 0 {
+			return delFunc, fmt.Errorf("controller pod %s has no IPv6 address: %v", p.Name, p.Status.PodIPs)
+		}
+		endpoints = append(endpoints, controllerEndpoints(p)...)
+	}
+	if len(endpoints) == 0 {
+		return delFunc, errors.New("controller pods declare no TCP ports to connect to")
+	}
+
+	// Probe shares network of node plugin, so it connects the way node plugin would
+	var failed []string
+	for _, plugin := range nodePlugins {
+		if len(ipv6Addresses(plugin)) == 0 {
+			return delFunc, fmt.Errorf("node plugin pod %s has no IPv6 address: %v", plugin.Name, plugin.Status.PodIPs)
+		}
+		conf := testcore.ProvisioningPodConfig(nil, "", is.Image)
+		conf.NamePrefix = "ipv6-probe-"
+		probe := podClient.MakePod(conf)
+		ipv6ProbePod(probe, plugin)
+		created := podClient.Create(ctx, probe)
+		if created.HasError() {
+			return delFunc, created.GetError()
+		}
+		ready, err := waitPodReady(ctx, podClient, created.Object.Name)
+		if err != nil {
+			return delFunc, err
+		}
+		for _, endpoint := range endpoints {
+			source := fmt.Sprintf("%s -> %s", plugin.Spec.NodeName, endpoint)
+			start := time.Now()
+			connErr := podClient.Exec(ctx, ready, connectCommand(endpoint), io.Discard, io.Discard, true)
+			sample := &store.LatencySample{Metric: store.IPv6ConnectMetric, Source: source, Value: time.Since(start), Timestamp: time.Now()}
+			if connErr != nil {
+				log.Errorf("Can't connect %s; error=%v", source, connErr)
+				sample.Metric = store.IPv6ConnectFailedMetric
+				failed = append(failed, source)
+			} else {
+				log.Infof("Connected %s in %s", source, sample.Value)
+			}
+			is.samples = append(is.samples, sample)
+		}
+		if deleted := podClient.Delete(ctx, ready).Sync(ctx); deleted.HasError() {
+			return delFunc, deleted.GetError()
+		}
+	}
+
+	// Volume is provisioned, attached and mounted through driver talking over IPv6 only
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, is.VolumeSize, "", "")))
+	if claim.HasError() {
+		return delFunc, claim.GetError()
+	}
+	conf := testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", is.Image)
+	conf.NamePrefix = "ipv6-volume-"
+	start := time.Now()
+	created := podClient.Create(ctx, podClient.MakePod(conf))
+	if created.HasError() {
+		return delFunc, created.GetError()
+	}
+	ready, err := waitPodReady(ctx, podClient, created.Object.Name)
+	if err != nil {
+		return delFunc, err
+	}
+	is.samples = append(is.samples, &store.LatencySample{Metric: "Pod ready (IPv6)", Source: ready.Name, Value: time.Since(start), Timestamp: time.Now()})
+
+	if len(failed) != 0 {
+		return delFunc, fmt.Errorf("node plugins can't reach controller over IPv6: %s", strings.Join(failed, ", "))
+	}
+	return delFunc, nil
+}
+
+// GetLatencySamples returns node plugin to controller connections and pod ready latency
+func (is *IPv6Suite) GetLatencySamples() []*store.LatencySample {
+	return is.samples
+}
+
+// GetObservers returns all observers
+func (*IPv6Suite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and kube clients
+func (*IPv6Suite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		KubeClient:        client,
+	}, nil
+}
+
+// GetNamespace returns IPv6 suite namespace
+func (*IPv6Suite) GetNamespace() string {
+	return "ipv6-test"
+}
+
+// GetName returns IPv6 suite name
+func (*IPv6Suite) GetName() string {
+	return "IPv6Suite"
+}
+
+// Parameters returns formatted string of parameters
+func (is *IPv6Suite) Parameters() string {
+	return fmt.Sprintf("{driverNamespace: %s, size: %s}", is.DriverNamespace, is.VolumeSize)
+}
+
+// KubeletRestartSuite is used to manage kubelet restart test suite, it restarts kubelet of a node with mounted volumes
+// and validates that volumes stay mounted, pods stay healthy and that remounts after pod restart are idempotent
+type KubeletRestartSuite struct {
+	PodNumber  int
+	VolumeSize string
+	// RestartHook restarts kubelet of node passed in CERT_CSI_NODE, privileged pod restarts it with systemctl if empty
+	RestartHook string
+	Image       string
+
+	samples []*store.LatencySample
+}
+
+// Run executes kubelet restart test suite
+func (krs *KubeletRestartSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if krs.PodNumber <= 0 {
+		log.Info("Using default number of pods")
+		krs.PodNumber = 2
+	}
+	if krs.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		krs.VolumeSize = "3Gi"
+	}
+	if krs.Image == "" {
+		krs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", krs.Image)
+	}
+	krs.samples = nil
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	// Pods are pinned to the node of the first one, so a single restart affects all volumes
+	node := ""
+	var pods []*v1.Pod
+	volumes := make(map[string]string)
+	for i := 0; i < krs.PodNumber; i++ {
+		claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, krs.VolumeSize, "", "")))
+		if claim.HasError() {
+			return delFunc, claim.GetError()
+		}
+		p, err := krs.startPod(ctx, podClient, claim.Object.Name, node)
+		if err != nil {
+			return delFunc, err
+		}
+		node = p.Spec.NodeName
+		bound := pvcClient.Get(ctx, claim.Object.Name)
+		if bound.HasError() {
+			return delFunc, bound.GetError()
+		}
+		volumes[p.Name] = bound.Object.Spec.VolumeName
+		write := fmt.Sprintf("echo %s > /data0/%s && sync", p.Name, kubeletRestartFile)
+		if err := podClient.Exec(ctx, p, []string{"bash", "-c", write}, io.Discard, io.Discard, true); err != nil {
+			return delFunc, fmt.Errorf("can't write to volume of pod %s: %v", p.Name, err)
+		}
+		pods = append(pods, p)
+	}
+
+	diag := podClient.Create(ctx, podClient.MakeDiagnosticPod(node, krs.Image))
+	if diag.HasError() {
+		return delFunc, diag.GetError()
+	}
+	defer func() {
+		podClient.Delete(context.Background(), diag.Object)
+	}()
+	if err := diag.WaitForRunning(ctx); err != nil {
+		return delFunc, err
+	}
+
+	log.Infof("Restarting kubelet of node %s with %d mounted volumes", color.CyanString(node), len(pods))
+	triggered := time.Now()
+	if err := restartKubelet(ctx, podClient, diag.Object, krs.RestartHook, node); err != nil {
+		return delFunc, err
+	}
+	downtime, err := waitKubeletRestarted(ctx, clients.NodeClient, node, triggered)
+	if err != nil {
+		return delFunc, err
+	}
+	log.Infof("Kubelet of node %s is back after %s", node, color.YellowString(downtime.String()))
+	krs.samples = append(krs.samples, &store.LatencySample{Metric: "Kubelet restart downtime", Source: node, Value: downtime, Timestamp: time.Now()})
+
+	// Volumes must survive kubelet restart without pods noticing
+	var failed []string
+	table, err := readDiagMountTable(ctx, podClient, diag.Object)
+	if err != nil {
+		return delFunc, err
+	}
+	for _, p := range pods {
+		actual, err := podClient.Interface.Get(ctx, p.Name, metav1.GetOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		if !pod.IsPodReady(actual) || podRestarts(actual) != podRestarts(p) {
+			failed = append(failed, fmt.Sprintf("%s: pod isn't healthy after kubelet restart, %d container restarts", p.Name, podRestarts(actual)-podRestarts(p)))
+			continue
+		}
+		if n := countMounts(table, publishPathSuffix(string(p.UID), volumes[p.Name])); n != 1 {
+			failed = append(failed, fmt.Sprintf("%s: volume is mounted %d times after kubelet restart", p.Name, n))
+		}
+		if err := krs.checkData(ctx, podClient, actual, p.Name); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", p.Name, err))
+		}
+	}
+
+	// Restarted pods mount the same volumes again through restarted kubelet
+	for _, p := range pods {
+		claimName := p.Spec.Volumes[0].PersistentVolumeClaim.ClaimName
+		pvName := volumes[p.Name]
+		if deleted := podClient.Delete(ctx, p).Sync(ctx); deleted.HasError() {
+			return delFunc, deleted.GetError()
+		}
+		start := time.Now()
+		restarted, err := krs.startPod(ctx, podClient, claimName, node)
+		if err != nil {
+			return delFunc, err
+		}
+		remount := time.Since(start)
+		log.Infof("Pod %s remounted %s in %s", restarted.Name, claimName, remount)
+		krs.samples = append(krs.samples, &store.LatencySample{Metric: "Remount after kubelet restart", Source: restarted.Name, Value: remount, Timestamp: time.Now()})
+		if err := krs.checkData(ctx, podClient, restarted, p.Name); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", restarted.Name, err))
+		}
+
+		table, err := readDiagMountTable(ctx, podClient, diag.Object)
+		if err != nil {
+			return delFunc, err
+		}
+		if n := countMounts(table, publishPathSuffix(string(p.UID), pvName)); n != 0 {
+			failed = append(failed, fmt.Sprintf("%s: volume of deleted pod is still mounted %d times", p.Name, n))
+		}
+		if n := countMounts(table, publishPathSuffix(string(restarted.UID), pvName)); n != 1 {
+			failed = append(failed, fmt.Sprintf("%s: volume is mounted %d times after remount", restarted.Name, n))
+		}
+	}
+
+	if len(failed) != 0 {
+		return delFunc, fmt.Errorf("volumes didn't survive kubelet restart: %s", strings.Join(failed, "; "))
+	}
+	return delFunc, nil
+}
+
+// startPod starts pod with the volume on the node and waits until it's ready, node is picked by scheduler if empty
+func (krs *KubeletRestartSuite) startPod(ctx context.Context, podClient *pod.Client, claim, node string) (*v1.Pod, error) {
+	conf := testcore.ProvisioningPodConfig([]string{claim}, "", krs.Image)
+	conf.NamePrefix = "kubelet-restart-"
+	p := podClient.MakePod(conf)
+	pinToNode(p, node)
+	created := podClient.Create(ctx, p)
+	if created.HasError() {
+		return nil, created.GetError()
+	}
+	return waitPodReady(ctx, podClient, created.Object.Name)
+}
+
+// checkData validates that volume mounted by the pod still has content written by the writer pod
+func (krs *KubeletRestartSuite) checkData(ctx context.Context, podClient *pod.Client, p *v1.Pod, writer string) error {
+	var stdout bytes.Buffer
+	if err := podClient.Exec(ctx, p, []string{"cat", "/data0/" + kubeletRestartFile}, &stdout, io.Discard, true); err != nil {
+		return fmt.Errorf("can't read volume: %v", err)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != writer {
+		return fmt.Errorf("volume has %q instead of %q", got, writer)
+	}
+	return nil
+}
+
+// GetLatencySamples returns kubelet downtime and remount latencies
+func (krs *KubeletRestartSuite) GetLatencySamples() []*store.LatencySample {
+	return krs.samples
+}
+
+// GetObservers returns all observers
+func (*KubeletRestartSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*KubeletRestartSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns kubelet restart suite namespace
+func (*KubeletRestartSuite) GetNamespace() string {
+	return "kubelet-restart-test"
+}
+
+// GetName returns kubelet restart suite name
+func (*KubeletRestartSuite) GetName() string {
+	return "KubeletRestartSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (krs *KubeletRestartSuite) Parameters() string {
+	return fmt.Sprintf("{pods: %d, size: %s, restartHook: %s}", krs.PodNumber, krs.VolumeSize, krs.RestartHook)
+}
+
+// MountRecoverySuite is used to manage mount failure recovery test suite, it starts pods while fault hook keeps
+// node from reaching backend and validates that kubelet and driver retries succeed once fault is cleared
+type MountRecoverySuite struct {
+	PodNumber  int
+	VolumeSize string
+	// FaultHook injects and clears fault for node passed in CERT_CSI_NODE, action is passed in CERT_CSI_FAULT
+	FaultHook string
+	// FaultDuration is how long fault is kept while pods are trying to mount their volumes
+	FaultDuration time.Duration
+	Image         string
+
+	results []*store.MountRecoveryResult
+	samples []*store.LatencySample
+}
+
+// Run executes mount failure recovery test suite
+func (mrs *MountRecoverySuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if mrs.FaultHook == "" {
+		return delFunc, errors.New("fault hook is required to inject mount failures")
+	}
+	if mrs.PodNumber <= 0 {
+		log.Info("Using default number of pods")
+		mrs.PodNumber = 2
+	}
+	if mrs.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		mrs.VolumeSize = "3Gi"
+	}
+	if mrs.FaultDuration <= 0 {
+		log.Info("Using default fault duration 1m")
+		mrs.FaultDuration = time.Minute
+	}
+	if mrs.Image == "" {
+		mrs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", mrs.Image)
+	}
+	mrs.results = nil
+	mrs.samples = nil
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	nodes, err := schedulableNodes(ctx, clients.NodeClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if len(nodes) == 0 {
+		return delFunc, errors.New("no schedulable node to inject mount fault on")
+	}
+	node := nodes[0]
+
+	// Volumes are provisioned before fault, so it disrupts only staging and publishing on the node
+	var claims []string
+	for i := 0; i < mrs.PodNumber; i++ {
+		claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, mrs.VolumeSize, "", "")))
+		if claim.HasError() {
+			return delFunc, claim.GetError()
+		}
+		claims = append(claims, claim.Object.Name)
+	}
+	wffc, err := shouldWaitForFirstConsumer(ctx, storageClass, pvcClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if !wffc {
+		if err := pvcClient.WaitForAllToBeBound(ctx); err != nil {
+			return delFunc, err
+		}
+	}
+
+	log.Infof("Injecting mount fault on node %s for %s", color.CyanString(node), mrs.FaultDuration)
+	if err := runFaultHook(ctx, mrs.FaultHook, MountFaultInject, node); err != nil {
+		return delFunc, err
+	}
+	cleared := false
+	defer func() {
+		if !cleared {
+			if err := runFaultHook(context.Background(), mrs.FaultHook, MountFaultRecover, node); err != nil {
+				log.Errorf("Can't clear mount fault on node %s; error=%v", node, err)
+			}
+		}
+	}()
+
+	results := make(map[string]*store.MountRecoveryResult)
+	created := make(map[string]time.Time)
+	for _, claim := range claims {
+		conf := testcore.ProvisioningPodConfig([]string{claim}, "", mrs.Image)
+		conf.NamePrefix = "mount-recovery-"
+		p := podClient.MakePod(conf)
+		pinToNode(p, node)
+		createdPod := podClient.Create(ctx, p)
+		if createdPod.HasError() {
+			return delFunc, createdPod.GetError()
+		}
+		results[createdPod.Object.Name] = &store.MountRecoveryResult{Pod: createdPod.Object.Name, Node: node}
+		created[createdPod.Object.Name] = time.Now()
+	}
+
+	select {
+	case <-ctx.Done():
+		return delFunc, ctx.Err()
+	case <-time.After(mrs.FaultDuration):
+	}
+	if err := runFaultHook(ctx, mrs.FaultHook, MountFaultRecover, node); err != nil {
+		return delFunc, err
+	}
+	cleared = true
+	clearedAt := time.Now()
+	log.Infof("Mount fault on node %s cleared, waiting for pods to recover", color.CyanString(node))
+
+	waitRecovered(ctx, podClient, results, created, clearedAt)
+	var failed []string
+	disrupted := false
+	for name, res := range results {
+		p, err := podClient.Interface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		res.Retries, res.FirstFailure = mountRetries(ctx, podClient, p)
+		disrupted = disrupted || res.Retries > 0
+		mrs.results = append(mrs.results, res)
+		if !res.Recovered {
+			failed = append(failed, fmt.Sprintf("%s isn't ready %s after fault was cleared, %d retries", name, MountRecoveryTimeout, res.Retries))
+			continue
+		}
+		log.Infof("Pod %s recovered %s after fault was cleared, %d retries", name, color.YellowString(res.Recovery.String()), res.Retries)
+		mrs.samples = append(mrs.samples,
+			&store.LatencySample{Metric: "Mount recovery after fault", Source: name, Value: res.Recovery, Timestamp: time.Now()},
+			&store.LatencySample{Metric: "Pod ready with mount fault", Source: name, Value: res.Total, Timestamp: time.Now()})
+	}
+	sort.Slice(mrs.results, func(i, j int) bool { return mrs.results[i].Pod < mrs.results[j].Pod })
+
+	if len(failed) != 0 {
+		return delFunc, fmt.Errorf("pods didn't recover from mount failures: %s", strings.Join(failed, "; "))
+	}
+	if !disrupted {
+		return delFunc, fmt.Errorf("no attach or mount failures were reported during fault, check that fault hook disrupts node %s", node)
+	}
+	return delFunc, nil
+}
+
+// GetMountRecoveryResults returns how every pod recovered from mount failures
+func (mrs *MountRecoverySuite) GetMountRecoveryResults() []*store.MountRecoveryResult {
+	return mrs.results
+}
+
+// GetLatencySamples returns recovery latencies of pods
+func (mrs *MountRecoverySuite) GetLatencySamples() []*store.LatencySample {
+	return mrs.samples
+}
+
+// GetObservers returns all observers
+func (*MountRecoverySuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*MountRecoverySuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns mount recovery suite namespace
+func (*MountRecoverySuite) GetNamespace() string {
+	return "mount-recovery-test"
+}
+
+// GetName returns mount recovery suite name
+func (*MountRecoverySuite) GetName() string {
+	return "MountRecoverySuite"
+}
+
+// Parameters returns formatted string of parameters
+func (mrs *MountRecoverySuite) Parameters() string {
+	return fmt.Sprintf("{pods: %d, size: %s, faultHook: %s, faultDuration: %s}", mrs.PodNumber, mrs.VolumeSize, mrs.FaultHook, mrs.FaultDuration)
+}
+
+// RWXConcurrentWriteSuite is used to manage RWX concurrent write test suite, writers spread over nodes by
+// anti-affinity append records to their own files of RWX volume at once and validator verifies no write was lost.
+// Shared file mode also lets all writers append to one file and records what was observed without failing
+type RWXConcurrentWriteSuite struct {
+	Writers    int
+	Appends    int
+	SharedFile bool
+	VolumeSize string
+	Image      string
+
+	results []*store.ConcurrentWriteResult
+	samples []*store.LatencySample
+}
+
+// Run executes RWX concurrent write test suite
+func (cws *RWXConcurrentWriteSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if cws.Writers <= 0 {
+		log.Info("Using default number of writers")
+		cws.Writers = 2
+	}
+	if cws.Appends <= 0 {
+		log.Info("Using default number of appends")
+		cws.Appends = 500
+	}
+	if cws.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		cws.VolumeSize = "3Gi"
+	}
+	if cws.Image == "" {
+		cws.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", cws.Image)
+	}
+	cws.results = nil
+	cws.samples = nil
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	nodes, err := schedulableNodes(ctx, clients.NodeClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if len(nodes) < 2 {
+		return delFunc, fmt.Errorf("concurrent writes need at least 2 schedulable nodes, found %d", len(nodes))
+	}
+	if cws.Writers > len(nodes) {
+		log.Warnf("Only %d schedulable nodes, using %d writers instead of %d", len(nodes), len(nodes), cws.Writers)
+		cws.Writers = len(nodes)
+	}
+
+	claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, cws.VolumeSize, "", "ReadWriteMany")))
+	if claim.HasError() {
+		return delFunc, claim.GetError()
+	}
+
+	var writers []*v1.Pod
+	for i := 0; i < cws.Writers; i++ {
+		conf := testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", cws.Image)
+		conf.NamePrefix = "rwx-writer-"
+		p := podClient.MakePod(conf)
+		spreadWriters(p)
+		writer := podClient.Create(ctx, p)
+		if writer.HasError() {
+			return delFunc, writer.GetError()
+		}
+		ready, err := waitPodReady(ctx, podClient, writer.Object.Name)
+		if err != nil {
+			return delFunc, err
+		}
+		writers = append(writers, ready)
+	}
+	conf := testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", cws.Image)
+	conf.NamePrefix = "rwx-validator-"
+	validator := podClient.Create(ctx, podClient.MakePod(conf))
+	if validator.HasError() {
+		return delFunc, validator.GetError()
+	}
+	validatorPod, err := waitPodReady(ctx, podClient, validator.Object.Name)
+	if err != nil {
+		return delFunc, err
+	}
+
+	mountPath := writers[0].Spec.Containers[0].VolumeMounts[0].MountPath
+	var names, writerNodes []string
+	for _, w := range writers {
+		names = append(names, w.Name)
+		writerNodes = append(writerNodes, w.Spec.NodeName)
+	}
+	log.Infof("Writers %s run on nodes %s", color.CyanString(strings.Join(names, ",")), color.CyanString(strings.Join(writerNodes, ",")))
+
+	// Every writer appends to its own file, validator reading through its own mount must find every record
+	files := make(map[string]string)
+	for _, w := range writers {
+		files[w.Name] = fmt.Sprintf("%s/%s", mountPath, w.Name)
+	}
+	if err := cws.appendConcurrently(ctx, podClient, writers, "Appends to own file", func(w *v1.Pod) string { return files[w.Name] }); err != nil {
+		return delFunc, err
+	}
+	var failed []string
+	for _, w := range writers {
+		res := &store.ConcurrentWriteResult{Mode: store.ConcurrentWriteDistinct, File: files[w.Name], Writers: w.Name, Nodes: w.Spec.NodeName}
+		if err := readRecords(ctx, podClient, validatorPod, res, []string{w.Name}, cws.Appends); err != nil {
+			return delFunc, err
+		}
+		cws.results = append(cws.results, res)
+		if !res.Passed {
+			failed = append(failed, describeWriteResult(res))
+			continue
+		}
+		log.Infof("All %d records of writer %s are intact", res.Expected, w.Name)
+	}
+
+	// Appends of different nodes to one file aren't guaranteed to be atomic on every filesystem, so what was
+	// observed is only recorded
+	if cws.SharedFile {
+		shared := mountPath + "/shared"
+		if err := cws.appendConcurrently(ctx, podClient, writers, "Appends to shared file", func(*v1.Pod) string { return shared }); err != nil {
+			return delFunc, err
+		}
+		res := &store.ConcurrentWriteResult{Mode: store.ConcurrentWriteShared, File: shared,
+			Writers: strings.Join(names, ","), Nodes: strings.Join(writerNodes, ",")}
+		if err := readRecords(ctx, podClient, validatorPod, res, names, cws.Appends); err != nil {
+			return delFunc, err
+		}
+		cws.results = append(cws.results, res)
+		log.Infof("Shared file appends observed: %s", describeWriteResult(res))
+	}
+
+	if len(failed) != 0 {
+		return delFunc, fmt.Errorf("writes to distinct files were lost or corrupted: %s", strings.Join(failed, "; "))
+	}
+	return delFunc, nil
+}
+
+// appendConcurrently makes every writer append its records to file at the same time and samples how long it took
+func (cws *RWXConcurrentWriteSuite) appendConcurrently(ctx context.Context, podClient *pod.Client, writers []*v1.Pod, metric string, file func(*v1.Pod) string) error {
+	samples := make([]*store.LatencySample, len(writers))
+	appends := errgroup.Group{}
+	for i, w := range writers {
+		i, w := i, w
+		appends.Go(func() error {
+			start := time.Now()
+			script := appendScript(w.Name, file(w), cws.Appends)
+			if err := podClient.Exec(ctx, w, []string{"/bin/bash", "-c", script}, io.Discard, io.Discard, true); err != nil {
+				return fmt.Errorf("writer %s failed to append to %s: %w", w.Name, file(w), err)
+			}
+			samples[i] = &store.LatencySample{
+				Metric:    metric,
+				Source:    w.Name,
+				Value:     time.Since(start),
+				Timestamp: start,
+			}
+			return nil
+		})
+	}
+	if err := appends.Wait(); err != nil {
+		return err
+	}
+	cws.samples = append(cws.samples, samples...)
+	return nil
+}
+
+// readRecords reads file through validator and validates records writers appended to it
+func readRecords(ctx context.Context, podClient *pod.Client, validator *v1.Pod, res *store.ConcurrentWriteResult, writers []string, records int) error {
+	var content bytes.Buffer
+	if err := podClient.Exec(ctx, validator, []string{"cat", res.File}, &content, io.Discard, true); err != nil {
+		return fmt.Errorf("validator can't read %s: %w", res.File, err)
+	}
+	validateRecords(res, content.Bytes(), writers, records)
+	return nil
+}
+
+// GetConcurrentWriteResults returns what validator found in every file
+func (cws *RWXConcurrentWriteSuite) GetConcurrentWriteResults() []*store.ConcurrentWriteResult {
+	return cws.results
+}
+
+// GetLatencySamples returns time every writer took to append its records
+func (cws *RWXConcurrentWriteSuite) GetLatencySamples() []*store.LatencySample {
+	return cws.samples
+}
+
+// GetObservers returns all observers
+func (*RWXConcurrentWriteSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*RWXConcurrentWriteSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns RWX concurrent write suite namespace
+func (*RWXConcurrentWriteSuite) GetNamespace() string {
+	return "rwx-concurrent-write-test"
+}
+
+// GetName returns RWX concurrent write suite name
+func (*RWXConcurrentWriteSuite) GetName() string {
+	return "RWXConcurrentWriteSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (cws *RWXConcurrentWriteSuite) Parameters() string {
+	return fmt.Sprintf("{writers: %d, appends: %d, sharedFile: %t, size: %s}", cws.Writers, cws.Appends, cws.SharedFile, cws.VolumeSize)
+}
+
+// ControllerFailoverSuite is used to manage controller failover test suite, it deletes leader pod of driver
+// controller or scales its Deployment down and up while volumes are being provisioned, verifies that leader election
+// hands leases over and provisioning resumes, and measures provisioning stall window from events
+type ControllerFailoverSuite struct {
+	DriverNamespace string
+	// Deployment is name of controller Deployment, the one running csi-provisioner is found if empty
+	Deployment string
+	// Disruption is either delete-leader or scale
+	Disruption     string
+	VolumeNumber   int
+	CreateInterval time.Duration
+	VolumeSize     string
+
+	results []*store.ControllerFailoverResult
+	samples []*store.LatencySample
+}
+
+// Run executes controller failover test suite
+func (cfs *ControllerFailoverSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if cfs.DriverNamespace == "" {
+		return delFunc, errors.New("driver namespace is required to find controller deployment and its leases")
+	}
+	if cfs.Disruption == "" {
+		cfs.Disruption = ControllerDeleteLeader
+	}
+	if cfs.Disruption != ControllerDeleteLeader && cfs.Disruption != ControllerScale {
+		return delFunc, fmt.Errorf("unknown disruption %s, use %s or %s", cfs.Disruption, ControllerDeleteLeader, ControllerScale)
+	}
+	if cfs.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		cfs.VolumeNumber = 10
+	}
+	if cfs.CreateInterval <= 0 {
+		log.Info("Using default create interval 2s")
+		cfs.CreateInterval = 2 * time.Second
+	}
+	if cfs.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		cfs.VolumeSize = "3Gi"
+	}
+	cfs.results = nil
+	cfs.samples = nil
+	pvcClient := clients.PVCClient
+	clientSet := clients.PodClient.ClientSet
+
+	// Stall is measured from provisioning events, which claims waiting for consumer wouldn't have
+	wffc, err := shouldWaitForFirstConsumer(ctx, storageClass, pvcClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if wffc {
+		return delFunc, fmt.Errorf("storage class %s waits for first consumer, use storage class with Immediate binding", storageClass)
+	}
+	sc, err := clientSet.StorageV1().StorageClasses().Get(ctx, storageClass, metav1.GetOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+
+	d, err := controllerDeployment(ctx, clientSet, cfs.DriverNamespace, cfs.Deployment)
+	if err != nil {
+		return delFunc, err
+	}
+	if err := waitDeploymentReady(ctx, clientSet, d.Namespace, d.Name); err != nil {
+		return delFunc, fmt.Errorf("controller deployment %s isn't ready before disruption: %w", d.Name, err)
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	if cfs.Disruption == ControllerDeleteLeader && replicas < 2 {
+		log.Warnf("Controller deployment %s has a single replica, leader is replaced by a new pod instead of a standby", d.Name)
+	}
+	pods, err := deploymentPods(ctx, clientSet, d)
+	if err != nil {
+		return delFunc, err
+	}
+	leases, err := controllerLeases(ctx, clientSet, d.Namespace, pods)
+	if err != nil {
+		return delFunc, err
+	}
+	lease, err := provisionerLease(leases, sc.Provisioner)
+	if err != nil {
+		return delFunc, fmt.Errorf("can't find provisioner lease of deployment %s: %w", d.Name, err)
+	}
+	leader := leaseHolder(leases[lease], pods)
+
+	// Deleted leader has to hand over only leases it holds, scaled down Deployment has to hand over all of them
+	disrupted := make(map[string]string)
+	var names []string
+	for name, holder := range leases {
+		if cfs.Disruption == ControllerScale || leaseHolder(holder, pods) == leader {
+			disrupted[name] = holder
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	res := &store.ControllerFailoverResult{
+		Deployment: d.Name,
+		Disruption: cfs.Disruption,
+		Replicas:   int(replicas),
+		Leases:     strings.Join(names, ","),
+		Lease:      lease,
+		OldLeader:  leases[lease],
+		Volumes:    cfs.VolumeNumber,
+	}
+	cfs.results = append(cfs.results, res)
+	log.Infof("Pod %s of %s holds %d leases, provisioner lease is %s", color.CyanString(leader), d.Name, len(names), color.CyanString(lease))
+
+	// Claims are created one by one, so provisioning is in progress before, during and after disruption
+	halfway := make(chan struct{})
+	created := make(chan error, 1)
+	go func() {
+		defer close(created)
+		for i := 0; i < cfs.VolumeNumber; i++ {
+			if i == cfs.VolumeNumber/2 {
+				close(halfway)
+			}
+			claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, cfs.VolumeSize, "", "")))
+			if claim.HasError() {
+				created <- claim.GetError()
+				return
+			}
+			select {
+			case <-ctx.Done():
+				created <- ctx.Err()
+				return
+			case <-time.After(cfs.CreateInterval):
+			}
+		}
+	}()
+	select {
+	case <-halfway:
+	case err := <-created:
+		return delFunc, err
+	}
+
+	disruptedAt := time.Now()
+	if cfs.Disruption == ControllerDeleteLeader {
+		log.Infof("Deleting controller leader pod %s", color.CyanString(leader))
+		if err := clientSet.CoreV1().Pods(d.Namespace).Delete(ctx, leader, metav1.DeleteOptions{}); err != nil {
+			return delFunc, err
+		}
+	} else {
+		log.Infof("Scaling controller deployment %s down from %d replicas", color.CyanString(d.Name), replicas)
+		if err := scaleDeployment(ctx, clientSet, d.Namespace, d.Name, 0); err != nil {
+			return delFunc, err
+		}
+		restored := false
+		defer func() {
+			if !restored {
+				if err := scaleDeployment(context.Background(), clientSet, d.Namespace, d.Name, replicas); err != nil {
+					log.Errorf("Can't scale controller deployment %s back to %d replicas; error=%v", d.Name, replicas, err)
+				}
+			}
+		}()
+		err := commonparams.PollUntil(ctx, ControllerFailoverPoll, LeaderHandoverTimeout, nil, func(context.Context) (bool, error) {
+			running, err := deploymentPods(ctx, clientSet, d)
+			return err == nil && len(running) == 0, nil
+		})
+		if err != nil {
+			return delFunc, fmt.Errorf("pods of controller deployment %s didn't stop after scale down: %w", d.Name, err)
+		}
+		log.Infof("Scaling controller deployment %s back up to %d replicas", color.CyanString(d.Name), replicas)
+		if err := scaleDeployment(ctx, clientSet, d.Namespace, d.Name, replicas); err != nil {
+			return delFunc, err
+		}
+		restored = true
+	}
+
+	var failed []string
+	holders, err := waitHandedOver(ctx, clientSet, d, disrupted)
+	res.Handover = time.Since(disruptedAt)
+	res.HandedOver = err == nil
+	res.NewLeader = holders[lease]
+	if res.HandedOver {
+		log.Infof("Leases handed over %s after disruption, provisioner lease is held by %s", color.YellowString(res.Handover.String()), color.CyanString(res.NewLeader))
+		cfs.samples = append(cfs.samples, &store.LatencySample{Metric: "Leader handover", Source: d.Name, Value: res.Handover, Timestamp: time.Now()})
+	} else {
+		var left []string
+		for _, name := range names {
+			if holders[name] == "" || holders[name] == disrupted[name] {
+				left = append(left, name)
+			}
+		}
+		failed = append(failed, fmt.Sprintf("leases %s weren't handed over in %s", strings.Join(left, ","), LeaderHandoverTimeout))
+	}
+
+	if err := <-created; err != nil {
+		return delFunc, err
+	}
+	if err := pvcClient.WaitForAllToBeBound(ctx); err != nil {
+		failed = append(failed, fmt.Sprintf("provisioning didn't resume: %v", err))
+	}
+	claims, err := pvcClient.Interface.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+	for _, claim := range claims.Items {
+		if claim.Status.Phase == v1.ClaimBound {
+			res.Provisioned++
+		}
+	}
+	provisioned, err := provisionedAt(ctx, clientSet, pvcClient.Namespace)
+	if err != nil {
+		return delFunc, err
+	}
+	res.Stall = provisioningStall(provisioned, disruptedAt, time.Now())
+	log.Infof("%d of %d volumes provisioned, provisioning stalled for %s", res.Provisioned, res.Volumes, color.YellowString(res.Stall.String()))
+	cfs.samples = append(cfs.samples, &store.LatencySample{Metric: "Provisioning stall", Source: d.Name, Value: res.Stall, Timestamp: time.Now()})
+
+	if err := waitDeploymentReady(ctx, clientSet, d.Namespace, d.Name); err != nil {
+		failed = append(failed, fmt.Sprintf("controller deployment %s isn't ready after disruption: %v", d.Name, err))
+	}
+	res.Passed = len(failed) == 0
+	if !res.Passed {
+		return delFunc, fmt.Errorf("controller didn't recover from %s: %s", cfs.Disruption, strings.Join(failed, "; "))
+	}
+	return delFunc, nil
+}
+
+// GetControllerFailoverResults returns how controller recovered from disruption
+func (cfs *ControllerFailoverSuite) GetControllerFailoverResults() []*store.ControllerFailoverResult {
+	return cfs.results
+}
+
+// GetLatencySamples returns leader handover and provisioning stall latencies
+func (cfs *ControllerFailoverSuite) GetLatencySamples() []*store.LatencySample {
+	return cfs.samples
+}
+
+// GetObservers returns all observers
+func (*ControllerFailoverSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va and metrics clients
+func (*ControllerFailoverSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+	}, nil
+}
+
+// GetNamespace returns controller failover suite namespace
+func (*ControllerFailoverSuite) GetNamespace() string {
+	return "controller-failover-test"
+}
+
+// GetName returns controller failover suite name
+func (*ControllerFailoverSuite) GetName() string {
+	return "ControllerFailoverSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (cfs *ControllerFailoverSuite) Parameters() string {
+	return fmt.Sprintf("{driverNamespace: %s, deployment: %s, disruption: %s, volumes: %d, createInterval: %s, size: %s}",
+		cfs.DriverNamespace, cfs.Deployment, cfs.Disruption, cfs.VolumeNumber, cfs.CreateInterval, cfs.VolumeSize)
+}
+
+// ReadAfterDetachSuite is used to manage read-after-detach test suite, it detaches volumes written on one node,
+// probes that node for mounts and devices left behind and verifies data once volumes are reattached elsewhere
+type ReadAfterDetachSuite struct {
+	VolumeNumber int
+	VolumeSize   string
+	Image        string
+	RawBlock     bool
+
+	results []*store.DetachProbeResult
+}
+
+// Run executes read-after-detach test suite
+func (rads *ReadAfterDetachSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if rads.VolumeNumber <= 0 {
+		log.Info("Using default number of volumes")
+		rads.VolumeNumber = 1
+	}
+	if rads.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		rads.VolumeSize = "3Gi"
+	}
+	if rads.Image == "" {
+		rads.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", rads.Image)
+	}
+	rads.results = nil
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	nodes, err := schedulableNodes(ctx, clients.NodeClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if len(nodes) == 0 {
+		return delFunc, errors.New("no schedulable node to attach volumes to")
+	}
+	oldNode, newNode := nodes[0], nodes[0]
+	if len(nodes) > 1 {
+		newNode = nodes[1]
+	} else {
+		log.Warnf("Only node %s is schedulable, volumes are reattached to the same node", oldNode)
+	}
+
+	var claims []string
+	for i := 0; i < rads.VolumeNumber; i++ {
+		vcconf := testcore.VolumeCreationConfig(storageClass, rads.VolumeSize, "", "")
+		vcconf.NamePrefix = "vol-detach-test-"
+		if rads.RawBlock {
+			var mode v1.PersistentVolumeMode = pvc.Block
+			vcconf.VolumeMode = &mode
+		}
+		claim := pvcClient.Create(ctx, pvcClient.MakePVC(vcconf))
+		if claim.HasError() {
+			return delFunc, claim.GetError()
+		}
+		claims = append(claims, claim.Object.Name)
+	}
+
+	podconf := testcore.ProvisioningPodConfig(claims, "", rads.Image)
+	podconf.NamePrefix = "detach-writer-"
+	if rads.RawBlock {
+		podconf.VolumeMode = pod.Block
+		podconf.Capabilities = []v1.Capability{"SYS_ADMIN"}
+	}
+	paths := make([]string, len(claims))
+	for i := range claims {
+		if rads.RawBlock {
+			paths[i] = "/dev" + podconf.MountPath + strconv.Itoa(i)
+		} else {
+			paths[i] = podconf.MountPath + strconv.Itoa(i)
+		}
+	}
+
+	writerTmpl := podClient.MakePod(podconf)
+	pinToNode(writerTmpl, oldNode)
+	writer := podClient.Create(ctx, writerTmpl)
+	if writer.HasError() {
+		return delFunc, writer.GetError()
+	}
+	if err := writer.WaitForRunning(ctx); err != nil {
+		return delFunc, err
+	}
+
+	// Data is written through the page cache and synced, so what reader sees must come from the backend
+	sums := make([]string, len(claims))
+	devices := make([]string, len(claims))
+	for i, path := range paths {
+		file := path + "/detach-test"
+		write := fmt.Sprintf("dd if=/dev/urandom of=%[1]s bs=1M count=16 && sync && sha512sum %[1]s", file)
+		if rads.RawBlock {
+			write = fmt.Sprintf("dd if=/dev/urandom of=/tmp/blob bs=1M count=16 && dd if=/tmp/blob of=%s bs=1M oflag=direct && sha512sum /tmp/blob", path)
+		}
+		var stdout, stderr bytes.Buffer
+		if err := podClient.Exec(ctx, writer.Object, []string{"/bin/bash", "-c", write}, &stdout, &stderr, true); err != nil {
+			return delFunc, fmt.Errorf("can't write data to %s: %w: %s", claims[i], err, stderr.String())
+		}
+		sums[i] = strings.Fields(stdout.String() + " ")[0]
+		if devices[i], err = volumeDevice(ctx, podClient, writer.Object, path, rads.RawBlock); err != nil {
+			return delFunc, err
+		}
+	}
+
+	pvNames := make([]string, len(claims))
+	for i, claim := range claims {
+		bound, err := pvcClient.Interface.Get(ctx, claim, metav1.GetOptions{})
+		if err != nil {
+			return delFunc, err
+		}
+		pvNames[i] = bound.Spec.VolumeName
+	}
+
+	log.Infof("Detaching %d volumes from node %s", len(claims), color.CyanString(oldNode))
+	deleted := time.Now()
+	if err := podClient.Delete(ctx, writer.Object).Sync(ctx).GetError(); err != nil {
+		return delFunc, err
+	}
+	for i, claim := range claims {
+		res := &store.DetachProbeResult{PVC: claim, OldNode: oldNode, NewNode: newNode, Device: devices[i]}
+		if err := waitForAttachment(ctx, clients.VaClient, pvNames[i], oldNode, false); err != nil {
+			return delFunc, fmt.Errorf("volume %s wasn't detached from node %s: %w", claim, oldNode, err)
+		}
+		res.Detach = time.Since(deleted)
+		rads.results = append(rads.results, res)
+	}
+
+	// Old node is probed before reattach, so a device there can't belong to the new attachment
+	diag := podClient.Create(ctx, podClient.MakeDiagnosticPod(oldNode, rads.Image))
+	if diag.HasError() {
+		return delFunc, diag.GetError()
+	}
+	defer func() {
+		podClient.Delete(context.Background(), diag.Object)
+	}()
+	if err := diag.WaitForRunning(ctx); err != nil {
+		return delFunc, err
+	}
+	table, err := readDiagMountTable(ctx, podClient, diag.Object)
+	if err != nil {
+		return delFunc, err
+	}
+	for i, res := range rads.results {
+		res.StaleMounts = strings.Join(staleMounts(table, pvNames[i]), ",")
+		res.DeviceState = DeviceGone
+		if res.Device != "" {
+			if res.DeviceState, err = probeDevice(ctx, podClient, diag.Object, res.Device); err != nil {
+				return delFunc, err
+			}
+		}
+	}
+
+	log.Infof("Reattaching %d volumes to node %s", len(claims), color.CyanString(newNode))
+	podconf.NamePrefix = "detach-reader-"
+	readerTmpl := podClient.MakePod(podconf)
+	pinToNode(readerTmpl, newNode)
+	reader := podClient.Create(ctx, readerTmpl)
+	if reader.HasError() {
+		return delFunc, reader.GetError()
+	}
+	if err := reader.WaitForRunning(ctx); err != nil {
+		return delFunc, err
+	}
+
+	var failed []string
+	for i, res := range rads.results {
+		read := fmt.Sprintf("sha512sum %s/detach-test", paths[i])
+		if rads.RawBlock {
+			read = fmt.Sprintf("dd if=%s bs=1M count=16 iflag=direct 2>/dev/null | sha512sum", paths[i])
+		}
+		var stdout, stderr bytes.Buffer
+		if err := podClient.Exec(ctx, reader.Object, []string{"/bin/bash", "-c", read}, &stdout, &stderr, true); err != nil {
+			log.Errorf("Can't read data of %s on node %s: %v: %s", res.PVC, newNode, err, stderr.String())
+		} else {
+			res.Consistent = strings.Fields(stdout.String() + " ")[0] == sums[i]
+		}
+		res.Passed = res.Consistent && res.StaleMounts == "" && res.DeviceState != DeviceReadable
+
+		var problems []string
+		if res.StaleMounts != "" {
+			problems = append(problems, "stale mounts "+res.StaleMounts)
+		}
+		if res.DeviceState == DeviceReadable {
+			problems = append(problems, fmt.Sprintf("device %s still readable", res.Device))
+		}
+		if !res.Consistent {
+			problems = append(problems, "data differs after reattach")
+		}
+		if len(problems) != 0 {
+			failed = append(failed, fmt.Sprintf("%s: %s", res.PVC, strings.Join(problems, ", ")))
+			continue
+		}
+		log.Infof("Volume %s detached from node %s in %s, device %s, data consistent on node %s",
+			res.PVC, oldNode, color.YellowString(res.Detach.String()), res.DeviceState, newNode)
+	}
+
+	if len(failed) != 0 {
+		return delFunc, fmt.Errorf("volumes weren't cleaned up on node %s after detach: %s", oldNode, strings.Join(failed, "; "))
+	}
+	return delFunc, nil
+}
+
+// GetDetachProbeResults returns what was left of every volume on old node and whether its data survived reattach
+func (rads *ReadAfterDetachSuite) GetDetachProbeResults() []*store.DetachProbeResult {
+	return rads.results
+}
+
+// GetObservers returns all observers
+func (*ReadAfterDetachSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*ReadAfterDetachSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns read-after-detach suite namespace
+func (*ReadAfterDetachSuite) GetNamespace() string {
+	return "read-after-detach-test"
+}
+
+// GetName returns read-after-detach suite name
+func (*ReadAfterDetachSuite) GetName() string {
+	return "ReadAfterDetachSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (rads *ReadAfterDetachSuite) Parameters() string {
+	return fmt.Sprintf("{volumes: %d, size: %s, raw-block: %s}", rads.VolumeNumber, rads.VolumeSize, strconv.FormatBool(rads.RawBlock))
+}
+
+// NetworkPartitionSuite is used to manage storage network partition test suite, writers keep doing IO on a node
+// while fault hook disrupts it at steps of fault plan, ex. by blocking its storage VLAN, and IO errors and recovery
+// of every fault window are measured
+type NetworkPartitionSuite struct {
+	PodNumber  int
+	VolumeSize string
+	Image      string
+	// FaultHook injects and clears fault for node passed in CERT_CSI_NODE, action is passed in CERT_CSI_FAULT
+	FaultHook string
+	// Plan lists when faults are injected and how long they last, DefaultFaultPlan if empty
+	Plan []FaultStep
+	// Settle is how long writers keep running after the last fault is cleared
+	Settle time.Duration
+
+	results []*store.FaultWindow
+	samples []*store.LatencySample
+	tags    []*observer.Tag
+}
+
+// Run executes storage network partition test suite
+func (nps *NetworkPartitionSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if nps.FaultHook == "" {
+		return delFunc, errors.New("fault hook is required to partition node from storage network")
+	}
+	if nps.PodNumber <= 0 {
+		log.Info("Using default number of pods")
+		nps.PodNumber = 1
+	}
+	if nps.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		nps.VolumeSize = "3Gi"
+	}
+	if nps.Image == "" {
+		nps.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", nps.Image)
+	}
+	if len(nps.Plan) == 0 {
+		nps.Plan = DefaultFaultPlan
+	}
+	if nps.Settle <= 0 {
+		log.Info("Using default settle time 1m")
+		nps.Settle = time.Minute
+	}
+	nps.results = nil
+	nps.samples = nil
+	nps.tags = nil
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	nodes, err := schedulableNodes(ctx, clients.NodeClient)
+	if err != nil {
+		return delFunc, err
+	}
+	if len(nodes) == 0 {
+		return delFunc, errors.New("no schedulable node to partition")
+	}
+	node := nodes[0]
+
+	const ioLog = "/tmp/partition-io.log"
+	var writers []*v1.Pod
+	for i := 0; i < nps.PodNumber; i++ {
+		claim := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, nps.VolumeSize, "", "")))
+		if claim.HasError() {
+			return delFunc, claim.GetError()
+		}
+		conf := testcore.ProvisioningPodConfig([]string{claim.Object.Name}, "", nps.Image)
+		conf.NamePrefix = "partition-writer-"
+		conf.Args = []string{"-c", faultWriterScript(conf.MountPath+"0/partition-io", ioLog)}
+		p := podClient.MakePod(conf)
+		pinToNode(p, node)
+		writer := podClient.Create(ctx, p)
+		if writer.HasError() {
+			return delFunc, writer.GetError()
+		}
+		if err := writer.WaitForRunning(ctx); err != nil {
+			return delFunc, err
+		}
+		writers = append(writers, writer.Object)
+	}
+
+	injected := false
+	defer func() {
+		if injected {
+			if err := runFaultHook(context.Background(), nps.FaultHook, MountFaultRecover, node); err != nil {
+				log.Errorf("Can't clear fault on node %s; error=%v", node, err)
+			}
+		}
+	}()
+	wait := func(until time.Time) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(until)):
+			return nil
+		}
+	}
+	tag := func(t store.EventTypeEnum, msg string) {
+		for _, w := range writers {
+			nps.tags = append(nps.tags, observer.NewTag(w.Name, t, msg))
+		}
+	}
+
+	started := time.Now()
+	for i, step := range nps.Plan {
+		if err := wait(started.Add(step.At)); err != nil {
+			return delFunc, err
+		}
+		log.Infof("Injecting fault %d on node %s for %s", i+1, color.CyanString(node), step.Duration)
+		if err := runFaultHook(ctx, nps.FaultHook, MountFaultInject, node); err != nil {
+			return delFunc, err
+		}
+		injected = true
+		fw := &store.FaultWindow{Step: i + 1, Node: node, Start: time.Now()}
+		tag(store.FaultInjected, fmt.Sprintf("fault %d injected on node %s", i+1, node))
+
+		if err := wait(fw.Start.Add(step.Duration)); err != nil {
+			return delFunc, err
+		}
+		if err := runFaultHook(ctx, nps.FaultHook, MountFaultRecover, node); err != nil {
+			return delFunc, err
+		}
+		injected = false
+		fw.End = time.Now()
+		tag(store.FaultCleared, fmt.Sprintf("fault %d cleared on node %s after %s", i+1, node, fw.End.Sub(fw.Start).Round(time.Second)))
+		nps.results = append(nps.results, fw)
+	}
+	if err := wait(time.Now().Add(nps.Settle)); err != nil {
+		return delFunc, err
+	}
+
+	var ops [][]ioOp
+	for _, w := range writers {
+		var stdout, stderr bytes.Buffer
+		if err := podClient.Exec(ctx, w, []string{"cat", ioLog}, &stdout, &stderr, true); err != nil {
+			return delFunc, fmt.Errorf("can't read IO log of writer %s: %w: %s", w.Name, err, stderr.String())
+		}
+		ops = append(ops, parseIOLog(stdout.String()))
+	}
+
+	var failed []string
+	disrupted := false
+	for i, fw := range nps.results {
+		until := time.Now()
+		if i+1 < len(nps.results) {
+			until = nps.results[i+1].Start
+		}
+		fillFaultWindow(fw, ops, until)
+		disrupted = disrupted || fw.Errors > 0
+		if !fw.Recovered {
+			failed = append(failed, fmt.Sprintf("writers didn't recover from fault %d, %d of %d writes failed after it was cleared", fw.Step, fw.ErrorsAfter, fw.OpsAfter))
+			continue
+		}
+		log.Infof("Fault %d: %d of %d writes failed, writers recovered %s after fault was cleared",
+			fw.Step, fw.Errors, fw.Ops, color.YellowString(fw.Recovery.String()))
+		nps.samples = append(nps.samples, &store.LatencySample{Metric: "IO recovery after fault", Source: fmt.Sprintf("fault %d", fw.Step), Value: fw.Recovery, Timestamp: time.Now()})
+	}
+
+	if len(failed) != 0 {
+		return delFunc, fmt.Errorf("IO didn't recover from storage network partition: %s", strings.Join(failed, "; "))
+	}
+	if !disrupted {
+		return delFunc, fmt.Errorf("no writes failed during faults, check that fault hook disrupts storage network of node %s", node)
+	}
+	return delFunc, nil
+}
+
+// GetFaultWindows returns injected faults with IO of writers during and after them
+func (nps *NetworkPartitionSuite) GetFaultWindows() []*store.FaultWindow {
+	return nps.results
+}
+
+// GetLatencySamples returns IO recovery latencies of faults
+func (nps *NetworkPartitionSuite) GetLatencySamples() []*store.LatencySample {
+	return nps.samples
+}
+
+// GetTags returns fault windows as events of writer pods, so they show up on pod timelines
+func (nps *NetworkPartitionSuite) GetTags() []*observer.Tag {
+	return nps.tags
+}
+
+// GetObservers returns all observers
+func (*NetworkPartitionSuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients returns pvc, pod, va, metrics and node clients
+func (*NetworkPartitionSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	nodeClient, nodeErr := client.CreateNodeClient()
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	return &k8sclient.Clients{
+		PVCClient:         pvcClient,
+		PodClient:         podClient,
+		VaClient:          vaClient,
+		StatefulSetClient: nil,
+		MetricsClient:     metricsClient,
+		NodeClient:        nodeClient,
+	}, nil
+}
+
+// GetNamespace returns network partition suite namespace
+func (*NetworkPartitionSuite) GetNamespace() string {
+	return "network-partition-test"
+}
+
+// GetName returns network partition suite name
+func (*NetworkPartitionSuite) GetName() string {
+	return "NetworkPartitionSuite"
+}
+
+// Parameters returns formatted string of parameters
+func (nps *NetworkPartitionSuite) Parameters() string {
+	steps := make([]string, len(nps.Plan))
+	for i, step := range nps.Plan {
+		steps[i] = fmt.Sprintf("%s:%s", step.At, step.Duration)
+	}
+	return fmt.Sprintf("{pods: %d, size: %s, faultHook: %s, plan: %s, settle: %s}", nps.PodNumber, nps.VolumeSize, nps.FaultHook, strings.Join(steps, ","), nps.Settle)
+}
+
+// CrossNamespaceRestoreSuite is used to manage cross-namespace snapshot restore test suite
+type CrossNamespaceRestoreSuite struct {
+	SnapClass  string
+	VolumeSize string
+	Image      string
+
+	comparisons []*store.Comparison
+}
+
+// Run snapshots volume, restores it in the same and in another namespace and compares how long restores take
+func (cns *CrossNamespaceRestoreSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if cns.VolumeSize == "" {
+		log.Info("Using default volume size 3Gi")
+		cns.VolumeSize = "3Gi"
+	}
+	if cns.Image == "" {
+		cns.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", cns.Image)
+	}
+	cns.comparisons = nil
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	pvc := pvcClient.Create(ctx, pvcClient.MakePVC(testcore.VolumeCreationConfig(storageClass, cns.VolumeSize, "", "")))
+	if pvc.HasError() {
+		return delFunc, pvc.GetError()
+	}
+	namespace := pvc.Object.Namespace
+
+	podconf := testcore.IoWritePodConfig([]string{pvc.Object.Name}, "", cns.Image)
+	file := fmt.Sprintf("%s0/writer-%d.data", podconf.MountPath, 0)
+	sum := fmt.Sprintf("%s0/writer-%d.sha512", podconf.MountPath, 0)
+	writerPod := podClient.Create(ctx, podClient.MakePod(podconf)).Sync(ctx)
+	if writerPod.HasError() {
+		return delFunc, writerPod.GetError()
+	}
+	if err := podClient.Exec(ctx, writerPod.Object, []string{"dd", "if=/dev/urandom", "of=" + file, "bs=1M", "count=64", "oflag=sync"}, io.Discard, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", "sha512sum " + file + " > " + sum}, os.Stdout, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	podClient.Delete(ctx, writerPod.Object).Sync(ctx)
+	if writerPod.HasError() {
+		return delFunc, writerPod.GetError()
+	}
+
+	snap := clients.SnapClientGA.Create(ctx, &snapv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: DefaultSnapPrefix + "-",
+			Namespace:    namespace,
+		},
+		Spec: snapv1.VolumeSnapshotSpec{
+			Source:                  snapv1.VolumeSnapshotSource{PersistentVolumeClaimName: &pvc.Object.Name},
+			VolumeSnapshotClassName: &cns.SnapClass,
+		},
+	})
+	if snap.HasError() {
+		return delFunc, snap.GetError()
+	}
+	if err := snap.WaitForRunning(ctx); err != nil {
+		return delFunc, err
+	}
+
+	log.Infof("Restoring %s in namespace %s", snap.Name(), color.CyanString(namespace))
+	sameCfg := testcore.VolumeCreationConfig(storageClass, cns.VolumeSize, "", "")
+	sameCfg.SnapName = snap.Name()
+	sameTime, err := cns.restore(ctx, pvcClient, podClient, sameCfg, sum)
+	if err != nil {
+		return delFunc, fmt.Errorf("same-namespace restore failed: %w", err)
+	}
+
+	// Target namespace isn't managed by runner, so it's deleted by callback together with the grant
+	targetNamespace := namespace + "-target"
+	if _, err := clients.KubeClient.CreateNamespace(ctx, targetNamespace); err != nil {
+		return delFunc, err
+	}
+	var grant *unstructured.Unstructured
+	delFunc = func() error {
+		var errs []string
+		if grant != nil {
+			if err := clients.RefGrantClient.Delete(context.Background(), grant); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if err := clients.KubeClient.DeleteNamespace(context.Background(), targetNamespace); err != nil && !apierrs.IsNotFound(err) {
+			errs = append(errs, err.Error())
+		}
+		if len(errs) != 0 {
+			return fmt.Errorf("can't clean up cross-namespace restore: %s", strings.Join(errs, "; "))
+		}
+		return nil
+	}
+	grant, err = clients.RefGrantClient.Create(ctx, namespace, "cert-csi-"+targetNamespace, targetNamespace)
+	if err != nil {
+		return delFunc, err
+	}
+
+	targetPVCClient, err := clients.KubeClient.CreatePVCClient(targetNamespace)
+	if err != nil {
+		return delFunc, err
+	}
+	targetPodClient, err := clients.KubeClient.CreatePodClient(targetNamespace)
+	if err != nil {
+		return delFunc, err
+	}
+	log.Infof("Restoring %s in namespace %s", snap.Name(), color.CyanString(targetNamespace))
+	crossCfg := testcore.VolumeCreationConfig(storageClass, cns.VolumeSize, "", "")
+	crossCfg.SnapName = snap.Name()
+	crossCfg.SnapNamespace = namespace
+	crossTime, err := cns.restore(ctx, targetPVCClient, targetPodClient, crossCfg, sum)
+	if err != nil {
+		return delFunc, fmt.Errorf("cross-namespace restore failed: %w", err)
+	}
+
+	c := &store.Comparison{
+		Metric:         "Restore to pod ready",
+		Baseline:       "same namespace",
+		BaselineValue:  sameTime,
+		Candidate:      "cross namespace",
+		CandidateValue: crossTime,
+	}
+	log.Infof("%s overhead of %s: %s", c.Metric, c.Candidate, color.YellowString(c.Difference().String()))
+	cns.comparisons = append(cns.comparisons, c)
+	return delFunc, nil
+}
 
-				// WAIT FOR VA TO BE DELETED
-				err := vaClient.WaitUntilVaGone(ctx, pvName)
-				if err != nil {
-					return err
-				}
-			}
-			return nil
-		})
+// restore creates PVC from snapshot with a pod using it, verifies restored data and returns time it took the pod to become ready
+func (cns *CrossNamespaceRestoreSuite) restore(ctx context.Context, pvcClient *pvc.Client, podClient *pod.Client, cfg *pvc.Config, sum string) (time.Duration, error) {
+	start := time.Now()
+	restored := pvcClient.Create(ctx, pvcClient.MakePVC(cfg))
+	if restored.HasError() {
+		return 0, restored.GetError()
+	}
+	// API server silently drops namespace of data source if the feature is disabled
+	if cfg.SnapNamespace != "" && (restored.Object.Spec.DataSourceRef == nil || restored.Object.Spec.DataSourceRef.Namespace == nil) {
+		return 0, fmt.Errorf("namespace of data source was dropped, is CrossNamespaceVolumeDataSource feature gate enabled")
 	}
 
-	return delFunc, errs.Wait()
+	checker := podClient.Create(ctx, podClient.MakePod(testcore.IoWritePodConfig([]string{restored.Object.Name}, "", cns.Image))).Sync(ctx)
+	if checker.HasError() {
+		return 0, checker.GetError()
+	}
+	elapsed := time.Since(start)
+
+	checkRes := bytes.NewBufferString("")
+	if err := podClient.Exec(ctx, checker.Object, []string{"/bin/bash", "-c", "sha512sum -c " + sum}, checkRes, os.Stderr, false); err != nil {
+		return elapsed, fmt.Errorf("restored data doesn't match snapshot data: %w", err)
+	}
+	if !strings.Contains(checkRes.String(), "OK") {
+		return elapsed, fmt.Errorf("restored data doesn't match snapshot data")
+	}
+	utils.GetLoggerFromContext(ctx).Infof("Restored volume %s/%s is ready in %s, hashes match", restored.Object.Namespace,
+		restored.Object.Name, color.HiYellowString(elapsed.String()))
+	return elapsed, nil
+}
+
+// GetComparisons returns restore latency in another namespace compared to the same one
+func (cns *CrossNamespaceRestoreSuite) GetComparisons() []*store.Comparison {
+	return cns.comparisons
 }
 
 // GetObservers returns all observers
-func (*VolumeIoSuite) GetObservers(obsType observer.Type) []observer.Interface {
+func (*CrossNamespaceRestoreSuite) GetObservers(obsType observer.Type) []observer.Interface {
 	return getAllObservers(obsType)
 }
 
-// GetClients returns pvc, pod, va, metrics clients
-func (*VolumeIoSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+// GetClients returns pvc, pod, va, metrics, snapshot and ReferenceGrant clients
+func (cns *CrossNamespaceRestoreSuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	if ok, err := client.SnapshotClassExists(cns.SnapClass); !ok {
+		return nil, fmt.Errorf("snapshotclass class doesn't exist; error = %v", err)
+	}
+	refGrantClient, err := client.CreateReferenceGrantClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := refGrantClient.Installed(context.Background()); err != nil {
+		return nil, err
+	}
+
 	pvcClient, pvcErr := client.CreatePVCClient(namespace)
 	if pvcErr != nil {
 		return nil, pvcErr
@@ -1154,29 +5968,43 @@ func (*VolumeIoSuite) GetClients(namespace string, client *k8sclient.KubeClient)
 		return nil, mcErr
 	}
 
+	snapGA, _, snErr := GetSnapshotClient(namespace, client)
+	if snErr != nil {
+		return nil, snErr
+	}
+	if snapGA == nil {
+		return nil, fmt.Errorf("cross-namespace restore requires GA VolumeSnapshot API")
+	}
+
 	return &k8sclient.Clients{
-		PVCClient:         pvcClient,
-		PodClient:         podClient,
-		VaClient:          vaClient,
-		StatefulSetClient: nil,
-		MetricsClient:     metricsClient,
+		PVCClient:      pvcClient,
+		PodClient:      podClient,
+		VaClient:       vaClient,
+		MetricsClient:  metricsClient,
+		SnapClientGA:   snapGA,
+		KubeClient:     client,
+		RefGrantClient: refGrantClient,
 	}, nil
 }
 
-// GetNamespace returns volume IO test suite namespace
-func (*VolumeIoSuite) GetNamespace() string {
-	return "volumeio-test"
+// GetNamespace returns cross-namespace restore suite namespace
+func (*CrossNamespaceRestoreSuite) GetNamespace() string {
+	return "cross-ns-restore-test"
 }
 
-// GetName returns volume IO test suite name
-func (*VolumeIoSuite) GetName() string {
-	return "VolumeIoSuite"
+// GetName returns cross-namespace restore suite name
+func (*CrossNamespaceRestoreSuite) GetName() string {
+	return "CrossNamespaceRestoreSuite"
 }
 
 // Parameters returns formatted string of parameters
-func (vis *VolumeIoSuite) Parameters() string {
-	return fmt.Sprintf("{volumes: %d, volumeSize: %s chains: %d-%d}", vis.VolumeNumber, vis.VolumeSize,
-		vis.ChainNumber, vis.ChainLength)
+func (cns *CrossNamespaceRestoreSuite) Parameters() string {
+	return fmt.Sprintf("{snapClass: %s, size: %s}", cns.SnapClass, cns.VolumeSize)
+}
+
+// GetSnapClass returns volume snapshot class suite takes snapshots with
+func (cns *CrossNamespaceRestoreSuite) GetSnapClass() string {
+	return cns.SnapClass
 }
 
 // VolumeGroupSnapSuite is used to manage volume group snap test suite
@@ -1337,8 +6165,14 @@ type SnapSuite struct {
 	AccessModeOriginal string
 	AccessModeRestored string
 	Image              string
+	// Variant describes parameter overrides of snapshot class in snapshot class sweep, test cases of sweep
+	// are named after their snapshot class
+	Variant string
 }
 
+// SnapSweepPrefix is a prefix of names of test cases of snapshot class sweep, it's followed by snapshot class
+const SnapSweepPrefix = "SnapSuite/"
+
 // Run executes snap test suite
 func (ss *SnapSuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
 	log := utils.GetLoggerFromContext(ctx)
@@ -1507,8 +6341,7 @@ func (ss *SnapSuite) Run(ctx context.Context, storageClass string, clients *k8sc
 	}
 	// Create second PVC from snapshot
 	var pvcFromSnapNameList []string
-	rand.Seed(time.Now().Unix())
-	n := rand.Int() % len(snaps) // #nosec
+	n := k8sclient.RandomIntn(len(snaps))
 	vcconf.SnapName = snaps[n].Name()
 	log.Infof("Restoring from %s", vcconf.SnapName)
 	vcconf.Name = vcconf.SnapName + "-restore"
@@ -1619,14 +6452,25 @@ func (ss *SnapSuite) GetName() string {
 	if ss.Description != "" {
 		return ss.Description
 	}
+	if ss.Variant != "" {
+		return SnapSweepPrefix + ss.SnapClass
+	}
 	return "SnapSuite"
 }
 
 // Parameters returns formatted string of paramters
 func (ss *SnapSuite) Parameters() string {
+	if ss.Variant != "" {
+		return fmt.Sprintf("{snapshots: %d, volumeSize; %s, variant: %s}", ss.SnapAmount, ss.VolumeSize, ss.Variant)
+	}
 	return fmt.Sprintf("{snapshots: %d, volumeSize; %s}", ss.SnapAmount, ss.VolumeSize)
 }
 
+// GetSnapClass returns volume snapshot class suite takes snapshots with
+func (ss *SnapSuite) GetSnapClass() string {
+	return ss.SnapClass
+}
+
 func getAllObservers(obsType observer.Type) []observer.Interface {
 	if obsType == observer.EVENT {
 		return []observer.Interface{
@@ -1635,6 +6479,7 @@ func getAllObservers(obsType observer.Type) []observer.Interface {
 			&observer.PodObserver{},
 			&observer.EntityNumberObserver{},
 			&observer.ContainerMetricsObserver{},
+			&observer.ComponentEventObserver{},
 		}
 	} else if obsType == observer.LIST {
 		return []observer.Interface{
@@ -1648,6 +6493,250 @@ func getAllObservers(obsType observer.Type) []observer.Interface {
 	return []observer.Interface{}
 }
 
+// SnapshotConsistencySuite is used to manage snapshot while writing consistency test suite
+type SnapshotConsistencySuite struct {
+	SnapClass  string
+	VolumeSize string
+	Image      string
+}
+
+const (
+	journalFile    = "/data0/journal.log"
+	journalPidFile = "/tmp/journal.pid"
+	journalPayload = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// Run executes snapshot consistency test suite
+func (scs *SnapshotConsistencySuite) Run(ctx context.Context, storageClass string, clients *k8sclient.Clients) (delFunc func() error, e error) {
+	log := utils.GetLoggerFromContext(ctx)
+	if scs.VolumeSize == "" {
+		log.Info("Using default volume size : 3Gi")
+		scs.VolumeSize = "3Gi"
+	}
+	if scs.Image == "" {
+		scs.Image = "quay.io/centos/centos:latest"
+		log.Infof("Using default image: %s", scs.Image)
+	}
+
+	pvcClient := clients.PVCClient
+	podClient := clients.PodClient
+
+	vcconf := testcore.VolumeCreationConfig(storageClass, scs.VolumeSize, "", "")
+	pvc := pvcClient.Create(ctx, pvcClient.MakePVC(vcconf))
+	if pvc.HasError() {
+		return delFunc, pvc.GetError()
+	}
+
+	writerPod := podClient.Create(ctx, podClient.MakePod(testcore.IoWritePodConfig([]string{pvc.Object.Name}, "", scs.Image))).Sync(ctx)
+	if writerPod.HasError() {
+		return delFunc, writerPod.GetError()
+	}
+
+	// Every record is synced before the next one is written, so a crash-consistent snapshot
+	// may only lose records that were in flight when it was cut
+	journal := fmt.Sprintf(`echo $$ > %s; i=0; while true; do printf "seq %%08d %s end\n" $i >> %s && sync %s; i=$((i+1)); done`,
+		journalPidFile, journalPayload, journalFile, journalFile)
+	writer := fmt.Sprintf("setsid bash -c '%s' > /dev/null 2>&1 < /dev/null &", journal)
+	if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", writer}, os.Stdout, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	log.Info("Started journal writer in pod ", writerPod.Object.GetName())
+
+	countRecords := func() (int, error) {
+		out := bytes.NewBufferString("")
+		if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", "wc -l < " + journalFile}, out, os.Stderr, false); err != nil {
+			return 0, err
+		}
+		return strconv.Atoi(strings.TrimSpace(out.String()))
+	}
+
+	// Let the journal grow before cutting the snapshot
+	time.Sleep(10 * time.Second)
+	recordsBefore, err := countRecords()
+	if err != nil {
+		return delFunc, err
+	}
+
+	gotPvc, err := pvcClient.Interface.Get(ctx, pvc.Object.Name, metav1.GetOptions{})
+	if err != nil {
+		return delFunc, err
+	}
+
+	log.Infof("Creating snapshot while writing, %d records are synced", recordsBefore)
+	var createSnap volumesnapshot.Interface
+	if clients.SnapClientGA != nil {
+		createSnap = clients.SnapClientGA.Create(ctx,
+			&snapv1.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      DefaultSnapPrefix + "-journal",
+					Namespace: gotPvc.Namespace,
+				},
+				Spec: snapv1.VolumeSnapshotSpec{
+					Source: snapv1.VolumeSnapshotSource{
+						PersistentVolumeClaimName: &gotPvc.Name,
+					},
+					VolumeSnapshotClassName: &scs.SnapClass,
+				},
+			})
+	} else if clients.SnapClientBeta != nil {
+		createSnap = clients.SnapClientBeta.Create(ctx,
+			&snapbeta.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      DefaultSnapPrefix + "-journal",
+					Namespace: gotPvc.Namespace,
+				},
+				Spec: snapbeta.VolumeSnapshotSpec{
+					Source: snapbeta.VolumeSnapshotSource{
+						PersistentVolumeClaimName: &gotPvc.Name,
+					},
+					VolumeSnapshotClassName: &scs.SnapClass,
+				},
+			})
+	} else {
+		return delFunc, fmt.Errorf("can't get alpha or beta snapshot client")
+	}
+	if createSnap.HasError() {
+		return delFunc, createSnap.GetError()
+	}
+	if err := createSnap.WaitForRunning(ctx); err != nil {
+		return delFunc, err
+	}
+
+	recordsAfter, err := countRecords()
+	if err != nil {
+		return delFunc, err
+	}
+	if err := podClient.Exec(ctx, writerPod.Object, []string{"/bin/bash", "-c", "kill $(cat " + journalPidFile + ")"}, os.Stdout, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+	log.Infof("Stopped journal writer, %d records were written by the time snapshot was ready", recordsAfter)
+
+	vcconf.SnapName = createSnap.Name()
+	vcconf.Name = vcconf.SnapName + "-restore"
+	log.Infof("Restoring from %s", vcconf.SnapName)
+	pvcRestored := pvcClient.Create(ctx, pvcClient.MakePVC(vcconf))
+	if pvcRestored.HasError() {
+		return delFunc, pvcRestored.GetError()
+	}
+
+	checkerPod := podClient.Create(ctx, podClient.MakePod(testcore.IoWritePodConfig([]string{pvcRestored.Object.Name}, "", scs.Image))).Sync(ctx)
+	if checkerPod.HasError() {
+		return delFunc, checkerPod.GetError()
+	}
+
+	restored := bytes.NewBufferString("")
+	if err := podClient.Exec(ctx, checkerPod.Object, []string{"cat", journalFile}, restored, os.Stderr, false); err != nil {
+		return delFunc, err
+	}
+
+	records, torn, err := validateJournal(restored.String())
+	if err != nil {
+		return delFunc, err
+	}
+	log.Infof("Restored journal has %s records and %s torn tail record(s)", color.HiYellowString(strconv.Itoa(records)),
+		color.HiYellowString(strconv.Itoa(torn)))
+
+	// Last record counted before snapshot may have been mid-sync
+	if records < recordsBefore-1 {
+		return delFunc, fmt.Errorf("restored journal is missing records: has %d, at least %d were synced before snapshot", records, recordsBefore-1)
+	}
+	if records > recordsAfter {
+		return delFunc, fmt.Errorf("restored journal has %d records, but only %d were written before snapshot was ready", records, recordsAfter)
+	}
+
+	return delFunc, nil
+}
+
+// validateJournal checks that journal records form contiguous sequence, only the last record may be torn
+func validateJournal(journal string) (records int, torn int, err error) {
+	lines := strings.Split(journal, "\n")
+	// Journal ends with newline unless its tail is torn
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		var seq int
+		var payload, end string
+		n, scanErr := fmt.Sscanf(line, "seq %d %s %s", &seq, &payload, &end)
+		if scanErr != nil || n != 3 || payload != journalPayload || end != "end" {
+			if i == len(lines)-1 {
+				return records, 1, nil
+			}
+			return records, torn, fmt.Errorf("journal record %d is torn: %q", i, line)
+		}
+		if seq != i {
+			return records, torn, fmt.Errorf("journal records are out of sequence: expected %d, got %d", i, seq)
+		}
+		records++
+	}
+	return records, torn, nil
+}
+
+// GetObservers returns all observers
+func (*SnapshotConsistencySuite) GetObservers(obsType observer.Type) []observer.Interface {
+	return getAllObservers(obsType)
+}
+
+// GetClients creates and returns pvc, pod, va, metrics, snapshot clients
+func (scs *SnapshotConsistencySuite) GetClients(namespace string, client *k8sclient.KubeClient) (*k8sclient.Clients, error) {
+	if ok, err := client.SnapshotClassExists(scs.SnapClass); !ok {
+		return nil, fmt.Errorf("snapshotclass class doesn't exist; error = %v", err)
+	}
+
+	pvcClient, pvcErr := client.CreatePVCClient(namespace)
+	if pvcErr != nil {
+		return nil, pvcErr
+	}
+
+	podClient, podErr := client.CreatePodClient(namespace)
+	if podErr != nil {
+		return nil, podErr
+	}
+
+	vaClient, vaErr := client.CreateVaClient(namespace)
+	if vaErr != nil {
+		return nil, vaErr
+	}
+
+	metricsClient, mcErr := client.CreateMetricsClient(namespace)
+	if mcErr != nil {
+		return nil, mcErr
+	}
+
+	snapGA, snapBeta, snErr := GetSnapshotClient(namespace, client)
+	if snErr != nil {
+		return nil, snErr
+	}
+	return &k8sclient.Clients{
+		PVCClient:      pvcClient,
+		PodClient:      podClient,
+		VaClient:       vaClient,
+		MetricsClient:  metricsClient,
+		SnapClientGA:   snapGA,
+		SnapClientBeta: snapBeta,
+	}, nil
+}
+
+// GetNamespace returns snapshot consistency suite namespace
+func (*SnapshotConsistencySuite) GetNamespace() string {
+	return "snap-consistency-test"
+}
+
+// GetName returns snapshot consistency suite name
+func (*SnapshotConsistencySuite) GetName() string {
+	return "SnapshotConsistencySuite"
+}
+
+// Parameters returns formatted string of parameters
+func (scs *SnapshotConsistencySuite) Parameters() string {
+	return fmt.Sprintf("{volumeSize: %s, snapClass: %s}", scs.VolumeSize, scs.SnapClass)
+}
+
+// GetSnapClass returns volume snapshot class suite takes snapshots with
+func (scs *SnapshotConsistencySuite) GetSnapClass() string {
+	return scs.SnapClass
+}
+
 // ReplicationSuite is used to manage replication test suite
 type ReplicationSuite struct {
 	VolumeNumber int
@@ -1841,8 +6930,10 @@ func (rs *ReplicationSuite) Run(ctx context.Context, storageClass string, client
 				log.Warnf("error when deleting local RG: %s", deletedLocalRG.GetError().Error())
 			}
 
-			log.Info("Sleeping for 1 minute...")
-			time.Sleep(1 * time.Minute)
+			log.Info("Waiting for RG to be deleted")
+			if err := deletedLocalRG.WaitUntilGone(context.Background()); err != nil {
+				log.Warnf("local RG wasn't deleted: %v", err)
+			}
 
 			return nil
 		}
@@ -1919,6 +7010,11 @@ func (rs *ReplicationSuite) Parameters() string {
 	return fmt.Sprintf("{pods: %d, volumes: %d, volumeSize: %s}", rs.PodNumber, rs.VolumeNumber, rs.VolumeSize)
 }
 
+// GetSnapClass returns volume snapshot class suite takes snapshots with
+func (rs *ReplicationSuite) GetSnapClass() string {
+	return rs.SnapClass
+}
+
 // VolumeExpansionSuite is used to manage volume expansion test suite
 type VolumeExpansionSuite struct {
 	VolumeNumber int
@@ -1929,6 +7025,8 @@ type VolumeExpansionSuite struct {
 	Description  string
 	AccessMode   string
 	Image        string
+
+	expansionOutcomes []*store.ExpansionOutcome
 }
 
 // Run executes volume expansion test suite
@@ -2030,29 +7128,37 @@ func (ves *VolumeExpansionSuite) Run(ctx context.Context, storageClass string, c
 		return delFunc, err
 	}
 
+	ves.expansionOutcomes = nil
+	requested := make(map[string]time.Time)
 	for i := range pvcList.Items {
-		pvcList.Items[i].Spec.Resources.Requests = v1.ResourceList{
-			v1.ResourceStorage: resource.MustParse(ves.ExpandedSize),
-		}
-		updatedPVC := pvcClient.Update(ctx, &pvcList.Items[i])
-		if updatedPVC.HasError() {
-			return delFunc, updatedPVC.GetError()
+		requested[pvcList.Items[i].Name] = time.Now()
+		expandedPVC := pvcClient.Expand(ctx, pvcList.Items[i].Name, ves.ExpandedSize)
+		if expandedPVC.HasError() {
+			return delFunc, expandedPVC.GetError()
 		}
 	}
 
-	// Give some time for driver to expand
-	time.Sleep(10 * time.Second)
+	ves.expansionOutcomes = trackExpansions(ctx, pvcClient, requested, ves.ExpandedSize)
+	var unfinished []string
+	for _, eo := range ves.expansionOutcomes {
+		if eo.Outcome != store.ExpansionCompleted {
+			unfinished = append(unfinished, fmt.Sprintf("%s %s %s", eo.PVC, eo.Outcome, eo.Message))
+		}
+	}
+	if len(unfinished) != 0 {
+		return delFunc, fmt.Errorf("expansion of %d volumes didn't complete: %s", len(unfinished), strings.Join(unfinished, "; "))
+	}
 
 	if ves.IsBlock {
 		// Check for "FileSystemResizeSuccessful" event to confirm successful resizing
 		log.Infof("Waiting for 'FileSystemResizeSuccessful' event for each pod")
 
 		for _, pod := range podObjectList {
-			pollErr := wait.PollImmediate(10*time.Second, time.Duration(pvcClient.Timeout)*time.Second,
-				func() (bool, error) {
-					eventList, err := podClient.ClientSet.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{
-						FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=Pod", pod.Name),
-					})
+			eventsClient := podClient.ClientSet.CoreV1().Events(pod.Namespace)
+			podEvents := metav1.ListOptions{FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=Pod", pod.Name)}
+			pollErr := commonparams.PollUntil(ctx, 10*time.Second, time.Duration(pvcClient.Timeout)*time.Second, commonparams.Watch(eventsClient, podEvents),
+				func(context.Context) (bool, error) {
+					eventList, err := eventsClient.List(ctx, podEvents)
 					if err != nil {
 						log.Errorf("Failed to list events for pod %s: %v", pod.Name, err)
 						return false, err
@@ -2090,8 +7196,8 @@ func (ves *VolumeExpansionSuite) Run(ctx context.Context, storageClass string, c
 			}
 			oldDelta := deltas[p.Name+v.MountPath]
 
-			pollErr := wait.PollImmediate(10*time.Second, time.Duration(pvcClient.Timeout)*time.Second,
-				func() (bool, error) {
+			pollErr := commonparams.PollUntil(ctx, 10*time.Second, time.Duration(pvcClient.Timeout)*time.Second, nil,
+				func(context.Context) (bool, error) {
 					select {
 					case <-ctx.Done():
 						log.Infof("Stopping pod wait polling")
@@ -2128,6 +7234,101 @@ func (ves *VolumeExpansionSuite) Run(ctx context.Context, storageClass string, c
 	return delFunc, nil
 }
 
+// trackExpansions waits until expansion of every PVC completes or fails with terminal error and tells how far each got.
+// Node phase is zero if both phases finished between two polls
+func trackExpansions(ctx context.Context, pvcClient *pvc.Client, requested map[string]time.Time, size string) []*store.ExpansionOutcome {
+	log := utils.GetLoggerFromContext(ctx)
+	want := resource.MustParse(size)
+	outcomes := make(map[string]*store.ExpansionOutcome)
+	controllerExpanded := make(map[string]time.Time)
+	for name := range requested {
+		outcomes[name] = &store.ExpansionOutcome{PVC: name, Outcome: store.ExpansionControllerPending}
+	}
+
+	pollErr := commonparams.PollUntil(ctx, pvc.Poll, time.Duration(pvcClient.Timeout)*time.Second, commonparams.Watch(pvcClient.Interface, metav1.ListOptions{}),
+		func(context.Context) (bool, error) {
+			done := true
+			for name, eo := range outcomes {
+				if eo.Outcome == store.ExpansionCompleted || eo.Outcome == store.ExpansionInfeasible {
+					continue
+				}
+				done = false
+				claim, err := pvcClient.Interface.Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					log.Debugf("Can't get PVC %s; error=%v", name, err)
+					continue
+				}
+				var volume *v1.PersistentVolume
+				if claim.Spec.VolumeName != "" {
+					// PV is optional, namespaced runs aren't allowed to get it
+					volume, _ = pvcClient.ClientSet.CoreV1().PersistentVolumes().Get(ctx, claim.Spec.VolumeName, metav1.GetOptions{})
+				}
+				eo.Outcome, eo.Message = expansionOutcome(claim, volume, want)
+
+				now := time.Now()
+				if eo.Outcome != store.ExpansionControllerPending && eo.Outcome != store.ExpansionInfeasible && controllerExpanded[name].IsZero() {
+					controllerExpanded[name] = now
+					eo.Controller = now.Sub(requested[name])
+				}
+				if eo.Outcome == store.ExpansionCompleted {
+					eo.Node = now.Sub(controllerExpanded[name])
+					log.Infof("%s expanded in %s on controller and %s on node", name, eo.Controller, eo.Node)
+				}
+			}
+			return done, nil
+		})
+	if pollErr != nil {
+		log.Warnf("Stopped waiting for expansion; error=%v", pollErr)
+	}
+
+	result := make([]*store.ExpansionOutcome, 0, len(outcomes))
+	for _, eo := range outcomes {
+		result = append(result, eo)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PVC < result[j].PVC })
+	return result
+}
+
+// expansionOutcome classifies expansion of PVC to requested size by its status and conditions, and capacity of its PV if known
+func expansionOutcome(claim *v1.PersistentVolumeClaim, volume *v1.PersistentVolume, requested resource.Quantity) (store.ExpansionOutcomeEnum, string) {
+	if capacity, ok := claim.Status.Capacity[v1.ResourceStorage]; ok && capacity.Cmp(requested) >= 0 {
+		return store.ExpansionCompleted, ""
+	}
+
+	var message string
+	nodePending := false
+	for _, c := range claim.Status.Conditions {
+		if c.Status != v1.ConditionTrue {
+			continue
+		}
+		if c.Message != "" {
+			message = c.Message
+		}
+		if c.Type == v1.PersistentVolumeClaimFileSystemResizePending {
+			nodePending = true
+		}
+	}
+
+	// Newer Kubernetes reports terminal errors as Infeasible, older ones as Failed
+	switch claim.Status.AllocatedResourceStatuses[v1.ResourceStorage] {
+	case v1.PersistentVolumeClaimControllerResizeFailed, v1.PersistentVolumeClaimNodeResizeFailed,
+		"ControllerResizeInfeasible", "NodeResizeInfeasible":
+		return store.ExpansionInfeasible, message
+	case v1.PersistentVolumeClaimNodeResizePending, v1.PersistentVolumeClaimNodeResizeInProgress:
+		nodePending = true
+	}
+	if nodePending {
+		return store.ExpansionNodePending, message
+	}
+
+	if volume != nil {
+		if capacity, ok := volume.Spec.Capacity[v1.ResourceStorage]; ok && capacity.Cmp(requested) >= 0 {
+			return store.ExpansionControllerOnly, message
+		}
+	}
+	return store.ExpansionControllerPending, message
+}
+
 func checkSize(ctx context.Context, podClient *pod.Client, p *v1.Pod, v v1.VolumeMount, quiet bool) (int, error) {
 	log := utils.GetLoggerFromContext(ctx)
 	res := bytes.NewBufferString("")
@@ -2173,6 +7374,11 @@ func convertSpecSize(specSize string) (int, error) {
 	return wantSize, nil
 }
 
+// GetExpansionOutcomes returns how expansion of every volume ended in the last run
+func (ves *VolumeExpansionSuite) GetExpansionOutcomes() []*store.ExpansionOutcome {
+	return ves.expansionOutcomes
+}
+
 // GetObservers returns all observers
 func (*VolumeExpansionSuite) GetObservers(obsType observer.Type) []observer.Interface {
 	return getAllObservers(obsType)
@@ -3064,8 +8270,8 @@ func (bss *BlockSnapSuite) Run(ctx context.Context, storageClass string, clients
 	}
 
 	newHash := bytes.NewBufferString("")
-	pollErr := wait.PollImmediate(10*time.Second, 5*time.Minute,
-		func() (bool, error) {
+	pollErr := commonparams.PollUntil(ctx, 10*time.Second, 5*time.Minute, nil,
+		func(context.Context) (bool, error) {
 			select {
 			case <-ctx.Done():
 				log.Infof("Stopping pod wait polling")
@@ -3166,18 +8372,14 @@ func (bss *BlockSnapSuite) Parameters() string {
 	return fmt.Sprintf("{size: %s, accMode: %s}", bss.VolumeSize, bss.AccessMode)
 }
 
+// GetSnapClass returns volume snapshot class suite takes snapshots with
+func (bss *BlockSnapSuite) GetSnapClass() string {
+	return bss.SnapClass
+}
+
 // GetSnapshotClient returns snapshot client
 func GetSnapshotClient(namespace string, client *k8sclient.KubeClient) (*snapv1client.SnapshotClient, *snapbetaclient.SnapshotClient, error) {
-	gaClient, snErr := client.CreateSnapshotGAClient(namespace)
-	_, err := gaClient.Interface.List(context.Background(), metav1.ListOptions{})
-	if err != nil || snErr != nil {
-		betaClient, snErr := client.CreateSnapshotBetaClient(namespace)
-		if snErr != nil {
-			return nil, nil, snErr
-		}
-		return nil, betaClient, nil
-	}
-	return gaClient, nil, nil
+	return client.CreateSnapshotClients(namespace)
 }
 
 // VolumeMigrateSuite is used to manage volume migrate test suite
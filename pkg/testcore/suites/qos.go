@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QoSPoll is an interval between readiness checks of pods measured by QoS class suite
+var QoSPoll = 250 * time.Millisecond
+
+// QoSClasses are measured in this order, Guaranteed pods are the baseline others are compared to
+var QoSClasses = []v1.PodQOSClass{v1.PodQOSGuaranteed, v1.PodQOSBurstable, v1.PodQOSBestEffort}
+
+// qosResources returns requests and limits making pod of the QoS class
+func qosResources(class v1.PodQOSClass) (requests, limits v1.ResourceList) {
+	switch class {
+	case v1.PodQOSGuaranteed:
+		limits = v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("500m"),
+			v1.ResourceMemory: resource.MustParse("256Mi"),
+		}
+		return limits, limits
+	case v1.PodQOSBurstable:
+		return v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("100m"),
+			v1.ResourceMemory: resource.MustParse("64Mi"),
+		}, nil
+	}
+	return nil, nil
+}
+
+// pinToNode makes scheduler place pod on the node, unlike node name it keeps resource checks of scheduler
+func pinToNode(p *v1.Pod, node string) {
+	if node == "" {
+		return
+	}
+	p.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchFields: []v1.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{node},
+				}},
+			}},
+		},
+	}}
+}
+
+// waitPodReady polls pod until it's ready and returns it, timeout of pod client is used
+func waitPodReady(ctx context.Context, podClient *pod.Client, name string) (*v1.Pod, error) {
+	timeout := pod.Timeout
+	if podClient.Timeout != 0 {
+		timeout = time.Duration(podClient.Timeout) * time.Second
+	}
+	var ready *v1.Pod
+	pollErr := commonparams.PollUntil(ctx, QoSPoll, timeout, commonparams.Watch(podClient.Interface, commonparams.NameSelector(name)), func(context.Context) (bool, error) {
+		p, err := podClient.Interface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if pod.IsPodReady(p) {
+			ready = p
+			return true, nil
+		}
+		return false, nil
+	})
+	if pollErr != nil {
+		return nil, fmt.Errorf("pod %s isn't ready: %v", name, pollErr)
+	}
+	return ready, nil
+}
+
+// timedWrite writes size MiB to file bypassing page cache, so memory limits of the pod don't skew the result
+func timedWrite(ctx context.Context, podClient *pod.Client, p *v1.Pod, file string, size int) (time.Duration, error) {
+	dd := []string{"dd", "if=/dev/zero", "of=" + file, "bs=1M", fmt.Sprintf("count=%d", size), "oflag=direct"}
+	start := time.Now()
+	if err := podClient.Exec(ctx, p, dd, io.Discard, io.Discard, false); err != nil {
+		return 0, fmt.Errorf("can't write to volume of pod %s: %v", p.Name, err)
+	}
+	return time.Since(start), nil
+}
@@ -0,0 +1,82 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+	"github.com/fatih/color"
+)
+
+// runRamp runs stage for each concurrency of ramp profile, cleanup is called between stages,
+// so every stage starts from scratch and its latencies aren't affected by the previous one
+func runRamp(ctx context.Context, ramp []int, stage func(concurrency int) (int, error), cleanup func() error) ([]*store.RampStage, error) {
+	log := utils.GetLoggerFromContext(ctx)
+	var stages []*store.RampStage
+	for i, concurrency := range ramp {
+		if i > 0 {
+			if err := cleanup(); err != nil {
+				return stages, err
+			}
+		}
+		log.Infof("Ramp stage %d/%d: %s concurrent volumes", i+1, len(ramp), color.YellowString(strconv.Itoa(concurrency)))
+		rs := &store.RampStage{StartTimestamp: time.Now()}
+		volumes, err := stage(concurrency)
+		rs.Concurrency = volumes
+		rs.EndTimestamp = time.Now()
+		stages = append(stages, rs)
+		if err != nil {
+			return stages, err
+		}
+		log.Infof("Ramp stage %d/%d took %s", i+1, len(ramp), color.CyanString(rs.Duration().String()))
+	}
+	return stages, nil
+}
+
+// rampParameter formats ramp profile to be appended to suite parameters, suites without ramp keep their parameters unchanged
+func rampParameter(ramp []int) string {
+	if len(ramp) == 0 {
+		return ""
+	}
+	stages := make([]string, len(ramp))
+	for i, n := range ramp {
+		stages[i] = strconv.Itoa(n)
+	}
+	return fmt.Sprintf(", ramp: %s", strings.Join(stages, "/"))
+}
+
+// ParseRamp parses comma separated list of stage concurrencies, e.g. "10,50,100,250"
+func ParseRamp(ramp string) ([]int, error) {
+	if ramp == "" {
+		return nil, nil
+	}
+	var stages []int
+	for _, s := range strings.Split(ramp, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid ramp stage %q, positive number expected", s)
+		}
+		stages = append(stages, n)
+	}
+	return stages, nil
+}
@@ -0,0 +1,79 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/pod"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DeviceGone means block device of detached volume no longer exists on the old node
+	DeviceGone = "gone"
+	// DeviceUnreadable means device node is left on the old node but can't be read anymore
+	DeviceUnreadable = "unreadable"
+	// DeviceReadable means the old node can still read data of detached volume, i.e. it wasn't unmapped
+	DeviceReadable = "readable"
+)
+
+// volumeDevice returns major:minor of block device backing volume in the pod, empty if volume isn't
+// backed by a block device, ex. NFS
+func volumeDevice(ctx context.Context, podClient *pod.Client, p *v1.Pod, path string, block bool) (string, error) {
+	cmd := []string{"mountpoint", "-d", path}
+	if block {
+		cmd = []string{"mountpoint", "-x", path}
+	}
+	var stdout, stderr bytes.Buffer
+	if err := podClient.Exec(ctx, p, cmd, &stdout, &stderr, true); err != nil {
+		return "", fmt.Errorf("can't get device of %s: %w: %s", path, err, stderr.String())
+	}
+	device := strings.TrimSpace(stdout.String())
+	if strings.HasPrefix(device, "0:") {
+		return "", nil
+	}
+	return device, nil
+}
+
+// staleMounts returns mount points of the host which still reference the persistent volume
+func staleMounts(entries []mountEntry, pvName string) []string {
+	var stale []string
+	for _, e := range entries {
+		if strings.Contains(e.MountPoint, pvName) {
+			stale = append(stale, e.MountPoint)
+		}
+	}
+	return stale
+}
+
+// probeDevice tries to read the first block of device with major:minor in the host mount namespace
+// of the diagnostic pod and returns its state
+func probeDevice(ctx context.Context, podClient *pod.Client, diag *v1.Pod, device string) (string, error) {
+	script := fmt.Sprintf(`test -e /sys/dev/block/%[1]s || { echo %[2]s; exit 0; }; `+
+		`dev=$(readlink -f /sys/dev/block/%[1]s); dev=/dev/${dev##*/}; `+
+		`dd if=$dev of=/dev/null bs=4096 count=1 iflag=direct 2>/dev/null && echo %[3]s || echo %[4]s`,
+		device, DeviceGone, DeviceReadable, DeviceUnreadable)
+	var stdout, stderr bytes.Buffer
+	if err := podClient.Exec(ctx, diag, []string{"nsenter", "-t", "1", "-m", "--", "sh", "-c", script}, &stdout, &stderr, true); err != nil {
+		return "", fmt.Errorf("can't probe device %s: %w: %s", device, err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
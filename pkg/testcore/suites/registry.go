@@ -0,0 +1,125 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package suites
+
+import (
+	"sort"
+	"sync"
+)
+
+// Parameter describes a knob of a suite
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Info contains metadata of a suite
+type Info struct {
+	Name         string      `json:"name"`
+	Command      string      `json:"command"`
+	Description  string      `json:"description"`
+	Parameters   []Parameter `json:"parameters"`
+	Capabilities []string    `json:"capabilities,omitempty"`
+}
+
+var (
+	registry      = make(map[string]Info)
+	registryMutex sync.RWMutex
+)
+
+// Register adds suite metadata to registry, info with the same command replaces the previous one
+func Register(info Info) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[info.Command] = info
+}
+
+// Registered returns metadata of all registered suites sorted by command
+func Registered() []Info {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	infos := make([]Info, 0, len(registry))
+	for _, info := range registry {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Command < infos[j].Command
+	})
+	return infos
+}
+
+func init() {
+	for _, info := range []Info{
+		{Name: "VolumeCreationSuite", Command: "test volume-creation", Description: "creates and deletes a number of volumes"},
+		{Name: "ProvisioningSuite", Command: "test provisioning", Description: "creates pods with volumes attached"},
+		{Name: "ScalingSuite", Command: "test scaling", Description: "scales statefulset with volumes up and down", Capabilities: []string{"StatefulSets"}},
+		{Name: "VolumeIoSuite", Command: "test volumeio", Description: "writes data by chains of pods and checks its integrity between them"},
+		{Name: "FSGroupSuite", Command: "test fsgroup", Description: "validates volume ownership with different fsGroup and SecurityContext settings"},
+		{Name: "DiskPressureEvictionSuite", Command: "test disk-pressure-eviction", Description: "evicts pods with volumes by exceeding ephemeral storage limit and checks volumes detach and reattach", Capabilities: []string{"Local ephemeral storage isolation"}},
+		{Name: "WorkloadChurnSuite", Command: "test workload-churn", Description: "keeps steady state of volumes with pods while recreating a percentage of them every minute and reports latency drift"},
+		{Name: "SnapSuite", Command: "test snapshot", Description: "creates snapshots of a volume and restores them", Capabilities: []string{"VolumeSnapshot CRDs", "VolumeSnapshotClass"}},
+		{Name: "SnapshotConsistencySuite", Command: "test snapshot-consistency", Description: "snapshots a volume while pod writes a journal and validates crash-consistency of restored data", Capabilities: []string{"VolumeSnapshot CRDs", "VolumeSnapshotClass"}},
+		{Name: "VolumeGroupSnapSuite", Command: "test volume-group-snapshot", Description: "creates a snapshot of a group of volumes", Capabilities: []string{"VolumeGroupSnapshot CRDs", "VolumeSnapshotClass"}},
+		{Name: "ReplicationSuite", Command: "test replication", Description: "creates volumes from snapshots of populated volumes", Capabilities: []string{"VolumeSnapshot CRDs", "VolumeSnapshotClass"}},
+		{Name: "CloneVolumeSuite", Command: "test clone-volume", Description: "clones volumes and attaches clones to pods", Capabilities: []string{"Volume cloning"}},
+		{Name: "MultiAttachSuite", Command: "test multi-attach-vol", Description: "attaches a volume to multiple pods", Capabilities: []string{"ReadWriteMany or ReadWriteOncePod access mode", "Multiple nodes"}},
+		{Name: "VolumeExpansionSuite", Command: "test expansion", Description: "expands volumes attached to pods and checks new size", Capabilities: []string{"allowVolumeExpansion storage class"}},
+		{Name: "ObservabilitySuite", Command: "test observability", Description: "checks that every created volume shows up in metrics exported by CSM Observability otel collector", Capabilities: []string{"CSM Observability"}},
+		{Name: "BindingModeComparisonSuite", Command: "test binding-mode-comparison", Description: "provisions volumes with Immediate and WaitForFirstConsumer clones of storage class and compares binding latencies", Capabilities: []string{"StorageClass create permissions"}},
+		{Name: "SnapshotScheduleSuite", Command: "test snapshot-schedule", Description: "takes snapshots of busy volumes periodically with retention and reports latency drift", Capabilities: []string{"VolumeSnapshot CRDs", "VolumeSnapshotClass"}},
+		{Name: "AttachPingPongSuite", Command: "test attach-ping-pong", Description: "moves a pod with RWO volume between two nodes repeatedly and reports detach and attach latency drift", Capabilities: []string{"At least 2 schedulable nodes"}},
+		{Name: "RWXSharingSuite", Command: "test rwx-sharing", Description: "shares RWX volume between writer deployment and readers behind headless service and reports content propagation latency distribution", Capabilities: []string{"ReadWriteMany access mode"}},
+		{Name: "VeleroBackupSuite", Command: "test velero-backup", Description: "backs up volume with Velero using CSI snapshots, restores it to another namespace and validates restored data and timing", Capabilities: []string{"VolumeSnapshot CRDs", "Velero with CSI snapshot support", "VolumeSnapshotClass labeled velero.io/csi-volumesnapshot-class"}},
+		{Name: "VolumeStatsSuite", Command: "test volume-stats", Description: "writes known amount of data to volumes and validates capacity, used and available bytes reported by kubelet", Capabilities: []string{"nodes/proxy access to kubelet summary API"}},
+		{Name: "MountOptionMatrixSuite", Command: "test mount-options", Description: "clones storage class with sets of mount options and validates that options are applied on node or that mount fails cleanly", Capabilities: []string{"StorageClass create permissions", "Privileged pods"}},
+		{Name: "CapacityFullSuite", Command: "test capacity-full", Description: "provisions volumes until backend pool is exhausted, validates ResourceExhausted errors, health of bound volumes and capacity restored by cleanup", Capabilities: []string{"Dedicated backend pool which may be filled up"}},
+		{Name: "QoSClassSuite", Command: "test qos-class", Description: "mounts and writes to volumes from Guaranteed, Burstable and BestEffort pods on the same node, optionally under CPU pressure, and compares latencies", Capabilities: []string{"Namespace without LimitRange"}},
+		{Name: "CrossNamespaceRestoreSuite", Command: "test cross-namespace-restore", Description: "restores snapshot into another namespace through ReferenceGrant, validates data and compares latency with same-namespace restore", Capabilities: []string{"VolumeSnapshot CRDs", "Gateway API ReferenceGrant CRD", "CrossNamespaceVolumeDataSource feature gate", "Driver provisioner with --feature-gates=CrossNamespaceVolumeDataSource=true"}},
+		{Name: "IPv6Suite", Command: "test ipv6", Description: "connects from network of every node plugin to driver controller over IPv6 and provisions and mounts a volume", Capabilities: []string{"IPv6 or dual-stack cluster", "Driver namespace", "Controller pods declaring container ports"}},
+		{Name: "KubeletRestartSuite", Command: "test kubelet-restart", Description: "restarts kubelet of a node with mounted volumes, validates volumes stay mounted and pods healthy, measures downtime and checks that remounts after pod restart are idempotent", Capabilities: []string{"Privileged pods with host PID or kubelet restart hook", "nodes/proxy access to kubelet health endpoint"}},
+		{Name: "MountRecoverySuite", Command: "test mount-recovery", Description: "starts pods while fault hook keeps node from reaching backend, validates that kubelet and driver retries succeed once fault is cleared and measures recovery time and retry count from events", Capabilities: []string{"Fault hook able to block backend of a node", "Events access"}},
+		{Name: "RWXConcurrentWriteSuite", Command: "test rwx-concurrent-write", Description: "writers on different nodes append records to their own files of RWX volume at once and validator verifies no write was lost and sizes are correct, optionally records semantics of appends to a shared file", Capabilities: []string{"ReadWriteMany volumes", "At least 2 schedulable nodes"}},
+		{Name: "ControllerFailoverSuite", Command: "test controller-failover", Description: "deletes leader pod of driver controller or scales its deployment down and up while volumes are provisioned, verifies leader election hands leases over and provisioning resumes and measures stall window from events", Capabilities: []string{"Driver controller with leader election", "Deployment scale and pod delete access in driver namespace", "Events access"}},
+		{Name: "ReadAfterDetachSuite", Command: "test read-after-detach", Description: "detaches written volumes from a node, probes that node for stale mounts and readable device paths left behind and verifies data once volumes are reattached on another node", Capabilities: []string{"Privileged pods with host PID", "At least 2 schedulable nodes"}},
+		{Name: "NetworkPartitionSuite", Command: "test network-partition", Description: "writers keep doing IO on a node while fault hook partitions it from storage network at steps of fault plan, fault windows are recorded on pod timelines with IO error counts and recovery time", Capabilities: []string{"Fault hook able to partition a node from storage network"}},
+		{Name: "VolumeHealthMetricSuite", Command: "test volumehealthmetrics", Description: "checks volume health events reported by the driver", Capabilities: []string{"External health monitor sidecar"}},
+		{Name: "BlockSnapSuite", Command: "test blocksnap", Description: "writes data to filesystem volume, snapshots it and reads data from raw block restore", Capabilities: []string{"VolumeSnapshot CRDs", "VolumeSnapshotClass", "Raw block volumes"}},
+		{Name: "PostgresqlSuite", Command: "test psql", Description: "installs postgresql with helm and runs benchmark", Capabilities: []string{"Helm"}},
+		{Name: "RemoteReplicationProvisioningSuite", Command: "test replication-provisioning", Description: "provisions replicated volumes and fails them over to remote cluster", Capabilities: []string{"Replication CRDs", "Remote cluster"}},
+		{Name: "VolumeMigrationSuite", Command: "test volume-migrate", Description: "migrates volumes of a statefulset to another storage class", Capabilities: []string{"Migration CRDs"}},
+		{Name: "EphemeralVolumeSuite", Command: "test ephemeral-volume", Description: "mounts CSI ephemeral inline volumes and validates their lifecycle", Capabilities: []string{"Ephemeral volume lifecycle mode"}},
+		{Name: "VolumeDeletionSuite", Command: "functional-test volume-deletion", Description: "deletes a volume by its name"},
+		{Name: "PodDeletionSuite", Command: "functional-test pod-deletion", Description: "deletes a pod by its name"},
+		{Name: "ClonedVolumeDeletionSuite", Command: "functional-test clone-volume-deletion", Description: "deletes a cloned volume by its name"},
+		{Name: "SnapshotDeletionSuite", Command: "functional-test snap-deletion", Description: "deletes a volume snapshot by its name", Capabilities: []string{"VolumeSnapshot CRDs"}},
+		{Name: "VolumeCreationSuite", Command: "functional-test volume-creation", Description: "creates volumes without deleting them"},
+		{Name: "CloneVolumeSuite", Command: "functional-test clone-volume", Description: "clones volumes without deleting them", Capabilities: []string{"Volume cloning"}},
+		{Name: "ProvisioningSuite", Command: "functional-test provisioning", Description: "creates pods with volumes without deleting them"},
+		{Name: "SnapSuite", Command: "functional-test snapshot", Description: "creates snapshots without deleting them", Capabilities: []string{"VolumeSnapshot CRDs", "VolumeSnapshotClass"}},
+		{Name: "MultiAttachSuite", Command: "functional-test multi-attach-vol", Description: "attaches a volume to multiple pods without deleting them", Capabilities: []string{"ReadWriteMany or ReadWriteOncePod access mode"}},
+		{Name: "EphemeralVolumeSuite", Command: "functional-test ephemeral-volume", Description: "mounts CSI ephemeral inline volumes", Capabilities: []string{"Ephemeral volume lifecycle mode"}},
+		{Name: "NodeDrainSuite", Command: "functional-test node-drain", Description: "drains a node and checks that pods are rescheduled", Capabilities: []string{"Multiple nodes"}},
+		{Name: "NodeUncordonSuite", Command: "functional-test node-uncordon", Description: "uncordons drained nodes"},
+		{Name: "CapacityTrackingSuite", Command: "functional-test capacity-tracking", Description: "checks storage capacity tracking objects of the driver", Capabilities: []string{"CSIStorageCapacity"}},
+		{Name: "InvalidSCParamsSuite", Command: "functional-test invalid-params", Description: "clones storage class with sets of invalid parameters and validates that provisioning fails fast with an event and leaves no volume behind", Capabilities: []string{"StorageClass create permissions", "Events access"}},
+	} {
+		Register(info)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package suites
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dell/cert-csi/pkg/k8sclient"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/commonparams"
+	"github.com/dell/cert-csi/pkg/k8sclient/resources/node"
+	"github.com/dell/cert-csi/pkg/store"
+	"github.com/dell/cert-csi/pkg/utils"
+	"github.com/fatih/color"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// VolumeStatsPoll is a poll interval of kubelet summary API
+	VolumeStatsPoll = 10 * time.Second
+	// VolumeStatsTimeout is how long kubelet is given to report usage of written data, it refreshes stats every minute by default
+	VolumeStatsTimeout = 3 * time.Minute
+	// volumeStatsTolerance is a part of written data, which may be missing from reported usage
+	volumeStatsTolerance = 0.9
+)
+
+// checkVolumeStat validates that usage reported by kubelet is non-zero and accounts for data written to the volume
+func checkVolumeStat(vs *store.VolumeStat) {
+	var problems []string
+	if vs.Capacity == 0 {
+		problems = append(problems, "capacity is zero")
+	}
+	if vs.Used == 0 {
+		problems = append(problems, "used is zero")
+	}
+	if vs.Available == 0 {
+		problems = append(problems, "available is zero")
+	}
+	if vs.Used != 0 && float64(vs.Used) < float64(vs.Written)*volumeStatsTolerance {
+		problems = append(problems, fmt.Sprintf("used %d is less than written %d", vs.Used, vs.Written))
+	}
+	if vs.Capacity != 0 && vs.Capacity < vs.Written {
+		problems = append(problems, fmt.Sprintf("capacity %d is less than written %d", vs.Capacity, vs.Written))
+	}
+	if vs.Capacity != 0 && vs.Used+vs.Available > vs.Capacity {
+		problems = append(problems, fmt.Sprintf("used and available %d exceed capacity %d", vs.Used+vs.Available, vs.Capacity))
+	}
+	vs.Valid = len(problems) == 0
+	vs.Message = strings.Join(problems, "; ")
+}
+
+// sampleVolumeStats polls kubelet summary API of nodes until usage of every claim is valid or timeout expires,
+// claims are mapped to nodes of pods using them, stats are returned as of the last poll
+func sampleVolumeStats(ctx context.Context, clients *k8sclient.Clients, claims map[string]string, written int64) ([]*store.VolumeStat, error) {
+	log := utils.GetLoggerFromContext(ctx)
+	namespace := clients.PVCClient.Namespace
+
+	var stats []*store.VolumeStat
+	pollErr := commonparams.PollUntil(ctx, VolumeStatsPoll, VolumeStatsTimeout, nil, func(context.Context) (bool, error) {
+		reported := make(map[string]node.VolumeStats)
+		nodes := make(map[string]bool)
+		for _, nodeName := range claims {
+			if nodes[nodeName] {
+				continue
+			}
+			nodes[nodeName] = true
+			nodeStats, err := clients.NodeClient.GetVolumeStats(ctx, nodeName)
+			if err != nil {
+				return false, fmt.Errorf("can't read volume stats of node %s: %w", nodeName, err)
+			}
+			for _, s := range nodeStats {
+				if s.Namespace == namespace {
+					reported[s.PVC] = s
+				}
+			}
+		}
+
+		stats = nil
+		valid := true
+		for claim, nodeName := range claims {
+			vs := &store.VolumeStat{PVC: claim, Node: nodeName, Written: written}
+			if r, ok := reported[claim]; ok {
+				vs.Capacity, vs.Used, vs.Available = r.Capacity, r.Used, r.Available
+				checkVolumeStat(vs)
+			} else {
+				vs.Message = "volume stats aren't reported"
+			}
+			valid = valid && vs.Valid
+			stats = append(stats, vs)
+		}
+		sort.Slice(stats, func(i, j int) bool { return stats[i].PVC < stats[j].PVC })
+		return valid, nil
+	})
+	if pollErr != nil && !wait.Interrupted(pollErr) {
+		return stats, pollErr
+	}
+
+	for _, vs := range stats {
+		if vs.Valid {
+			log.Debugf("Volume %s on %s: capacity %d, used %d, available %d", vs.PVC, vs.Node, vs.Capacity, vs.Used, vs.Available)
+		} else {
+			log.Errorf("Volume stats of %s on %s are implausible: %s", color.CyanString(vs.PVC), vs.Node, vs.Message)
+		}
+	}
+	return stats, nil
+}
+
+// invalidVolumeStats returns error describing volumes with implausible stats, nil if all are valid
+func invalidVolumeStats(stats []*store.VolumeStat) error {
+	var invalid []string
+	for _, vs := range stats {
+		if !vs.Valid {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", vs.PVC, vs.Message))
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return fmt.Errorf("kubelet volume stats are implausible: %s", strings.Join(invalid, ", "))
+}
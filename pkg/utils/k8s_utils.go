@@ -19,7 +19,6 @@
 package utils
 
 import (
-	"bufio"
 	"bytes"
 	"errors"
 	"io"
@@ -336,18 +335,11 @@ func ExecuteE2ECommand(args []string, ch chan os.Signal) error {
 			return
 		}
 		log.Info("Received termination signal,")
-		log.Info("Do you really want to terminate the process ? (Y/n)")
-		reader := bufio.NewReader(os.Stdin)
-		log.Info("-> ")
-		input, err := reader.ReadString('\n')
-		if err != nil {
-			log.Error(err)
-		}
-		input = strings.TrimSuffix(input, "\n")
-		if input == "n" || input == "N" || input == "No" || input == "no" {
+		input := Prompt("Do you really want to terminate the process ? (Y/n)", 'y')
+		if input == 'n' || input == 'N' {
 			return
 		}
-		err = cmd.Process.Signal(syscall.SIGINT)
+		err := cmd.Process.Signal(syscall.SIGINT)
 		if err != nil {
 			log.Errorf("Unable to terminate the process, Termination failed with -->%s", err.Error())
 			return
@@ -0,0 +1,59 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// NonInteractiveEnv enables non-interactive mode when set to a true boolean value, ex. 1 or true
+const NonInteractiveEnv = "CERT_CSI_NON_INTERACTIVE"
+
+// NonInteractive makes every prompt return its default answer instead of reading stdin
+var NonInteractive bool
+
+// IsNonInteractive reports whether prompts must not read stdin
+func IsNonInteractive() bool {
+	if NonInteractive {
+		return true
+	}
+	// Values which aren't booleans keep prompts interactive
+	enabled, err := strconv.ParseBool(os.Getenv(NonInteractiveEnv))
+	return err == nil && enabled
+}
+
+// Prompt prints question and returns the first rune typed by the user.
+// In non-interactive mode def is returned without touching stdin.
+func Prompt(question string, def rune) rune {
+	fmt.Println(question)
+	if IsNonInteractive() {
+		log.Infof("Non-interactive mode, answering '%c'", def)
+		return def
+	}
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("-> ")
+	char, _, err := reader.ReadRune()
+	if err != nil {
+		log.Error(err)
+		return def
+	}
+	return char
+}
@@ -0,0 +1,38 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptNonInteractive(t *testing.T) {
+	NonInteractive = true
+	defer func() { NonInteractive = false }()
+
+	assert.True(t, IsNonInteractive())
+	assert.Equal(t, 'y', Prompt("Continue? (Y/n)", 'y'))
+	assert.Equal(t, 'n', Prompt("Are you sure (y/N)", 'n'))
+}
+
+func TestIsNonInteractiveEnv(t *testing.T) {
+	for value, expected := range map[string]bool{"1": true, "true": true, "TRUE": true, "0": false, "false": false, "yes": false, "": false} {
+		t.Setenv(NonInteractiveEnv, value)
+		assert.Equal(t, expected, IsNonInteractive(), value)
+	}
+}
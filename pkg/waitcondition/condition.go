@@ -0,0 +1,238 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package waitcondition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	// DefaultTimeout is how long condition without timeout is waited for
+	DefaultTimeout = 5 * time.Minute
+	// PollInterval is how often objects are checked
+	PollInterval = 2 * time.Second
+)
+
+// resourceAliases are shorthands of resources wait conditions are commonly declared for
+var resourceAliases = map[string]string{
+	"pvc": "persistentvolumeclaims",
+	"pv":  "persistentvolumes",
+	"pod": "pods",
+}
+
+// Condition is an extra wait condition of a suite, every object of resource must satisfy expression
+// before suite is considered ready
+type Condition struct {
+	// Name identifies condition in logs and events, expression is used if empty
+	Name string
+	// Resource is a plural resource name of core API, e.g. persistentvolumeclaims or pvc,
+	// version/resource of core API or group/version/resource, e.g. replication.storage.dell.com/v1/dellcsireplicationgroups
+	Resource string
+	// Namespace objects are looked for in, namespace of the suite if empty
+	Namespace string
+	// ClusterScoped marks resources which aren't namespaced
+	ClusterScoped bool
+	// Selector is a label selector of objects, all objects of resource if empty
+	Selector string
+	// Expression every object must satisfy, e.g. status.phase == Synced
+	Expression string
+	// Timeout is how long condition is waited for after suite, DefaultTimeout if zero
+	Timeout time.Duration
+
+	expr *Expression
+	gvr  schema.GroupVersionResource
+}
+
+// Result is an outcome of condition for a single object
+type Result struct {
+	Object string
+	UID    string
+	Met    bool
+	// ObservedAt is when object was first seen satisfying condition or when waiting stopped
+	ObservedAt time.Time
+	// Elapsed is time from object creation, or start of the suite if it is older, to ObservedAt
+	Elapsed time.Duration
+}
+
+// Validate parses expression and resource of condition
+func (c *Condition) Validate() error {
+	if c.Resource == "" {
+		return errors.New("resource of wait condition is required")
+	}
+	if c.Expression == "" {
+		return errors.New("expression of wait condition is required")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout of wait condition %s can't be negative", c.GetName())
+	}
+	expr, err := Parse(c.Expression)
+	if err != nil {
+		return err
+	}
+	gvr, err := parseResource(c.Resource)
+	if err != nil {
+		return err
+	}
+	c.expr, c.gvr = expr, gvr
+	return nil
+}
+
+// GetName returns name of condition
+func (c *Condition) GetName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Expression
+}
+
+// parseResource resolves resource of condition to group, version and resource
+func parseResource(resource string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(strings.ToLower(resource), "/")
+	for _, part := range parts {
+		if part == "" {
+			return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q", resource)
+		}
+	}
+	switch len(parts) {
+	case 1:
+		if alias, ok := resourceAliases[parts[0]]; ok {
+			parts[0] = alias
+		}
+		return schema.GroupVersionResource{Version: "v1", Resource: parts[0]}, nil
+	case 2:
+		return schema.GroupVersionResource{Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q, expected resource, version/resource or group/version/resource", resource)
+}
+
+// Wait polls objects until all of them satisfy condition or timeout expires, since is when suite started.
+// Condition with no objects isn't met, so objects created asynchronously, e.g. by driver, are waited for
+func (c *Condition) Wait(ctx context.Context, client dynamic.Interface, namespace string, since time.Time) ([]*Result, error) {
+	if c.expr == nil {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if c.Namespace != "" {
+		namespace = c.Namespace
+	}
+	var resource dynamic.ResourceInterface = client.Resource(c.gvr).Namespace(namespace)
+	if c.ClusterScoped {
+		resource = client.Resource(c.gvr)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	results := make(map[string]*Result)
+	for {
+		list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: c.Selector})
+		if err != nil {
+			return nil, fmt.Errorf("can't list %s; error=%w", c.gvr.Resource, err)
+		}
+		now := time.Now()
+		pending := 0
+		seen := make(map[string]bool)
+		for i := range list.Items {
+			res := c.check(&list.Items[i], results, since, now)
+			seen[res.UID] = true
+			if !res.Met {
+				pending++
+			}
+		}
+		// Objects deleted before satisfying condition aren't waited for
+		for uid, res := range results {
+			if !seen[uid] && !res.Met {
+				delete(results, uid)
+			}
+		}
+		if pending == 0 && len(list.Items) != 0 {
+			return sortResults(results), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			if len(results) == 0 {
+				return nil, fmt.Errorf("wait condition %s timed out after %s: no %s found", c.GetName(), timeout, c.gvr.Resource)
+			}
+			var unmet []string
+			for _, res := range results {
+				if !res.Met {
+					res.ObservedAt = time.Now()
+					unmet = append(unmet, res.Object)
+				}
+			}
+			sort.Strings(unmet)
+			return sortResults(results), fmt.Errorf("wait condition %s timed out after %s: %s not satisfied by %s",
+				c.GetName(), timeout, c.Expression, strings.Join(unmet, ", "))
+		case <-ticker.C:
+		}
+	}
+}
+
+// check evaluates condition against object, remembering when it was first satisfied
+func (c *Condition) check(obj *unstructured.Unstructured, results map[string]*Result, since, now time.Time) *Result {
+	res, ok := results[string(obj.GetUID())]
+	if !ok {
+		res = &Result{Object: obj.GetName(), UID: string(obj.GetUID())}
+		if obj.GetNamespace() != "" {
+			res.Object = obj.GetNamespace() + "/" + obj.GetName()
+		}
+		results[res.UID] = res
+	}
+	if res.Met || !c.expr.Eval(obj.Object) {
+		return res
+	}
+	start := obj.GetCreationTimestamp().Time
+	if start.Before(since) {
+		start = since
+	}
+	res.Met = true
+	res.ObservedAt = now
+	res.Elapsed = now.Sub(start)
+	return res
+}
+
+func sortResults(results map[string]*Result) []*Result {
+	sorted := make([]*Result, 0, len(results))
+	for _, res := range results {
+		sorted = append(sorted, res)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Object < sorted[j].Object
+	})
+	return sorted
+}
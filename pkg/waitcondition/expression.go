@@ -0,0 +1,398 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package waitcondition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Expression is a parsed wait condition, e.g. `status.phase == Synced` or
+// `metadata.annotations["example.com/ready"] && !status.conditions[type=Degraded]`.
+// Path alone is true when the field exists, comparison of fields which don't exist is false except for !=.
+// <, <=, > and >= compare numbers or quantities like 8Gi
+type Expression struct {
+	source string
+	root   node
+}
+
+// node is an element of parsed expression evaluated against an object
+type node interface {
+	eval(obj map[string]interface{}) bool
+}
+
+// Parse parses expression of wait condition
+func Parse(source string) (*Expression, error) {
+	p := &parser{src: source}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("can't parse expression %q: %w", source, err)
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("can't parse expression %q: unexpected %q at %d", source, p.src[p.pos:], p.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Eval checks whether object, as returned by dynamic client, satisfies expression
+func (e *Expression) Eval(obj map[string]interface{}) bool {
+	return e.root.eval(obj)
+}
+
+// String returns source of expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(obj map[string]interface{}) bool { return n.left.eval(obj) || n.right.eval(obj) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(obj map[string]interface{}) bool { return n.left.eval(obj) && n.right.eval(obj) }
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(obj map[string]interface{}) bool { return !n.operand.eval(obj) }
+
+type existsNode struct{ path path }
+
+func (n existsNode) eval(obj map[string]interface{}) bool {
+	v, ok := n.path.lookup(obj)
+	return ok && v != nil
+}
+
+type compareNode struct {
+	path  path
+	op    string
+	value string
+}
+
+func (n compareNode) eval(obj map[string]interface{}) bool {
+	v, ok := n.path.lookup(obj)
+	if !ok || v == nil {
+		return n.op == "!="
+	}
+	actual := format(v)
+	switch n.op {
+	case "==":
+		return actual == n.value
+	case "!=":
+		return actual != n.value
+	}
+	cmp, ok := compare(actual, n.value)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// compare compares values as numbers or as quantities, false if either of them is neither
+func compare(a, b string) (int, bool) {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	x, err := resource.ParseQuantity(a)
+	if err != nil {
+		return 0, false
+	}
+	y, err := resource.ParseQuantity(b)
+	if err != nil {
+		return 0, false
+	}
+	return x.Cmp(y), true
+}
+
+// format returns scalar field value as it is written in expressions
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// step is a single element of field path: map key, list index or list element selected by its field
+type step struct {
+	key    string
+	index  int
+	filter *compareNode
+}
+
+type path []step
+
+// lookup returns value of the field path points to
+func (p path) lookup(obj map[string]interface{}) (interface{}, bool) {
+	var current interface{} = obj
+	for _, s := range p {
+		switch {
+		case s.filter != nil:
+			list, ok := current.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			current = nil
+			for _, item := range list {
+				if m, ok := item.(map[string]interface{}); ok && s.filter.eval(m) {
+					current = item
+					break
+				}
+			}
+			if current == nil {
+				return nil, false
+			}
+		case s.key != "":
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m[s.key]; !ok {
+				return nil, false
+			}
+		default:
+			list, ok := current.([]interface{})
+			if !ok || s.index < 0 || s.index >= len(list) {
+				return nil, false
+			}
+			current = list[s.index]
+		}
+	}
+	return current, true
+}
+
+// parser is a recursive descent parser of expressions:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" or ")" | path [ op value ]
+//	path    = name { "." name | "[" ( string | index | name "=" value ) "]" }
+//	value   = string | bare word
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips token if it is next in source
+func (p *parser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.consume("!") {
+		if p.consume("=") {
+			return nil, fmt.Errorf("unexpected != at %d", p.pos-2)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if p.consume("(") {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, fmt.Errorf("missing ) at %d", p.pos)
+		}
+		return n, nil
+	}
+	fieldPath, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	op := p.parseOp()
+	if op == "" {
+		return existsNode{fieldPath}, nil
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{path: fieldPath, op: op, value: value}, nil
+}
+
+func (p *parser) parseOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *parser) parsePath() (path, error) {
+	name := p.parseName()
+	if name == "" {
+		return nil, fmt.Errorf("field expected at %d", p.pos)
+	}
+	result := path{{key: name}}
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '.':
+			p.pos++
+			if name = p.parseName(); name == "" {
+				return nil, fmt.Errorf("field expected at %d", p.pos)
+			}
+			result = append(result, step{key: name})
+		case '[':
+			p.pos++
+			s, err := p.parseSubscript()
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume("]") {
+				return nil, fmt.Errorf("missing ] at %d", p.pos)
+			}
+			result = append(result, s)
+		default:
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+// parseSubscript parses contents of brackets: quoted key, list index or field=value filter
+func (p *parser) parseSubscript() (step, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		key, err := p.parseString()
+		return step{key: key}, err
+	}
+	name := p.parseName()
+	if name == "" {
+		return step{}, fmt.Errorf("key, index or filter expected at %d", p.pos)
+	}
+	if !p.consume("=") {
+		index, err := strconv.Atoi(name)
+		if err != nil {
+			return step{}, fmt.Errorf("%q is not an index, quote keys or use field=value to select list element", name)
+		}
+		return step{index: index}, nil
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return step{}, err
+	}
+	return step{filter: &compareNode{path: path{{key: name}}, op: "==", value: value}}, nil
+}
+
+func (p *parser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := rune(p.src[p.pos])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) parseValue() (string, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		return p.parseString()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !unicode.IsSpace(rune(p.src[p.pos])) && !strings.ContainsRune("()[]&|", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("value expected at %d", p.pos)
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *parser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	end := strings.IndexByte(p.src[p.pos+1:], quote)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at %d", p.pos)
+	}
+	s := p.src[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return s, nil
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright © 2022-2023 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package waitcondition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestExpression(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"example.com/ready": ""},
+		},
+		"status": map[string]interface{}{
+			"phase":    "Synced",
+			"replicas": int64(3),
+			"capacity": map[string]interface{}{"storage": "8Gi"},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Degraded", "status": "False"},
+			},
+		},
+	}
+	for expr, expected := range map[string]bool{
+		`status.phase == Synced`:                                  true,
+		`status.phase != Synced`:                                  false,
+		`status.phase == "Synced" && status.replicas >= 3`:        true,
+		`metadata.annotations["example.com/ready"]`:               true,
+		`metadata.annotations['example.com/missing']`:             false,
+		`!metadata.annotations["example.com/missing"]`:            true,
+		`status.missing != x`:                                     true,
+		`status.missing == x`:                                     false,
+		`status.conditions[type=Ready].status == True`:            true,
+		`status.conditions[type=Degraded].status == True`:         false,
+		`status.conditions[1].type == Degraded`:                   true,
+		`status.conditions[5]`:                                    false,
+		`status.capacity.storage > 4Gi`:                           true,
+		`status.capacity.storage < 4Gi || (status.replicas < 10)`: true,
+		`status.phase > 4Gi`:                                      false,
+	} {
+		e, err := Parse(expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, e.Eval(obj), expr)
+	}
+
+	for _, expr := range []string{"", "status.phase ==", "(status.phase", "status.conditions[type", `status["x`, "status.phase == a b", "status.conditions[x]"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCondition(t *testing.T) {
+	for resource, expected := range map[string]schema.GroupVersionResource{
+		"pvc":       {Version: "v1", Resource: "persistentvolumeclaims"},
+		"v1/pods":   {Version: "v1", Resource: "pods"},
+		"a.io/v1/b": {Group: "a.io", Version: "v1", Resource: "b"},
+	} {
+		gvr, err := parseResource(resource)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, gvr)
+	}
+	_, err := parseResource("a/b/c/d")
+	assert.Error(t, err)
+	assert.Error(t, (&Condition{Resource: "pvc"}).Validate())
+	assert.Error(t, (&Condition{Resource: "pvc", Expression: "status.phase =="}).Validate())
+
+	PollInterval = 10 * time.Millisecond
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "syncs"}
+	newObject := func(name, phase string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"phase": phase}}}
+		obj.SetAPIVersion("example.com/v1")
+		obj.SetKind("Sync")
+		obj.SetName(name)
+		obj.SetNamespace("suite-ns")
+		obj.SetUID(types.UID("uid-" + name))
+		obj.SetCreationTimestamp(metav1.Now())
+		return obj
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "SyncList"}, newObject("a", "Synced"), newObject("b", "Pending"))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, err := client.Resource(gvr).Namespace("suite-ns").Update(context.Background(), newObject("b", "Synced"), metav1.UpdateOptions{})
+		assert.NoError(t, err)
+	}()
+	cond := &Condition{Resource: "example.com/v1/syncs", Expression: "status.phase == Synced", Timeout: time.Second}
+	results, err := cond.Wait(context.Background(), client, "suite-ns", time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "suite-ns/a", results[0].Object)
+	assert.True(t, results[1].Met)
+	assert.Greater(t, results[1].Elapsed, results[0].Elapsed)
+
+	cond = &Condition{Name: "failed", Resource: "example.com/v1/syncs", Expression: "status.phase == Failed", Timeout: 50 * time.Millisecond}
+	results, err = cond.Wait(context.Background(), client, "suite-ns", time.Now())
+	assert.ErrorContains(t, err, "suite-ns/a, suite-ns/b")
+	assert.Len(t, results, 2)
+	assert.False(t, results[0].Met)
+
+	_, err = cond.Wait(context.Background(), client, "other-ns", time.Now())
+	assert.ErrorContains(t, err, "no syncs found")
+}